@@ -0,0 +1,188 @@
+package ack
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+// dateLayout is the format of expiry dates
+const dateLayout = "2006-01-02"
+
+// Acknowledgment suppresses findings for one member until Expires
+type Acknowledgment struct {
+	Member  string    `json:"member"` // "Type.member"
+	Reason  string    `json:"reason"`
+	Expires time.Time `json:"expires"` // Midnight UTC of the expiry date
+	Line    int       `json:"line,omitempty"`
+}
+
+// Expired reports whether the acknowledgment no longer applies at now
+func (a Acknowledgment) Expired(now time.Time) bool {
+	return !now.Before(a.Expires)
+}
+
+func (a Acknowledgment) String() string {
+	return fmt.Sprintf("%s: %s, %s", a.Member, a.Reason, a.Expires.Format(dateLayout))
+}
+
+// List is a set of acknowledgments keyed by member
+type List struct {
+	Acknowledgments []Acknowledgment // In file order
+
+	members map[string]Acknowledgment
+}
+
+// Result is the outcome of checking findings against a List
+type Result struct {
+	Reported   []string         `json:"reported"`             // Findings without a current acknowledgment, in input order
+	Suppressed []Acknowledgment `json:"suppressed,omitempty"` // Acknowledgments that suppressed a finding
+	Expired    []Acknowledgment `json:"expired,omitempty"`    // Acknowledgments past their date, whether or not they matched
+	Unused     []Acknowledgment `json:"unused,omitempty"`     // Current acknowledgments that matched no finding
+}
+
+// Failed reports whether a check should fail: a finding was not suppressed,
+// or an acknowledgment expired
+func (r *Result) Failed() bool {
+	return len(r.Reported) > 0 || len(r.Expired) > 0
+}
+
+// New creates a List from acknowledgments. Members must be unique.
+func New(acks []Acknowledgment) (*List, error) {
+	l := &List{members: make(map[string]Acknowledgment)}
+	for _, a := range acks {
+		if prev, dup := l.members[a.Member]; dup {
+			return nil, fmt.Errorf("duplicate acknowledgment for %s (lines %d and %d)", a.Member, prev.Line, a.Line)
+		}
+		l.members[a.Member] = a
+		l.Acknowledgments = append(l.Acknowledgments, a)
+	}
+	return l, nil
+}
+
+// Parse reads an acknowledgments file, see the package documentation for the format
+func Parse(r io.Reader) (*List, error) {
+	var acks []Acknowledgment
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		a, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		a.Line = n
+		acks = append(acks, a)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read acknowledgments: %w", err)
+	}
+	return New(acks)
+}
+
+// NewWithFile reads an acknowledgments file with Parse
+func NewWithFile(path string) (*List, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open acknowledgments: %w", err)
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// parseLine parses "Type.member: reason, expiry". The reason may contain
+// commas; the expiry is the text after the last one.
+func parseLine(line string) (Acknowledgment, error) {
+	member, rest, ok := strings.Cut(line, ":")
+	member = strings.TrimSpace(member)
+	typeName, memberName, dotted := strings.Cut(member, ".")
+	if !ok || !dotted || typeName == "" || memberName == "" {
+		return Acknowledgment{}, fmt.Errorf("expected \"Type.member: reason, expiry\", got %q", line)
+	}
+	i := strings.LastIndex(rest, ",")
+	if i < 0 {
+		return Acknowledgment{}, fmt.Errorf("acknowledgment for %s has no expiry date", member)
+	}
+	reason := strings.TrimSpace(rest[:i])
+	if reason == "" {
+		return Acknowledgment{}, fmt.Errorf("acknowledgment for %s has no reason", member)
+	}
+	expires, err := time.Parse(dateLayout, strings.TrimSpace(rest[i+1:]))
+	if err != nil {
+		return Acknowledgment{}, fmt.Errorf("invalid expiry date for %s: %w", member, err)
+	}
+	return Acknowledgment{Member: member, Reason: reason, Expires: expires}, nil
+}
+
+// Check splits findings, given as "Type.member", into the ones still reported
+// and the ones suppressed by an acknowledgment that has not expired at now
+func (l *List) Check(findings []string, now time.Time) *Result {
+	r := &Result{Reported: []string{}}
+	matched := make(map[string]bool)
+	for _, member := range findings {
+		a, ok := l.members[member]
+		if !ok || a.Expired(now) {
+			r.Reported = append(r.Reported, member)
+			matched[member] = ok
+			continue
+		}
+		if !matched[member] {
+			r.Suppressed = append(r.Suppressed, a)
+		}
+		matched[member] = true
+	}
+	for _, a := range l.Acknowledgments {
+		switch {
+		case a.Expired(now):
+			r.Expired = append(r.Expired, a)
+		case !matched[a.Member]:
+			r.Unused = append(r.Unused, a)
+		}
+	}
+	return r
+}
+
+// FilterDeprecated applies Check to a report from Schema.Deprecated and
+// returns the report without the suppressed members. Types left without
+// deprecated members are dropped.
+func (l *List) FilterDeprecated(report []schema.DeprecatedType, now time.Time) ([]schema.DeprecatedType, *Result) {
+	var findings []string
+	for _, t := range report {
+		for _, m := range t.Members() {
+			findings = append(findings, t.Name+"."+m.Name)
+		}
+	}
+	result := l.Check(findings, now)
+	reported := make(map[string]bool, len(result.Reported))
+	for _, member := range result.Reported {
+		reported[member] = true
+	}
+
+	keep := func(typeName string, members []schema.DeprecatedMember) []schema.DeprecatedMember {
+		var out []schema.DeprecatedMember
+		for _, m := range members {
+			if reported[typeName+"."+m.Name] {
+				out = append(out, m)
+			}
+		}
+		return out
+	}
+	filtered := []schema.DeprecatedType{}
+	for _, t := range report {
+		t.Fields = keep(t.Name, t.Fields)
+		t.Arguments = keep(t.Name, t.Arguments)
+		t.InputFields = keep(t.Name, t.InputFields)
+		t.EnumValues = keep(t.Name, t.EnumValues)
+		if len(t.Members()) > 0 {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered, result
+}
@@ -0,0 +1,143 @@
+package ack
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+const testFile = `# Type.member: reason, expiry
+Repository.isTemplateRepo: migrating, see #42, 2026-12-31
+
+IssueState.LOCKED: blocked on upstream, 2026-01-01
+Issue.body: never deprecated, 2027-01-01
+`
+
+func TestParse(t *testing.T) {
+	l, err := Parse(strings.NewReader(testFile))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(l.Acknowledgments) != 3 {
+		t.Fatalf("Expected 3 acknowledgments, got %+v", l.Acknowledgments)
+	}
+	a := l.Acknowledgments[0]
+	want := Acknowledgment{
+		Member:  "Repository.isTemplateRepo",
+		Reason:  "migrating, see #42",
+		Expires: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+		Line:    2,
+	}
+	if a != want {
+		t.Errorf("Expected %+v, got %+v", want, a)
+	}
+	if l.Acknowledgments[1].Line != 4 {
+		t.Errorf("Expected line numbers to count blank and comment lines, got %d", l.Acknowledgments[1].Line)
+	}
+
+	for _, bad := range []string{
+		"Repository: reason, 2026-01-01",
+		"Repository.isTemplateRepo reason 2026-01-01",
+		"Repository.isTemplateRepo: reason",
+		"Repository.isTemplateRepo: , 2026-01-01",
+		"Repository.isTemplateRepo: reason, next year",
+		"A.b: x, 2026-01-01\nA.b: y, 2026-02-01",
+	} {
+		if _, err := Parse(strings.NewReader(bad)); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	l, err := Parse(strings.NewReader(testFile))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	r := l.Check([]string{"Repository.isTemplateRepo", "IssueState.LOCKED", "Issue.state"}, now)
+	if !reflect.DeepEqual(r.Reported, []string{"IssueState.LOCKED", "Issue.state"}) {
+		t.Errorf("Unexpected reported findings: %v", r.Reported)
+	}
+	if len(r.Suppressed) != 1 || r.Suppressed[0].Member != "Repository.isTemplateRepo" {
+		t.Errorf("Unexpected suppressed: %+v", r.Suppressed)
+	}
+	if len(r.Expired) != 1 || r.Expired[0].Member != "IssueState.LOCKED" {
+		t.Errorf("Unexpected expired: %+v", r.Expired)
+	}
+	if len(r.Unused) != 1 || r.Unused[0].Member != "Issue.body" {
+		t.Errorf("Unexpected unused: %+v", r.Unused)
+	}
+	if !r.Failed() {
+		t.Error("Expected the check to fail")
+	}
+
+	// An acknowledgment applies through the day before its expiry date
+	r = l.Check([]string{"Repository.isTemplateRepo"}, time.Date(2026, 12, 30, 23, 59, 0, 0, time.UTC))
+	if len(r.Reported) != 0 {
+		t.Errorf("Expected suppression before the expiry date, got %v", r.Reported)
+	}
+	r = l.Check([]string{"Repository.isTemplateRepo"}, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC))
+	if len(r.Reported) != 1 {
+		t.Errorf("Expected the finding on the expiry date, got %v", r.Reported)
+	}
+}
+
+func TestFilterDeprecated(t *testing.T) {
+	s, err := schema.NewSample()
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	report, err := s.Deprecated()
+	if err != nil {
+		t.Fatalf("Deprecated failed: %v", err)
+	}
+
+	var acks []Acknowledgment
+	expires := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, typ := range report {
+		for _, f := range typ.Fields {
+			acks = append(acks, Acknowledgment{Member: typ.Name + "." + f.Name, Reason: "scheduled", Expires: expires})
+		}
+	}
+	if len(acks) == 0 {
+		t.Fatal("Expected deprecated fields in the sample schema")
+	}
+	l, err := New(acks)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	filtered, result := l.FilterDeprecated(report, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+	for _, typ := range filtered {
+		if len(typ.Fields) != 0 {
+			t.Errorf("Expected acknowledged fields of %s to be removed, got %+v", typ.Name, typ.Fields)
+		}
+	}
+	if len(result.Suppressed) != len(acks) || len(result.Unused) != 0 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	// Once expired, everything is reported again
+	filtered, result = l.FilterDeprecated(report, expires)
+	if !reflect.DeepEqual(filtered, report) || len(result.Expired) != len(acks) {
+		t.Errorf("Expected the full report after expiry, got %+v (%+v)", filtered, result)
+	}
+
+	// Arguments are acknowledged as Type.field.argument
+	args, err := New([]Acknowledgment{{Member: "Repository.issues.after", Reason: "scheduled", Expires: expires}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	filtered, result = args.FilterDeprecated([]schema.DeprecatedType{{
+		Name:      "Repository",
+		Arguments: []schema.DeprecatedMember{{Name: "issues.after"}, {Name: "issues.before"}},
+	}}, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+	if len(filtered) != 1 || len(filtered[0].Arguments) != 1 || filtered[0].Arguments[0].Name != "issues.before" || len(result.Suppressed) != 1 {
+		t.Errorf("Expected only issues.before to remain, got %+v (%+v)", filtered, result)
+	}
+}
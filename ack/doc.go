@@ -0,0 +1,23 @@
+// Package ack suppresses known deprecation findings until a date, so checks
+// in CI stay green while migrations are scheduled.
+//
+// An acknowledgments file has one entry per line, naming a deprecated member
+// as "Type.member", or "Type.field.argument" for an argument, the reason it is
+// still in use, and the date (YYYY-MM-DD) until which it is suppressed. Blank
+// lines and lines starting with "#" are ignored:
+//
+//	# Type.member: reason, expiry
+//	Repository.isTemplateRepo: replaced in the v3 client, 2026-12-31
+//	MergeStateStatus.DRAFT: waiting on upstream, 2026-11-15
+//
+// Entries stop suppressing their member at the start of the expiry date
+// (UTC), and are reported as expired so they are fixed or renewed:
+//
+//	l, err := ack.NewWithFile("deprecations.ack")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	result := l.Check([]string{"Repository.isTemplateRepo", "Issue.state"}, time.Now())
+//	// result.Reported: Issue.state, plus any member whose entry expired
+//	// result.Expired: entries past their date
+package ack
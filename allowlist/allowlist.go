@@ -0,0 +1,282 @@
+package allowlist
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+// List is a set of allowed field selections, written as "Type.field" for a
+// single field or "Type.*" for every field of a type. Fields are matched
+// against the type they are selected on, so "Node.id" does not allow selecting
+// id on Issue. __typename is always allowed.
+type List struct {
+	schema *schema.Schema
+	fields map[string]bool
+	types  map[string]bool
+}
+
+// Violation is a selection outside the allowlist
+type Violation struct {
+	Type  string           // Type the field was selected on
+	Field string           // Field name
+	Path  string           // Response path such as "repository.owner.email"
+	Pos   graphql.Position // Position of the field in the document
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s.%s at %s (%s)", v.Type, v.Field, v.Path, v.Pos)
+}
+
+// ViolationError is returned by Check when an operation selects fields outside the allowlist
+type ViolationError struct {
+	Violations []Violation
+}
+
+func (e *ViolationError) Error() string {
+	items := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		items[i] = v.String()
+	}
+	return fmt.Sprintf("%d selection(s) outside the allowlist: %s", len(e.Violations), strings.Join(items, ", "))
+}
+
+// New creates a List from entries, verifying that every entry names an
+// existing type and field so that typos cannot silently allow nothing
+func New(s *schema.Schema, entries []string) (*List, error) {
+	l := &List{
+		schema: s,
+		fields: make(map[string]bool),
+		types:  make(map[string]bool),
+	}
+	for _, entry := range entries {
+		typeName, fieldName, ok := strings.Cut(strings.TrimSpace(entry), ".")
+		if !ok || typeName == "" || fieldName == "" {
+			return nil, fmt.Errorf("invalid allowlist entry %q: expected Type.field or Type.*", entry)
+		}
+		if fieldName == "*" {
+			if _, err := s.Fields(typeName); err != nil {
+				return nil, fmt.Errorf("invalid allowlist entry %q: %w", entry, err)
+			}
+			l.types[typeName] = true
+			continue
+		}
+		if _, err := s.FieldType(typeName, fieldName); err != nil {
+			return nil, fmt.Errorf("invalid allowlist entry %q: %w", entry, err)
+		}
+		l.fields[typeName+"."+fieldName] = true
+	}
+	return l, nil
+}
+
+// NewWithFile creates a List from a file with one entry per line.
+// Blank lines and lines starting with "#" are ignored.
+func NewWithFile(s *schema.Schema, path string) (*List, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open allowlist: %w", err)
+	}
+	defer f.Close()
+
+	var entries []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read allowlist: %w", err)
+	}
+	return New(s, entries)
+}
+
+// Allows reports whether fieldName may be selected on typeName
+func (l *List) Allows(typeName, fieldName string) bool {
+	return fieldName == "__typename" || l.types[typeName] || l.fields[typeName+"."+fieldName]
+}
+
+// Check rejects documents that select anything outside the allowlist,
+// returning a *ViolationError that lists every offending selection
+func (l *List) Check(doc *graphql.Document) error {
+	_, violations, err := l.Strip(doc)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return &ViolationError{Violations: violations}
+	}
+	return nil
+}
+
+// Strip returns a copy of doc without the selections outside the allowlist,
+// together with the violations it removed. Fields and fragments whose
+// selections all get removed are dropped as well, as are fragments that are
+// no longer referenced. The input document is not modified. It fails if an
+// operation has nothing left to select.
+func (l *List) Strip(doc *graphql.Document) (*graphql.Document, []Violation, error) {
+	st := &stripper{
+		list:      l,
+		fragments: doc.Fragments(),
+		stripped:  make(map[string]graphql.SelectionSet),
+		active:    make(map[string]bool),
+	}
+
+	ops := make(map[*graphql.OperationDefinition]*graphql.OperationDefinition)
+	used := make(map[string]bool)
+	for _, op := range doc.Operations() {
+		root := l.schema.RootTypeName(string(op.Operation))
+		if root == "" {
+			return nil, nil, fmt.Errorf("schema does not support %s operations", op.Operation)
+		}
+		set, err := st.selectionSet(root, op.SelectionSet, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(set) == 0 {
+			name := op.Name
+			if name == "" {
+				name = "(anonymous)"
+			}
+			return nil, st.violations, fmt.Errorf("operation %s has no allowed selections", name)
+		}
+		copied := *op
+		copied.SelectionSet = set
+		ops[op] = &copied
+		st.markUsed(set, used)
+	}
+
+	// Keep only the fragments still reachable from the stripped operations
+	out := &graphql.Document{}
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *graphql.OperationDefinition:
+			out.Definitions = append(out.Definitions, ops[def])
+		case *graphql.FragmentDefinition:
+			if used[def.Name] {
+				copied := *def
+				copied.SelectionSet = st.stripped[def.Name]
+				out.Definitions = append(out.Definitions, &copied)
+			}
+		}
+	}
+	return out, st.violations, nil
+}
+
+type stripper struct {
+	list       *List
+	fragments  map[string]*graphql.FragmentDefinition
+	stripped   map[string]graphql.SelectionSet // Stripped fragment selection sets
+	active     map[string]bool                 // Fragments being stripped, to detect cycles
+	violations []Violation
+}
+
+func (st *stripper) selectionSet(typeName string, set graphql.SelectionSet, path string) (graphql.SelectionSet, error) {
+	var out graphql.SelectionSet
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *graphql.Field:
+			fieldPath := sel.ResponseKey()
+			if path != "" {
+				fieldPath = path + "." + fieldPath
+			}
+			if !st.list.Allows(typeName, sel.Name) {
+				st.violate(typeName, sel, fieldPath)
+				continue
+			}
+			if sel.Name == "__typename" || len(sel.SelectionSet) == 0 {
+				out = append(out, sel)
+				continue
+			}
+			// Type.* may allow names that do not exist in the schema
+			ref, err := st.list.schema.FieldType(typeName, sel.Name)
+			if err != nil {
+				st.violate(typeName, sel, fieldPath)
+				continue
+			}
+			sub, err := st.selectionSet(ref.NamedType(), sel.SelectionSet, fieldPath)
+			if err != nil {
+				return nil, err
+			}
+			if len(sub) > 0 {
+				copied := *sel
+				copied.SelectionSet = sub
+				out = append(out, &copied)
+			}
+		case *graphql.InlineFragment:
+			condition := typeName
+			if sel.TypeCondition != "" {
+				condition = sel.TypeCondition
+			}
+			sub, err := st.selectionSet(condition, sel.SelectionSet, path)
+			if err != nil {
+				return nil, err
+			}
+			if len(sub) > 0 {
+				copied := *sel
+				copied.SelectionSet = sub
+				out = append(out, &copied)
+			}
+		case *graphql.FragmentSpread:
+			sub, err := st.fragment(sel, path)
+			if err != nil {
+				return nil, err
+			}
+			if len(sub) > 0 {
+				out = append(out, sel)
+			}
+		}
+	}
+	return out, nil
+}
+
+// fragment strips a named fragment once; violations are reported with the
+// path of the first spread that reaches them
+func (st *stripper) fragment(spread *graphql.FragmentSpread, path string) (graphql.SelectionSet, error) {
+	if set, ok := st.stripped[spread.Name]; ok {
+		return set, nil
+	}
+	fragment, ok := st.fragments[spread.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown fragment %q at %s", spread.Name, spread.Pos)
+	}
+	if st.active[spread.Name] {
+		return nil, fmt.Errorf("fragment %q spreads itself at %s", spread.Name, spread.Pos)
+	}
+
+	st.active[spread.Name] = true
+	set, err := st.selectionSet(fragment.TypeCondition, fragment.SelectionSet, path)
+	delete(st.active, spread.Name)
+	if err != nil {
+		return nil, err
+	}
+	st.stripped[spread.Name] = set
+	return set, nil
+}
+
+func (st *stripper) violate(typeName string, f *graphql.Field, path string) {
+	st.violations = append(st.violations, Violation{Type: typeName, Field: f.Name, Path: path, Pos: f.Pos})
+}
+
+// markUsed records the fragments reachable from a stripped selection set
+func (st *stripper) markUsed(set graphql.SelectionSet, used map[string]bool) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *graphql.Field:
+			st.markUsed(sel.SelectionSet, used)
+		case *graphql.InlineFragment:
+			st.markUsed(sel.SelectionSet, used)
+		case *graphql.FragmentSpread:
+			if !used[sel.Name] {
+				used[sel.Name] = true
+				st.markUsed(st.stripped[sel.Name], used)
+			}
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package allowlist
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+func loadSchema(t testing.TB) *schema.Schema {
+	t.Helper()
+	s, err := schema.NewSample()
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	return s
+}
+
+func parse(t testing.TB, src string) *graphql.Document {
+	t.Helper()
+	doc, err := graphql.Parse(src)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return doc
+}
+
+func TestStrip(t *testing.T) {
+	l, err := New(loadSchema(t), []string{
+		"Query.repository",
+		"Query.viewer",
+		"Repository.name",
+		"Repository.owner",
+		"RepositoryOwner.login",
+		"User.login",
+		"Repository.issues",
+		"IssueConnection.*",
+		"Issue.title",
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	doc := parse(t, `query Repo {
+  repository(owner: "o", name: "n") {
+    name
+    stargazerCount
+    owner { login ... on User { email } }
+    issues(first: 10) { totalCount nodes { ...IssueFields } }
+  }
+  viewer { email }
+}
+
+fragment IssueFields on Issue { __typename title body }
+
+fragment Unused on User { login }
+`)
+	stripped, violations, err := l.Strip(doc)
+	if err != nil {
+		t.Fatalf("Strip failed: %v", err)
+	}
+
+	want := `query Repo {
+  repository(owner: "o", name: "n") {
+    name
+    owner {
+      login
+    }
+    issues(first: 10) {
+      totalCount
+      nodes {
+        ...IssueFields
+      }
+    }
+  }
+}
+
+fragment IssueFields on Issue {
+  __typename
+  title
+}
+`
+	if got := graphql.Print(stripped); got != want {
+		t.Errorf("Stripped document mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	wantViolations := []string{
+		"Repository.stargazerCount at repository.stargazerCount (4:5)",
+		"User.email at repository.owner.email (5:33)",
+		"Issue.body at repository.issues.nodes.body (11:50)",
+		"User.email at viewer.email (8:12)",
+	}
+	if len(violations) != len(wantViolations) {
+		t.Fatalf("Got violations %v, want %v", violations, wantViolations)
+	}
+	for i, v := range violations {
+		if v.String() != wantViolations[i] {
+			t.Errorf("Violation %d = %s, want %s", i, v, wantViolations[i])
+		}
+	}
+
+	// The input document is left untouched
+	if !strings.Contains(graphql.Print(doc), "stargazerCount") {
+		t.Error("Strip modified the input document")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	l, err := New(loadSchema(t), []string{"Query.viewer", "User.login"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if err := l.Check(parse(t, `{ viewer { login __typename } }`)); err != nil {
+		t.Errorf("Expected allowed document, got %v", err)
+	}
+
+	err = l.Check(parse(t, `{ viewer { login email } }`))
+	var violationErr *ViolationError
+	if !errors.As(err, &violationErr) {
+		t.Fatalf("Expected *ViolationError, got %v", err)
+	}
+	if len(violationErr.Violations) != 1 || violationErr.Violations[0].Field != "email" {
+		t.Errorf("Unexpected violations: %v", violationErr.Violations)
+	}
+
+	if _, _, err := l.Strip(parse(t, `{ viewer { email } }`)); err == nil || !strings.Contains(err.Error(), "no allowed selections") {
+		t.Errorf("Expected error for an operation with nothing left, got %v", err)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	s := loadSchema(t)
+
+	for _, entry := range []string{"Repository", "Repository.", "Robot.*", "Repository.nope", ".name"} {
+		if _, err := New(s, []string{entry}); err == nil {
+			t.Errorf("Expected error for entry %q", entry)
+		}
+	}
+}
+
+func TestNewWithFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allowlist.txt")
+	content := "# Public repository data only\nQuery.repository\n\nRepository.name\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := NewWithFile(loadSchema(t), path)
+	if err != nil {
+		t.Fatalf("NewWithFile failed: %v", err)
+	}
+	if !l.Allows("Repository", "name") || l.Allows("Repository", "owner") {
+		t.Error("Unexpected allowlist contents")
+	}
+}
@@ -0,0 +1,25 @@
+// Package allowlist enforces a field allowlist on GraphQL operations before
+// they are sent to GitHub, for proxies that must guarantee sensitive fields
+// (such as User.email or IP allow list entries) are never queried.
+//
+// Entries name the type a field is selected on, either as "Type.field" or as
+// "Type.*" for every field of a type:
+//
+//	l, err := allowlist.New(s, []string{"Query.repository", "Repository.name", "Repository.issues", "IssueConnection.*", "Issue.title"})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	doc, err := graphql.Parse(query)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	// Reject the operation outright
+//	if err := l.Check(doc); err != nil {
+//		log.Fatal(err) // *allowlist.ViolationError
+//	}
+//
+//	// Or remove the disallowed selections and forward the rest
+//	stripped, violations, err := l.Strip(doc)
+//	query = graphql.Print(stripped)
+package allowlist
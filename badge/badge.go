@@ -0,0 +1,80 @@
+package badge
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// Label is the text on the left side of the badge
+const Label = "graphql"
+
+// Summary is the data shown on a badge
+type Summary struct {
+	Operations       int    `json:"operations"`
+	DeprecatedUsages int    `json:"deprecatedUsages"`
+	SchemaSnapshot   string `json:"schemaSnapshot"` // Date of the schema, empty when unknown
+}
+
+// Message returns the text on the right side of the badge
+func (s Summary) Message() string {
+	snapshot := s.SchemaSnapshot
+	if snapshot == "" {
+		snapshot = "unknown"
+	}
+	return fmt.Sprintf("operations validated: %d, deprecated usages: %d, schema snapshot: %s", s.Operations, s.DeprecatedUsages, snapshot)
+}
+
+// Color returns the shields.io color name of the badge: green without
+// deprecated usages, yellow with them
+func (s Summary) Color() string {
+	if s.DeprecatedUsages > 0 {
+		return "yellow"
+	}
+	return "brightgreen"
+}
+
+// Endpoint is the shields.io endpoint badge format
+// (https://shields.io/badges/endpoint-badge)
+type Endpoint struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// Endpoint returns the badge in the shields.io endpoint format
+func (s Summary) Endpoint() Endpoint {
+	return Endpoint{SchemaVersion: 1, Label: Label, Message: s.Message(), Color: s.Color()}
+}
+
+// colors maps the color names used by Color to their shields.io values
+var colors = map[string]string{
+	"brightgreen": "#4c1",
+	"yellow":      "#dfb317",
+}
+
+// SVG renders the badge as a flat shields.io style image
+func (s Summary) SVG() []byte {
+	labelWidth, messageWidth := textWidth(Label), textWidth(s.Message())
+	width := labelWidth + messageWidth
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`,
+		width, Label, html.EscapeString(s.Message()))
+	fmt.Fprintf(&b, `<title>%s: %s</title>`, Label, html.EscapeString(s.Message()))
+	fmt.Fprintf(&b, `<rect width="%d" height="20" fill="#555"/>`, labelWidth)
+	fmt.Fprintf(&b, `<rect x="%d" width="%d" height="20" fill="%s"/>`, labelWidth, messageWidth, colors[s.Color()])
+	b.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, labelWidth/2, Label)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, labelWidth+messageWidth/2, html.EscapeString(s.Message()))
+	b.WriteString("</g></svg>\n")
+	return []byte(b.String())
+}
+
+// textWidth approximates the width in pixels of text in 11px Verdana,
+// including 10px of padding on each side
+func textWidth(text string) int {
+	return utf8.RuneCountInString(text)*7 + 20
+}
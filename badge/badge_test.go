@@ -0,0 +1,34 @@
+package badge
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	s := Summary{Operations: 12, DeprecatedUsages: 3, SchemaSnapshot: "2026-10-01"}
+	if got, want := s.Message(), "operations validated: 12, deprecated usages: 3, schema snapshot: 2026-10-01"; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+	if e := s.Endpoint(); e.SchemaVersion != 1 || e.Label != "graphql" || e.Message != s.Message() || e.Color != "yellow" {
+		t.Errorf("Unexpected endpoint %+v", e)
+	}
+
+	clean := Summary{Operations: 1}
+	if clean.Color() != "brightgreen" || !strings.HasSuffix(clean.Message(), "schema snapshot: unknown") {
+		t.Errorf("Unexpected clean badge %q %q", clean.Color(), clean.Message())
+	}
+}
+
+func TestSVG(t *testing.T) {
+	svg := Summary{Operations: 2, SchemaSnapshot: "<today>"}.SVG()
+	if err := xml.Unmarshal(svg, new(struct{})); err != nil {
+		t.Fatalf("SVG is not well-formed: %v\n%s", err, svg)
+	}
+	for _, want := range []string{"#4c1", "operations validated: 2", "&lt;today&gt;"} {
+		if !strings.Contains(string(svg), want) {
+			t.Errorf("SVG does not contain %q:\n%s", want, svg)
+		}
+	}
+}
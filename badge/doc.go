@@ -0,0 +1,12 @@
+// Package badge renders a summary of local schema analysis as badge data for
+// READMEs and dashboards: the number of validated operations, the number of
+// selections of deprecated fields, and the date of the schema snapshot they
+// were checked against.
+//
+//	s := badge.Summary{Operations: report.Operations, DeprecatedUsages: report.DeprecatedUsages, SchemaSnapshot: "2026-10-01"}
+//	fmt.Println(s.Message()) // operations validated: 12, deprecated usages: 0, schema snapshot: 2026-10-01
+//	os.WriteFile("badge.svg", s.SVG(), 0o644)
+//
+// Endpoint returns the same data in the shields.io endpoint format, for
+// badges served from a JSON file.
+package badge
@@ -0,0 +1,173 @@
+package benchmarks
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+var (
+	loadOnce sync.Once
+	loaded   *schema.Schema
+	loadErr  error
+)
+
+// embedded returns the embedded schema, parsed once and shared by all benchmarks
+// that measure queries rather than loading
+func embedded(b *testing.B) *schema.Schema {
+	b.Helper()
+	loadOnce.Do(func() {
+		loaded, loadErr = schema.New()
+	})
+	if loadErr != nil {
+		b.Fatalf("Failed to load embedded schema: %v", loadErr)
+	}
+	return loaded
+}
+
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := schema.New(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNewCached(b *testing.B) {
+	dir := b.TempDir()
+	if _, err := schema.NewCached(dir); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := schema.NewCached(dir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNewLazy(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := schema.NewLazy(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkType(b *testing.B) {
+	s := embedded(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Type("Repository"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLazyType(b *testing.B) {
+	l, err := schema.NewLazy()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := l.Type("Repository"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkModelType(b *testing.B) {
+	s := embedded(b)
+	s.Model()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if s.Model().Type("Repository").Field("issues") == nil {
+			b.Fatal("Repository.issues not found")
+		}
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	s := embedded(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Search("review.*thread"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMutation(b *testing.B) {
+	s := embedded(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Mutation("createIssue"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQuery(b *testing.B) {
+	s := embedded(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Query(schema.ListObjectTypesQuery, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDiffWith(b *testing.B) {
+	s := embedded(b)
+	sample, err := schema.NewSample()
+	if err != nil {
+		b.Fatal(err)
+	}
+	// The models are built once and shared, like the Model of a loaded schema
+	s.Model()
+	sample.Model()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if cs := s.DiffWith(sample); len(cs.Changes) == 0 {
+			b.Fatal("Expected changes between the embedded and sample schemas")
+		}
+	}
+}
+
+// The JQ benchmarks run the reference jq expressions behind the native
+// lookups above, to compare the two engines
+
+func BenchmarkTypeJQ(b *testing.B) {
+	benchmarkJQ(b, schema.Queries().Type, map[string]interface{}{"type": "Repository"})
+}
+
+func BenchmarkMutationJQ(b *testing.B) {
+	benchmarkJQ(b, schema.Queries().Mutation, map[string]interface{}{"mutation": "createIssue"})
+}
+
+func BenchmarkSearchJQ(b *testing.B) {
+	benchmarkJQ(b, schema.Queries().Search, map[string]interface{}{"pattern": "review.*thread"})
+}
+
+func benchmarkJQ(b *testing.B, q schema.PredefinedQuery, vars map[string]interface{}) {
+	s := embedded(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Run(q, vars); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Package benchmarks contains benchmarks for the schema package measured
+// against the embedded GitHub schema. It has no non-test code; run it with
+//
+//	make bench
+//
+// which writes benchstat-compatible output to bench.txt. To compare a change
+// against the previous state:
+//
+//	git stash && make bench && mv bench.txt old.txt
+//	git stash pop && make bench
+//	benchstat old.txt bench.txt
+//
+// # Performance budgets
+//
+// The following budgets are upper bounds per operation, about 1.5 times the
+// slowest of three runs of
+//
+//	go test -run '^$' -bench . -benchtime 5x -count 3 ./benchmarks
+//
+// on a typical developer machine, rounded up. A change that pushes a
+// benchmark past its budget needs a justification in its pull request.
+//
+//	Benchmark            Budget
+//	BenchmarkNew          1.5s   decompress and parse the full schema
+//	BenchmarkNewCached    50ms   load the parsed schema from the on-disk cache
+//	BenchmarkNewLazy      15ms   decompress and index the types array
+//	BenchmarkType        250µs   Type("Repository") on a loaded schema
+//	BenchmarkLazyType     10ms   Type("Repository") on an indexed LazySchema
+//	BenchmarkModelType     2µs   Model().Type("Repository").Field("issues")
+//	BenchmarkSearch      2.5ms   Search over all type names
+//	BenchmarkMutation     25µs   Mutation("createIssue") with input expansion
+//	BenchmarkQuery          2s   a custom jq query listing object types
+//	BenchmarkDiffWith    1.5ms   DiffWith from the embedded schema to the sample
+//	BenchmarkTypeJQ       2.5s   the jq expression behind Type
+//	BenchmarkMutationJQ   2.5s   the jq expression behind Mutation
+//	BenchmarkSearchJQ     2.5s   the jq expression behind Search
+package benchmarks
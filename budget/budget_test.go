@@ -0,0 +1,201 @@
+package budget
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+func loadSchema(t testing.TB) *schema.Schema {
+	t.Helper()
+	s, err := schema.NewSample()
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	return s
+}
+
+func TestEstimate(t *testing.T) {
+	e := NewEstimator(loadSchema(t))
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		want      Estimate
+	}{
+		{
+			name:  "no connections",
+			query: `{ viewer { login } }`,
+			want:  Estimate{Requests: 0, Nodes: 0, Cost: 1},
+		},
+		{
+			name:  "single connection",
+			query: `query Issues { repository(owner: "o", name: "n") { issues(first: 50) { nodes { title } totalCount } } }`,
+			want:  Estimate{Operation: "Issues", Requests: 1, Nodes: 50, Cost: 1},
+		},
+		{
+			name: "nested connections multiply",
+			query: `{
+  search(first: 100, query: "q", type: ISSUE) {
+    nodes { ... on Issue { author { ... on User { issues(last: 100) { edges { node { id } } } } } } }
+  }
+}`,
+			want: Estimate{Requests: 101, Nodes: 10100, Cost: 1},
+		},
+		{
+			name: "variables and defaults",
+			query: `query($n: Int = 20, $m: Int) {
+  search(first: $m, query: "q", type: USER) { nodes { ...U } }
+}
+fragment U on User { issues(first: $n) { totalCount } }`,
+			variables: map[string]interface{}{"m": float64(100)},
+			want:      Estimate{Requests: 101, Nodes: 2100, Cost: 1},
+		},
+		{
+			name: "cost rounds requests to hundreds",
+			query: `{
+  a: search(first: 100, query: "a", type: USER) { nodes { ... on User { issues(first: 1) { totalCount } } } }
+  b: search(first: 100, query: "b", type: USER) { nodes { ... on User { issues(first: 1) { totalCount } } } }
+}`,
+			want: Estimate{Requests: 202, Nodes: 400, Cost: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := graphql.Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			got, err := e.Estimate(doc, "", tt.variables)
+			if err != nil {
+				t.Fatalf("Estimate failed: %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("Estimate = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateValidation(t *testing.T) {
+	e := NewEstimator(loadSchema(t))
+
+	tests := []struct {
+		name    string
+		query   string
+		wantPos graphql.Position
+		wantMsg string
+	}{
+		{"unknown field", `{ viewer { nope } }`, graphql.Position{Line: 1, Column: 12}, `field "nope" not found on type "User"`},
+		{"unknown argument", `{ viewer { issues(first: 1, bogus: 2) { totalCount } } }`, graphql.Position{Line: 1, Column: 29}, `unknown argument "bogus"`},
+		{"missing page size", `{ viewer { issues { totalCount } } }`, graphql.Position{Line: 1, Column: 12}, "requires a first or last argument"},
+		{"page size too large", `{ viewer { issues(first: 101) { totalCount } } }`, graphql.Position{Line: 1, Column: 19}, "between 1 and 100"},
+		{"unknown fragment", `{ viewer { ...Missing } }`, graphql.Position{Line: 1, Column: 12}, `unknown fragment "Missing"`},
+		{"fragment cycle", "{ viewer { ...A } }\nfragment A on User { ...A }", graphql.Position{Line: 2, Column: 22}, "spreads itself"},
+		{"field on scalar", `{ viewer { login { x } } }`, graphql.Position{Line: 1, Column: 20}, `not found on type "String"`},
+		{"unknown type condition", `{ viewer { ... on Robot { id } } }`, graphql.Position{Line: 1, Column: 12}, `unknown type "Robot"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := graphql.Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			_, err = e.Estimate(doc, "", nil)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected *ValidationError, got %v", err)
+			}
+			if validationErr.Pos != tt.wantPos || !strings.Contains(validationErr.Message, tt.wantMsg) {
+				t.Errorf("Got %v, want %q at %v", err, tt.wantMsg, tt.wantPos)
+			}
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var hits atomic.Int32
+	var lastBody atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		lastBody.Store(string(body))
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	s := loadSchema(t)
+	const expensive = `{"query":"{ a: search(first: 100, query: \"a\", type: USER) { nodes { ... on User { issues(first: 100) { totalCount } } } } b: search(first: 100, query: \"b\", type: USER) { nodes { ... on User { issues(first: 100) { totalCount } } } } }"}`
+	const cheap = `{"query":"query($n: Int!) { viewer { issues(first: $n) { totalCount } } }","variables":{"n":10}}`
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		logOnly  bool
+		wantErr  interface{}
+		wantSent bool
+	}{
+		{"within budget", "/graphql", cheap, false, nil, true},
+		{"over budget", "/graphql", expensive, false, &BudgetError{}, false},
+		{"over budget log only", "/graphql", expensive, true, nil, true},
+		{"invalid operation", "/graphql", `{"query":"{ viewer { nope } }"}`, false, &ValidationError{}, false},
+		{"syntax error", "/graphql", `{"query":"{ viewer "}`, false, &graphql.SyntaxError{}, false},
+		{"not graphql", "/repos/o/n", expensive, false, nil, true},
+		{"not json", "/graphql", `not json`, false, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := NewTransport(s, 1)
+			transport.LogOnly = tt.logOnly
+			client := &http.Client{Transport: transport}
+
+			before := hits.Load()
+			resp, err := client.Post(server.URL+tt.path, "application/json", strings.NewReader(tt.body))
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			switch want := tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			case *BudgetError:
+				if !errors.As(err, &want) {
+					t.Fatalf("Expected *BudgetError, got %v", err)
+				}
+				if want.Estimate.Cost != 2 || want.MaxCost != 1 {
+					t.Errorf("Unexpected budget error: %+v", want)
+				}
+			case *ValidationError:
+				if !errors.As(err, &want) {
+					t.Fatalf("Expected *ValidationError, got %v", err)
+				}
+			case *graphql.SyntaxError:
+				if !errors.As(err, &want) {
+					t.Fatalf("Expected *graphql.SyntaxError, got %v", err)
+				}
+			}
+
+			sent := hits.Load() != before
+			if sent != tt.wantSent {
+				t.Fatalf("Request sent = %v, want %v", sent, tt.wantSent)
+			}
+			if sent && lastBody.Load() != tt.body {
+				t.Errorf("Body was modified: %q", lastBody.Load())
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+// Package budget protects Go services that call the GitHub GraphQL API with
+// raw HTTP clients from sending operations that are invalid or too expensive.
+//
+// Transport is a drop-in http.RoundTripper: it parses each outgoing GraphQL
+// request, validates fields, arguments, and fragments against the schema, and
+// estimates the rate limit cost from the first and last arguments of every
+// connection, following GitHub's published formula.
+//
+//	s, err := schema.New()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	client := &http.Client{Transport: budget.NewTransport(s, 10)}
+//
+// Operations over budget fail with a *BudgetError before reaching GitHub. Set
+// Transport.LogOnly to only log them while rolling the middleware out.
+//
+// Estimator.Prune rewrites an operation over budget to fit instead, lowering
+// page sizes and dropping connections, and reports the cost before and after.
+package budget
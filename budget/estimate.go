@@ -0,0 +1,55 @@
+package budget
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+const (
+	// MaxPageSize is the largest first or last value GitHub accepts on a connection
+	MaxPageSize = schema.MaxPageSize
+
+	// MaxNodes is the largest number of nodes GitHub allows a single call to request
+	MaxNodes = 500000
+)
+
+// Estimate is the predicted size and rate limit cost of an operation, see
+// Schema.EstimateOperationCost
+type Estimate = schema.CostEstimate
+
+// ValidationError reports an operation that does not match the schema
+type ValidationError struct {
+	Pos     graphql.Position
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid operation at %s: %s", e.Pos, e.Message)
+}
+
+// Estimator validates operations against a schema and estimates their cost.
+// It is safe for concurrent use.
+type Estimator struct {
+	schema *schema.Schema
+}
+
+// NewEstimator creates an Estimator for the given schema
+func NewEstimator(s *schema.Schema) *Estimator {
+	return &Estimator{schema: s}
+}
+
+// Estimate validates the selected operation of doc and estimates its cost.
+// operationName may be empty when the document has a single operation.
+// Variables supply first and last values passed as variables; declared
+// defaults are used for variables that are not provided.
+func (e *Estimator) Estimate(doc *graphql.Document, operationName string, variables map[string]interface{}) (*Estimate, error) {
+	estimate, err := e.schema.EstimateOperationCost(doc, operationName, variables)
+	var queryErr *schema.QueryError
+	if errors.As(err, &queryErr) {
+		return nil, &ValidationError{Pos: graphql.Position{Line: queryErr.Line, Column: queryErr.Column}, Message: queryErr.Message}
+	}
+	return estimate, err
+}
@@ -0,0 +1,530 @@
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+)
+
+// Pruning is an operation reduced to fit a point budget by Estimator.Prune
+type Pruning struct {
+	// Document holds the pruned operation and the fragments it still uses
+	Document *graphql.Document `json:"-"`
+	// Query is Document printed
+	Query string `json:"query"`
+	// Variables are the given variables with lowered page sizes, without
+	// those the pruned operation no longer uses
+	Variables  map[string]interface{} `json:"variables,omitempty"`
+	Before     *Estimate              `json:"before"`
+	After      *Estimate              `json:"after"`
+	Reductions []Reduction            `json:"reductions"`
+	// Fits reports whether After is within the budget; when false, the
+	// reductions are as far as Prune could get
+	Fits bool `json:"fits"`
+}
+
+// Reduction is one change Prune made
+type Reduction struct {
+	// Path is the response path of the connection, such as
+	// "repository.issues", or the variable holding its page size, such as
+	// "$first"
+	Path    string `json:"path"`
+	Action  string `json:"action"` // "lower" or "drop"
+	Message string `json:"message"`
+}
+
+// Prune reduces the selected operation of doc until its estimated cost is at
+// most points and it requests at most MaxNodes nodes, reporting the estimate
+// before and after. Page sizes of connections that multiply the requests of
+// the connections nested in them are halved first, each step taking the one
+// that saves the most; when no page size can go lower usefully, the
+// connection whose removal saves the most is dropped, as long as its parent
+// keeps other selections. Page sizes given as variables are lowered in the
+// returned variables rather than in the document.
+//
+// doc is not modified. Invalid operations fail like Estimate.
+func (e *Estimator) Prune(doc *graphql.Document, operationName string, variables map[string]interface{}, points int) (*Pruning, error) {
+	if points < 1 {
+		return nil, fmt.Errorf("budget must be at least 1 point, got %d", points)
+	}
+	op, err := doc.Operation(operationName)
+	if err != nil {
+		return nil, err
+	}
+	before, err := e.Estimate(doc, operationName, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pruner{
+		estimator: e,
+		op:        op,
+		fragments: doc.Fragments(),
+		active:    make(map[string]bool),
+		variables: make(map[string]interface{}),
+	}
+	for _, def := range op.VariableDefinitions {
+		if def.DefaultValue != nil {
+			p.variables[def.Name] = def.DefaultValue.Interface(nil)
+		}
+	}
+	for name, value := range variables {
+		p.variables[name] = value
+	}
+	root := e.schema.RootTypeName(string(op.Operation))
+	p.discover(root, op.SelectionSet, "")
+
+	current := plan{sizes: make(map[knobKey]int), dropped: make(map[*graphql.Field]bool), variables: p.variables}
+	estimate := before
+	var lowered []knobKey
+	var drops []*connection
+	for !p.fits(estimate, points) {
+		next, nextEstimate, knob, conn := p.step(current, estimate, points)
+		if nextEstimate == nil {
+			break
+		}
+		current, estimate = next, nextEstimate
+		if conn != nil {
+			drops = append(drops, conn)
+		} else if !containsKnob(lowered, knob) {
+			lowered = append(lowered, knob)
+		}
+	}
+
+	result := &Pruning{Before: before, After: estimate, Fits: p.fits(estimate, points), Reductions: []Reduction{}}
+	result.Document, result.Variables = p.build(current)
+	result.Query = graphql.Print(result.Document)
+	for _, k := range lowered {
+		from, to := p.value(k, plan{variables: p.variables}), p.value(k, current)
+		// Sizes inside connections dropped later no longer matter
+		if _, used := result.Variables[k.variable]; current.droppedKnob(p, k) || (k.variable != "" && !used) {
+			continue
+		}
+		result.Reductions = append(result.Reductions, Reduction{
+			Path:    p.knobPath(k),
+			Action:  "lower",
+			Message: fmt.Sprintf("%s lowered from %d to %d", p.knobName(k), from, to),
+		})
+	}
+	for _, c := range drops {
+		result.Reductions = append(result.Reductions, Reduction{
+			Path:    c.path,
+			Action:  "drop",
+			Message: fmt.Sprintf("connection %s dropped", c.field.Name),
+		})
+	}
+	if len(result.Variables) == 0 {
+		result.Variables = nil
+	}
+	return result, nil
+}
+
+// knobKey identifies a page size: a literal first or last argument of a
+// field, or a variable passed as either
+type knobKey struct {
+	field    *graphql.Field
+	arg      string
+	variable string
+}
+
+// connection is a connection field found in the operation or its fragments
+type connection struct {
+	field  *graphql.Field
+	parent graphql.SelectionSet // Selection set the field is in
+	path   string
+}
+
+// plan is a set of reductions applied to the original document
+type plan struct {
+	sizes     map[knobKey]int // Lowered literal page sizes
+	dropped   map[*graphql.Field]bool
+	variables map[string]interface{}
+}
+
+func (pl plan) with(update func(next *plan)) plan {
+	next := plan{sizes: make(map[knobKey]int), dropped: make(map[*graphql.Field]bool), variables: make(map[string]interface{})}
+	for k, v := range pl.sizes {
+		next.sizes[k] = v
+	}
+	for k, v := range pl.dropped {
+		next.dropped[k] = v
+	}
+	for k, v := range pl.variables {
+		next.variables[k] = v
+	}
+	update(&next)
+	return next
+}
+
+// droppedKnob reports whether the field of a literal knob was dropped
+func (pl plan) droppedKnob(p *pruner, k knobKey) bool {
+	if k.field == nil {
+		return false
+	}
+	for _, c := range p.connections {
+		if pl.dropped[c.field] && (c.field == k.field || strings.HasPrefix(p.paths[k.field], c.path+".")) {
+			return true
+		}
+	}
+	return false
+}
+
+type pruner struct {
+	estimator *Estimator
+	op        *graphql.OperationDefinition
+	fragments map[string]*graphql.FragmentDefinition
+	active    map[string]bool
+	variables map[string]interface{} // Given variables and defaults
+
+	knobs       []knobKey
+	connections []*connection
+	paths       map[*graphql.Field]string
+}
+
+// discover records the connections and page sizes of a selection set,
+// following fragment spreads; a field reached through several spreads keeps
+// the first path
+func (p *pruner) discover(typeName string, set graphql.SelectionSet, path string) {
+	if p.paths == nil {
+		p.paths = make(map[*graphql.Field]string)
+	}
+	t := p.estimator.schema.Model().Type(typeName)
+	if t == nil {
+		return
+	}
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *graphql.Field:
+			def := t.Field(sel.Name)
+			if def == nil {
+				continue
+			}
+			fieldPath := sel.ResponseKey()
+			if path != "" {
+				fieldPath = path + "." + fieldPath
+			}
+			if _, seen := p.paths[sel]; !seen {
+				p.paths[sel] = fieldPath
+				if def.Arg("first") != nil && def.Arg("last") != nil {
+					p.connections = append(p.connections, &connection{field: sel, parent: set, path: fieldPath})
+					for _, name := range []string{"first", "last"} {
+						arg := sel.Argument(name)
+						switch {
+						case arg == nil:
+						case arg.Value.Kind == graphql.VariableValue:
+							if k := (knobKey{variable: arg.Value.Raw}); !containsKnob(p.knobs, k) {
+								p.knobs = append(p.knobs, k)
+							}
+						case arg.Value.Kind == graphql.IntValue:
+							p.knobs = append(p.knobs, knobKey{field: sel, arg: name})
+						}
+					}
+				}
+			}
+			p.discover(def.Type.NamedType(), sel.SelectionSet, fieldPath)
+		case *graphql.InlineFragment:
+			condition := typeName
+			if sel.TypeCondition != "" {
+				condition = sel.TypeCondition
+			}
+			p.discover(condition, sel.SelectionSet, path)
+		case *graphql.FragmentSpread:
+			fragment, ok := p.fragments[sel.Name]
+			if !ok || p.active[sel.Name] {
+				continue
+			}
+			p.active[sel.Name] = true
+			p.discover(fragment.TypeCondition, fragment.SelectionSet, path)
+			delete(p.active, sel.Name)
+		}
+	}
+}
+
+func (p *pruner) fits(e *Estimate, points int) bool {
+	return e.Cost <= points && e.Nodes <= MaxNodes
+}
+
+// step returns the plan one reduction further along and its estimate, or a
+// nil estimate when no reduction helps. Lowering a page size is preferred
+// over dropping a connection.
+func (p *pruner) step(current plan, estimate *Estimate, points int) (plan, *Estimate, knobKey, *connection) {
+	// The measure that is over budget has to go down
+	overCost := estimate.Cost > points
+	measure := func(e *Estimate) int {
+		if overCost {
+			return e.Requests
+		}
+		return e.Nodes
+	}
+
+	var best plan
+	var bestEstimate *Estimate
+	var bestKnob knobKey
+	consider := func(next plan) bool {
+		e, err := p.estimate(next)
+		if err != nil || measure(e) >= measure(estimate) {
+			return false
+		}
+		if bestEstimate == nil || measure(e) < measure(bestEstimate) {
+			best, bestEstimate = next, e
+			return true
+		}
+		return false
+	}
+
+	for _, k := range p.knobs {
+		size := p.value(k, current)
+		if size <= 1 || current.droppedKnob(p, k) {
+			continue
+		}
+		next := current.with(func(next *plan) {
+			if k.variable != "" {
+				next.variables[k.variable] = size / 2
+			} else {
+				next.sizes[k] = size / 2
+			}
+		})
+		if consider(next) {
+			bestKnob = k
+		}
+	}
+	if bestEstimate != nil {
+		return best, bestEstimate, bestKnob, nil
+	}
+
+	var bestConn *connection
+	for _, c := range p.connections {
+		if current.dropped[c.field] || !p.keepsSiblings(c, current) {
+			continue
+		}
+		next := current.with(func(next *plan) { next.dropped[c.field] = true })
+		if consider(next) {
+			bestConn = c
+		}
+	}
+	return best, bestEstimate, knobKey{}, bestConn
+}
+
+// keepsSiblings reports whether dropping a connection leaves its selection
+// set with other selections
+func (p *pruner) keepsSiblings(c *connection, current plan) bool {
+	for _, sel := range c.parent {
+		if f, ok := sel.(*graphql.Field); !ok || (f != c.field && !current.dropped[f]) {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the page size of a knob under a plan, or 0 when unknown
+func (p *pruner) value(k knobKey, pl plan) int {
+	if k.variable != "" {
+		n, _ := intValue(pl.variables[k.variable])
+		return n
+	}
+	if size, ok := pl.sizes[k]; ok {
+		return size
+	}
+	n, _ := strconv.Atoi(k.field.Argument(k.arg).Value.Raw)
+	return n
+}
+
+// knobName returns the argument or variable name of a knob
+func (p *pruner) knobName(k knobKey) string {
+	if k.variable != "" {
+		return "$" + k.variable
+	}
+	return k.arg
+}
+
+func (p *pruner) knobPath(k knobKey) string {
+	if k.variable != "" {
+		return "$" + k.variable
+	}
+	return p.paths[k.field]
+}
+
+func (p *pruner) estimate(pl plan) (*Estimate, error) {
+	doc, variables := p.build(pl)
+	return p.estimator.Estimate(doc, "", variables)
+}
+
+// build applies a plan to a copy of the operation, keeping the fragments it
+// still spreads and the variables it still uses
+func (p *pruner) build(pl plan) (*graphql.Document, map[string]interface{}) {
+	b := &builder{plan: pl, fragments: make(map[string]*graphql.FragmentDefinition), variables: make(map[string]bool)}
+	op := *p.op
+	op.SelectionSet = b.selectionSet(p.op.SelectionSet)
+	// Fragments may spread further fragments
+	for len(b.pending) > 0 {
+		b.spread(p.fragments)
+	}
+	for _, d := range p.op.Directives {
+		b.arguments(d.Arguments)
+	}
+
+	var defs []*graphql.VariableDefinition
+	for _, def := range p.op.VariableDefinitions {
+		if b.variables[def.Name] {
+			defs = append(defs, def)
+		}
+	}
+	op.VariableDefinitions = defs
+	variables := make(map[string]interface{})
+	for name, value := range pl.variables {
+		if b.variables[name] {
+			variables[name] = value
+		}
+	}
+
+	doc := &graphql.Document{Definitions: []graphql.Definition{&op}}
+	for _, def := range p.fragmentOrder() {
+		if f, ok := b.fragments[def.Name]; ok {
+			doc.Definitions = append(doc.Definitions, f)
+		}
+	}
+	return doc, variables
+}
+
+// fragmentOrder returns the fragment definitions in a stable order
+func (p *pruner) fragmentOrder() []*graphql.FragmentDefinition {
+	var defs []*graphql.FragmentDefinition
+	names := make([]string, 0, len(p.fragments))
+	for name := range p.fragments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		defs = append(defs, p.fragments[name])
+	}
+	return defs
+}
+
+// builder copies selection sets under a plan
+type builder struct {
+	plan      plan
+	fragments map[string]*graphql.FragmentDefinition // Copied fragments, by name
+	pending   []string                               // Spread fragments not copied yet
+	variables map[string]bool                        // Variables still used
+}
+
+func (b *builder) selectionSet(set graphql.SelectionSet) graphql.SelectionSet {
+	var out graphql.SelectionSet
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *graphql.Field:
+			if b.plan.dropped[sel] {
+				continue
+			}
+			copied := *sel
+			copied.Arguments = nil
+			for _, arg := range sel.Arguments {
+				if size, ok := b.plan.sizes[knobKey{field: sel, arg: arg.Name}]; ok {
+					arg = &graphql.Argument{Name: arg.Name, Value: &graphql.Value{Kind: graphql.IntValue, Raw: strconv.Itoa(size), Pos: arg.Value.Pos}, Pos: arg.Pos}
+				}
+				copied.Arguments = append(copied.Arguments, arg)
+			}
+			b.arguments(copied.Arguments)
+			for _, d := range sel.Directives {
+				b.arguments(d.Arguments)
+			}
+			copied.SelectionSet = b.selectionSet(sel.SelectionSet)
+			out = append(out, &copied)
+		case *graphql.InlineFragment:
+			copied := *sel
+			for _, d := range sel.Directives {
+				b.arguments(d.Arguments)
+			}
+			copied.SelectionSet = b.selectionSet(sel.SelectionSet)
+			out = append(out, &copied)
+		case *graphql.FragmentSpread:
+			for _, d := range sel.Directives {
+				b.arguments(d.Arguments)
+			}
+			if _, ok := b.fragments[sel.Name]; !ok {
+				b.fragments[sel.Name] = nil
+				b.pending = append(b.pending, sel.Name)
+			}
+			out = append(out, sel)
+		}
+	}
+	return out
+}
+
+// spread copies the fragments spread so far
+func (b *builder) spread(fragments map[string]*graphql.FragmentDefinition) {
+	pending := b.pending
+	b.pending = nil
+	for _, name := range pending {
+		def, ok := fragments[name]
+		if !ok {
+			delete(b.fragments, name)
+			continue
+		}
+		copied := *def
+		for _, d := range def.Directives {
+			b.arguments(d.Arguments)
+		}
+		copied.SelectionSet = b.selectionSet(def.SelectionSet)
+		b.fragments[name] = &copied
+	}
+}
+
+// arguments records the variables argument values use
+func (b *builder) arguments(args []*graphql.Argument) {
+	for _, arg := range args {
+		b.value(arg.Value)
+	}
+}
+
+func (b *builder) value(v *graphql.Value) {
+	if v == nil {
+		return
+	}
+	switch v.Kind {
+	case graphql.VariableValue:
+		b.variables[v.Raw] = true
+	case graphql.ListValue:
+		for _, item := range v.List {
+			b.value(item)
+		}
+	case graphql.ObjectValue:
+		for _, f := range v.Fields {
+			b.value(f.Value)
+		}
+	}
+}
+
+func containsKnob(knobs []knobKey, k knobKey) bool {
+	for _, existing := range knobs {
+		if existing == k {
+			return true
+		}
+	}
+	return false
+}
+
+// intValue converts a decoded JSON number to an int
+func intValue(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	case float64:
+		if n != float64(int(n)) {
+			return 0, false
+		}
+		return int(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,135 @@
+package budget
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/graphql"
+)
+
+func TestPrune(t *testing.T) {
+	e := NewEstimator(loadSchema(t))
+
+	var aliases strings.Builder
+	for i := 0; i < 150; i++ {
+		fmt.Fprintf(&aliases, "i%d: issues(first: 1) { totalCount }\n", i)
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		variables  map[string]interface{}
+		points     int
+		before     Estimate
+		after      Estimate
+		reductions []Reduction
+		wantVars   map[string]interface{}
+		contains   string
+	}{
+		{
+			name: "lowers outer page sizes",
+			query: `{
+  a: search(first: 100, query: "a", type: USER) { nodes { ... on User { issues(first: 100) { totalCount } } } }
+  b: search(first: 100, query: "b", type: USER) { nodes { ... on User { issues(first: 100) { totalCount } } } }
+}`,
+			points: 1,
+			before: Estimate{Requests: 202, Nodes: 20200, Cost: 2},
+			after:  Estimate{Requests: 102, Nodes: 10100, Cost: 1},
+			reductions: []Reduction{
+				{Path: "a", Action: "lower", Message: "first lowered from 100 to 50"},
+				{Path: "b", Action: "lower", Message: "first lowered from 100 to 50"},
+			},
+			contains: `a: search(first: 50, query: "a", type: USER)`,
+		},
+		{
+			name: "lowers variables",
+			query: `query($n: Int = 100) {
+  search(first: $n, query: "q", type: USER) { nodes { ...U } }
+}
+fragment U on User { open: issues(first: 100) { totalCount } closed: issues(first: 100) { totalCount } }`,
+			points:     1,
+			before:     Estimate{Requests: 201, Nodes: 20100, Cost: 2},
+			after:      Estimate{Requests: 101, Nodes: 10050, Cost: 1},
+			reductions: []Reduction{{Path: "$n", Action: "lower", Message: "$n lowered from 100 to 50"}},
+			wantVars:   map[string]interface{}{"n": 50},
+			contains:   "fragment U on User",
+		},
+		{
+			name:       "drops connections",
+			query:      "{ viewer { login\n" + aliases.String() + "} }",
+			points:     1,
+			before:     Estimate{Requests: 150, Nodes: 150, Cost: 2},
+			after:      Estimate{Requests: 149, Nodes: 149, Cost: 1},
+			reductions: []Reduction{{Path: "viewer.i0", Action: "drop", Message: "connection issues dropped"}},
+		},
+		{
+			name:       "already fits",
+			query:      `{ viewer { issues(first: 100) { totalCount } } }`,
+			points:     1,
+			before:     Estimate{Requests: 1, Nodes: 100, Cost: 1},
+			after:      Estimate{Requests: 1, Nodes: 100, Cost: 1},
+			reductions: []Reduction{},
+			contains:   "issues(first: 100)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := graphql.Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			original := graphql.Print(doc)
+			got, err := e.Prune(doc, "", tt.variables, tt.points)
+			if err != nil {
+				t.Fatalf("Prune failed: %v", err)
+			}
+			if *got.Before != tt.before || *got.After != tt.after || !got.Fits {
+				t.Errorf("Before = %+v, After = %+v, Fits = %v; want %+v, %+v", *got.Before, *got.After, got.Fits, tt.before, tt.after)
+			}
+			if !reflect.DeepEqual(got.Reductions, tt.reductions) {
+				t.Errorf("Reductions = %+v, want %+v", got.Reductions, tt.reductions)
+			}
+			if !reflect.DeepEqual(got.Variables, tt.wantVars) {
+				t.Errorf("Variables = %v, want %v", got.Variables, tt.wantVars)
+			}
+			if !strings.Contains(got.Query, tt.contains) {
+				t.Errorf("Query does not contain %q:\n%s", tt.contains, got.Query)
+			}
+			if graphql.Print(doc) != original {
+				t.Errorf("Prune modified the input document")
+			}
+
+			// The pruned operation estimates the same when sent as is
+			reparsed, err := graphql.Parse(got.Query)
+			if err != nil {
+				t.Fatalf("Pruned query does not parse: %v", err)
+			}
+			again, err := e.Estimate(reparsed, "", got.Variables)
+			if err != nil || *again != tt.after {
+				t.Errorf("Estimate of pruned query = %+v, %v; want %+v", again, err, tt.after)
+			}
+		})
+	}
+}
+
+func TestPruneInvalid(t *testing.T) {
+	e := NewEstimator(loadSchema(t))
+	doc, err := graphql.Parse(`{ viewer { issues(first: 10) { totalCount } } }`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if _, err := e.Prune(doc, "", nil, 0); err == nil || !strings.Contains(err.Error(), "at least 1 point") {
+		t.Errorf("Expected budget error, got %v", err)
+	}
+
+	doc, err = graphql.Parse(`{ viewer { nope } }`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if _, err := e.Prune(doc, "", nil, 1); err == nil {
+		t.Errorf("Expected validation error")
+	}
+}
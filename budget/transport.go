@@ -0,0 +1,130 @@
+package budget
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/apstndb/go-yamlformat"
+)
+
+// BudgetError reports an operation whose estimate exceeds the configured budget
+type BudgetError struct {
+	Estimate Estimate
+	MaxCost  int
+	MaxNodes int
+}
+
+func (e *BudgetError) Error() string {
+	name := e.Estimate.Operation
+	if name == "" {
+		name = "(anonymous)"
+	}
+	if e.MaxCost > 0 && e.Estimate.Cost > e.MaxCost {
+		return fmt.Sprintf("operation %s costs an estimated %d points, over the budget of %d", name, e.Estimate.Cost, e.MaxCost)
+	}
+	return fmt.Sprintf("operation %s requests an estimated %d nodes, over the limit of %d", name, e.Estimate.Nodes, e.MaxNodes)
+}
+
+// Transport is an http.RoundTripper that parses outgoing GraphQL requests,
+// validates them against the schema, and estimates their cost before they
+// are sent. Requests that fail validation or exceed the budget are rejected
+// with a *graphql.SyntaxError, *ValidationError, or *BudgetError (wrapped in
+// a *url.Error by http.Client), unless LogOnly is set.
+//
+// Only POST requests whose path ends in /graphql are inspected; everything
+// else, including bodies that are not GraphQL JSON, is passed through.
+type Transport struct {
+	// Base sends the requests; http.DefaultTransport is used when nil
+	Base http.RoundTripper
+
+	// Estimator validates operations and estimates their cost
+	Estimator *Estimator
+
+	// MaxCost is the largest allowed cost in rate limit points; zero disables the check
+	MaxCost int
+
+	// MaxNodes is the largest allowed number of nodes; zero disables the check
+	MaxNodes int
+
+	// LogOnly logs rejected operations with slog.Warn and sends them anyway
+	LogOnly bool
+}
+
+// NewTransport creates a Transport that rejects operations costing more than
+// maxCost points or requesting more nodes than GitHub allows
+func NewTransport(s *schema.Schema, maxCost int) *Transport {
+	return &Transport{
+		Estimator: NewEstimator(s),
+		MaxCost:   maxCost,
+		MaxNodes:  MaxNodes,
+	}
+}
+
+// graphqlRequest is the JSON body of a GraphQL request
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Method != http.MethodPost || !strings.HasSuffix(req.URL.Path, "/graphql") || req.Body == nil {
+		return base.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	// The original request must not be modified, so send a clone with a fresh body
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	if err := t.check(body); err != nil {
+		if !t.LogOnly {
+			return nil, err
+		}
+		slog.Warn("GraphQL operation rejected by budget", "url", req.URL.String(), "error", err)
+	}
+	return base.RoundTrip(out)
+}
+
+// check validates the GraphQL request in body against the budget
+func (t *Transport) check(body []byte) error {
+	var gqlReq graphqlRequest
+	if err := yamlformat.Unmarshal(body, &gqlReq); err != nil || gqlReq.Query == "" {
+		slog.Debug("Passing through request without a GraphQL query", "error", err)
+		return nil
+	}
+
+	doc, err := graphql.Parse(gqlReq.Query)
+	if err != nil {
+		return err
+	}
+	estimate, err := t.Estimator.Estimate(doc, gqlReq.OperationName, gqlReq.Variables)
+	if err != nil {
+		return err
+	}
+	slog.Debug("Estimated GraphQL operation", "operation", estimate.Operation, "cost", estimate.Cost, "nodes", estimate.Nodes)
+
+	if (t.MaxCost > 0 && estimate.Cost > t.MaxCost) || (t.MaxNodes > 0 && estimate.Nodes > t.MaxNodes) {
+		return &BudgetError{Estimate: *estimate, MaxCost: t.MaxCost, MaxNodes: t.MaxNodes}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/apstndb/github-schema-go/usage"
+	"github.com/spf13/cobra"
+)
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Analyze GraphQL operations against the schema",
+}
+
+var analyzeUsageCmd = &cobra.Command{
+	Use:   "usage --operations <dir-or-file>...",
+	Short: "Report which types and fields a set of operations uses",
+	Long: `Report which types and fields a codebase uses, with per-type field coverage.
+Operations are read from .graphql and .gql files; directories are searched
+recursively. Fragments are counted once, where they are defined.
+
+Examples:
+  github-schema analyze usage --operations ./queries/
+  github-schema analyze usage --operations a.graphql --operations b.graphql --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		paths, _ := cmd.Flags().GetStringSlice("operations")
+
+		report, err := analyzeOperations("analyze", paths)
+		if err != nil {
+			return err
+		}
+		return outputResult(report)
+	},
+}
+
+// analyzeOperations builds the usage report of the operations in paths,
+// reporting the files read to --progress as operation
+func analyzeOperations(operation string, paths []string) (*usage.Report, error) {
+	handler, err := progressHandler()
+	if err != nil {
+		return nil, err
+	}
+	files, err := operationFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .graphql or .gql files found")
+	}
+
+	s, err := getSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	a := usage.NewAnalyzer(s)
+	progress := schema.NewProgress(operation, "files", len(files), handler)
+	for _, file := range files {
+		if err := analyzeFile(a, file); err != nil {
+			return nil, progress.Finish(err)
+		}
+		progress.Step(1, file)
+	}
+	progress.Finish(nil)
+
+	report, err := a.Report()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build usage report: %w", err)
+	}
+	return report, nil
+}
+
+func analyzeFile(a *usage.Analyzer, file string) error {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read operations: %w", err)
+	}
+	doc, err := graphql.Parse(string(src))
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	if err := a.Add(doc); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return nil
+}
+
+// operationFiles expands files and directories into the GraphQL files they contain
+func operationFiles(paths []string) ([]string, error) {
+	return collectFiles(paths, ".graphql", ".gql")
+}
+
+// collectFiles expands files and directories into the files with one of the
+// extensions they contain. Files named directly are kept whatever their
+// extension, and a Go-style "/..." suffix on a directory is ignored, since
+// directories are always searched recursively.
+func collectFiles(paths []string, exts ...string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		if path == "..." {
+			path = "."
+		}
+		path = strings.TrimSuffix(path, "/...")
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read operations: %w", err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && slices.Contains(exts, filepath.Ext(p)) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read operations: %w", err)
+		}
+	}
+	return files, nil
+}
+
+func init() {
+	analyzeUsageCmd.Flags().StringSlice("operations", nil, "GraphQL files or directories to analyze")
+	analyzeUsageCmd.MarkFlagRequired("operations")
+
+	analyzeCmd.AddCommand(analyzeUsageCmd)
+	rootCmd.AddCommand(analyzeCmd)
+}
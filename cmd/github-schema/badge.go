@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/apstndb/github-schema-go/badge"
+	"github.com/apstndb/go-yamlformat"
+	"github.com/spf13/cobra"
+)
+
+var badgeCmd = &cobra.Command{
+	Use:   "badge --operations <dir-or-file>...",
+	Short: "Generate badge data summarizing operation validation and deprecated usage",
+	Long: `Validate the operations in .graphql and .gql files against the schema and
+summarize the result as "operations validated: N, deprecated usages: M, schema
+snapshot: DATE" for READMEs and dashboards. Everything is computed locally.
+
+Formats:
+  json      the summary counts, message, and color
+  endpoint  the shields.io endpoint badge format
+  svg       a flat badge image
+
+The snapshot date is --snapshot-date, or the modification date of the --schema
+file; it is "unknown" for the embedded schema.
+
+Examples:
+  github-schema badge --operations ./queries/ --format svg -o docs/graphql.svg
+  github-schema badge --operations ./queries/ --format endpoint -o badge.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		paths, _ := cmd.Flags().GetStringSlice("operations")
+		format, _ := cmd.Flags().GetString("format")
+		outputFile, _ := cmd.Flags().GetString("output")
+		snapshot, _ := cmd.Flags().GetString("snapshot-date")
+
+		if format != "json" && format != "endpoint" && format != "svg" {
+			return fmt.Errorf("unknown badge format %q (supported: json, endpoint, svg)", format)
+		}
+		if snapshot == "" && schemaFile != "" {
+			info, err := os.Stat(schemaFile)
+			if err != nil {
+				return err
+			}
+			snapshot = info.ModTime().UTC().Format(time.DateOnly)
+		}
+
+		report, err := analyzeOperations("badge", paths)
+		if err != nil {
+			return err
+		}
+		summary := badge.Summary{
+			Operations:       report.Operations,
+			DeprecatedUsages: report.DeprecatedUsages,
+			SchemaSnapshot:   snapshot,
+		}
+
+		var data []byte
+		switch format {
+		case "json":
+			data, err = yamlformat.MarshalJSON(map[string]interface{}{
+				"operations":       summary.Operations,
+				"deprecatedUsages": summary.DeprecatedUsages,
+				"schemaSnapshot":   summary.SchemaSnapshot,
+				"message":          summary.Message(),
+				"color":            summary.Color(),
+			})
+		case "endpoint":
+			data, err = yamlformat.MarshalJSON(summary.Endpoint())
+		case "svg":
+			data = summary.SVG()
+		}
+		if err != nil {
+			return err
+		}
+
+		if outputFile == "" {
+			_, err = stdout.Write(data)
+			return err
+		}
+		return os.WriteFile(outputFile, data, 0o644)
+	},
+}
+
+func init() {
+	badgeCmd.Flags().StringSlice("operations", nil, "GraphQL files or directories to validate")
+	badgeCmd.Flags().String("format", "json", "Badge format (json, endpoint, svg)")
+	badgeCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	badgeCmd.Flags().String("snapshot-date", "", "Date of the schema snapshot shown on the badge")
+	badgeCmd.MarkFlagRequired("operations")
+
+	rootCmd.AddCommand(badgeCmd)
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/codegen"
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/spf13/cobra"
+)
+
+var codegenCmd = &cobra.Command{
+	Use:   "codegen",
+	Short: "Generate clients, plan regeneration after schema updates, and render doc comments",
+}
+
+var codegenPlanCmd = &cobra.Command{
+	Use:   "plan --diff <old.json> [new.json] --manifest <codegen.manifest>",
+	Short: "List generated files to regenerate and changes needing manual attention",
+	Long: `Compare the schema code was generated against with an updated schema and list
+the generated files in the manifest that need regeneration, along with breaking
+input changes (removed or retyped input fields and arguments, new required
+inputs, removed enum values) that need manual attention.
+
+The new schema defaults to the --schema file or the embedded schema.
+
+Manifest format, one generated file per line:
+  # comment
+  internal/github/issues_gen.go: Issue IssueConnection Query.repository
+
+Examples:
+  github-schema codegen plan --diff old.json new.json --manifest codegen.manifest
+  github-schema codegen plan --diff old.json --manifest codegen.manifest  # against the embedded schema`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldFile, _ := cmd.Flags().GetString("diff")
+		manifestFile, _ := cmd.Flags().GetString("manifest")
+
+		m, err := codegen.ReadManifest(manifestFile)
+		if err != nil {
+			return err
+		}
+
+		oldSchema, err := schema.NewWithFile(oldFile)
+		if err != nil {
+			return fmt.Errorf("failed to load old schema: %w", err)
+		}
+		var newSchema *schema.Schema
+		if len(args) == 1 {
+			newSchema, err = schema.NewWithFile(args[0])
+		} else {
+			newSchema, err = getSchema()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load new schema: %w", err)
+		}
+
+		plan, err := codegen.NewPlan(oldSchema, newSchema, m)
+		if err != nil {
+			return fmt.Errorf("failed to plan regeneration: %w", err)
+		}
+		return outputResult(plan)
+	},
+}
+
+var codegenGodocCmd = &cobra.Command{
+	Use:   "godoc <Type|Type.member>",
+	Short: "Print the description of a type, field, or enum value as a Go doc comment",
+	Long: `Convert the GraphQL description of a type, field, input field, or enum value
+into a Go doc comment, as a code generator would emit it: paragraphs rewrapped,
+Markdown headings, lists, code blocks, and links in their doc comment forms, and
+a "Deprecated:" paragraph for deprecated members.
+
+Examples:
+  github-schema codegen godoc FileChanges
+  github-schema codegen godoc Repository.isTemplate --name IsTemplate --indent "\t"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		width, _ := cmd.Flags().GetInt("width")
+		indent, _ := cmd.Flags().GetString("indent")
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+		typeName, member, hasMember := strings.Cut(args[0], ".")
+		typeName = resolveTypeName(s, typeName)
+		info, err := s.LookupType(typeName)
+		if err != nil {
+			return err
+		}
+
+		opts := codegen.GodocOptions{Name: name, Width: width, Indent: strings.ReplaceAll(indent, `\t`, "\t")}
+		description := info.Description
+		if hasMember {
+			description, opts.Deprecated, opts.DeprecationReason, err = memberDoc(s, info, member)
+			if err != nil {
+				return err
+			}
+		}
+		_, err = io.WriteString(stdout, codegen.Godoc(description, opts))
+		return err
+	},
+}
+
+var codegenClientCmd = &cobra.Command{
+	Use:   "client --operations <dir-or-file>... -o <dir>",
+	Short: "Generate a typed Go client for GraphQL operations",
+	Long: `Validate the named queries and mutations in .graphql and .gql files against the
+schema and generate a Go client for them into the output directory:
+
+  client.go      Client, whose Execute method authenticates with the token found
+                 in GH_TOKEN, GITHUB_TOKEN, or the login of gh
+  operations.go  a method per operation with typed variables and response
+                 structs, the enums and input objects they use, All methods
+                 paging through connections that take an "after" variable, and
+                 RegisterTypes, which registers a struct for each object type
+                 of a fragment under an interface or union with a
+                 typename.Registry
+
+Fragments may be defined in any of the files. The package name defaults to the
+name of the output directory. --manifest writes a manifest listing the schema
+elements operations.go depends on, for codegen plan.
+
+Examples:
+  github-schema codegen client --operations ./queries/ -o client/
+  github-schema codegen client --operations ./queries/ -o internal/gh --package gh --manifest codegen.manifest`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		paths, _ := cmd.Flags().GetStringSlice("operations")
+		outputDir, _ := cmd.Flags().GetString("output")
+		pkg, _ := cmd.Flags().GetString("package")
+		manifestFile, _ := cmd.Flags().GetString("manifest")
+
+		if pkg == "" {
+			abs, err := filepath.Abs(outputDir)
+			if err != nil {
+				return err
+			}
+			pkg = strings.ReplaceAll(filepath.Base(abs), "-", "")
+		}
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+		files, err := operationFiles(paths)
+		if err != nil {
+			return err
+		}
+		sort.Strings(files)
+		sources := make([]codegen.Source, len(files))
+		for i, file := range files {
+			src, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read operations: %w", err)
+			}
+			sources[i] = codegen.Source{Name: file, Text: string(src)}
+		}
+
+		generated, err := codegen.GenerateClient(s, sources, codegen.ClientOptions{Package: pkg})
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(outputDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		m := &codegen.Manifest{}
+		for _, f := range generated {
+			path := filepath.Join(outputDir, f.Name)
+			if err := os.WriteFile(path, f.Content, 0o644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+			if len(f.Dependencies) > 0 {
+				m.Entries = append(m.Entries, codegen.Entry{File: filepath.ToSlash(path), Dependencies: f.Dependencies})
+			}
+			slog.Info("Generated file", "path", path)
+		}
+		if manifestFile == "" {
+			return nil
+		}
+		var b strings.Builder
+		b.WriteString("# Generated by github-schema codegen client\n")
+		m.WriteTo(&b)
+		if err := os.WriteFile(manifestFile, []byte(b.String()), 0o644); err != nil {
+			return fmt.Errorf("failed to write manifest: %w", err)
+		}
+		return nil
+	},
+}
+
+// memberDoc returns the description and deprecation of an enum value or a
+// field of the type
+func memberDoc(s *schema.Schema, info *schema.TypeInfo, member string) (string, bool, string, error) {
+	for _, v := range info.EnumValues {
+		if v.Name == member {
+			return v.Description, v.IsDeprecated, v.DeprecationReason, nil
+		}
+	}
+	f, err := s.Field(info.Name, resolveFieldName(s, info.Name, member))
+	if err != nil {
+		return "", false, "", err
+	}
+	return f.Description, f.IsDeprecated, f.DeprecationReason, nil
+}
+
+func init() {
+	codegenPlanCmd.Flags().String("diff", "", "Schema file the code was generated against")
+	codegenPlanCmd.Flags().String("manifest", "", "Manifest mapping generated files to their schema dependencies")
+	codegenPlanCmd.MarkFlagRequired("diff")
+	codegenPlanCmd.MarkFlagRequired("manifest")
+
+	codegenGodocCmd.Flags().String("name", "", "Go identifier the comment documents, used to start the first sentence")
+	codegenGodocCmd.Flags().Int("width", 80, "Maximum line width, including the // prefix")
+	codegenGodocCmd.Flags().String("indent", "", `Indentation of every line, with \t for a tab`)
+
+	codegenClientCmd.Flags().StringSlice("operations", nil, "GraphQL files or directories with the operations")
+	codegenClientCmd.Flags().StringP("output", "o", "", "Directory to write client.go and operations.go to")
+	codegenClientCmd.Flags().String("package", "", "Package name of the generated files (default: the output directory name)")
+	codegenClientCmd.Flags().String("manifest", "", "Also write a manifest of the schema dependencies for codegen plan")
+	codegenClientCmd.MarkFlagRequired("operations")
+	codegenClientCmd.MarkFlagRequired("output")
+
+	codegenCmd.AddCommand(codegenPlanCmd, codegenGodocCmd, codegenClientCmd)
+	rootCmd.AddCommand(codegenCmd)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/spf13/cobra"
+)
+
+// completeNames completes the first argument with the names returned by
+// list, matching the prefix case-insensitively like name normalization does
+func completeNames(list func(s *schema.Schema) ([]string, error)) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		s, err := getSchema()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		names, err := list(s)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		prefix := strings.ToLower(toComplete)
+		var matches []string
+		for _, name := range names {
+			if strings.HasPrefix(strings.ToLower(name), prefix) {
+				matches = append(matches, name)
+			}
+		}
+		return matches, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeKind completes type names of the given kinds
+func completeKind(kinds ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return completeNames(func(s *schema.Schema) ([]string, error) {
+		return s.ListTypesOfKind(kinds...)
+	})
+}
+
+// completeList completes names of a list kind such as "mutations"
+func completeList(kind string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return completeNames(func(s *schema.Schema) ([]string, error) {
+		return s.List(kind)
+	})
+}
+
+// completeTypeField completes a Type.field argument: type names followed by a
+// dot, then the fields of the type once the dot is typed
+func completeTypeField(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	typeName, _, ok := strings.Cut(toComplete, ".")
+	if !ok {
+		names, directive := completeList("types")(cmd, args, toComplete)
+		for i, name := range names {
+			names[i] = name + "."
+		}
+		return names, directive | cobra.ShellCompDirectiveNoSpace
+	}
+	return completeNames(func(s *schema.Schema) ([]string, error) {
+		fields, err := s.Fields(resolveTypeName(s, typeName))
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, len(fields))
+		for i, f := range fields {
+			names[i] = typeName + "." + f.Name
+		}
+		return names, nil
+	})(cmd, args, toComplete)
+}
+
+func init() {
+	typeCmd.ValidArgsFunction = completeList("types")
+	mutationCmd.ValidArgsFunction = completeList("mutations")
+	queryFieldCmd.ValidArgsFunction = completeList("queries")
+	enumCmd.ValidArgsFunction = completeKind("ENUM")
+	implementsCmd.ValidArgsFunction = completeKind("INTERFACE")
+	unionCmd.ValidArgsFunction = completeKind("UNION")
+	inputCmd.ValidArgsFunction = completeKind("INPUT_OBJECT")
+	fieldCmd.ValidArgsFunction = completeTypeField
+	listCmd.RegisterFlagCompletionFunc("category", cobra.FixedCompletions(schema.CategoryNames(), cobra.ShellCompDirectiveNoFileComp))
+}
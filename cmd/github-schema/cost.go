@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/apstndb/github-schema-go/budget"
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/go-yamlformat"
+	"github.com/spf13/cobra"
+)
+
+var costCmd = &cobra.Command{
+	Use:   "cost <query.graphql>",
+	Short: "Estimate the rate limit cost of an operation",
+	Long: `Estimate the rate limit cost of an operation the way GitHub computes it: every
+connection is assumed to return as many nodes as its first or last argument
+allows, nested connections multiply, and each 100 connection requests cost one
+point. The result has the connection requests, the maximum node count, and the
+point cost.
+
+First and last values passed as variables are read from --variables, a JSON or
+YAML object; declared defaults apply to variables not given. Select one
+operation of a document with several with --operation-name.
+
+With --budget, an operation over the given points is pruned to fit: first
+and last values of connections with nested connections are halved, those
+saving the most first, and connections are dropped when no page size can go
+lower. The result has the estimates before and after, each reduction, the
+pruned query, and the variables with lowered values.
+
+Examples:
+  github-schema cost issues.graphql
+  github-schema cost issues.graphql --variables '{"first": 100}'
+  github-schema cost operations.graphql --operation-name GetIssues
+  github-schema cost issues.graphql --budget 1`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		operationName, _ := cmd.Flags().GetString("operation-name")
+		variablesText, _ := cmd.Flags().GetString("variables")
+		points, _ := cmd.Flags().GetInt("budget")
+
+		src, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read operation: %w", err)
+		}
+		doc, err := graphql.Parse(string(src))
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		var variables map[string]interface{}
+		if variablesText != "" {
+			if err := yamlformat.Unmarshal([]byte(variablesText), &variables); err != nil {
+				return fmt.Errorf("failed to parse variables: %w", err)
+			}
+		}
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		if cmd.Flags().Changed("budget") {
+			pruning, err := budget.NewEstimator(s).Prune(doc, operationName, variables, points)
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+			return outputResult(pruning)
+		}
+
+		estimate, err := s.EstimateOperationCost(doc, operationName, variables)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		return outputResult(estimate)
+	},
+}
+
+func init() {
+	costCmd.Flags().String("variables", "", "Variables as a JSON or YAML object")
+	costCmd.Flags().String("operation-name", "", "Operation to estimate when the document has several")
+	costCmd.Flags().Int("budget", 0, "Prune the operation to cost at most this many points")
+
+	rootCmd.AddCommand(costCmd)
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/apstndb/go-yamlformat"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// daemonCommands are the commands a daemon answers. They only read the schema
+// and their arguments, never files or stdin of the calling process, except
+// through flags marked with MarkFlagFilename, which make them run locally.
+var daemonCommands = []string{
+	"type", "mutation", "search", "query", "enum", "implements", "union",
+	"query-field", "input", "path", "list", "directives", "deprecated", "fulltext", "grep", "field",
+}
+
+// Set while serving, so getSchema and getLazySchema return the warm schema
+var (
+	warmSchema *schema.Schema
+	warmLazy   *schema.LazySchema
+)
+
+// daemonRequest is a command line forwarded by a client
+type daemonRequest struct {
+	Args []string `json:"args"`
+	Dir  string   `json:"dir"`
+}
+
+// daemonResponse carries the output of a forwarded command. Fallback asks
+// the client to run the command itself.
+type daemonResponse struct {
+	Stdout   string    `json:"stdout"`
+	Stderr   string    `json:"stderr"`
+	Error    string    `json:"error,omitempty"`
+	Details  *cliError `json:"details,omitempty"` // Error as describeError classifies it
+	Fallback bool      `json:"fallback,omitempty"`
+}
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Serve commands from a warm, indexed schema over a local socket",
+	Long: `Keep a parsed and indexed schema in memory and answer commands over a unix
+socket, so shell completion and rapid successive calls skip loading the schema.
+
+While a daemon is listening, the schema lookup commands (type, mutation, enum,
+list, path, and so on) and shell completion are forwarded to it automatically.
+Commands for a different --schema file, with profiling flags, or reading files
+or stdin still run locally. Pass --no-daemon to never forward.
+
+The socket defaults to daemon.sock in the schema cache directory and can be
+changed with $GITHUB_SCHEMA_SOCKET.
+
+Example:
+  github-schema daemon &
+  github-schema type pullrequest`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		socket, err := daemonSocket()
+		if err != nil {
+			return err
+		}
+		if schemaFile != "" {
+			if schemaFile, err = filepath.Abs(schemaFile); err != nil {
+				return err
+			}
+		}
+
+		start := time.Now()
+		if warmSchema, err = getSchema(); err != nil {
+			return err
+		}
+		// Read onto the heap even with --mmap, since commands close the lazy schema
+		if schemaFile != "" {
+			warmLazy, err = schema.NewLazyWithFile(schemaFile)
+		} else {
+			warmLazy, err = schema.NewLazy()
+		}
+		if err != nil {
+			return err
+		}
+
+		listener, err := listenDaemon(socket)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(socket)
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-signals
+			listener.Close()
+		}()
+
+		slog.Info("Daemon listening", "socket", socket, "schema", schemaFile, "load_ms", time.Since(start).Milliseconds())
+		d := &daemon{schemaFile: schemaFile, logger: slog.Default()}
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if errors.Is(err, net.ErrClosed) {
+					return nil
+				}
+				return fmt.Errorf("failed to accept connection: %w", err)
+			}
+			go d.handle(conn)
+		}
+	},
+}
+
+var noDaemon bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noDaemon, "no-daemon", false, "Run locally even when a daemon is listening")
+
+	rootCmd.AddCommand(daemonCmd)
+}
+
+// daemonSocket returns the socket path shared by the daemon and its clients
+func daemonSocket() (string, error) {
+	if socket := os.Getenv("GITHUB_SCHEMA_SOCKET"); socket != "" {
+		return socket, nil
+	}
+	dir, err := schema.DefaultCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "daemon.sock"), nil
+}
+
+// listenDaemon listens on socket, replacing a stale socket file left by a
+// daemon that did not shut down cleanly
+func listenDaemon(socket string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(socket), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	if conn, err := net.Dial("unix", socket); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("a daemon is already listening on %s", socket)
+	}
+	os.Remove(socket)
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+	return listener, nil
+}
+
+// daemon runs forwarded command lines one at a time, since commands share
+// global flag variables
+type daemon struct {
+	mu         sync.Mutex
+	schemaFile string
+	logger     *slog.Logger // Daemon's own log; the default logger is swapped per request
+}
+
+// readMessage reads one message of the daemon protocol, a JSON document on a
+// single line as the JSON encoder writes it
+func readMessage(r io.Reader, v interface{}) error {
+	line, err := bufio.NewReader(r).ReadBytes('\n')
+	if err != nil && (err != io.EOF || len(line) == 0) {
+		return err
+	}
+	return yamlformat.Unmarshal(line, v)
+}
+
+func (d *daemon) handle(conn net.Conn) {
+	defer conn.Close()
+
+	var req daemonRequest
+	if err := readMessage(conn, &req); err != nil {
+		d.logger.Debug("Invalid daemon request", "error", err)
+		return
+	}
+	start := time.Now()
+	resp := d.run(req)
+	d.logger.Debug("Served request", "args", req.Args, "fallback", resp.Fallback, "elapsed", time.Since(start))
+	if err := yamlformat.NewJSONEncoder(conn).Encode(resp); err != nil {
+		d.logger.Debug("Failed to write daemon response", "error", err)
+	}
+}
+
+func (d *daemon) run(req daemonRequest) *daemonResponse {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	fallback := &daemonResponse{Fallback: true}
+	cmd, flags, err := rootCmd.Find(completionTarget(req.Args))
+	if err != nil || !slices.Contains(daemonCommands, cmd.Name()) {
+		return fallback
+	}
+
+	// Check the global flags before running anything
+	resetFlags(rootCmd)
+	defer resetFlags(rootCmd)
+	cmd.ParseFlags(flags)
+	file := schemaFile
+	if file != "" && !filepath.IsAbs(file) {
+		file = filepath.Join(req.Dir, file)
+	}
+	if file != d.schemaFile || cpuProfile != "" || memProfile != "" || traceFile != "" || readsFiles(cmd) {
+		return fallback
+	}
+	var out, errOut bytes.Buffer
+	logger, err := newLogger(&errOut)
+	resetFlags(rootCmd)
+	if err != nil {
+		return &daemonResponse{Error: err.Error()}
+	}
+
+	slog.SetDefault(logger)
+	stdout = &out
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&errOut)
+	rootCmd.SetArgs(req.Args)
+	defer func() {
+		slog.SetDefault(d.logger)
+		stdout = os.Stdout
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+	}()
+
+	resp := &daemonResponse{}
+	if err := rootCmd.Execute(); err != nil {
+		resp.Error, resp.Details = err.Error(), describeError(err)
+	}
+	resp.Stdout = out.String()
+	resp.Stderr = errOut.String()
+	return resp
+}
+
+// readsFiles reports whether a flag naming a file of the caller is set
+func readsFiles(cmd *cobra.Command) bool {
+	found := false
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if _, ok := f.Annotations[cobra.BashCompFilenameExt]; ok {
+			found = true
+		}
+	})
+	return found
+}
+
+// completionTarget returns the command line that a shell completion request
+// completes, or args itself for other requests
+func completionTarget(args []string) []string {
+	if len(args) > 0 && (args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd) {
+		return args[1:]
+	}
+	return args
+}
+
+// resetFlags restores every flag of cmd and its subcommands to its default,
+// so one forwarded command line does not leak into the next
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if v, ok := f.Value.(pflag.SliceValue); ok {
+			v.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+// forwardToDaemon runs args on a listening daemon. It reports false when the
+// command should run locally: no daemon is listening, the daemon declined,
+// or the exchange failed.
+func forwardToDaemon(args []string) (bool, error) {
+	if slices.Contains(args, "--no-daemon") {
+		return false, nil
+	}
+	cmd, _, err := rootCmd.Find(completionTarget(args))
+	if err != nil || !slices.Contains(daemonCommands, cmd.Name()) {
+		return false, nil
+	}
+	socket, err := daemonSocket()
+	if err != nil {
+		return false, nil
+	}
+	conn, err := net.DialTimeout("unix", socket, 100*time.Millisecond)
+	if err != nil {
+		return false, nil
+	}
+	defer conn.Close()
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return false, nil
+	}
+	var resp daemonResponse
+	if err := yamlformat.NewJSONEncoder(conn).Encode(daemonRequest{Args: args, Dir: dir}); err != nil {
+		slog.Debug("Failed to send daemon request", "error", err)
+		return false, nil
+	}
+	if err := readMessage(conn, &resp); err != nil {
+		slog.Debug("Failed to read daemon response", "error", err)
+		return false, nil
+	}
+	if resp.Fallback {
+		return false, nil
+	}
+
+	os.Stdout.WriteString(resp.Stdout)
+	os.Stderr.WriteString(resp.Stderr)
+	if resp.Details != nil {
+		return true, resp.Details
+	}
+	if resp.Error != "" {
+		return true, errors.New(resp.Error)
+	}
+	return true, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/apstndb/github-schema-go/ack"
+	"github.com/apstndb/github-schema-go/report"
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/spf13/cobra"
+)
+
+var deprecatedCmd = &cobra.Command{
+	Use:   "deprecated",
+	Short: "List deprecated fields, arguments, and enum values grouped by type",
+	Long: `List deprecated fields, field arguments, input fields, and enum values
+grouped by type. Arguments are named field.argument, as in
+Repository.issues.orderBy.
+
+With --ack, members listed in an acknowledgments file are left out until their
+expiry date, and the command fails if any deprecated member is not acknowledged
+or any acknowledgment has expired. Each line of the file reads
+"Type.member: reason, YYYY-MM-DD", with Type.field.argument for arguments.
+
+With --baseline, members listed in an earlier --report output are left out too,
+and the command fails if any other deprecated member remains, so a large schema
+dependency can adopt the check without fixing everything first.
+
+Examples:
+  github-schema deprecated
+  github-schema deprecated --ack deprecations.ack
+  github-schema deprecated --report > baseline.json
+  github-schema deprecated --baseline baseline.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ackFile, _ := cmd.Flags().GetString("ack")
+
+		baseline, err := loadBaseline(cmd)
+		if err != nil {
+			return err
+		}
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		deprecated, err := s.Deprecated()
+		if err != nil {
+			return fmt.Errorf("failed to query deprecated members: %w", err)
+		}
+
+		var result *ack.Result
+		if ackFile != "" {
+			acks, err := ack.NewWithFile(ackFile)
+			if err != nil {
+				return err
+			}
+			deprecated, result = acks.FilterDeprecated(deprecated, time.Now())
+			for _, a := range result.Expired {
+				slog.Error("Acknowledgment expired", "member", a.Member, "expires", a.Expires.Format(time.DateOnly), "reason", a.Reason, "line", a.Line)
+			}
+			for _, a := range result.Unused {
+				slog.Warn("Acknowledgment matches no deprecated member", "member", a.Member, "line", a.Line)
+			}
+		}
+		if baseline != nil {
+			deprecated = filterDeprecatedBaseline(deprecated, baseline)
+		}
+
+		if reportRequested(cmd) {
+			r := report.FromDeprecated(deprecated, s.Fingerprint())
+			if result != nil {
+				for _, a := range result.Expired {
+					r.Findings = append(r.Findings, report.Finding{
+						Rule:     "expired-acknowledgment",
+						Severity: report.Error,
+						Message:  fmt.Sprintf("Acknowledgment of %s expired on %s", a.Member, a.Expires.Format(time.DateOnly)),
+						Paths:    []string{a.Member},
+						Location: &report.Location{File: ackFile, Line: a.Line},
+					})
+				}
+			}
+			err = outputReport(r)
+		} else if result != nil {
+			err = outputResult(map[string]interface{}{
+				"deprecated":   deprecated,
+				"acknowledged": result.Suppressed,
+				"expired":      result.Expired,
+			})
+		} else {
+			err = outputResult(map[string]interface{}{
+				"deprecated": deprecated,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		members := 0
+		for _, t := range deprecated {
+			members += len(t.Members())
+		}
+		switch {
+		case result != nil && (members > 0 || len(result.Expired) > 0):
+			return fmt.Errorf("%d unacknowledged deprecated member(s), %d expired acknowledgment(s)", members, len(result.Expired))
+		case baseline != nil && members > 0:
+			return fmt.Errorf("%d deprecated member(s) not in the baseline", members)
+		}
+		return nil
+	},
+}
+
+// filterDeprecatedBaseline leaves out the deprecated members in the baseline
+func filterDeprecatedBaseline(deprecated []schema.DeprecatedType, baseline *report.Baseline) []schema.DeprecatedType {
+	keep := func(typeName string, members []schema.DeprecatedMember) []schema.DeprecatedMember {
+		var kept []schema.DeprecatedMember
+		for _, m := range members {
+			if !baseline.Known(report.DeprecationFinding(typeName, m.Name, m.DeprecationReason)) {
+				kept = append(kept, m)
+			}
+		}
+		return kept
+	}
+	var fresh []schema.DeprecatedType
+	for _, t := range deprecated {
+		t.Fields = keep(t.Name, t.Fields)
+		t.Arguments = keep(t.Name, t.Arguments)
+		t.InputFields = keep(t.Name, t.InputFields)
+		t.EnumValues = keep(t.Name, t.EnumValues)
+		if len(t.Members()) > 0 {
+			fresh = append(fresh, t)
+		}
+	}
+	logBaseline(baseline)
+	return fresh
+}
+
+func init() {
+	deprecatedCmd.Flags().String("ack", "", "Acknowledgments file suppressing known deprecations until a date")
+	deprecatedCmd.MarkFlagFilename("ack")
+	addReportFlag(deprecatedCmd)
+	addBaselineFlag(deprecatedCmd)
+
+	rootCmd.AddCommand(deprecatedCmd)
+}
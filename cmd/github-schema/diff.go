@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apstndb/github-schema-go/feed"
+	"github.com/apstndb/github-schema-go/report"
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old.json> <new.json> | --against-embedded <new.json>",
+	Short: "List the changes between two schema files",
+	Long: `Compare two introspection results and list the added, removed, and changed
+types, fields, arguments, input fields, enum values, and directives. Each change
+has a type named as in GraphQL Inspector (such as FIELD_REMOVED), the path of
+the changed element, and the old and new values where they apply.
+
+Each change is classified as BREAKING (existing operations become invalid, such
+as a removed field), DANGEROUS (operations stay valid but may behave
+differently, such as a new optional argument or enum value), or NON_BREAKING
+(such as a new type), following GraphQL Inspector. The command exits with a
+non-zero status when there are breaking changes, so it can gate CI pipelines.
+
+With --against-embedded, the old schema is the one embedded in the binary, to
+review a freshly downloaded schema without keeping the previous file around.
+
+With --atom, an entry listing the changes and entries for the removals the new
+schema schedules are merged into an Atom feed file, created if needed, so that
+a scheduled download and diff publish a feed for readers and chat integrations
+to subscribe to (see the feed command to serve it).
+
+Examples:
+  github-schema diff old.json new.json
+  github-schema diff old.json new.json --json | jq '.changes[] | select(.type == "FIELD_REMOVED")'
+  github-schema download -o new.json && github-schema diff --against-embedded new.json
+  github-schema diff old.json new.json --report > diff-report.json
+  github-schema diff old.json new.json --atom schema.atom`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if embedded, _ := cmd.Flags().GetBool("against-embedded"); embedded {
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		againstEmbedded, _ := cmd.Flags().GetBool("against-embedded")
+		handler, err := progressHandler()
+		if err != nil {
+			return err
+		}
+
+		newSchema, err := schema.NewWithFileStrict(args[len(args)-1])
+		if err != nil {
+			return fmt.Errorf("failed to load new schema: %w", err)
+		}
+		var oldSchema *schema.Schema
+		oldFingerprint := schema.EmbeddedInfo().Fingerprint
+		if againstEmbedded {
+			if oldSchema, err = schema.Default(); err != nil {
+				return fmt.Errorf("failed to load embedded schema: %w", err)
+			}
+		} else {
+			if oldSchema, err = schema.NewWithFileStrict(args[0]); err != nil {
+				return fmt.Errorf("failed to load old schema: %w", err)
+			}
+			oldFingerprint = oldSchema.Fingerprint()
+		}
+		changes := oldSchema.DiffWithEvents(newSchema, handler)
+
+		if atomFile, _ := cmd.Flags().GetString("atom"); atomFile != "" {
+			if err := publishChanges(cmd, atomFile, changes, oldFingerprint, newSchema); err != nil {
+				return err
+			}
+		}
+
+		breaking := len(changes.Filter(schema.Breaking))
+		if reportRequested(cmd) {
+			err = outputReport(report.FromChangeSet(changes, newSchema.Fingerprint()))
+		} else {
+			err = outputResult(map[string]interface{}{
+				"count":       len(changes.Changes),
+				"breaking":    breaking,
+				"dangerous":   len(changes.Filter(schema.Dangerous)),
+				"nonBreaking": len(changes.Filter(schema.NonBreaking)),
+				"changes":     changes.Changes,
+			})
+		}
+		if err != nil {
+			return err
+		}
+		if breaking > 0 {
+			return fmt.Errorf("%d breaking change(s) detected", breaking)
+		}
+		return nil
+	},
+}
+
+// publishChanges merges the entries for changes and the removals scheduled
+// by newSchema into the feed file at path. Entries are dated when the new
+// schema was downloaded, if it records it.
+func publishChanges(cmd *cobra.Command, path string, changes *schema.ChangeSet, oldFingerprint string, newSchema *schema.Schema) error {
+	title, _ := cmd.Flags().GetString("atom-title")
+	limit, _ := cmd.Flags().GetInt("atom-limit")
+
+	at := time.Now()
+	if meta := newSchema.Metadata(); meta != nil && !meta.DownloadedAt.IsZero() {
+		at = meta.DownloadedAt
+	}
+	removals, err := newSchema.ScheduledRemovals()
+	if err != nil {
+		return err
+	}
+	entries := feed.ChangeEntries(changes, oldFingerprint, newSchema.Fingerprint(), at)
+	entries = append(entries, feed.RemovalEntries(removals, at)...)
+	_, err = updateFeed(path, title, limit, entries)
+	return err
+}
+
+func init() {
+	diffCmd.Flags().Bool("against-embedded", false, "Compare the embedded schema with the given schema file")
+	diffCmd.Flags().String("atom", "", "Merge the changes and scheduled removals into this Atom feed file")
+	addFeedFlags(diffCmd, "atom-")
+	addReportFlag(diffCmd)
+
+	rootCmd.AddCommand(diffCmd)
+}
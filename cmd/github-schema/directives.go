@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var directivesCmd = &cobra.Command{
+	Use:   "directives",
+	Short: "List directives with their locations and arguments",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		directives, err := s.Directives()
+		if err != nil {
+			return fmt.Errorf("failed to query directives: %w", err)
+		}
+
+		return outputResult(map[string]interface{}{
+			"directives": directives,
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(directivesCmd)
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	buildinfo "runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/spf13/cobra"
+)
+
+// libraryModule is the module of the schema package, for comparing the
+// version of the library a CLI was built with to the CLI's own
+const libraryModule = "github.com/apstndb/github-schema-go"
+
+// doctorCheck is the outcome of one check of the doctor command
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // ok, warn, fail, or skip
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the local installation and print fixes for what is wrong",
+	Long: `Check the environment the tool runs in and print a fix for every problem:
+
+  embedded  The embedded schema decompresses, is well-formed, and matches
+            the fingerprint recorded when it was embedded
+  schema    The --schema file, when given, loads and is well-formed
+  cache     The parsed schema cache directory is usable and writable
+  auth      A GitHub token is available for downloads and invoke
+  registry  The target registry parses and its schema files exist
+  remotes   The endpoints of registered targets answer (--online only)
+  version   The CLI and the schema library it was built with agree
+  daemon    Whether a daemon is serving commands
+
+Nothing is sent over the network unless --online is given, so the command
+is safe to run on air-gapped machines. Checks that fail make the command
+exit with a non-zero status; warnings do not.
+
+Examples:
+  github-schema doctor
+  github-schema doctor --online --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		online, _ := cmd.Flags().GetBool("online")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		checks := []doctorCheck{checkEmbedded(), checkSchemaFile(), checkCache(), checkAuth()}
+		targets, registryCheck := checkRegistry()
+		checks = append(checks, registryCheck)
+		checks = append(checks, checkRemotes(cmd.Context(), targets, online, timeout)...)
+		checks = append(checks, checkVersion(), checkDaemon())
+
+		failed, warnings := 0, 0
+		for _, c := range checks {
+			switch c.Status {
+			case "fail":
+				failed++
+			case "warn":
+				warnings++
+			}
+		}
+		if err := outputResult(map[string]interface{}{
+			"checks":   checks,
+			"failed":   failed,
+			"warnings": warnings,
+		}); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+		}
+		return nil
+	},
+}
+
+func checkEmbedded() doctorCheck {
+	check := doctorCheck{Name: "embedded"}
+	if err := schema.VerifyEmbedded(); err != nil {
+		check.Status, check.Detail = "fail", err.Error()
+		check.Fix = "reinstall github-schema; the binary is damaged or was built from a schema without go generate ./schema"
+		return check
+	}
+	info := schema.EmbeddedInfo()
+	check.Status = "ok"
+	check.Detail = "fingerprint " + info.Fingerprint[:12]
+	if !info.CapturedAt.IsZero() {
+		check.Detail += ", captured " + info.CapturedAt.Format(time.DateOnly)
+	}
+	if info.Options == schema.LegacyQueryOptions {
+		check.Detail += ", with the legacy introspection query"
+	}
+	return check
+}
+
+func checkSchemaFile() doctorCheck {
+	check := doctorCheck{Name: "schema"}
+	if schemaFile == "" {
+		check.Status, check.Detail = "skip", "no --schema file given; the embedded schema is used"
+		return check
+	}
+	s, err := schema.NewWithFileStrict(schemaFile)
+	if err != nil {
+		check.Status, check.Detail = "fail", err.Error()
+		check.Fix = fmt.Sprintf("download it again with 'github-schema download -o %s', or check that it is an introspection result or SDL", schemaFile)
+		return check
+	}
+	check.Status = "ok"
+	check.Detail = fmt.Sprintf("%s, fingerprint %s", schemaFile, s.Fingerprint()[:12])
+	if meta := s.Metadata(); meta != nil {
+		check.Detail += ", downloaded " + meta.DownloadedAt.Format(time.DateOnly)
+	}
+	return check
+}
+
+func checkCache() doctorCheck {
+	check := doctorCheck{Name: "cache"}
+	dir := cacheDir
+	if dir == "" {
+		var err error
+		if dir, err = schema.DefaultCacheDir(); err != nil {
+			check.Status, check.Detail = "warn", err.Error()
+			check.Fix = "pass --cache-dir to use a cache"
+			return check
+		}
+	}
+	inUse := useCache || cacheDir != ""
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		check.Status, check.Detail = "ok", dir+" does not exist yet; it is created when --cache is first used"
+		return check
+	}
+	if err != nil {
+		check.Status, check.Detail = "warn", err.Error()
+		check.Fix = "pass --cache-dir with a readable directory"
+		return check
+	}
+
+	count, size := 0, int64(0)
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && strings.HasSuffix(e.Name(), ".gob") {
+			count++
+			size += info.Size()
+		}
+	}
+	check.Status = "ok"
+	check.Detail = fmt.Sprintf("%s: %d entries, %d bytes", dir, count, size)
+	tmp, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		check.Status = "warn"
+		if inUse {
+			check.Status = "fail"
+		}
+		check.Detail += ", not writable"
+		check.Fix = "pass --cache-dir with a writable directory, or fix the permissions of " + dir
+		return check
+	}
+	tmp.Close()
+	os.Remove(tmp.Name())
+	return check
+}
+
+func checkAuth() doctorCheck {
+	check := doctorCheck{Name: "auth"}
+	cred := schema.ResolveCredential("")
+	if cred.Token == "" {
+		check.Status = "warn"
+		check.Detail = "no GitHub token found (tried " + strings.Join(cred.Tried, "; ") + ")"
+		check.Fix = "set GH_TOKEN or run 'gh auth login'; only download, sync, and invoke need a token"
+		return check
+	}
+	check.Status, check.Detail = "ok", "token from "+cred.Source
+	return check
+}
+
+// checkRegistry also returns the registered targets for checkRemotes
+func checkRegistry() ([]schema.Target, doctorCheck) {
+	check := doctorCheck{Name: "registry"}
+	path, err := registryPath()
+	if err != nil {
+		check.Status, check.Detail = "warn", err.Error()
+		check.Fix = "pass --registry to use targets"
+		return nil, check
+	}
+	r, err := schema.LoadRegistry(path)
+	if err != nil {
+		check.Status, check.Detail = "fail", err.Error()
+		check.Fix = "fix the YAML of " + path + ", or move it away and register the targets again with 'github-schema target add'"
+		return nil, check
+	}
+	if len(r.Targets) == 0 {
+		check.Status, check.Detail = "ok", "no targets registered in "+path
+		return nil, check
+	}
+
+	var missing []string
+	for _, t := range r.Targets {
+		if _, err := os.Stat(t.Path); err != nil {
+			missing = append(missing, t.Name)
+		}
+	}
+	check.Status = "ok"
+	check.Detail = fmt.Sprintf("%s: %d targets", path, len(r.Targets))
+	if len(missing) > 0 {
+		check.Status = "warn"
+		check.Detail += fmt.Sprintf(", schema files missing for %s", strings.Join(missing, ", "))
+		check.Fix = "run 'github-schema sync " + strings.Join(missing, " ") + "', or remove the targets with 'github-schema target remove'"
+	}
+	return r.Targets, check
+}
+
+// checkRemotes checks that the endpoint of every target answers HTTP
+// requests; any response counts, since GraphQL endpoints reject requests
+// without a query or token
+func checkRemotes(ctx context.Context, targets []schema.Target, online bool, timeout time.Duration) []doctorCheck {
+	if !online {
+		return []doctorCheck{{Name: "remotes", Status: "skip", Detail: "not checked; use --online to contact the endpoints of registered targets"}}
+	}
+	var checks []doctorCheck
+	client := &http.Client{Timeout: timeout}
+	for _, t := range targets {
+		if t.Endpoint == "" {
+			continue
+		}
+		check := doctorCheck{Name: "remotes/" + t.Name}
+		req, err := http.NewRequestWithContext(ctx, "GET", t.Endpoint, nil)
+		if err != nil {
+			check.Status, check.Detail = "fail", err.Error()
+			check.Fix = "fix the endpoint of target " + t.Name + " in the registry"
+			checks = append(checks, check)
+			continue
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			check.Status, check.Detail = "fail", err.Error()
+			check.Fix = "check the network, proxy settings (HTTPS_PROXY), and the endpoint of target " + t.Name
+			checks = append(checks, check)
+			continue
+		}
+		resp.Body.Close()
+		check.Status, check.Detail = "ok", t.Endpoint+" answered "+resp.Status
+		checks = append(checks, check)
+	}
+	if len(checks) == 0 {
+		return []doctorCheck{{Name: "remotes", Status: "skip", Detail: "no registered target has an endpoint"}}
+	}
+	return checks
+}
+
+func checkVersion() doctorCheck {
+	check := doctorCheck{Name: "version"}
+	info, ok := buildinfo.ReadBuildInfo()
+	if !ok {
+		check.Status, check.Detail = "skip", "the binary has no build information"
+		return check
+	}
+	cli, library := info.Main.Version, info.Main.Version
+	if info.Main.Path != libraryModule {
+		library = ""
+		for _, dep := range info.Deps {
+			if dep.Path != libraryModule {
+				continue
+			}
+			library = dep.Version
+			if dep.Replace != nil {
+				library = dep.Replace.Version + " (replaced by " + dep.Replace.Path + ")"
+			}
+		}
+	}
+	check.Status = "ok"
+	check.Detail = fmt.Sprintf("CLI %s, library %s, %s", cli, library, info.GoVersion)
+	if released(cli) && released(library) && cli != library {
+		check.Status = "warn"
+		check.Fix = "rebuild the CLI against the same library version, or install a matching release with 'go install " + libraryModule + "/cmd/github-schema@" + cli + "'"
+	}
+	return check
+}
+
+// released reports whether version is a module version rather than a
+// development build
+func released(version string) bool {
+	return version != "" && version != "(devel)" && !strings.Contains(version, "(replaced")
+}
+
+func checkDaemon() doctorCheck {
+	check := doctorCheck{Name: "daemon", Status: "ok"}
+	socket, err := daemonSocket()
+	if err != nil {
+		check.Status, check.Detail = "skip", err.Error()
+		return check
+	}
+	conn, err := net.DialTimeout("unix", socket, 100*time.Millisecond)
+	if err != nil {
+		check.Detail = "not running"
+		return check
+	}
+	conn.Close()
+	check.Detail = "listening on " + socket + "; restart it after upgrading or changing --schema so it serves the same schema"
+	return check
+}
+
+func init() {
+	doctorCmd.Flags().Bool("online", false, "Also check that the endpoints of registered targets answer")
+	doctorCmd.Flags().Duration("timeout", 5*time.Second, "Timeout for each endpoint with --online")
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var enumCmd = &cobra.Command{
+	Use:   "enum <EnumName>",
+	Short: "Show values and deprecation status of an enum",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := getLazySchema()
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+
+		name := resolveTypeName(s, args[0])
+		values, err := s.EnumValues(name)
+		if err != nil {
+			return fmt.Errorf("failed to query enum: %w", err)
+		}
+
+		return outputResult(map[string]interface{}{
+			"enum": map[string]interface{}{
+				"name":   name,
+				"values": values,
+			},
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(enumCmd)
+}
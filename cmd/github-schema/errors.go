@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/apstndb/github-schema-go/schema/introspect"
+	"github.com/apstndb/go-yamlformat"
+	"github.com/spf13/cobra"
+)
+
+// cliError is a failed command as --json reports it on stderr:
+//
+//	{"error": {"code": "type_not_found", "message": "...", "suggestions": ["Issue"]}}
+//
+// Code is one of the values describeError assigns, so wrapping tools can
+// branch on it without parsing the message.
+type cliError struct {
+	Code        string   `json:"code"`
+	Message     string   `json:"message"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+func (e *cliError) Error() string {
+	return e.Message
+}
+
+func init() {
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &cliError{Code: "usage", Message: err.Error()}
+	})
+}
+
+// describeError classifies err for reporting
+func describeError(err error) *cliError {
+	var (
+		described *cliError
+		notFound  *schema.NotFoundError
+		status    *introspect.StatusError
+	)
+	switch {
+	case errors.As(err, &described):
+		return described
+	case errors.As(err, &notFound):
+		return &cliError{Code: notFound.Kind + "_not_found", Message: err.Error(), Suggestions: notFound.Suggestions}
+	case errors.Is(err, schema.ErrInvalidSchema):
+		return &cliError{Code: "invalid_schema", Message: err.Error()}
+	case errors.As(err, &status) && status.RateLimited:
+		return &cliError{Code: "rate_limited", Message: err.Error()}
+	case errors.As(err, &status):
+		return &cliError{Code: "http_error", Message: err.Error()}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &cliError{Code: "timeout", Message: err.Error()}
+	case errors.Is(err, fs.ErrNotExist):
+		return &cliError{Code: "file_not_found", Message: err.Error()}
+	case strings.HasPrefix(err.Error(), "unknown command "):
+		// cobra appends its suggestions to the message on further lines
+		message, _, _ := strings.Cut(err.Error(), "\n")
+		e := &cliError{Code: "unknown_command", Message: message}
+		var name, path string
+		if _, scanErr := fmt.Sscanf(message, "unknown command %q for %q", &name, &path); scanErr == nil {
+			if parent, _, findErr := rootCmd.Find(strings.Fields(path)[1:]); findErr == nil {
+				e.Suggestions = parent.SuggestionsFor(name)
+			}
+		}
+		return e
+	}
+	return &cliError{Code: "error", Message: err.Error()}
+}
+
+// reportError reports the error a command failed with: as a JSON object on w
+// with --json, logged otherwise
+func reportError(w io.Writer, err error) {
+	if !outputJSON {
+		slog.Error("Command failed", "error", err)
+		return
+	}
+	if encodeErr := yamlformat.NewJSONEncoder(w).Encode(map[string]*cliError{"error": describeError(err)}); encodeErr != nil {
+		slog.Error("Failed to encode error", "error", encodeErr, "cause", err)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/go-yamlformat"
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <query.graphql>",
+	Short: "Show where the cost of an operation is spent",
+	Long: `Lay out an operation as a tree of its selections, each annotated with its
+resolved type, the page size of connections, how many times it is resolved,
+and the connection requests and nodes of its branch with their share of the
+operation's requests. Costs are estimated as by the cost command, and
+variables and --operation-name are read the same way.
+
+Formats:
+  tree     Indented text tree (default)
+  mermaid  Mermaid flowchart for pull requests and docs; connections and
+           branches with half or more of the requests are highlighted
+  yaml     The plan as data (json with --json)
+
+Examples:
+  github-schema explain issues.graphql
+  github-schema explain issues.graphql --format mermaid
+  github-schema explain operations.graphql --operation-name GetIssues --variables '{"first": 100}'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		operationName, _ := cmd.Flags().GetString("operation-name")
+		variablesText, _ := cmd.Flags().GetString("variables")
+		format, _ := cmd.Flags().GetString("format")
+
+		if format != "tree" && format != "mermaid" && format != "yaml" {
+			return fmt.Errorf("unknown explain format %q (supported: tree, mermaid, yaml)", format)
+		}
+		src, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read operation: %w", err)
+		}
+		doc, err := graphql.Parse(string(src))
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		var variables map[string]interface{}
+		if variablesText != "" {
+			if err := yamlformat.Unmarshal([]byte(variablesText), &variables); err != nil {
+				return fmt.Errorf("failed to parse variables: %w", err)
+			}
+		}
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+		plan, err := s.ExplainOperation(doc, operationName, variables)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		switch format {
+		case "tree":
+			_, err = io.WriteString(stdout, plan.Tree())
+		case "mermaid":
+			_, err = io.WriteString(stdout, plan.Mermaid())
+		case "yaml":
+			err = outputResult(plan)
+		}
+		return err
+	},
+}
+
+func init() {
+	explainCmd.Flags().String("variables", "", "Variables as a JSON or YAML object")
+	explainCmd.Flags().String("operation-name", "", "Operation to explain when the document has several")
+	explainCmd.Flags().String("format", "tree", "Output format (tree, mermaid, yaml)")
+
+	rootCmd.AddCommand(explainCmd)
+}
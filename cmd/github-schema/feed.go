@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/apstndb/github-schema-go/feed"
+	"github.com/spf13/cobra"
+)
+
+var feedCmd = &cobra.Command{
+	Use:   "feed [-o schema.atom] [--serve ADDR]",
+	Short: "Publish the scheduled removals of the schema as an Atom feed",
+	Long: `Write an Atom feed with an entry for every day on which deprecated members
+of the schema are scheduled to be removed, as GitHub announces with "Removal on
+YYYY-MM-DD UTC" in deprecation reasons. Removal days that are past are left out.
+
+With -o, the entries are merged into the feed file, which is created if
+needed; entries of earlier runs, such as those 'diff --atom' adds for schema
+changes, are kept, and entries whose content did not change keep their date,
+so feed readers only show what is new.
+
+With --serve, the feed is served over HTTP at ADDR, so feed readers and chat
+integrations can subscribe to it. When -o is also given the file is read on
+every request, so entries added later by 'diff --atom' are served too.
+
+Examples:
+  github-schema feed --schema schema.json
+  github-schema feed --schema schema.json -o schema.atom
+  github-schema feed -o schema.atom --serve :8080`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFile, _ := cmd.Flags().GetString("output")
+		title, _ := cmd.Flags().GetString("title")
+		limit, _ := cmd.Flags().GetInt("limit")
+		addr, _ := cmd.Flags().GetString("serve")
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+		removals, err := s.ScheduledRemovals()
+		if err != nil {
+			return err
+		}
+		entries := feed.RemovalEntries(removals, time.Now())
+
+		var f *feed.Feed
+		if outputFile != "" {
+			if f, err = updateFeed(outputFile, title, limit, entries); err != nil {
+				return err
+			}
+		} else {
+			f = feed.New(title)
+			f.Add(entries...)
+			f.Trim(limit)
+		}
+		if addr == "" {
+			if outputFile != "" {
+				return nil
+			}
+			data, err := f.Marshal()
+			if err != nil {
+				return err
+			}
+			_, err = stdout.Write(data)
+			return err
+		}
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			served := f
+			if outputFile != "" {
+				data, err := os.ReadFile(outputFile)
+				if err == nil {
+					served, err = feed.Parse(data)
+				}
+				if err != nil {
+					slog.Error("Failed to read feed", "file", outputFile, "error", err)
+					http.Error(w, "failed to read feed", http.StatusInternalServerError)
+					return
+				}
+			}
+			copied := *served
+			copied.Links = []feed.Link{{Href: "http://" + r.Host + r.URL.Path, Rel: "self"}}
+			data, err := copied.Marshal()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+			w.Write(data)
+		})
+		slog.Info("Serving feed", "addr", addr, "file", outputFile)
+		return http.ListenAndServe(addr, handler)
+	},
+}
+
+// updateFeed adds entries to the feed file at path, creating it titled title
+// if it does not exist, keeps its limit newest entries, and writes it back
+func updateFeed(path, title string, limit int, entries []feed.Entry) (*feed.Feed, error) {
+	f := feed.New(title)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if f, err = feed.Parse(data); err != nil {
+			return nil, fmt.Errorf("failed to load feed %q: %w", path, err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+	f.Add(entries...)
+	f.Trim(limit)
+	if data, err = f.Marshal(); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write feed %q: %w", path, err)
+	}
+	return f, nil
+}
+
+// addFeedFlags adds the flags for the title and length of a feed file, with
+// names starting with prefix
+func addFeedFlags(cmd *cobra.Command, prefix string) {
+	cmd.Flags().String(prefix+"title", "GitHub GraphQL schema", "Title of a new feed")
+	cmd.Flags().Int(prefix+"limit", 50, "Number of newest entries kept in the feed")
+}
+
+func init() {
+	feedCmd.Flags().StringP("output", "o", "", "Feed file to merge the entries into (default: print a new feed)")
+	feedCmd.Flags().String("serve", "", "Serve the feed over HTTP at this address, such as :8080")
+	addFeedFlags(feedCmd, "")
+
+	rootCmd.AddCommand(feedCmd)
+}
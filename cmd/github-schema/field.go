@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var fieldCmd = &cobra.Command{
+	Use:   "field <Type.field>",
+	Short: "Show one field of a type: arguments, return type, and deprecation",
+	Long: `Show a single field instead of every field of its type, with the field's
+description, return type, arguments with their defaults, and deprecation status.
+Fields of input objects are shown too.
+
+Examples:
+  github-schema field Repository.issues
+  github-schema field CreateIssueInput.assigneeIds`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		typeName, fieldName, ok := strings.Cut(args[0], ".")
+		if strings.Contains(fieldName, ".") {
+			return fmt.Errorf("expected Type.field, got %q (use path for longer selections)", args[0])
+		}
+		if !ok || typeName == "" || fieldName == "" {
+			return fmt.Errorf("expected Type.field, got %q", args[0])
+		}
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		typeName = resolveTypeName(s, typeName)
+		fieldName = resolveFieldName(s, typeName, fieldName)
+
+		field, err := s.Field(typeName, fieldName)
+		if err != nil {
+			return fmt.Errorf("failed to query field: %w", err)
+		}
+
+		return outputResult(map[string]interface{}{
+			"type":  typeName,
+			"field": field,
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(fieldCmd)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/apstndb/go-yamlformat"
+	"github.com/spf13/cobra"
+)
+
+var flattenCmd = &cobra.Command{
+	Use:   "flatten --operation <file.graphql> [response.json]",
+	Short: "Flatten a GraphQL response into rows for CSV or warehouse loads",
+	Long: `Flatten a GraphQL response into flat records using the operation that
+produced it. Connection nodes are unrolled into rows and nested objects become
+dotted column names. The response is read from stdin when no file is given.
+
+Examples:
+  gh api graphql -f query="$(cat issues.graphql)" | github-schema flatten -o issues.graphql --csv
+  github-schema flatten -o issues.graphql response.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		operationFile, _ := cmd.Flags().GetString("operation")
+		asCSV, _ := cmd.Flags().GetBool("csv")
+
+		operation, err := os.ReadFile(operationFile)
+		if err != nil {
+			return fmt.Errorf("failed to read operation: %w", err)
+		}
+
+		var response []byte
+		if len(args) == 1 {
+			response, err = os.ReadFile(args[0])
+		} else {
+			response, err = io.ReadAll(os.Stdin)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		table, err := s.Flatten(response, string(operation))
+		if err != nil {
+			return fmt.Errorf("failed to flatten response: %w", err)
+		}
+
+		if !asCSV {
+			return outputResult(table.Rows)
+		}
+		return writeCSV(stdout, table.Columns, table.Rows)
+	},
+}
+
+// writeCSV writes rows with a header line; nested values are encoded as JSON
+func writeCSV(w io.Writer, columns []string, rows []map[string]interface{}) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, column := range columns {
+			switch v := row[column].(type) {
+			case nil:
+				record[i] = ""
+			case string:
+				record[i] = v
+			case []interface{}, map[string]interface{}:
+				data, err := yamlformat.MarshalJSON(v)
+				if err != nil {
+					return err
+				}
+				record[i] = string(data)
+			default:
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func init() {
+	flattenCmd.Flags().StringP("operation", "o", "", "GraphQL document containing the operation that produced the response")
+	flattenCmd.Flags().Bool("csv", false, "Write CSV with a header row instead of YAML/JSON records")
+	flattenCmd.MarkFlagRequired("operation")
+
+	rootCmd.AddCommand(flattenCmd)
+}
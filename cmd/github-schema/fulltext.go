@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/spf13/cobra"
+)
+
+var fulltextCmd = &cobra.Command{
+	Use:   "fulltext <text>... | fulltext --serve ADDR",
+	Short: "Search descriptions of types, fields, arguments, and enum values",
+	Long: `Search the documentation text of the schema. The arguments are joined
+into one search text; every word must occur in a description, case-insensitively,
+and a quoted phrase is matched as one term. Matches are ranked, and each has a
+snippet of the description, the byte ranges of the matched terms within it, and
+the JSON path of the description in the introspection document.
+
+With --serve, the search is served over HTTP at ADDR instead, for web UIs and
+other tools: GET /search/fulltext?q=TEXT returns the same JSON, with the
+optional parameters limit and highlight in place of the flags.
+
+Examples:
+  github-schema fulltext merge pull request
+  github-schema fulltext '"rate limit"' --limit 5
+  github-schema fulltext draft --highlight '**'
+  github-schema fulltext --serve :8080`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if addr, _ := cmd.Flags().GetString("serve"); addr != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		mark, _ := cmd.Flags().GetString("highlight")
+		addr, _ := cmd.Flags().GetString("serve")
+		text := strings.Join(args, " ")
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		if addr != "" {
+			mux := http.NewServeMux()
+			mux.Handle("/search/fulltext", schema.SearchTextHandler(s))
+			slog.Info("Serving full-text search", "addr", addr, "path", "/search/fulltext")
+			return http.ListenAndServe(addr, mux)
+		}
+
+		matches, err := s.SearchText(text, limit)
+		if err != nil {
+			return fmt.Errorf("failed to search descriptions: %w", err)
+		}
+
+		highlightMatches(matches, mark)
+
+		return outputResult(map[string]interface{}{
+			"count":   len(matches),
+			"text":    text,
+			"matches": matches,
+		})
+	},
+}
+
+// highlightMatches replaces the highlight ranges of matches with mark around
+// the matched text in each snippet, for output read by people
+func highlightMatches(matches []schema.TextMatch, mark string) {
+	if mark == "" {
+		return
+	}
+	for i, m := range matches {
+		matches[i].Snippet = m.Highlight(mark, mark)
+		matches[i].Highlights = nil
+	}
+}
+
+func init() {
+	fulltextCmd.Flags().Int("limit", schema.DefaultSearchLimit, "Maximum number of matches (0 for all)")
+	fulltextCmd.Flags().String("highlight", "", "Wrap matched terms in the snippet with this marker instead of listing byte ranges")
+	fulltextCmd.Flags().String("serve", "", "Serve the search over HTTP at this address, such as :8080, under /search/fulltext")
+
+	rootCmd.AddCommand(fulltextCmd)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var grepCmd = &cobra.Command{
+	Use:   "grep <pattern>",
+	Short: "Search descriptions for a regular expression",
+	Long: `List the types, fields, arguments, input fields, enum values, and directives
+whose description matches a regular expression, case-insensitively, in schema
+order. Use search to match type names, and fulltext for ranked word search.
+
+Examples:
+  github-schema grep "merge queue"
+  github-schema grep "secondary rate limit" --highlight '**'
+  github-schema grep "deprecated.*use" --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mark, _ := cmd.Flags().GetString("highlight")
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		matches, err := s.SearchDescriptions(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to search descriptions: %w", err)
+		}
+
+		highlightMatches(matches, mark)
+
+		return outputResult(map[string]interface{}{
+			"count":   len(matches),
+			"pattern": args[0],
+			"matches": matches,
+		})
+	},
+}
+
+func init() {
+	grepCmd.Flags().String("highlight", "", "Wrap matches in the snippet with this marker instead of listing byte ranges")
+
+	rootCmd.AddCommand(grepCmd)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var implementsCmd = &cobra.Command{
+	Use:   "implements <InterfaceName>",
+	Short: "List object types implementing an interface",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := getLazySchema()
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+
+		name := resolveTypeName(s, args[0])
+		implementers, err := s.Implementers(name)
+		if err != nil {
+			return fmt.Errorf("failed to query implementers: %w", err)
+		}
+
+		return outputResult(map[string]interface{}{
+			"interface":    name,
+			"implementers": implementers,
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(implementsCmd)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/spf13/cobra"
+)
+
+var inputCmd = &cobra.Command{
+	Use:   "input <InputObjectName>",
+	Short: "Show an input object with nested input objects expanded",
+	Long: `Show an input object with nested input objects expanded.
+
+With --matrix, print one row per field with its type, requiredness, default
+value, deprecation, and oneOf group instead, as a plain text table or a
+Markdown table ready to paste into a review.
+
+Examples:
+  github-schema input CreateIssueInput --depth 2
+  github-schema input CreateIssueInput --matrix markdown`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		depth, _ := cmd.Flags().GetInt("depth")
+		matrix, _ := cmd.Flags().GetString("matrix")
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		info, err := s.InputObject(resolveTypeName(s, args[0]), depth)
+		if err != nil {
+			return fmt.Errorf("failed to query input object: %w", err)
+		}
+
+		if matrix != "" {
+			return schema.WriteInputMatrix(stdout, info, schema.MatrixFormat(matrix))
+		}
+		return outputResult(map[string]interface{}{"input": info})
+	},
+}
+
+func init() {
+	inputCmd.Flags().Int("depth", 1, "Levels of nested input objects to expand (-1 for all)")
+	inputCmd.Flags().String("matrix", "", "Print a field matrix instead (table or markdown)")
+
+	rootCmd.AddCommand(inputCmd)
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/apstndb/go-yamlformat"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var invokeCmd = &cobra.Command{
+	Use:   "invoke <mutation> [--<input-field> <value>...]",
+	Short: "Call a mutation with flags generated from its input (experimental)",
+	Long: `Call a mutation with a flag for each of its scalar and enum inputs. Flags
+are the kebab-case names of the input fields, such as --subject-id for
+subjectId, with the fields of nested input objects prefixed by the field
+name, such as --metadata-priority. List inputs take one value per flag and
+the flag is repeated. A mutation input named like a flag of github-schema
+itself is given with an "input-" prefix instead. Run with the mutation name
+and --help to list its flags.
+
+Values are checked against the schema before anything is sent: missing
+required flags, values that are not of their type, and unknown enum values
+are reported and the command exits with a non-zero status.
+
+With --dry-run the mutation and its variables are printed. Otherwise the
+mutation is sent to --endpoint with the GitHub token of --token, or the one
+found for the endpoint's host: $GH_TOKEN or $GITHUB_TOKEN for github.com,
+$GH_ENTERPRISE_TOKEN for other hosts, then gh config or 'gh auth token', and
+the response is printed. The command is experimental and its flags may change.
+
+Examples:
+  github-schema invoke addComment --help
+  github-schema invoke addComment --subject-id I_kwDOA --body "Looks good" --dry-run
+  github-schema invoke addLabelsToLabelable --labelable-id I_kwDOA --label-ids LA_1 --label-ids LA_2`,
+	// The flags depend on the mutation, so they are parsed in RunE
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Find the mutation and the global flags first, leaving its own
+		// flags for later
+		static := pflag.NewFlagSet(cmd.Name(), pflag.ContinueOnError)
+		static.ParseErrorsWhitelist.UnknownFlags = true
+		static.AddFlagSet(cmd.Flags())
+		static.AddFlagSet(cmd.InheritedFlags())
+		static.Usage = func() {}
+		if err := static.Parse(args); err != nil {
+			return err
+		}
+		help, _ := static.GetBool("help")
+		if static.NArg() == 0 {
+			if help {
+				return cmd.Help()
+			}
+			return fmt.Errorf("requires a mutation name")
+		}
+		mutation := static.Arg(0)
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+		flags, err := s.MutationFlags(mutation)
+		if err != nil {
+			return err
+		}
+		dynamic := pflag.NewFlagSet(mutation, pflag.ContinueOnError)
+		names := make(map[string]string, len(flags)) // Command line names to MutationFlag names
+		for _, f := range flags {
+			name := f.Name
+			if static.Lookup(name) != nil {
+				name = "input-" + name
+			}
+			names[name] = f.Name
+			usage := invokeUsage(f)
+			switch {
+			case f.List:
+				dynamic.StringArray(name, nil, usage)
+			case f.Type == "Boolean" || f.Type == "Boolean!":
+				dynamic.Bool(name, false, usage)
+			default:
+				dynamic.String(name, "", usage)
+			}
+		}
+		if help {
+			cmd.Use = "invoke " + mutation + " [flags]"
+			cmd.Flags().AddFlagSet(dynamic)
+			return cmd.Help()
+		}
+
+		all := pflag.NewFlagSet(mutation, pflag.ContinueOnError)
+		all.AddFlagSet(static)
+		all.AddFlagSet(dynamic)
+		all.Usage = func() {}
+		if err := all.Parse(args); err != nil {
+			return err
+		}
+		if all.NArg() > 1 {
+			return fmt.Errorf("unexpected arguments after the mutation name: %s", strings.Join(all.Args()[1:], " "))
+		}
+		values := make(map[string][]string)
+		all.Visit(func(f *pflag.Flag) {
+			if dynamic.Lookup(f.Name) == nil {
+				return
+			}
+			if list, ok := f.Value.(pflag.SliceValue); ok {
+				values[names[f.Name]] = list.GetSlice()
+			} else {
+				values[names[f.Name]] = []string{f.Value.String()}
+			}
+		})
+
+		call, err := s.NewMutationCall(mutation, values)
+		if err != nil {
+			return err
+		}
+		for i, e := range call.Errors {
+			// Report flags by the name they were given with
+			for name, original := range names {
+				if name != original && e.Path == "--"+original {
+					call.Errors[i].Path = "--" + name
+				}
+			}
+		}
+		if len(call.Errors) > 0 {
+			if err := outputResult(map[string]interface{}{"errors": call.Errors}); err != nil {
+				return err
+			}
+			return fmt.Errorf("%d invalid flags for mutation %q", len(call.Errors), mutation)
+		}
+
+		dryRun, _ := all.GetBool("dry-run")
+		if dryRun {
+			return outputResult(map[string]interface{}{
+				"query":     call.Query,
+				"variables": call.Variables,
+			})
+		}
+		endpoint, _ := all.GetString("endpoint")
+		token, _ := all.GetString("token")
+		cred := schema.ResolveCredentialForHost(token, schema.EndpointHost(endpoint))
+		if cred.Token == "" {
+			return fmt.Errorf("no token found for %s (tried %s); give one with --token or use --dry-run", schema.EndpointHost(endpoint), strings.Join(cred.Tried, ", "))
+		}
+		return sendMutation(cmd, endpoint, cred.Token, call)
+	},
+}
+
+// invokeUsage describes a flag of invoke by its type, description, and enum
+// values
+func invokeUsage(f schema.MutationFlag) string {
+	usage := f.Type
+	if f.Required {
+		usage += ", required"
+	}
+	if f.List {
+		usage += ", repeatable"
+	}
+	if f.Description != "" {
+		usage += ": " + f.Description
+	}
+	if len(f.EnumValues) > 0 {
+		usage += " (" + strings.Join(f.EnumValues, ", ") + ")"
+	}
+	return usage
+}
+
+// sendMutation posts call to endpoint and prints the response. Mutations are
+// not retried, since a failed attempt may still have taken effect.
+func sendMutation(cmd *cobra.Command, endpoint, token string, call *schema.MutationCall) error {
+	body, err := yamlformat.MarshalJSON(map[string]interface{}{
+		"query":     call.Query,
+		"variables": call.Variables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(cmd.Context(), "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result map[string]interface{}
+	if err := yamlformat.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("unexpected response with status %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	if err := outputResult(result); err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("mutation failed with status %s", resp.Status)
+	}
+	if errs, ok := result["errors"].([]interface{}); ok && len(errs) > 0 {
+		return fmt.Errorf("mutation returned %d errors", len(errs))
+	}
+	return nil
+}
+
+func init() {
+	invokeCmd.Flags().Bool("dry-run", false, "Print the mutation and its variables instead of sending it")
+	invokeCmd.Flags().String("endpoint", schema.GitHubAPIURL, "GraphQL endpoint, such as https://HOST/api/graphql for GitHub Enterprise Server")
+	invokeCmd.Flags().String("token", "", "GitHub token (default: $GH_TOKEN or $GITHUB_TOKEN for github.com, $GH_ENTERPRISE_TOKEN for other hosts, then gh config or 'gh auth token' for the endpoint's host)")
+
+	rootCmd.AddCommand(invokeCmd)
+}
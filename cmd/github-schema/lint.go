@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/lint"
+	"github.com/apstndb/github-schema-go/report"
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [<dir-or-file>...]",
+	Short: "Check conventions and deprecated usage of operation documents",
+	Long: `Check the conventions of GraphQL operation documents:
+
+  named-operation  every operation has a name
+  file-name        the operation of a single-operation file is named after the
+                   file (get_issues.graphql or GetIssues.graphql holds GetIssues)
+  variable-case    variables are camelCase
+  header           the file starts with a comment block containing every
+                   --require-header key as a "# key: value" line
+  deprecated-usage no deprecated field, argument, input field, or enum value
+                   of the schema is used; findings include the deprecation
+                   reason and the replacement it suggests
+
+Operations are read from .graphql and .gql files given as arguments or with
+--operations; directories are searched recursively, and a Go-style "/..."
+suffix is accepted. With --go, string literals in .go files that hold query,
+mutation, subscription, or fragment definitions are linted as well, without
+the header and file-name rules; .go files given directly are always scanned.
+The command exits with a non-zero status when there are findings.
+
+With --baseline, findings in an earlier --report output are not reported, so
+an existing collection can adopt the rules and fail only on new violations.
+Findings match by rule, file, and message, not by line.
+
+Examples:
+  github-schema lint ./queries/...
+  github-schema lint --go ./internal/...
+  github-schema lint --operations ./queries/ --require-header owner --require-header ticket
+  github-schema lint --operations ./queries/ --disable file-name
+  github-schema lint --operations ./queries/ --report > lint-baseline.json
+  github-schema lint --operations ./queries/ --baseline lint-baseline.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		paths, _ := cmd.Flags().GetStringSlice("operations")
+		headers, _ := cmd.Flags().GetStringSlice("require-header")
+		disabled, _ := cmd.Flags().GetStringSlice("disable")
+		scanGo, _ := cmd.Flags().GetBool("go")
+		paths = append(paths, args...)
+		if len(paths) == 0 {
+			return fmt.Errorf("no files or directories to lint")
+		}
+		handler, err := progressHandler()
+		if err != nil {
+			return err
+		}
+
+		config := lint.Config{RequiredHeaders: headers}
+		for _, r := range disabled {
+			config.Disabled = append(config.Disabled, lint.Rule(r))
+		}
+		if !slices.Contains(config.Disabled, lint.DeprecatedUsage) {
+			s, err := getSchema()
+			if err != nil {
+				return err
+			}
+			config.Schema = s
+		}
+		l, err := lint.New(config)
+		if err != nil {
+			return err
+		}
+		baseline, err := loadBaseline(cmd)
+		if err != nil {
+			return err
+		}
+
+		exts := []string{".graphql", ".gql"}
+		if scanGo {
+			exts = append(exts, ".go")
+		}
+		files, err := collectFiles(paths, exts...)
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no %s files found", strings.Join(exts, ", "))
+		}
+
+		findings := []lint.Finding{}
+		progress := schema.NewProgress("lint", "files", len(files), handler)
+		for _, file := range files {
+			found, err := lintFile(l, file)
+			if err != nil {
+				return progress.Finish(err)
+			}
+			for _, f := range found {
+				if baseline == nil || !baseline.Known(report.LintFinding(f)) {
+					findings = append(findings, f)
+				}
+			}
+			progress.Step(1, file)
+		}
+		progress.Finish(nil)
+		if baseline != nil {
+			logBaseline(baseline)
+		}
+
+		if reportRequested(cmd) {
+			err = outputReport(report.FromLint(findings))
+		} else {
+			err = outputResult(map[string]interface{}{
+				"files":    len(files),
+				"count":    len(findings),
+				"findings": findings,
+			})
+		}
+		if err != nil {
+			return err
+		}
+		if len(findings) > 0 {
+			return fmt.Errorf("%d lint finding(s) in %d file(s)", len(findings), len(files))
+		}
+		return nil
+	},
+}
+
+// lintFile lints the operations of a GraphQL file, or of the string literals
+// of a Go file
+func lintFile(l *lint.Linter, file string) ([]lint.Finding, error) {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operations: %w", err)
+	}
+	run := l.Lint
+	if filepath.Ext(file) == ".go" {
+		run = l.LintGo
+	}
+	found, err := run(file, string(src))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return found, nil
+}
+
+func init() {
+	lintCmd.Flags().StringSlice("operations", nil, "GraphQL files or directories to lint")
+	lintCmd.Flags().StringSlice("require-header", nil, "Key that must appear as a \"# key: value\" line in the leading comment block of every file")
+	lintCmd.Flags().StringSlice("disable", nil, "Rules to skip (named-operation, file-name, variable-case, header, deprecated-usage)")
+	lintCmd.Flags().Bool("go", false, "Also lint operations in string literals of .go files")
+	addReportFlag(lintCmd)
+	addBaselineFlag(lintCmd)
+
+	rootCmd.AddCommand(lintCmd)
+}
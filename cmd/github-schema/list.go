@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list <kind>",
+	Short: "List type, mutation, or query field names",
+	Long: `List names from the schema in schema order.
+
+Kinds: ` + strings.Join(schema.ListKinds, ", ") + `
+
+Types can be narrowed to a domain of the curated taxonomy with --category.
+Categories: ` + strings.Join(schema.CategoryNames(), ", ") + `
+
+Examples:
+  github-schema list mutations
+  github-schema list enums
+  github-schema list types --kind INTERFACE --kind UNION
+  github-schema list types --category security`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: schema.ListKinds,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kinds, _ := cmd.Flags().GetStringSlice("kind")
+		category, _ := cmd.Flags().GetString("category")
+		if len(kinds) > 0 && args[0] != "types" {
+			return fmt.Errorf("--kind can only be used with list types")
+		}
+		if category != "" && args[0] != "types" {
+			return fmt.Errorf("--category can only be used with list types")
+		}
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		var names []string
+		switch {
+		case category != "":
+			names, err = s.ListTypesInCategory(category)
+			if err == nil && len(kinds) > 0 {
+				names = filterKinds(s, names, kinds)
+			}
+		case len(kinds) > 0:
+			names, err = s.ListTypesOfKind(kinds...)
+		default:
+			names, err = s.List(args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", args[0], err)
+		}
+
+		return outputResult(names)
+	},
+}
+
+// filterKinds keeps the names of types with any of the given GraphQL kinds
+func filterKinds(s *schema.Schema, names, kinds []string) []string {
+	filtered := []string{}
+	for _, name := range names {
+		t := s.Model().Type(name)
+		for _, kind := range kinds {
+			if t != nil && strings.EqualFold(t.Kind, kind) {
+				filtered = append(filtered, name)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+func init() {
+	listCmd.Flags().StringSlice("kind", nil, "Only list types of these GraphQL kinds (OBJECT, INTERFACE, UNION, ENUM, INPUT_OBJECT, SCALAR)")
+	listCmd.Flags().String("category", "", "Only list types in this category of the taxonomy")
+
+	rootCmd.AddCommand(listCmd)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// Results of every command go to stdout and everything else (logs, progress
+// events, errors) to stderr, so stdout can be piped into a parser as is.
+var (
+	logFormat string
+	silent    bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Format of diagnostic logs on stderr (text, json)")
+	rootCmd.PersistentFlags().BoolVarP(&silent, "silent", "q", false, "Only log errors; results are still written to stdout")
+
+	// Errors are logged by main with the configured handler instead of
+	// printed by cobra, and usage is only shown when asked for
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+}
+
+// newLogger returns a logger writing to w as selected by --log-format,
+// --debug, and --silent
+func newLogger(w io.Writer) (*slog.Logger, error) {
+	if debug && silent {
+		return nil, fmt.Errorf("--debug and --silent cannot be used together")
+	}
+	opts := &slog.HandlerOptions{Level: slog.LevelInfo}
+	switch {
+	case debug:
+		opts.Level = slog.LevelDebug
+	case silent:
+		opts.Level = slog.LevelError
+	}
+
+	switch logFormat {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (supported: text, json)", logFormat)
+	}
+}
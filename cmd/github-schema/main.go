@@ -1,20 +1,32 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
-	"strings"
+	"time"
 
 	"github.com/apstndb/go-yamlformat"
 	"github.com/apstndb/github-schema-go/schema"
+	"github.com/apstndb/github-schema-go/schema/introspect"
 	"github.com/spf13/cobra"
 )
 
 var (
-	schemaFile string
-	outputJSON bool
-	debug      bool
+	schemaFile     string
+	outputJSON     bool
+	debug          bool
+	progressFormat string
+	useMmap        bool
+	useCache       bool
+	cacheDir       string
+	strict         bool
+
+	// stdout receives command output; the daemon swaps it per request
+	stdout io.Writer = os.Stdout
 )
 
 var rootCmd = &cobra.Command{
@@ -22,22 +34,48 @@ var rootCmd = &cobra.Command{
 	Short: "Query GitHub GraphQL schema offline",
 	Long: `Query GitHub GraphQL schema using embedded data or custom schema files.
 The embedded schema is obtained via GitHub GraphQL API introspection.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return startProfiling()
+	},
 }
 
 var typeCmd = &cobra.Command{
 	Use:   "type <TypeName>",
 	Short: "Show fields and descriptions for a type",
-	Args:  cobra.ExactArgs(1),
+	Long: `Show the fields, input fields, or enum values of a type with their
+descriptions.
+
+With --hints, the example values and constraints the descriptions state, such
+as "e.g. GHSA or CVE", "Limit: 10", or ISO 8601 dates, are listed under
+"hints" by schema coordinate, with the 1 to 100 bounds of connection page
+sizes.
+
+Examples:
+  github-schema type PullRequest
+  github-schema type CreateRefInput --hints`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		s, err := getSchema()
+		withHints, _ := cmd.Flags().GetBool("hints")
+		s, err := getLazySchema()
 		if err != nil {
 			return err
 		}
+		defer s.Close()
 
-		result, err := s.Type(args[0])
+		name := resolveTypeName(s, args[0])
+		result, err := s.Type(name)
 		if err != nil {
 			return fmt.Errorf("failed to query type: %w", err)
 		}
+		if withHints {
+			full, err := getSchema()
+			if err != nil {
+				return err
+			}
+			if result["hints"], err = full.TypeHints(name); err != nil {
+				return err
+			}
+		}
 
 		return outputResult(result)
 	},
@@ -48,12 +86,13 @@ var mutationCmd = &cobra.Command{
 	Short: "Show mutation input requirements",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		s, err := getSchema()
+		s, err := getLazySchema()
 		if err != nil {
 			return err
 		}
+		defer s.Close()
 
-		result, err := s.Mutation(args[0])
+		result, err := s.Mutation(resolveRootField(s, "mutation", args[0]))
 		if err != nil {
 			return fmt.Errorf("failed to query mutation: %w", err)
 		}
@@ -65,8 +104,51 @@ var mutationCmd = &cobra.Command{
 var searchCmd = &cobra.Command{
 	Use:   "search <pattern>",
 	Short: "Search schema for matching types/fields",
-	Args:  cobra.ExactArgs(1),
+	Long: `Search the schema for types whose names match a regular expression.
+
+With --all-targets, search every registered target (see 'github-schema target')
+instead and report which targets define each matching symbol, so a type
+present on github.com but missing from a GitHub Enterprise Server version
+stands out. A pattern containing a dot matches Type.field coordinates. Use
+--missing to list only the symbols some target lacks, and --matrix for a plain
+text or Markdown table.
+
+Examples:
+  github-schema search PullRequest
+  github-schema search --all-targets '^Discussion'
+  github-schema search --all-targets 'PullRequest\.merge' --missing --matrix table`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		allTargets, _ := cmd.Flags().GetBool("all-targets")
+		missing, _ := cmd.Flags().GetBool("missing")
+		matrixFormat, _ := cmd.Flags().GetString("matrix")
+
+		if allTargets {
+			schemas, err := loadTargets()
+			if err != nil {
+				return err
+			}
+			matrix, err := schema.SearchTargets(args[0], schemas)
+			if err != nil {
+				return fmt.Errorf("failed to search targets: %w", err)
+			}
+			if missing {
+				matrix.Symbols = matrix.Partial()
+			}
+			if matrixFormat != "" {
+				return schema.WriteSymbolMatrix(stdout, matrix, schema.MatrixFormat(matrixFormat))
+			}
+			return outputResult(map[string]interface{}{
+				"pattern": matrix.Pattern,
+				"targets": matrix.Targets,
+				"symbols": matrix.Symbols,
+				"count":   len(matrix.Symbols),
+			})
+		}
+		if missing || matrixFormat != "" {
+			return fmt.Errorf("--missing and --matrix require --all-targets")
+		}
+
 		s, err := getSchema()
 		if err != nil {
 			return err
@@ -85,51 +167,110 @@ var downloadCmd = &cobra.Command{
 	Use:   "download",
 	Short: "Download latest schema via GraphQL introspection",
 	Long: `Download the latest GitHub GraphQL schema using introspection query.
-Requires 'gh auth login' to be configured.
+Requires a GitHub token, taken from --token, $GH_TOKEN, $GITHUB_TOKEN, the gh
+config file, or 'gh auth token', in that order.
+
+With --source sdl, the GraphQL SDL GitHub publishes with its documentation is
+downloaded instead, without a token, and converted into an introspection
+result locally; --endpoint then names the SDL file's URL.
 
 Examples:
   github-schema download                           # Download to stdout
   github-schema download -o schema.json            # Download to file
   github-schema download -o schema.json.gz         # Auto-compress (detected by .gz extension)
-  github-schema download --compress                # Download compressed to stdout
-  github-schema download -c -o schema.json.gz      # Explicitly compress to file`,
+  github-schema download -o schema.json.zst        # Compress with zstd (detected by .zst extension)
+  github-schema download --compress                # Download gzip-compressed to stdout
+  github-schema download --compress=zstd -o s.bin  # Explicitly compress to file with zstd
+  github-schema --progress json download -o x.gz   # Emit JSON progress events to stderr
+  github-schema download --endpoint https://ghe.example.com/api/graphql -o ghes.json.gz
+  github-schema download --timeout 30s -o schema.json.gz   # Give up after 30 seconds
+  github-schema download --retries 0 -o schema.json.gz     # Fail on the first error
+  github-schema download --if-changed -o schema.json.gz    # Keep the file if the schema is the same
+  github-schema download --source sdl -o schema.json.gz    # No token needed
+
+With --if-changed, the file is only rewritten when the schema differs from the
+one it holds, by the SHA-256 and ETag recorded in it, so that repeated downloads
+do not touch the file for a new download time alone. The command then exits
+with status 3, or --unchanged-exit-code, to tell callers nothing changed.
+
+Network errors, server errors, and rate limits are retried with exponential
+backoff, waiting as long as a rate limit response asks up to a minute.
+
+The download time, the SHA-256 of the response, the endpoint, and the GitHub
+Enterprise Server version, if any, are recorded under "extensions" of the
+result; see 'github-schema version'.`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		compressFlag, _ := cmd.Flags().GetBool("compress")
+		compressFlag, _ := cmd.Flags().GetString("compress")
 		outputFile, _ := cmd.Flags().GetString("output")
+		token, _ := cmd.Flags().GetString("token")
+		endpoint, _ := cmd.Flags().GetString("endpoint")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		retries, _ := cmd.Flags().GetInt("retries")
+		ifChanged, _ := cmd.Flags().GetBool("if-changed")
+		unchangedCode, _ := cmd.Flags().GetInt("unchanged-exit-code")
+		source, _ := cmd.Flags().GetString("source")
+		if source != string(schema.SourceIntrospection) && source != string(schema.SourceSDL) {
+			return fmt.Errorf("invalid --source %q; use introspection or sdl", source)
+		}
+		if source == string(schema.SourceSDL) && !cmd.Flags().Changed("endpoint") {
+			endpoint = schema.PublishedSDLURL
+		}
 		
 		// If no output file specified, write to stdout
 		toStdout := outputFile == ""
+		if ifChanged && toStdout {
+			return fmt.Errorf("--if-changed requires --output")
+		}
 		
 		// Determine if we should compress
-		// Priority: --compress flag > .gz extension > default (no compression)
-		compress := compressFlag
-		if !toStdout && !compress && strings.HasSuffix(outputFile, ".gz") {
-			compress = true
+		// Priority: --compress flag > .gz or .zst extension > default (no compression)
+		compress, err := schema.ParseCompression(compressFlag)
+		if err != nil {
+			return err
+		}
+		if !toStdout && !cmd.Flags().Changed("compress") {
+			compress = schema.CompressionForPath(outputFile)
+		}
+		
+		handler, err := progressHandler()
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		opts := []schema.DownloadOption{
+			schema.WithToken(token),
+			schema.WithEndpoint(endpoint),
+			schema.WithSource(schema.DownloadSource(source)),
+			schema.WithCompressionFormat(compress),
+			schema.WithEventHandler(handler),
+			schema.WithRetry(retryPolicy(retries)),
 		}
 		
 		if toStdout {
 			// Write to stdout
-			if compress {
-				return schema.DownloadAndCompressToWriter(os.Stdout)
-			} else {
-				return schema.DownloadToWriter(os.Stdout)
-			}
+			return schema.Download(ctx, append(opts, schema.WithOutput(stdout))...)
 		}
 		
 		// Write to file
 		slog.Info("Downloading schema via introspection", 
-			"endpoint", schema.GitHubAPIURL,
+			"endpoint", endpoint,
 			"output", outputFile,
 			"compress", compress)
 		
-		var err error
-		if compress {
-			err = schema.DownloadAndCompressSchema(outputFile)
-		} else {
-			err = schema.DownloadSchema(outputFile)
+		err = schema.Download(ctx, append(opts, schema.WithOutputPath(outputFile), schema.WithIfChanged(ifChanged))...)
+		if errors.Is(err, schema.ErrUnchanged) {
+			slog.Info("Schema unchanged, file left as it is", "file", outputFile)
+			if unchangedCode == 0 {
+				return nil
+			}
+			return &exitCodeError{code: unchangedCode}
 		}
-		
 		if err != nil {
 			return err
 		}
@@ -145,7 +286,7 @@ Examples:
 			"size_kb", fmt.Sprintf("%.2f", float64(info.Size())/1024),
 		}
 		
-		if compress && !compressFlag {
+		if compress != schema.NoCompression && !cmd.Flags().Changed("compress") {
 			logAttrs = append(logAttrs, "auto_compressed", true)
 		}
 		
@@ -160,12 +301,19 @@ var queryCmd = &cobra.Command{
 	Short: "Run custom jq query on schema",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		timeout, _ := cmd.Flags().GetDuration("timeout")
 		s, err := getSchema()
 		if err != nil {
 			return err
 		}
 
-		result, err := s.Query(args[0], nil)
+		ctx := cmd.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		result, err := s.QueryContext(ctx, args[0], nil)
 		if err != nil {
 			return fmt.Errorf("failed to run query: %w", err)
 		}
@@ -175,42 +323,137 @@ var queryCmd = &cobra.Command{
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&schemaFile, "schema", "s", "", "Path to custom schema file")
+	rootCmd.PersistentFlags().StringVarP(&schemaFile, "schema", "s", "", "Path to custom schema file (introspection JSON or SDL)")
 	rootCmd.PersistentFlags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON instead of YAML")
 	rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "Enable debug logging")
+	rootCmd.PersistentFlags().BoolVar(&useCache, "cache", false, "Cache the parsed schema on disk to speed up repeated invocations")
+	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", "", "Directory for the parsed schema cache (implies --cache)")
+	rootCmd.PersistentFlags().BoolVar(&useMmap, "mmap", false, "Memory-map the uncompressed --schema file instead of reading it onto the heap")
+	rootCmd.PersistentFlags().StringVar(&progressFormat, "progress", "", "Report progress of long operations on stderr, such as the bytes of a download received or the files a lint has checked (json, text)")
+	rootCmd.PersistentFlags().BoolVar(&strict, "strict", false, "Match type, field, and mutation names exactly instead of case-insensitively")
+
+	typeCmd.Flags().Bool("hints", false, "Also list the example values and constraints stated in descriptions")
 
-	downloadCmd.Flags().BoolP("compress", "c", false, "Compress downloaded schema with gzip")
+	searchCmd.Flags().Bool("all-targets", false, "Search every registered target and report where each symbol is present")
+	searchCmd.Flags().Bool("missing", false, "With --all-targets, list only symbols missing from some target")
+	searchCmd.Flags().String("matrix", "", "With --all-targets, print a presence matrix instead (table or markdown)")
+
+	downloadCmd.Flags().StringP("compress", "c", "", "Compress downloaded schema with gzip, or zstd with --compress=zstd, which loads faster")
+	downloadCmd.Flags().Lookup("compress").NoOptDefVal = string(schema.Gzip)
 	downloadCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	downloadCmd.Flags().String("token", "", "GitHub token (default: $GH_TOKEN or $GITHUB_TOKEN for github.com, $GH_ENTERPRISE_TOKEN for other hosts, then gh config or 'gh auth token' for the endpoint's host)")
+	downloadCmd.Flags().Duration("timeout", 5*time.Minute, "Give up on the download after this long (0: no limit)")
+	downloadCmd.Flags().Int("retries", introspect.DefaultRetryPolicy.MaxAttempts-1, "Retries after network errors, server errors, and rate limits, with exponential backoff")
+	downloadCmd.Flags().Bool("if-changed", false, "Leave the output file untouched when it already holds the downloaded schema")
+	downloadCmd.Flags().Int("unchanged-exit-code", 3, "Exit status when --if-changed finds the schema unchanged")
+	downloadCmd.Flags().String("endpoint", schema.GitHubAPIURL, "GraphQL endpoint, such as https://HOST/api/graphql for GitHub Enterprise Server; with --source sdl, the SDL file's URL, "+schema.PublishedSDLURL+" unless given")
+	downloadCmd.Flags().String("source", string(schema.SourceIntrospection), "Where to get the schema: introspection (needs a token), or sdl, the SDL published with GitHub's docs, converted locally")
+
+	queryCmd.Flags().Duration("timeout", schema.DefaultQueryTimeout, "Give up on the jq expression after this long (0: no limit)")
 
 	rootCmd.AddCommand(typeCmd, mutationCmd, searchCmd, downloadCmd, queryCmd)
 }
 
+// exitCodeError makes the command exit with code without reporting a failure
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// retryPolicy returns the default retry policy with the given number of
+// retries
+func retryPolicy(retries int) schema.RetryPolicy {
+	policy := introspect.DefaultRetryPolicy
+	policy.MaxAttempts = retries + 1
+	return policy
+}
+
 func main() {
-	// Parse flags early to get debug setting
+	// Parse flags early to get the logging settings
 	rootCmd.ParseFlags(os.Args[1:])
 	
-	// Configure slog to write to stderr with text handler
-	logLevel := slog.LevelInfo
-	if debug {
-		logLevel = slog.LevelDebug
+	// Logs go to stderr so stdout carries only results
+	logger, err := newLogger(os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
 	}
-	
-	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-		Level: logLevel,
-	}))
 	slog.SetDefault(logger)
 	
-	if err := rootCmd.Execute(); err != nil {
-		slog.Error("Command failed", "error", err)
+	if forwarded, err := forwardToDaemon(os.Args[1:]); forwarded {
+		if err != nil {
+			reportError(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	
+	err = rootCmd.Execute()
+	stopProfiling()
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.code)
+	}
+	if err != nil {
+		reportError(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
 func getSchema() (*schema.Schema, error) {
+	if warmSchema != nil {
+		return warmSchema, nil
+	}
+	if useCache || cacheDir != "" {
+		dir := cacheDir
+		if dir == "" {
+			var err error
+			if dir, err = schema.DefaultCacheDir(); err != nil {
+				return nil, err
+			}
+		}
+		if schemaFile != "" {
+			// Custom files are user supplied, so verify their structure up front
+			return schema.NewCachedWithFileStrict(schemaFile, dir)
+		}
+		return schema.NewCached(dir)
+	}
+	if schemaFile != "" {
+		// Custom files are user supplied, so verify their structure up front
+		return schema.NewWithFileStrict(schemaFile)
+	}
+	return schema.Default()
+}
+
+// progressHandler returns the event handler selected by --progress, or nil when disabled
+func progressHandler() (schema.EventHandler, error) {
+	switch progressFormat {
+	case "":
+		return nil, nil
+	case "json":
+		return schema.JSONEventHandler(os.Stderr), nil
+	case "text":
+		return schema.TextEventHandler(os.Stderr), nil
+	default:
+		return nil, fmt.Errorf("unknown progress format %q (supported: json, text)", progressFormat)
+	}
+}
+
+// getLazySchema returns a schema that parses only the types a command touches
+func getLazySchema() (*schema.LazySchema, error) {
+	if warmLazy != nil {
+		return warmLazy, nil
+	}
+	if schemaFile != "" && useMmap {
+		return schema.NewLazyWithMmap(schemaFile)
+	}
 	if schemaFile != "" {
-		return schema.NewWithFile(schemaFile)
+		return schema.NewLazyWithFile(schemaFile)
 	}
-	return schema.New()
+	return schema.DefaultLazy()
 }
 
 func outputResult(result interface{}) error {
@@ -219,6 +462,6 @@ func outputResult(result interface{}) error {
 		format = yamlformat.FormatJSON
 	}
 	
-	encoder := yamlformat.NewEncoderForFormat(os.Stdout, format)
+	encoder := yamlformat.NewEncoderForFormat(stdout, format)
 	return encoder.Encode(result)
 }
\ No newline at end of file
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+// nameResolver is implemented by both *schema.Schema and *schema.LazySchema
+type nameResolver interface {
+	CanonicalTypeName(name string) (string, error)
+	CanonicalFieldName(typeName, fieldName string) (string, error)
+	RootTypeName(operation string) string
+}
+
+// resolveTypeName returns the schema's spelling of a type name given on the
+// command line, unless --strict is set. Unresolvable names are passed through
+// so the command reports its usual error.
+func resolveTypeName(r nameResolver, name string) string {
+	if strict {
+		return name
+	}
+	canonical, err := r.CanonicalTypeName(name)
+	if err != nil {
+		slog.Debug("Type name not normalized", "name", name, "error", err)
+		return name
+	}
+	logNormalized(name, canonical)
+	return canonical
+}
+
+// resolveRootField returns the schema's spelling of a field of the query or
+// mutation root type, unless --strict is set
+func resolveRootField(r nameResolver, operation, name string) string {
+	root := r.RootTypeName(operation)
+	if root == "" {
+		return name
+	}
+	return resolveFieldName(r, root, name)
+}
+
+// resolveFieldName returns the schema's spelling of a field of a type, unless
+// --strict is set. typeName must already be canonical.
+func resolveFieldName(r nameResolver, typeName, name string) string {
+	if strict {
+		return name
+	}
+	canonical, err := r.CanonicalFieldName(typeName, name)
+	if err != nil {
+		slog.Debug("Field name not normalized", "type", typeName, "name", name, "error", err)
+		return name
+	}
+	logNormalized(name, canonical)
+	return canonical
+}
+
+// resolvePath normalizes each segment of a Type.field.field path, stopping
+// at the first segment that does not resolve
+func resolvePath(s *schema.Schema, path string) string {
+	if strict {
+		return path
+	}
+	segments := strings.Split(path, ".")
+	typeName, err := s.CanonicalTypeName(segments[0])
+	if err != nil {
+		return path
+	}
+	segments[0] = typeName
+	for i := 1; i < len(segments); i++ {
+		field, err := s.CanonicalFieldName(typeName, segments[i])
+		if err != nil {
+			break
+		}
+		segments[i] = field
+		ref, err := s.FieldType(typeName, field)
+		if err != nil {
+			break
+		}
+		typeName = ref.NamedType()
+	}
+	canonical := strings.Join(segments, ".")
+	logNormalized(path, canonical)
+	return canonical
+}
+
+func logNormalized(name, canonical string) {
+	if name != canonical {
+		slog.Debug("Normalized name", "name", name, "canonical", canonical)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/spf13/cobra"
+)
+
+var opdiffCmd = &cobra.Command{
+	Use:   "opdiff <old.graphql> <new.graphql>",
+	Short: "Show the semantic differences between two operation documents",
+	Long: `Compare two versions of an operation document and list what changed:
+operations, variables, selected fields, arguments, directives, inline
+fragments, fragment spreads, and fragment definitions. Formatting and
+reordering are ignored. Each change names the schema element involved, such as
+Repository.issues or Repository.issues(first:), with its type, so a review can
+tell at a glance what a query change reads from the API.
+
+Fields are matched by response key, so changing the field behind an alias is
+reported as well. When each file contains a single operation, the two are
+compared even if renamed.
+
+Examples:
+  github-schema opdiff old/issues.graphql issues.graphql
+  git show main:queries/issues.graphql > /tmp/old.graphql && github-schema opdiff /tmp/old.graphql queries/issues.graphql`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var docs [2]*graphql.Document
+		for i, file := range args {
+			src, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read operations: %w", err)
+			}
+			if docs[i], err = graphql.Parse(string(src)); err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+		}
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+		changes := s.DiffOperations(docs[0], docs[1])
+		return outputResult(map[string]interface{}{
+			"count":   len(changes),
+			"changes": changes,
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(opdiffCmd)
+}
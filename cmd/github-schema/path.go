@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var pathCmd = &cobra.Command{
+	Use:   "path <Type.field.field...>",
+	Short: "Show the type at each step of a selection path",
+	Long: `Show the type at each step of a dotted selection path, answering "what type do
+I get at this selection" without chaining type lookups.
+
+Example:
+  github-schema path Repository.issues.nodes.labels`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		path := resolvePath(s, args[0])
+		steps, err := s.ResolvePath(path)
+		if err != nil {
+			return fmt.Errorf("failed to resolve path: %w", err)
+		}
+
+		return outputResult(map[string]interface{}{
+			"path":  path,
+			"steps": steps,
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pathCmd)
+}
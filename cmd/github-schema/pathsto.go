@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+var pathsToCmd = &cobra.Command{
+	Use:   "paths-to <field>",
+	Short: "Find the shortest query paths to a field",
+	Long: `Find every type exposing a field and the shortest path of fields from Query
+to each, ranked by path length. A path steps into a member of an interface or
+union with a segment naming the type, and walks connections through nodes.
+The node, nodes, and resource fields, which fetch any object by ID or URL, are
+not followed. The paths are accepted by the skeleton command; with --skeleton,
+the query for the shortest path is printed instead.
+
+Examples:
+  github-schema paths-to statusCheckRollup
+  github-schema paths-to mergeQueue --limit 0
+  github-schema paths-to statusCheckRollup --skeleton`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		skeleton, _ := cmd.Flags().GetBool("skeleton")
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		paths, err := s.FieldPaths(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to find paths: %w", err)
+		}
+
+		if skeleton {
+			doc, err := s.Skeleton(paths[0].Path)
+			if err != nil {
+				return fmt.Errorf("failed to build skeleton: %w", err)
+			}
+			_, err = io.WriteString(stdout, doc)
+			return err
+		}
+
+		count := len(paths)
+		if limit > 0 && len(paths) > limit {
+			paths = paths[:limit]
+		}
+		return outputResult(map[string]interface{}{
+			"field": args[0],
+			"count": count,
+			"paths": paths,
+		})
+	},
+}
+
+func init() {
+	pathsToCmd.Flags().Int("limit", 10, "Maximum number of paths (0 for all)")
+	pathsToCmd.Flags().Bool("skeleton", false, "Print the query for the shortest path")
+
+	rootCmd.AddCommand(pathsToCmd)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+var (
+	cpuProfile string
+	memProfile string
+	traceFile  string
+
+	cpuProfileOut *os.File
+	traceOut      *os.File
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&cpuProfile, "cpuprofile", "", "Write a CPU profile (pprof) to this file")
+	rootCmd.PersistentFlags().StringVar(&memProfile, "memprofile", "", "Write a heap profile (pprof) to this file on exit")
+	rootCmd.PersistentFlags().StringVar(&traceFile, "trace", "", "Write an execution trace to this file")
+	for _, name := range []string{"cpuprofile", "memprofile", "trace"} {
+		rootCmd.PersistentFlags().MarkHidden(name)
+	}
+}
+
+// startProfiling starts the CPU profile and execution trace requested by flags
+func startProfiling() error {
+	if cpuProfile != "" {
+		f, err := os.Create(cpuProfile)
+		if err != nil {
+			return fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		cpuProfileOut = f
+	}
+
+	if traceFile != "" {
+		f, err := os.Create(traceFile)
+		if err != nil {
+			return fmt.Errorf("failed to create trace file: %w", err)
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to start trace: %w", err)
+		}
+		traceOut = f
+	}
+
+	return nil
+}
+
+// stopProfiling flushes all profiles. It runs after the command finishes,
+// including when the command failed, so slow failures can be profiled too.
+func stopProfiling() {
+	if cpuProfileOut != nil {
+		pprof.StopCPUProfile()
+		cpuProfileOut.Close()
+		slog.Debug("Wrote CPU profile", "file", cpuProfile)
+	}
+
+	if traceOut != nil {
+		trace.Stop()
+		traceOut.Close()
+		slog.Debug("Wrote execution trace", "file", traceFile)
+	}
+
+	if memProfile != "" {
+		f, err := os.Create(memProfile)
+		if err != nil {
+			slog.Error("Failed to create heap profile", "error", err)
+			return
+		}
+		defer f.Close()
+		// Collect garbage first so the profile reflects live memory
+		runtime.GC()
+		if err := pprof.Lookup("heap").WriteTo(f, 0); err != nil {
+			slog.Error("Failed to write heap profile", "error", err)
+			return
+		}
+		slog.Debug("Wrote heap profile", "file", memProfile)
+	}
+}
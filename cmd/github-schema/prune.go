@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/spf13/cobra"
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune [directory...]",
+	Short: "Remove old schema snapshots and cache entries by a retention policy",
+	Long: `Remove the schema files of snapshot archive directories, and with --cache the
+entries of the parsed schema cache, that the retention policy does not keep:
+the --keep-last newest files, and the newest file of each of the --keep-monthly
+latest months. Files are dated by modification time, cache entries by their
+last use. Files that do not look like schemas are left alone.
+
+The cache is also pruned automatically whenever an entry is written, keeping
+the 8 entries used last.
+
+Examples:
+  github-schema prune snapshots/ --keep-last 10 --keep-monthly 12
+  github-schema prune --cache --keep-last 2
+  github-schema prune snapshots/ --keep-last 5 --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keepLast, _ := cmd.Flags().GetInt("keep-last")
+		keepMonthly, _ := cmd.Flags().GetInt("keep-monthly")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		pruneCache, _ := cmd.Flags().GetBool("cache")
+		if len(args) == 0 && !pruneCache {
+			return fmt.Errorf("nothing to prune; give directories or --cache")
+		}
+		policy := schema.RetentionPolicy{KeepLast: keepLast, KeepMonthly: keepMonthly}
+
+		var removed []schema.SnapshotFile
+		for _, dir := range args {
+			files, err := schema.Prune(dir, policy, dryRun)
+			removed = append(removed, files...)
+			if err != nil {
+				return fmt.Errorf("failed to prune %s: %w", dir, err)
+			}
+		}
+		if pruneCache {
+			dir := cacheDir
+			if dir == "" {
+				var err error
+				if dir, err = schema.DefaultCacheDir(); err != nil {
+					return err
+				}
+			}
+			files, err := schema.PruneCache(dir, policy, dryRun)
+			removed = append(removed, files...)
+			if err != nil {
+				return fmt.Errorf("failed to prune cache: %w", err)
+			}
+		}
+
+		var freed int64
+		for _, f := range removed {
+			freed += f.Size
+		}
+		if removed == nil {
+			removed = []schema.SnapshotFile{}
+		}
+		return outputResult(map[string]interface{}{
+			"removed":    removed,
+			"count":      len(removed),
+			"freedBytes": freed,
+			"dryRun":     dryRun,
+		})
+	},
+}
+
+func init() {
+	pruneCmd.Flags().Int("keep-last", 0, "Keep this many of the newest files")
+	pruneCmd.Flags().Int("keep-monthly", 0, "Keep the newest file of each of this many latest months")
+	pruneCmd.Flags().Bool("cache", false, "Prune the parsed schema cache (--cache-dir or the default cache directory)")
+	pruneCmd.Flags().BoolP("dry-run", "n", false, "Report what would be removed without removing it")
+
+	rootCmd.AddCommand(pruneCmd)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var queryFieldCmd = &cobra.Command{
+	Use:   "query-field <fieldName>",
+	Short: "Show arguments and return type of a Query root field",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := getLazySchema()
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+
+		result, err := s.QueryField(resolveRootField(s, "query", args[0]))
+		if err != nil {
+			return fmt.Errorf("failed to query field: %w", err)
+		}
+
+		return outputResult(result)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(queryFieldCmd)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/apstndb/github-schema-go/rename"
+	"github.com/apstndb/github-schema-go/schema/introspection"
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename --map <file> [<dir-or-file>...]",
+	Short: "Simulate renaming types and fields in a schema and its operations",
+	Long: `Apply a rename map to the schema and to a set of operations at the same
+time, reporting which files would change and anything that cannot be
+rewritten. The map is a YAML or JSON object whose keys are type names, or
+Type.field for fields and input fields, and whose values are the new names:
+
+  Issue: Ticket
+  Issue.title: headline
+  CreateIssueInput.title: headline
+
+Operations are read from .graphql and .gql files given as arguments or with
+--operations; directories are searched recursively. Type conditions,
+variable types, selected fields, and input fields of object literals are
+rewritten in place, keeping comments and formatting.
+
+Nothing is written by default. With --write, the operation files are
+rewritten, and with --output the renamed schema is written as an
+introspection result; both are refused while there are issues unless
+--force is given. The command exits with a non-zero status when there are
+issues.
+
+Examples:
+  github-schema --schema custom.json rename --map renames.yaml ./queries/
+  github-schema --schema custom.json rename --map renames.yaml ./queries/ --write -o renamed.json
+  github-schema --schema custom.json rename --map renames.yaml ./queries/ --keep-response-keys --write`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mapFile, _ := cmd.Flags().GetString("map")
+		paths, _ := cmd.Flags().GetStringSlice("operations")
+		output, _ := cmd.Flags().GetString("output")
+		write, _ := cmd.Flags().GetBool("write")
+		force, _ := cmd.Flags().GetBool("force")
+		keepKeys, _ := cmd.Flags().GetBool("keep-response-keys")
+		paths = append(paths, args...)
+
+		data, err := os.ReadFile(mapFile)
+		if err != nil {
+			return fmt.Errorf("failed to read rename map: %w", err)
+		}
+		m, err := rename.ParseMap(data)
+		if err != nil {
+			return err
+		}
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+		r, err := rename.New(s, m, rename.Options{KeepResponseKeys: keepKeys})
+		if err != nil {
+			return err
+		}
+		issues := append([]rename.Issue{}, r.Issues()...)
+
+		files, err := operationFiles(paths)
+		if err != nil {
+			return err
+		}
+		type fileChange struct {
+			Path    string `json:"path"`
+			Renames int    `json:"renames"`
+			out     string
+		}
+		changed := []fileChange{}
+		for _, file := range files {
+			src, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read operations: %w", err)
+			}
+			out, n, fileIssues, err := r.Operations(string(src))
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			for _, issue := range fileIssues {
+				issue.File = file
+				issues = append(issues, issue)
+			}
+			if n > 0 {
+				changed = append(changed, fileChange{Path: file, Renames: n, out: out})
+			}
+		}
+
+		commit := (write || output != "") && (len(issues) == 0 || force)
+		if commit && output != "" {
+			doc, err := r.Schema()
+			if err != nil {
+				return err
+			}
+			data, err := introspection.Marshal(doc)
+			if err != nil {
+				return fmt.Errorf("failed to encode schema: %w", err)
+			}
+			if err := os.WriteFile(output, append(data, '\n'), 0644); err != nil {
+				return fmt.Errorf("failed to write schema: %w", err)
+			}
+		}
+		if commit && write {
+			for _, c := range changed {
+				if err := os.WriteFile(c.Path, []byte(c.out), 0644); err != nil {
+					return fmt.Errorf("failed to write operations: %w", err)
+				}
+			}
+		}
+
+		if err := outputResult(map[string]interface{}{
+			"renames": r.Renames(),
+			"files":   changed,
+			"issues":  issues,
+			"written": commit,
+		}); err != nil {
+			return err
+		}
+		if len(issues) > 0 {
+			if (write || output != "") && !force {
+				return fmt.Errorf("%d rename issues; nothing was written (use --force to write anyway)", len(issues))
+			}
+			return fmt.Errorf("%d rename issues", len(issues))
+		}
+		return nil
+	},
+}
+
+func init() {
+	renameCmd.Flags().String("map", "", "YAML or JSON file mapping type names and Type.field to new names")
+	renameCmd.Flags().StringSlice("operations", nil, "Operation files or directories to rewrite (repeatable)")
+	renameCmd.Flags().StringP("output", "o", "", "Write the renamed schema to this file")
+	renameCmd.Flags().Bool("write", false, "Rewrite the operation files in place")
+	renameCmd.Flags().Bool("force", false, "Write even when there are issues")
+	renameCmd.Flags().Bool("keep-response-keys", false, "Alias renamed fields to their old names so responses keep their shape")
+	renameCmd.MarkFlagRequired("map")
+
+	rootCmd.AddCommand(renameCmd)
+}
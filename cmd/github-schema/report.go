@@ -0,0 +1,55 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/apstndb/github-schema-go/report"
+	"github.com/apstndb/go-yamlformat"
+	"github.com/spf13/cobra"
+)
+
+// addReportFlag adds --report to a command whose findings can be emitted in
+// the shared analysis report format
+func addReportFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("report", false, "Output the analysis report JSON shared by diff, lint, and deprecated")
+}
+
+// reportRequested reports whether --report was given
+func reportRequested(cmd *cobra.Command) bool {
+	asReport, _ := cmd.Flags().GetBool("report")
+	return asReport
+}
+
+// outputReport writes r as JSON regardless of --json, since the report
+// format is defined as JSON
+func outputReport(r *report.Report) error {
+	return yamlformat.NewEncoderForFormat(stdout, yamlformat.FormatJSON).Encode(r)
+}
+
+// addBaselineFlag adds --baseline, which leaves out the findings of an
+// earlier report so a command fails only on new ones
+func addBaselineFlag(cmd *cobra.Command) {
+	cmd.Flags().String("baseline", "", "Report from an earlier --report run whose findings are known and not reported again")
+	cmd.MarkFlagFilename("baseline", "json")
+}
+
+// loadBaseline reads the --baseline report, or returns nil when not given
+func loadBaseline(cmd *cobra.Command) (*report.Baseline, error) {
+	path, _ := cmd.Flags().GetString("baseline")
+	if path == "" {
+		return nil, nil
+	}
+	r, err := report.NewWithFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return report.NewBaseline(r), nil
+}
+
+// logBaseline notes baseline findings that no longer occur, so the baseline
+// can be regenerated to ratchet down
+func logBaseline(b *report.Baseline) {
+	if n := b.Remaining(); n > 0 {
+		slog.Info("Baseline findings no longer occur; regenerate the baseline to keep them from coming back", "count", n)
+	}
+}
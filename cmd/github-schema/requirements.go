@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/spf13/cobra"
+)
+
+var requirementsCmd = &cobra.Command{
+	Use:   "requirements <file-or-dir>...",
+	Short: "Record or check the schema parts operations depend on",
+	Long: `Record the types, fields, and mutations a set of operations depends on, or
+check that the schema still provides them.
+
+Without --check, the operations are read from .graphql and .gql files and their
+requirements printed: every selected field with its type and the arguments
+passed to it, the types named by fragments and variables, every field of the
+input objects passed, and the mutations called. Save them with --json and keep
+them with the operations.
+
+With --check, the saved requirements are checked against the schema instead:
+types and mutations must exist, fields must keep types the operations can
+read, passed arguments must keep their types, and no new required argument or
+input field may be missing. Every unmet requirement is listed and the command
+exits with a non-zero status. Run it with --schema against a new snapshot
+before bumping it.
+
+Examples:
+  github-schema --json requirements ./queries/ > requirements.json
+  github-schema requirements --check requirements.json
+  github-schema --schema new.json requirements --check requirements.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		checkFile, _ := cmd.Flags().GetString("check")
+
+		if checkFile != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("--check takes no operation files")
+			}
+			data, err := os.ReadFile(checkFile)
+			if err != nil {
+				return fmt.Errorf("failed to read requirements: %w", err)
+			}
+			req, err := schema.ParseRequirements(data)
+			if err != nil {
+				return err
+			}
+			s, err := getSchema()
+			if err != nil {
+				return err
+			}
+
+			incompatibilities := []schema.Incompatibility{}
+			err = s.AssertCompatible(req)
+			var ce *schema.CompatibilityError
+			switch {
+			case errors.As(err, &ce):
+				incompatibilities = ce.Incompatibilities
+			case err != nil:
+				return err
+			}
+			if err := outputResult(map[string]interface{}{
+				"compatible":        len(incompatibilities) == 0,
+				"count":             len(incompatibilities),
+				"incompatibilities": incompatibilities,
+			}); err != nil {
+				return err
+			}
+			if len(incompatibilities) > 0 {
+				return fmt.Errorf("schema is incompatible with %d requirement(s)", len(incompatibilities))
+			}
+			return nil
+		}
+
+		if len(args) == 0 {
+			return fmt.Errorf("requires operation files or --check")
+		}
+		files, err := operationFiles(args)
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no .graphql or .gql files found")
+		}
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		var docs []*graphql.Document
+		for _, file := range files {
+			src, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read operations: %w", err)
+			}
+			doc, err := graphql.Parse(string(src))
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			// Collect per file so errors point at the right one
+			if _, err := s.RequirementsOf(doc); err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			docs = append(docs, doc)
+		}
+		req, err := s.RequirementsOf(docs...)
+		if err != nil {
+			return err
+		}
+		return outputResult(req)
+	},
+}
+
+func init() {
+	requirementsCmd.Flags().String("check", "", "Requirements file to check against the schema instead of recording")
+
+	rootCmd.AddCommand(requirementsCmd)
+}
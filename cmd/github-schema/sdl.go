@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var sdlCmd = &cobra.Command{
+	Use:   "sdl [-o schema.graphql]",
+	Short: "Convert the schema to GraphQL SDL",
+	Long: `Convert the introspection result into GraphQL schema definition language
+text with descriptions and deprecations, for tools such as gqlparser, genqlient,
+and graphql-codegen that read SDL. Built-in scalars and directives are left out.
+
+Examples:
+  github-schema sdl -o schema.graphql
+  github-schema --schema ghes.json sdl > ghes.graphql`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFile, _ := cmd.Flags().GetString("output")
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+		sdl := s.ToSDL()
+
+		if outputFile == "" {
+			_, err = io.WriteString(stdout, sdl)
+			return err
+		}
+		if err := os.WriteFile(outputFile, []byte(sdl), 0o644); err != nil {
+			return fmt.Errorf("failed to write SDL: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	sdlCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+
+	rootCmd.AddCommand(sdlCmd)
+}
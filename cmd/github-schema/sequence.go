@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+var sequenceCmd = &cobra.Command{
+	Use:   "sequence <mutation>...",
+	Short: "Plan a chain of mutations whose payload IDs feed later inputs",
+	Long: `Plan a chain of mutations run in the given order. The ID inputs of each
+mutation are matched with the IDs the payloads of earlier mutations provide,
+by name: projectId takes the id of a projectV2 payload field, and contentId
+the id of an Issue, which belongs to the ProjectV2ItemContent union. The plan
+lists the required inputs of each step and where their values come from, and
+warns about required IDs no earlier payload provides.
+
+With --script, a shell script running the chain with gh and jq is printed
+instead. Inputs the caller gives are read from environment variables such as
+OWNER_ID and TITLE; IDs from payloads are passed on in shell variables.
+
+Examples:
+  github-schema sequence createProjectV2 createIssue addProjectV2ItemById
+  github-schema sequence createProjectV2 addProjectV2DraftIssue --script > setup.sh`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		script, _ := cmd.Flags().GetBool("script")
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		mutations := make([]string, len(args))
+		for i, name := range args {
+			mutations[i] = resolveRootField(s, "mutation", name)
+		}
+		seq, err := s.SequenceMutations(mutations)
+		if err != nil {
+			return fmt.Errorf("failed to sequence mutations: %w", err)
+		}
+
+		if script {
+			_, err = io.WriteString(stdout, seq.Script())
+			return err
+		}
+		return outputResult(seq)
+	},
+}
+
+func init() {
+	sequenceCmd.Flags().Bool("script", false, "Print a gh and jq shell script running the chain")
+
+	rootCmd.AddCommand(sequenceCmd)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var skeletonCmd = &cobra.Command{
+	Use:   "skeleton <field.field...>",
+	Short: "Expand a field path into a complete query",
+	Long: `Expand a dotted field path into a complete operation selecting it. The path
+starts with a field of the Query type, or with Mutation followed by a mutation.
+
+Required arguments become variables, connections along the way get first and
+after variables and a pageInfo selection, and nodes is inserted where the path
+steps from a connection to a field of its node type. A segment naming a member
+of an interface or union selects an inline fragment on it, as in the paths
+printed by paths-to. The operation is printed as GraphQL text.
+
+Examples:
+  github-schema skeleton repository.pullRequests.reviews
+  github-schema skeleton repository.pullRequests.nodes.reviews.nodes.author
+  github-schema skeleton repository.object.Commit.statusCheckRollup
+  github-schema skeleton Mutation.addStar.starrable`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		// Normalize through the root type, which the path may leave out
+		path := args[0]
+		first, _, _ := strings.Cut(path, ".")
+		root := s.RootTypeName("query")
+		for _, op := range []string{"query", "mutation", "subscription"} {
+			if name := s.RootTypeName(op); name != "" && strings.EqualFold(name, first) {
+				root = ""
+			}
+		}
+		if root != "" {
+			path = strings.TrimPrefix(resolvePath(s, root+"."+path), root+".")
+		} else {
+			path = resolvePath(s, path)
+		}
+
+		doc, err := s.Skeleton(path)
+		if err != nil {
+			return fmt.Errorf("failed to build skeleton: %w", err)
+		}
+		_, err = io.WriteString(stdout, doc)
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(skeletonCmd)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/apstndb/github-schema-go/schema/introspect"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [target...]",
+	Short: "Download the schemas of registered targets concurrently",
+	Long: `Refresh the schema files of registered targets from their endpoints, several
+at a time. Each file is only rewritten when its schema changed, like
+'download --if-changed'. Targets without an endpoint and SDL files are skipped.
+
+The status of each target is logged to stderr as it finishes, and a summary
+table is written to stdout, or the results as JSON with --json. The command
+fails when any target failed. The token is resolved like for 'download'.
+
+Examples:
+  github-schema sync --all
+  github-schema sync ghes-3.10 ghes-3.11 --retries 5
+  github-schema sync --all --concurrency 8 --timeout 10m --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		retries, _ := cmd.Flags().GetInt("retries")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		token, _ := cmd.Flags().GetString("token")
+		if all == (len(args) > 0) {
+			return fmt.Errorf("give target names or --all")
+		}
+		handler, err := progressHandler()
+		if err != nil {
+			return err
+		}
+
+		r, err := loadRegistry()
+		if err != nil {
+			return err
+		}
+		targets := r.Targets
+		if !all {
+			targets = nil
+			for _, name := range args {
+				t := r.Target(name)
+				if t == nil {
+					// Remove reports the names that were likely meant
+					return r.Remove(name)
+				}
+				targets = append(targets, *t)
+			}
+		}
+		if len(targets) == 0 {
+			return fmt.Errorf("no targets registered; add them with 'github-schema target add'")
+		}
+
+		ctx := cmd.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		results := schema.SyncTargets(ctx, targets, schema.SyncOptions{
+			Concurrency: concurrency,
+			Handler:     handler,
+			Options: func(schema.Target) []schema.DownloadOption {
+				return []schema.DownloadOption{schema.WithToken(token), schema.WithRetry(retryPolicy(retries))}
+			},
+			OnResult: func(r schema.SyncResult) {
+				attrs := []interface{}{"target", r.Target, "status", r.Status, "attempts", r.Attempts,
+					"elapsed", (time.Duration(r.ElapsedMS) * time.Millisecond).String()}
+				switch r.Status {
+				case schema.SyncFailed:
+					slog.Error("Target failed to sync", append(attrs, "error", r.Error)...)
+				case schema.SyncSkipped:
+					slog.Info("Target skipped", append(attrs, "reason", r.Error)...)
+				default:
+					slog.Info("Target synced", attrs...)
+				}
+			},
+		})
+
+		if outputJSON {
+			err = outputResult(map[string]interface{}{"results": results})
+		} else {
+			err = schema.WriteSyncTable(stdout, results)
+		}
+		if err != nil {
+			return err
+		}
+		failed := 0
+		for _, r := range results {
+			if r.Status == schema.SyncFailed {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d targets failed to sync", failed, len(results))
+		}
+		return nil
+	},
+}
+
+func init() {
+	syncCmd.Flags().Bool("all", false, "Sync every registered target")
+	syncCmd.Flags().Int("concurrency", 4, "Downloads to run at once")
+	syncCmd.Flags().Int("retries", introspect.DefaultRetryPolicy.MaxAttempts-1, "Retries of each download after network errors, server errors, and rate limits")
+	syncCmd.Flags().Duration("timeout", 10*time.Minute, "Give up on the whole sync after this long (0: no limit)")
+	syncCmd.Flags().String("token", "", "GitHub token for every target (default: $GH_TOKEN or $GITHUB_TOKEN for github.com, $GH_ENTERPRISE_TOKEN for other hosts, then gh config or 'gh auth token' for the endpoint's host)")
+
+	rootCmd.AddCommand(syncCmd)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/spf13/cobra"
+)
+
+var registryFile string
+
+var targetCmd = &cobra.Command{
+	Use:   "target",
+	Short: "Register the schemas of several GitHub instances",
+	Long: `Register schema files under names, such as one for github.com and one per
+GitHub Enterprise Server version, so that commands like 'search --all-targets'
+can compare them. Targets are kept in targets.yaml in the user configuration
+directory, or in the file given with --registry.
+
+Examples:
+  github-schema target add dotcom schema.json
+  github-schema target add ghes-3.10 ghes-3.10.json --endpoint https://ghes.example.com/api/graphql
+  github-schema target list
+  github-schema target remove ghes-3.10`,
+}
+
+var targetAddCmd = &cobra.Command{
+	Use:   "add <name> <schema-file>",
+	Short: "Register a schema file, replacing a target of the same name",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		endpoint, _ := cmd.Flags().GetString("endpoint")
+
+		// Store an absolute path so the target works from any directory
+		path, err := filepath.Abs(args[1])
+		if err != nil {
+			return err
+		}
+		// Refuse files that would fail later, when the target is used
+		if _, err := schema.NewWithFileStrict(path); err != nil {
+			return err
+		}
+
+		return updateRegistry(func(r *schema.Registry) error {
+			return r.Add(schema.Target{Name: args[0], Path: path, Endpoint: endpoint})
+		})
+	},
+}
+
+var targetRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Unregister a target",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateRegistry(func(r *schema.Registry) error {
+			return r.Remove(args[0])
+		})
+	},
+}
+
+var targetListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the registered targets",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		r, err := loadRegistry()
+		if err != nil {
+			return err
+		}
+		return outputResult(map[string]interface{}{
+			"targets": r.Targets,
+			"count":   len(r.Targets),
+		})
+	},
+}
+
+// registryPath returns the --registry file or the default one
+func registryPath() (string, error) {
+	if registryFile != "" {
+		return registryFile, nil
+	}
+	return schema.DefaultRegistryPath()
+}
+
+func loadRegistry() (*schema.Registry, error) {
+	path, err := registryPath()
+	if err != nil {
+		return nil, err
+	}
+	return schema.LoadRegistry(path)
+}
+
+// updateRegistry applies update to the registry and saves it
+func updateRegistry(update func(r *schema.Registry) error) error {
+	path, err := registryPath()
+	if err != nil {
+		return err
+	}
+	r, err := schema.LoadRegistry(path)
+	if err != nil {
+		return err
+	}
+	if err := update(r); err != nil {
+		return err
+	}
+	return r.Save(path)
+}
+
+// loadTargets loads the schema of every registered target
+func loadTargets() ([]schema.NamedSchema, error) {
+	r, err := loadRegistry()
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Targets) == 0 {
+		return nil, fmt.Errorf("no targets registered; add them with 'github-schema target add'")
+	}
+	schemas := make([]schema.NamedSchema, len(r.Targets))
+	for i, t := range r.Targets {
+		s, err := schema.NewWithFileStrict(t.Path)
+		if err != nil {
+			return nil, fmt.Errorf("target %q: %w", t.Name, err)
+		}
+		schemas[i] = schema.NamedSchema{Name: t.Name, Schema: s}
+	}
+	return schemas, nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&registryFile, "registry", "", "Target registry file (default: targets.yaml in the user config directory)")
+
+	targetAddCmd.Flags().String("endpoint", "", "GraphQL endpoint the schema is downloaded from")
+
+	targetCmd.AddCommand(targetAddCmd, targetRemoveCmd, targetListCmd)
+	rootCmd.AddCommand(targetCmd)
+}
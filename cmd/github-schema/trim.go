@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var trimCmd = &cobra.Command{
+	Use:   "trim --operation <file.graphql> [response.json]",
+	Short: "Trim a recorded GraphQL response to the selected fields for test fixtures",
+	Long: `Trim a recorded GraphQL response to the fields selected by the operation that
+produced it. The operation is validated against the schema, unselected members
+are dropped, and the result is written as indented JSON with sorted keys. The
+response is read from stdin when no file is given.
+
+Examples:
+  github-schema trim -o issues.graphql recorded.json > testdata/issues.json
+  github-schema trim -o issues.graphql --max-items 2 -w testdata/issues.json recorded.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		operationFile, _ := cmd.Flags().GetString("operation")
+		maxItems, _ := cmd.Flags().GetInt("max-items")
+		outputFile, _ := cmd.Flags().GetString("write")
+
+		operation, err := os.ReadFile(operationFile)
+		if err != nil {
+			return fmt.Errorf("failed to read operation: %w", err)
+		}
+
+		var response []byte
+		if len(args) == 1 {
+			response, err = os.ReadFile(args[0])
+		} else {
+			response, err = io.ReadAll(os.Stdin)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		fixture, err := s.Trim(response, string(operation), maxItems)
+		if err != nil {
+			return fmt.Errorf("failed to trim response: %w", err)
+		}
+
+		if outputFile == "" {
+			_, err = stdout.Write(fixture)
+			return err
+		}
+		if err := os.WriteFile(outputFile, fixture, 0644); err != nil {
+			return fmt.Errorf("failed to write fixture: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	trimCmd.Flags().StringP("operation", "o", "", "GraphQL document containing the operation that produced the response")
+	trimCmd.Flags().Int("max-items", 0, "Keep at most this many elements of every list (0 keeps all)")
+	trimCmd.Flags().StringP("write", "w", "", "Write the fixture to a file instead of stdout")
+	trimCmd.MarkFlagRequired("operation")
+
+	rootCmd.AddCommand(trimCmd)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var unionCmd = &cobra.Command{
+	Use:   "union <UnionName>",
+	Short: "List possible types of a union",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := getLazySchema()
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+
+		name := resolveTypeName(s, args[0])
+		members, err := s.UnionMembers(name)
+		if err != nil {
+			return fmt.Errorf("failed to query union members: %w", err)
+		}
+
+		return outputResult(map[string]interface{}{
+			"union":   name,
+			"members": members,
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(unionCmd)
+}
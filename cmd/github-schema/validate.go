@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/spf13/cobra"
+)
+
+// validationError is a problem in one of the validated files
+type validationError struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Path    string `json:"path,omitempty"` // Of the offending value in a variables file
+	Message string `json:"message"`
+}
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <file-or-dir>...",
+	Short: "Validate GraphQL documents against the schema",
+	Long: `Validate GraphQL operation documents against the schema: fields must exist
+on their parent types, arguments and input fields must be defined and their
+values fit their types, fragment type conditions must apply where fragments are
+spread, and variables must be defined, used, and of matching types. Syntax
+errors are reported as problems of their file.
+
+Documents are read from .graphql and .gql files; directories are searched
+recursively. Each problem has the line and column of the offending node, and
+the command exits with a non-zero status when there are problems.
+
+With --variables, a JSON variables payload is also checked against the
+variable definitions of the operation in a single document: values must fit
+their declared types, including nested input object fields and enum values,
+and required variables must be given. Its problems carry the path of the
+offending value, such as $input.labelIds[1], instead of a line.
+
+Examples:
+  github-schema validate query.graphql
+  github-schema validate query.graphql --variables variables.json
+  github-schema validate ./queries/
+  github-schema --schema ghes.json validate ./queries/`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		variablesFile, _ := cmd.Flags().GetString("variables")
+
+		files, err := operationFiles(args)
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no .graphql or .gql files found")
+		}
+		if variablesFile != "" && len(files) != 1 {
+			return fmt.Errorf("--variables requires a single document, got %d files", len(files))
+		}
+
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		problems := []validationError{}
+		for _, file := range files {
+			src, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read operations: %w", err)
+			}
+			errs, err := s.ValidateQuery(string(src))
+			var syntaxErr *graphql.SyntaxError
+			switch {
+			case errors.As(err, &syntaxErr):
+				problems = append(problems, validationError{File: file, Line: syntaxErr.Pos.Line, Column: syntaxErr.Pos.Column, Message: "syntax error: " + syntaxErr.Message})
+			case err != nil:
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			for _, e := range errs {
+				problems = append(problems, validationError{File: file, Line: e.Line, Column: e.Column, Message: e.Message})
+			}
+			if variablesFile != "" && err == nil {
+				variables, err := os.ReadFile(variablesFile)
+				if err != nil {
+					return fmt.Errorf("failed to read variables: %w", err)
+				}
+				errs, err := s.ValidateVariables(string(src), variables)
+				if err != nil {
+					return fmt.Errorf("%s: %w", variablesFile, err)
+				}
+				for _, e := range errs {
+					problems = append(problems, validationError{File: variablesFile, Path: e.Path, Message: e.Message})
+				}
+			}
+		}
+
+		if err := outputResult(map[string]interface{}{
+			"files":  len(files),
+			"count":  len(problems),
+			"errors": problems,
+		}); err != nil {
+			return err
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("%d validation error(s) in %d file(s)", len(problems), len(files))
+		}
+		return nil
+	},
+}
+
+func init() {
+	validateCmd.Flags().String("variables", "", "JSON variables file to check against the operation's variable definitions")
+
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	buildinfo "runtime/debug"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/spf13/cobra"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show the version of the tool and where its schema came from",
+	Long: `Show the version of the tool and the schema in use: the embedded schema or
+the --schema file, its fingerprint, and the metadata recorded when it was
+downloaded (download time, SHA-256 of the response, endpoint, and GitHub
+Enterprise Server version). Schemas downloaded by other tools have no metadata.
+For the embedded schema, the provenance recorded when it was embedded is shown
+as well.
+
+Examples:
+  github-schema version
+  github-schema --schema schema.json version --json | jq .schema.metadata.downloadedAt`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		version := "unknown"
+		if info, ok := buildinfo.ReadBuildInfo(); ok {
+			version = info.Main.Version
+		}
+		info := map[string]interface{}{
+			"source":      "embedded",
+			"fingerprint": s.Fingerprint(),
+			"metadata":    s.Metadata(),
+		}
+		if schemaFile != "" {
+			info["source"] = schemaFile
+		} else {
+			info["embedded"] = schema.EmbeddedInfo()
+		}
+		return outputResult(map[string]interface{}{
+			"version": version,
+			"schema":  info,
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
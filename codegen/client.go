@@ -0,0 +1,795 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+// Source is a GraphQL document of operations and fragments to generate a
+// client for
+type Source struct {
+	Name string // File name, used in error messages
+	Text string
+}
+
+// ClientOptions controls GenerateClient
+type ClientOptions struct {
+	// Package is the package name of the generated files; empty means "client"
+	Package string
+}
+
+// File is a generated Go source file. Dependencies are the schema elements it
+// was generated from, in the form of manifest dependencies.
+type File struct {
+	Name         string
+	Content      []byte
+	Dependencies []string
+}
+
+// GenerateClient generates a typed Go client for the named queries and
+// mutations in sources, after validating them against s. Fragments may be
+// defined in any source. It returns two files: client.go with the Client and
+// its Execute method, which authenticates with the token
+// schema.ResolveCredential finds, and operations.go with a method per
+// operation and the types of its variables and response.
+//
+// Response structs mirror the selections: nullable fields are pointers, and
+// the selections of fragments on interfaces and unions are merged into one
+// struct whose type-conditioned fields are nil for other types. Enums and
+// input objects become named types. An operation with a connection whose
+// "after" argument is a variable and whose pageInfo selects hasNextPage and
+// endCursor also gets an All method that follows the cursor through every
+// page and returns the nodes, or edges, of all of them.
+//
+// Object types named by type conditions under interfaces and unions also get
+// a struct of their own, named after the type, with the fields their
+// selections select on them, and RegisterTypes registers those structs with a
+// typename.Registry, so that Decode returns them by __typename.
+func GenerateClient(s *schema.Schema, sources []Source, opts ClientOptions) ([]File, error) {
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = "client"
+	}
+
+	var text strings.Builder
+	var starts []int // Line of the joined text each source starts at
+	doc := &graphql.Document{}
+	sourceOf := make(map[*graphql.OperationDefinition]string)
+	line := 1
+	for _, src := range sources {
+		parsed, err := graphql.Parse(src.Text)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src.Name, err)
+		}
+		for _, op := range parsed.Operations() {
+			sourceOf[op] = src.Name
+		}
+		doc.Definitions = append(doc.Definitions, parsed.Definitions...)
+		starts = append(starts, line)
+		text.WriteString(src.Text)
+		text.WriteByte('\n')
+		line += strings.Count(src.Text, "\n") + 1
+	}
+	if len(sourceOf) == 0 {
+		return nil, fmt.Errorf("no operations to generate a client for")
+	}
+
+	problems, err := s.ValidateQuery(text.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		messages := make([]string, len(problems))
+		for i, p := range problems {
+			j := sort.Search(len(starts), func(j int) bool { return starts[j] > p.Line }) - 1
+			messages[i] = fmt.Sprintf("%s:%d:%d: %s", sources[j].Name, p.Line-starts[j]+1, p.Column, p.Message)
+		}
+		return nil, fmt.Errorf("invalid operations:\n%s", strings.Join(messages, "\n"))
+	}
+
+	g := newClientGen(s.Model(), doc.Fragments())
+	for _, op := range doc.Operations() {
+		switch {
+		case op.Name == "":
+			return nil, fmt.Errorf("%s:%s: operations must be named to generate methods for them", sourceOf[op], op.Pos)
+		case op.Operation == graphql.Subscription:
+			return nil, fmt.Errorf("%s:%s: subscription %q is not supported", sourceOf[op], op.Pos, op.Name)
+		}
+		if err := g.operation(op); err != nil {
+			return nil, fmt.Errorf("%s:%s: %w", sourceOf[op], op.Pos, err)
+		}
+	}
+	g.concreteTypes()
+	g.namedTypes()
+
+	header := "// Code generated by github-schema codegen client. DO NOT EDIT.\n\npackage " + pkg + "\n\n"
+	operations, err := format.Source([]byte(header + g.imports() + g.out.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format operations.go: %w", err)
+	}
+	deps := make([]string, 0, len(g.deps))
+	for dep := range g.deps {
+		deps = append(deps, dep)
+	}
+	sort.Strings(deps)
+	return []File{
+		{Name: "client.go", Content: []byte(header + clientRuntime)},
+		{Name: "operations.go", Content: operations, Dependencies: deps},
+	}, nil
+}
+
+// clientGen accumulates the declarations of operations.go
+type clientGen struct {
+	model     *schema.Model
+	fragments map[string]*graphql.FragmentDefinition
+	out       bytes.Buffer
+
+	declared map[string]bool // Go type names in use
+	enums    map[string]bool
+	inputs   map[string]bool
+	deps     map[string]bool
+	packages map[string]bool // Imports besides context
+
+	// concrete maps the object types named by type conditions under
+	// interfaces and unions to the selection sets of every such selection
+	concrete     map[string][][]graphql.SelectionSet
+	concreteDone bool // Set once the structs of concrete are declared
+}
+
+func newClientGen(model *schema.Model, fragments map[string]*graphql.FragmentDefinition) *clientGen {
+	g := &clientGen{
+		model:     model,
+		fragments: fragments,
+		declared:  map[string]bool{"Client": true, "Error": true, "Errors": true, "RegisterTypes": true},
+		enums:     make(map[string]bool),
+		inputs:    make(map[string]bool),
+		deps:      make(map[string]bool),
+		packages:  map[string]bool{"github.com/apstndb/github-schema-go/typename": true},
+		concrete:  make(map[string][][]graphql.SelectionSet),
+	}
+	// Enums and input objects keep their GraphQL names, so response structs
+	// must not take them
+	for _, t := range model.Types {
+		if t.Kind == "ENUM" || t.Kind == "INPUT_OBJECT" {
+			g.declared[t.Name] = true
+		}
+	}
+	return g
+}
+
+// declare reserves a Go type name, numbering it when it is taken
+func (g *clientGen) declare(name string) string {
+	unique := name
+	for i := 2; g.declared[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	g.declared[unique] = true
+	return unique
+}
+
+func (g *clientGen) imports() string {
+	packages := []string{"context"}
+	for p := range g.packages {
+		packages = append(packages, p)
+	}
+	sort.Strings(packages)
+	var b strings.Builder
+	b.WriteString("import (\n")
+	for _, p := range packages {
+		if !strings.Contains(p, ".") {
+			fmt.Fprintf(&b, "\t%q\n", p)
+		}
+	}
+	// Other modules follow the standard library, as goimports groups them
+	b.WriteString("\n")
+	for _, p := range packages {
+		if strings.Contains(p, ".") {
+			fmt.Fprintf(&b, "\t%q\n", p)
+		}
+	}
+	b.WriteString(")\n\n")
+	return b.String()
+}
+
+// goStruct is a generated response struct
+type goStruct struct {
+	name   string
+	typ    *schema.Type
+	path   string // Response keys leading to the struct, empty for the response
+	fields []*goField
+}
+
+// goField is a field of a response struct
+type goField struct {
+	name  string
+	key   string
+	def   *schema.Field // nil for __typename
+	typ   string
+	elem  *goStruct // Struct of an object selection, nil for leaf fields
+	after string    // Variable passed as the "after" argument, if any
+}
+
+// selection is a response key with the selections merged into it
+type selection struct {
+	key         string
+	def         *schema.Field
+	sets        []graphql.SelectionSet
+	after       string
+	conditional bool
+}
+
+// operation writes the document, variables, response, and methods of op
+func (g *clientGen) operation(op *graphql.OperationDefinition) error {
+	name := goName(op.Name)
+	if name == "Execute" {
+		return fmt.Errorf("operation %q conflicts with Client.Execute", op.Name)
+	}
+	root := g.model.Type(g.model.QueryType)
+	if op.Operation == graphql.Mutation {
+		root = g.model.Type(g.model.MutationType)
+	}
+
+	// The document sent is the operation with the fragments it reaches
+	sent := &graphql.Document{Definitions: []graphql.Definition{op}}
+	for _, f := range g.reachableFragments(op.SelectionSet) {
+		sent.Definitions = append(sent.Definitions, g.fragments[f])
+	}
+	document := strings.TrimSuffix(graphql.Print(sent), "\n")
+	literal := "`" + document + "`"
+	if strings.Contains(document, "`") {
+		literal = strconv.Quote(document)
+	}
+	documentName := g.declare(name + "Document")
+	g.comment("%s is the %s sent by Client.%s", documentName, op.Operation, name)
+	fmt.Fprintf(&g.out, "const %s = %s\n\n", documentName, literal)
+
+	vars, varsName := make(map[string]string), ""
+	if len(op.VariableDefinitions) > 0 {
+		varsName = g.declare(name + "Variables")
+		g.comment("%s are the variables of the %s %s", varsName, op.Name, op.Operation)
+		fmt.Fprintf(&g.out, "type %s struct {\n", varsName)
+		for _, d := range op.VariableDefinitions {
+			ref := g.typeRefOf(d.Type)
+			typ := g.goType(ref, d.DefaultValue != nil, nil)
+			vars[d.Name] = typ
+			tag := d.Name
+			if !ref.IsNonNull() || d.DefaultValue != nil {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(&g.out, "\t%s %s `json:%q`\n", goName(d.Name), typ, tag)
+		}
+		g.out.WriteString("}\n\n")
+	}
+
+	response := &goStruct{name: g.declare(name + "Response"), typ: root}
+	var structs []*goStruct
+	g.fill(response, name, []graphql.SelectionSet{op.SelectionSet}, &structs)
+	for _, st := range structs {
+		if st.path == "" {
+			g.comment("%s is the data of a response to the %s %s", st.name, op.Name, op.Operation)
+		} else {
+			g.comment("%s is the %s selected at %s", st.name, st.typ.Name, st.path)
+		}
+		g.writeStruct(st)
+	}
+
+	params, args := "ctx context.Context", "nil"
+	if varsName != "" {
+		params, args = "ctx context.Context, vars "+varsName, "vars"
+	}
+	g.comment("%s executes the %s %s. The response is returned even when err is not nil: it holds the partial data of a response with Errors.", name, op.Name, op.Operation)
+	fmt.Fprintf(&g.out, `func (c *Client) %s(%s) (*%s, error) {
+	var data %s
+	err := c.Execute(ctx, %s, %q, %s, &data)
+	return &data, err
+}
+
+`, name, params, response.name, response.name, documentName, op.Name, args)
+
+	if varsName != "" {
+		g.paginators(name, varsName, op, response, vars)
+	}
+	return nil
+}
+
+// reachableFragments returns the names of the fragments spread in set,
+// directly or through other fragments, in sorted order
+func (g *clientGen) reachableFragments(set graphql.SelectionSet) []string {
+	seen := make(map[string]bool)
+	var walk func(set graphql.SelectionSet)
+	walk = func(set graphql.SelectionSet) {
+		for _, sel := range set {
+			switch sel := sel.(type) {
+			case *graphql.Field:
+				walk(sel.SelectionSet)
+			case *graphql.InlineFragment:
+				walk(sel.SelectionSet)
+			case *graphql.FragmentSpread:
+				if !seen[sel.Name] {
+					seen[sel.Name] = true
+					walk(g.fragments[sel.Name].SelectionSet)
+				}
+			}
+		}
+	}
+	walk(set)
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fill collects the fields of the selections of st, declaring the structs
+// of object selections in depth-first order after st in structs
+func (g *clientGen) fill(st *goStruct, prefix string, sets []graphql.SelectionSet, structs *[]*goStruct) {
+	var selections []*selection
+	for _, set := range sets {
+		g.collect(st.typ, st.typ, set, false, &selections)
+	}
+	if st.typ.Kind != "OBJECT" && !g.concreteDone {
+		for _, set := range sets {
+			for _, name := range g.typeConditions(set) {
+				g.concrete[name] = append(g.concrete[name], sets)
+			}
+		}
+	}
+	g.fillSelections(st, prefix, selections, structs)
+}
+
+// fillSelections adds a field to st for each of selections, like fill
+func (g *clientGen) fillSelections(st *goStruct, prefix string, selections []*selection, structs *[]*goStruct) {
+	*structs = append(*structs, st)
+	names := make(map[string]bool)
+	for _, sel := range selections {
+		f := &goField{name: goName(sel.key), key: sel.key, def: sel.def, after: sel.after}
+		for i := 2; names[f.name]; i++ {
+			f.name = goName(sel.key) + strconv.Itoa(i)
+		}
+		names[f.name] = true
+
+		if sel.def == nil {
+			f.typ = "string"
+			if sel.conditional {
+				f.typ = "*string"
+			}
+			st.fields = append(st.fields, f)
+			continue
+		}
+		fieldPath := sel.key
+		if st.path != "" {
+			fieldPath = st.path + "." + sel.key
+		}
+		f.typ = g.goType(sel.def.Type, sel.conditional, func() string {
+			f.elem = &goStruct{name: g.declare(prefix + f.name), typ: g.model.Resolve(sel.def.Type), path: fieldPath}
+			g.fill(f.elem, prefix+f.name, sel.sets, structs)
+			return f.elem.name
+		})
+		st.fields = append(st.fields, f)
+	}
+}
+
+// collect merges the fields of set, selected on scope within a struct of
+// structType, into selections. Fields are conditional when a directive or a
+// fragment that does not apply to every possible type of structType may
+// leave them out. Fragments that cannot apply to an object structType are
+// left out.
+func (g *clientGen) collect(structType, scope *schema.Type, set graphql.SelectionSet, conditional bool, selections *[]*selection) {
+	fragment := func(typeCondition string, directives []*graphql.Directive, set graphql.SelectionSet) {
+		t := scope
+		if typeCondition != "" {
+			t = g.model.Type(typeCondition)
+		}
+		if structType.Kind == "OBJECT" && !g.covers(t, structType) {
+			return
+		}
+		g.collect(structType, t, set, conditional || skippable(directives) || !g.covers(t, structType), selections)
+	}
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *graphql.Field:
+			cond := conditional || skippable(sel.Directives)
+			if sel.Name != "__typename" {
+				g.deps[scope.Name+"."+sel.Name] = true
+			}
+			i := slices.IndexFunc(*selections, func(s *selection) bool { return s.key == sel.ResponseKey() })
+			if i >= 0 {
+				existing := (*selections)[i]
+				existing.sets = append(existing.sets, sel.SelectionSet)
+				existing.conditional = existing.conditional && cond
+				continue
+			}
+			s := &selection{key: sel.ResponseKey(), conditional: cond, sets: []graphql.SelectionSet{sel.SelectionSet}}
+			if sel.Name != "__typename" {
+				s.def = scope.Field(sel.Name)
+			}
+			if arg := sel.Argument("after"); arg != nil && arg.Value.Kind == graphql.VariableValue {
+				s.after = arg.Value.Raw
+			}
+			*selections = append(*selections, s)
+		case *graphql.InlineFragment:
+			fragment(sel.TypeCondition, sel.Directives, sel.SelectionSet)
+		case *graphql.FragmentSpread:
+			f := g.fragments[sel.Name]
+			fragment(f.TypeCondition, sel.Directives, f.SelectionSet)
+		}
+	}
+}
+
+// typeConditions returns the object types named by the type conditions of
+// the fragments in set, including fragments nested in fragments, in order of
+// appearance
+func (g *clientGen) typeConditions(set graphql.SelectionSet) []string {
+	var names []string
+	var walk func(set graphql.SelectionSet)
+	condition := func(typeCondition string, set graphql.SelectionSet) {
+		if t := g.model.Type(typeCondition); t != nil && t.Kind == "OBJECT" && !slices.Contains(names, t.Name) {
+			names = append(names, t.Name)
+		}
+		walk(set)
+	}
+	walk = func(set graphql.SelectionSet) {
+		for _, sel := range set {
+			switch sel := sel.(type) {
+			case *graphql.InlineFragment:
+				condition(sel.TypeCondition, sel.SelectionSet)
+			case *graphql.FragmentSpread:
+				f := g.fragments[sel.Name]
+				condition(f.TypeCondition, f.SelectionSet)
+			}
+		}
+	}
+	walk(set)
+	return names
+}
+
+// concreteTypes writes a struct for each object type of g.concrete, with
+// the fields any of its selections select on it, and RegisterTypes to
+// register them with a typename.Registry. Fields that some selection leaves
+// out, or may leave out, are pointers.
+func (g *clientGen) concreteTypes() {
+	g.concreteDone = true
+	var registered []*goStruct
+	var structs []*goStruct
+	for _, name := range sortedKeys(setOf(g.concrete)) {
+		t := g.model.Type(name)
+		sites := g.concrete[name]
+		required := make(map[string]int)
+		var selections []*selection
+		for _, sets := range sites {
+			var site []*selection
+			for _, set := range sets {
+				g.collect(t, t, set, false, &site)
+			}
+			for _, sel := range site {
+				if !sel.conditional {
+					required[sel.key]++
+				}
+			}
+			for _, set := range sets {
+				g.collect(t, t, set, false, &selections)
+			}
+		}
+		for _, sel := range selections {
+			sel.conditional = required[sel.key] < len(sites)
+		}
+		st := &goStruct{name: g.declare(goName(name)), typ: t, path: name}
+		registered = append(registered, st)
+		g.fillSelections(st, st.name, selections, &structs)
+	}
+
+	for _, st := range structs {
+		if slices.Contains(registered, st) {
+			g.comment("%s is the %s selected by a type condition under an interface or union, which a typename.Registry decodes by __typename after RegisterTypes", st.name, st.typ.Name)
+		} else {
+			g.comment("%s is the %s selected at %s", st.name, st.typ.Name, st.path)
+		}
+		g.writeStruct(st)
+	}
+	g.comment("RegisterTypes registers the structs of the object types selected by type conditions under interfaces and unions with r, so that r.Decode returns them for those selections. It panics if the schema of r lacks one of the types.")
+	g.out.WriteString("func RegisterTypes(r *typename.Registry) {\n")
+	for _, st := range registered {
+		fmt.Fprintf(&g.out, "\tr.MustRegister(%q, %s{})\n", st.typ.Name, st.name)
+	}
+	g.out.WriteString("}\n\n")
+}
+
+// covers reports whether every possible type of inner is also one of t
+func (g *clientGen) covers(t, inner *schema.Type) bool {
+	possible := func(t *schema.Type) []string {
+		if t.Kind == "OBJECT" {
+			return []string{t.Name}
+		}
+		return t.PossibleTypes
+	}
+	outer := possible(t)
+	for _, name := range possible(inner) {
+		if !slices.Contains(outer, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// skippable reports whether @include or @skip may leave a selection out
+func skippable(directives []*graphql.Directive) bool {
+	return slices.ContainsFunc(directives, func(d *graphql.Directive) bool {
+		return d.Name == "include" || d.Name == "skip"
+	})
+}
+
+// goType returns the Go type of ref: nullable types and optional ones are
+// pointers, lists are slices, and object selections get the struct object
+// declares
+func (g *clientGen) goType(ref *schema.TypeRef, optional bool, object func() string) string {
+	if ref.Nullable().Kind == "LIST" {
+		return "[]" + g.goType(ref.Elem(), false, object)
+	}
+	var typ string
+	switch named := ref.Unwrap(); named.Kind {
+	case "SCALAR":
+		typ = g.scalar(named.Name)
+	case "ENUM":
+		g.enums[named.Name] = true
+		g.deps[named.Name] = true
+		typ = named.Name
+	case "INPUT_OBJECT":
+		g.input(named.Name)
+		typ = named.Name
+	default:
+		typ = object()
+	}
+	if optional || !ref.IsNonNull() {
+		typ = "*" + typ
+	}
+	return typ
+}
+
+// scalar returns the Go type of a scalar. GitHub's custom scalars are
+// strings, except the timestamps; unknown scalars are kept as raw JSON.
+func (g *clientGen) scalar(name string) string {
+	switch name {
+	case "Int":
+		return "int"
+	case "Float":
+		return "float64"
+	case "Boolean":
+		return "bool"
+	case "DateTime", "PreciseDateTime":
+		g.packages["time"] = true
+		return "time.Time"
+	case "String", "ID", "Base64String", "BigInt", "Date", "GitObjectID", "GitRefname", "GitSSHRemote", "GitTimestamp", "HTML", "URI", "X509Certificate":
+		return "string"
+	}
+	g.packages["encoding/json"] = true
+	return "json.RawMessage"
+}
+
+// typeRefOf converts the type of a variable definition
+func (g *clientGen) typeRefOf(t *graphql.Type) *schema.TypeRef {
+	ref := &schema.TypeRef{Kind: g.model.Type(t.NamedType()).Kind, Name: t.Name}
+	if t.Elem != nil {
+		ref = &schema.TypeRef{Kind: "LIST", OfType: g.typeRefOf(t.Elem)}
+	}
+	if t.NonNull {
+		ref = &schema.TypeRef{Kind: "NON_NULL", OfType: ref}
+	}
+	return ref
+}
+
+// input records an input object and, through its fields, the enums and
+// input objects it refers to
+func (g *clientGen) input(name string) {
+	if g.inputs[name] {
+		return
+	}
+	g.inputs[name] = true
+	g.deps[name] = true
+	for _, f := range g.model.Type(name).InputFields {
+		g.goType(f.Type, false, nil)
+	}
+}
+
+// comment writes a doc comment of the formatted text, wrapped like the
+// descriptions
+func (g *clientGen) comment(format string, args ...interface{}) {
+	g.out.WriteString(Godoc(fmt.Sprintf(format, args...), GodocOptions{}))
+}
+
+// writeStruct writes a response struct with the descriptions of its fields
+func (g *clientGen) writeStruct(st *goStruct) {
+	fmt.Fprintf(&g.out, "type %s struct {\n", st.name)
+	for _, f := range st.fields {
+		if f.def == nil {
+			g.out.WriteString("\t// Typename is the name of the object type\n")
+		} else {
+			g.out.WriteString(Godoc(f.def.Description, GodocOptions{
+				Name: f.name, Indent: "\t", Deprecated: f.def.IsDeprecated, DeprecationReason: f.def.DeprecationReason,
+			}))
+		}
+		fmt.Fprintf(&g.out, "\t%s %s `json:%q`\n", f.name, f.typ, f.key)
+	}
+	g.out.WriteString("}\n\n")
+}
+
+// paginator is a connection an All method pages through
+type paginator struct {
+	path  []*goField // Fields from the response to the connection
+	items *goField   // nodes or edges
+	after string
+}
+
+// paginators writes an All method for each connection of the response that
+// can be paged through with an operation variable
+func (g *clientGen) paginators(name, varsName string, op *graphql.OperationDefinition, response *goStruct, vars map[string]string) {
+	var found []paginator
+	var walk func(st *goStruct, path []*goField)
+	walk = func(st *goStruct, path []*goField) {
+		for _, f := range st.fields {
+			if f.elem == nil || strings.HasPrefix(f.typ, "[]") {
+				continue
+			}
+			path := append(slices.Clip(path), f)
+			if p, ok := connection(f, vars); ok {
+				p.path = path
+				found = append(found, p)
+			}
+			walk(f.elem, path)
+		}
+	}
+	walk(response, nil)
+
+	for _, p := range found {
+		method := name + "All"
+		var keys []string
+		for _, f := range p.path {
+			keys = append(keys, f.key)
+		}
+		if len(found) > 1 {
+			for _, f := range p.path {
+				method += f.name
+			}
+		}
+		conn := p.path[len(p.path)-1]
+		pageInfo := field(conn.elem, "pageInfo")
+		endCursor := field(pageInfo.elem, "endCursor")
+		hasNextPage := field(pageInfo.elem, "hasNextPage")
+
+		g.comment("%s executes the %s %s for every page of %s, passing pageInfo.endCursor as $%s, and returns the %s of all pages. Other variables are kept as given. On error, the %s of the pages before it are returned.",
+			method, op.Name, op.Operation, strings.Join(keys, "."), p.after, p.items.key, p.items.key)
+		fmt.Fprintf(&g.out, `func (c *Client) %s(ctx context.Context, vars %s) (%s, error) {
+	var all %s
+	for {
+		resp, err := c.%s(ctx, vars)
+		if err != nil {
+			return all, err
+		}
+`, method, varsName, p.items.typ, p.items.typ, name)
+
+		expr := "resp"
+		for _, f := range p.path[:len(p.path)-1] {
+			expr += "." + f.name
+			if strings.HasPrefix(f.typ, "*") {
+				fmt.Fprintf(&g.out, "\t\tif %s == nil {\n\t\t\treturn all, nil\n\t\t}\n", expr)
+			}
+		}
+		fmt.Fprintf(&g.out, "\t\tconn := %s.%s\n", expr, conn.name)
+		if strings.HasPrefix(conn.typ, "*") {
+			g.out.WriteString("\t\tif conn == nil {\n\t\t\treturn all, nil\n\t\t}\n")
+		}
+		cursor := "conn." + pageInfo.name + "." + endCursor.name
+		fmt.Fprintf(&g.out, "\t\tall = append(all, conn.%s...)\n", p.items.name)
+		stop := "!conn." + pageInfo.name + "." + hasNextPage.name
+		if strings.HasPrefix(endCursor.typ, "*") {
+			stop += " || " + cursor + " == nil"
+		}
+		fmt.Fprintf(&g.out, "\t\tif %s {\n\t\t\treturn all, nil\n\t\t}\n", stop)
+		switch after := goName(p.after); {
+		case vars[p.after] == endCursor.typ:
+			fmt.Fprintf(&g.out, "\t\tvars.%s = %s\n", after, cursor)
+		case strings.HasPrefix(endCursor.typ, "*"):
+			fmt.Fprintf(&g.out, "\t\tvars.%s = *%s\n", after, cursor)
+		default:
+			fmt.Fprintf(&g.out, "\t\tcursor := %s\n\t\tvars.%s = &cursor\n", cursor, after)
+		}
+		g.out.WriteString("\t}\n}\n\n")
+	}
+}
+
+// connection reports whether f is a connection paged through with a variable
+// as its "after" argument, selecting pageInfo { hasNextPage endCursor } and
+// nodes or edges
+func connection(f *goField, vars map[string]string) (paginator, bool) {
+	if f.after == "" || vars[f.after] == "" || strings.HasPrefix(vars[f.after], "[]") {
+		return paginator{}, false
+	}
+	pageInfo := field(f.elem, "pageInfo")
+	if pageInfo == nil || pageInfo.elem == nil || strings.HasPrefix(pageInfo.typ, "*") {
+		return paginator{}, false
+	}
+	if hasNextPage := field(pageInfo.elem, "hasNextPage"); hasNextPage == nil || hasNextPage.typ != "bool" {
+		return paginator{}, false
+	}
+	if endCursor := field(pageInfo.elem, "endCursor"); endCursor == nil || strings.TrimPrefix(endCursor.typ, "*") != "string" {
+		return paginator{}, false
+	}
+	items := field(f.elem, "nodes")
+	if items == nil {
+		items = field(f.elem, "edges")
+	}
+	if items == nil || !strings.HasPrefix(items.typ, "[]") {
+		return paginator{}, false
+	}
+	return paginator{items: items, after: f.after}, true
+}
+
+// field returns the field of st selecting the schema field with the given
+// name, or nil
+func field(st *goStruct, name string) *goField {
+	for _, f := range st.fields {
+		if f.def != nil && f.def.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// namedTypes writes the enums and input objects the operations refer to
+func (g *clientGen) namedTypes() {
+	for _, name := range sortedKeys(g.enums) {
+		t := g.model.Type(name)
+		g.out.WriteString(Godoc(t.Description, GodocOptions{Name: name}))
+		fmt.Fprintf(&g.out, "type %s string\n\nconst (\n", name)
+		for _, v := range t.EnumValues {
+			constName := name + goName(v.Name)
+			g.out.WriteString(Godoc(v.Description, GodocOptions{
+				Name: constName, Indent: "\t", Deprecated: v.IsDeprecated, DeprecationReason: v.DeprecationReason,
+			}))
+			fmt.Fprintf(&g.out, "\t%s %s = %q\n", constName, name, v.Name)
+		}
+		g.out.WriteString(")\n\n")
+	}
+	for _, name := range sortedKeys(g.inputs) {
+		t := g.model.Type(name)
+		g.out.WriteString(Godoc(t.Description, GodocOptions{Name: name}))
+		fmt.Fprintf(&g.out, "type %s struct {\n", name)
+		for _, f := range t.InputFields {
+			g.out.WriteString(Godoc(f.Description, GodocOptions{
+				Name: goName(f.Name), Indent: "\t", Deprecated: f.IsDeprecated, DeprecationReason: f.DeprecationReason,
+			}))
+			tag := f.Name
+			if !f.Required() {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(&g.out, "\t%s %s `json:%q`\n", goName(f.Name), g.goType(f.Type, f.DefaultValue != nil, nil), tag)
+		}
+		g.out.WriteString("}\n\n")
+	}
+}
+
+// setOf returns the keys of m as a set
+func setOf[V any](m map[string]V) map[string]bool {
+	set := make(map[string]bool, len(m))
+	for k := range m {
+		set[k] = true
+	}
+	return set
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
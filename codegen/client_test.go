@@ -0,0 +1,197 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+// TestGenerateClientExample checks that the client generated into
+// examples/client is up to date; run go generate ./examples/client otherwise
+func TestGenerateClientExample(t *testing.T) {
+	s, err := schema.New()
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	dir := filepath.Join("..", "examples", "client")
+	entries, err := os.ReadDir(filepath.Join(dir, "queries"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sources []Source
+	for _, e := range entries {
+		text, err := os.ReadFile(filepath.Join(dir, "queries", e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sources = append(sources, Source{Name: e.Name(), Text: string(text)})
+	}
+
+	files, err := GenerateClient(s, sources, ClientOptions{})
+	if err != nil {
+		t.Fatalf("GenerateClient failed: %v", err)
+	}
+	for _, f := range files {
+		want, err := os.ReadFile(filepath.Join(dir, f.Name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(f.Content) != string(want) {
+			t.Errorf("examples/client/%s is out of date; run go generate ./examples/client", f.Name)
+		}
+	}
+	deps := files[1].Dependencies
+	for _, want := range []string{"AddCommentInput", "IssueState", "Query.repository", "Issue.number", "PullRequest.number"} {
+		if !slices.Contains(deps, want) {
+			t.Errorf("Dependencies %v do not include %s", deps, want)
+		}
+	}
+}
+
+func TestGenerateClient(t *testing.T) {
+	s, err := schema.New()
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	files, err := GenerateClient(s, []Source{{Name: "ops.graphql", Text: `
+query Stargazers($owner: String!, $name: String!, $cursor: String!, $withForks: Boolean!) {
+  repository(owner: $owner, name: $name) {
+    stargazers(first: 100, after: $cursor) {
+      pageInfo { hasNextPage endCursor }
+      edges { starredAt }
+    }
+    forks(first: 10, after: $cursor) @include(if: $withForks) {
+      pageInfo { hasNextPage endCursor }
+      nodes { databaseId }
+    }
+    owner { login ... on Organization { url } }
+  }
+}`}}, ClientOptions{Package: "gh"})
+	if err != nil {
+		t.Fatalf("GenerateClient failed: %v", err)
+	}
+	got := string(files[1].Content)
+	for _, want := range []string{
+		"package gh\n",
+		"\tCursor    string `json:\"cursor\"`\n",
+		// A field left out by @include is a pointer
+		"\tForks *StargazersRepositoryForks `json:\"forks\"`\n",
+		// A field of a fragment on an implementation of the interface is a pointer
+		"\tURL *string `json:\"url\"`\n",
+		"\tDatabaseID *int `json:\"databaseId\"`\n",
+		// Two connections get an All method each, paging through edges or nodes
+		"func (c *Client) StargazersAllRepositoryStargazers(ctx context.Context, vars StargazersVariables) ([]*StargazersRepositoryStargazersEdges, error) {",
+		"func (c *Client) StargazersAllRepositoryForks(ctx context.Context, vars StargazersVariables) ([]*StargazersRepositoryForksNodes, error) {",
+		"\t\tif conn == nil {\n",
+		"\t\tvars.Cursor = *conn.PageInfo.EndCursor\n",
+		// The Organization of the fragment under RepositoryOwner is registered
+		"type Organization struct {\n",
+		"\tURL string `json:\"url\"`\n",
+		"func RegisterTypes(r *typename.Registry) {\n\tr.MustRegister(\"Organization\", Organization{})\n}\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Generated code does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestGenerateClientRegisterTypes(t *testing.T) {
+	s, err := schema.NewSample()
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	files, err := GenerateClient(s, []Source{{Name: "ops.graphql", Text: `
+query Item($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    issueOrPullRequest(number: $number) {
+      __typename
+      ... on Issue { number title }
+    }
+  }
+}
+
+query Search($q: String!) {
+  search(query: $q, type: ISSUE, first: 10) {
+    nodes {
+      __typename
+      ... on Node { id }
+      ... on Issue { number }
+      ... on PullRequest { merged }
+    }
+  }
+}`}}, ClientOptions{})
+	if err != nil {
+		t.Fatalf("GenerateClient failed: %v", err)
+	}
+	got := string(files[1].Content)
+	for _, want := range []string{
+		// Issue is selected under two unions; fields of only one are pointers
+		"type Issue struct {\n\t// Typename is the name of the object type\n\tTypename string `json:\"__typename\"`\n",
+		"\tNumber int `json:\"number\"`\n",
+		"\tTitle *string `json:\"title\"`\n",
+		"\tID *string `json:\"id\"`\n",
+		// Fields of fragments on other types are left out
+		"type PullRequest struct {\n\t// Typename is the name of the object type\n\tTypename string `json:\"__typename\"`\n",
+		"\tr.MustRegister(\"Issue\", Issue{})\n\tr.MustRegister(\"PullRequest\", PullRequest{})\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Generated code does not contain %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got[strings.Index(got, "type Issue struct"):strings.Index(got, "type PullRequest struct")], "Merged") {
+		t.Errorf("Issue has a field of the PullRequest fragment:\n%s", got)
+	}
+}
+
+func TestGenerateClientErrors(t *testing.T) {
+	s, err := schema.New()
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	tests := []struct {
+		name    string
+		sources []Source
+		want    string
+	}{
+		{name: "no operations", sources: []Source{{Name: "f.graphql", Text: "fragment F on User { login }"}}, want: "no operations"},
+		{name: "syntax", sources: []Source{{Name: "bad.graphql", Text: "query {"}}, want: "bad.graphql: syntax error"},
+		{
+			name: "invalid field in the second file",
+			sources: []Source{
+				{Name: "a.graphql", Text: "query A {\n  viewer { login }\n}\n"},
+				{Name: "b.graphql", Text: "query B {\n  viewer { nope }\n}"},
+			},
+			want: "b.graphql:2:12:",
+		},
+		{name: "anonymous", sources: []Source{{Name: "anon.graphql", Text: "{ viewer { login } }"}}, want: "anon.graphql:1:1: operations must be named"},
+		{name: "Execute", sources: []Source{{Name: "x.graphql", Text: "query execute { viewer { login } }"}}, want: "conflicts with Client.Execute"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := GenerateClient(s, tt.sources, ClientOptions{}); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestGoName(t *testing.T) {
+	for name, want := range map[string]string{
+		"databaseId":    "DatabaseID",
+		"avatarUrl":     "AvatarURL",
+		"nameWithOwner": "NameWithOwner",
+		"MERGE_COMMIT":  "MergeCommit",
+		"OPEN":          "Open",
+		"__typename":    "Typename",
+		"assigneeIds":   "AssigneeIDs",
+		"sha256":        "Sha256",
+	} {
+		if got := goName(name); got != want {
+			t.Errorf("goName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
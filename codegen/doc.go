@@ -0,0 +1,30 @@
+// Package codegen generates typed Go clients for GraphQL operations, plans
+// code regeneration after a schema update, and renders schema descriptions as
+// Go doc comments for generated code.
+//
+// A manifest maps each generated file to the schema elements it was generated
+// from. Comparing the schema the code was generated against with the updated
+// schema yields the files that need regeneration and the breaking input
+// changes (removed or retyped input fields and arguments, new required
+// inputs, removed enum values) that need manual attention, because callers
+// must be changed rather than regenerated:
+//
+//	m, err := codegen.ReadManifest("codegen.manifest")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	plan, err := codegen.NewPlan(oldSchema, newSchema, m)
+//
+// Dependencies are not followed transitively: a file that embeds the fields
+// of a referenced type must list that type too.
+//
+// Godoc converts a GraphQL description, which is Markdown, into a gofmt-clean
+// doc comment with wrapped paragraphs, doc links, and a "Deprecated:"
+// paragraph for deprecated members.
+//
+// GenerateClient turns validated operations into a Client with a method per
+// operation, typed variables and responses, All methods that page through
+// connections, and RegisterTypes for decoding interface and union selections
+// with a typename.Registry. The Dependencies of the generated files form a
+// manifest entry for NewPlan.
+package codegen
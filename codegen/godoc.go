@@ -0,0 +1,411 @@
+package codegen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultDocsURL replaces the ${externalDocsUrl} placeholder found in links
+// of GitHub's descriptions
+const DefaultDocsURL = "https://docs.github.com"
+
+// GodocOptions controls how Godoc renders a description
+type GodocOptions struct {
+	// Name is the Go identifier the comment documents. When set, the first
+	// sentence is rephrased to start with it where the wording allows, as
+	// godoc recommends: "The title of the issue." becomes "Title is the title
+	// of the issue." and "Identifies the primary key." becomes "ID identifies
+	// the primary key." Other sentences are kept as they are.
+	Name string
+	// Width is the maximum length of a wrapped line including the "// "
+	// prefix but not Indent; zero means 80. Words longer than the width, such
+	// as URLs, are not broken.
+	Width int
+	// Indent is prepended to every line, such as "\t" for struct fields
+	Indent string
+	// DocsURL replaces ${externalDocsUrl}; empty means DefaultDocsURL
+	DocsURL string
+	// Deprecated adds a final "Deprecated:" paragraph, which pkg.go.dev and
+	// linters recognize, with DeprecationReason or, when it is empty, the
+	// GraphQL default reason
+	Deprecated        bool
+	DeprecationReason string
+}
+
+// Godoc converts a GraphQL description, which is Markdown, into a Go doc
+// comment of "//" lines ending in a newline. Paragraphs are rewrapped;
+// headings, bullet and numbered lists, and fenced or indented code blocks
+// become their doc comment forms; Markdown links become doc links with their
+// URLs listed at the end; bold markers are dropped. The result is already in
+// the form gofmt gives doc comments. An empty description without
+// deprecation gives "".
+func Godoc(description string, opts GodocOptions) string {
+	if opts.Width <= 0 {
+		opts.Width = 80
+	}
+	if opts.DocsURL == "" {
+		opts.DocsURL = DefaultDocsURL
+	}
+
+	blocks := parseMarkdown(description)
+	if opts.Deprecated {
+		reason := strings.TrimSpace(opts.DeprecationReason)
+		if reason == "" {
+			reason = "No longer supported"
+		}
+		blocks = append(blocks, docBlock{kind: paragraphBlock, text: "Deprecated: " + reason})
+	}
+	if len(blocks) == 0 {
+		return ""
+	}
+
+	// Doc comments cannot nest code blocks in lists: an indented block after
+	// a list continues the list. The items of a list interleaved with code
+	// blocks are written as plain paragraphs instead.
+	plain := make([]bool, len(blocks))
+	for i := 0; i < len(blocks); {
+		if blocks[i].kind != listBlock {
+			i++
+			continue
+		}
+		end, hasCode := i, false
+		for end < len(blocks) && (blocks[end].kind == codeBlock || sameList(blocks[i], blocks[end])) {
+			hasCode = hasCode || blocks[end].kind == codeBlock
+			end++
+		}
+		for ; i < end; i++ {
+			plain[i] = hasCode
+		}
+	}
+
+	links := &linkSet{urls: map[string]string{}}
+	width := opts.Width - len("// ")
+	var lines []string
+	for i, b := range blocks {
+		// Items of one list are written without blank lines between them
+		if i > 0 && !(sameList(blocks[i-1], b) && !plain[i]) {
+			lines = append(lines, "")
+		}
+		switch {
+		case b.kind == codeBlock:
+			for _, line := range b.lines {
+				if line != "" {
+					line = "\t" + line
+				}
+				lines = append(lines, line)
+			}
+		case b.kind == headingBlock:
+			text := inlineMarkdown(b.text, links, opts.DocsURL)
+			if strings.ContainsAny(text[len(text)-1:], ".,:;!?") {
+				// Doc comment headings cannot end in punctuation
+				lines = append(lines, wrap(text, width)...)
+			} else {
+				lines = append(lines, "# "+text)
+			}
+		case b.kind == listBlock && plain[i]:
+			text := inlineMarkdown(b.marker+" "+b.text, links, opts.DocsURL)
+			lines = append(lines, wrap(text, width)...)
+		case b.kind == listBlock:
+			text := inlineMarkdown(b.text, links, opts.DocsURL)
+			marker := "  - "
+			if b.marker != "-" {
+				marker = fmt.Sprintf("%3s ", b.marker)
+			}
+			for j, line := range wrap(text, width-len(marker)) {
+				if j == 0 {
+					lines = append(lines, marker+line)
+				} else {
+					lines = append(lines, strings.Repeat(" ", len(marker))+line)
+				}
+			}
+		default:
+			text := inlineMarkdown(b.text, links, opts.DocsURL)
+			if i == 0 && opts.Name != "" {
+				text = withName(opts.Name, text)
+			}
+			lines = append(lines, wrap(text, width)...)
+		}
+	}
+	if len(links.order) > 0 {
+		lines = append(lines, "")
+		for _, text := range links.order {
+			lines = append(lines, "["+text+"]: "+links.urls[text])
+		}
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(opts.Indent)
+		switch {
+		case line == "":
+			b.WriteString("//")
+		case strings.HasPrefix(line, "\t"):
+			b.WriteString("//" + line)
+		default:
+			b.WriteString("// " + line)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+type blockKind int
+
+const (
+	paragraphBlock blockKind = iota
+	headingBlock
+	listBlock
+	codeBlock
+)
+
+// docBlock is a block of a description: the text of a paragraph, heading,
+// or list item, or the lines of a code block
+type docBlock struct {
+	kind   blockKind
+	text   string
+	lines  []string
+	marker string // "-" for bullets, "1." and so on for numbered items
+}
+
+var (
+	headingPattern  = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*$`)
+	listItemPattern = regexp.MustCompile(`^([-*+]|\d{1,3}[.)])\s+(.*)$`)
+)
+
+// parseMarkdown splits a description into blocks. It covers the Markdown
+// GitHub's descriptions use rather than all of CommonMark.
+func parseMarkdown(description string) []docBlock {
+	lines := strings.Split(strings.ReplaceAll(description, "\r\n", "\n"), "\n")
+	var blocks []docBlock
+	var text []string // Lines of the open paragraph or list item
+	open := false     // Whether text belongs to the last block
+	itemIndent := -1  // Content column of the last list item, -1 outside lists
+	blank := true     // Whether the previous line was blank
+	flush := func() {
+		if open {
+			blocks[len(blocks)-1].text = strings.Join(text, " ")
+		}
+		open = false
+	}
+	start := func(b docBlock) {
+		flush()
+		blocks = append(blocks, b)
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+		indent := indentOf(line)
+		base := 0
+		if itemIndent >= 0 {
+			base = itemIndent
+		}
+
+		switch {
+		case trimmed == "":
+			// Text after a blank line continues a list item only when indented
+			if open && blocks[len(blocks)-1].kind == paragraphBlock {
+				flush()
+			}
+			blank = true
+			continue
+
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence := trimmed[:3]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, strings.TrimRight(lines[i], " \t"))
+			}
+			start(docBlock{kind: codeBlock, lines: dedent(code)})
+
+		case indent >= base+4 && (blank || !open):
+			code := []string{line}
+			for i+1 < len(lines) {
+				next := strings.TrimRight(lines[i+1], " \t")
+				if next != "" && indentOf(next) < base+4 {
+					break
+				}
+				code = append(code, next)
+				i++
+			}
+			for len(code) > 0 && code[len(code)-1] == "" {
+				code = code[:len(code)-1]
+			}
+			start(docBlock{kind: codeBlock, lines: dedent(code)})
+
+		case indent < 4 && headingPattern.MatchString(trimmed) && headingPattern.FindStringSubmatch(trimmed)[1] != "":
+			start(docBlock{kind: headingBlock, text: headingPattern.FindStringSubmatch(trimmed)[1]})
+			itemIndent = -1
+
+		case listItemPattern.MatchString(trimmed) && (blank || itemIndent >= 0 || !open):
+			m := listItemPattern.FindStringSubmatch(trimmed)
+			marker := m[1]
+			if len(marker) == 1 && !unicode.IsDigit(rune(marker[0])) {
+				marker = "-"
+			} else {
+				marker = strings.TrimRight(marker, ".)") + "."
+			}
+			start(docBlock{kind: listBlock, marker: marker})
+			text, open = []string{m[2]}, true
+			itemIndent = indent + len(trimmed) - len(m[2])
+
+		case open && (!blank || (itemIndent >= 0 && indent >= itemIndent)):
+			text = append(text, trimmed)
+
+		default:
+			if blank || itemIndent < 0 || indent < itemIndent {
+				itemIndent = -1
+			}
+			start(docBlock{kind: paragraphBlock})
+			text, open = []string{trimmed}, true
+		}
+		blank = false
+	}
+	flush()
+	return blocks
+}
+
+// sameList reports whether a and b are items of one list, both bulleted or
+// both numbered
+func sameList(a, b docBlock) bool {
+	return a.kind == listBlock && b.kind == listBlock && (a.marker == "-") == (b.marker == "-")
+}
+
+// indentOf returns the indentation of line in columns, counting a tab as four
+func indentOf(line string) int {
+	n := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			n++
+		case '\t':
+			n += 4
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// dedent removes the indentation common to the non-blank lines
+func dedent(lines []string) []string {
+	common := -1
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		if n := indentOf(line); common < 0 || n < common {
+			common = n
+		}
+	}
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		line = strings.ReplaceAll(line[:len(line)-len(strings.TrimLeft(line, " \t"))], "\t", "    ") + strings.TrimLeft(line, " \t")
+		out[i] = line[common:]
+	}
+	return out
+}
+
+// linkSet collects the URLs of doc links in order of first use
+type linkSet struct {
+	urls  map[string]string
+	order []string
+}
+
+// add records a link and reports whether its text can be a doc link, which
+// needs the same text to always link to the same URL
+func (s *linkSet) add(text, url string) bool {
+	if strings.ContainsAny(text, "[]") {
+		return false
+	}
+	if existing, ok := s.urls[text]; ok {
+		return existing == url
+	}
+	s.urls[text] = url
+	s.order = append(s.order, text)
+	return true
+}
+
+var (
+	markdownLink = regexp.MustCompile(`\[([^\[\]]+)\]\(([^()\s]+)\)`)
+	autoLink     = regexp.MustCompile(`<(https?://[^<>\s]+)>`)
+	boldText     = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	whitespace   = regexp.MustCompile(`\s+`)
+)
+
+// inlineMarkdown converts the inline Markdown of a block's text
+func inlineMarkdown(text string, links *linkSet, docsURL string) string {
+	text = strings.ReplaceAll(text, "${externalDocsUrl}", strings.TrimSuffix(docsURL, "/"))
+	text = markdownLink.ReplaceAllStringFunc(text, func(link string) string {
+		m := markdownLink.FindStringSubmatch(link)
+		label := whitespace.ReplaceAllString(boldText.ReplaceAllString(m[1], "$1"), " ")
+		if links.add(label, m[2]) {
+			return "[" + label + "]"
+		}
+		return label + " (" + m[2] + ")"
+	})
+	text = autoLink.ReplaceAllString(text, "$1")
+	text = boldText.ReplaceAllString(text, "$1")
+	return strings.TrimSpace(whitespace.ReplaceAllString(text, " "))
+}
+
+// wrap breaks text into lines of at most width bytes where possible
+func wrap(text string, width int) []string {
+	var lines []string
+	var line strings.Builder
+	for _, word := range strings.Fields(text) {
+		if line.Len() > 0 && line.Len()+1+len(word) > width {
+			lines = append(lines, line.String())
+			line.Reset()
+		}
+		if line.Len() > 0 {
+			line.WriteByte(' ')
+		}
+		line.WriteString(word)
+	}
+	return append(lines, line.String())
+}
+
+// describingVerbs are words starting descriptions that read as a verb after
+// the documented name, as in "ID identifies the primary key"
+var describingVerbs = map[string]bool{
+	"identifies": true, "represents": true, "indicates": true, "returns": true,
+	"specifies": true, "describes": true, "determines": true, "contains": true,
+	"fetches": true, "sets": true, "adds": true, "updates": true, "creates": true,
+	"deletes": true, "removes": true, "marks": true, "filters": true,
+}
+
+// withName rephrases the first sentence of text to start with name where
+// its first word allows
+func withName(name, text string) string {
+	first, rest, ok := strings.Cut(text, " ")
+	if !ok {
+		return text
+	}
+	lower := lowerFirst(first)
+	switch {
+	case lower == "the" || lower == "a" || lower == "an":
+		return name + " is " + lower + " " + rest
+	case lower == "whether":
+		return name + " reports whether " + rest
+	case describingVerbs[lower]:
+		return name + " " + lower + " " + rest
+	}
+	return text
+}
+
+// lowerFirst lowercases the first letter of a capitalized word, leaving
+// acronyms such as URL as they are
+func lowerFirst(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	if next, _ := utf8.DecodeRuneInString(word[size:]); unicode.IsUpper(next) {
+		return word
+	}
+	return string(unicode.ToLower(r)) + word[size:]
+}
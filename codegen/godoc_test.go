@@ -0,0 +1,152 @@
+package codegen
+
+import (
+	"go/doc/comment"
+	"strings"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+func TestGodoc(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		opts        GodocOptions
+		want        string
+	}{
+		{name: "empty", description: "", want: ""},
+		{name: "article", description: "The title of the issue.", opts: GodocOptions{Name: "Title"}, want: "// Title is the title of the issue.\n"},
+		{name: "verb", description: "Identifies the primary key from the database.", opts: GodocOptions{Name: "DatabaseID"}, want: "// DatabaseID identifies the primary key from the database.\n"},
+		{name: "whether", description: "Whether or not the pull request is rebaseable.", opts: GodocOptions{Name: "Rebaseable"}, want: "// Rebaseable reports whether or not the pull request is rebaseable.\n"},
+		{name: "other wording", description: "URL of the avatar.", opts: GodocOptions{Name: "AvatarURL"}, want: "// URL of the avatar.\n"},
+		{
+			name:        "wrapped with indent",
+			description: "The HTTP URL for this\nrepository, which is where the repository page is served from.",
+			opts:        GodocOptions{Width: 40, Indent: "\t"},
+			want:        "\t// The HTTP URL for this repository,\n\t// which is where the repository page is\n\t// served from.\n",
+		},
+		{
+			name:        "inline markdown",
+			description: "See \"[About alerts](${externalDocsUrl}/code-security/alerts)\" and <https://spdx.org/licenses>.\n\n**Upcoming Change on 2024-04-01 UTC**\n**Reason:** Topics are no longer supported",
+			want: "// See \"[About alerts]\" and https://spdx.org/licenses.\n//\n" +
+				"// Upcoming Change on 2024-04-01 UTC Reason: Topics are no longer supported\n//\n" +
+				"// [About alerts]: https://docs.github.com/code-security/alerts\n",
+		},
+		{
+			name:        "conflicting link texts",
+			description: "[docs](https://a.example) or [docs](https://b.example)",
+			want:        "// [docs] or docs (https://b.example)\n//\n// [docs]: https://a.example\n",
+		},
+		{
+			name:        "deprecated",
+			description: "The body.",
+			opts:        GodocOptions{Name: "Body", Deprecated: true, DeprecationReason: "`body` will be removed. Use `bodyText`. Removal on 2025-01-01 UTC."},
+			want:        "// Body is the body.\n//\n// Deprecated: `body` will be removed. Use `bodyText`. Removal on 2025-01-01\n// UTC.\n",
+		},
+		{name: "deprecated without reason", opts: GodocOptions{Deprecated: true}, want: "// Deprecated: No longer supported\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Godoc(tt.description, tt.opts); got != tt.want {
+				t.Errorf("Godoc() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGodocBlocks(t *testing.T) {
+	description := "A git ref.\n\n### Examples\n\nSpecify a branch:\n\n    { \"id\": \"MDM6\" }\n\n" +
+		"Changes can be:\n\n1. An addition at `docs/README.txt`:\n\n       { \"additions\": [] }\n\n" +
+		"2. A modification, which replaces the\n   content of the file.\n\n- `path` values\n* must be unique\n\nFenced:\n\n```\nfenced\n  code\n```\n"
+	want := `// A git ref.
+//
+// # Examples
+//
+// Specify a branch:
+//
+//	{ "id": "MDM6" }
+//
+// Changes can be:
+//
+// 1. An addition at ` + "`docs/README.txt`" + `:
+//
+//	{ "additions": [] }
+//
+// 2. A modification, which replaces the content of the file.
+//
+//   - ` + "`path`" + ` values
+//   - must be unique
+//
+// Fenced:
+//
+//	fenced
+//	  code
+`
+	if got := Godoc(description, GodocOptions{}); got != want {
+		t.Errorf("Godoc() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestGodocGofmt checks that the comments for every description of the
+// embedded schema are left unchanged by gofmt and stay within the width
+func TestGodocGofmt(t *testing.T) {
+	s, err := schema.New()
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	check := func(where, description string, deprecated bool, reason string) {
+		t.Helper()
+		got := Godoc(description, GodocOptions{Width: 80, Deprecated: deprecated, DeprecationReason: reason})
+		if got == "" {
+			return
+		}
+		if formatted := gofmtComment(got); formatted != got {
+			t.Errorf("%s: gofmt changes the comment\n%s\ninto\n%s", where, got, formatted)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(got, "\n"), "\n") {
+			if len(line) > 80 && strings.Contains(strings.TrimSpace(strings.TrimPrefix(line, "//")), " ") &&
+				!strings.HasPrefix(line, "//\t") && !strings.HasPrefix(line, "// [") {
+				t.Errorf("%s: line longer than 80 bytes: %q", where, line)
+			}
+		}
+	}
+	for _, typ := range s.Model().Types {
+		check(typ.Name, typ.Description, false, "")
+		for _, f := range typ.Fields {
+			check(typ.Name+"."+f.Name, f.Description, f.IsDeprecated, f.DeprecationReason)
+		}
+		for _, f := range typ.InputFields {
+			check(typ.Name+"."+f.Name, f.Description, f.IsDeprecated, f.DeprecationReason)
+		}
+		for _, v := range typ.EnumValues {
+			check(typ.Name+"."+v.Name, v.Description, v.IsDeprecated, v.DeprecationReason)
+		}
+	}
+}
+
+// gofmtComment reformats a "//" doc comment the way gofmt does
+func gofmtComment(text string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		line = strings.TrimPrefix(line, "//")
+		lines = append(lines, strings.TrimPrefix(line, " "))
+	}
+	var p comment.Parser
+	var pr comment.Printer
+	out := string(pr.Comment(p.Parse(strings.Join(lines, "\n") + "\n")))
+
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		switch {
+		case line == "":
+			b.WriteString("//")
+		case strings.HasPrefix(line, "\t"):
+			b.WriteString("//" + line)
+		default:
+			b.WriteString("// " + line)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package codegen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Manifest lists generated files and the schema elements they depend on
+type Manifest struct {
+	Entries []Entry
+}
+
+// Entry is a generated file and its dependencies. A dependency is a type name
+// such as "Issue", which covers every change to the type, or a field such as
+// "Query.repository", which covers changes to that field and its arguments.
+type Entry struct {
+	File         string
+	Dependencies []string
+}
+
+// ParseManifest reads a manifest with one generated file per line, followed
+// by a colon and its whitespace-separated dependencies:
+//
+//	# Lines starting with # are comments
+//	internal/github/issues_gen.go: Issue IssueConnection Query.repository
+//	internal/github/mutations_gen.go: Mutation.createIssue CreateIssueInput
+func ParseManifest(r io.Reader) (*Manifest, error) {
+	m := &Manifest{}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		file, deps, ok := strings.Cut(text, ":")
+		file = strings.TrimSpace(file)
+		if !ok || file == "" {
+			return nil, fmt.Errorf("manifest line %d: expected \"file: dependencies\"", line)
+		}
+		entry := Entry{File: file, Dependencies: strings.Fields(deps)}
+		if len(entry.Dependencies) == 0 {
+			return nil, fmt.Errorf("manifest line %d: %s has no dependencies", line, file)
+		}
+		for _, dep := range entry.Dependencies {
+			if typeName, field, _ := strings.Cut(dep, "."); typeName == "" || strings.Contains(field, ".") {
+				return nil, fmt.Errorf("manifest line %d: invalid dependency %q", line, dep)
+			}
+		}
+		m.Entries = append(m.Entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return m, nil
+}
+
+// WriteTo writes the manifest in the format ParseManifest reads, one entry
+// per line
+func (m *Manifest) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	for _, entry := range m.Entries {
+		fmt.Fprintf(&b, "%s: %s\n", entry.File, strings.Join(entry.Dependencies, " "))
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ReadManifest reads a manifest file
+func ReadManifest(path string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest: %w", err)
+	}
+	defer f.Close()
+	return ParseManifest(f)
+}
@@ -0,0 +1,59 @@
+package codegen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// initialisms are the words Go names spell in capitals, after the list of
+// golint
+var initialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IDS": true,
+	"IP": true, "JSON": true, "QPS": true, "RAM": true, "RPC": true, "SHA": true,
+	"SLA": true, "SMTP": true, "SQL": true, "SSH": true, "SSO": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "URI": true,
+	"URL": true, "UTF8": true, "UUID": true, "VM": true, "XML": true, "XSRF": true,
+	"XSS": true,
+}
+
+// goName converts a GraphQL name into an exported Go identifier: camelCase
+// and SCREAMING_CASE words are capitalized and initialisms spelled in
+// capitals, so "databaseId" becomes "DatabaseID" and "MERGE_COMMIT" becomes
+// "MergeCommit". "__typename" becomes "Typename".
+func goName(name string) string {
+	var b strings.Builder
+	for _, word := range splitWords(name) {
+		upper := strings.ToUpper(word)
+		switch {
+		case upper == "IDS":
+			b.WriteString("IDs")
+		case initialisms[upper]:
+			b.WriteString(upper)
+		default:
+			b.WriteString(upper[:1])
+			b.WriteString(strings.ToLower(word[1:]))
+		}
+	}
+	return b.String()
+}
+
+// splitWords splits a GraphQL name at underscores and where a lowercase
+// letter or digit is followed by an uppercase one
+func splitWords(name string) []string {
+	var words []string
+	for _, part := range strings.Split(name, "_") {
+		start := 0
+		for i := 1; i < len(part); i++ {
+			prev, r := rune(part[i-1]), rune(part[i])
+			if unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)) {
+				words = append(words, part[start:i])
+				start = i
+			}
+		}
+		if start < len(part) {
+			words = append(words, part[start:])
+		}
+	}
+	return words
+}
@@ -0,0 +1,295 @@
+package codegen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+// Change is a difference between two versions of a schema element
+type Change struct {
+	Path    string   `json:"path"` // Type, Type.member, or Type.field.argument
+	Message string   `json:"message"`
+	Files   []string `json:"files,omitempty"` // Generated files affected, only set in Plan.ManualAttention
+
+	typeName string
+	member   string
+	breaking bool
+}
+
+// FilePlan is a generated file that needs regeneration and the changes to
+// its dependencies
+type FilePlan struct {
+	File    string   `json:"file"`
+	Changes []Change `json:"changes"`
+}
+
+// Plan is the result of comparing two schemas against a manifest
+type Plan struct {
+	Regenerate      []FilePlan `json:"regenerate"`
+	Unchanged       []string   `json:"unchanged"`
+	ManualAttention []Change   `json:"manualAttention"` // Breaking input changes; regeneration alone does not fix callers
+}
+
+// NewPlan compares the schema the code was generated against (oldSchema) with
+// the updated schema and returns the files in m that need regeneration. Every
+// dependency must exist in at least one of the schemas.
+func NewPlan(oldSchema, newSchema *schema.Schema, m *Manifest) (*Plan, error) {
+	oldTypes, err := typesByName(oldSchema)
+	if err != nil {
+		return nil, err
+	}
+	newTypes, err := typesByName(newSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string][]Change)
+	plan := &Plan{Regenerate: []FilePlan{}, Unchanged: []string{}, ManualAttention: []Change{}}
+	manual := make(map[string]int)
+	for _, entry := range m.Entries {
+		var fileChanges []Change
+		seen := make(map[string]bool)
+		for _, dep := range entry.Dependencies {
+			typeName, member, _ := strings.Cut(dep, ".")
+			oldType, newType := oldTypes[typeName], newTypes[typeName]
+			if oldType == nil && newType == nil {
+				return nil, fmt.Errorf("%s: unknown type %q", entry.File, typeName)
+			}
+			if member != "" && !hasMember(oldType, member) && !hasMember(newType, member) {
+				return nil, fmt.Errorf("%s: %q has no member %q", entry.File, typeName, member)
+			}
+
+			typeChanges, ok := changes[typeName]
+			if !ok {
+				typeChanges = diffType(typeName, oldType, newType)
+				changes[typeName] = typeChanges
+			}
+			for _, c := range typeChanges {
+				if member != "" && c.member != member {
+					continue
+				}
+				key := c.Path + "\x00" + c.Message
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				fileChanges = append(fileChanges, c)
+
+				if !c.breaking {
+					continue
+				}
+				if i, ok := manual[key]; ok {
+					plan.ManualAttention[i].Files = append(plan.ManualAttention[i].Files, entry.File)
+					continue
+				}
+				manual[key] = len(plan.ManualAttention)
+				c.Files = []string{entry.File}
+				plan.ManualAttention = append(plan.ManualAttention, c)
+			}
+		}
+		if len(fileChanges) == 0 {
+			plan.Unchanged = append(plan.Unchanged, entry.File)
+		} else {
+			plan.Regenerate = append(plan.Regenerate, FilePlan{File: entry.File, Changes: fileChanges})
+		}
+	}
+	return plan, nil
+}
+
+// typesByName returns the raw introspection entries of a schema's types
+func typesByName(s *schema.Schema) (map[string]map[string]interface{}, error) {
+	result, err := s.Query(`[.data.__schema.types[]]`, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read types: %w", err)
+	}
+	list, _ := result.([]interface{})
+	types := make(map[string]map[string]interface{}, len(list))
+	for _, item := range list {
+		if t, ok := item.(map[string]interface{}); ok {
+			if name, ok := t["name"].(string); ok {
+				types[name] = t
+			}
+		}
+	}
+	return types, nil
+}
+
+// memberLists are the keys of a type entry holding named members
+var memberLists = []string{"fields", "inputFields", "enumValues"}
+
+func hasMember(t map[string]interface{}, name string) bool {
+	for _, key := range memberLists {
+		if namedEntry(t, key, name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// diffType lists the changes between two versions of a type; either may be nil
+func diffType(name string, oldType, newType map[string]interface{}) []Change {
+	switch {
+	case oldType == nil:
+		return []Change{{Path: name, Message: "type added", typeName: name}}
+	case newType == nil:
+		return []Change{{Path: name, Message: "type removed", typeName: name, breaking: true}}
+	}
+
+	var changes []Change
+	add := func(member, path, message string, breaking bool) {
+		changes = append(changes, Change{Path: path, Message: message, typeName: name, member: member, breaking: breaking})
+	}
+
+	oldKind, newKind := oldType["kind"], newType["kind"]
+	if oldKind != newKind {
+		add("", name, fmt.Sprintf("kind changed from %v to %v", oldKind, newKind), true)
+		return changes
+	}
+	if oldType["description"] != newType["description"] {
+		add("", name, "description changed", false)
+	}
+	for _, key := range []string{"interfaces", "possibleTypes"} {
+		if removed, added := diffNames(entryNames(oldType, key), entryNames(newType, key)); len(removed)+len(added) > 0 {
+			add("", name, fmt.Sprintf("%s changed (added: %v, removed: %v)", key, added, removed), false)
+		}
+	}
+
+	input := newKind == "INPUT_OBJECT"
+	for _, key := range memberLists {
+		eachMember(oldType, newType, key, func(member string, oldEntry, newEntry map[string]interface{}) {
+			path := name + "." + member
+			switch {
+			case newEntry == nil:
+				add(member, path, singular(key)+" removed", input || key == "enumValues")
+			case oldEntry == nil:
+				if input && isRequired(newEntry) {
+					add(member, path, "required input field added", true)
+				} else {
+					add(member, path, singular(key)+" added", false)
+				}
+			default:
+				diffMember(oldEntry, newEntry, input, func(message string, breaking bool) {
+					add(member, path, message, breaking)
+				})
+			}
+
+			// Arguments are inputs even on output types
+			if key != "fields" {
+				return
+			}
+			eachMember(oldEntry, newEntry, "args", func(arg string, oldArg, newArg map[string]interface{}) {
+				argPath := path + "." + arg
+				switch {
+				case oldEntry == nil || newEntry == nil:
+					// Covered by the field change
+				case newArg == nil:
+					add(member, argPath, "argument removed", true)
+				case oldArg == nil:
+					if isRequired(newArg) {
+						add(member, argPath, "required argument added", true)
+					} else {
+						add(member, argPath, "argument added", false)
+					}
+				default:
+					diffMember(oldArg, newArg, true, func(message string, breaking bool) {
+						add(member, argPath, message, breaking)
+					})
+				}
+			})
+		})
+	}
+	return changes
+}
+
+// diffMember compares two versions of a field, input field, argument, or enum value
+func diffMember(oldEntry, newEntry map[string]interface{}, input bool, report func(message string, breaking bool)) {
+	oldRef, newRef := schema.TypeRefFromMap(oldEntry["type"]), schema.TypeRefFromMap(newEntry["type"])
+	if oldRef.String() != newRef.String() {
+		report(fmt.Sprintf("type changed from %s to %s", oldRef, newRef), input)
+	}
+	if !reflect.DeepEqual(oldEntry["defaultValue"], newEntry["defaultValue"]) {
+		report(fmt.Sprintf("default value changed from %v to %v", oldEntry["defaultValue"], newEntry["defaultValue"]), false)
+	}
+	if oldEntry["description"] != newEntry["description"] {
+		report("description changed", false)
+	}
+	if oldEntry["isDeprecated"] != true && newEntry["isDeprecated"] == true {
+		report(fmt.Sprintf("deprecated: %v", newEntry["deprecationReason"]), false)
+	}
+}
+
+// eachMember calls fn for every member of key in either entry, in old order
+// followed by the members only present in newEntry
+func eachMember(oldEntry, newEntry map[string]interface{}, key string, fn func(name string, oldMember, newMember map[string]interface{})) {
+	oldNames := entryNames(oldEntry, key)
+	for _, name := range oldNames {
+		fn(name, namedEntry(oldEntry, key, name), namedEntry(newEntry, key, name))
+	}
+	for _, name := range entryNames(newEntry, key) {
+		if namedEntry(oldEntry, key, name) == nil {
+			fn(name, nil, namedEntry(newEntry, key, name))
+		}
+	}
+}
+
+func entryNames(entry map[string]interface{}, key string) []string {
+	list, _ := entry[key].([]interface{})
+	names := make([]string, 0, len(list))
+	for _, item := range list {
+		if m, ok := item.(map[string]interface{}); ok {
+			if name, ok := m["name"].(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+func namedEntry(entry map[string]interface{}, key, name string) map[string]interface{} {
+	list, _ := entry[key].([]interface{})
+	for _, item := range list {
+		if m, ok := item.(map[string]interface{}); ok && m["name"] == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// diffNames returns the names only in a and only in b
+func diffNames(a, b []string) (onlyA, onlyB []string) {
+	inA := make(map[string]bool, len(a))
+	for _, name := range a {
+		inA[name] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, name := range b {
+		inB[name] = true
+		if !inA[name] {
+			onlyB = append(onlyB, name)
+		}
+	}
+	for _, name := range a {
+		if !inB[name] {
+			onlyA = append(onlyA, name)
+		}
+	}
+	return onlyA, onlyB
+}
+
+// isRequired reports whether an input field or argument is non-null without a default
+func isRequired(entry map[string]interface{}) bool {
+	return schema.UnwrapTypeRef(schema.TypeRefFromMap(entry["type"])).IsNonNull() && entry["defaultValue"] == nil
+}
+
+func singular(key string) string {
+	switch key {
+	case "inputFields":
+		return "input field"
+	case "enumValues":
+		return "enum value"
+	}
+	return "field"
+}
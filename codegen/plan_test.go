@@ -0,0 +1,170 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/apstndb/go-yamlformat"
+)
+
+// loadSchemas returns the sample schema and a copy modified by update
+func loadSchemas(t *testing.T, update func(types map[string]map[string]interface{})) (*schema.Schema, *schema.Schema) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "schema", "testdata", "synthetic.json"))
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	oldSchema, err := schema.NewWithData(data)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := yamlformat.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Failed to decode fixture: %v", err)
+	}
+	types := make(map[string]map[string]interface{})
+	for _, item := range doc["data"].(map[string]interface{})["__schema"].(map[string]interface{})["types"].([]interface{}) {
+		entry := item.(map[string]interface{})
+		types[entry["name"].(string)] = entry
+	}
+	update(types)
+	modified, err := yamlformat.MarshalJSON(doc)
+	if err != nil {
+		t.Fatalf("Failed to encode modified fixture: %v", err)
+	}
+	newSchema, err := schema.NewWithData(modified)
+	if err != nil {
+		t.Fatalf("Failed to load modified schema: %v", err)
+	}
+	return oldSchema, newSchema
+}
+
+func scalar(name string) map[string]interface{} {
+	return map[string]interface{}{"kind": "SCALAR", "name": name, "ofType": nil}
+}
+
+func nonNull(ref map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"kind": "NON_NULL", "name": nil, "ofType": ref}
+}
+
+func removeNamed(entry map[string]interface{}, key, name string) {
+	list := entry[key].([]interface{})
+	for i, item := range list {
+		if item.(map[string]interface{})["name"] == name {
+			entry[key] = append(list[:i:i], list[i+1:]...)
+			return
+		}
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	oldSchema, newSchema := loadSchemas(t, func(types map[string]map[string]interface{}) {
+		input := types["CreateIssueInput"]
+		removeNamed(input, "inputFields", "body")
+		input["inputFields"] = append(input["inputFields"].([]interface{}), map[string]interface{}{
+			"name": "assigneeId", "description": "The assignee.", "type": nonNull(scalar("ID")), "defaultValue": nil,
+		})
+		removeNamed(types["IssueState"], "enumValues", "LOCKED")
+		types["PageInfo"]["fields"] = append(types["PageInfo"]["fields"].([]interface{}), map[string]interface{}{
+			"name": "hasPreviousPage", "description": "", "args": []interface{}{}, "type": nonNull(scalar("Boolean")), "isDeprecated": false, "deprecationReason": nil,
+		})
+		repository := types["Query"]["fields"].([]interface{})[0].(map[string]interface{})
+		repository["args"] = append(repository["args"].([]interface{}), map[string]interface{}{
+			"name": "ref", "description": "", "type": scalar("String"), "defaultValue": nil,
+		})
+	})
+
+	m, err := ParseManifest(strings.NewReader(`# generated clients
+gen/mutations.go: Mutation.createIssue CreateIssueInput
+gen/issues.go: Query.repository IssueState PageInfo
+
+gen/users.go: User
+gen/stars.go: Mutation.addStar
+`))
+	if err != nil {
+		t.Fatalf("ParseManifest failed: %v", err)
+	}
+
+	plan, err := NewPlan(oldSchema, newSchema, m)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	got := make(map[string][]string)
+	for _, f := range plan.Regenerate {
+		for _, c := range f.Changes {
+			got[f.File] = append(got[f.File], c.Path+": "+c.Message)
+		}
+	}
+	want := map[string][]string{
+		"gen/mutations.go": {"CreateIssueInput.body: input field removed", "CreateIssueInput.assigneeId: required input field added"},
+		"gen/issues.go":    {"Query.repository.ref: argument added", "IssueState.LOCKED: enum value removed", "PageInfo.hasPreviousPage: field added"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Regenerate = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(plan.Unchanged, []string{"gen/users.go", "gen/stars.go"}) {
+		t.Errorf("Unchanged = %v", plan.Unchanged)
+	}
+
+	var manual []string
+	for _, c := range plan.ManualAttention {
+		manual = append(manual, c.Path+" "+strings.Join(c.Files, ","))
+	}
+	wantManual := []string{"CreateIssueInput.body gen/mutations.go", "CreateIssueInput.assigneeId gen/mutations.go", "IssueState.LOCKED gen/issues.go"}
+	if !reflect.DeepEqual(manual, wantManual) {
+		t.Errorf("ManualAttention = %v, want %v", manual, wantManual)
+	}
+}
+
+func TestNewPlanUnknownDependency(t *testing.T) {
+	oldSchema, newSchema := loadSchemas(t, func(map[string]map[string]interface{}) {})
+
+	tests := []struct {
+		manifest string
+		wantErr  string
+	}{
+		{"a.go: Robot", `unknown type "Robot"`},
+		{"a.go: Issue.nope", `"Issue" has no member "nope"`},
+	}
+	for _, tt := range tests {
+		m, err := ParseManifest(strings.NewReader(tt.manifest))
+		if err != nil {
+			t.Fatalf("ParseManifest failed: %v", err)
+		}
+		if _, err := NewPlan(oldSchema, newSchema, m); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("NewPlan(%q) error = %v, want %q", tt.manifest, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseManifestErrors(t *testing.T) {
+	for _, input := range []string{"no colon here", "a.go:", ": Issue", "a.go: Issue.title.extra"} {
+		if _, err := ParseManifest(strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), "manifest line 1") {
+			t.Errorf("ParseManifest(%q) error = %v, want a line 1 error", input, err)
+		}
+	}
+}
+
+func TestManifestWriteTo(t *testing.T) {
+	m := &Manifest{Entries: []Entry{
+		{File: "gen/issues.go", Dependencies: []string{"Issue", "Query.repository"}},
+		{File: "gen/users.go", Dependencies: []string{"User"}},
+	}}
+	var b strings.Builder
+	if _, err := m.WriteTo(&b); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if want := "gen/issues.go: Issue Query.repository\ngen/users.go: User\n"; b.String() != want {
+		t.Errorf("WriteTo wrote %q, want %q", b.String(), want)
+	}
+	parsed, err := ParseManifest(strings.NewReader(b.String()))
+	if err != nil || !reflect.DeepEqual(parsed, m) {
+		t.Errorf("ParseManifest = %+v, %v; want %+v", parsed, err, m)
+	}
+}
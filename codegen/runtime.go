@@ -0,0 +1,130 @@
+package codegen
+
+// clientRuntime is the body of the generated client.go, after the package
+// clause
+const clientRuntime = `import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+// DefaultEndpoint is the GraphQL endpoint of github.com
+const DefaultEndpoint = "https://api.github.com/graphql"
+
+// Client executes GraphQL operations. The zero value sends them to
+// DefaultEndpoint, authenticated with the token schema.ResolveCredential finds
+// in GH_TOKEN, GITHUB_TOKEN, or the login of gh. With another Endpoint the
+// token is the one schema.ResolveCredentialForHost finds for its host. A
+// Client must not be copied after first use.
+type Client struct {
+	// Endpoint is the GraphQL endpoint; empty means DefaultEndpoint
+	Endpoint string
+	// HTTPClient sends the requests; nil means http.DefaultClient, which
+	// honors HTTPS_PROXY and NO_PROXY
+	HTTPClient *http.Client
+	// TokenSource returns the token for each request; nil means the token
+	// schema.ResolveCredentialForHost finds for the host of Endpoint, looked
+	// up on first use
+	TokenSource schema.TokenSource
+
+	once  sync.Once
+	token string
+}
+
+// Error is an error in a GraphQL response
+type Error struct {
+	Message string        ` + "`json:\"message\"`" + `
+	Type    string        ` + "`json:\"type,omitempty\"`" + ` // Such as NOT_FOUND or FORBIDDEN
+	Path    []interface{} ` + "`json:\"path,omitempty\"`" + `
+}
+
+// Errors are the errors in a GraphQL response
+type Errors []Error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return "graphql: " + strings.Join(messages, "; ")
+}
+
+// Execute sends query with variables, which may be nil, and decodes the data
+// of the response into data. The errors in a response are returned as Errors
+// after decoding any partial data.
+func (c *Client) Execute(ctx context.Context, query, operationName string, variables, data interface{}) error {
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	token, err := c.resolveToken(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to get token: %w", err)
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"query":         query,
+		"operationName": operationName,
+		"variables":     variables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "bearer "+token)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized && token == "":
+		return fmt.Errorf("%s returned HTTP %d; no GitHub token found. Set GH_TOKEN or GITHUB_TOKEN, or run 'gh auth login'", endpoint, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%s returned HTTP %d", endpoint, resp.StatusCode)
+	}
+
+	var result struct {
+		Data   json.RawMessage ` + "`json:\"data\"`" + `
+		Errors Errors          ` + "`json:\"errors\"`" + `
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(result.Data) > 0 && string(result.Data) != "null" {
+		if err := json.Unmarshal(result.Data, data); err != nil {
+			return fmt.Errorf("failed to decode data: %w", err)
+		}
+	}
+	if len(result.Errors) > 0 {
+		return result.Errors
+	}
+	return nil
+}
+
+func (c *Client) resolveToken(ctx context.Context, endpoint string) (string, error) {
+	if c.TokenSource != nil {
+		return c.TokenSource(ctx)
+	}
+	c.once.Do(func() {
+		c.token = schema.ResolveCredentialForHost(schema.ExplicitToken, schema.EndpointHost(endpoint)).Token
+	})
+	return c.token, nil
+}
+`
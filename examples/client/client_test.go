@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/apstndb/github-schema-go/typename"
+)
+
+// request is a GraphQL request as the server receives it
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// newServer answers each request with the next of responses
+func newServer(t *testing.T, responses ...string) (*Client, *[]request) {
+	t.Helper()
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "bearer test-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		requests = append(requests, req)
+		if len(requests) > len(responses) {
+			t.Errorf("Unexpected request %d", len(requests))
+			return
+		}
+		io.WriteString(w, responses[len(requests)-1])
+	}))
+	t.Cleanup(server.Close)
+	client := &Client{
+		Endpoint:    server.URL,
+		TokenSource: func(context.Context) (string, error) { return "test-token", nil },
+	}
+	return client, &requests
+}
+
+func TestRepositoryIssuesAll(t *testing.T) {
+	client, requests := newServer(t,
+		`{"data": {"repository": {"nameWithOwner": "cli/cli", "issues": {"totalCount": 3,
+			"pageInfo": {"hasNextPage": true, "endCursor": "c1"},
+			"nodes": [{"number": 1, "title": "One", "state": "OPEN", "createdAt": "2024-01-02T03:04:05Z", "author": {"login": "octocat"}}, null]}}}}`,
+		`{"data": {"repository": {"nameWithOwner": "cli/cli", "issues": {"totalCount": 3,
+			"pageInfo": {"hasNextPage": false, "endCursor": "c2"},
+			"nodes": [{"number": 3, "title": "Three", "state": "CLOSED", "createdAt": "2024-01-02T03:04:05Z", "author": null}]}}}}`,
+	)
+	nodes, err := client.RepositoryIssuesAll(context.Background(), RepositoryIssuesVariables{
+		Owner: "cli", Name: "cli", States: []IssueState{IssueStateOpen, IssueStateClosed},
+	})
+	if err != nil {
+		t.Fatalf("RepositoryIssuesAll failed: %v", err)
+	}
+	if len(nodes) != 3 || nodes[0].Author.Login != "octocat" || nodes[1] != nil || nodes[2].State != IssueStateClosed {
+		t.Errorf("Unexpected nodes %+v", nodes)
+	}
+	if nodes[0].CreatedAt.Year() != 2024 {
+		t.Errorf("CreatedAt = %s", nodes[0].CreatedAt)
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("Made %d requests, want 2", len(*requests))
+	}
+	first, second := (*requests)[0], (*requests)[1]
+	if first.Query != RepositoryIssuesDocument || first.OperationName != "RepositoryIssues" {
+		t.Errorf("Unexpected request %+v", first)
+	}
+	if _, ok := first.Variables["after"]; ok {
+		t.Errorf("First page sent after: %v", first.Variables)
+	}
+	if _, ok := first.Variables["first"]; ok {
+		t.Errorf("Unset variable with a default was sent: %v", first.Variables)
+	}
+	if second.Variables["after"] != "c1" || second.Variables["owner"] != "cli" {
+		t.Errorf("Second page variables = %v", second.Variables)
+	}
+}
+
+func TestIssueOrPullRequest(t *testing.T) {
+	client, _ := newServer(t,
+		`{"data": {"repository": {"issueOrPullRequest": {"__typename": "PullRequest", "number": 7, "title": "Fix", "merged": true}}}}`,
+	)
+	resp, err := client.IssueOrPullRequest(context.Background(), IssueOrPullRequestVariables{Owner: "cli", Name: "cli", Number: 7})
+	if err != nil {
+		t.Fatalf("IssueOrPullRequest failed: %v", err)
+	}
+	item := resp.Repository.IssueOrPullRequest
+	if item.Typename != "PullRequest" || *item.Number != 7 || !*item.Merged || item.State != nil {
+		t.Errorf("Unexpected item %+v", item)
+	}
+}
+
+func TestRegisterTypes(t *testing.T) {
+	s, err := schema.New()
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	r := typename.NewRegistry(s)
+	RegisterTypes(r)
+
+	v, err := r.Decode("IssueOrPullRequest", []byte(`{"__typename": "PullRequest", "number": 7, "title": "Fix", "merged": true}`))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if pr, ok := v.(*PullRequest); !ok || pr.Number != 7 || !pr.Merged {
+		t.Errorf("Expected a merged *PullRequest, got %#v", v)
+	}
+	v, err = r.Decode("IssueOrPullRequest", []byte(`{"__typename": "Issue", "number": 8, "title": "Bug", "state": "OPEN", "createdAt": "2024-01-02T03:04:05Z", "author": {"login": "octocat"}}`))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if issue, ok := v.(*Issue); !ok || issue.State != IssueStateOpen || issue.Author.Login != "octocat" {
+		t.Errorf("Expected an open *Issue, got %#v", v)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	client, _ := newServer(t,
+		`{"data": {"repository": null}, "errors": [{"type": "NOT_FOUND", "path": ["repository"], "message": "Could not resolve to a Repository with the name 'cli/nope'."}]}`,
+	)
+	resp, err := client.IssueOrPullRequest(context.Background(), IssueOrPullRequestVariables{Owner: "cli", Name: "nope", Number: 1})
+	var gqlErrors Errors
+	if !errors.As(err, &gqlErrors) || len(gqlErrors) != 1 || gqlErrors[0].Type != "NOT_FOUND" {
+		t.Fatalf("Expected a NOT_FOUND error, got %v", err)
+	}
+	if resp == nil || resp.Repository != nil {
+		t.Errorf("Expected the partial data, got %+v", resp)
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	client := &Client{Endpoint: server.URL, TokenSource: func(context.Context) (string, error) { return "test-token", nil }}
+	if _, err := client.AddComment(context.Background(), AddCommentVariables{}); err == nil {
+		t.Error("Expected an error for HTTP 502")
+	}
+}
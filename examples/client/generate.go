@@ -0,0 +1,5 @@
+// Package client is a GitHub GraphQL client generated by github-schema codegen
+// client from the operations in the queries directory
+package client
+
+//go:generate go run ../../cmd/github-schema codegen client --operations ./queries/ -o .
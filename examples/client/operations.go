@@ -0,0 +1,362 @@
+// Code generated by github-schema codegen client. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/apstndb/github-schema-go/typename"
+)
+
+// RepositoryIssuesDocument is the query sent by Client.RepositoryIssues
+const RepositoryIssuesDocument = `query RepositoryIssues($owner: String!, $name: String!, $states: [IssueState!], $first: Int = 50, $after: String) {
+  repository(owner: $owner, name: $name) {
+    nameWithOwner
+    issues(states: $states, first: $first, after: $after) {
+      totalCount
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+      nodes {
+        ...IssueSummary
+      }
+    }
+  }
+}
+
+fragment IssueSummary on Issue {
+  number
+  title
+  state
+  createdAt
+  author {
+    login
+  }
+}`
+
+// RepositoryIssuesVariables are the variables of the RepositoryIssues query
+type RepositoryIssuesVariables struct {
+	Owner  string       `json:"owner"`
+	Name   string       `json:"name"`
+	States []IssueState `json:"states,omitempty"`
+	First  *int         `json:"first,omitempty"`
+	After  *string      `json:"after,omitempty"`
+}
+
+// RepositoryIssuesResponse is the data of a response to the RepositoryIssues
+// query
+type RepositoryIssuesResponse struct {
+	// Lookup a given repository by the owner and repository name.
+	Repository *RepositoryIssuesRepository `json:"repository"`
+}
+
+// RepositoryIssuesRepository is the Repository selected at repository
+type RepositoryIssuesRepository struct {
+	// NameWithOwner is the repository's name with owner.
+	NameWithOwner string `json:"nameWithOwner"`
+	// Issues is a list of issues that have been opened in the repository.
+	Issues RepositoryIssuesRepositoryIssues `json:"issues"`
+}
+
+// RepositoryIssuesRepositoryIssues is the IssueConnection selected at
+// repository.issues
+type RepositoryIssuesRepositoryIssues struct {
+	// TotalCount identifies the total count of items in the connection.
+	TotalCount int `json:"totalCount"`
+	// Information to aid in pagination.
+	PageInfo RepositoryIssuesRepositoryIssuesPageInfo `json:"pageInfo"`
+	// Nodes is a list of nodes.
+	Nodes []*RepositoryIssuesRepositoryIssuesNodes `json:"nodes"`
+}
+
+// RepositoryIssuesRepositoryIssuesPageInfo is the PageInfo selected at
+// repository.issues.pageInfo
+type RepositoryIssuesRepositoryIssuesPageInfo struct {
+	// When paginating forwards, are there more items?
+	HasNextPage bool `json:"hasNextPage"`
+	// When paginating forwards, the cursor to continue.
+	EndCursor *string `json:"endCursor"`
+}
+
+// RepositoryIssuesRepositoryIssuesNodes is the Issue selected at
+// repository.issues.nodes
+type RepositoryIssuesRepositoryIssuesNodes struct {
+	// Number identifies the issue number.
+	Number int `json:"number"`
+	// Title identifies the issue title.
+	Title string `json:"title"`
+	// State identifies the state of the issue.
+	State IssueState `json:"state"`
+	// CreatedAt identifies the date and time when the object was created.
+	CreatedAt time.Time `json:"createdAt"`
+	// Author is the actor who authored the comment.
+	Author *RepositoryIssuesRepositoryIssuesNodesAuthor `json:"author"`
+}
+
+// RepositoryIssuesRepositoryIssuesNodesAuthor is the Actor selected at
+// repository.issues.nodes.author
+type RepositoryIssuesRepositoryIssuesNodesAuthor struct {
+	// Login is the username of the actor.
+	Login string `json:"login"`
+}
+
+// RepositoryIssues executes the RepositoryIssues query. The response is
+// returned even when err is not nil: it holds the partial data of a response
+// with Errors.
+func (c *Client) RepositoryIssues(ctx context.Context, vars RepositoryIssuesVariables) (*RepositoryIssuesResponse, error) {
+	var data RepositoryIssuesResponse
+	err := c.Execute(ctx, RepositoryIssuesDocument, "RepositoryIssues", vars, &data)
+	return &data, err
+}
+
+// RepositoryIssuesAll executes the RepositoryIssues query for every page of
+// repository.issues, passing pageInfo.endCursor as $after, and returns the
+// nodes of all pages. Other variables are kept as given. On error, the nodes of
+// the pages before it are returned.
+func (c *Client) RepositoryIssuesAll(ctx context.Context, vars RepositoryIssuesVariables) ([]*RepositoryIssuesRepositoryIssuesNodes, error) {
+	var all []*RepositoryIssuesRepositoryIssuesNodes
+	for {
+		resp, err := c.RepositoryIssues(ctx, vars)
+		if err != nil {
+			return all, err
+		}
+		if resp.Repository == nil {
+			return all, nil
+		}
+		conn := resp.Repository.Issues
+		all = append(all, conn.Nodes...)
+		if !conn.PageInfo.HasNextPage || conn.PageInfo.EndCursor == nil {
+			return all, nil
+		}
+		vars.After = conn.PageInfo.EndCursor
+	}
+}
+
+// IssueOrPullRequestDocument is the query sent by Client.IssueOrPullRequest
+const IssueOrPullRequestDocument = `query IssueOrPullRequest($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    issueOrPullRequest(number: $number) {
+      __typename
+      ... on Issue {
+        ...IssueSummary
+        stateReason
+      }
+      ... on PullRequest {
+        number
+        title
+        merged
+      }
+    }
+  }
+}
+
+fragment IssueSummary on Issue {
+  number
+  title
+  state
+  createdAt
+  author {
+    login
+  }
+}`
+
+// IssueOrPullRequestVariables are the variables of the IssueOrPullRequest query
+type IssueOrPullRequestVariables struct {
+	Owner  string `json:"owner"`
+	Name   string `json:"name"`
+	Number int    `json:"number"`
+}
+
+// IssueOrPullRequestResponse is the data of a response to the
+// IssueOrPullRequest query
+type IssueOrPullRequestResponse struct {
+	// Lookup a given repository by the owner and repository name.
+	Repository *IssueOrPullRequestRepository `json:"repository"`
+}
+
+// IssueOrPullRequestRepository is the Repository selected at repository
+type IssueOrPullRequestRepository struct {
+	// IssueOrPullRequest returns a single issue-like object from the current
+	// repository by number.
+	IssueOrPullRequest *IssueOrPullRequestRepositoryIssueOrPullRequest `json:"issueOrPullRequest"`
+}
+
+// IssueOrPullRequestRepositoryIssueOrPullRequest is the IssueOrPullRequest
+// selected at repository.issueOrPullRequest
+type IssueOrPullRequestRepositoryIssueOrPullRequest struct {
+	// Typename is the name of the object type
+	Typename string `json:"__typename"`
+	// Number identifies the issue number.
+	Number *int `json:"number"`
+	// Title identifies the issue title.
+	Title *string `json:"title"`
+	// State identifies the state of the issue.
+	State *IssueState `json:"state"`
+	// CreatedAt identifies the date and time when the object was created.
+	CreatedAt *time.Time `json:"createdAt"`
+	// Author is the actor who authored the comment.
+	Author *IssueOrPullRequestRepositoryIssueOrPullRequestAuthor `json:"author"`
+	// StateReason identifies the reason for the issue state.
+	//
+	// Deprecated: The state reason for duplicate issue is now returned by default.
+	// Removal on 2025-10-01 UTC.
+	StateReason *IssueStateReason `json:"stateReason"`
+	// Merged reports whether or not the pull request was merged.
+	Merged *bool `json:"merged"`
+}
+
+// IssueOrPullRequestRepositoryIssueOrPullRequestAuthor is the Actor selected at
+// repository.issueOrPullRequest.author
+type IssueOrPullRequestRepositoryIssueOrPullRequestAuthor struct {
+	// Login is the username of the actor.
+	Login string `json:"login"`
+}
+
+// IssueOrPullRequest executes the IssueOrPullRequest query. The response is
+// returned even when err is not nil: it holds the partial data of a response
+// with Errors.
+func (c *Client) IssueOrPullRequest(ctx context.Context, vars IssueOrPullRequestVariables) (*IssueOrPullRequestResponse, error) {
+	var data IssueOrPullRequestResponse
+	err := c.Execute(ctx, IssueOrPullRequestDocument, "IssueOrPullRequest", vars, &data)
+	return &data, err
+}
+
+// AddCommentDocument is the mutation sent by Client.AddComment
+const AddCommentDocument = `mutation AddComment($input: AddCommentInput!) {
+  addComment(input: $input) {
+    commentEdge {
+      node {
+        id
+        url
+      }
+    }
+  }
+}`
+
+// AddCommentVariables are the variables of the AddComment mutation
+type AddCommentVariables struct {
+	Input AddCommentInput `json:"input"`
+}
+
+// AddCommentResponse is the data of a response to the AddComment mutation
+type AddCommentResponse struct {
+	// AddComment adds a comment to an Issue or Pull Request.
+	AddComment *AddCommentAddComment `json:"addComment"`
+}
+
+// AddCommentAddComment is the AddCommentPayload selected at addComment
+type AddCommentAddComment struct {
+	// CommentEdge is the edge from the subject's comment connection.
+	CommentEdge *AddCommentAddCommentCommentEdge `json:"commentEdge"`
+}
+
+// AddCommentAddCommentCommentEdge is the IssueCommentEdge selected at
+// addComment.commentEdge
+type AddCommentAddCommentCommentEdge struct {
+	// Node is the item at the end of the edge.
+	Node *AddCommentAddCommentCommentEdgeNode `json:"node"`
+}
+
+// AddCommentAddCommentCommentEdgeNode is the IssueComment selected at
+// addComment.commentEdge.node
+type AddCommentAddCommentCommentEdgeNode struct {
+	// ID is the Node ID of the IssueComment object
+	ID string `json:"id"`
+	// URL is the HTTP URL for this issue comment
+	URL string `json:"url"`
+}
+
+// AddComment executes the AddComment mutation. The response is returned even
+// when err is not nil: it holds the partial data of a response with Errors.
+func (c *Client) AddComment(ctx context.Context, vars AddCommentVariables) (*AddCommentResponse, error) {
+	var data AddCommentResponse
+	err := c.Execute(ctx, AddCommentDocument, "AddComment", vars, &data)
+	return &data, err
+}
+
+// Issue is the Issue selected by a type condition under an interface or union,
+// which a typename.Registry decodes by __typename after RegisterTypes
+type Issue struct {
+	// Typename is the name of the object type
+	Typename string `json:"__typename"`
+	// Number identifies the issue number.
+	Number int `json:"number"`
+	// Title identifies the issue title.
+	Title string `json:"title"`
+	// State identifies the state of the issue.
+	State IssueState `json:"state"`
+	// CreatedAt identifies the date and time when the object was created.
+	CreatedAt time.Time `json:"createdAt"`
+	// Author is the actor who authored the comment.
+	Author *IssueAuthor `json:"author"`
+	// StateReason identifies the reason for the issue state.
+	//
+	// Deprecated: The state reason for duplicate issue is now returned by default.
+	// Removal on 2025-10-01 UTC.
+	StateReason *IssueStateReason `json:"stateReason"`
+}
+
+// IssueAuthor is the Actor selected at Issue.author
+type IssueAuthor struct {
+	// Login is the username of the actor.
+	Login string `json:"login"`
+}
+
+// PullRequest is the PullRequest selected by a type condition under an
+// interface or union, which a typename.Registry decodes by __typename after
+// RegisterTypes
+type PullRequest struct {
+	// Typename is the name of the object type
+	Typename string `json:"__typename"`
+	// Number identifies the pull request number.
+	Number int `json:"number"`
+	// Title identifies the pull request title.
+	Title string `json:"title"`
+	// Merged reports whether or not the pull request was merged.
+	Merged bool `json:"merged"`
+}
+
+// RegisterTypes registers the structs of the object types selected by type
+// conditions under interfaces and unions with r, so that r.Decode returns them
+// for those selections. It panics if the schema of r lacks one of the types.
+func RegisterTypes(r *typename.Registry) {
+	r.MustRegister("Issue", Issue{})
+	r.MustRegister("PullRequest", PullRequest{})
+}
+
+// IssueState is the possible states of an issue.
+type IssueState string
+
+const (
+	// IssueStateOpen is an issue that is still open
+	IssueStateOpen IssueState = "OPEN"
+	// IssueStateClosed is an issue that has been closed
+	IssueStateClosed IssueState = "CLOSED"
+)
+
+// IssueStateReason is the possible state reasons of an issue.
+type IssueStateReason string
+
+const (
+	// IssueStateReasonReopened is an issue that has been reopened
+	IssueStateReasonReopened IssueStateReason = "REOPENED"
+	// IssueStateReasonNotPlanned is an issue that has been closed as not planned
+	IssueStateReasonNotPlanned IssueStateReason = "NOT_PLANNED"
+	// IssueStateReasonCompleted is an issue that has been closed as completed
+	IssueStateReasonCompleted IssueStateReason = "COMPLETED"
+	// IssueStateReasonDuplicate is an issue that has been closed as a duplicate.
+	IssueStateReasonDuplicate IssueStateReason = "DUPLICATE"
+)
+
+// Autogenerated input type of AddComment
+type AddCommentInput struct {
+	// ClientMutationID is a unique identifier for the client performing the
+	// mutation.
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	// SubjectID is the Node ID of the subject to modify.
+	SubjectID string `json:"subjectId"`
+	// Body is the contents of the comment.
+	Body string `json:"body"`
+}
@@ -0,0 +1,15 @@
+// Package feed publishes schema changes and scheduled removals as an Atom
+// feed (RFC 4287), so teams can follow them in feed readers and chat
+// integrations that poll feeds.
+//
+//	f, err := feed.Parse(existing) // Or feed.New("GitHub GraphQL schema") for a new one
+//	f.Add(feed.ChangeEntries(oldSchema.DiffWith(newSchema), oldSchema.Fingerprint(), newSchema.Fingerprint(), time.Now())...)
+//	removals, err := newSchema.ScheduledRemovals()
+//	f.Add(feed.RemovalEntries(removals, time.Now())...)
+//	f.Trim(50)
+//	data, err := f.Marshal()
+//
+// Entries have stable IDs: a diff between the same two schemas, or the
+// removals scheduled for the same day, update their entry instead of adding
+// another one, so a feed file can be regenerated on every schema download.
+package feed
@@ -0,0 +1,197 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+// Feed is an Atom feed. Entries are ordered newest first.
+type Feed struct {
+	XMLName xml.Name  `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string    `xml:"id"`
+	Title   string    `xml:"title"`
+	Updated time.Time `xml:"updated"`
+	Author  Person    `xml:"author"`
+	Links   []Link    `xml:"link,omitempty"`
+	Entries []Entry   `xml:"entry"`
+}
+
+// Person is the author of a feed
+type Person struct {
+	Name string `xml:"name"`
+}
+
+// Link is a link of a feed or entry, such as the URL a feed is served at
+// with Rel "self"
+type Link struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+// Category is a term an entry is filed under, such as "BREAKING" or
+// "removal"
+type Category struct {
+	Term string `xml:"term,attr"`
+}
+
+// Entry is an entry of a feed. Summary is plain text with one line per
+// change or removal.
+type Entry struct {
+	ID         string     `xml:"id"`
+	Title      string     `xml:"title"`
+	Updated    time.Time  `xml:"updated"`
+	Categories []Category `xml:"category,omitempty"`
+	Summary    string     `xml:"summary"`
+}
+
+// New creates an empty feed titled title. Its ID is derived from the title,
+// so feeds of different schemas, such as github.com and a GitHub Enterprise
+// Server instance, are told apart by readers.
+func New(title string) *Feed {
+	return &Feed{
+		ID:      "urn:github-schema:feed:" + schema.Fingerprint([]byte(title))[:16],
+		Title:   title,
+		Updated: time.Now().UTC().Truncate(time.Second),
+		Author:  Person{Name: "github-schema"},
+	}
+}
+
+// Parse decodes an Atom feed, such as one written by Marshal earlier
+func Parse(data []byte) (*Feed, error) {
+	var f Feed
+	if err := xml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+	return &f, nil
+}
+
+// Marshal encodes the feed as an indented XML document
+func (f *Feed) Marshal() ([]byte, error) {
+	data, err := xml.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// Add adds entries to the feed. An entry with the ID of an existing one
+// replaces it when its title or summary differ and is dropped otherwise, so
+// unchanged entries keep the time they were first published and readers do
+// not show them again.
+func (f *Feed) Add(entries ...Entry) {
+	for _, e := range entries {
+		e.Updated = e.Updated.UTC().Truncate(time.Second)
+		i := f.index(e.ID)
+		switch {
+		case i < 0:
+			f.Entries = append(f.Entries, e)
+		case f.Entries[i].Title != e.Title || f.Entries[i].Summary != e.Summary:
+			f.Entries[i] = e
+		}
+	}
+	sort.SliceStable(f.Entries, func(i, j int) bool {
+		return f.Entries[i].Updated.After(f.Entries[j].Updated)
+	})
+	for _, e := range f.Entries {
+		if e.Updated.After(f.Updated) {
+			f.Updated = e.Updated
+		}
+	}
+}
+
+func (f *Feed) index(id string) int {
+	for i, e := range f.Entries {
+		if e.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Trim keeps the n newest entries
+func (f *Feed) Trim(n int) {
+	if n >= 0 && len(f.Entries) > n {
+		f.Entries = f.Entries[:n]
+	}
+}
+
+// ChangeEntries returns an entry summarizing the changes between the schemas
+// with the fingerprints oldFingerprint and newFingerprint, published at, or
+// none when there are no changes. The entry lists every change with its
+// criticality and is filed under the criticalities present.
+func ChangeEntries(changes *schema.ChangeSet, oldFingerprint, newFingerprint string, at time.Time) []Entry {
+	if changes.Empty() {
+		return nil
+	}
+	var counts []string
+	var categories []Category
+	for _, level := range []schema.Criticality{schema.Breaking, schema.Dangerous, schema.NonBreaking} {
+		if n := len(changes.Filter(level)); n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, strings.ReplaceAll(strings.ToLower(string(level)), "_", "-")))
+			categories = append(categories, Category{Term: string(level)})
+		}
+	}
+	var summary strings.Builder
+	for _, c := range changes.Changes {
+		fmt.Fprintf(&summary, "%s: %s\n", c.Criticality, c.Message)
+	}
+	return []Entry{{
+		ID:         "urn:github-schema:changes:" + short(oldFingerprint) + ":" + short(newFingerprint),
+		Title:      fmt.Sprintf("Schema changed: %s", strings.Join(counts, ", ")),
+		Updated:    at,
+		Categories: categories,
+		Summary:    summary.String(),
+	}}
+}
+
+// RemovalEntries returns an entry per day with scheduled removals from now
+// on, published at now. The entry lists the members removed that day with
+// their deprecation reasons.
+func RemovalEntries(removals []schema.ScheduledRemoval, now time.Time) []Entry {
+	today := now.UTC().Truncate(24 * time.Hour)
+	byDay := make(map[string][]schema.ScheduledRemoval)
+	var days []string
+	for _, r := range removals {
+		if r.Date.Before(today) {
+			continue
+		}
+		day := r.Date.Format(time.DateOnly)
+		if byDay[day] == nil {
+			days = append(days, day)
+		}
+		byDay[day] = append(byDay[day], r)
+	}
+	sort.Strings(days)
+
+	var entries []Entry
+	for _, day := range days {
+		var summary strings.Builder
+		for _, r := range byDay[day] {
+			fmt.Fprintf(&summary, "%s: %s\n", r.Coordinate, r.Reason)
+		}
+		noun := "members"
+		if len(byDay[day]) == 1 {
+			noun = "member"
+		}
+		entries = append(entries, Entry{
+			ID:         "urn:github-schema:removals:" + day,
+			Title:      fmt.Sprintf("%d %s to be removed on %s", len(byDay[day]), noun, day),
+			Updated:    now,
+			Categories: []Category{{Term: "removal"}},
+			Summary:    summary.String(),
+		})
+	}
+	return entries
+}
+
+func short(fingerprint string) string {
+	if len(fingerprint) > 16 {
+		return fingerprint[:16]
+	}
+	return fingerprint
+}
@@ -0,0 +1,112 @@
+package feed
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+var (
+	day1 = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	day2 = day1.Add(24 * time.Hour)
+)
+
+var sampleChanges = &schema.ChangeSet{Changes: []schema.Change{
+	{Type: schema.FieldRemoved, Criticality: schema.Breaking, Path: "Repository.isTemplateRepo", Message: "Field 'isTemplateRepo' was removed from object type 'Repository'"},
+	{Type: schema.FieldAdded, Criticality: schema.NonBreaking, Path: "Repository.isPinned", Message: "Field 'isPinned' was added to object type 'Repository'"},
+	{Type: schema.TypeAdded, Criticality: schema.NonBreaking, Path: "Pin", Message: "Type 'Pin' was added"},
+}}
+
+func TestChangeEntries(t *testing.T) {
+	if entries := ChangeEntries(&schema.ChangeSet{}, "aaaa", "bbbb", day1); len(entries) != 0 {
+		t.Errorf("Expected no entries without changes, got %+v", entries)
+	}
+
+	entries := ChangeEntries(sampleChanges, strings.Repeat("a", 64), strings.Repeat("b", 64), day1)
+	if len(entries) != 1 {
+		t.Fatalf("Expected one entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if want := "urn:github-schema:changes:aaaaaaaaaaaaaaaa:bbbbbbbbbbbbbbbb"; e.ID != want {
+		t.Errorf("ID = %q, want %q", e.ID, want)
+	}
+	if want := "Schema changed: 1 breaking, 2 non-breaking"; e.Title != want {
+		t.Errorf("Title = %q, want %q", e.Title, want)
+	}
+	if len(e.Categories) != 2 || e.Categories[0].Term != "BREAKING" || e.Categories[1].Term != "NON_BREAKING" {
+		t.Errorf("Unexpected categories %+v", e.Categories)
+	}
+	if !strings.HasPrefix(e.Summary, "BREAKING: Field 'isTemplateRepo' was removed") || strings.Count(e.Summary, "\n") != 3 {
+		t.Errorf("Unexpected summary %q", e.Summary)
+	}
+}
+
+func TestRemovalEntries(t *testing.T) {
+	removals := []schema.ScheduledRemoval{
+		{Coordinate: "Repository.isTemplateRepo", Date: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), Reason: "past"},
+		{Coordinate: "Issue.body", Date: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), Reason: "Removal on 2026-10-01 UTC."},
+		{Coordinate: "Issue.title", Date: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), Reason: "Removal on 2027-01-01 UTC."},
+		{Coordinate: "User.bio", Date: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), Reason: "Removal on 2027-01-01 UTC."},
+	}
+	entries := RemovalEntries(removals, day1)
+	if len(entries) != 2 {
+		t.Fatalf("Expected entries for today and 2027-01-01, got %+v", entries)
+	}
+	if entries[0].ID != "urn:github-schema:removals:2026-10-01" || entries[0].Title != "1 member to be removed on 2026-10-01" {
+		t.Errorf("Unexpected first entry %+v", entries[0])
+	}
+	if entries[1].Title != "2 members to be removed on 2027-01-01" || entries[1].Summary != "Issue.title: Removal on 2027-01-01 UTC.\nUser.bio: Removal on 2027-01-01 UTC.\n" {
+		t.Errorf("Unexpected second entry %+v", entries[1])
+	}
+}
+
+func TestAdd(t *testing.T) {
+	f := New("GitHub GraphQL schema")
+	f.Add(Entry{ID: "a", Title: "A", Summary: "first", Updated: day1})
+	f.Add(Entry{ID: "a", Title: "A", Summary: "first", Updated: day2}, Entry{ID: "b", Title: "B", Updated: day2})
+	if len(f.Entries) != 2 || f.Entries[0].ID != "b" || !f.Entries[1].Updated.Equal(day1) {
+		t.Errorf("Unchanged entries should keep their time, got %+v", f.Entries)
+	}
+	if f.Updated.Before(day2) {
+		t.Errorf("Updated = %v, want at least %v", f.Updated, day2)
+	}
+
+	f.Add(Entry{ID: "a", Title: "A", Summary: "second", Updated: day2.Add(time.Hour)})
+	if len(f.Entries) != 2 || f.Entries[0].ID != "a" || f.Entries[0].Summary != "second" {
+		t.Errorf("Changed entries should be replaced, got %+v", f.Entries)
+	}
+
+	f.Trim(1)
+	if len(f.Entries) != 1 || f.Entries[0].ID != "a" {
+		t.Errorf("Trim(1) kept %+v", f.Entries)
+	}
+}
+
+func TestMarshalParse(t *testing.T) {
+	f := New("GitHub GraphQL schema")
+	f.Links = []Link{{Href: "https://example.com/schema.atom", Rel: "self"}}
+	f.Add(ChangeEntries(sampleChanges, "old", "new", day1)...)
+	data, err := f.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`<?xml`, `<feed xmlns="http://www.w3.org/2005/Atom">`, `<category term="BREAKING"></category>`, `<link href="https://example.com/schema.atom" rel="self"></link>`, "<updated>2026-10-01T12:00:00Z</updated>"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Feed does not contain %q:\n%s", want, data)
+		}
+	}
+
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.ID != f.ID || len(parsed.Entries) != 1 || parsed.Entries[0].Summary != f.Entries[0].Summary || !parsed.Entries[0].Updated.Equal(day1) {
+		t.Errorf("Round trip lost data: %+v", parsed)
+	}
+
+	if _, err := Parse([]byte(`<rss version="2.0"></rss>`)); err == nil {
+		t.Error("Expected an error for an RSS document")
+	}
+}
@@ -8,7 +8,9 @@ require (
 	github.com/apstndb/go-jq-yamlformat v0.0.0-20250624104049-8065cb9ec8ea
 	github.com/apstndb/go-yamlformat v0.0.0-20250624080809-593ba2da569d
 	github.com/itchyny/gojq v0.12.16
+	github.com/klauspost/compress v1.18.0
 	github.com/spf13/cobra v1.9.1
+	github.com/spf13/pflag v1.0.6
 )
 
 require (
@@ -16,7 +18,6 @@ require (
 	github.com/goccy/go-yaml v1.18.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/itchyny/timefmt-go v0.1.6 // indirect
-	github.com/spf13/pflag v1.0.6 // indirect
 	golang.org/x/net v0.32.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
 )
@@ -0,0 +1,266 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position is a location in a GraphQL document. Lines and columns start at 1;
+// columns count bytes.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Document is a parsed executable GraphQL document
+type Document struct {
+	Definitions []Definition
+}
+
+// Definition is an *OperationDefinition or a *FragmentDefinition
+type Definition interface {
+	definitionNode()
+}
+
+// OperationType is the kind of an operation
+type OperationType string
+
+const (
+	Query        OperationType = "query"
+	Mutation     OperationType = "mutation"
+	Subscription OperationType = "subscription"
+)
+
+// OperationDefinition is a query, mutation, or subscription.
+// Name is empty for anonymous operations.
+type OperationDefinition struct {
+	Operation           OperationType
+	Name                string
+	VariableDefinitions []*VariableDefinition
+	Directives          []*Directive
+	SelectionSet        SelectionSet
+	Pos                 Position
+}
+
+// FragmentDefinition is a named fragment
+type FragmentDefinition struct {
+	Name             string
+	TypeCondition    string
+	Directives       []*Directive
+	SelectionSet     SelectionSet
+	Pos              Position
+	TypeConditionPos Position
+}
+
+func (*OperationDefinition) definitionNode() {}
+func (*FragmentDefinition) definitionNode()  {}
+
+// VariableDefinition declares an operation variable. Name excludes the leading "$".
+type VariableDefinition struct {
+	Name         string
+	Type         *Type
+	DefaultValue *Value
+	Directives   []*Directive
+	Pos          Position
+}
+
+// SelectionSet is the list of selections between braces
+type SelectionSet []Selection
+
+// Selection is a *Field, *FragmentSpread, or *InlineFragment
+type Selection interface {
+	selectionNode()
+}
+
+// Field is a field selection. Pos is the position of the selection, at the
+// alias if there is one; NamePos is the position of the field name.
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    []*Argument
+	Directives   []*Directive
+	SelectionSet SelectionSet
+	Pos          Position
+	NamePos      Position
+}
+
+// ResponseKey returns the key of the field in the response: the alias if set, otherwise the name
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// Argument returns the argument with the given name, or nil
+func (f *Field) Argument(name string) *Argument {
+	for _, a := range f.Arguments {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// FragmentSpread is a "...Name" selection
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+	Pos        Position
+}
+
+// InlineFragment is a "... on Type { }" selection. TypeCondition is empty
+// when the fragment applies to the enclosing type.
+type InlineFragment struct {
+	TypeCondition    string
+	Directives       []*Directive
+	SelectionSet     SelectionSet
+	Pos              Position
+	TypeConditionPos Position
+}
+
+func (*Field) selectionNode()          {}
+func (*FragmentSpread) selectionNode() {}
+func (*InlineFragment) selectionNode() {}
+
+// Argument is a name and value pair of a field or directive
+type Argument struct {
+	Name  string
+	Value *Value
+	Pos   Position
+}
+
+// Directive is a directive such as @include(if: $flag)
+type Directive struct {
+	Name      string
+	Arguments []*Argument
+	Pos       Position
+}
+
+// Argument returns the argument with the given name, or nil
+func (d *Directive) Argument(name string) *Argument {
+	for _, a := range d.Arguments {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// ValueKind identifies the kind of a Value
+type ValueKind int
+
+const (
+	VariableValue ValueKind = iota
+	IntValue
+	FloatValue
+	StringValue
+	BooleanValue
+	NullValue
+	EnumValue
+	ListValue
+	ObjectValue
+)
+
+// Value is an input value. Raw holds the variable name (without "$") for
+// variables, the decoded text for strings, and the literal text for the other
+// scalar kinds. List and Fields hold the items of lists and input objects.
+type Value struct {
+	Kind   ValueKind
+	Raw    string
+	List   []*Value
+	Fields []*ObjectField
+	Pos    Position
+}
+
+// ObjectField is a field of an input object value
+type ObjectField struct {
+	Name  string
+	Value *Value
+	Pos   Position
+}
+
+// Type is a type reference in a variable definition. Exactly one of Name
+// and Elem is set: Name for named types, Elem for list types.
+type Type struct {
+	Name    string
+	Elem    *Type
+	NonNull bool
+	Pos     Position
+}
+
+// String returns the type in GraphQL notation such as "[ID!]!"
+func (t *Type) String() string {
+	var b strings.Builder
+	t.write(&b)
+	return b.String()
+}
+
+func (t *Type) write(b *strings.Builder) {
+	if t.Elem != nil {
+		b.WriteByte('[')
+		t.Elem.write(b)
+		b.WriteByte(']')
+	} else {
+		b.WriteString(t.Name)
+	}
+	if t.NonNull {
+		b.WriteByte('!')
+	}
+}
+
+// NamedType returns the innermost type name
+func (t *Type) NamedType() string {
+	for t.Elem != nil {
+		t = t.Elem
+	}
+	return t.Name
+}
+
+// Operations returns the operation definitions in document order
+func (d *Document) Operations() []*OperationDefinition {
+	var ops []*OperationDefinition
+	for _, def := range d.Definitions {
+		if op, ok := def.(*OperationDefinition); ok {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// Fragments returns the fragment definitions keyed by name
+func (d *Document) Fragments() map[string]*FragmentDefinition {
+	fragments := make(map[string]*FragmentDefinition)
+	for _, def := range d.Definitions {
+		if f, ok := def.(*FragmentDefinition); ok {
+			fragments[f.Name] = f
+		}
+	}
+	return fragments
+}
+
+// Operation selects an operation the way a GraphQL server does: by name, or
+// the only operation in the document when name is empty
+func (d *Document) Operation(name string) (*OperationDefinition, error) {
+	ops := d.Operations()
+	if name == "" {
+		switch len(ops) {
+		case 0:
+			return nil, fmt.Errorf("document contains no operations")
+		case 1:
+			return ops[0], nil
+		default:
+			return nil, fmt.Errorf("document contains %d operations; an operation name is required", len(ops))
+		}
+	}
+	for _, op := range ops {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("operation %q not found", name)
+}
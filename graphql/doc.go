@@ -0,0 +1,14 @@
+// Package graphql parses executable GraphQL documents (operations and
+// fragments) into a small AST that records the position of every node.
+// ParseSchema parses type system documents (SDL), such as GitHub's published
+// schema.docs.graphql, with the same lexer.
+//
+// The package has no knowledge of the GitHub schema; schema-aware checks are
+// built on top of it by other packages in this module.
+//
+//	doc, err := graphql.Parse(`query($owner: String!) { repository(owner: $owner, name: "cli") { id } }`)
+//	if err != nil {
+//		log.Fatal(err) // *graphql.SyntaxError with line and column
+//	}
+//	op, err := doc.Operation("")
+package graphql
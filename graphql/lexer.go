@@ -0,0 +1,347 @@
+package graphql
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of document"
+	case tokenPunct:
+		return "punctuator"
+	case tokenName:
+		return "name"
+	case tokenInt:
+		return "integer"
+	case tokenFloat:
+		return "float"
+	default:
+		return "string"
+	}
+}
+
+type token struct {
+	kind  tokenKind
+	value string // Decoded value for strings, source text otherwise
+	pos   Position
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return t.kind.String()
+	case tokenString:
+		return "string " + strconv.Quote(t.value)
+	default:
+		return strconv.Quote(t.value)
+	}
+}
+
+// lexer splits a GraphQL document into tokens, skipping whitespace, commas,
+// and comments
+type lexer struct {
+	src       string
+	off       int
+	line      int
+	lineStart int
+}
+
+func newLexer(src string) *lexer {
+	l := &lexer{src: src, line: 1}
+	// Skip a byte order mark
+	if strings.HasPrefix(src, "\uFEFF") {
+		l.off = len("\uFEFF")
+		l.lineStart = l.off
+	}
+	return l
+}
+
+func (l *lexer) pos() Position {
+	return Position{Line: l.line, Column: l.off - l.lineStart + 1}
+}
+
+func (l *lexer) newline() {
+	l.line++
+	l.lineStart = l.off
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	pos := l.pos()
+	if l.off >= len(l.src) {
+		return token{kind: tokenEOF, pos: pos}, nil
+	}
+
+	c := l.src[l.off]
+	switch {
+	case strings.IndexByte("!$&()[]{}:=@|", c) >= 0:
+		l.off++
+		return token{kind: tokenPunct, value: string(c), pos: pos}, nil
+	case c == '.':
+		if strings.HasPrefix(l.src[l.off:], "...") {
+			l.off += 3
+			return token{kind: tokenPunct, value: "...", pos: pos}, nil
+		}
+		return token{}, &SyntaxError{Pos: pos, Message: `unexpected ".", did you mean "..."?`}
+	case isNameStart(c):
+		start := l.off
+		for l.off < len(l.src) && isNameContinue(l.src[l.off]) {
+			l.off++
+		}
+		return token{kind: tokenName, value: l.src[start:l.off], pos: pos}, nil
+	case c == '-' || isDigit(c):
+		return l.number(pos)
+	case c == '"':
+		if strings.HasPrefix(l.src[l.off:], `"""`) {
+			return l.blockString(pos)
+		}
+		return l.string(pos)
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.src[l.off:])
+	return token{}, &SyntaxError{Pos: pos, Message: "unexpected character " + strconv.QuoteRune(r)}
+}
+
+func (l *lexer) skipIgnored() {
+	for l.off < len(l.src) {
+		switch c := l.src[l.off]; c {
+		case ' ', '\t', ',':
+			l.off++
+		case '\n':
+			l.off++
+			l.newline()
+		case '\r':
+			l.off++
+			if l.off < len(l.src) && l.src[l.off] == '\n' {
+				l.off++
+			}
+			l.newline()
+		case '#':
+			for l.off < len(l.src) && l.src[l.off] != '\n' && l.src[l.off] != '\r' {
+				l.off++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) number(pos Position) (token, error) {
+	start := l.off
+	kind := tokenInt
+	if l.src[l.off] == '-' {
+		l.off++
+	}
+	if l.off < len(l.src) && l.src[l.off] == '0' {
+		l.off++
+		if l.off < len(l.src) && isDigit(l.src[l.off]) {
+			return token{}, &SyntaxError{Pos: pos, Message: "invalid number, unexpected digit after 0"}
+		}
+	} else if !l.digits() {
+		return token{}, &SyntaxError{Pos: pos, Message: "invalid number, expected digit"}
+	}
+	if l.off < len(l.src) && l.src[l.off] == '.' {
+		kind = tokenFloat
+		l.off++
+		if !l.digits() {
+			return token{}, &SyntaxError{Pos: pos, Message: "invalid number, expected digit after \".\""}
+		}
+	}
+	if l.off < len(l.src) && (l.src[l.off] == 'e' || l.src[l.off] == 'E') {
+		kind = tokenFloat
+		l.off++
+		if l.off < len(l.src) && (l.src[l.off] == '+' || l.src[l.off] == '-') {
+			l.off++
+		}
+		if !l.digits() {
+			return token{}, &SyntaxError{Pos: pos, Message: "invalid number, expected digit in exponent"}
+		}
+	}
+	if l.off < len(l.src) && (isNameStart(l.src[l.off]) || l.src[l.off] == '.') {
+		return token{}, &SyntaxError{Pos: pos, Message: "invalid number, unexpected " + strconv.Quote(l.src[l.off:l.off+1])}
+	}
+	return token{kind: kind, value: l.src[start:l.off], pos: pos}, nil
+}
+
+// digits consumes a run of digits and reports whether there was at least one
+func (l *lexer) digits() bool {
+	start := l.off
+	for l.off < len(l.src) && isDigit(l.src[l.off]) {
+		l.off++
+	}
+	return l.off > start
+}
+
+func (l *lexer) string(pos Position) (token, error) {
+	l.off++ // Opening quote
+	var b strings.Builder
+	for l.off < len(l.src) {
+		c := l.src[l.off]
+		switch {
+		case c == '"':
+			l.off++
+			return token{kind: tokenString, value: b.String(), pos: pos}, nil
+		case c == '\n' || c == '\r':
+			return token{}, &SyntaxError{Pos: l.pos(), Message: "unterminated string"}
+		case c == '\\':
+			if err := l.escape(&b); err != nil {
+				return token{}, err
+			}
+		default:
+			b.WriteByte(c)
+			l.off++
+		}
+	}
+	return token{}, &SyntaxError{Pos: l.pos(), Message: "unterminated string"}
+}
+
+func (l *lexer) escape(b *strings.Builder) error {
+	pos := l.pos()
+	if l.off+1 >= len(l.src) {
+		return &SyntaxError{Pos: pos, Message: "unterminated string"}
+	}
+	c := l.src[l.off+1]
+	l.off += 2
+	switch c {
+	case '"', '\\', '/':
+		b.WriteByte(c)
+	case 'b':
+		b.WriteByte('\b')
+	case 'f':
+		b.WriteByte('\f')
+	case 'n':
+		b.WriteByte('\n')
+	case 'r':
+		b.WriteByte('\r')
+	case 't':
+		b.WriteByte('\t')
+	case 'u':
+		r, ok := l.hex4()
+		if !ok {
+			return &SyntaxError{Pos: pos, Message: "invalid unicode escape sequence"}
+		}
+		if utf8.ValidRune(r) {
+			b.WriteRune(r)
+			return nil
+		}
+		// Combine surrogate pairs written as two escapes
+		if r >= 0xd800 && r < 0xdc00 && strings.HasPrefix(l.src[l.off:], `\u`) {
+			l.off += 2
+			if lo, ok := l.hex4(); ok && lo >= 0xdc00 && lo < 0xe000 {
+				b.WriteRune((r-0xd800)<<10 + (lo - 0xdc00) + 0x10000)
+				return nil
+			}
+		}
+		return &SyntaxError{Pos: pos, Message: "invalid unicode escape sequence"}
+	default:
+		return &SyntaxError{Pos: pos, Message: "invalid escape sequence \\" + string(c)}
+	}
+	return nil
+}
+
+func (l *lexer) hex4() (rune, bool) {
+	if l.off+4 > len(l.src) {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(l.src[l.off:l.off+4], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	l.off += 4
+	return rune(v), true
+}
+
+func (l *lexer) blockString(pos Position) (token, error) {
+	l.off += 3
+	var b strings.Builder
+	for l.off < len(l.src) {
+		switch {
+		case strings.HasPrefix(l.src[l.off:], `"""`):
+			l.off += 3
+			return token{kind: tokenString, value: blockStringValue(b.String()), pos: pos}, nil
+		case strings.HasPrefix(l.src[l.off:], `\"""`):
+			b.WriteString(`"""`)
+			l.off += 4
+		case l.src[l.off] == '\n':
+			b.WriteByte('\n')
+			l.off++
+			l.newline()
+		case l.src[l.off] == '\r':
+			b.WriteByte('\n')
+			l.off++
+			if l.off < len(l.src) && l.src[l.off] == '\n' {
+				l.off++
+			}
+			l.newline()
+		default:
+			b.WriteByte(l.src[l.off])
+			l.off++
+		}
+	}
+	return token{}, &SyntaxError{Pos: l.pos(), Message: "unterminated block string"}
+}
+
+// blockStringValue removes the common indentation and surrounding blank lines
+// of a block string, as defined by the GraphQL specification
+func blockStringValue(raw string) string {
+	lines := strings.Split(raw, "\n")
+
+	common := -1
+	for _, line := range lines[1:] {
+		indent := leadingWhitespace(line)
+		if indent < len(line) && (common < 0 || indent < common) {
+			common = indent
+		}
+	}
+	if common > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= common {
+				lines[i] = lines[i][common:]
+			} else {
+				lines[i] = ""
+			}
+		}
+	}
+
+	for len(lines) > 0 && leadingWhitespace(lines[0]) == len(lines[0]) {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && leadingWhitespace(lines[len(lines)-1]) == len(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func leadingWhitespace(s string) int {
+	i := 0
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+func isNameContinue(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,474 @@
+package graphql
+
+import (
+	"fmt"
+)
+
+// maxDepth bounds the nesting of selection sets and values so that hostile
+// documents cannot exhaust the stack
+const maxDepth = 256
+
+// SyntaxError reports a document that is not valid GraphQL syntax
+type SyntaxError struct {
+	Pos     Position
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at %s: %s", e.Pos, e.Message)
+}
+
+// Parse parses an executable GraphQL document containing operations and fragments
+func Parse(src string) (*Document, error) {
+	p := &parser{lexer: newLexer(src)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{}
+	for p.tok.kind != tokenEOF {
+		def, err := p.definition()
+		if err != nil {
+			return nil, err
+		}
+		doc.Definitions = append(doc.Definitions, def)
+	}
+	if len(doc.Definitions) == 0 {
+		return nil, &SyntaxError{Pos: p.tok.pos, Message: "document contains no definitions"}
+	}
+	return doc, nil
+}
+
+type parser struct {
+	lexer *lexer
+	tok   token
+	depth int
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	return &SyntaxError{Pos: p.tok.pos, Message: "unexpected " + p.tok.String()}
+}
+
+// peek reports whether the current token is the given punctuator
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == punct
+}
+
+// skip consumes the given punctuator if it is the current token
+func (p *parser) skip(punct string) (bool, error) {
+	if !p.peek(punct) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.peek(punct) {
+		return &SyntaxError{Pos: p.tok.pos, Message: fmt.Sprintf("expected %q, got %s", punct, p.tok)}
+	}
+	return p.advance()
+}
+
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", &SyntaxError{Pos: p.tok.pos, Message: "expected name, got " + p.tok.String()}
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) keyword(word string) error {
+	if p.tok.kind != tokenName || p.tok.value != word {
+		return &SyntaxError{Pos: p.tok.pos, Message: fmt.Sprintf("expected %q, got %s", word, p.tok)}
+	}
+	return p.advance()
+}
+
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > maxDepth {
+		return &SyntaxError{Pos: p.tok.pos, Message: "document is nested too deeply"}
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) definition() (Definition, error) {
+	if p.peek("{") {
+		pos := p.tok.pos
+		set, err := p.selectionSet()
+		if err != nil {
+			return nil, err
+		}
+		return &OperationDefinition{Operation: Query, SelectionSet: set, Pos: pos}, nil
+	}
+	if p.tok.kind == tokenName {
+		switch p.tok.value {
+		case "query", "mutation", "subscription":
+			return p.operationDefinition()
+		case "fragment":
+			return p.fragmentDefinition()
+		}
+	}
+	return nil, p.unexpected()
+}
+
+func (p *parser) operationDefinition() (*OperationDefinition, error) {
+	op := &OperationDefinition{Operation: OperationType(p.tok.value), Pos: p.tok.pos}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if p.tok.kind == tokenName {
+		if op.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek("(") {
+		if op.VariableDefinitions, err = p.variableDefinitions(); err != nil {
+			return nil, err
+		}
+	}
+	if op.Directives, err = p.directives(false); err != nil {
+		return nil, err
+	}
+	if op.SelectionSet, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (p *parser) fragmentDefinition() (*FragmentDefinition, error) {
+	f := &FragmentDefinition{Pos: p.tok.pos}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if p.tok.kind == tokenName && p.tok.value == "on" {
+		return nil, &SyntaxError{Pos: p.tok.pos, Message: `fragment name must not be "on"`}
+	}
+	if f.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if err := p.keyword("on"); err != nil {
+		return nil, err
+	}
+	f.TypeConditionPos = p.tok.pos
+	if f.TypeCondition, err = p.name(); err != nil {
+		return nil, err
+	}
+	if f.Directives, err = p.directives(false); err != nil {
+		return nil, err
+	}
+	if f.SelectionSet, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (p *parser) variableDefinitions() ([]*VariableDefinition, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var defs []*VariableDefinition
+	for {
+		v := &VariableDefinition{Pos: p.tok.pos}
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+		var err error
+		if v.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if v.Type, err = p.typeRef(); err != nil {
+			return nil, err
+		}
+		if ok, err := p.skip("="); err != nil {
+			return nil, err
+		} else if ok {
+			if v.DefaultValue, err = p.value(true); err != nil {
+				return nil, err
+			}
+		}
+		if v.Directives, err = p.directives(true); err != nil {
+			return nil, err
+		}
+		defs = append(defs, v)
+
+		if ok, err := p.skip(")"); err != nil || ok {
+			return defs, err
+		}
+	}
+}
+
+func (p *parser) typeRef() (*Type, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	t := &Type{Pos: p.tok.pos}
+	var err error
+	if ok, err := p.skip("["); err != nil {
+		return nil, err
+	} else if ok {
+		if t.Elem, err = p.typeRef(); err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	} else if t.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if t.NonNull, err = p.skip("!"); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (p *parser) selectionSet() (SelectionSet, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var set SelectionSet
+	for {
+		sel, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, sel)
+
+		if ok, err := p.skip("}"); err != nil || ok {
+			return set, err
+		}
+	}
+}
+
+func (p *parser) selection() (Selection, error) {
+	if !p.peek("...") {
+		return p.field()
+	}
+
+	pos := p.tok.pos
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenName && p.tok.value != "on" {
+		spread := &FragmentSpread{Pos: pos}
+		var err error
+		if spread.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if spread.Directives, err = p.directives(false); err != nil {
+			return nil, err
+		}
+		return spread, nil
+	}
+
+	inline := &InlineFragment{Pos: pos}
+	var err error
+	if p.tok.kind == tokenName {
+		if err := p.advance(); err != nil { // "on"
+			return nil, err
+		}
+		inline.TypeConditionPos = p.tok.pos
+		if inline.TypeCondition, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if inline.Directives, err = p.directives(false); err != nil {
+		return nil, err
+	}
+	if inline.SelectionSet, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return inline, nil
+}
+
+func (p *parser) field() (*Field, error) {
+	f := &Field{Pos: p.tok.pos, NamePos: p.tok.pos}
+	var err error
+	if f.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(":"); err != nil {
+		return nil, err
+	} else if ok {
+		f.Alias = f.Name
+		f.NamePos = p.tok.pos
+		if f.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if f.Arguments, err = p.arguments(false); err != nil {
+		return nil, err
+	}
+	if f.Directives, err = p.directives(false); err != nil {
+		return nil, err
+	}
+	if p.peek("{") {
+		if f.SelectionSet, err = p.selectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (p *parser) arguments(constant bool) ([]*Argument, error) {
+	if ok, err := p.skip("("); err != nil || !ok {
+		return nil, err
+	}
+	var args []*Argument
+	for {
+		arg := &Argument{Pos: p.tok.pos}
+		var err error
+		if arg.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if arg.Value, err = p.value(constant); err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		if ok, err := p.skip(")"); err != nil || ok {
+			return args, err
+		}
+	}
+}
+
+func (p *parser) directives(constant bool) ([]*Directive, error) {
+	var directives []*Directive
+	for p.peek("@") {
+		d := &Directive{Pos: p.tok.pos}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		if d.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if d.Arguments, err = p.arguments(constant); err != nil {
+			return nil, err
+		}
+		directives = append(directives, d)
+	}
+	return directives, nil
+}
+
+// value parses an input value; constant values may not contain variables
+func (p *parser) value(constant bool) (*Value, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	v := &Value{Pos: p.tok.pos, Raw: p.tok.value}
+	switch p.tok.kind {
+	case tokenInt:
+		v.Kind = IntValue
+	case tokenFloat:
+		v.Kind = FloatValue
+	case tokenString:
+		v.Kind = StringValue
+	case tokenName:
+		switch p.tok.value {
+		case "true", "false":
+			v.Kind = BooleanValue
+		case "null":
+			v.Kind = NullValue
+		default:
+			v.Kind = EnumValue
+		}
+	case tokenPunct:
+		switch p.tok.value {
+		case "$":
+			if constant {
+				return nil, &SyntaxError{Pos: p.tok.pos, Message: "unexpected variable in constant value"}
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			v.Kind = VariableValue
+			var err error
+			v.Raw, err = p.name()
+			return v, err
+		case "[":
+			return p.listValue(v, constant)
+		case "{":
+			return p.objectValue(v, constant)
+		}
+		return nil, p.unexpected()
+	default:
+		return nil, p.unexpected()
+	}
+	return v, p.advance()
+}
+
+func (p *parser) listValue(v *Value, constant bool) (*Value, error) {
+	v.Kind = ListValue
+	v.Raw = ""
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	for {
+		if ok, err := p.skip("]"); err != nil || ok {
+			return v, err
+		}
+		item, err := p.value(constant)
+		if err != nil {
+			return nil, err
+		}
+		v.List = append(v.List, item)
+	}
+}
+
+func (p *parser) objectValue(v *Value, constant bool) (*Value, error) {
+	v.Kind = ObjectValue
+	v.Raw = ""
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	for {
+		if ok, err := p.skip("}"); err != nil || ok {
+			return v, err
+		}
+		field := &ObjectField{Pos: p.tok.pos}
+		var err error
+		if field.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if field.Value, err = p.value(constant); err != nil {
+			return nil, err
+		}
+		v.Fields = append(v.Fields, field)
+	}
+}
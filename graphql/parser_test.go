@@ -0,0 +1,208 @@
+package graphql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	src := `# Fetch open issues
+query OpenIssues($owner: String!, $name: String! = "cli", $states: [IssueState!] = [OPEN]) {
+  repository(owner: $owner, name: $name) {
+    issues(first: 10, states: $states, orderBy: {field: CREATED_AT, direction: DESC}) {
+      nodes { ...IssueFields }
+      pageInfo { hasNextPage endCursor }
+    }
+    issueOrPullRequest(number: 1) {
+      __typename
+      ... on Issue { title }
+      ... @include(if: true) { __typename }
+    }
+  }
+}
+
+fragment IssueFields on Issue {
+  number
+  headline: title
+}
+`
+	doc, err := Parse(src)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(doc.Definitions) != 2 {
+		t.Fatalf("Expected 2 definitions, got %d", len(doc.Definitions))
+	}
+
+	op, err := doc.Operation("")
+	if err != nil {
+		t.Fatalf("Operation failed: %v", err)
+	}
+	if op.Operation != Query || op.Name != "OpenIssues" {
+		t.Errorf("Unexpected operation: %s %s", op.Operation, op.Name)
+	}
+	if op.Pos != (Position{Line: 2, Column: 1}) {
+		t.Errorf("Unexpected operation position: %v", op.Pos)
+	}
+	if len(op.VariableDefinitions) != 3 {
+		t.Fatalf("Expected 3 variables, got %d", len(op.VariableDefinitions))
+	}
+	if got := op.VariableDefinitions[2].Type.String(); got != "[IssueState!]" {
+		t.Errorf("Unexpected variable type: %s", got)
+	}
+	if got := op.VariableDefinitions[1].DefaultValue; got == nil || got.Kind != StringValue || got.Raw != "cli" {
+		t.Errorf("Unexpected default value: %+v", got)
+	}
+
+	repo := op.SelectionSet[0].(*Field)
+	if repo.Name != "repository" || len(repo.Arguments) != 2 {
+		t.Fatalf("Unexpected repository field: %+v", repo)
+	}
+	if arg := repo.Argument("owner"); arg == nil || arg.Value.Kind != VariableValue || arg.Value.Raw != "owner" {
+		t.Errorf("Unexpected owner argument: %+v", arg)
+	}
+
+	issues := repo.SelectionSet[0].(*Field)
+	if issues.Pos != (Position{Line: 4, Column: 5}) {
+		t.Errorf("Unexpected issues position: %v", issues.Pos)
+	}
+	orderBy := issues.Argument("orderBy").Value
+	if orderBy.Kind != ObjectValue || len(orderBy.Fields) != 2 || orderBy.Fields[1].Value.Kind != EnumValue {
+		t.Errorf("Unexpected orderBy value: %+v", orderBy)
+	}
+	if spread, ok := issues.SelectionSet[0].(*Field).SelectionSet[0].(*FragmentSpread); !ok || spread.Name != "IssueFields" {
+		t.Errorf("Expected fragment spread, got %+v", issues.SelectionSet[0])
+	}
+
+	union := repo.SelectionSet[1].(*Field)
+	if inline, ok := union.SelectionSet[1].(*InlineFragment); !ok || inline.TypeCondition != "Issue" || inline.TypeConditionPos != (Position{Line: 10, Column: 14}) {
+		t.Errorf("Expected inline fragment on Issue, got %+v", union.SelectionSet[1])
+	}
+	if inline, ok := union.SelectionSet[2].(*InlineFragment); !ok || inline.TypeCondition != "" || len(inline.Directives) != 1 {
+		t.Errorf("Expected inline fragment without type condition, got %+v", union.SelectionSet[2])
+	}
+
+	fragment := doc.Fragments()["IssueFields"]
+	if fragment == nil || fragment.TypeCondition != "Issue" || fragment.TypeConditionPos != (Position{Line: 16, Column: 25}) {
+		t.Fatalf("Unexpected fragment: %+v", fragment)
+	}
+	if f := fragment.SelectionSet[1].(*Field); f.Alias != "headline" || f.Name != "title" || f.ResponseKey() != "headline" ||
+		f.Pos != (Position{Line: 18, Column: 3}) || f.NamePos != (Position{Line: 18, Column: 13}) {
+		t.Errorf("Unexpected aliased field: %+v", f)
+	}
+}
+
+func TestParseShorthand(t *testing.T) {
+	doc, err := Parse(`{ viewer { login } }`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	op, err := doc.Operation("")
+	if err != nil {
+		t.Fatalf("Operation failed: %v", err)
+	}
+	if op.Operation != Query || op.Name != "" {
+		t.Errorf("Unexpected operation: %+v", op)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		pos  Position
+	}{
+		{"empty", "", Position{1, 1}},
+		{"unclosed selection set", "{ viewer {\n  login\n", Position{3, 1}},
+		{"missing type condition", "fragment F { id }", Position{1, 12}},
+		{"bad character", "{ viewer ? }", Position{1, 10}},
+		{"variable in default", "query($a: Int = $b) { id }", Position{1, 17}},
+		{"leading zero", "{ f(a: 01) }", Position{1, 8}},
+		{"unterminated string", "{ f(a: \"abc\n) }", Position{1, 12}},
+		{"type definition", "type Foo { id: ID }", Position{1, 1}},
+		{"empty selection set", "{ }", Position{1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Expected *SyntaxError, got %v", err)
+			}
+			if syntaxErr.Pos != tt.pos {
+				t.Errorf("Expected error at %v, got %v (%v)", tt.pos, syntaxErr.Pos, err)
+			}
+		})
+	}
+}
+
+func TestParseDepthLimit(t *testing.T) {
+	src := ""
+	for i := 0; i < maxDepth+1; i++ {
+		src += "{ a "
+	}
+	if _, err := Parse(src); err == nil {
+		t.Fatal("Expected error for deeply nested document")
+	}
+}
+
+func TestStringValues(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`"plain"`, "plain"},
+		{`"esc\"aped\né"`, "esc\"aped\né"},
+		{`"😀"`, "\U0001F600"},
+		{"\"\"\"\n    Block\n      indented\n    \"\"\"", "Block\n  indented"},
+		{`"""with \""" quotes"""`, `with """ quotes`},
+	}
+
+	for _, tt := range tests {
+		doc, err := Parse("{ f(a: " + tt.src + ") }")
+		if err != nil {
+			t.Errorf("Parse(%s) failed: %v", tt.src, err)
+			continue
+		}
+		got := doc.Definitions[0].(*OperationDefinition).SelectionSet[0].(*Field).Arguments[0].Value.Raw
+		if got != tt.want {
+			t.Errorf("Parse(%s) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestValueInterface(t *testing.T) {
+	doc, err := Parse(`{ f(a: [1, 2.5, "s", ENUM, true, null, $v, {k: 3}]) }`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	value := doc.Definitions[0].(*OperationDefinition).SelectionSet[0].(*Field).Arguments[0].Value
+	got := value.Interface(map[string]interface{}{"v": "var"}).([]interface{})
+
+	want := []interface{}{int64(1), 2.5, "s", "ENUM", true, nil, "var"}
+	for i, w := range want {
+		if got[i] != w {
+			t.Errorf("item %d = %#v, want %#v", i, got[i], w)
+		}
+	}
+	if obj, ok := got[7].(map[string]interface{}); !ok || obj["k"] != int64(3) {
+		t.Errorf("Unexpected object value: %#v", got[7])
+	}
+}
+
+func TestDocumentOperation(t *testing.T) {
+	doc, err := Parse(`query A { a } query B { b }`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if _, err := doc.Operation(""); err == nil {
+		t.Error("Expected error when selecting among several operations without a name")
+	}
+	if op, err := doc.Operation("B"); err != nil || op.Name != "B" {
+		t.Errorf("Operation(B) = %v, %v", op, err)
+	}
+	if _, err := doc.Operation("C"); err == nil {
+		t.Error("Expected error for unknown operation")
+	}
+}
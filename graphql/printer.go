@@ -0,0 +1,201 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Print renders a document in a canonical format: two-space indentation, one
+// selection per line, and a blank line between definitions. Comments and
+// insignificant punctuation are not preserved.
+func Print(doc *Document) string {
+	var p printer
+	for i, def := range doc.Definitions {
+		if i > 0 {
+			p.b.WriteByte('\n')
+		}
+		switch def := def.(type) {
+		case *OperationDefinition:
+			p.operation(def)
+		case *FragmentDefinition:
+			p.fragment(def)
+		}
+		p.b.WriteByte('\n')
+	}
+	return p.b.String()
+}
+
+type printer struct {
+	b      strings.Builder
+	indent int
+}
+
+func (p *printer) operation(op *OperationDefinition) {
+	// The query shorthand is kept when nothing else needs to be printed
+	if op.Operation == Query && op.Name == "" && len(op.VariableDefinitions) == 0 && len(op.Directives) == 0 {
+		p.selectionSet(op.SelectionSet)
+		return
+	}
+
+	p.b.WriteString(string(op.Operation))
+	if op.Name != "" {
+		p.b.WriteByte(' ')
+		p.b.WriteString(op.Name)
+	}
+	if len(op.VariableDefinitions) > 0 {
+		p.b.WriteByte('(')
+		for i, v := range op.VariableDefinitions {
+			if i > 0 {
+				p.b.WriteString(", ")
+			}
+			p.b.WriteByte('$')
+			p.b.WriteString(v.Name)
+			p.b.WriteString(": ")
+			p.b.WriteString(v.Type.String())
+			if v.DefaultValue != nil {
+				p.b.WriteString(" = ")
+				p.value(v.DefaultValue)
+			}
+			p.directives(v.Directives)
+		}
+		p.b.WriteByte(')')
+	}
+	p.directives(op.Directives)
+	p.b.WriteByte(' ')
+	p.selectionSet(op.SelectionSet)
+}
+
+func (p *printer) fragment(f *FragmentDefinition) {
+	fmt.Fprintf(&p.b, "fragment %s on %s", f.Name, f.TypeCondition)
+	p.directives(f.Directives)
+	p.b.WriteByte(' ')
+	p.selectionSet(f.SelectionSet)
+}
+
+func (p *printer) selectionSet(set SelectionSet) {
+	p.b.WriteString("{\n")
+	p.indent++
+	for _, sel := range set {
+		p.b.WriteString(strings.Repeat("  ", p.indent))
+		switch sel := sel.(type) {
+		case *Field:
+			if sel.Alias != "" {
+				p.b.WriteString(sel.Alias)
+				p.b.WriteString(": ")
+			}
+			p.b.WriteString(sel.Name)
+			p.arguments(sel.Arguments)
+			p.directives(sel.Directives)
+			if len(sel.SelectionSet) > 0 {
+				p.b.WriteByte(' ')
+				p.selectionSet(sel.SelectionSet)
+			}
+		case *FragmentSpread:
+			p.b.WriteString("...")
+			p.b.WriteString(sel.Name)
+			p.directives(sel.Directives)
+		case *InlineFragment:
+			p.b.WriteString("...")
+			if sel.TypeCondition != "" {
+				p.b.WriteString(" on ")
+				p.b.WriteString(sel.TypeCondition)
+			}
+			p.directives(sel.Directives)
+			p.b.WriteByte(' ')
+			p.selectionSet(sel.SelectionSet)
+		}
+		p.b.WriteByte('\n')
+	}
+	p.indent--
+	p.b.WriteString(strings.Repeat("  ", p.indent))
+	p.b.WriteByte('}')
+}
+
+func (p *printer) arguments(args []*Argument) {
+	if len(args) == 0 {
+		return
+	}
+	p.b.WriteByte('(')
+	for i, arg := range args {
+		if i > 0 {
+			p.b.WriteString(", ")
+		}
+		p.b.WriteString(arg.Name)
+		p.b.WriteString(": ")
+		p.value(arg.Value)
+	}
+	p.b.WriteByte(')')
+}
+
+func (p *printer) directives(directives []*Directive) {
+	for _, d := range directives {
+		p.b.WriteString(" @")
+		p.b.WriteString(d.Name)
+		p.arguments(d.Arguments)
+	}
+}
+
+func (p *printer) value(v *Value) {
+	switch v.Kind {
+	case VariableValue:
+		p.b.WriteByte('$')
+		p.b.WriteString(v.Raw)
+	case StringValue:
+		p.b.WriteString(QuoteString(v.Raw))
+	case ListValue:
+		p.b.WriteByte('[')
+		for i, item := range v.List {
+			if i > 0 {
+				p.b.WriteString(", ")
+			}
+			p.value(item)
+		}
+		p.b.WriteByte(']')
+	case ObjectValue:
+		p.b.WriteByte('{')
+		for i, f := range v.Fields {
+			if i > 0 {
+				p.b.WriteString(", ")
+			}
+			p.b.WriteString(f.Name)
+			p.b.WriteString(": ")
+			p.value(f.Value)
+		}
+		p.b.WriteByte('}')
+	default:
+		p.b.WriteString(v.Raw)
+	}
+}
+
+// QuoteString returns s as a GraphQL string literal
+func QuoteString(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package graphql
+
+import (
+	"testing"
+)
+
+func TestPrint(t *testing.T) {
+	src := `query Issues($owner: String! = "o\"k", $states: [IssueState!]) @cached {
+  repository(owner: $owner, name: "cli") {
+    issues(first: 10, states: $states, orderBy: {field: CREATED_AT, direction: DESC}) @include(if: true) {
+      nodes { ...F title: body }
+    }
+    ... on Node { id }
+  }
+}
+fragment F on Issue { number labels(first: [1, 2.5, null, false]) { totalCount } }
+`
+	want := `query Issues($owner: String! = "o\"k", $states: [IssueState!]) @cached {
+  repository(owner: $owner, name: "cli") {
+    issues(first: 10, states: $states, orderBy: {field: CREATED_AT, direction: DESC}) @include(if: true) {
+      nodes {
+        ...F
+        title: body
+      }
+    }
+    ... on Node {
+      id
+    }
+  }
+}
+
+fragment F on Issue {
+  number
+  labels(first: [1, 2.5, null, false]) {
+    totalCount
+  }
+}
+`
+	doc, err := Parse(src)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	got := Print(doc)
+	if got != want {
+		t.Errorf("Print mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Printing is stable
+	again, err := Parse(got)
+	if err != nil {
+		t.Fatalf("Parse of printed document failed: %v", err)
+	}
+	if Print(again) != got {
+		t.Error("Printing a printed document changed it")
+	}
+}
+
+func TestPrintShorthand(t *testing.T) {
+	doc, err := Parse(`{ viewer { login } }`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got, want := Print(doc), "{\n  viewer {\n    login\n  }\n}\n"; got != want {
+		t.Errorf("Print = %q, want %q", got, want)
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", `"plain"`},
+		{"a\"b\\c", `"a\"b\\c"`},
+		{"line\nbreak\ttab", `"line\nbreak\ttab"`},
+		{"\x01é", `"\u0001é"`},
+	}
+	for _, tt := range tests {
+		if got := QuoteString(tt.in); got != tt.want {
+			t.Errorf("QuoteString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
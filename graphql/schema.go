@@ -0,0 +1,471 @@
+package graphql
+
+// SchemaDocument is a parsed type system document, such as GitHub's
+// published schema.docs.graphql. Definitions and extensions are kept in
+// document order and are not merged or validated.
+type SchemaDocument struct {
+	Schema     []*SchemaDefinition // Schema definitions and extensions
+	Types      []*TypeDefinition
+	Directives []*DirectiveDefinition
+}
+
+// SchemaDefinition is a schema definition, or a schema extension when Extend
+// is set
+type SchemaDefinition struct {
+	Description    string
+	Extend         bool
+	Directives     []*Directive
+	OperationTypes []*OperationTypeDefinition
+	Pos            Position
+}
+
+// OperationTypeDefinition names the root type of an operation, such as
+// "query: Query"
+type OperationTypeDefinition struct {
+	Operation OperationType
+	Type      string
+	Pos       Position
+}
+
+// TypeDefinition is a scalar, object, interface, union, enum, or input object
+// definition, or an extension of one when Extend is set. Kind is named as
+// introspection names it: SCALAR, OBJECT, INTERFACE, UNION, ENUM, or
+// INPUT_OBJECT. Only the members of its kind are set: Interfaces and Fields
+// for objects and interfaces, Types for unions, EnumValues for enums, and
+// InputFields for input objects.
+type TypeDefinition struct {
+	Kind        string
+	Name        string
+	Description string
+	Extend      bool
+	Interfaces  []string
+	Directives  []*Directive
+	Fields      []*FieldDefinition
+	Types       []string
+	EnumValues  []*EnumValueDefinition
+	InputFields []*InputValueDefinition
+	Pos         Position
+}
+
+// FieldDefinition is a field of an object or interface type
+type FieldDefinition struct {
+	Description string
+	Name        string
+	Arguments   []*InputValueDefinition
+	Type        *Type
+	Directives  []*Directive
+	Pos         Position
+}
+
+// InputValueDefinition is an argument or an input field
+type InputValueDefinition struct {
+	Description  string
+	Name         string
+	Type         *Type
+	DefaultValue *Value
+	Directives   []*Directive
+	Pos          Position
+}
+
+// EnumValueDefinition is a value of an enum type
+type EnumValueDefinition struct {
+	Description string
+	Name        string
+	Directives  []*Directive
+	Pos         Position
+}
+
+// DirectiveDefinition declares a directive. Locations are named as
+// introspection names them, such as FIELD_DEFINITION.
+type DirectiveDefinition struct {
+	Description string
+	Name        string
+	Arguments   []*InputValueDefinition
+	Repeatable  bool
+	Locations   []string
+	Pos         Position
+}
+
+// typeKinds maps the keywords of type definitions to the kinds of the types
+var typeKinds = map[string]string{
+	"scalar": "SCALAR", "type": "OBJECT", "interface": "INTERFACE",
+	"union": "UNION", "enum": "ENUM", "input": "INPUT_OBJECT",
+}
+
+// IsSchemaDocument reports whether src starts like a type system document:
+// with a description or with a keyword such as "type" or "schema", after
+// whitespace and comments. It looks at the first token only, so src may be
+// a prefix of the document.
+func IsSchemaDocument(src string) bool {
+	l := newLexer(src)
+	l.skipIgnored()
+	if l.off >= len(l.src) {
+		return false
+	}
+	if l.src[l.off] == '"' {
+		return true
+	}
+	tok, err := l.next()
+	if err != nil || tok.kind != tokenName {
+		return false
+	}
+	switch tok.value {
+	case "schema", "directive", "extend":
+		return true
+	}
+	return typeKinds[tok.value] != ""
+}
+
+// ParseSchema parses a type system document of schema, type, and directive
+// definitions and extensions
+func ParseSchema(src string) (*SchemaDocument, error) {
+	p := &parser{lexer: newLexer(src)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &SchemaDocument{}
+	for p.tok.kind != tokenEOF {
+		if err := p.typeSystemDefinition(doc); err != nil {
+			return nil, err
+		}
+	}
+	if len(doc.Schema)+len(doc.Types)+len(doc.Directives) == 0 {
+		return nil, &SyntaxError{Pos: p.tok.pos, Message: "document contains no definitions"}
+	}
+	return doc, nil
+}
+
+// description consumes the description before a definition, if any
+func (p *parser) description() (string, error) {
+	if p.tok.kind != tokenString {
+		return "", nil
+	}
+	description := p.tok.value
+	return description, p.advance()
+}
+
+func (p *parser) typeSystemDefinition(doc *SchemaDocument) error {
+	description, err := p.description()
+	if err != nil {
+		return err
+	}
+	if p.tok.kind != tokenName {
+		return p.unexpected()
+	}
+	pos := p.tok.pos
+	extend := p.tok.value == "extend"
+	if extend {
+		if description != "" {
+			return &SyntaxError{Pos: pos, Message: "extensions cannot have descriptions"}
+		}
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if p.tok.kind != tokenName {
+			return p.unexpected()
+		}
+	}
+
+	switch keyword := p.tok.value; {
+	case keyword == "schema":
+		def, err := p.schemaDefinition(extend)
+		if err != nil {
+			return err
+		}
+		def.Description, def.Pos = description, pos
+		doc.Schema = append(doc.Schema, def)
+	case typeKinds[keyword] != "":
+		def, err := p.typeDefinition(extend)
+		if err != nil {
+			return err
+		}
+		def.Description, def.Pos = description, pos
+		doc.Types = append(doc.Types, def)
+	case keyword == "directive" && !extend:
+		def, err := p.directiveDefinition()
+		if err != nil {
+			return err
+		}
+		def.Description, def.Pos = description, pos
+		doc.Directives = append(doc.Directives, def)
+	default:
+		return p.unexpected()
+	}
+	return nil
+}
+
+func (p *parser) schemaDefinition(extend bool) (*SchemaDefinition, error) {
+	def := &SchemaDefinition{Extend: extend}
+	if err := p.advance(); err != nil { // "schema"
+		return nil, err
+	}
+	var err error
+	if def.Directives, err = p.directives(true); err != nil {
+		return nil, err
+	}
+	// Extensions may add directives only
+	if extend && !p.peek("{") {
+		return def, nil
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for {
+		op := &OperationTypeDefinition{Pos: p.tok.pos}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		switch op.Operation = OperationType(name); op.Operation {
+		case Query, Mutation, Subscription:
+		default:
+			return nil, &SyntaxError{Pos: op.Pos, Message: "unknown operation type " + name}
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if op.Type, err = p.name(); err != nil {
+			return nil, err
+		}
+		def.OperationTypes = append(def.OperationTypes, op)
+
+		if ok, err := p.skip("}"); err != nil || ok {
+			return def, err
+		}
+	}
+}
+
+func (p *parser) typeDefinition(extend bool) (*TypeDefinition, error) {
+	def := &TypeDefinition{Kind: typeKinds[p.tok.value], Extend: extend}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	var err error
+	if def.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if (def.Kind == "OBJECT" || def.Kind == "INTERFACE") && p.tok.kind == tokenName && p.tok.value == "implements" {
+		if def.Interfaces, err = p.implements(); err != nil {
+			return nil, err
+		}
+	}
+	if def.Directives, err = p.directives(true); err != nil {
+		return nil, err
+	}
+
+	switch def.Kind {
+	case "OBJECT", "INTERFACE":
+		if p.peek("{") {
+			def.Fields, err = p.fieldsDefinition()
+		}
+	case "UNION":
+		if ok, err := p.skip("="); err != nil {
+			return nil, err
+		} else if ok {
+			def.Types, err = p.unionMembers()
+			if err != nil {
+				return nil, err
+			}
+		}
+	case "ENUM":
+		if p.peek("{") {
+			def.EnumValues, err = p.enumValuesDefinition()
+		}
+	case "INPUT_OBJECT":
+		if p.peek("{") {
+			def.InputFields, err = p.inputValueDefinitions("{", "}")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+// implements parses "implements A & B"
+func (p *parser) implements() ([]string, error) {
+	if err := p.advance(); err != nil { // "implements"
+		return nil, err
+	}
+	if _, err := p.skip("&"); err != nil {
+		return nil, err
+	}
+	var names []string
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if ok, err := p.skip("&"); err != nil || !ok {
+			return names, err
+		}
+	}
+}
+
+// unionMembers parses the member types after the "=" of a union
+func (p *parser) unionMembers() ([]string, error) {
+	if _, err := p.skip("|"); err != nil {
+		return nil, err
+	}
+	var names []string
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if ok, err := p.skip("|"); err != nil || !ok {
+			return names, err
+		}
+	}
+}
+
+func (p *parser) fieldsDefinition() ([]*FieldDefinition, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var fields []*FieldDefinition
+	for {
+		if ok, err := p.skip("}"); err != nil || ok {
+			return fields, err
+		}
+		f := &FieldDefinition{}
+		var err error
+		if f.Description, err = p.description(); err != nil {
+			return nil, err
+		}
+		f.Pos = p.tok.pos
+		if f.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if p.peek("(") {
+			if f.Arguments, err = p.inputValueDefinitions("(", ")"); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if f.Type, err = p.typeRef(); err != nil {
+			return nil, err
+		}
+		if f.Directives, err = p.directives(true); err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+}
+
+// inputValueDefinitions parses arguments or input fields between open and
+// close
+func (p *parser) inputValueDefinitions(open, close string) ([]*InputValueDefinition, error) {
+	if err := p.expect(open); err != nil {
+		return nil, err
+	}
+	var values []*InputValueDefinition
+	for {
+		if ok, err := p.skip(close); err != nil || ok {
+			return values, err
+		}
+		v := &InputValueDefinition{}
+		var err error
+		if v.Description, err = p.description(); err != nil {
+			return nil, err
+		}
+		v.Pos = p.tok.pos
+		if v.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if v.Type, err = p.typeRef(); err != nil {
+			return nil, err
+		}
+		if ok, err := p.skip("="); err != nil {
+			return nil, err
+		} else if ok {
+			if v.DefaultValue, err = p.value(true); err != nil {
+				return nil, err
+			}
+		}
+		if v.Directives, err = p.directives(true); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+}
+
+func (p *parser) enumValuesDefinition() ([]*EnumValueDefinition, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var values []*EnumValueDefinition
+	for {
+		if ok, err := p.skip("}"); err != nil || ok {
+			return values, err
+		}
+		v := &EnumValueDefinition{}
+		var err error
+		if v.Description, err = p.description(); err != nil {
+			return nil, err
+		}
+		v.Pos = p.tok.pos
+		if p.tok.kind == tokenName {
+			switch p.tok.value {
+			case "true", "false", "null":
+				return nil, &SyntaxError{Pos: v.Pos, Message: "enum value must not be " + p.tok.value}
+			}
+		}
+		if v.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if v.Directives, err = p.directives(true); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+}
+
+func (p *parser) directiveDefinition() (*DirectiveDefinition, error) {
+	def := &DirectiveDefinition{}
+	if err := p.advance(); err != nil { // "directive"
+		return nil, err
+	}
+	if err := p.expect("@"); err != nil {
+		return nil, err
+	}
+	var err error
+	if def.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if p.peek("(") {
+		if def.Arguments, err = p.inputValueDefinitions("(", ")"); err != nil {
+			return nil, err
+		}
+	}
+	if p.tok.kind == tokenName && p.tok.value == "repeatable" {
+		def.Repeatable = true
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.keyword("on"); err != nil {
+		return nil, err
+	}
+	if _, err := p.skip("|"); err != nil {
+		return nil, err
+	}
+	for {
+		location, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		def.Locations = append(def.Locations, location)
+		if ok, err := p.skip("|"); err != nil || !ok {
+			return def, err
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package graphql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSchema(t *testing.T) {
+	doc, err := ParseSchema(`# GitHub-style schema
+"The schema."
+schema @a { query: Query mutation: Mutation }
+
+"""
+A repository.
+
+With a block description.
+"""
+type Repository implements & Node & Starrable @key(fields: "id") {
+  id: ID!
+  "Issues in the repository."
+  issues(
+    "How to order them."
+    orderBy: IssueOrder = {field: CREATED_AT, direction: DESC}
+    labels: [String!] = ["bug"]
+  ): [Issue] @deprecated(reason: "Use search.")
+}
+
+union SearchResult = | Issue | Repository
+enum State { OPEN "Closed." CLOSED @deprecated }
+input IssueOrder @oneOf { field: String! = "CREATED_AT" }
+scalar URI @specifiedBy(url: "https://www.rfc-editor.org/rfc/rfc3986")
+interface Node { id: ID! }
+directive @preview("The toggle." toggledBy: String!) repeatable on | FIELD_DEFINITION | ENUM_VALUE
+extend type Repository { name: String }
+extend schema @b
+`)
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+	if len(doc.Schema) != 2 || len(doc.Types) != 7 || len(doc.Directives) != 1 {
+		t.Fatalf("Unexpected definitions: %d schema, %d types, %d directives", len(doc.Schema), len(doc.Types), len(doc.Directives))
+	}
+
+	schema := doc.Schema[0]
+	if schema.Description != "The schema." || len(schema.OperationTypes) != 2 || schema.OperationTypes[1].Operation != Mutation || schema.OperationTypes[1].Type != "Mutation" {
+		t.Errorf("Unexpected schema definition %+v", schema)
+	}
+	if ext := doc.Schema[1]; !ext.Extend || len(ext.Directives) != 1 || ext.OperationTypes != nil {
+		t.Errorf("Unexpected schema extension %+v", ext)
+	}
+
+	repo := doc.Types[0]
+	if repo.Kind != "OBJECT" || repo.Description != "A repository.\n\nWith a block description." || len(repo.Interfaces) != 2 || repo.Interfaces[1] != "Starrable" {
+		t.Errorf("Unexpected type %+v", repo)
+	}
+	if repo.Pos != (Position{Line: 10, Column: 1}) {
+		t.Errorf("Expected the position of the keyword, got %s", repo.Pos)
+	}
+	issues := repo.Fields[1]
+	if issues.Description != "Issues in the repository." || issues.Type.String() != "[Issue]" || issues.Directives[0].Argument("reason").Value.Raw != "Use search." {
+		t.Errorf("Unexpected field %+v", issues)
+	}
+	if got := issues.Arguments[0].DefaultValue.String(); got != "{field: CREATED_AT, direction: DESC}" || issues.Arguments[0].Description != "How to order them." {
+		t.Errorf("Unexpected argument default %s", got)
+	}
+
+	if union := doc.Types[1]; union.Kind != "UNION" || len(union.Types) != 2 || union.Types[0] != "Issue" {
+		t.Errorf("Unexpected union %+v", union)
+	}
+	if enum := doc.Types[2]; len(enum.EnumValues) != 2 || enum.EnumValues[1].Description != "Closed." || enum.EnumValues[1].Directives[0].Name != "deprecated" {
+		t.Errorf("Unexpected enum %+v", enum)
+	}
+	if input := doc.Types[3]; input.Kind != "INPUT_OBJECT" || input.InputFields[0].DefaultValue.Raw != "CREATED_AT" || input.Directives[0].Name != "oneOf" {
+		t.Errorf("Unexpected input object %+v", input)
+	}
+	d := doc.Directives[0]
+	if !d.Repeatable || len(d.Locations) != 2 || d.Locations[1] != "ENUM_VALUE" || d.Arguments[0].Description != "The toggle." {
+		t.Errorf("Unexpected directive %+v", d)
+	}
+	if ext := doc.Types[6]; !ext.Extend || ext.Name != "Repository" || len(ext.Fields) != 1 {
+		t.Errorf("Unexpected extension %+v", ext)
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		pos Position
+	}{
+		{"", Position{1, 1}},
+		{"query { a }", Position{1, 1}},
+		{"type Query { a Int }", Position{1, 16}},
+		{`"Doc" extend type Query { a: Int }`, Position{1, 7}},
+		{"extend directive @a on FIELD", Position{1, 8}},
+		{"schema { root: Query }", Position{1, 10}},
+		{"enum E { A null }", Position{1, 12}},
+		{"directive @a(b: Int = $c) on FIELD", Position{1, 23}},
+		{"union U = A |", Position{1, 14}},
+	}
+	for _, tt := range tests {
+		_, err := ParseSchema(tt.src)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseSchema(%q): expected *SyntaxError, got %v", tt.src, err)
+			continue
+		}
+		if syntaxErr.Pos != tt.pos {
+			t.Errorf("ParseSchema(%q): error at %s, want %s: %v", tt.src, syntaxErr.Pos, tt.pos, err)
+		}
+	}
+}
+
+func TestIsSchemaDocument(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{`{"data": {}}`, false},
+		{"  \n{}", false},
+		{"data:\n  __schema: {}", false},
+		{"", false},
+		{"type Query { a: Int }", true},
+		{"\ufeffschema { query: Q }", true},
+		{"# comment\n\n\"\"\"Doc\"\"\"\nscalar A", true},
+		{"directive @a on FIELD", true},
+		{"extend type A { b: Int }", true},
+		{"query { a }", false},
+		{"# only a comment", false},
+	}
+	for _, tt := range tests {
+		if got := IsSchemaDocument(tt.src); got != tt.want {
+			t.Errorf("IsSchemaDocument(%q) = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package graphql
+
+import (
+	"strconv"
+)
+
+// Interface converts the value into the Go representation used by decoded
+// JSON variables: int64, float64, string, bool, nil, []interface{}, and
+// map[string]interface{}. Enum values become strings, and variables are
+// looked up in variables (a missing variable yields nil).
+func (v *Value) Interface(variables map[string]interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	switch v.Kind {
+	case VariableValue:
+		return variables[v.Raw]
+	case IntValue:
+		if n, err := strconv.ParseInt(v.Raw, 10, 64); err == nil {
+			return n
+		}
+		f, _ := strconv.ParseFloat(v.Raw, 64)
+		return f
+	case FloatValue:
+		f, _ := strconv.ParseFloat(v.Raw, 64)
+		return f
+	case StringValue, EnumValue:
+		return v.Raw
+	case BooleanValue:
+		return v.Raw == "true"
+	case ListValue:
+		items := make([]interface{}, len(v.List))
+		for i, item := range v.List {
+			items[i] = item.Interface(variables)
+		}
+		return items
+	case ObjectValue:
+		fields := make(map[string]interface{}, len(v.Fields))
+		for _, f := range v.Fields {
+			fields[f.Name] = f.Value.Interface(variables)
+		}
+		return fields
+	}
+	return nil
+}
+
+// String returns the value in GraphQL notation, as Print writes it
+func (v *Value) String() string {
+	var p printer
+	p.value(v)
+	return p.b.String()
+}
@@ -0,0 +1,130 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+// deprecation is a use of a deprecated schema member
+type deprecation struct {
+	pos         graphql.Position
+	message     string
+	replacement string
+}
+
+// deprecationFinder walks the selections of a document through the schema
+// and collects uses of deprecated fields, arguments, input fields, and enum
+// values. Selections the schema does not define are skipped; they are the
+// concern of Schema.ValidateQuery.
+type deprecationFinder struct {
+	schema *schema.Schema
+	model  *schema.Model
+	found  []deprecation
+}
+
+func findDeprecations(s *schema.Schema, doc *graphql.Document) []deprecation {
+	d := &deprecationFinder{schema: s, model: s.Model()}
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *graphql.OperationDefinition:
+			for _, v := range def.VariableDefinitions {
+				if v.DefaultValue != nil {
+					d.value(v.DefaultValue, v.Type.NamedType())
+				}
+			}
+			if root := d.model.Type(s.RootTypeName(string(def.Operation))); root != nil {
+				d.selectionSet(root, def.SelectionSet)
+			}
+		case *graphql.FragmentDefinition:
+			if t := d.model.Type(def.TypeCondition); t != nil {
+				d.selectionSet(t, def.SelectionSet)
+			}
+		}
+	}
+	return d.found
+}
+
+func (d *deprecationFinder) report(pos graphql.Position, member, reason string) {
+	d.found = append(d.found, deprecation{
+		pos:         pos,
+		message:     fmt.Sprintf("%s is deprecated: %s", member, reason),
+		replacement: schema.DeprecationReplacement(reason),
+	})
+}
+
+func (d *deprecationFinder) selectionSet(parent *schema.Type, set graphql.SelectionSet) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *graphql.Field:
+			d.field(parent, sel)
+		case *graphql.InlineFragment:
+			t := parent
+			if sel.TypeCondition != "" {
+				if t = d.model.Type(sel.TypeCondition); t == nil {
+					continue
+				}
+			}
+			d.selectionSet(t, sel.SelectionSet)
+		}
+	}
+}
+
+func (d *deprecationFinder) field(parent *schema.Type, f *graphql.Field) {
+	def := parent.Field(f.Name)
+	if def == nil {
+		return
+	}
+	if def.IsDeprecated {
+		d.report(f.Pos, fmt.Sprintf("field %s.%s", parent.Name, f.Name), def.DeprecationReason)
+	}
+	for _, a := range f.Arguments {
+		for _, arg := range def.Args {
+			if arg.Name != a.Name {
+				continue
+			}
+			if arg.IsDeprecated {
+				d.report(a.Pos, fmt.Sprintf("argument %s.%s(%s:)", parent.Name, f.Name, a.Name), arg.DeprecationReason)
+			}
+			d.value(a.Value, arg.Type.NamedType())
+		}
+	}
+	if t := d.model.Type(def.Type.NamedType()); t != nil && len(f.SelectionSet) > 0 {
+		d.selectionSet(t, f.SelectionSet)
+	}
+}
+
+// value checks a literal given where the named type typeName, or a list of
+// it, is expected
+func (d *deprecationFinder) value(val *graphql.Value, typeName string) {
+	if val.Kind == graphql.ListValue {
+		for _, item := range val.List {
+			d.value(item, typeName)
+		}
+		return
+	}
+	t := d.model.Type(typeName)
+	if t == nil {
+		return
+	}
+	switch {
+	case t.Kind == "ENUM" && val.Kind == graphql.EnumValue:
+		for _, e := range t.EnumValues {
+			if e.Name == val.Raw && e.IsDeprecated {
+				d.report(val.Pos, fmt.Sprintf("enum value %s.%s", t.Name, e.Name), e.DeprecationReason)
+			}
+		}
+	case t.Kind == "INPUT_OBJECT" && val.Kind == graphql.ObjectValue:
+		for _, f := range val.Fields {
+			def := t.InputField(f.Name)
+			if def == nil {
+				continue
+			}
+			if def.IsDeprecated {
+				d.report(f.Pos, fmt.Sprintf("input field %s.%s", t.Name, f.Name), def.DeprecationReason)
+			}
+			d.value(f.Value, def.Type.NamedType())
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// Package lint checks the conventions of GraphQL operation documents, so
+// large collections of operations stay consistent:
+//
+//   - named-operation: every operation has a name
+//   - file-name: the operation of a single-operation file is named after the
+//     file, so "get_issues.graphql" and "GetIssues.graphql" hold GetIssues
+//   - variable-case: variables are camelCase
+//   - header: the file starts with a comment block containing every required
+//     "# key: value" line, such as an owner or a ticket
+//   - deprecated-usage: no deprecated field, argument, input field, or enum
+//     value of Config.Schema is used; findings carry the deprecation reason
+//     and the replacement it suggests
+//
+// Rules are run on each file with Lint:
+//
+//	l, err := lint.New(lint.Config{RequiredHeaders: []string{"owner", "ticket"}})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	findings, err := l.Lint("queries/get_issues.graphql", src)
+//	if err != nil {
+//		log.Fatal(err) // *graphql.SyntaxError
+//	}
+//	for _, f := range findings {
+//		fmt.Println(f) // queries/get_issues.graphql:1:1: missing "# ticket:" header (header)
+//	}
+//
+// LintGo applies the rules to operations embedded in the string literals of
+// Go source files.
+package lint
@@ -0,0 +1,239 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+// Rule identifies a lint rule
+type Rule string
+
+const (
+	NamedOperation  Rule = "named-operation"
+	FileName        Rule = "file-name"
+	VariableCase    Rule = "variable-case"
+	Header          Rule = "header"
+	DeprecatedUsage Rule = "deprecated-usage"
+)
+
+// Rules lists every rule in the order findings are reported
+var Rules = []Rule{Header, NamedOperation, FileName, VariableCase, DeprecatedUsage}
+
+// Config selects the rules to run
+type Config struct {
+	// RequiredHeaders are keys that must appear in the leading comment block
+	// as "# key: value" lines with a value, matched case-insensitively. The
+	// header rule is skipped when there are none.
+	RequiredHeaders []string
+	// Schema is checked for deprecated fields, arguments, input fields, and
+	// enum values used by the operations. The deprecated-usage rule is
+	// skipped without one.
+	Schema *schema.Schema
+	// Disabled rules are not run
+	Disabled []Rule
+}
+
+// Finding is a violation of a rule
+type Finding struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Rule    Rule   `json:"rule"`
+	Message string `json:"message"`
+	// Replacement is the member the deprecation reason suggests instead, for
+	// deprecated-usage findings
+	Replacement string `json:"replacement,omitempty"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s (%s)", f.File, f.Line, f.Column, f.Message, f.Rule)
+}
+
+// Linter checks operation documents against a Config
+type Linter struct {
+	config   Config
+	disabled map[Rule]bool
+}
+
+// New creates a Linter. It returns an error for unknown rules in
+// config.Disabled.
+func New(config Config) (*Linter, error) {
+	l := &Linter{config: config, disabled: make(map[Rule]bool)}
+	for _, r := range config.Disabled {
+		if !isRule(r) {
+			return nil, fmt.Errorf("unknown lint rule %q", r)
+		}
+		l.disabled[r] = true
+	}
+	return l, nil
+}
+
+func isRule(r Rule) bool {
+	for _, known := range Rules {
+		if r == known {
+			return true
+		}
+	}
+	return false
+}
+
+// camelCase matches names such as "owner" and "pullRequestId"
+var camelCase = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+
+// Lint parses src, the contents of file, and returns its findings in rule
+// order. Syntax errors are returned as errors.
+func (l *Linter) Lint(file, src string) ([]Finding, error) {
+	doc, err := graphql.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return l.lint(file, src, doc, true), nil
+}
+
+// lint runs the rules on a parsed document. The header and file-name rules
+// only apply when the document is a file of its own.
+func (l *Linter) lint(file, src string, doc *graphql.Document, wholeFile bool) []Finding {
+	var findings []Finding
+	report := func(r Rule, pos graphql.Position, format string, args ...interface{}) {
+		if !l.disabled[r] {
+			findings = append(findings, Finding{File: file, Line: pos.Line, Column: pos.Column, Rule: r, Message: fmt.Sprintf(format, args...)})
+		}
+	}
+	start := graphql.Position{Line: 1, Column: 1}
+
+	if wholeFile && len(l.config.RequiredHeaders) > 0 {
+		headers := parseHeaders(src)
+		for _, key := range l.config.RequiredHeaders {
+			if headers[strings.ToLower(key)] == "" {
+				report(Header, start, "missing %q header", "# "+key+":")
+			}
+		}
+	}
+
+	ops := doc.Operations()
+	for _, op := range ops {
+		if op.Name == "" {
+			report(NamedOperation, op.Pos, "%s operation has no name", op.Operation)
+		}
+	}
+
+	if wholeFile && len(ops) == 1 && ops[0].Name != "" {
+		base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if normalize(base) != normalize(ops[0].Name) {
+			report(FileName, ops[0].Pos, "operation %s does not match file name %s", ops[0].Name, filepath.Base(file))
+		}
+	}
+
+	for _, op := range ops {
+		for _, v := range op.VariableDefinitions {
+			if !camelCase.MatchString(v.Name) {
+				report(VariableCase, v.Pos, "variable $%s is not camelCase", v.Name)
+			}
+		}
+	}
+
+	if l.config.Schema != nil && !l.disabled[DeprecatedUsage] {
+		for _, d := range findDeprecations(l.config.Schema, doc) {
+			findings = append(findings, Finding{
+				File: file, Line: d.pos.Line, Column: d.pos.Column, Rule: DeprecatedUsage,
+				Message: d.message, Replacement: d.replacement,
+			})
+		}
+	}
+	return findings
+}
+
+// LintGo finds the GraphQL operations embedded in the string literals of src,
+// the contents of the Go source file file, and returns their findings. A
+// literal is linted when it starts with a query, mutation, subscription, or
+// fragment definition and parses as a document; the shorthand "{ ... }" form
+// is not recognized. The header and file-name rules do not apply. Findings
+// are placed at their position in the Go file, or at the literal when it
+// contains escape sequences. A file that is not valid Go is returned as an
+// error.
+func (l *Linter) LintGo(file, src string) ([]Finding, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	ast.Inspect(f, func(n ast.Node) bool {
+		lit, ok := n.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		text, err := strconv.Unquote(lit.Value)
+		if err != nil || !startsWithDefinition(text) {
+			return true
+		}
+		doc, err := graphql.Parse(text)
+		if err != nil {
+			return true
+		}
+
+		pos := fset.Position(lit.ValuePos)
+		exact := strings.HasPrefix(lit.Value, "`") || !strings.Contains(lit.Value, `\`)
+		for _, finding := range l.lint(file, text, doc, false) {
+			switch {
+			case !exact:
+				finding.Line, finding.Column = pos.Line, pos.Column
+			case finding.Line == 1:
+				// The text starts after the opening quote
+				finding.Line, finding.Column = pos.Line, pos.Column+finding.Column
+			default:
+				finding.Line += pos.Line - 1
+			}
+			findings = append(findings, finding)
+		}
+		return true
+	})
+	return findings, nil
+}
+
+// definitionStart matches text beginning with an operation or fragment
+// definition, after whitespace and comments
+var definitionStart = regexp.MustCompile(`^(?:\s|#[^\n]*)*(?:query|mutation|subscription|fragment)\b`)
+
+func startsWithDefinition(text string) bool {
+	return definitionStart.MatchString(text)
+}
+
+// parseHeaders returns the "# key: value" lines of the comment block at the
+// start of src, keyed by lowercased key
+func parseHeaders(src string) map[string]string {
+	headers := make(map[string]string)
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		comment, ok := strings.CutPrefix(line, "#")
+		if !ok {
+			break
+		}
+		if key, value, ok := strings.Cut(comment, ":"); ok {
+			key = strings.ToLower(strings.TrimSpace(key))
+			if _, seen := headers[key]; !seen {
+				headers[key] = strings.TrimSpace(value)
+			}
+		}
+	}
+	return headers
+}
+
+// normalize drops separators and case, so get_issues, get-issues, and
+// GetIssues compare equal
+func normalize(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "").Replace(name))
+}
@@ -0,0 +1,187 @@
+package lint
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+func TestLint(t *testing.T) {
+	l, err := New(Config{RequiredHeaders: []string{"owner", "Ticket"}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	tests := []struct {
+		name, file, src string
+		want            []Finding
+	}{
+		{
+			name: "clean",
+			file: "queries/get_issues.graphql",
+			src: `# owner: @octo-org/issues
+# ticket: ISSUE-42
+
+query GetIssues($owner: String!, $pageSize: Int) { viewer { login } }
+`,
+		},
+		{
+			name: "fragments only",
+			file: "fragments.graphql",
+			src:  "# Owner: @octo-org/issues\n# ticket: ISSUE-42\nfragment F on Issue { id }\n",
+		},
+		{
+			name: "violations",
+			file: "issues.gql",
+			src: `# owner: @octo-org/issues
+# ticket:
+query GetIssues($repo_owner: String!, $Name: String!) { viewer { login } }
+`,
+			want: []Finding{
+				{File: "issues.gql", Line: 1, Column: 1, Rule: Header, Message: `missing "# Ticket:" header`},
+				{File: "issues.gql", Line: 3, Column: 1, Rule: FileName, Message: "operation GetIssues does not match file name issues.gql"},
+				{File: "issues.gql", Line: 3, Column: 17, Rule: VariableCase, Message: "variable $repo_owner is not camelCase"},
+				{File: "issues.gql", Line: 3, Column: 39, Rule: VariableCase, Message: "variable $Name is not camelCase"},
+			},
+		},
+		{
+			name: "headers after the first definition do not count",
+			file: "a.graphql",
+			src:  "# owner: me\nquery A { viewer { login } }\n# ticket: X-1\nmutation { addStar(input: {}) { clientMutationId } }\n",
+			want: []Finding{
+				{File: "a.graphql", Line: 1, Column: 1, Rule: Header, Message: `missing "# Ticket:" header`},
+				{File: "a.graphql", Line: 4, Column: 1, Rule: NamedOperation, Message: "mutation operation has no name"},
+			},
+		},
+		{
+			name: "anonymous shorthand",
+			file: "viewer.graphql",
+			src:  "# owner: me\n# ticket: X-1\n{ viewer { login } }",
+			want: []Finding{
+				{File: "viewer.graphql", Line: 3, Column: 1, Rule: NamedOperation, Message: "query operation has no name"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := l.Lint(tt.file, tt.src)
+			if err != nil {
+				t.Fatalf("Lint failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := l.Lint("bad.graphql", "query {"); err == nil {
+		t.Error("Expected syntax error")
+	}
+}
+
+func TestLintDisabled(t *testing.T) {
+	if _, err := New(Config{Disabled: []Rule{"no-such-rule"}}); err == nil {
+		t.Error("Expected error for unknown rule")
+	}
+
+	l, err := New(Config{Disabled: []Rule{FileName, VariableCase}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	got, err := l.Lint("other.graphql", "query GetIssues($Bad: Int) { viewer { login } }")
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Expected no findings without required headers and with rules disabled, got %+v", got)
+	}
+}
+
+func TestLintDeprecatedUsage(t *testing.T) {
+	s, err := schema.NewWithFile(filepath.Join("..", "schema", "testdata", "synthetic.json"))
+	if err != nil {
+		t.Fatalf("Failed to load synthetic schema: %v", err)
+	}
+	l, err := New(Config{Schema: s})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	src := `query Templates($states: [IssueState!] = [LOCKED]) {
+  repository(owner: "o", name: "n") {
+    ...Template
+    issues(first: 1, states: [OPEN, LOCKED]) { totalCount }
+  }
+}
+
+mutation CreateIssue {
+  createIssue(input: {repositoryId: "R", title: "t", metadata: {parent: {url: "u"}}}) { clientMutationId }
+}
+
+fragment Template on Repository { isTemplateRepo undefinedField }
+`
+	got, err := l.Lint("templates.graphql", src)
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	want := []Finding{
+		{File: "templates.graphql", Line: 1, Column: 43, Rule: DeprecatedUsage, Message: "enum value IssueState.LOCKED is deprecated: Locking is now tracked by `Issue.locked`."},
+		{File: "templates.graphql", Line: 4, Column: 37, Rule: DeprecatedUsage, Message: "enum value IssueState.LOCKED is deprecated: Locking is now tracked by `Issue.locked`."},
+		{File: "templates.graphql", Line: 9, Column: 74, Rule: DeprecatedUsage, Message: "input field IssueLocatorInput.url is deprecated: Use `id` or `number` instead.", Replacement: "id"},
+		{File: "templates.graphql", Line: 12, Column: 35, Rule: DeprecatedUsage, Message: "field Repository.isTemplateRepo is deprecated: Use `Repository.isTemplate` instead. Removal on 2025-01-01 UTC.", Replacement: "Repository.isTemplate"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	l, err = New(Config{Schema: s, Disabled: []Rule{DeprecatedUsage}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if got, _ := l.Lint("templates.graphql", src); len(got) != 0 {
+		t.Errorf("Expected no findings with deprecated-usage disabled, got %+v", got)
+	}
+}
+
+func TestLintGo(t *testing.T) {
+	s, err := schema.NewWithFile(filepath.Join("..", "schema", "testdata", "synthetic.json"))
+	if err != nil {
+		t.Fatalf("Failed to load synthetic schema: %v", err)
+	}
+	l, err := New(Config{Schema: s, RequiredHeaders: []string{"owner"}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	src := "package client\n" +
+		"\n" +
+		"const templateQuery = `\n" +
+		"query Template($Owner: String!) {\n" +
+		"  repository(owner: $Owner, name: \"n\") { isTemplateRepo }\n" +
+		"}`\n" +
+		"\n" +
+		"var inline = \"query { viewer { login } }\"\n" +
+		"var escaped = \"query Q {\\n  repository(owner: \\\"o\\\", name: \\\"n\\\") { isTemplateRepo }\\n}\"\n" +
+		"var prose = \"query failed: %v\"\n" +
+		"var shorthand = \"{ viewer { login } }\"\n"
+	got, err := l.LintGo("client.go", src)
+	if err != nil {
+		t.Fatalf("LintGo failed: %v", err)
+	}
+	reason := "Use `Repository.isTemplate` instead. Removal on 2025-01-01 UTC."
+	want := []Finding{
+		{File: "client.go", Line: 4, Column: 16, Rule: VariableCase, Message: "variable $Owner is not camelCase"},
+		{File: "client.go", Line: 5, Column: 42, Rule: DeprecatedUsage, Message: "field Repository.isTemplateRepo is deprecated: " + reason, Replacement: "Repository.isTemplate"},
+		{File: "client.go", Line: 8, Column: 15, Rule: NamedOperation, Message: "query operation has no name"},
+		{File: "client.go", Line: 9, Column: 15, Rule: DeprecatedUsage, Message: "field Repository.isTemplateRepo is deprecated: " + reason, Replacement: "Repository.isTemplate"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	if _, err := l.LintGo("bad.go", "package"); err == nil {
+		t.Error("Expected error for invalid Go source")
+	}
+}
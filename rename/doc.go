@@ -0,0 +1,23 @@
+// Package rename simulates renaming types and fields of a custom schema, for
+// teams maintaining internal extensions of GitHub's schema. A Map is applied
+// to the schema document and to the operations that query it at the same
+// time, so that both stay consistent:
+//
+//	r, err := rename.New(s, rename.Map{
+//		"Issue":       "Ticket",
+//		"Issue.title": "headline",
+//	}, rename.Options{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	doc, err := r.Schema()                    // the renamed introspection document
+//	out, n, issues, err := r.Operations(src) // src with n names rewritten
+//
+// Operations are rewritten in place: only the renamed names change, so
+// comments and formatting are kept. Anything a rename cannot be applied to
+// is reported as an Issue instead of being guessed at, such as entries naming
+// types or fields the schema does not have, interface fields renamed
+// differently from their implementations, fields selected through an
+// interface that is not renamed with them, and input fields renamed under a
+// variable whose value is built elsewhere.
+package rename
@@ -0,0 +1,506 @@
+package rename
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/apstndb/github-schema-go/schema/introspection"
+	"github.com/apstndb/go-yamlformat"
+)
+
+// Map renames types and fields. Keys are type names, or "Type.field" for
+// fields of object and interface types and input fields of input objects;
+// values are the new names. Fields are keyed by the current name of their
+// type, even when the type is renamed too.
+type Map map[string]string
+
+// ParseMap decodes a Map from a YAML or JSON object
+func ParseMap(data []byte) (Map, error) {
+	var m Map
+	if err := yamlformat.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse rename map: %w", err)
+	}
+	return m, nil
+}
+
+// Issue is a rename that could not be applied, or that would leave the
+// schema or the operations inconsistent. File, Line, and Column locate
+// issues found in operations; Operations leaves File to the caller.
+type Issue struct {
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Rename  string `json:"rename,omitempty"` // Key of the Map entry involved
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	if i.Line == 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+}
+
+// Options configures a Renamer
+type Options struct {
+	// KeepResponseKeys aliases renamed fields that have no alias to their
+	// old name, so that responses keep their shape and the code reading
+	// them needs no change
+	KeepResponseKeys bool
+}
+
+// Renamer applies a Map to a schema and to operations against it
+type Renamer struct {
+	schema *schema.Schema
+	model  *schema.Model
+	opts   Options
+
+	types  map[string]string            // Old type name to new
+	fields map[string]map[string]string // Old type name to old field name to new
+	issues []Issue
+}
+
+var namePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// builtinScalars are defined by the GraphQL specification
+var builtinScalars = map[string]bool{"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true}
+
+// New prepares the renames of m for the schema s. Malformed entries, such
+// as ones whose names are not GraphQL names, fail; entries the schema cannot
+// take, such as unknown types or names that are already taken, are left out
+// and reported by Issues.
+func New(s *schema.Schema, m Map, opts Options) (*Renamer, error) {
+	r := &Renamer{
+		schema: s,
+		model:  s.Model(),
+		opts:   opts,
+		types:  make(map[string]string),
+		fields: make(map[string]map[string]string),
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		to := m[key]
+		typeName, fieldName, isField := strings.Cut(key, ".")
+		if !namePattern.MatchString(typeName) || isField && !namePattern.MatchString(fieldName) {
+			return nil, fmt.Errorf("invalid rename %q: want Type or Type.field", key)
+		}
+		if !namePattern.MatchString(to) {
+			return nil, fmt.Errorf("invalid new name %q for %q", to, key)
+		}
+		if to == typeName && !isField || to == fieldName && isField {
+			continue
+		}
+		if isField {
+			r.addField(key, typeName, fieldName, to)
+		} else {
+			r.addType(key, typeName, to)
+		}
+	}
+	r.checkTaken()
+	r.checkInterfaces()
+	return r, nil
+}
+
+func (r *Renamer) report(key, format string, args ...interface{}) {
+	r.issues = append(r.issues, Issue{Rename: key, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *Renamer) addType(key, from, to string) {
+	switch {
+	case r.model.Type(from) == nil:
+		r.report(key, "type %q not found", from)
+	case strings.HasPrefix(from, "__") || strings.HasPrefix(to, "__") || builtinScalars[from]:
+		r.report(key, "type %q is built in and cannot be renamed", from)
+	default:
+		r.types[from] = to
+	}
+}
+
+func (r *Renamer) addField(key, typeName, from, to string) {
+	t := r.model.Type(typeName)
+	switch {
+	case t == nil:
+		r.report(key, "type %q not found", typeName)
+		return
+	case t.Field(from) == nil && t.InputField(from) == nil:
+		r.report(key, "field %q not found on type %q", from, typeName)
+		return
+	case strings.HasPrefix(to, "__"):
+		r.report(key, "field names starting with __ are reserved")
+		return
+	}
+	if r.fields[typeName] == nil {
+		r.fields[typeName] = make(map[string]string)
+	}
+	r.fields[typeName][from] = to
+}
+
+// checkTaken drops renames to names that remain taken after the renames,
+// reporting them
+func (r *Renamer) checkTaken() {
+	taken := make(map[string][]string)
+	for _, t := range r.model.Types {
+		name := r.typeName(t.Name)
+		taken[name] = append(taken[name], t.Name)
+	}
+	for from, to := range r.types {
+		if len(taken[to]) > 1 {
+			r.report(from, "type %q cannot be renamed to %q: %q has that name after the renames", from, to, other(taken[to], from))
+			delete(r.types, from)
+		}
+	}
+
+	for typeName, renames := range r.fields {
+		t := r.model.Type(typeName)
+		taken := make(map[string][]string)
+		for _, f := range t.Fields {
+			name := r.fieldName(typeName, f.Name)
+			taken[name] = append(taken[name], f.Name)
+		}
+		for _, f := range t.InputFields {
+			name := r.fieldName(typeName, f.Name)
+			taken[name] = append(taken[name], f.Name)
+		}
+		for from, to := range renames {
+			if len(taken[to]) > 1 {
+				key := typeName + "." + from
+				r.report(key, "field %q cannot be renamed to %q: %s.%s has that name after the renames", key, to, typeName, other(taken[to], from))
+				delete(renames, from)
+			}
+		}
+	}
+	sortIssues(r.issues)
+}
+
+// other returns the first of names that is not self
+func other(names []string, self string) string {
+	for _, name := range names {
+		if name != self {
+			return name
+		}
+	}
+	return self
+}
+
+// checkInterfaces reports fields renamed differently on an interface and on
+// the types implementing it, since they have to keep matching
+func (r *Renamer) checkInterfaces() {
+	var issues []Issue
+	for _, t := range r.model.Types {
+		for _, name := range t.Interfaces {
+			iface := r.model.Type(name)
+			if iface == nil {
+				continue
+			}
+			for _, f := range iface.Fields {
+				if t.Field(f.Name) == nil {
+					continue
+				}
+				ifaceName, implName := r.fieldName(iface.Name, f.Name), r.fieldName(t.Name, f.Name)
+				if ifaceName == implName {
+					continue
+				}
+				key := iface.Name + "." + f.Name
+				if ifaceName == f.Name {
+					key = t.Name + "." + f.Name
+				}
+				issues = append(issues, Issue{Rename: key, Message: fmt.Sprintf(
+					"%s.%s implements %s.%s, but they would be named %q and %q; rename both",
+					t.Name, f.Name, iface.Name, f.Name, implName, ifaceName)})
+			}
+		}
+	}
+	sortIssues(issues)
+	r.issues = append(r.issues, issues...)
+}
+
+func sortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Rename < issues[j].Rename })
+}
+
+// Issues returns the problems with the Map found by New: entries left out,
+// and renames that leave interfaces and their implementations inconsistent
+func (r *Renamer) Issues() []Issue {
+	return r.issues
+}
+
+// Renames returns the entries of the Map that are applied, without the
+// ones Issues reports as left out
+func (r *Renamer) Renames() Map {
+	m := make(Map)
+	for from, to := range r.types {
+		m[from] = to
+	}
+	for typeName, renames := range r.fields {
+		for from, to := range renames {
+			m[typeName+"."+from] = to
+		}
+	}
+	return m
+}
+
+func (r *Renamer) typeName(name string) string {
+	if to, ok := r.types[name]; ok {
+		return to
+	}
+	return name
+}
+
+func (r *Renamer) fieldName(typeName, name string) string {
+	if to, ok := r.fields[typeName][name]; ok {
+		return to
+	}
+	return name
+}
+
+// Schema returns the introspection document of the schema with the renames
+// applied to type definitions, fields, input fields, and every reference to
+// a renamed type. Descriptions are left as they are.
+func (r *Renamer) Schema() (*introspection.Document, error) {
+	doc, err := r.schema.Introspection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode schema: %w", err)
+	}
+	s := &doc.Data.Schema
+	for _, root := range []*introspection.RootType{s.QueryType, s.MutationType, s.SubscriptionType} {
+		if root != nil {
+			root.Name = r.typeName(root.Name)
+		}
+	}
+	for i := range s.Types {
+		t := &s.Types[i]
+		old := t.Name
+		t.Name = r.typeName(old)
+		for j := range t.Fields {
+			f := &t.Fields[j]
+			f.Name = r.fieldName(old, f.Name)
+			r.renameRef(&f.Type)
+			r.renameInputValues(f.Args)
+		}
+		for j := range t.InputFields {
+			t.InputFields[j].Name = r.fieldName(old, t.InputFields[j].Name)
+		}
+		r.renameInputValues(t.InputFields)
+		for j := range t.Interfaces {
+			r.renameRef(&t.Interfaces[j])
+		}
+		for j := range t.PossibleTypes {
+			r.renameRef(&t.PossibleTypes[j])
+		}
+	}
+	for i := range s.Directives {
+		r.renameInputValues(s.Directives[i].Args)
+	}
+	return doc, nil
+}
+
+func (r *Renamer) renameInputValues(values []introspection.InputValue) {
+	for i := range values {
+		r.renameRef(&values[i].Type)
+	}
+}
+
+func (r *Renamer) renameRef(ref *introspection.TypeRef) {
+	for ; ref != nil; ref = ref.OfType {
+		if ref.Name != nil {
+			name := r.typeName(*ref.Name)
+			ref.Name = &name
+		}
+	}
+}
+
+// edit replaces the name at offset
+type edit struct {
+	offset int
+	old    string
+	new    string
+}
+
+// rewriter collects the edits and issues of one document
+type rewriter struct {
+	*Renamer
+	lines  []int // Offsets of the line starts
+	edits  []edit
+	issues []Issue
+}
+
+// Operations rewrites the operations and fragments of the GraphQL document
+// src: type conditions, variable types, selected fields, and input fields of
+// object literals. It returns the rewritten source and the number of names
+// rewritten, with the issues found; fields the schema does not define are
+// reported and left as they are. Only syntax errors fail.
+func (r *Renamer) Operations(src string) (string, int, []Issue, error) {
+	doc, err := graphql.Parse(src)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	w := &rewriter{Renamer: r, lines: []int{0}}
+	for i := 0; i < len(src); i++ {
+		if src[i] == '\n' {
+			w.lines = append(w.lines, i+1)
+		}
+	}
+
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *graphql.OperationDefinition:
+			root := r.schema.RootTypeName(string(def.Operation))
+			if root == "" {
+				w.report(def.Pos, "", "schema does not support %s operations", def.Operation)
+				continue
+			}
+			for _, v := range def.VariableDefinitions {
+				w.variable(v)
+			}
+			w.selectionSet(root, def.SelectionSet)
+		case *graphql.FragmentDefinition:
+			w.typeCondition(def.TypeCondition, def.TypeConditionPos)
+			w.selectionSet(def.TypeCondition, def.SelectionSet)
+		}
+	}
+
+	sort.Slice(w.edits, func(i, j int) bool { return w.edits[i].offset > w.edits[j].offset })
+	out := src
+	for _, e := range w.edits {
+		out = out[:e.offset] + e.new + out[e.offset+len(e.old):]
+	}
+	return out, len(w.edits), w.issues, nil
+}
+
+func (w *rewriter) report(pos graphql.Position, key, format string, args ...interface{}) {
+	w.issues = append(w.issues, Issue{Line: pos.Line, Column: pos.Column, Rename: key, Message: fmt.Sprintf(format, args...)})
+}
+
+func (w *rewriter) replace(pos graphql.Position, old, new string) {
+	w.edits = append(w.edits, edit{offset: w.lines[pos.Line-1] + pos.Column - 1, old: old, new: new})
+}
+
+func (w *rewriter) typeCondition(name string, pos graphql.Position) {
+	if to, ok := w.types[name]; ok {
+		w.replace(pos, name, to)
+	}
+}
+
+func (w *rewriter) variable(v *graphql.VariableDefinition) {
+	t := v.Type
+	for t.Elem != nil {
+		t = t.Elem
+	}
+	w.typeCondition(t.Name, t.Pos)
+	// The value of a variable is built by the caller of the operation, out
+	// of reach of the rewrite
+	if key, to := w.inputRename(t.Name, make(map[string]bool)); key != "" {
+		w.report(v.Pos, key, "input field %s is renamed to %q, but $%s takes it from the variables; update the code building them",
+			key, to, v.Name)
+	}
+}
+
+// inputRename returns the first renamed input field reachable from the
+// input object typeName, as a Map key, and its new name; the key is "" when
+// there is none
+func (w *rewriter) inputRename(typeName string, seen map[string]bool) (string, string) {
+	t := w.model.Type(typeName)
+	if t == nil || t.Kind != "INPUT_OBJECT" || seen[typeName] {
+		return "", ""
+	}
+	seen[typeName] = true
+	for _, f := range t.InputFields {
+		if to, ok := w.fields[typeName][f.Name]; ok {
+			return typeName + "." + f.Name, to
+		}
+		if key, to := w.inputRename(f.Type.NamedType(), seen); key != "" {
+			return key, to
+		}
+	}
+	return "", ""
+}
+
+func (w *rewriter) selectionSet(typeName string, set graphql.SelectionSet) {
+	t := w.model.Type(typeName)
+	if t == nil {
+		return
+	}
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *graphql.Field:
+			w.field(t, sel)
+		case *graphql.InlineFragment:
+			condition := typeName
+			if sel.TypeCondition != "" {
+				condition = sel.TypeCondition
+				w.typeCondition(condition, sel.TypeConditionPos)
+			}
+			w.selectionSet(condition, sel.SelectionSet)
+		}
+	}
+}
+
+func (w *rewriter) field(t *schema.Type, sel *graphql.Field) {
+	if strings.HasPrefix(sel.Name, "__") {
+		return
+	}
+	f := t.Field(sel.Name)
+	if f == nil {
+		w.report(sel.NamePos, "", "field %q not found on type %q; left as it is", sel.Name, t.Name)
+		return
+	}
+	if to, ok := w.fields[t.Name][sel.Name]; ok {
+		replacement := to
+		if w.opts.KeepResponseKeys && sel.Alias == "" {
+			replacement = sel.Name + ": " + to
+		}
+		w.replace(sel.NamePos, sel.Name, replacement)
+	} else if t.Kind == "INTERFACE" {
+		// Renaming the field on only some implementations cannot be
+		// applied to a selection through the interface
+		for _, name := range t.PossibleTypes {
+			if to, ok := w.fields[name][sel.Name]; ok {
+				key := name + "." + sel.Name
+				w.report(sel.NamePos, key, "%s is selected through %s, which keeps the name; select it in \"... on %s\" to rename it to %q",
+					key, t.Name, name, to)
+			}
+		}
+	}
+
+	for _, arg := range sel.Arguments {
+		if a := f.Arg(arg.Name); a != nil {
+			w.value(arg.Value, a.Type)
+		}
+	}
+	if len(sel.SelectionSet) > 0 {
+		w.selectionSet(f.Type.NamedType(), sel.SelectionSet)
+	}
+}
+
+// value renames the input fields of object literals in v, of type ref
+func (w *rewriter) value(v *graphql.Value, ref *schema.TypeRef) {
+	typeName := ref.NamedType()
+	switch v.Kind {
+	case graphql.ListValue:
+		for _, item := range v.List {
+			w.value(item, ref)
+		}
+	case graphql.ObjectValue:
+		t := w.model.Type(typeName)
+		if t == nil {
+			return
+		}
+		for _, of := range v.Fields {
+			if to, ok := w.fields[typeName][of.Name]; ok {
+				w.replace(of.Pos, of.Name, to)
+			}
+			if f := t.InputField(of.Name); f != nil {
+				w.value(of.Value, f.Type)
+			}
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package rename
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/apstndb/github-schema-go/schema/introspection"
+)
+
+func newRenamer(t *testing.T, m Map, opts Options) *Renamer {
+	t.Helper()
+	s, err := schema.NewWithFile(filepath.Join("..", "schema", "testdata", "synthetic.json"))
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	r, err := New(s, m, opts)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return r
+}
+
+func messages(issues []Issue) string {
+	var b strings.Builder
+	for _, i := range issues {
+		b.WriteString(i.String() + "\n")
+	}
+	return b.String()
+}
+
+func TestNewIssues(t *testing.T) {
+	r := newRenamer(t, Map{
+		"Issue":             "Ticket",
+		"Missing":           "Other",
+		"String":            "Text",
+		"PullRequest":       "User",
+		"Issue.nope":        "yes",
+		"Issue.title":       "number",
+		"Node.id":           "nodeId",
+		"Repository.id":     "nodeId",
+		"CreateIssueInput":  "CreateIssueInput",
+		"IssueLocatorInput": "TicketLocator",
+	}, Options{})
+
+	want := []string{
+		`Issue.id implements Node.id, but they would be named "id" and "nodeId"; rename both`,
+		`field "Issue.title" cannot be renamed to "number": Issue.number has that name after the renames`,
+		`field "nope" not found on type "Issue"`,
+		`type "Missing" not found`,
+		`type "PullRequest" cannot be renamed to "User": "User" has that name after the renames`,
+		`type "String" is built in and cannot be renamed`,
+	}
+	got := messages(r.Issues())
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("Issues do not contain %q:\n%s", w, got)
+		}
+	}
+	if strings.Contains(got, "Repository.id implements") {
+		t.Errorf("Expected Repository.id to be consistent with Node.id:\n%s", got)
+	}
+	renames := r.Renames()
+	if len(renames) != 4 || renames["Issue"] != "Ticket" || renames["Repository.id"] != "nodeId" {
+		t.Errorf("Renames = %v", renames)
+	}
+
+	s, _ := schema.NewWithFile(filepath.Join("..", "schema", "testdata", "synthetic.json"))
+	for _, m := range []Map{{"Issue.": "x"}, {"Issue": "not-a-name"}, {"a.b.c": "d"}} {
+		if _, err := New(s, m, Options{}); err == nil {
+			t.Errorf("Expected an error for %v", m)
+		}
+	}
+}
+
+func TestSchema(t *testing.T) {
+	r := newRenamer(t, Map{
+		"Issue":                       "Ticket",
+		"Issue.title":                 "headline",
+		"IssueState":                  "TicketState",
+		"CreateIssueInput.title":      "headline",
+		"Node.id":                     "nodeId",
+		"Repository.id":               "nodeId",
+		"Issue.id":                    "nodeId",
+		"PullRequest.id":              "nodeId",
+		"User.id":                     "nodeId",
+		"Query":                       "RootQuery",
+		"IssueMetadataInput.priority": "urgency",
+	}, Options{})
+	if issues := r.Issues(); len(issues) != 0 {
+		t.Fatalf("Unexpected issues:\n%s", messages(issues))
+	}
+	doc, err := r.Schema()
+	if err != nil {
+		t.Fatalf("Schema failed: %v", err)
+	}
+	data, err := introspection.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := schema.NewWithDataStrict(data)
+	if err != nil {
+		t.Fatalf("Renamed schema is invalid: %v", err)
+	}
+
+	if _, err := s.LookupType("Issue"); err == nil {
+		t.Error("Expected Issue to be renamed")
+	}
+	if f, err := s.Field("Ticket", "headline"); err != nil || f.Type != "String!" {
+		t.Errorf("Ticket.headline = %+v, %v", f, err)
+	}
+	if f, err := s.Field("Ticket", "state"); err != nil || f.Type != "TicketState!" {
+		t.Errorf("Ticket.state = %+v, %v", f, err)
+	}
+	if s.RootTypeName("query") != "RootQuery" {
+		t.Errorf("Query root is %q", s.RootTypeName("query"))
+	}
+	if members, err := s.UnionMembers("IssueOrPullRequest"); err != nil || members[0] != "Ticket" {
+		t.Errorf("IssueOrPullRequest members = %v, %v", members, err)
+	}
+	model := s.Model()
+	if model.Type("CreateIssueInput").InputField("headline") == nil || model.Type("IssueMetadataInput").InputField("urgency") == nil {
+		t.Error("Expected input fields to be renamed")
+	}
+}
+
+func TestOperations(t *testing.T) {
+	r := newRenamer(t, Map{
+		"Issue":                       "Ticket",
+		"Issue.title":                 "headline",
+		"IssueState":                  "TicketState",
+		"CreateIssueInput.title":      "headline",
+		"IssueMetadataInput.priority": "urgency",
+		"Repository.issues":           "tickets",
+	}, Options{})
+
+	src := `# Keeps comments
+query Issues($states: [IssueState!], $n: Int) {
+  repository(owner: "o", name: "n") {
+    recent: issues(first: $n, states: $states) {
+      nodes { ...IssueFields }
+    }
+    issueOrPullRequest(number: 1) {
+      ... on Issue { title }
+      ... on PullRequest { title }
+    }
+  }
+}
+
+fragment IssueFields on Issue {
+  number
+  heading: title
+  missing
+}
+
+mutation Create($input: CreateIssueInput!) {
+  createIssue(input: {repositoryId: "R", title: "t", metadata: {priority: 1}}) { issue { id } }
+  again: createIssue(input: $input) { clientMutationId }
+}
+`
+	out, n, issues, err := r.Operations(src)
+	if err != nil {
+		t.Fatalf("Operations failed: %v", err)
+	}
+	want := `# Keeps comments
+query Issues($states: [TicketState!], $n: Int) {
+  repository(owner: "o", name: "n") {
+    recent: tickets(first: $n, states: $states) {
+      nodes { ...IssueFields }
+    }
+    issueOrPullRequest(number: 1) {
+      ... on Ticket { headline }
+      ... on PullRequest { title }
+    }
+  }
+}
+
+fragment IssueFields on Ticket {
+  number
+  heading: headline
+  missing
+}
+
+mutation Create($input: CreateIssueInput!) {
+  createIssue(input: {repositoryId: "R", headline: "t", metadata: {urgency: 1}}) { issue { id } }
+  again: createIssue(input: $input) { clientMutationId }
+}
+`
+	if out != want {
+		t.Errorf("Rewritten operations:\n%s\nwant:\n%s", out, want)
+	}
+	if n != 8 {
+		t.Errorf("Rewrote %d names, want 8", n)
+	}
+	got := messages(issues)
+	for _, w := range []string{
+		`:17:3: field "missing" not found on type "Issue"; left as it is`,
+		`:20:17: input field CreateIssueInput.title is renamed to "headline", but $input takes it from the variables`,
+	} {
+		if !strings.Contains(got, w) {
+			t.Errorf("Issues do not contain %q:\n%s", w, got)
+		}
+	}
+
+	if _, _, _, err := r.Operations("query {"); err == nil {
+		t.Error("Expected a syntax error")
+	}
+}
+
+func TestOperationsThroughInterface(t *testing.T) {
+	r := newRenamer(t, Map{"Issue.id": "nodeId"}, Options{KeepResponseKeys: true})
+	out, _, issues, err := r.Operations(`{ node(id: "1") { id ... on Issue { id } } }`)
+	if err != nil {
+		t.Fatalf("Operations failed: %v", err)
+	}
+	if want := `{ node(id: "1") { id ... on Issue { id: nodeId } } }`; out != want {
+		t.Errorf("Rewritten operation = %s, want %s", out, want)
+	}
+	got := messages(issues)
+	if !strings.Contains(got, `1:19: Issue.id is selected through Node, which keeps the name`) {
+		t.Errorf("Expected an issue for the selection through Node:\n%s", got)
+	}
+	if !strings.Contains(messages(r.Issues()), "Issue.id implements Node.id") {
+		t.Errorf("Expected an interface issue:\n%s", messages(r.Issues()))
+	}
+}
+
+func TestParseMap(t *testing.T) {
+	m, err := ParseMap([]byte("Issue: Ticket\nIssue.title: headline\n"))
+	if err != nil || m["Issue"] != "Ticket" || m["Issue.title"] != "headline" {
+		t.Errorf("ParseMap = %v, %v", m, err)
+	}
+	if _, err := ParseMap([]byte("[1, 2]")); err == nil {
+		t.Error("Expected an error for a list")
+	}
+}
@@ -0,0 +1,97 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+// Parse reads a report written as JSON, such as the output of --report
+func Parse(r io.Reader) (*Report, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+	var report Report
+	if err := yamlformat.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report: %w", err)
+	}
+	if report.Version != Version {
+		return nil, fmt.Errorf("unsupported report version %d (supported: %d)", report.Version, Version)
+	}
+	return &report, nil
+}
+
+// NewWithFile reads a report file with Parse
+func NewWithFile(path string) (*Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open report: %w", err)
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Baseline holds the findings of an earlier report, so checks can fail only
+// on findings that are new since then. Findings are matched by rule, file,
+// and schema paths, or by message when they have no paths, so they still
+// match after unrelated edits move them to another line. Each baseline
+// finding suppresses one finding.
+type Baseline struct {
+	known map[string]int
+}
+
+// NewBaseline creates a Baseline of the findings in r
+func NewBaseline(r *Report) *Baseline {
+	b := &Baseline{known: make(map[string]int)}
+	for _, f := range r.Findings {
+		b.known[baselineKey(f)]++
+	}
+	return b
+}
+
+// Known reports whether f is in the baseline, using up one matching entry
+func (b *Baseline) Known(f Finding) bool {
+	key := baselineKey(f)
+	if b.known[key] == 0 {
+		return false
+	}
+	b.known[key]--
+	return true
+}
+
+// Filter returns the findings of r that are not in the baseline
+func (b *Baseline) Filter(r *Report) *Report {
+	fresh := New(r.Tool, r.SchemaFingerprint)
+	for _, f := range r.Findings {
+		if !b.Known(f) {
+			fresh.Findings = append(fresh.Findings, f)
+		}
+	}
+	return fresh
+}
+
+// Remaining returns the number of baseline findings that have not matched
+// a finding. They were fixed, so the baseline can be regenerated to keep
+// them from coming back unnoticed.
+func (b *Baseline) Remaining() int {
+	n := 0
+	for _, count := range b.known {
+		n += count
+	}
+	return n
+}
+
+func baselineKey(f Finding) string {
+	file := ""
+	if f.Location != nil {
+		file = f.Location.File
+	}
+	if len(f.Paths) == 0 {
+		return strings.Join([]string{f.Rule, file, f.Message}, "\x00")
+	}
+	return strings.Join(append([]string{f.Rule, file}, f.Paths...), "\x00")
+}
@@ -0,0 +1,44 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBaseline(t *testing.T) {
+	baseline, err := Parse(strings.NewReader(`{"version": 1, "tool": "github-schema lint", "findings": [
+  {"rule": "variable-case", "severity": "error", "message": "variable $Name is not camelCase", "location": {"file": "a.graphql", "line": 2, "column": 17}},
+  {"rule": "deprecated", "severity": "warning", "message": "Repository.isTemplateRepo is deprecated", "paths": ["Repository.isTemplateRepo"]},
+  {"rule": "named-operation", "severity": "error", "message": "query operation has no name", "location": {"file": "gone.graphql", "line": 1, "column": 1}}
+]}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	b := NewBaseline(baseline)
+
+	current := New("github-schema lint", "")
+	current.Findings = []Finding{
+		// Moved to another line
+		{Rule: "variable-case", Severity: Error, Message: "variable $Name is not camelCase", Location: &Location{File: "a.graphql", Line: 5, Column: 17}},
+		// Same message in another file
+		{Rule: "variable-case", Severity: Error, Message: "variable $Name is not camelCase", Location: &Location{File: "b.graphql", Line: 2, Column: 17}},
+		// Reason changed
+		{Rule: "deprecated", Severity: Warning, Message: "Repository.isTemplateRepo is deprecated: use isTemplate", Paths: []string{"Repository.isTemplateRepo"}},
+		// Second occurrence of a known finding
+		{Rule: "deprecated", Severity: Warning, Message: "Repository.isTemplateRepo is deprecated", Paths: []string{"Repository.isTemplateRepo"}},
+	}
+	fresh := b.Filter(current)
+	if len(fresh.Findings) != 2 || fresh.Findings[0].Location.File != "b.graphql" || fresh.Findings[1].Rule != "deprecated" {
+		t.Errorf("Unexpected new findings %+v", fresh.Findings)
+	}
+	if b.Remaining() != 1 {
+		t.Errorf("Expected the fixed finding to remain in the baseline, got %d", b.Remaining())
+	}
+
+	if _, err := Parse(strings.NewReader(`{"version": 2, "findings": []}`)); err == nil {
+		t.Error("Expected error for unsupported version")
+	}
+	if _, err := Parse(strings.NewReader(`not json`)); err == nil {
+		t.Error("Expected error for invalid report")
+	}
+}
@@ -0,0 +1,20 @@
+// Package report defines the analysis report, the one JSON format in which
+// the diff, lint, and deprecated commands can emit their findings, so
+// dashboards aggregating results across repositories parse a single shape:
+//
+//	{
+//	  "version": 1,
+//	  "tool": "github-schema diff",
+//	  "schemaFingerprint": "3f2a…",
+//	  "findings": [
+//	    {"rule": "FIELD_REMOVED", "severity": "error", "message": "Field Issue.body was removed", "paths": ["Issue.body"]}
+//	  ]
+//	}
+//
+// The From functions convert the results of each analysis:
+//
+//	r := report.FromChangeSet(oldSchema.DiffWith(newSchema), newSchema.Fingerprint())
+//
+// Fields are only ever added to the format; a change that breaks consumers
+// increments Version.
+package report
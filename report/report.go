@@ -0,0 +1,128 @@
+package report
+
+import (
+	"github.com/apstndb/github-schema-go/lint"
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+// Version is the version of the report format
+const Version = 1
+
+// Severity is how much attention a finding needs
+type Severity string
+
+const (
+	// Error findings fail checks, such as breaking schema changes
+	Error Severity = "error"
+	// Warning findings need review, such as dangerous changes and deprecations
+	Warning Severity = "warning"
+	// Info findings are informational, such as non-breaking changes
+	Info Severity = "info"
+)
+
+// Location is a position in a file
+type Location struct {
+	File   string `json:"file"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// Finding is a single result of an analysis. Rule identifies the check or
+// change type, and Paths the schema coordinates ("Type", "Type.member", or
+// "Type.field.argument") it concerns.
+type Finding struct {
+	Rule     string    `json:"rule"`
+	Severity Severity  `json:"severity"`
+	Message  string    `json:"message"`
+	Paths    []string  `json:"paths,omitempty"`
+	Location *Location `json:"location,omitempty"`
+}
+
+// Report is the envelope of an analysis. SchemaFingerprint is the
+// Schema.Fingerprint of the schema analyzed against, empty for analyses that
+// do not use a schema.
+type Report struct {
+	Version           int       `json:"version"`
+	Tool              string    `json:"tool"`
+	SchemaFingerprint string    `json:"schemaFingerprint,omitempty"`
+	Findings          []Finding `json:"findings"`
+}
+
+// New creates an empty report of tool
+func New(tool, schemaFingerprint string) *Report {
+	return &Report{Version: Version, Tool: tool, SchemaFingerprint: schemaFingerprint, Findings: []Finding{}}
+}
+
+// Count returns the number of findings with the given severity
+func (r *Report) Count(severity Severity) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// severities maps change criticality to severity
+var severities = map[schema.Criticality]Severity{
+	schema.Breaking:    Error,
+	schema.Dangerous:   Warning,
+	schema.NonBreaking: Info,
+}
+
+// FromChangeSet converts a schema diff. Breaking changes are errors,
+// dangerous changes warnings, and the others informational.
+func FromChangeSet(changes *schema.ChangeSet, schemaFingerprint string) *Report {
+	r := New("github-schema diff", schemaFingerprint)
+	for _, c := range changes.Changes {
+		r.Findings = append(r.Findings, Finding{
+			Rule:     string(c.Type),
+			Severity: severities[c.Criticality],
+			Message:  c.Message,
+			Paths:    []string{c.Path},
+		})
+	}
+	return r
+}
+
+// FromLint converts lint findings, which are all errors
+func FromLint(findings []lint.Finding) *Report {
+	r := New("github-schema lint", "")
+	for _, f := range findings {
+		r.Findings = append(r.Findings, LintFinding(f))
+	}
+	return r
+}
+
+// LintFinding converts a single lint finding
+func LintFinding(f lint.Finding) Finding {
+	return Finding{
+		Rule:     string(f.Rule),
+		Severity: Error,
+		Message:  f.Message,
+		Location: &Location{File: f.File, Line: f.Line, Column: f.Column},
+	}
+}
+
+// FromDeprecated converts a deprecation report into one warning per
+// deprecated field, argument, input field, or enum value
+func FromDeprecated(deprecated []schema.DeprecatedType, schemaFingerprint string) *Report {
+	r := New("github-schema deprecated", schemaFingerprint)
+	for _, t := range deprecated {
+		for _, m := range t.Members() {
+			r.Findings = append(r.Findings, DeprecationFinding(t.Name, m.Name, m.DeprecationReason))
+		}
+	}
+	return r
+}
+
+// DeprecationFinding converts a single deprecated member of typeName
+func DeprecationFinding(typeName, member, reason string) Finding {
+	path := typeName + "." + member
+	message := path + " is deprecated"
+	if reason != "" {
+		message += ": " + reason
+	}
+	return Finding{Rule: "deprecated", Severity: Warning, Message: message, Paths: []string{path}}
+}
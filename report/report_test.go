@@ -0,0 +1,93 @@
+package report
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/lint"
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/apstndb/go-yamlformat"
+)
+
+func TestFromChangeSet(t *testing.T) {
+	changes := &schema.ChangeSet{Changes: []schema.Change{
+		{Type: schema.FieldRemoved, Criticality: schema.Breaking, Path: "Issue.body", Message: "Field Issue.body was removed"},
+		{Type: schema.EnumValueAdded, Criticality: schema.Dangerous, Path: "IssueState.ARCHIVED", Message: "Enum value IssueState.ARCHIVED was added"},
+		{Type: schema.TypeAdded, Criticality: schema.NonBreaking, Path: "Label", Message: "Type Label was added"},
+	}}
+	r := FromChangeSet(changes, "abc")
+	if r.Version != Version || r.Tool != "github-schema diff" || r.SchemaFingerprint != "abc" {
+		t.Errorf("Unexpected envelope %+v", r)
+	}
+	want := []Finding{
+		{Rule: "FIELD_REMOVED", Severity: Error, Message: "Field Issue.body was removed", Paths: []string{"Issue.body"}},
+		{Rule: "ENUM_VALUE_ADDED", Severity: Warning, Message: "Enum value IssueState.ARCHIVED was added", Paths: []string{"IssueState.ARCHIVED"}},
+		{Rule: "TYPE_ADDED", Severity: Info, Message: "Type Label was added", Paths: []string{"Label"}},
+	}
+	if !reflect.DeepEqual(r.Findings, want) {
+		t.Errorf("Expected %+v, got %+v", want, r.Findings)
+	}
+	if r.Count(Error) != 1 || r.Count(Warning) != 1 || r.Count(Info) != 1 {
+		t.Errorf("Unexpected counts in %+v", r.Findings)
+	}
+}
+
+func TestFromLint(t *testing.T) {
+	r := FromLint([]lint.Finding{{File: "a.graphql", Line: 2, Column: 17, Rule: lint.VariableCase, Message: "variable $Name is not camelCase"}})
+	want := []Finding{{Rule: "variable-case", Severity: Error, Message: "variable $Name is not camelCase", Location: &Location{File: "a.graphql", Line: 2, Column: 17}}}
+	if r.Tool != "github-schema lint" || r.SchemaFingerprint != "" || !reflect.DeepEqual(r.Findings, want) {
+		t.Errorf("Unexpected report %+v", r)
+	}
+}
+
+func TestFromDeprecated(t *testing.T) {
+	s, err := schema.NewWithFile(filepath.Join("..", "schema", "testdata", "synthetic.json"))
+	if err != nil {
+		t.Fatalf("Failed to load synthetic schema: %v", err)
+	}
+	deprecated, err := s.Deprecated()
+	if err != nil {
+		t.Fatalf("Deprecated failed: %v", err)
+	}
+	r := FromDeprecated(deprecated, s.Fingerprint())
+	if len(r.Findings) == 0 || r.Count(Warning) != len(r.Findings) {
+		t.Fatalf("Expected only warnings, got %+v", r.Findings)
+	}
+	found := false
+	for _, f := range r.Findings {
+		if f.Rule != "deprecated" || len(f.Paths) != 1 {
+			t.Errorf("Unexpected finding %+v", f)
+		}
+		found = found || f.Paths[0] == "Repository.isTemplateRepo"
+	}
+	if !found {
+		t.Errorf("Expected Repository.isTemplateRepo in %+v", r.Findings)
+	}
+
+	r = FromDeprecated([]schema.DeprecatedType{{
+		Name:      "Repository",
+		Arguments: []schema.DeprecatedMember{{Name: "issues.after", DeprecationReason: "Use `before`."}},
+	}}, "")
+	if len(r.Findings) != 1 || r.Findings[0].Paths[0] != "Repository.issues.after" {
+		t.Errorf("Expected a finding for the deprecated argument, got %+v", r.Findings)
+	}
+}
+
+func TestReportJSON(t *testing.T) {
+	// Empty reports keep an empty findings array for consumers
+	data, err := yamlformat.MarshalJSON(New("github-schema lint", ""))
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := yamlformat.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if findings, ok := decoded["findings"].([]interface{}); !ok || len(findings) != 0 {
+		t.Errorf("Expected empty findings array, got %s", data)
+	}
+	if _, ok := decoded["schemaFingerprint"]; ok {
+		t.Errorf("Expected no schemaFingerprint, got %s", data)
+	}
+}
@@ -0,0 +1,199 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+// ExplicitToken, when set, is used by the download functions before any other
+// credential source, for example from a --token flag. The WithToken option
+// overrides it for a single download.
+var ExplicitToken string
+
+// Credential is the GitHub token used for a request and where it came from.
+// Token is empty for anonymous requests.
+type Credential struct {
+	Token  string
+	Source string   // "GH_TOKEN", "gh auth token", "anonymous", ...
+	Tried  []string // Sources probed before Source, with why each was skipped
+
+	host string
+}
+
+// ResolveCredential finds a GitHub token for github.com, probing in order:
+// explicit, the GH_TOKEN and GITHUB_TOKEN environment variables, the
+// oauth_token stored for github.com in gh's hosts.yml, and `gh auth token`.
+// When none yields a token the credential is anonymous, which the GraphQL API
+// rejects; the download error then lists every source that was tried.
+func ResolveCredential(explicit string) *Credential {
+	return ResolveCredentialForHost(explicit, "github.com")
+}
+
+// ResolveCredentialForHost finds a token for the GitHub host an endpoint is
+// served from, such as "github.com" or a GitHub Enterprise Server host name.
+// For github.com it probes like ResolveCredential; for other hosts it probes
+// explicit, the GH_ENTERPRISE_TOKEN and GITHUB_ENTERPRISE_TOKEN environment
+// variables, the oauth_token stored for host in gh's hosts.yml, and
+// `gh auth token --hostname host`, so a github.com token is never sent to
+// another host.
+func ResolveCredentialForHost(explicit, host string) *Credential {
+	c := &Credential{host: host}
+	found := func(token, source string) bool {
+		if token == "" {
+			return false
+		}
+		c.Token, c.Source = token, source
+		return true
+	}
+
+	if found(explicit, "explicit token") {
+		return c
+	}
+	c.Tried = append(c.Tried, "explicit token: not set")
+
+	envs := []string{"GH_TOKEN", "GITHUB_TOKEN"}
+	if !c.isGitHub() {
+		envs = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+	for _, env := range envs {
+		if found(strings.TrimSpace(os.Getenv(env)), env) {
+			return c
+		}
+		c.Tried = append(c.Tried, env+": not set")
+	}
+
+	path, token, err := ghConfigToken(host)
+	if err == nil && found(token, "gh config ("+path+")") {
+		return c
+	}
+	switch {
+	case err != nil:
+		c.Tried = append(c.Tried, fmt.Sprintf("gh config: %v", err))
+	default:
+		c.Tried = append(c.Tried, fmt.Sprintf("gh config (%s): no token stored for %s, gh may keep it in the system keyring", path, host))
+	}
+
+	token, err = ghCLIToken(host)
+	if err == nil && found(token, "gh auth token") {
+		return c
+	}
+	if err == nil {
+		err = fmt.Errorf("returned no token")
+	}
+	c.Tried = append(c.Tried, fmt.Sprintf("gh auth token: %v", err))
+
+	c.Source = "anonymous"
+	return c
+}
+
+// EndpointHost returns the host whose credentials authenticate requests to a
+// GraphQL endpoint: "github.com" for https://api.github.com/graphql, and the
+// host name otherwise, such as "ghe.example.com" for
+// https://ghe.example.com/api/graphql
+func EndpointHost(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return endpoint
+	}
+	host := strings.ToLower(u.Host)
+	if host == "api.github.com" {
+		return "github.com"
+	}
+	return host
+}
+
+// isGitHub reports whether the credential is for github.com rather than a
+// GitHub Enterprise Server host
+func (c *Credential) isGitHub() bool {
+	return c.host == "" || c.host == "github.com"
+}
+
+// statusError describes an unsuccessful HTTP status, explaining how to
+// authenticate when the request was rejected for lack of a token
+func (c *Credential) statusError(status int) error {
+	switch {
+	case c.Token == "" && (status == http.StatusUnauthorized || status == http.StatusForbidden) && !c.isGitHub():
+		return fmt.Errorf("GitHub API returned HTTP %d for an anonymous request; no token found for %s (tried %s). Set GH_ENTERPRISE_TOKEN, pass --token, or run 'gh auth login --hostname %s'",
+			status, c.host, strings.Join(c.Tried, "; "), c.host)
+	case c.Token == "" && (status == http.StatusUnauthorized || status == http.StatusForbidden):
+		return fmt.Errorf("GitHub API returned HTTP %d for an anonymous request; no GitHub token found (tried %s). Set GH_TOKEN or GITHUB_TOKEN, pass --token, or run 'gh auth login'",
+			status, strings.Join(c.Tried, "; "))
+	case status == http.StatusUnauthorized:
+		return fmt.Errorf("GitHub API returned HTTP %d: the token from %s was rejected", status, c.Source)
+	}
+	return fmt.Errorf("GitHub API returned HTTP %d", status)
+}
+
+// ghConfigDir returns gh's configuration directory, resolved like gh does
+func ghConfigDir() (string, error) {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh"), nil
+	}
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("AppData"); dir != "" {
+			return filepath.Join(dir, "GitHub CLI"), nil
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "gh"), nil
+}
+
+// ghConfigToken reads the oauth_token of host from gh's hosts.yml
+func ghConfigToken(host string) (path, token string, err error) {
+	dir, err := ghConfigDir()
+	if err != nil {
+		return "", "", err
+	}
+	path = filepath.Join(dir, "hosts.yml")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return path, "", fmt.Errorf("%s does not exist", path)
+	}
+	if err != nil {
+		return path, "", err
+	}
+	token, err = hostsToken(data, host)
+	if err != nil {
+		return path, "", fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return path, token, nil
+}
+
+// hostsToken returns the oauth_token set directly under host in a hosts.yml
+// document, such as the one gh writes
+func hostsToken(data []byte, host string) (string, error) {
+	var hosts map[string]struct {
+		OAuthToken string `json:"oauth_token"`
+	}
+	if err := yamlformat.Unmarshal(data, &hosts); err != nil {
+		return "", err
+	}
+	return hosts[host].OAuthToken, nil
+}
+
+// ghCLIToken asks the gh CLI for its token for host
+func ghCLIToken(host string) (string, error) {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return "", fmt.Errorf("gh is not installed")
+	}
+	out, err := exec.Command("gh", "auth", "token", "--hostname", host).Output()
+	if err != nil {
+		return "", fmt.Errorf("not logged in to %s (run 'gh auth login --hostname %s'): %w", host, host, err)
+	}
+	return string(bytes.TrimSpace(out)), nil
+}
@@ -0,0 +1,182 @@
+package schema
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// isolateAuth clears every credential source so tests do not pick up the
+// developer's own token
+func isolateAuth(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+	t.Setenv("GH_CONFIG_DIR", dir)
+	t.Setenv("PATH", t.TempDir())
+	return dir
+}
+
+func TestResolveCredential(t *testing.T) {
+	t.Run("explicit wins", func(t *testing.T) {
+		isolateAuth(t)
+		t.Setenv("GH_TOKEN", "env")
+		c := ResolveCredential("flag")
+		if c.Token != "flag" || c.Source != "explicit token" {
+			t.Errorf("Unexpected credential: %+v", c)
+		}
+	})
+
+	t.Run("GH_TOKEN before GITHUB_TOKEN", func(t *testing.T) {
+		isolateAuth(t)
+		t.Setenv("GH_TOKEN", "gh")
+		t.Setenv("GITHUB_TOKEN", "github")
+		if c := ResolveCredential(""); c.Token != "gh" || c.Source != "GH_TOKEN" {
+			t.Errorf("Unexpected credential: %+v", c)
+		}
+	})
+
+	t.Run("GITHUB_TOKEN without gh", func(t *testing.T) {
+		isolateAuth(t)
+		t.Setenv("GITHUB_TOKEN", " github\n")
+		if c := ResolveCredential(""); c.Token != "github" || c.Source != "GITHUB_TOKEN" {
+			t.Errorf("Unexpected credential: %+v", c)
+		}
+	})
+
+	t.Run("gh config", func(t *testing.T) {
+		dir := isolateAuth(t)
+		hosts := `example.com:
+    oauth_token: other
+github.com:
+    users:
+        octocat:
+            oauth_token: nested
+    oauth_token: "from-config"
+    git_protocol: https
+`
+		if err := os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		c := ResolveCredential("")
+		if c.Token != "from-config" || !strings.HasPrefix(c.Source, "gh config") {
+			t.Errorf("Unexpected credential: %+v", c)
+		}
+	})
+
+	t.Run("anonymous lists what was tried", func(t *testing.T) {
+		isolateAuth(t)
+		c := ResolveCredential("")
+		if c.Token != "" || c.Source != "anonymous" || len(c.Tried) != 5 {
+			t.Fatalf("Unexpected credential: %+v", c)
+		}
+		msg := c.statusError(http.StatusUnauthorized).Error()
+		for _, want := range []string{"HTTP 401", "GH_TOKEN: not set", "GITHUB_TOKEN: not set", "hosts.yml does not exist", "gh is not installed", "gh auth login"} {
+			if !strings.Contains(msg, want) {
+				t.Errorf("Error %q does not mention %q", msg, want)
+			}
+		}
+	})
+}
+
+func TestResolveCredentialForHost(t *testing.T) {
+	hosts := `github.com:
+    oauth_token: github
+ghe.example.com:
+    oauth_token: ghes
+`
+	t.Run("GitHub tokens stay on github.com", func(t *testing.T) {
+		dir := isolateAuth(t)
+		t.Setenv("GH_TOKEN", "gh")
+		t.Setenv("GITHUB_TOKEN", "github")
+		if err := os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if c := ResolveCredentialForHost("", "ghe.example.com"); c.Token != "ghes" || !strings.HasPrefix(c.Source, "gh config") {
+			t.Errorf("Unexpected credential: %+v", c)
+		}
+		c := ResolveCredentialForHost("", "127.0.0.1:8080")
+		if c.Token != "" || c.Source != "anonymous" {
+			t.Fatalf("Unexpected credential: %+v", c)
+		}
+		msg := c.statusError(http.StatusUnauthorized).Error()
+		for _, want := range []string{"GH_ENTERPRISE_TOKEN: not set", "no token stored for 127.0.0.1:8080", "gh auth login --hostname 127.0.0.1:8080"} {
+			if !strings.Contains(msg, want) {
+				t.Errorf("Error %q does not mention %q", msg, want)
+			}
+		}
+	})
+
+	t.Run("GH_ENTERPRISE_TOKEN", func(t *testing.T) {
+		isolateAuth(t)
+		t.Setenv("GH_TOKEN", "gh")
+		t.Setenv("GH_ENTERPRISE_TOKEN", "enterprise")
+		if c := ResolveCredentialForHost("", "ghe.example.com"); c.Token != "enterprise" || c.Source != "GH_ENTERPRISE_TOKEN" {
+			t.Errorf("Unexpected credential: %+v", c)
+		}
+		if c := ResolveCredentialForHost("", "github.com"); c.Token != "gh" {
+			t.Errorf("Unexpected credential for github.com: %+v", c)
+		}
+	})
+}
+
+func TestHostsToken(t *testing.T) {
+	hosts := `# written by gh
+example.com:
+    oauth_token: other
+github.com:
+    users:
+        octocat:
+            oauth_token: nested
+    oauth_token: "from-config"
+    user: octocat
+ghe.example.com:
+    oauth_token: 'single quoted'
+keyring.example.com:
+    users:
+        octocat:
+            oauth_token: nested
+`
+	for host, want := range map[string]string{
+		"github.com":          "from-config",
+		"example.com":         "other",
+		"ghe.example.com":     "single quoted",
+		"keyring.example.com": "",
+		"missing.example.com": "",
+	} {
+		got, err := hostsToken([]byte(hosts), host)
+		if err != nil {
+			t.Fatalf("hostsToken(%q) failed: %v", host, err)
+		}
+		if got != want {
+			t.Errorf("hostsToken(%q) = %q, want %q", host, got, want)
+		}
+	}
+
+	if _, err := hostsToken([]byte("github.com: [oauth_token"), "github.com"); err == nil {
+		t.Error("Expected error for invalid YAML")
+	}
+}
+
+func TestEndpointHost(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		GitHubAPIURL:                          "github.com",
+		"https://GHE.example.com/api/graphql": "ghe.example.com",
+		"http://127.0.0.1:8080":               "127.0.0.1:8080",
+	} {
+		if got := EndpointHost(endpoint); got != want {
+			t.Errorf("EndpointHost(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
+
+func TestCredentialStatusError(t *testing.T) {
+	if err := (&Credential{Token: "t", Source: "GH_TOKEN"}).statusError(http.StatusUnauthorized); !strings.Contains(err.Error(), "token from GH_TOKEN was rejected") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,393 @@
+package schema
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+// cacheFormatVersion is part of every cache file name, so changing the
+// serialized representation invalidates existing entries
+const cacheFormatVersion = "v3"
+
+// cacheEntry is the serialized form of a parsed schema. It holds the typed
+// Model rather than the generic document, which gob would have to encode
+// with the type of every value; the document is rebuilt from the Model when
+// a jq query or raw lookup needs it.
+type cacheEntry struct {
+	Model       *Model
+	Fingerprint string    // See Schema.Fingerprint
+	Metadata    *Metadata // Of downloaded documents
+	Strict      bool      // The source passed the checks of NewWithDataStrict
+}
+
+// Fingerprint returns a stable identifier for raw schema bytes (hex SHA-256)
+func Fingerprint(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Fingerprint returns a stable identifier of the schema content (hex SHA-256
+// of the canonical JSON encoding of "data" with sorted keys), so the same
+// schema has the same fingerprint whether it was read compressed, indented,
+// or embedded, and with or without download Metadata
+func (s *Schema) Fingerprint() string {
+	s.fingerprintOnce.Do(func() {
+		content := s.tree()
+		if doc, ok := content.(map[string]interface{}); ok && doc["data"] != nil {
+			content = doc["data"]
+		}
+		// Maps are encoded with sorted keys, so the encoding is deterministic
+		data, err := yamlformat.MarshalJSON(content)
+		if err != nil {
+			slog.Debug("Failed to encode schema for fingerprint", "error", err)
+			return
+		}
+		s.fingerprint = Fingerprint(data)
+	})
+	return s.fingerprint
+}
+
+// DefaultCacheDir returns the directory used for parsed schema caches,
+// located under the user's cache directory
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "github-schema"), nil
+}
+
+// NewCached creates a Schema instance using the embedded schema, reusing a
+// parsed copy stored in cacheDir when one exists
+func NewCached(cacheDir string) (*Schema, error) {
+	return loadCached(cacheDir, Fingerprint(embeddedSchema), false, New)
+}
+
+// NewCachedWithFile creates a Schema instance from a file, reusing a parsed
+// copy stored in cacheDir when one exists for the file's current content
+func NewCachedWithFile(path, cacheDir string) (*Schema, error) {
+	buf, err := readFileBuffer(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+	defer putBuffer(buf)
+
+	return NewCachedWithData(buf.Bytes(), cacheDir)
+}
+
+// NewCachedWithFileStrict creates a Schema instance from a file like
+// NewCachedWithFile, verifying the structure of the file as
+// NewWithFileStrict does
+func NewCachedWithFileStrict(path, cacheDir string) (*Schema, error) {
+	buf, err := readFileBuffer(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+	defer putBuffer(buf)
+
+	return NewCachedWithDataStrict(buf.Bytes(), cacheDir)
+}
+
+// NewCachedWithData creates a Schema instance from raw JSON data, reusing a
+// parsed copy stored in cacheDir when one exists for the same content.
+//
+// Cache entries are keyed by the Fingerprint of the source bytes, so a changed
+// source never hits a stale entry. Unreadable or corrupt entries are ignored
+// and rewritten; failing to write the cache is logged but not an error.
+//
+// Entries hold the Model, from which a cached schema rebuilds the document
+// for jq queries in the form GitHub returns: empty descriptions and
+// deprecation reasons are null, and members that do not apply to the kind
+// of a type are null.
+func NewCachedWithData(data []byte, cacheDir string) (*Schema, error) {
+	return loadCached(cacheDir, Fingerprint(data), false, func() (*Schema, error) {
+		return NewWithData(data)
+	})
+}
+
+// NewCachedWithDataStrict creates a Schema instance like NewCachedWithData,
+// verifying the structure of data as NewWithDataStrict does. Entries written
+// by a non-strict load are checked again.
+func NewCachedWithDataStrict(data []byte, cacheDir string) (*Schema, error) {
+	return loadCached(cacheDir, Fingerprint(data), true, func() (*Schema, error) {
+		return NewWithDataStrict(data)
+	})
+}
+
+func loadCached(cacheDir, fingerprint string, strict bool, parse func() (*Schema, error)) (*Schema, error) {
+	path := filepath.Join(cacheDir, fingerprint+"."+cacheFormatVersion+".gob")
+
+	if entry, err := readCacheFile(path); err == nil && (entry.Strict || !strict) {
+		slog.Debug("Loaded schema from cache", "path", path)
+		// Date the entry by its last use, so CacheRetention keeps it
+		now := time.Now()
+		os.Chtimes(path, now, now)
+		return entry.schema(), nil
+	} else if err != nil && !os.IsNotExist(err) {
+		slog.Debug("Ignoring unusable schema cache", "path", path, "error", err)
+	}
+
+	s, err := parse()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &cacheEntry{Model: s.Model(), Fingerprint: s.Fingerprint(), Metadata: s.Metadata(), Strict: strict}
+	if err := writeCacheFile(path, entry); err != nil {
+		slog.Warn("Failed to write schema cache", "path", path, "error", err)
+	} else {
+		slog.Debug("Wrote schema cache", "path", path)
+		if CacheRetention != (RetentionPolicy{}) {
+			if removed, err := PruneCache(cacheDir, CacheRetention, false); err != nil {
+				slog.Debug("Failed to prune schema cache", "dir", cacheDir, "error", err)
+			} else if len(removed) > 0 {
+				slog.Debug("Pruned schema cache", "dir", cacheDir, "removed", len(removed))
+			}
+		}
+	}
+	return s, nil
+}
+
+func readCacheFile(path string) (*cacheEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entry cacheEntry
+	if err := gob.NewDecoder(bufio.NewReader(f)).Decode(&entry); err != nil {
+		return nil, fmt.Errorf("failed to decode schema cache: %w", err)
+	}
+	if entry.Model == nil {
+		return nil, fmt.Errorf("failed to decode schema cache: no model")
+	}
+	entry.Model.reindex()
+	return &entry, nil
+}
+
+// writeCacheFile writes the cache atomically so concurrent CLI invocations
+// never observe a partially written entry
+func writeCacheFile(path string, entry *cacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".schema-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	if err := gob.NewEncoder(w).Encode(entry); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to encode schema cache: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// schema returns a Schema backed by the cached Model
+func (e *cacheEntry) schema() *Schema {
+	s := &Schema{metadata: e.Metadata}
+	s.modelOnce.Do(func() { s.model = e.Model })
+	s.fingerprintOnce.Do(func() { s.fingerprint = e.Fingerprint })
+	return s
+}
+
+// reindex restores what gob leaves out of a decoded Model: the unexported
+// name indexes, and empty lists, which gob decodes as nil
+func (m *Model) reindex() {
+	if m.Types == nil {
+		m.Types = []*Type{}
+	}
+	if m.Directives == nil {
+		m.Directives = []*Directive{}
+	}
+	m.types = make(map[string]*Type, len(m.Types))
+	for _, t := range m.Types {
+		m.types[t.Name] = t
+		if len(t.Fields) > 0 {
+			t.fields = make(map[string]*Field, len(t.Fields))
+		}
+		for _, f := range t.Fields {
+			if f.Args == nil {
+				f.Args = []*InputValue{}
+			}
+			t.fields[f.Name] = f
+		}
+		if len(t.InputFields) > 0 {
+			t.inputFields = make(map[string]*InputValue, len(t.InputFields))
+		}
+		for _, v := range t.InputFields {
+			t.inputFields[v.Name] = v
+		}
+	}
+	for _, d := range m.Directives {
+		if d.Args == nil {
+			d.Args = []*InputValue{}
+		}
+	}
+}
+
+// document rebuilds the introspection result of a cached schema from its
+// Model, recording meta under the extensions like Download does
+func (m *Model) document(meta *Metadata) map[string]interface{} {
+	types := make([]interface{}, 0, len(m.Types))
+	for _, t := range m.Types {
+		types = append(types, typeDocument(t))
+	}
+	directives := make([]interface{}, 0, len(m.Directives))
+	for _, d := range m.Directives {
+		locations := make([]interface{}, 0, len(d.Locations))
+		for _, l := range d.Locations {
+			locations = append(locations, l)
+		}
+		entry := map[string]interface{}{
+			"name":        d.Name,
+			"description": nullable(d.Description),
+			"locations":   locations,
+			"args":        inputValueDocuments(d.Args),
+		}
+		if d.IsRepeatable {
+			entry["isRepeatable"] = true
+		}
+		directives = append(directives, entry)
+	}
+
+	schema := map[string]interface{}{
+		"queryType":        rootDocument(m.QueryType),
+		"mutationType":     rootDocument(m.MutationType),
+		"subscriptionType": rootDocument(m.SubscriptionType),
+		"types":            types,
+		"directives":       directives,
+	}
+	if m.Description != "" {
+		schema["description"] = m.Description
+	}
+	doc := map[string]interface{}{"data": map[string]interface{}{"__schema": schema}}
+	if meta != nil {
+		var value interface{}
+		if data, err := yamlformat.MarshalJSON(meta); err == nil && yamlformat.Unmarshal(data, &value) == nil {
+			doc["extensions"] = map[string]interface{}{metadataExtension: value}
+		}
+	}
+	return doc
+}
+
+func typeDocument(t *Type) map[string]interface{} {
+	entry := map[string]interface{}{
+		"kind":          t.Kind,
+		"name":          t.Name,
+		"description":   nullable(t.Description),
+		"fields":        nil,
+		"inputFields":   nil,
+		"interfaces":    nil,
+		"enumValues":    nil,
+		"possibleTypes": nil,
+	}
+	switch t.Kind {
+	case "OBJECT", "INTERFACE":
+		fields := make([]interface{}, 0, len(t.Fields))
+		for _, f := range t.Fields {
+			fields = append(fields, map[string]interface{}{
+				"name":              f.Name,
+				"description":       nullable(f.Description),
+				"args":              inputValueDocuments(f.Args),
+				"type":              typeRefDocument(f.Type),
+				"isDeprecated":      f.IsDeprecated,
+				"deprecationReason": nullable(f.DeprecationReason),
+			})
+		}
+		entry["fields"] = fields
+		entry["interfaces"] = typeRefDocuments(t.Interfaces, "INTERFACE")
+	case "INPUT_OBJECT":
+		entry["inputFields"] = inputValueDocuments(t.InputFields)
+	case "ENUM":
+		values := make([]interface{}, 0, len(t.EnumValues))
+		for _, v := range t.EnumValues {
+			values = append(values, map[string]interface{}{
+				"name":              v.Name,
+				"description":       nullable(v.Description),
+				"isDeprecated":      v.IsDeprecated,
+				"deprecationReason": nullable(v.DeprecationReason),
+			})
+		}
+		entry["enumValues"] = values
+	}
+	if t.Kind == "INTERFACE" || t.Kind == "UNION" {
+		entry["possibleTypes"] = typeRefDocuments(t.PossibleTypes, "OBJECT")
+	}
+	if t.SpecifiedByURL != "" {
+		entry["specifiedByURL"] = t.SpecifiedByURL
+	}
+	if t.OneOf {
+		entry["isOneOf"] = true
+	}
+	return entry
+}
+
+func inputValueDocuments(values []*InputValue) []interface{} {
+	list := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		entry := map[string]interface{}{
+			"name":         v.Name,
+			"description":  nullable(v.Description),
+			"type":         typeRefDocument(v.Type),
+			"defaultValue": nil,
+		}
+		if v.DefaultValue != nil {
+			entry["defaultValue"] = *v.DefaultValue
+		}
+		if v.IsDeprecated {
+			entry["isDeprecated"] = true
+			entry["deprecationReason"] = nullable(v.DeprecationReason)
+		}
+		list = append(list, entry)
+	}
+	return list
+}
+
+// typeRefDocuments converts the named references of interfaces or
+// possibleTypes, which all have the given kind
+func typeRefDocuments(names []string, kind string) []interface{} {
+	list := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		list = append(list, map[string]interface{}{"kind": kind, "name": name, "ofType": nil})
+	}
+	return list
+}
+
+func typeRefDocument(ref *TypeRef) interface{} {
+	if ref == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"kind":   ref.Kind,
+		"name":   nullable(ref.Name),
+		"ofType": typeRefDocument(ref.OfType),
+	}
+}
+
+func rootDocument(name string) interface{} {
+	if name == "" {
+		return nil
+	}
+	return map[string]interface{}{"name": name}
+}
@@ -0,0 +1,175 @@
+package schema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+func TestNewCachedWithData(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := NewCachedWithData(testSchemaData, dir)
+	if err != nil {
+		t.Fatalf("First load failed: %v", err)
+	}
+	cacheFile := filepath.Join(dir, Fingerprint(testSchemaData)+"."+cacheFormatVersion+".gob")
+	if _, err := os.Stat(cacheFile); err != nil {
+		t.Fatalf("Expected cache file to be written: %v", err)
+	}
+
+	second, err := NewCachedWithData(testSchemaData, dir)
+	if err != nil {
+		t.Fatalf("Cached load failed: %v", err)
+	}
+	want, _ := first.Type("PullRequest")
+	got, err := second.Type("PullRequest")
+	if err != nil {
+		t.Fatalf("Type on cached schema failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Cached schema differs: got %v, want %v", got, want)
+	}
+}
+
+func TestNewCachedWithFileInvalidation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, testSchemaData, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewCachedWithFile(path, dir); err != nil {
+		t.Fatalf("First load failed: %v", err)
+	}
+
+	// Changing the source must not return the stale cache entry
+	changed := []byte(`{"data": {"__schema": {"types": [{"name": "Only", "kind": "OBJECT"}]}}}`)
+	if err := os.WriteFile(path, changed, 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewCachedWithFile(path, dir)
+	if err != nil {
+		t.Fatalf("Second load failed: %v", err)
+	}
+	if _, err := s.Type("PullRequest"); err == nil {
+		t.Error("Expected stale type to be gone after source change")
+	}
+	if _, err := s.Type("Only"); err != nil {
+		t.Errorf("Expected new type after source change: %v", err)
+	}
+}
+
+func TestNewCachedIgnoresCorruptEntry(t *testing.T) {
+	dir := t.TempDir()
+	cacheFile := filepath.Join(dir, Fingerprint(testSchemaData)+"."+cacheFormatVersion+".gob")
+	if err := os.WriteFile(cacheFile, []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewCachedWithData(testSchemaData, dir)
+	if err != nil {
+		t.Fatalf("Load with corrupt cache failed: %v", err)
+	}
+	if _, err := s.Type("Issue"); err != nil {
+		t.Errorf("Type failed: %v", err)
+	}
+	// The corrupt entry is replaced with a valid one
+	if _, err := readCacheFile(cacheFile); err != nil {
+		t.Errorf("Expected cache entry to be rewritten: %v", err)
+	}
+}
+
+func TestNewCachedRebuildsDocument(t *testing.T) {
+	dir := t.TempDir()
+	data := syntheticData(t)
+	fresh := loadRichSchema(t)
+	if _, err := NewCachedWithData(data, dir); err != nil {
+		t.Fatalf("First load failed: %v", err)
+	}
+	cached, err := NewCachedWithData(data, dir)
+	if err != nil {
+		t.Fatalf("Cached load failed: %v", err)
+	}
+	if cached.data != nil {
+		t.Fatal("Expected the cached schema to start from its Model")
+	}
+
+	if got, want := cached.Fingerprint(), fresh.Fingerprint(); got != want {
+		t.Errorf("Fingerprint() = %q, want %q", got, want)
+	}
+	for _, query := range []string{ListTypesQuery, ListObjectTypesQuery, ListQueriesQuery} {
+		want, err := fresh.Query(query, nil)
+		if err != nil {
+			t.Fatalf("Query(%q) failed: %v", query, err)
+		}
+		got, err := cached.Query(query, nil)
+		if err != nil {
+			t.Fatalf("Query(%q) on cached schema failed: %v", query, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Query(%q) on cached schema = %v, want %v", query, got, want)
+		}
+	}
+	want, _ := fresh.Mutation("createIssue")
+	got, err := cached.Mutation("createIssue")
+	if err != nil {
+		t.Fatalf("Mutation on cached schema failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Cached Mutation differs: got %v, want %v", got, want)
+	}
+}
+
+func TestNewCachedWithDataStrict(t *testing.T) {
+	dir := t.TempDir()
+	// testSchemaData loads, but fails the structure check
+	if _, err := NewCachedWithData(testSchemaData, dir); err != nil {
+		t.Fatalf("Non-strict load failed: %v", err)
+	}
+	var structErr *StructureError
+	if _, err := NewCachedWithDataStrict(testSchemaData, dir); !errors.As(err, &structErr) {
+		t.Errorf("Expected the entry of a non-strict load to be checked again, got %v", err)
+	}
+
+	data := syntheticData(t)
+	if _, err := NewCachedWithDataStrict(data, dir); err != nil {
+		t.Fatalf("Strict load failed: %v", err)
+	}
+	entry, err := readCacheFile(filepath.Join(dir, Fingerprint(data)+"."+cacheFormatVersion+".gob"))
+	if err != nil {
+		t.Fatalf("Expected a cache entry: %v", err)
+	}
+	if !entry.Strict {
+		t.Error("Expected the entry of a strict load to be marked strict")
+	}
+}
+
+func TestSchemaFingerprint(t *testing.T) {
+	s := loadRichSchema(t)
+	var doc interface{}
+	if err := yamlformat.Unmarshal(syntheticData(t), &doc); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	compact, err := yamlformat.MarshalJSON(doc)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	other, err := NewWithData(compact)
+	if err != nil {
+		t.Fatalf("NewWithData failed: %v", err)
+	}
+	if s.Fingerprint() == "" || s.Fingerprint() != other.Fingerprint() {
+		t.Errorf("Expected the same fingerprint regardless of formatting, got %q and %q", s.Fingerprint(), other.Fingerprint())
+	}
+
+	modified := modifiedSample(t, func(types map[string]map[string]interface{}) {
+		delete(types, "AddStarPayload")
+	})
+	if modified.Fingerprint() == s.Fingerprint() {
+		t.Error("Expected a different fingerprint for a different schema")
+	}
+}
@@ -0,0 +1,106 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CanonicalTypeName returns the schema's spelling of a type name. When there
+// is no exact match the name is matched case-insensitively, so "pullrequest"
+// resolves to "PullRequest". It fails when no type or several types match.
+func (s *Schema) CanonicalTypeName(name string) (string, error) {
+	types := s.rawTypes()
+	if _, ok := types[name]; ok {
+		return name, nil
+	}
+	names := make([]string, 0, len(types))
+	for n := range types {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return canonicalName(names, name, "type", "")
+}
+
+// CanonicalFieldName returns the schema's spelling of a field or input field
+// of a type, matching case-insensitively like CanonicalTypeName. typeName
+// must already be canonical.
+func (s *Schema) CanonicalFieldName(typeName, fieldName string) (string, error) {
+	entry := s.rawType(typeName)
+	if entry == nil {
+		return "", s.Model().typeNotFound(typeName)
+	}
+	return canonicalName(memberNames(entry), fieldName, "field", typeName)
+}
+
+// CanonicalTypeName returns the schema's spelling of a type name, like
+// Schema.CanonicalTypeName, without parsing any type
+func (l *LazySchema) CanonicalTypeName(name string) (string, error) {
+	if _, ok := l.index[name]; ok {
+		return name, nil
+	}
+	return canonicalName(l.names, name, "type", "")
+}
+
+// CanonicalFieldName returns the schema's spelling of a field or input field
+// of a type, like Schema.CanonicalFieldName, parsing only that type
+func (l *LazySchema) CanonicalFieldName(typeName, fieldName string) (string, error) {
+	entry, err := l.RawType(typeName)
+	if err != nil {
+		return "", err
+	}
+	return canonicalName(memberNames(entry), fieldName, "field", typeName)
+}
+
+// RootTypeName returns the name of the root type for "query" or "mutation"
+// operations, or "" if the schema has none
+func (l *LazySchema) RootTypeName(operation string) string {
+	switch operation {
+	case "query":
+		return l.queryType
+	case "mutation":
+		return l.mutationType
+	}
+	return ""
+}
+
+// memberNames returns the names of the fields and input fields of a type entry
+func memberNames(entry map[string]interface{}) []string {
+	var names []string
+	for _, key := range []string{"fields", "inputFields"} {
+		list, _ := entry[key].([]interface{})
+		for _, item := range list {
+			if m, ok := item.(map[string]interface{}); ok {
+				if name, ok := m["name"].(string); ok {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// canonicalName finds name in candidates, exactly or else case-insensitively.
+// kind and parent describe the name in a *NotFoundError.
+func canonicalName(candidates []string, name, kind, parent string) (string, error) {
+	var matches []string
+	for _, c := range candidates {
+		if c == name {
+			return c, nil
+		}
+		if strings.EqualFold(c, name) {
+			matches = append(matches, c)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", &NotFoundError{Kind: kind, Name: name, Parent: parent, Suggestions: suggestNames(name, candidates)}
+	case 1:
+		return matches[0], nil
+	}
+	what := kind
+	if parent != "" {
+		what += " of " + parent
+	}
+	return "", fmt.Errorf("%s %q is ambiguous: matches %s", what, name, strings.Join(matches, ", "))
+}
@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanonicalNames(t *testing.T) {
+	s := loadRichSchema(t)
+	l, err := NewLazyWithData(SampleData())
+	if err != nil {
+		t.Fatalf("Failed to create lazy schema: %v", err)
+	}
+
+	type resolver interface {
+		CanonicalTypeName(name string) (string, error)
+		CanonicalFieldName(typeName, fieldName string) (string, error)
+		RootTypeName(operation string) string
+	}
+	for name, r := range map[string]resolver{"schema": s, "lazy": l} {
+		t.Run(name, func(t *testing.T) {
+			typeTests := []struct {
+				in, want, wantErr string
+			}{
+				{"PullRequest", "PullRequest", ""},
+				{"pullrequest", "PullRequest", ""},
+				{"CREATEISSUEINPUT", "CreateIssueInput", ""},
+				{"Pull_Request", "", `type "Pull_Request" not found`},
+			}
+			for _, tt := range typeTests {
+				got, err := r.CanonicalTypeName(tt.in)
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Errorf("CanonicalTypeName(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+					}
+					continue
+				}
+				if err != nil || got != tt.want {
+					t.Errorf("CanonicalTypeName(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+				}
+			}
+
+			if got, err := r.CanonicalFieldName(r.RootTypeName("mutation"), "CREATEissue"); err != nil || got != "createIssue" {
+				t.Errorf("CanonicalFieldName(Mutation, CREATEissue) = %q, %v", got, err)
+			}
+			if got, err := r.CanonicalFieldName("CreateIssueInput", "repositoryid"); err != nil || got != "repositoryId" {
+				t.Errorf("CanonicalFieldName(CreateIssueInput, repositoryid) = %q, %v", got, err)
+			}
+			if _, err := r.CanonicalFieldName("Issue", "nope"); err == nil || !strings.Contains(err.Error(), `field "nope" not found on type "Issue"`) {
+				t.Errorf("Expected field not found error, got %v", err)
+			}
+			if got := r.RootTypeName("query"); got != "Query" {
+				t.Errorf("RootTypeName(query) = %q", got)
+			}
+		})
+	}
+}
+
+func TestCanonicalNameAmbiguous(t *testing.T) {
+	_, err := canonicalName([]string{"URI", "Uri"}, "uri", "type", "")
+	if err == nil || !strings.Contains(err.Error(), "ambiguous: matches URI, Uri") {
+		t.Errorf("Expected ambiguity error, got %v", err)
+	}
+	if got, err := canonicalName([]string{"URI", "Uri"}, "Uri", "type", ""); err != nil || got != "Uri" {
+		t.Errorf("Exact match = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,73 @@
+package schema
+
+// TypePosition selects the rules applied by TypesCompatible
+type TypePosition int
+
+const (
+	// OutputPosition covers field types. Clients read these values, so a new
+	// type is compatible when every value it produces was valid for the old
+	// type: it may add non-null or narrow an abstract type to a member.
+	OutputPosition TypePosition = iota
+
+	// InputPosition covers arguments and input fields. Clients write these
+	// values, so a new type is compatible when it accepts every value the old
+	// type accepted: it may drop non-null but must keep the same named type.
+	InputPosition
+)
+
+func (p TypePosition) String() string {
+	if p == InputPosition {
+		return "input"
+	}
+	return "output"
+}
+
+// TypesCompatible reports whether changing a position in the schema from type
+// oldRef to type newRef is safe for existing clients, following the GraphQL
+// rules for nullability variance and list nesting. Abstract types are resolved
+// against this schema. Nil references are never compatible.
+func (s *Schema) TypesCompatible(oldRef, newRef *TypeRef, position TypePosition) bool {
+	for depth := 0; depth <= maxTypeRefDepth; depth++ {
+		if oldRef == nil || newRef == nil {
+			return false
+		}
+
+		oldNonNull := oldRef.Kind == "NON_NULL"
+		newNonNull := newRef.Kind == "NON_NULL"
+		switch {
+		case oldNonNull && newNonNull:
+			oldRef, newRef = oldRef.OfType, newRef.OfType
+			continue
+		case newNonNull:
+			// Output may become stricter; input may not start requiring a value
+			if position == InputPosition {
+				return false
+			}
+			newRef = newRef.OfType
+			continue
+		case oldNonNull:
+			// Input may become optional; output may not start returning null
+			if position == OutputPosition {
+				return false
+			}
+			oldRef = oldRef.OfType
+			continue
+		}
+
+		oldList := oldRef.Kind == "LIST"
+		newList := newRef.Kind == "LIST"
+		if oldList != newList {
+			return false
+		}
+		if oldList {
+			oldRef, newRef = oldRef.OfType, newRef.OfType
+			continue
+		}
+
+		if position == InputPosition {
+			return oldRef.Name == newRef.Name
+		}
+		return s.isSubtype(newRef.Name, oldRef.Name)
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package schema
+
+import (
+	"testing"
+)
+
+func TestTypesCompatible(t *testing.T) {
+	s := loadRichSchema(t)
+
+	named := func(kind, name string) *TypeRef { return &TypeRef{Kind: kind, Name: name} }
+	nonNull := func(of *TypeRef) *TypeRef { return &TypeRef{Kind: "NON_NULL", OfType: of} }
+	list := func(of *TypeRef) *TypeRef { return &TypeRef{Kind: "LIST", OfType: of} }
+	str := named("SCALAR", "String")
+	integer := named("SCALAR", "Int")
+	node := named("INTERFACE", "Node")
+	issue := named("OBJECT", "Issue")
+	union := named("UNION", "IssueOrPullRequest")
+	user := named("OBJECT", "User")
+
+	tests := []struct {
+		name     string
+		old, new *TypeRef
+		position TypePosition
+		want     bool
+	}{
+		{"same type", str, str, OutputPosition, true},
+		{"different scalar", str, integer, OutputPosition, false},
+		{"output adds non-null", str, nonNull(str), OutputPosition, true},
+		{"output drops non-null", nonNull(str), str, OutputPosition, false},
+		{"input drops non-null", nonNull(str), str, InputPosition, true},
+		{"input adds non-null", str, nonNull(str), InputPosition, false},
+		{"output narrows interface to implementer", node, issue, OutputPosition, true},
+		{"output widens object to interface", issue, node, OutputPosition, false},
+		{"output narrows union to member", union, issue, OutputPosition, true},
+		{"output to non-member", union, user, OutputPosition, false},
+		{"input keeps named type", node, issue, InputPosition, false},
+		{"list item adds non-null", list(issue), list(nonNull(issue)), OutputPosition, true},
+		{"list item drops non-null in input", list(nonNull(str)), list(str), InputPosition, true},
+		{"list to scalar", list(str), str, OutputPosition, false},
+		{"nesting change", list(str), list(list(str)), OutputPosition, false},
+		{"nil reference", nil, str, OutputPosition, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.TypesCompatible(tt.old, tt.new, tt.position); got != tt.want {
+				t.Errorf("TypesCompatible(%s, %s, %s) = %v, want %v", tt.old, tt.new, tt.position, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is the format schema files are compressed with
+type Compression string
+
+const (
+	NoCompression Compression = ""
+	Gzip          Compression = "gzip"
+	// Zstd decompresses several times faster than gzip at a similar ratio,
+	// which shortens loading large schemas such as the embedded one
+	Zstd Compression = "zstd"
+)
+
+// ParseCompression parses a compression name: "gzip", "zstd", or "none" or
+// the empty string for no compression
+func ParseCompression(s string) (Compression, error) {
+	switch strings.ToLower(s) {
+	case "", "none":
+		return NoCompression, nil
+	case "gzip", "gz":
+		return Gzip, nil
+	case "zstd", "zst":
+		return Zstd, nil
+	}
+	return NoCompression, fmt.Errorf("unknown compression %q; use gzip, zstd, or none", s)
+}
+
+// CompressionForPath returns the compression a file name asks for by its
+// extension: Gzip for .gz, Zstd for .zst, and NoCompression otherwise
+func CompressionForPath(path string) Compression {
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		return Gzip
+	case strings.HasSuffix(path, ".zst"):
+		return Zstd
+	}
+	return NoCompression
+}
+
+// detectCompression returns the compression of data by its magic number
+func detectCompression(data []byte) Compression {
+	switch {
+	case isGzip(data):
+		return Gzip
+	case isZstd(data):
+		return Zstd
+	}
+	return NoCompression
+}
+
+// isZstd reports whether data starts with the zstd frame magic number
+func isZstd(data []byte) bool {
+	return len(data) >= 4 && data[0] == 0x28 && data[1] == 0xb5 && data[2] == 0x2f && data[3] == 0xfd
+}
+
+// decompress decompresses gzip or zstd data into a pooled buffer.
+// The caller must release the buffer with putBuffer once it is no longer referenced.
+func decompress(data []byte) (*bytes.Buffer, error) {
+	switch detectCompression(data) {
+	case Gzip:
+		return decompressGzip(data)
+	case Zstd:
+		return decompressZstd(data)
+	}
+	return nil, fmt.Errorf("failed to decompress schema: not gzip or zstd data")
+}
+
+// zstdDecoder decodes whole zstd streams; DecodeAll is safe for concurrent use
+var zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+	return zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxPooledBuffer<<2))
+})
+
+// decompressZstd decompresses data into a pooled buffer.
+// The caller must release the buffer with putBuffer once it is no longer referenced.
+func decompressZstd(data []byte) (*bytes.Buffer, error) {
+	dec, err := zstdDecoder()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+	}
+
+	buf := getBuffer()
+	// The frame header usually records the uncompressed size, which lets
+	// the stream be decoded straight into the buffer
+	var header zstd.Header
+	if header.Decode(data) == nil && header.HasFCS && header.FrameContentSize <= maxPooledBuffer {
+		buf.Grow(int(header.FrameContentSize))
+	}
+	out, err := dec.DecodeAll(data, buf.AvailableBuffer())
+	if err != nil {
+		putBuffer(buf)
+		return nil, fmt.Errorf("failed to decompress schema: %w", err)
+	}
+	buf.Write(out)
+	return buf, nil
+}
+
+// compressWriter returns a writer compressing the size bytes written to it
+// to w with c; the caller must close it to flush the compressed stream
+func compressWriter(w io.Writer, c Compression, size int64) (io.WriteCloser, error) {
+	switch c {
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		// Schema files are written once and loaded many times, so spend
+		// the time on a smaller file
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBestCompression), zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		// Recording the size in the frame header lets decompressZstd
+		// allocate the buffer once
+		enc.ResetContentSize(w, size)
+		return enc, nil
+	}
+	return nil, fmt.Errorf("unknown compression %q", c)
+}
@@ -0,0 +1,146 @@
+package schema
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseCompression(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Compression
+	}{
+		{"", NoCompression},
+		{"none", NoCompression},
+		{"gzip", Gzip},
+		{"GZ", Gzip},
+		{"zstd", Zstd},
+		{"zst", Zstd},
+	}
+	for _, tt := range tests {
+		if got, err := ParseCompression(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseCompression(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ParseCompression("brotli"); err == nil {
+		t.Error("Expected an error for an unknown compression")
+	}
+
+	for path, want := range map[string]Compression{"schema.json.gz": Gzip, "schema.json.zst": Zstd, "schema.json": NoCompression} {
+		if got := CompressionForPath(path); got != want {
+			t.Errorf("CompressionForPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestCompressionRoundTrip(t *testing.T) {
+	for _, c := range []Compression{Gzip, Zstd} {
+		t.Run(string(c), func(t *testing.T) {
+			var compressed bytes.Buffer
+			if err := writeSchema(&compressed, testSchemaData, c); err != nil {
+				t.Fatalf("writeSchema failed: %v", err)
+			}
+			if got := detectCompression(compressed.Bytes()); got != c {
+				t.Fatalf("Detected %q, want %q", got, c)
+			}
+			// Run twice so the second call reuses pooled buffers
+			for i := 0; i < 2; i++ {
+				buf, err := decompress(compressed.Bytes())
+				if err != nil {
+					t.Fatalf("decompress failed: %v", err)
+				}
+				if !bytes.Equal(buf.Bytes(), testSchemaData) {
+					t.Errorf("Decompressed data does not match original")
+				}
+				putBuffer(buf)
+			}
+
+			if _, err := NewWithData(compressed.Bytes()); err != nil {
+				t.Errorf("Failed to load compressed data: %v", err)
+			}
+			if _, err := NewWithReader(bytes.NewReader(compressed.Bytes())); err != nil {
+				t.Errorf("Failed to load compressed data from a reader: %v", err)
+			}
+			if l, err := NewLazyWithData(compressed.Bytes()); err != nil {
+				t.Errorf("Failed to load compressed data lazily: %v", err)
+			} else if _, err := l.Type("PullRequest"); err != nil {
+				t.Errorf("Failed to look up a type in the lazy schema: %v", err)
+			}
+
+			path := filepath.Join(t.TempDir(), "schema.json"+map[Compression]string{Gzip: ".gz", Zstd: ".zst"}[c])
+			if err := os.WriteFile(path, compressed.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := NewWithFile(path); err != nil {
+				t.Errorf("Failed to load compressed file: %v", err)
+			}
+			if _, err := NewLazyWithFile(path); err != nil {
+				t.Errorf("Failed to load compressed file lazily: %v", err)
+			}
+		})
+	}
+
+	if _, err := decompress([]byte("\x28\xb5\x2f\xfdnot zstd")); err == nil {
+		t.Error("Expected error for invalid zstd data")
+	}
+	if _, err := decompress(testSchemaData); err == nil {
+		t.Error("Expected error for uncompressed data")
+	}
+	if _, err := NewWithData([]byte("\x1f\x8bnot gzip")); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("Expected ErrInvalidSchema for invalid gzip data, got %v", err)
+	}
+}
+
+func TestDownloadZstd(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(SampleData())
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "schema.json.zst")
+	download := func(c Compression) error {
+		return Download(context.Background(), WithOutputPath(path), WithCompressionFormat(c),
+			WithEndpoint(server.URL), WithToken("token"), WithIfChanged(true))
+	}
+	if err := download(Zstd); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if meta := fileMetadata(path, Zstd); meta == nil || meta.Endpoint != server.URL {
+		t.Fatalf("Expected metadata in the zstd file, got %+v", meta)
+	}
+	if err := download(Zstd); !errors.Is(err, ErrUnchanged) {
+		t.Errorf("Expected ErrUnchanged, got %v", err)
+	}
+	// A gzip download replaces the zstd file
+	if err := download(Gzip); err != nil {
+		t.Fatalf("Gzip download failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !isGzip(data) {
+		t.Error("Expected the file to be replaced with a gzip stream")
+	}
+}
+
+func BenchmarkDecompress(b *testing.B) {
+	for _, c := range []Compression{Gzip, Zstd} {
+		var compressed bytes.Buffer
+		if err := writeSchema(&compressed, testSchemaData, c); err != nil {
+			b.Fatal(err)
+		}
+		b.Run(string(c), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf, err := decompress(compressed.Bytes())
+				if err != nil {
+					b.Fatal(err)
+				}
+				putBuffer(buf)
+			}
+		})
+	}
+}
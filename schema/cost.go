@@ -0,0 +1,283 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+)
+
+const (
+	// MaxPageSize is the largest first or last value GitHub accepts on a connection
+	MaxPageSize = 100
+
+	// costSaturation caps intermediate products so deeply nested connections
+	// cannot overflow
+	costSaturation = 1 << 40
+)
+
+// CostEstimate is the predicted size and rate limit cost of an operation,
+// computed the way GitHub documents it: every connection is assumed to
+// return as many nodes as its first or last argument allows.
+type CostEstimate struct {
+	Operation string `json:"operation"` // Empty for anonymous operations
+	Requests  int    `json:"requests"`  // Connection requests needed to fulfill the call
+	Nodes     int    `json:"nodes"`     // Maximum number of nodes returned
+	Cost      int    `json:"cost"`      // Rate limit points: requests / 100, rounded, at least 1
+}
+
+// EstimateCost estimates the rate limit cost of the only operation in doc.
+// Variables supply first and last values passed as variables; declared
+// defaults are used for variables that are not provided. Fields, arguments,
+// and fragments the schema does not define fail with a *QueryError, as do
+// connections without a first or last argument between 1 and MaxPageSize.
+func (s *Schema) EstimateCost(doc string, variables map[string]interface{}) (*CostEstimate, error) {
+	parsed, err := graphql.Parse(doc)
+	if err != nil {
+		return nil, err
+	}
+	return s.EstimateOperationCost(parsed, "", variables)
+}
+
+// EstimateOperationCost is like EstimateCost for a parsed document.
+// operationName may be empty when the document has a single operation.
+func (s *Schema) EstimateOperationCost(doc *graphql.Document, operationName string, variables map[string]interface{}) (*CostEstimate, error) {
+	op, root, w, err := s.costWalker(doc, operationName, variables)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.selectionSet(root, op.SelectionSet, 1, op.Pos, nil); err != nil {
+		return nil, err
+	}
+	return w.estimate(op), nil
+}
+
+// costWalker prepares a walk of the selected operation of doc, returning the
+// operation and its root type
+func (s *Schema) costWalker(doc *graphql.Document, operationName string, variables map[string]interface{}) (*graphql.OperationDefinition, string, *costWalker, error) {
+	op, err := doc.Operation(operationName)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	root := s.RootTypeName(string(op.Operation))
+	if root == "" {
+		return nil, "", nil, costError(op.Pos, "schema does not support %s operations", op.Operation)
+	}
+
+	w := &costWalker{
+		model:     s.Model(),
+		fragments: doc.Fragments(),
+		variables: make(map[string]interface{}),
+		active:    make(map[string]bool),
+	}
+	for _, def := range op.VariableDefinitions {
+		if def.DefaultValue != nil {
+			w.variables[def.Name] = def.DefaultValue.Interface(nil)
+		}
+	}
+	for name, value := range variables {
+		w.variables[name] = value
+	}
+	return op, root, w, nil
+}
+
+// estimate returns the totals of a finished walk
+func (w *costWalker) estimate(op *graphql.OperationDefinition) *CostEstimate {
+	cost := (w.requests + 50) / 100
+	if cost < 1 {
+		cost = 1
+	}
+	return &CostEstimate{Operation: op.Name, Requests: w.requests, Nodes: w.nodes, Cost: cost}
+}
+
+func costError(pos graphql.Position, format string, args ...interface{}) *QueryError {
+	return &QueryError{Message: fmt.Sprintf(format, args...), Line: pos.Line, Column: pos.Column}
+}
+
+// costWalker sums the connection requests and nodes of an operation
+type costWalker struct {
+	model     *Model
+	fragments map[string]*graphql.FragmentDefinition
+	variables map[string]interface{}
+	active    map[string]bool // Fragments being expanded, to detect cycles
+
+	requests int
+	nodes    int
+}
+
+// selectionSet walks a selection set on typeName. multiplier is the number of
+// times the set is resolved, i.e. the product of the enclosing page sizes.
+// When parent is not nil, a PlanNode is added to it for every selection.
+func (w *costWalker) selectionSet(typeName string, set graphql.SelectionSet, multiplier int, pos graphql.Position, parent *PlanNode) error {
+	t := w.model.Type(typeName)
+	if t == nil {
+		return costError(pos, "unknown type %q", typeName)
+	}
+
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *graphql.Field:
+			// Introspection fields are answered without touching data
+			if strings.HasPrefix(sel.Name, "__") {
+				continue
+			}
+			def := t.Field(sel.Name)
+			if def == nil {
+				return costError(sel.Pos, "field %q not found on type %q", sel.Name, typeName)
+			}
+			var node *PlanNode
+			if parent != nil {
+				node = parent.add(&PlanNode{
+					Name:  sel.ResponseKey(),
+					Path:  childPath(parent.Path, sel.ResponseKey()),
+					Field: typeName + "." + sel.Name,
+					Type:  def.Type.String(),
+				})
+			}
+			if err := w.field(sel, def, multiplier, node); err != nil {
+				return err
+			}
+		case *graphql.InlineFragment:
+			condition := typeName
+			if sel.TypeCondition != "" {
+				condition = sel.TypeCondition
+			}
+			var node *PlanNode
+			if parent != nil {
+				node = parent.add(&PlanNode{Name: "... on " + condition, Path: parent.Path, Type: condition})
+			}
+			if err := w.fragment(condition, sel.SelectionSet, multiplier, sel.Pos, node); err != nil {
+				return err
+			}
+		case *graphql.FragmentSpread:
+			fragment, ok := w.fragments[sel.Name]
+			if !ok {
+				return costError(sel.Pos, "unknown fragment %q", sel.Name)
+			}
+			if w.active[sel.Name] {
+				return costError(sel.Pos, "fragment %q spreads itself", sel.Name)
+			}
+			var node *PlanNode
+			if parent != nil {
+				node = parent.add(&PlanNode{Name: "..." + sel.Name, Path: parent.Path, Type: fragment.TypeCondition})
+			}
+			w.active[sel.Name] = true
+			err := w.fragment(fragment.TypeCondition, fragment.SelectionSet, multiplier, fragment.Pos, node)
+			delete(w.active, sel.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fragment walks the selection set of a fragment, totalling it into node
+func (w *costWalker) fragment(typeName string, set graphql.SelectionSet, multiplier int, pos graphql.Position, node *PlanNode) error {
+	requests, nodes := w.requests, w.nodes
+	if err := w.selectionSet(typeName, set, multiplier, pos, node); err != nil {
+		return err
+	}
+	if node != nil {
+		node.Resolved, node.Requests, node.Nodes = multiplier, w.requests-requests, w.nodes-nodes
+	}
+	return nil
+}
+
+func (w *costWalker) field(f *graphql.Field, def *Field, multiplier int, node *PlanNode) error {
+	for _, arg := range f.Arguments {
+		if findInputValue(def.Args, arg.Name) == nil {
+			return costError(arg.Pos, "unknown argument %q on field %q", arg.Name, f.Name)
+		}
+	}
+
+	requests, nodes := w.requests, w.nodes
+	if node != nil {
+		node.Resolved = multiplier
+		defer func() { node.Requests, node.Nodes = w.requests-requests, w.nodes-nodes }()
+	}
+
+	// GitHub connections are the fields paginated with both first and last
+	if findInputValue(def.Args, "first") != nil && findInputValue(def.Args, "last") != nil {
+		size, err := w.pageSize(f)
+		if err != nil {
+			return err
+		}
+		if node != nil {
+			node.PageSize = size
+		}
+		w.requests = saturatingAdd(w.requests, multiplier)
+		multiplier = saturatingMul(multiplier, size)
+		w.nodes = saturatingAdd(w.nodes, multiplier)
+	}
+
+	if len(f.SelectionSet) == 0 {
+		return nil
+	}
+	return w.selectionSet(def.Type.NamedType(), f.SelectionSet, multiplier, f.Pos, node)
+}
+
+// pageSize returns the number of nodes a connection field requests
+func (w *costWalker) pageSize(f *graphql.Field) (int, error) {
+	size := 0
+	for _, name := range []string{"first", "last"} {
+		arg := f.Argument(name)
+		if arg == nil {
+			continue
+		}
+		value := arg.Value.Interface(w.variables)
+		if value == nil {
+			continue
+		}
+		n, ok := toInt(value)
+		if !ok {
+			return 0, costError(arg.Pos, "argument %q must be an integer", name)
+		}
+		if n < 1 || n > MaxPageSize {
+			return 0, costError(arg.Pos, "argument %q must be between 1 and %d, got %d", name, MaxPageSize, n)
+		}
+		if n > size {
+			size = n
+		}
+	}
+	if size == 0 {
+		return 0, costError(f.Pos, "connection %q requires a first or last argument", f.Name)
+	}
+	return size, nil
+}
+
+// toInt converts a decoded JSON number to an int
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	case float64:
+		if n != float64(int(n)) {
+			return 0, false
+		}
+		return int(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	}
+	return 0, false
+}
+
+func saturatingAdd(a, b int) int {
+	if a+b > costSaturation {
+		return costSaturation
+	}
+	return a + b
+}
+
+func saturatingMul(a, b int) int {
+	if b != 0 && a > costSaturation/b {
+		return costSaturation
+	}
+	return a * b
+}
@@ -0,0 +1,33 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEstimateCost(t *testing.T) {
+	s := loadRichSchema(t)
+
+	got, err := s.EstimateCost(`query Nested($first: Int = 10) {
+  repository(owner: "o", name: "n") {
+    issues(first: $first) { nodes { repository { issues(last: 20) { totalCount } } } }
+  }
+}`, map[string]interface{}{"first": float64(50)})
+	if err != nil {
+		t.Fatalf("EstimateCost failed: %v", err)
+	}
+	want := CostEstimate{Operation: "Nested", Requests: 51, Nodes: 1050, Cost: 1}
+	if *got != want {
+		t.Errorf("EstimateCost = %+v, want %+v", *got, want)
+	}
+
+	_, err = s.EstimateCost(`{ viewer { issues(first: 500) { totalCount } } }`, nil)
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) || queryErr.Error() != `1:19: argument "first" must be between 1 and 100, got 500` {
+		t.Errorf("Expected a *QueryError for the page size, got %v", err)
+	}
+
+	if _, err := s.EstimateCost(`query A { viewer { id } } query B { viewer { id } }`, nil); err == nil {
+		t.Error("Expected error for a document with several operations")
+	}
+}
@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"regexp"
+	"sort"
+	"time"
+)
+
+// replacementPatterns match the phrasings GitHub uses to name the successor of
+// a deprecated member, such as "Use `Repository.isTemplate` instead.", "Use
+// the `assignee` field instead.", "Use PullRequest.isDraft instead.", "will be
+// replaced by `inviterActor`", and "in favour of the more capable
+// `ProjectV2View#fields` API". The first submatch is the replacement.
+var replacementPatterns = []*regexp.Regexp{
+	regexp.MustCompile("\\b[Uu]se (?:the |normalized )?`([^`]+)`"),
+	regexp.MustCompile(`\b[Uu]se ([A-Z]\w*\.\w+) instead`),
+	regexp.MustCompile("\\breplaced by (?:the )?`([^`]+)`"),
+	regexp.MustCompile("\\bin favou?r of (?:the )?(?:more capable )?`([^`]+)`"),
+}
+
+// DeprecationReplacement returns the replacement a deprecation reason
+// suggests, such as "Repository.isTemplate" for "Use `Repository.isTemplate`
+// instead.", or "" when the reason names none
+func DeprecationReplacement(reason string) string {
+	for _, p := range replacementPatterns {
+		if m := p.FindStringSubmatch(reason); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// removalPattern matches the removal date GitHub appends to the reasons of
+// members scheduled for removal, such as "Removal on 2025-01-01 UTC."
+var removalPattern = regexp.MustCompile(`\bRemoval on (\d{4}-\d{2}-\d{2}) UTC`)
+
+// DeprecationRemovalDate returns the date a deprecation reason schedules the
+// removal for, such as 2025-01-01 for "... Removal on 2025-01-01 UTC.", and
+// whether it names one
+func DeprecationRemovalDate(reason string) (time.Time, bool) {
+	m := removalPattern.FindStringSubmatch(reason)
+	if m == nil {
+		return time.Time{}, false
+	}
+	date, err := time.Parse(time.DateOnly, m[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// ScheduledRemoval is a deprecated member whose deprecation reason schedules
+// its removal, see ScheduledRemovals
+type ScheduledRemoval struct {
+	Coordinate  string    `json:"coordinate"` // Such as "Repository.squashPrTitleUsedAsDefault"
+	Date        time.Time `json:"date"`       // Midnight UTC of the removal day
+	Reason      string    `json:"reason"`
+	Replacement string    `json:"replacement,omitempty"` // As DeprecationReplacement finds it
+}
+
+// ScheduledRemovals returns the deprecated members whose reasons name a
+// removal date, ordered by date and then coordinate. Removals that are past
+// are included; callers announcing upcoming ones filter by Date.
+func (s *Schema) ScheduledRemovals() ([]ScheduledRemoval, error) {
+	deprecated, err := s.Deprecated()
+	if err != nil {
+		return nil, err
+	}
+	removals := []ScheduledRemoval{}
+	for _, t := range deprecated {
+		for _, m := range t.Members() {
+			date, ok := DeprecationRemovalDate(m.DeprecationReason)
+			if !ok {
+				continue
+			}
+			removals = append(removals, ScheduledRemoval{
+				Coordinate:  t.Name + "." + m.Name,
+				Date:        date,
+				Reason:      m.DeprecationReason,
+				Replacement: DeprecationReplacement(m.DeprecationReason),
+			})
+		}
+	}
+	sort.SliceStable(removals, func(i, j int) bool {
+		if !removals[i].Date.Equal(removals[j].Date) {
+			return removals[i].Date.Before(removals[j].Date)
+		}
+		return removals[i].Coordinate < removals[j].Coordinate
+	})
+	return removals, nil
+}
@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeprecationReplacement(t *testing.T) {
+	tests := []struct {
+		reason, want string
+	}{
+		{"Use `Repository.isTemplate` instead. Removal on 2025-01-01 UTC.", "Repository.isTemplate"},
+		{"Use the `assignee` field instead.", "assignee"},
+		{"Use PullRequest.isDraft instead.", "PullRequest.isDraft"},
+		{"The `invitee` field will be replaced by `inviterActor`.", "inviterActor"},
+		{"Deprecated in favour of the more capable `ProjectV2View#fields` API.", "ProjectV2View#fields"},
+		{"Use `id` or `number` instead.", "id"},
+		{"Locking is now tracked by `Issue.locked`.", ""},
+		{"No longer supported", ""},
+	}
+	for _, tt := range tests {
+		if got := DeprecationReplacement(tt.reason); got != tt.want {
+			t.Errorf("DeprecationReplacement(%q) = %q, want %q", tt.reason, got, tt.want)
+		}
+	}
+}
+
+func TestDeprecationRemovalDate(t *testing.T) {
+	date, ok := DeprecationRemovalDate("Use `Repository.isTemplate` instead. Removal on 2025-01-01 UTC.")
+	if !ok || !date.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("DeprecationRemovalDate = %v, %v", date, ok)
+	}
+	for _, reason := range []string{"No longer supported", "Removal on 2025-13-01 UTC."} {
+		if _, ok := DeprecationRemovalDate(reason); ok {
+			t.Errorf("Expected no removal date in %q", reason)
+		}
+	}
+}
+
+func TestScheduledRemovals(t *testing.T) {
+	s := loadRichSchema(t)
+	removals, err := s.ScheduledRemovals()
+	if err != nil {
+		t.Fatalf("ScheduledRemovals failed: %v", err)
+	}
+	if len(removals) != 1 {
+		t.Fatalf("Removals = %+v", removals)
+	}
+	r := removals[0]
+	if r.Coordinate != "Repository.isTemplateRepo" || r.Date.Format(time.DateOnly) != "2025-01-01" || r.Replacement != "Repository.isTemplate" {
+		t.Errorf("Removal = %+v", r)
+	}
+}
@@ -0,0 +1,418 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangeType identifies what changed between two schemas. The names follow
+// GraphQL Inspector, so reports can be compared with its output.
+type ChangeType string
+
+const (
+	TypeAdded              ChangeType = "TYPE_ADDED"
+	TypeRemoved            ChangeType = "TYPE_REMOVED"
+	TypeKindChanged        ChangeType = "TYPE_KIND_CHANGED"
+	TypeDescriptionChanged ChangeType = "TYPE_DESCRIPTION_CHANGED"
+	InterfaceAdded         ChangeType = "OBJECT_TYPE_INTERFACE_ADDED"
+	InterfaceRemoved       ChangeType = "OBJECT_TYPE_INTERFACE_REMOVED"
+	UnionMemberAdded       ChangeType = "UNION_MEMBER_ADDED"
+	UnionMemberRemoved     ChangeType = "UNION_MEMBER_REMOVED"
+
+	FieldAdded              ChangeType = "FIELD_ADDED"
+	FieldRemoved            ChangeType = "FIELD_REMOVED"
+	FieldTypeChanged        ChangeType = "FIELD_TYPE_CHANGED"
+	FieldDescriptionChanged ChangeType = "FIELD_DESCRIPTION_CHANGED"
+	FieldDeprecationAdded   ChangeType = "FIELD_DEPRECATION_ADDED"
+	FieldDeprecationRemoved ChangeType = "FIELD_DEPRECATION_REMOVED"
+
+	ArgumentAdded              ChangeType = "FIELD_ARGUMENT_ADDED"
+	ArgumentRemoved            ChangeType = "FIELD_ARGUMENT_REMOVED"
+	ArgumentTypeChanged        ChangeType = "FIELD_ARGUMENT_TYPE_CHANGED"
+	ArgumentDefaultChanged     ChangeType = "FIELD_ARGUMENT_DEFAULT_CHANGED"
+	ArgumentDescriptionChanged ChangeType = "FIELD_ARGUMENT_DESCRIPTION_CHANGED"
+	ArgumentDeprecationAdded   ChangeType = "FIELD_ARGUMENT_DEPRECATION_ADDED"
+	ArgumentDeprecationRemoved ChangeType = "FIELD_ARGUMENT_DEPRECATION_REMOVED"
+
+	InputFieldAdded              ChangeType = "INPUT_FIELD_ADDED"
+	InputFieldRemoved            ChangeType = "INPUT_FIELD_REMOVED"
+	InputFieldTypeChanged        ChangeType = "INPUT_FIELD_TYPE_CHANGED"
+	InputFieldDefaultChanged     ChangeType = "INPUT_FIELD_DEFAULT_VALUE_CHANGED"
+	InputFieldDescriptionChanged ChangeType = "INPUT_FIELD_DESCRIPTION_CHANGED"
+	InputFieldDeprecationAdded   ChangeType = "INPUT_FIELD_DEPRECATION_ADDED"
+	InputFieldDeprecationRemoved ChangeType = "INPUT_FIELD_DEPRECATION_REMOVED"
+
+	EnumValueAdded              ChangeType = "ENUM_VALUE_ADDED"
+	EnumValueRemoved            ChangeType = "ENUM_VALUE_REMOVED"
+	EnumValueDescriptionChanged ChangeType = "ENUM_VALUE_DESCRIPTION_CHANGED"
+	EnumValueDeprecationAdded   ChangeType = "ENUM_VALUE_DEPRECATION_ADDED"
+	EnumValueDeprecationRemoved ChangeType = "ENUM_VALUE_DEPRECATION_REMOVED"
+
+	DirectiveAdded   ChangeType = "DIRECTIVE_ADDED"
+	DirectiveRemoved ChangeType = "DIRECTIVE_REMOVED"
+)
+
+// Criticality classifies a change by its effect on existing clients, following
+// GraphQL Inspector
+type Criticality string
+
+const (
+	// Breaking changes make valid operations invalid, such as removed fields
+	Breaking Criticality = "BREAKING"
+	// Dangerous changes keep operations valid but may change what clients
+	// receive, such as new enum values or optional arguments
+	Dangerous Criticality = "DANGEROUS"
+	// NonBreaking changes are safe for existing clients, such as new types
+	NonBreaking Criticality = "NON_BREAKING"
+)
+
+// criticalities are the criticalities of change types that do not depend on
+// the changed values
+var criticalities = map[ChangeType]Criticality{
+	TypeAdded:              NonBreaking,
+	TypeRemoved:            Breaking,
+	TypeKindChanged:        Breaking,
+	TypeDescriptionChanged: NonBreaking,
+	InterfaceAdded:         Dangerous,
+	InterfaceRemoved:       Breaking,
+	UnionMemberAdded:       Dangerous,
+	UnionMemberRemoved:     Breaking,
+
+	FieldAdded:              NonBreaking,
+	FieldRemoved:            Breaking,
+	FieldDescriptionChanged: NonBreaking,
+	FieldDeprecationAdded:   NonBreaking,
+	FieldDeprecationRemoved: NonBreaking,
+
+	ArgumentRemoved:            Breaking,
+	ArgumentDefaultChanged:     Dangerous,
+	ArgumentDescriptionChanged: NonBreaking,
+	ArgumentDeprecationAdded:   NonBreaking,
+	ArgumentDeprecationRemoved: NonBreaking,
+
+	InputFieldRemoved:            Breaking,
+	InputFieldDefaultChanged:     Dangerous,
+	InputFieldDescriptionChanged: NonBreaking,
+	InputFieldDeprecationAdded:   NonBreaking,
+	InputFieldDeprecationRemoved: NonBreaking,
+
+	EnumValueAdded:              Dangerous,
+	EnumValueRemoved:            Breaking,
+	EnumValueDescriptionChanged: NonBreaking,
+	EnumValueDeprecationAdded:   NonBreaking,
+	EnumValueDeprecationRemoved: NonBreaking,
+
+	DirectiveAdded:   NonBreaking,
+	DirectiveRemoved: Breaking,
+}
+
+// Change is a single difference between two schemas, classified by its
+// Criticality. Path names the changed element as "Type", "Type.member",
+// "Type.field.argument", or "@directive". OldValue and NewValue hold the
+// compared values, such as types in GraphQL notation, when the change has them.
+type Change struct {
+	Type        ChangeType  `json:"type"`
+	Criticality Criticality `json:"criticality"`
+	Path        string      `json:"path"`
+	Message     string      `json:"message"`
+	OldValue    string      `json:"oldValue,omitempty"`
+	NewValue    string      `json:"newValue,omitempty"`
+}
+
+// ChangeSet is the result of DiffWith. Changes are ordered by type as in the
+// old schema, followed by the types only in the new schema, and then by
+// member in the same way.
+type ChangeSet struct {
+	Changes []Change `json:"changes"`
+}
+
+// Empty reports whether the schemas are equivalent
+func (c *ChangeSet) Empty() bool {
+	return len(c.Changes) == 0
+}
+
+// Filter returns the changes with the given criticality
+func (c *ChangeSet) Filter(level Criticality) []Change {
+	var changes []Change
+	for _, change := range c.Changes {
+		if change.Criticality == level {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// HasBreaking reports whether any change breaks existing clients
+func (c *ChangeSet) HasBreaking() bool {
+	for _, change := range c.Changes {
+		if change.Criticality == Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffWith compares s, the old schema, with other, the new one, and lists
+// the added, removed, and changed types, fields, arguments, input fields,
+// enum values, and directives. Type changes are classified with
+// TypesCompatible against other, so a field narrowed from an interface to one
+// of its implementations is not breaking.
+func (s *Schema) DiffWith(other *Schema) *ChangeSet {
+	return s.DiffWithEvents(other, nil)
+}
+
+// DiffWithEvents compares s with other like DiffWith, reporting the progress
+// of the comparison to handler as a "diff" operation counting the types of
+// both schemas
+func (s *Schema) DiffWithEvents(other *Schema, handler EventHandler) *ChangeSet {
+	d := &differ{schema: other, changes: []Change{}}
+	oldModel, newModel := s.Model(), other.Model()
+	progress := NewProgress("diff", "types", len(oldModel.Types)+len(newModel.Types), handler)
+
+	for _, oldType := range oldModel.Types {
+		if newType := newModel.Type(oldType.Name); newType != nil {
+			d.diffType(oldType, newType)
+		} else {
+			d.add(TypeRemoved, oldType.Name, fmt.Sprintf("Type %s was removed", oldType.Name), "", "")
+		}
+	}
+	progress.Step(len(oldModel.Types), "compared the old types")
+	for _, newType := range newModel.Types {
+		if oldModel.Type(newType.Name) == nil {
+			d.add(TypeAdded, newType.Name, fmt.Sprintf("Type %s was added", newType.Name), "", "")
+		}
+	}
+	progress.Step(len(newModel.Types), "looked for added types")
+
+	newDirectives := make(map[string]bool, len(newModel.Directives))
+	for _, dir := range newModel.Directives {
+		newDirectives[dir.Name] = true
+	}
+	oldDirectives := make(map[string]bool, len(oldModel.Directives))
+	for _, dir := range oldModel.Directives {
+		oldDirectives[dir.Name] = true
+		if !newDirectives[dir.Name] {
+			d.add(DirectiveRemoved, "@"+dir.Name, fmt.Sprintf("Directive @%s was removed", dir.Name), "", "")
+		}
+	}
+	for _, dir := range newModel.Directives {
+		if !oldDirectives[dir.Name] {
+			d.add(DirectiveAdded, "@"+dir.Name, fmt.Sprintf("Directive @%s was added", dir.Name), "", "")
+		}
+	}
+	progress.Finish(nil)
+	return &ChangeSet{Changes: d.changes}
+}
+
+// DiffWithEmbedded compares the embedded schema, as the old schema, with s,
+// such as a freshly downloaded schema, so updates can be reviewed without
+// keeping the previous file around
+func (s *Schema) DiffWithEmbedded() (*ChangeSet, error) {
+	embedded, err := Default()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded schema: %w", err)
+	}
+	return embedded.DiffWith(s), nil
+}
+
+type differ struct {
+	schema  *Schema // The new schema, against which type changes are classified
+	changes []Change
+}
+
+func (d *differ) add(t ChangeType, path, message, oldValue, newValue string) {
+	d.addClassified(t, criticalities[t], path, message, oldValue, newValue)
+}
+
+func (d *differ) addClassified(t ChangeType, level Criticality, path, message, oldValue, newValue string) {
+	d.changes = append(d.changes, Change{Type: t, Criticality: level, Path: path, Message: message, OldValue: oldValue, NewValue: newValue})
+}
+
+func (d *differ) diffType(oldType, newType *Type) {
+	name := newType.Name
+	if oldType.Kind != newType.Kind {
+		d.add(TypeKindChanged, name, fmt.Sprintf("%s changed from %s to %s", name, oldType.Kind, newType.Kind), oldType.Kind, newType.Kind)
+		return
+	}
+	if oldType.Description != newType.Description {
+		d.add(TypeDescriptionChanged, name, fmt.Sprintf("Description of %s changed", name), oldType.Description, newType.Description)
+	}
+
+	removed, added := diffNames(oldType.Interfaces, newType.Interfaces)
+	for _, i := range removed {
+		d.add(InterfaceRemoved, name, fmt.Sprintf("%s no longer implements %s", name, i), i, "")
+	}
+	for _, i := range added {
+		d.add(InterfaceAdded, name, fmt.Sprintf("%s implements %s", name, i), "", i)
+	}
+	if newType.Kind == "UNION" {
+		removed, added := diffNames(oldType.PossibleTypes, newType.PossibleTypes)
+		for _, m := range removed {
+			d.add(UnionMemberRemoved, name, fmt.Sprintf("%s was removed from union %s", m, name), m, "")
+		}
+		for _, m := range added {
+			d.add(UnionMemberAdded, name, fmt.Sprintf("%s was added to union %s", m, name), "", m)
+		}
+	}
+
+	for _, f := range oldType.Fields {
+		if nf := newType.Field(f.Name); nf != nil {
+			d.diffField(name, f, nf)
+		} else {
+			d.add(FieldRemoved, name+"."+f.Name, fmt.Sprintf("Field %s.%s was removed", name, f.Name), "", "")
+		}
+	}
+	for _, f := range newType.Fields {
+		if oldType.Field(f.Name) == nil {
+			d.add(FieldAdded, name+"."+f.Name, fmt.Sprintf("Field %s.%s was added", name, f.Name), "", f.Type.String())
+		}
+	}
+
+	d.diffInputValues(name, oldType.InputFields, newType.InputFields, inputFieldChanges)
+
+	oldValues := make(map[string]*EnumValue, len(oldType.EnumValues))
+	for _, v := range oldType.EnumValues {
+		oldValues[v.Name] = v
+	}
+	newValues := make(map[string]*EnumValue, len(newType.EnumValues))
+	for _, v := range newType.EnumValues {
+		newValues[v.Name] = v
+	}
+	for _, v := range oldType.EnumValues {
+		path := name + "." + v.Name
+		nv := newValues[v.Name]
+		if nv == nil {
+			d.add(EnumValueRemoved, path, fmt.Sprintf("Enum value %s was removed", path), "", "")
+			continue
+		}
+		if v.Description != nv.Description {
+			d.add(EnumValueDescriptionChanged, path, fmt.Sprintf("Description of %s changed", path), v.Description, nv.Description)
+		}
+		d.diffDeprecation(path, v.IsDeprecated, nv.IsDeprecated, nv.DeprecationReason, EnumValueDeprecationAdded, EnumValueDeprecationRemoved)
+	}
+	for _, v := range newType.EnumValues {
+		if oldValues[v.Name] == nil {
+			d.add(EnumValueAdded, name+"."+v.Name, fmt.Sprintf("Enum value %s.%s was added", name, v.Name), "", "")
+		}
+	}
+}
+
+func (d *differ) diffField(typeName string, oldField, newField *Field) {
+	path := typeName + "." + newField.Name
+	if oldType, newType := oldField.Type.String(), newField.Type.String(); oldType != newType {
+		level := Breaking
+		if d.schema.TypesCompatible(oldField.Type, newField.Type, OutputPosition) {
+			level = NonBreaking
+		}
+		d.addClassified(FieldTypeChanged, level, path, fmt.Sprintf("Field %s changed type from %s to %s", path, oldType, newType), oldType, newType)
+	}
+	if oldField.Description != newField.Description {
+		d.add(FieldDescriptionChanged, path, fmt.Sprintf("Description of %s changed", path), oldField.Description, newField.Description)
+	}
+	d.diffDeprecation(path, oldField.IsDeprecated, newField.IsDeprecated, newField.DeprecationReason, FieldDeprecationAdded, FieldDeprecationRemoved)
+	d.diffInputValues(path, oldField.Args, newField.Args, argumentChanges)
+}
+
+func (d *differ) diffDeprecation(path string, wasDeprecated, isDeprecated bool, reason string, added, removed ChangeType) {
+	switch {
+	case !wasDeprecated && isDeprecated:
+		d.add(added, path, fmt.Sprintf("%s is deprecated", path), "", reason)
+	case wasDeprecated && !isDeprecated:
+		d.add(removed, path, fmt.Sprintf("%s is no longer deprecated", path), "", "")
+	}
+}
+
+// inputValueChanges are the change types for arguments or input fields
+type inputValueChanges struct {
+	noun                                                      string
+	added, removed, typeChanged, defaultChanged, descrChanged ChangeType
+	deprecationAdded, deprecationRemoved                      ChangeType
+}
+
+var (
+	argumentChanges = inputValueChanges{"Argument", ArgumentAdded, ArgumentRemoved, ArgumentTypeChanged, ArgumentDefaultChanged, ArgumentDescriptionChanged,
+		ArgumentDeprecationAdded, ArgumentDeprecationRemoved}
+	inputFieldChanges = inputValueChanges{"Input field", InputFieldAdded, InputFieldRemoved, InputFieldTypeChanged, InputFieldDefaultChanged, InputFieldDescriptionChanged,
+		InputFieldDeprecationAdded, InputFieldDeprecationRemoved}
+)
+
+// diffInputValues compares the arguments of a field or the fields of an input
+// object. Added values carry their type and whether they are required in
+// NewValue, since that decides whether existing callers break.
+func (d *differ) diffInputValues(owner string, oldValues, newValues []*InputValue, c inputValueChanges) {
+	for _, v := range oldValues {
+		path := owner + "." + v.Name
+		nv := findInputValue(newValues, v.Name)
+		if nv == nil {
+			d.add(c.removed, path, fmt.Sprintf("%s %s was removed", c.noun, path), v.Type.String(), "")
+			continue
+		}
+		if oldType, newType := v.Type.String(), nv.Type.String(); oldType != newType {
+			level := Breaking
+			if d.schema.TypesCompatible(v.Type, nv.Type, InputPosition) {
+				level = NonBreaking
+			}
+			d.addClassified(c.typeChanged, level, path, fmt.Sprintf("%s %s changed type from %s to %s", c.noun, path, oldType, newType), oldType, newType)
+		}
+		if oldDefault, newDefault := defaultLiteral(v), defaultLiteral(nv); oldDefault != newDefault {
+			d.add(c.defaultChanged, path, fmt.Sprintf("Default value of %s changed from %s to %s", path, orNone(oldDefault), orNone(newDefault)), oldDefault, newDefault)
+		}
+		if v.Description != nv.Description {
+			d.add(c.descrChanged, path, fmt.Sprintf("Description of %s changed", path), v.Description, nv.Description)
+		}
+		// Deprecated arguments and input fields are only introspected with
+		// args and inputFields(includeDeprecated: true)
+		d.diffDeprecation(path, v.IsDeprecated, nv.IsDeprecated, nv.DeprecationReason, c.deprecationAdded, c.deprecationRemoved)
+	}
+	for _, v := range newValues {
+		if findInputValue(oldValues, v.Name) == nil {
+			message, level := fmt.Sprintf("%s %s.%s was added", c.noun, owner, v.Name), Dangerous
+			if v.Required() {
+				message, level = fmt.Sprintf("Required %s %s.%s was added", lowerFirst(c.noun), owner, v.Name), Breaking
+			}
+			d.addClassified(c.added, level, owner+"."+v.Name, message, "", v.Type.String())
+		}
+	}
+}
+
+func defaultLiteral(v *InputValue) string {
+	if v.DefaultValue == nil {
+		return ""
+	}
+	return *v.DefaultValue
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// diffNames returns the names only in a and only in b, each in input order
+func diffNames(a, b []string) (onlyA, onlyB []string) {
+	inA := make(map[string]bool, len(a))
+	for _, name := range a {
+		inA[name] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, name := range b {
+		inB[name] = true
+		if !inA[name] {
+			onlyB = append(onlyB, name)
+		}
+	}
+	for _, name := range a {
+		if !inB[name] {
+			onlyA = append(onlyA, name)
+		}
+	}
+	return onlyA, onlyB
+}
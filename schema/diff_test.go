@@ -0,0 +1,284 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+// modifiedSample returns the synthetic schema after update has edited its types,
+// keyed by name
+func modifiedSample(t *testing.T, update func(types map[string]map[string]interface{})) *Schema {
+	t.Helper()
+	var doc map[string]interface{}
+	if err := yamlformat.Unmarshal(syntheticData(t), &doc); err != nil {
+		t.Fatalf("Failed to decode synthetic schema: %v", err)
+	}
+	schema := doc["data"].(map[string]interface{})["__schema"].(map[string]interface{})
+	types := make(map[string]map[string]interface{})
+	var kept []interface{}
+	for _, item := range schema["types"].([]interface{}) {
+		entry := item.(map[string]interface{})
+		types[entry["name"].(string)] = entry
+	}
+	update(types)
+	// Types deleted from the map are removed; new ones are appended
+	seen := make(map[string]bool)
+	for _, item := range schema["types"].([]interface{}) {
+		name := item.(map[string]interface{})["name"].(string)
+		if entry, ok := types[name]; ok {
+			kept = append(kept, entry)
+			seen[name] = true
+		}
+	}
+	for name, entry := range types {
+		if !seen[name] {
+			kept = append(kept, entry)
+		}
+	}
+	schema["types"] = kept
+
+	data, err := yamlformat.MarshalJSON(doc)
+	if err != nil {
+		t.Fatalf("Failed to encode modified sample: %v", err)
+	}
+	// Types are deleted without chasing the references to them, which
+	// NewWithData rejects, so only the structure is checked
+	var v interface{}
+	if err := yamlformat.Unmarshal(data, &v); err != nil {
+		t.Fatalf("Failed to decode modified sample: %v", err)
+	}
+	if err := checkStructure(v); err != nil {
+		t.Fatalf("Failed to load modified sample: %v", err)
+	}
+	return &Schema{data: v}
+}
+
+func member(t *testing.T, entry map[string]interface{}, key, name string) map[string]interface{} {
+	t.Helper()
+	for _, item := range entry[key].([]interface{}) {
+		if m := item.(map[string]interface{}); m["name"] == name {
+			return m
+		}
+	}
+	t.Fatalf("%s has no %s %q", entry["name"], key, name)
+	return nil
+}
+
+func removeMember(entry map[string]interface{}, key, name string) {
+	var kept []interface{}
+	for _, item := range entry[key].([]interface{}) {
+		if item.(map[string]interface{})["name"] != name {
+			kept = append(kept, item)
+		}
+	}
+	entry[key] = kept
+}
+
+func TestDiffWithIdentical(t *testing.T) {
+	s := loadRichSchema(t)
+	if changes := s.DiffWith(loadRichSchema(t)); !changes.Empty() {
+		t.Errorf("Expected no changes, got %+v", changes.Changes)
+	}
+}
+
+func TestDiffWith(t *testing.T) {
+	oldSchema := loadRichSchema(t)
+	newSchema := modifiedSample(t, func(types map[string]map[string]interface{}) {
+		delete(types, "AddStarPayload")
+		types["Label"] = map[string]interface{}{
+			"kind": "OBJECT", "name": "Label", "description": "A label.", "interfaces": []interface{}{},
+			"fields": []interface{}{map[string]interface{}{
+				"name": "name", "description": "", "args": []interface{}{}, "isDeprecated": false, "deprecationReason": nil,
+				"type": map[string]interface{}{"kind": "NON_NULL", "name": nil, "ofType": map[string]interface{}{"kind": "SCALAR", "name": "String", "ofType": nil}},
+			}},
+		}
+
+		repo := types["Repository"]
+		removeMember(repo, "fields", "hasIssuesEnabled")
+		member(t, repo, "fields", "name")["description"] = "The repository name."
+		issues := member(t, repo, "fields", "issues")
+		removeMember(issues, "args", "states")
+		member(t, issues, "args", "first")["type"] = map[string]interface{}{"kind": "NON_NULL", "name": nil, "ofType": map[string]interface{}{"kind": "SCALAR", "name": "Int", "ofType": nil}}
+		issues["isDeprecated"] = true
+		issues["deprecationReason"] = "Use search."
+		after := member(t, issues, "args", "after")
+		after["isDeprecated"] = true
+		after["deprecationReason"] = "Use `before`."
+		member(t, types["IssueLocatorInput"], "inputFields", "url")["isDeprecated"] = false
+
+		removeMember(types["IssueState"], "enumValues", "CLOSED")
+		member(t, types["IssueMetadataInput"], "inputFields", "priority")["defaultValue"] = "1"
+		types["IssueOrPullRequest"]["possibleTypes"] = []interface{}{map[string]interface{}{"kind": "OBJECT", "name": "Issue", "ofType": nil}}
+	})
+
+	changes := newSchema.DiffWith(newSchema)
+	if !changes.Empty() {
+		t.Fatalf("Expected no changes against itself, got %+v", changes.Changes)
+	}
+
+	got := make(map[string]Change)
+	for _, c := range oldSchema.DiffWith(newSchema).Changes {
+		got[string(c.Type)+" "+c.Path] = c
+	}
+	for key, want := range map[string]Change{
+		"TYPE_REMOVED AddStarPayload":                                   {Criticality: Breaking},
+		"TYPE_ADDED Label":                                              {Criticality: NonBreaking},
+		"FIELD_REMOVED Repository.hasIssuesEnabled":                     {Criticality: Breaking},
+		"FIELD_DESCRIPTION_CHANGED Repository.name":                     {Criticality: NonBreaking, OldValue: "The name of the repository.", NewValue: "The repository name."},
+		"FIELD_ARGUMENT_REMOVED Repository.issues.states":               {Criticality: Breaking, OldValue: "[IssueState!]"},
+		"FIELD_ARGUMENT_TYPE_CHANGED Repository.issues.first":           {Criticality: Breaking, OldValue: "Int", NewValue: "Int!"},
+		"FIELD_DEPRECATION_ADDED Repository.issues":                     {Criticality: NonBreaking, NewValue: "Use search."},
+		"FIELD_ARGUMENT_DEPRECATION_ADDED Repository.issues.after":      {Criticality: NonBreaking, NewValue: "Use `before`."},
+		"INPUT_FIELD_DEPRECATION_REMOVED IssueLocatorInput.url":         {Criticality: NonBreaking},
+		"ENUM_VALUE_REMOVED IssueState.CLOSED":                          {Criticality: Breaking},
+		"INPUT_FIELD_DEFAULT_VALUE_CHANGED IssueMetadataInput.priority": {Criticality: Dangerous, OldValue: "3", NewValue: "1"},
+		"UNION_MEMBER_REMOVED IssueOrPullRequest":                       {Criticality: Breaking, OldValue: "PullRequest"},
+	} {
+		c, ok := got[key]
+		if !ok {
+			t.Errorf("Missing change %s", key)
+			continue
+		}
+		if c.Criticality != want.Criticality || c.OldValue != want.OldValue || c.NewValue != want.NewValue || c.Message == "" {
+			t.Errorf("Unexpected %s: %+v", key, c)
+		}
+		delete(got, key)
+	}
+	for key, c := range got {
+		t.Errorf("Unexpected change %s: %+v", key, c)
+	}
+}
+
+func TestDiffWithOrder(t *testing.T) {
+	oldSchema := loadRichSchema(t)
+	newSchema := modifiedSample(t, func(types map[string]map[string]interface{}) {
+		delete(types, "User")
+		delete(types, "Query")
+		types["Zebra"] = map[string]interface{}{"kind": "SCALAR", "name": "Zebra", "description": ""}
+	})
+	var paths []string
+	for _, c := range oldSchema.DiffWith(newSchema).Changes {
+		if c.Type == TypeAdded || c.Type == TypeRemoved {
+			paths = append(paths, c.Path)
+		}
+	}
+	// Removed types in old schema order, then added types
+	if len(paths) != 3 || paths[0] != "Query" || paths[1] != "User" || paths[2] != "Zebra" {
+		t.Errorf("Unexpected order: %v", paths)
+	}
+}
+
+func TestDiffWithEvents(t *testing.T) {
+	oldSchema := loadRichSchema(t)
+	newSchema := modifiedSample(t, func(types map[string]map[string]interface{}) {
+		delete(types, "User")
+	})
+	var events []Event
+	changes := oldSchema.DiffWithEvents(newSchema, func(ev Event) { events = append(events, ev) })
+	if changes.Empty() {
+		t.Error("Expected the removed type to be reported")
+	}
+
+	total := int64(len(oldSchema.Model().Types) + len(newSchema.Model().Types))
+	if len(events) != 4 {
+		t.Fatalf("Expected start, two progress, and done events, got %+v", events)
+	}
+	phases := []EventPhase{EventStart, EventProgress, EventProgress, EventDone}
+	for i, ev := range events {
+		if ev.Operation != "diff" || ev.Phase != phases[i] || ev.Unit != "types" || ev.Total != total {
+			t.Errorf("Event %d = %+v, want a diff %s event over %d types", i, ev, phases[i], total)
+		}
+	}
+	if done := events[3]; done.Current != total {
+		t.Errorf("Done event counts %d of %d types", done.Current, total)
+	}
+}
+
+func TestDiffWithCriticality(t *testing.T) {
+	nonNull := func(name string) map[string]interface{} {
+		return map[string]interface{}{"kind": "NON_NULL", "name": nil, "ofType": map[string]interface{}{"kind": "SCALAR", "name": name, "ofType": nil}}
+	}
+	oldSchema := loadRichSchema(t)
+	newSchema := modifiedSample(t, func(types map[string]map[string]interface{}) {
+		repo := types["Repository"]
+		// Output fields may become non-null but not nullable
+		member(t, repo, "fields", "issueOrPullRequest")["type"] = map[string]interface{}{"kind": "NON_NULL", "name": nil, "ofType": map[string]interface{}{"kind": "UNION", "name": "IssueOrPullRequest", "ofType": nil}}
+		member(t, repo, "fields", "stargazerCount")["type"] = map[string]interface{}{"kind": "SCALAR", "name": "Int", "ofType": nil}
+		// and may narrow an interface to an implementation but not widen
+		member(t, types["Query"], "fields", "node")["type"] = map[string]interface{}{"kind": "OBJECT", "name": "Issue", "ofType": nil}
+		member(t, types["IssueEdge"], "fields", "node")["type"] = map[string]interface{}{"kind": "INTERFACE", "name": "Node", "ofType": nil}
+		issues := member(t, repo, "fields", "issues")
+		issues["args"] = append(issues["args"].([]interface{}),
+			map[string]interface{}{"name": "labels", "description": "", "type": map[string]interface{}{"kind": "SCALAR", "name": "String", "ofType": nil}, "defaultValue": nil},
+			map[string]interface{}{"name": "query", "description": "", "type": nonNull("String"), "defaultValue": nil},
+			map[string]interface{}{"name": "limit", "description": "", "type": nonNull("Int"), "defaultValue": "10"},
+		)
+
+		// Input fields may become nullable but not non-null
+		input := types["IssueMetadataInput"]
+		member(t, input, "inputFields", "priority")["type"] = nonNull("Int")
+		types["IssueState"]["enumValues"] = append(types["IssueState"]["enumValues"].([]interface{}),
+			map[string]interface{}{"name": "ARCHIVED", "description": "", "isDeprecated": false, "deprecationReason": nil})
+	})
+
+	changes := oldSchema.DiffWith(newSchema)
+	got := make(map[string]Criticality)
+	for _, c := range changes.Changes {
+		got[string(c.Type)+" "+c.Path] = c.Criticality
+	}
+	for key, want := range map[string]Criticality{
+		"FIELD_TYPE_CHANGED Repository.issueOrPullRequest":     NonBreaking,
+		"FIELD_TYPE_CHANGED Repository.stargazerCount":         Breaking,
+		"FIELD_TYPE_CHANGED Query.node":                        NonBreaking,
+		"FIELD_TYPE_CHANGED IssueEdge.node":                    Breaking,
+		"FIELD_ARGUMENT_ADDED Repository.issues.labels":        Dangerous,
+		"FIELD_ARGUMENT_ADDED Repository.issues.query":         Breaking,
+		"FIELD_ARGUMENT_ADDED Repository.issues.limit":         Dangerous,
+		"INPUT_FIELD_TYPE_CHANGED IssueMetadataInput.priority": Breaking,
+		"ENUM_VALUE_ADDED IssueState.ARCHIVED":                 Dangerous,
+	} {
+		if got[key] != want {
+			t.Errorf("%s: expected %s, got %q", key, want, got[key])
+		}
+	}
+	if !changes.HasBreaking() || len(changes.Filter(Breaking)) != 4 {
+		t.Errorf("Expected 4 breaking changes, got %+v", changes.Filter(Breaking))
+	}
+
+	// The reverse direction relaxes the input field, which is safe
+	for _, c := range newSchema.DiffWith(oldSchema).Changes {
+		if c.Type == InputFieldTypeChanged && c.Criticality != NonBreaking {
+			t.Errorf("Expected nullable input field to be non-breaking, got %+v", c)
+		}
+	}
+}
+
+func TestDiffWithEmbedded(t *testing.T) {
+	changes, err := loadRichSchema(t).DiffWithEmbedded()
+	if err != nil {
+		t.Fatalf("DiffWithEmbedded failed: %v", err)
+	}
+	// The sample is mostly a subset of the embedded schema, so the rest shows
+	// up as removed types
+	removed := make(map[string]bool)
+	for _, c := range changes.Changes {
+		if c.Type == TypeRemoved {
+			removed[c.Path] = true
+		}
+	}
+	if !removed["Commit"] || removed["Repository"] {
+		t.Errorf("Expected Commit but not Repository to be removed, got %d removed types", len(removed))
+	}
+
+	if testing.Short() {
+		return
+	}
+	s, err := New()
+	if err != nil {
+		t.Fatalf("Failed to load embedded schema: %v", err)
+	}
+	if changes, err := s.DiffWithEmbedded(); err != nil || !changes.Empty() {
+		t.Errorf("Expected no changes against itself, got %v, %v", changes, err)
+	}
+}
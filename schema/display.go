@@ -0,0 +1,233 @@
+package schema
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// typeDoc is the documentation of a type as TypeMarkdown and TypeHTML
+// render it
+type typeDoc struct {
+	name, kind, description string
+	related                 []relatedTypes
+	column                  string // Heading of the name column: Field, Input field, or Value
+	rows                    []docRow
+}
+
+// relatedTypes are the interfaces or possible types of a type
+type relatedTypes struct {
+	label string
+	names []string
+}
+
+type docRow struct {
+	name, typ, description string
+	args                   []string // As "name: Type"
+	deprecated             bool
+	deprecationReason      string
+}
+
+func (m *Model) typeDoc(name string) (*typeDoc, error) {
+	t := m.Type(name)
+	if t == nil {
+		return nil, m.typeNotFound(name)
+	}
+	doc := &typeDoc{name: t.Name, kind: t.Kind, description: t.Description}
+	if len(t.Interfaces) > 0 {
+		doc.related = append(doc.related, relatedTypes{"Implements", t.Interfaces})
+	}
+	if len(t.PossibleTypes) > 0 {
+		doc.related = append(doc.related, relatedTypes{"Possible types", t.PossibleTypes})
+	}
+	switch {
+	case len(t.Fields) > 0:
+		doc.column = "Field"
+		for _, f := range t.Fields {
+			row := docRow{name: f.Name, typ: f.Type.String(), description: f.Description, deprecated: f.IsDeprecated, deprecationReason: f.DeprecationReason}
+			for _, arg := range f.Args {
+				row.args = append(row.args, arg.Name+": "+arg.Type.String())
+			}
+			doc.rows = append(doc.rows, row)
+		}
+	case len(t.InputFields) > 0:
+		doc.column = "Input field"
+		for _, f := range t.InputFields {
+			typ := f.Type.String()
+			if f.DefaultValue != nil {
+				typ += " = " + *f.DefaultValue
+			}
+			doc.rows = append(doc.rows, docRow{name: f.Name, typ: typ, description: f.Description, deprecated: f.IsDeprecated, deprecationReason: f.DeprecationReason})
+		}
+	case len(t.EnumValues) > 0:
+		doc.column = "Value"
+		for _, v := range t.EnumValues {
+			doc.rows = append(doc.rows, docRow{name: v.Name, description: v.Description, deprecated: v.IsDeprecated, deprecationReason: v.DeprecationReason})
+		}
+	}
+	return doc, nil
+}
+
+// TypeMarkdown renders the documentation of a type as Markdown, for Go
+// notebooks and dashboards: a heading with its kind, its description, the
+// interfaces or possible types, and a table of its fields, input fields, or
+// enum values with their arguments and deprecations. Unknown types fail
+// with a *NotFoundError suggesting similar names.
+func (s *Schema) TypeMarkdown(typeName string) (string, error) {
+	doc, err := s.Model().typeDoc(typeName)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s (%s)\n", markdownCode(doc.name), doc.kind)
+	if doc.description != "" {
+		b.WriteString("\n" + strings.TrimSpace(doc.description) + "\n")
+	}
+	for _, r := range doc.related {
+		codes := make([]string, len(r.names))
+		for i, name := range r.names {
+			codes[i] = markdownCode(name)
+		}
+		fmt.Fprintf(&b, "\n%s: %s\n", r.label, strings.Join(codes, ", "))
+	}
+	if len(doc.rows) == 0 {
+		return b.String(), nil
+	}
+
+	withType := doc.column != "Value"
+	b.WriteString("\n| " + doc.column)
+	if withType {
+		b.WriteString(" | Type")
+	}
+	b.WriteString(" | Description |\n| ---")
+	if withType {
+		b.WriteString(" | ---")
+	}
+	b.WriteString(" | --- |\n")
+	for _, row := range doc.rows {
+		b.WriteString("| " + markdownCode(row.name))
+		if withType {
+			b.WriteString(" | " + markdownCode(row.typ))
+		}
+		var description []string
+		if row.deprecated {
+			description = append(description, "**Deprecated:** "+markdownText(row.deprecationReason))
+		}
+		if row.description != "" {
+			description = append(description, markdownText(row.description))
+		}
+		if len(row.args) > 0 {
+			args := make([]string, len(row.args))
+			for i, arg := range row.args {
+				args[i] = markdownCode(arg)
+			}
+			description = append(description, "Arguments: "+strings.Join(args, ", "))
+		}
+		b.WriteString(" | " + strings.Join(description, "<br>") + " |\n")
+	}
+	return b.String(), nil
+}
+
+// TypeHTML renders the same documentation as TypeMarkdown as an HTML
+// fragment in a div of class "github-schema-type". Descriptions are escaped
+// rather than rendered as Markdown.
+func (s *Schema) TypeHTML(typeName string) (string, error) {
+	doc, err := s.Model().typeDoc(typeName)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString(`<div class="github-schema-type">` + "\n")
+	fmt.Fprintf(&b, "<h3>%s <small>%s</small></h3>\n", htmlCode(doc.name), doc.kind)
+	if doc.description != "" {
+		b.WriteString("<p>" + htmlText(strings.TrimSpace(doc.description)) + "</p>\n")
+	}
+	for _, r := range doc.related {
+		codes := make([]string, len(r.names))
+		for i, name := range r.names {
+			codes[i] = htmlCode(name)
+		}
+		fmt.Fprintf(&b, "<p>%s: %s</p>\n", r.label, strings.Join(codes, ", "))
+	}
+	if len(doc.rows) > 0 {
+		withType := doc.column != "Value"
+		b.WriteString("<table>\n<thead><tr><th>" + doc.column + "</th>")
+		if withType {
+			b.WriteString("<th>Type</th>")
+		}
+		b.WriteString("<th>Description</th></tr></thead>\n<tbody>\n")
+		for _, row := range doc.rows {
+			b.WriteString("<tr><td>" + htmlCode(row.name) + "</td>")
+			if withType {
+				b.WriteString("<td>" + htmlCode(row.typ) + "</td>")
+			}
+			var description []string
+			if row.deprecated {
+				description = append(description, "<strong>Deprecated:</strong> "+htmlText(row.deprecationReason))
+			}
+			if row.description != "" {
+				description = append(description, htmlText(row.description))
+			}
+			if len(row.args) > 0 {
+				args := make([]string, len(row.args))
+				for i, arg := range row.args {
+					args[i] = htmlCode(arg)
+				}
+				description = append(description, "Arguments: "+strings.Join(args, ", "))
+			}
+			b.WriteString("<td>" + strings.Join(description, "<br>") + "</td></tr>\n")
+		}
+		b.WriteString("</tbody>\n</table>\n")
+	}
+	b.WriteString("</div>\n")
+	return b.String(), nil
+}
+
+// SearchMarkdown renders the result of Search as a Markdown table of the
+// matching types with their kinds and descriptions
+func (s *Schema) SearchMarkdown(pattern string) (string, error) {
+	result, err := s.SearchTypes(pattern)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d types matching %s\n", result.Count, markdownCode(pattern))
+	if len(result.Results) == 0 {
+		return b.String(), nil
+	}
+	b.WriteString("\n| Type | Kind | Description |\n| --- | --- | --- |\n")
+	for _, match := range result.Results {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", markdownCode(match.Name), match.Kind, markdownText(match.Description))
+	}
+	return b.String(), nil
+}
+
+// SearchHTML renders the result of Search as an HTML table in a div of class
+// "github-schema-search"
+func (s *Schema) SearchHTML(pattern string) (string, error) {
+	result, err := s.SearchTypes(pattern)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString(`<div class="github-schema-search">` + "\n")
+	fmt.Fprintf(&b, "<p>%d types matching %s</p>\n", result.Count, htmlCode(pattern))
+	if len(result.Results) > 0 {
+		b.WriteString("<table>\n<thead><tr><th>Type</th><th>Kind</th><th>Description</th></tr></thead>\n<tbody>\n")
+		for _, match := range result.Results {
+			fmt.Fprintf(&b, "<tr><td>%s</td><td>%s</td><td>%s</td></tr>\n", htmlCode(match.Name), match.Kind, htmlText(match.Description))
+		}
+		b.WriteString("</tbody>\n</table>\n")
+	}
+	b.WriteString("</div>\n")
+	return b.String(), nil
+}
+
+func htmlCode(s string) string {
+	return "<code>" + html.EscapeString(s) + "</code>"
+}
+
+// htmlText escapes s, keeping its line breaks
+func htmlText(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "\n", "<br>")
+}
@@ -0,0 +1,89 @@
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTypeMarkdown(t *testing.T) {
+	s := loadRichSchema(t)
+
+	md, err := s.TypeMarkdown("Repository")
+	if err != nil {
+		t.Fatalf("TypeMarkdown failed: %v", err)
+	}
+	for _, want := range []string{
+		"### `Repository` (OBJECT)\n\nA repository contains the content for a project.\n\nImplements: `Node`\n",
+		"| Field | Type | Description |\n| --- | --- | --- |\n",
+		"| `issueOrPullRequest` | `IssueOrPullRequest` | Returns a single issue-like object from the current repository by number.<br>Arguments: `number: Int!` |\n",
+		"| `isTemplateRepo` | `Boolean!` | **Deprecated:** Use `Repository.isTemplate` instead.",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown does not contain %q:\n%s", want, md)
+		}
+	}
+
+	md, err = s.TypeMarkdown("IssueState")
+	if err != nil {
+		t.Fatalf("TypeMarkdown failed: %v", err)
+	}
+	if !strings.Contains(md, "| Value | Description |\n| --- | --- |\n| `OPEN` | An issue that is still open |\n") {
+		t.Errorf("Unexpected enum Markdown:\n%s", md)
+	}
+
+	var notFound *NotFoundError
+	if _, err := s.TypeMarkdown("Repo"); !errors.As(err, &notFound) {
+		t.Errorf("Expected *NotFoundError, got %v", err)
+	}
+}
+
+func TestTypeHTML(t *testing.T) {
+	s := loadRichSchema(t)
+
+	h, err := s.TypeHTML("IssueOrPullRequest")
+	if err != nil {
+		t.Fatalf("TypeHTML failed: %v", err)
+	}
+	want := `<div class="github-schema-type">
+<h3><code>IssueOrPullRequest</code> <small>UNION</small></h3>
+<p>Used for return value of Repository.issueOrPullRequest.</p>
+<p>Possible types: <code>Issue</code>, <code>PullRequest</code></p>
+</div>
+`
+	if h != want {
+		t.Errorf("HTML:\n%s\nwant:\n%s", h, want)
+	}
+
+	h, err = s.TypeHTML("CreateIssueInput")
+	if err != nil {
+		t.Fatalf("TypeHTML failed: %v", err)
+	}
+	if !strings.Contains(h, "<tr><td><code>labelIds</code></td><td><code>[ID!]</code></td><td>An array of Node IDs of labels for this issue.</td></tr>") {
+		t.Errorf("Unexpected input object HTML:\n%s", h)
+	}
+	if got := htmlText("a <b> & \"c\"\nd"); got != "a &lt;b&gt; &amp; &#34;c&#34;<br>d" {
+		t.Errorf("htmlText = %q", got)
+	}
+}
+
+func TestSearchMarkdownAndHTML(t *testing.T) {
+	s := loadRichSchema(t)
+
+	md, err := s.SearchMarkdown("^IssueState$")
+	if err != nil {
+		t.Fatalf("SearchMarkdown failed: %v", err)
+	}
+	want := "1 types matching `^IssueState$`\n\n| Type | Kind | Description |\n| --- | --- | --- |\n| `IssueState` | ENUM | The possible states of an issue. |\n"
+	if md != want {
+		t.Errorf("Markdown:\n%s\nwant:\n%s", md, want)
+	}
+
+	h, err := s.SearchHTML("^Nothing$")
+	if err != nil {
+		t.Fatalf("SearchHTML failed: %v", err)
+	}
+	if want := "<div class=\"github-schema-search\">\n<p>0 types matching <code>^Nothing$</code></p>\n</div>\n"; h != want {
+		t.Errorf("HTML:\n%s\nwant:\n%s", h, want)
+	}
+}
@@ -19,12 +19,23 @@
 //
 //	go generate ./schema
 //	# or
-//	github-schema download --compress -o schema/schema.json.gz
+//	github-schema download --compress=zstd -o schema/schema.json.zst
 //
 // Custom schemas can be loaded from files:
 //
 //	s, err := schema.NewWithFile("custom-schema.json")
 //
 // The schema file must be in GraphQL introspection format with the standard
-// structure: {"data": {"__schema": {...}}}
+// structure, {"data": {"__schema": {...}}}, or the bare {"__schema": {...}}
+// that graphql-js and Apollo tooling write.
+//
+// # Memory
+//
+// Loading a schema decompresses or reads the raw JSON into a buffer taken from
+// a package-level sync.Pool, parses it, and returns the buffer to the pool.
+// The parsed Schema never references the raw bytes, so services that reload
+// schemas frequently (hot reload, multi-tenant) reuse the same few megabytes
+// of buffer space instead of allocating it on every load. Buffers larger than
+// 64 MiB are not pooled. The parsed representation itself is owned by the
+// Schema and is released when the Schema becomes unreachable.
 package schema
\ No newline at end of file
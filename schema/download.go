@@ -2,403 +2,512 @@ package schema
 
 import (
 	"bytes"
-	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
-	"os/exec"
+	"strings"
+	"time"
 
+	"github.com/apstndb/github-schema-go/schema/introspect"
 	"github.com/apstndb/go-yamlformat"
 )
 
 const (
 	// GitHubAPIURL is the GitHub GraphQL API endpoint
 	GitHubAPIURL = "https://api.github.com/graphql"
-	
+
 	// IntrospectionQuery is the GraphQL introspection query
-	IntrospectionQuery = `
-	{
-	  __schema {
-	    queryType { name }
-	    mutationType { name }
-	    subscriptionType { name }
-	    types {
-	      ...FullType
-	    }
-	    directives {
-	      name
-	      description
-	      locations
-	      args {
-	        ...InputValue
-	      }
-	    }
-	  }
-	}
-	
-	fragment FullType on __Type {
-	  kind
-	  name
-	  description
-	  fields(includeDeprecated: true) {
-	    name
-	    description
-	    args {
-	      ...InputValue
-	    }
-	    type {
-	      ...TypeRef
-	    }
-	    isDeprecated
-	    deprecationReason
-	  }
-	  inputFields {
-	    ...InputValue
-	  }
-	  interfaces {
-	    ...TypeRef
-	  }
-	  enumValues(includeDeprecated: true) {
-	    name
-	    description
-	    isDeprecated
-	    deprecationReason
-	  }
-	  possibleTypes {
-	    ...TypeRef
-	  }
-	}
-	
-	fragment InputValue on __InputValue {
-	  name
-	  description
-	  type { ...TypeRef }
-	  defaultValue
-	}
-	
-	fragment TypeRef on __Type {
-	  kind
-	  name
-	  ofType {
-	    kind
-	    name
-	    ofType {
-	      kind
-	      name
-	      ofType {
-	        kind
-	        name
-	        ofType {
-	          kind
-	          name
-	          ofType {
-	            kind
-	            name
-	            ofType {
-	              kind
-	              name
-	              ofType {
-	                kind
-	                name
-	              }
-	            }
-	          }
-	        }
-	      }
-	    }
-	  }
-	}`
+	IntrospectionQuery = introspect.Query
+
+	// LegacyIntrospectionQuery is the introspection query without the members
+	// added in recent versions of the specification, see
+	// introspect.LegacyQuery. Downloads fall back to it when a server rejects
+	// IntrospectionQuery.
+	LegacyIntrospectionQuery = introspect.LegacyQuery
+
+	// PublishedSDLURL is the GraphQL SDL of the github.com API that GitHub
+	// publishes with its documentation. It is downloaded without a token,
+	// see SourceSDL.
+	PublishedSDLURL = "https://docs.github.com/public/fpt/schema.docs.graphql"
 )
 
-// DownloadSchema downloads the schema using GitHub GraphQL API introspection.
-// This is an alias for DownloadIntrospectionSchema for backward compatibility.
-func DownloadSchema(outputPath string) error {
-	return DownloadIntrospectionSchema(outputPath)
+// DownloadEndpoint is the GraphQL endpoint the download functions query. Set
+// it to https://HOST/api/graphql to download the schema of a GitHub
+// Enterprise Server instance. The WithEndpoint option overrides it for a
+// single download.
+var DownloadEndpoint = GitHubAPIURL
+
+// TokenSource returns the GitHub token for a download, for example from a
+// secret manager. An empty token falls back to ResolveCredentialForHost.
+type TokenSource func(ctx context.Context) (string, error)
+
+// DownloadOption customizes a download
+type DownloadOption func(*downloadOptions)
+
+type downloadOptions struct {
+	output      io.Writer
+	outputPath  string
+	compression Compression
+	endpoint    string
+	token       string
+	tokenSource TokenSource
+	client      *http.Client
+	handler     EventHandler
+	retry       RetryPolicy
+	ifChanged   bool
+	etag        string // Of the existing output, for a conditional request
+	source      DownloadSource
+	cacheDir    string // For NewWithURL
 }
 
-// DownloadAndCompressSchema downloads the schema with gzip compression.
-// When possible, it uses GitHub API's native gzip compression to reduce bandwidth usage.
-// The compressed data is saved directly without re-compression.
-func DownloadAndCompressSchema(outputPath string) error {
-	// Get GitHub token from gh auth
-	cmd := exec.Command("gh", "auth", "token")
-	tokenBytes, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("failed to get GitHub token (run 'gh auth login'): %w", err)
-	}
-	token := string(bytes.TrimSpace(tokenBytes))
-	
-	// Prepare GraphQL request
-	requestBody := map[string]string{
-		"query": IntrospectionQuery,
-	}
-	
-	jsonBody, err := yamlformat.MarshalJSON(requestBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
-	}
-	
-	// Create HTTP request
-	req, err := http.NewRequest("POST", GitHubAPIURL, bytes.NewReader(jsonBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-	
-	req.Header.Set("Authorization", "bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept-Encoding", "gzip")
-	
-	// Use custom transport to prevent automatic decompression
-	client := &http.Client{
-		Transport: &http.Transport{
-			DisableCompression: true,
-		},
-	}
-	
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("GitHub API returned HTTP %d", resp.StatusCode)
-	}
-	
-	// Check if response is compressed
-	if resp.Header.Get("Content-Encoding") != "gzip" {
-		// Fallback: read uncompressed and compress it
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read response: %w", err)
-		}
-		
-		// Validate it's valid JSON
-		var result map[string]interface{}
-		if err := yamlformat.Unmarshal(body, &result); err != nil {
-			return fmt.Errorf("failed to parse response as JSON: %w", err)
-		}
-		
-		// Check for errors in response
-		if errors, ok := result["errors"]; ok {
-			return fmt.Errorf("GraphQL errors: %v", errors)
+// DownloadSource is where Download gets the schema from
+type DownloadSource string
+
+const (
+	// SourceIntrospection runs the introspection query against a GraphQL
+	// endpoint, which requires a token for GitHub
+	SourceIntrospection DownloadSource = "introspection"
+	// SourceSDL fetches a published GraphQL SDL file, PublishedSDLURL by
+	// default, without authentication and converts it into an introspection
+	// result. The SDL carries the same types, but GitHub may publish it a
+	// little after the API changes.
+	SourceSDL DownloadSource = "sdl"
+)
+
+// ErrUnchanged is returned by Download with WithIfChanged when the output
+// file already holds the downloaded schema; the file is left as it is
+var ErrUnchanged = errors.New("schema unchanged")
+
+// RetryPolicy controls retries of failed downloads, see
+// introspect.RetryPolicy
+type RetryPolicy = introspect.RetryPolicy
+
+// WithOutput writes the downloaded schema to w
+func WithOutput(w io.Writer) DownloadOption {
+	return func(o *downloadOptions) { o.output, o.outputPath = w, "" }
+}
+
+// WithOutputPath writes the downloaded schema to a file. The file is only
+// created once the download succeeded.
+func WithOutputPath(path string) DownloadOption {
+	return func(o *downloadOptions) { o.outputPath, o.output = path, nil }
+}
+
+// WithCompression gzip-compresses the written schema when compress is true
+func WithCompression(compress bool) DownloadOption {
+	return func(o *downloadOptions) {
+		o.compression = NoCompression
+		if compress {
+			o.compression = Gzip
 		}
-		
-		// Create output file and compress
-		out, err := os.Create(outputPath)
-		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
+	}
+}
+
+// WithCompressionFormat compresses the written schema with c, such as Zstd
+// for a file that loads faster than a gzipped one
+func WithCompressionFormat(c Compression) DownloadOption {
+	return func(o *downloadOptions) { o.compression = c }
+}
+
+// WithToken authenticates the download with token instead of resolving one
+// with ResolveCredentialForHost, for callers that manage their own credentials
+func WithToken(token string) DownloadOption {
+	return func(o *downloadOptions) { o.token, o.tokenSource = token, nil }
+}
+
+// WithTokenSource authenticates the download with the token returned by
+// source, which is called once per download
+func WithTokenSource(source TokenSource) DownloadOption {
+	return func(o *downloadOptions) { o.tokenSource = source }
+}
+
+// WithEndpoint downloads from endpoint instead of DownloadEndpoint, or
+// instead of PublishedSDLURL with SourceSDL
+func WithEndpoint(endpoint string) DownloadOption {
+	return func(o *downloadOptions) { o.endpoint = endpoint }
+}
+
+// WithHTTPClient sends the introspection request with client, for proxies,
+// custom TLS settings, or tests. The response is decompressed by Download,
+// whatever the client's transport settings.
+func WithHTTPClient(client *http.Client) DownloadOption {
+	return func(o *downloadOptions) { o.client = client }
+}
+
+// WithTransport sends the introspection request through rt, such as an
+// instrumenting transport or one replaying recorded responses in tests. It
+// replaces any client given with WithHTTPClient.
+func WithTransport(rt http.RoundTripper) DownloadOption {
+	return func(o *downloadOptions) { o.client = &http.Client{Transport: rt} }
+}
+
+// WithRetry retries failed requests as policy describes instead of
+// following introspect.DefaultRetryPolicy. A policy with MaxAttempts of 1
+// disables retries.
+func WithRetry(policy RetryPolicy) DownloadOption {
+	return func(o *downloadOptions) { o.retry = policy }
+}
+
+// WithIfChanged leaves the file given with WithOutputPath untouched when it
+// already holds the schema being downloaded, so that repeated downloads do
+// not rewrite it with a new download time; Download then returns
+// ErrUnchanged. The SHA-256 recorded in the file's Metadata is compared with
+// the response's, and the recorded ETag, if the server sent one, makes the
+// request conditional. Files without Metadata or compressed differently are
+// always replaced.
+func WithIfChanged(enabled bool) DownloadOption {
+	return func(o *downloadOptions) { o.ifChanged = enabled }
+}
+
+// WithSource downloads the schema from source instead of running the
+// introspection query, such as SourceSDL for callers without a token
+func WithSource(source DownloadSource) DownloadOption {
+	return func(o *downloadOptions) { o.source = source }
+}
+
+// WithEventHandler reports the progress of the download to handler, see Event.
+// Progress events with the Message "receiving" count the bytes of the
+// response received so far, with Total the length the server announced;
+// the others count the bytes written.
+func WithEventHandler(handler EventHandler) DownloadOption {
+	return func(o *downloadOptions) { o.handler = handler }
+}
+
+// Download downloads the schema using the standard introspection query and
+// writes it in the GraphQL introspection format, which includes the data
+// wrapper: {"data": {"__schema": {...}}}, with the download's Metadata
+// recorded under "extensions". The response is transferred gzip-compressed.
+//
+// The destination is given with WithOutput or WithOutputPath. Without
+// WithToken or WithTokenSource, the token is looked up by
+// ResolveCredentialForHost for the host of the endpoint, so a github.com
+// token is not sent to a GitHub Enterprise Server or other endpoint.
+// Network errors, server errors, and rate limits are retried following
+// introspect.DefaultRetryPolicy unless WithRetry is given; each retry is
+// logged and reported as a progress event.
+func Download(ctx context.Context, opts ...DownloadOption) error {
+	o := downloadOptions{token: ExplicitToken, retry: introspect.DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	fetch := fetchIntrospection
+	switch o.source {
+	case "", SourceIntrospection:
+		if o.endpoint == "" {
+			o.endpoint = DownloadEndpoint
 		}
-		defer out.Close()
-		
-		gz := gzip.NewWriter(out)
-		defer gz.Close()
-		
-		if _, err := gz.Write(body); err != nil {
-			return fmt.Errorf("failed to write compressed data: %w", err)
+	case SourceSDL:
+		if o.endpoint == "" {
+			o.endpoint = PublishedSDLURL
 		}
-	} else {
-		// Response is already compressed, save directly
-		out, err := os.Create(outputPath)
-		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
+		fetch = fetchSDL
+	default:
+		return fmt.Errorf("unknown download source %q; use %s or %s", o.source, SourceIntrospection, SourceSDL)
+	}
+	if o.output == nil && o.outputPath == "" {
+		return fmt.Errorf("download requires WithOutput or WithOutputPath")
+	}
+	var previous *Metadata
+	if o.ifChanged {
+		if o.outputPath == "" {
+			return fmt.Errorf("WithIfChanged requires WithOutputPath")
 		}
-		defer out.Close()
-		
-		if _, err := io.Copy(out, resp.Body); err != nil {
-			return fmt.Errorf("failed to write compressed data: %w", err)
+		if previous = fileMetadata(o.outputPath, o.compression); previous != nil {
+			o.etag = previous.ETag
 		}
 	}
-	
-	return nil
-}
 
-// DownloadToWriter downloads introspection schema and writes to writer
-func DownloadToWriter(w io.Writer) error {
-	return DownloadIntrospectionToWriter(w)
-}
+	emitter := newEventEmitter("download", o.handler)
+	emitter.emit(Event{Phase: EventStart, Message: o.outputPath})
 
-// DownloadAndCompressToWriter downloads introspection schema with native compression and writes to writer
-func DownloadAndCompressToWriter(w io.Writer) error {
-	// Get GitHub token from gh auth
-	cmd := exec.Command("gh", "auth", "token")
-	tokenBytes, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("failed to get GitHub token (run 'gh auth login'): %w", err)
+	onRetry := o.retry.OnRetry
+	o.retry.OnRetry = func(attempt int, delay time.Duration, err error) {
+		slog.Warn("Retrying schema download", "attempt", attempt, "delay", delay, "error", err)
+		emitter.emit(Event{Phase: EventProgress, Message: fmt.Sprintf("retrying in %s after attempt %d failed: %v", delay.Round(time.Millisecond), attempt, err)})
+		if onRetry != nil {
+			onRetry(attempt, delay, err)
+		}
 	}
-	token := string(bytes.TrimSpace(tokenBytes))
-	
-	// Prepare GraphQL request
-	requestBody := map[string]string{
-		"query": IntrospectionQuery,
+
+	body, meta, err := fetch(ctx, o, emitter)
+	if errors.Is(err, introspect.ErrNotModified) ||
+		err == nil && previous != nil && previous.SHA256 == meta.SHA256 && previous.Endpoint == meta.Endpoint {
+		emitter.emit(Event{Phase: EventDone, Message: "unchanged"})
+		return ErrUnchanged
 	}
-	
-	jsonBody, err := yamlformat.MarshalJSON(requestBody)
 	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
+		return emitter.finish(err, 0)
 	}
-	
-	// Create HTTP request
-	req, err := http.NewRequest("POST", GitHubAPIURL, bytes.NewReader(jsonBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+
+	var file *os.File
+	w := o.output
+	if o.outputPath != "" {
+		if file, err = os.Create(o.outputPath); err != nil {
+			return emitter.finish(fmt.Errorf("failed to create output file: %w", err), 0)
+		}
+		w = file
 	}
-	
-	req.Header.Set("Authorization", "bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept-Encoding", "gzip")
-	
-	// Use custom transport to prevent automatic decompression
-	client := &http.Client{
-		Transport: &http.Transport{
-			DisableCompression: true,
-		},
+	pw := &progressWriter{w: w, emitter: emitter}
+	err = writeSchema(pw, body, o.compression)
+	if file != nil {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write file: %w", closeErr)
+		}
 	}
-	
-	resp, err := client.Do(req)
+	return emitter.finish(err, pw.written)
+}
+
+// writeSchema writes body to w, compressed with c
+func writeSchema(w io.Writer, body []byte, c Compression) error {
+	if c == NoCompression {
+		if _, err := w.Write(body); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+		return nil
+	}
+	cw, err := compressWriter(w, c, int64(len(body)))
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
+		return fmt.Errorf("failed to compress schema: %w", err)
 	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("GitHub API returned HTTP %d", resp.StatusCode)
+	if _, err := cw.Write(body); err != nil {
+		return fmt.Errorf("failed to write compressed data: %w", err)
 	}
-	
-	// Check if response is compressed
-	if resp.Header.Get("Content-Encoding") != "gzip" {
-		// Fallback: compress on the fly
-		gz := gzip.NewWriter(w)
-		defer gz.Close()
-		
-		if _, err := io.Copy(gz, resp.Body); err != nil {
-			return fmt.Errorf("failed to write compressed response: %w", err)
-		}
-	} else {
-		// Response is already compressed, copy directly
-		if _, err := io.Copy(w, resp.Body); err != nil {
-			return fmt.Errorf("failed to write compressed response: %w", err)
-		}
+	if err := cw.Close(); err != nil {
+		return fmt.Errorf("failed to write compressed data: %w", err)
 	}
-	
 	return nil
 }
 
-// DownloadIntrospectionSchema downloads the GitHub GraphQL schema using the standard
-// introspection query. The schema is saved in the GraphQL introspection format,
-// which includes the data wrapper: {"data": {"__schema": {...}}}.
-// Requires GitHub authentication via 'gh auth login'.
-func DownloadIntrospectionSchema(outputPath string) error {
-	// Get GitHub token from gh auth
-	cmd := exec.Command("gh", "auth", "token")
-	tokenBytes, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("failed to get GitHub token (run 'gh auth login'): %w", err)
+// The functions below are shorthands for Download. Each has a variant with a
+// Context suffix that takes a context; the request is canceled when the
+// context is done, bounding the download by its deadline. The variants
+// without context run until the download completes.
+
+// DownloadSchema downloads the schema to outputPath.
+// This is an alias for DownloadIntrospectionSchema for backward compatibility.
+func DownloadSchema(outputPath string, opts ...DownloadOption) error {
+	return DownloadSchemaContext(context.Background(), outputPath, opts...)
+}
+
+// DownloadSchemaContext is DownloadSchema with a context
+func DownloadSchemaContext(ctx context.Context, outputPath string, opts ...DownloadOption) error {
+	return DownloadIntrospectionSchemaContext(ctx, outputPath, opts...)
+}
+
+// DownloadAndCompressSchema downloads the schema to outputPath with gzip
+// compression. It is Download with WithOutputPath and WithCompression.
+func DownloadAndCompressSchema(outputPath string, opts ...DownloadOption) error {
+	return DownloadAndCompressSchemaContext(context.Background(), outputPath, opts...)
+}
+
+// DownloadAndCompressSchemaContext is DownloadAndCompressSchema with a context
+func DownloadAndCompressSchemaContext(ctx context.Context, outputPath string, opts ...DownloadOption) error {
+	return downloadWith(ctx, opts, WithOutputPath(outputPath), WithCompression(true))
+}
+
+// DownloadToWriter downloads the schema and writes it to w.
+// This is an alias for DownloadIntrospectionToWriter for backward compatibility.
+func DownloadToWriter(w io.Writer, opts ...DownloadOption) error {
+	return DownloadToWriterContext(context.Background(), w, opts...)
+}
+
+// DownloadToWriterContext is DownloadToWriter with a context
+func DownloadToWriterContext(ctx context.Context, w io.Writer, opts ...DownloadOption) error {
+	return DownloadIntrospectionToWriterContext(ctx, w, opts...)
+}
+
+// DownloadAndCompressToWriter downloads the schema and writes it to w with
+// gzip compression. It is Download with WithOutput and WithCompression.
+func DownloadAndCompressToWriter(w io.Writer, opts ...DownloadOption) error {
+	return DownloadAndCompressToWriterContext(context.Background(), w, opts...)
+}
+
+// DownloadAndCompressToWriterContext is DownloadAndCompressToWriter with a context
+func DownloadAndCompressToWriterContext(ctx context.Context, w io.Writer, opts ...DownloadOption) error {
+	return downloadWith(ctx, opts, WithOutput(w), WithCompression(true))
+}
+
+// DownloadIntrospectionSchema downloads the schema to outputPath. It is
+// Download with WithOutputPath.
+func DownloadIntrospectionSchema(outputPath string, opts ...DownloadOption) error {
+	return DownloadIntrospectionSchemaContext(context.Background(), outputPath, opts...)
+}
+
+// DownloadIntrospectionSchemaContext is DownloadIntrospectionSchema with a context
+func DownloadIntrospectionSchemaContext(ctx context.Context, outputPath string, opts ...DownloadOption) error {
+	return downloadWith(ctx, opts, WithOutputPath(outputPath))
+}
+
+// DownloadIntrospectionToWriter downloads the schema and writes it to w. It
+// is Download with WithOutput.
+func DownloadIntrospectionToWriter(w io.Writer, opts ...DownloadOption) error {
+	return DownloadIntrospectionToWriterContext(context.Background(), w, opts...)
+}
+
+// DownloadIntrospectionToWriterContext is DownloadIntrospectionToWriter with a context
+func DownloadIntrospectionToWriterContext(ctx context.Context, w io.Writer, opts ...DownloadOption) error {
+	return downloadWith(ctx, opts, WithOutput(w))
+}
+
+// DownloadSchemaWithEvents downloads the schema to outputPath like DownloadSchema,
+// or DownloadAndCompressSchema when compress is true, reporting progress to handler.
+func DownloadSchemaWithEvents(outputPath string, compress bool, handler EventHandler, opts ...DownloadOption) error {
+	return DownloadSchemaWithEventsContext(context.Background(), outputPath, compress, handler, opts...)
+}
+
+// DownloadSchemaWithEventsContext is DownloadSchemaWithEvents with a context
+func DownloadSchemaWithEventsContext(ctx context.Context, outputPath string, compress bool, handler EventHandler, opts ...DownloadOption) error {
+	return downloadWith(ctx, opts, WithOutputPath(outputPath), WithCompression(compress), WithEventHandler(handler))
+}
+
+// DownloadToWriterWithEvents downloads the schema to w like DownloadToWriter,
+// or DownloadAndCompressToWriter when compress is true, reporting the number
+// of bytes written to handler as the download proceeds.
+func DownloadToWriterWithEvents(w io.Writer, compress bool, handler EventHandler, opts ...DownloadOption) error {
+	return DownloadToWriterWithEventsContext(context.Background(), w, compress, handler, opts...)
+}
+
+// DownloadToWriterWithEventsContext is DownloadToWriterWithEvents with a context
+func DownloadToWriterWithEventsContext(ctx context.Context, w io.Writer, compress bool, handler EventHandler, opts ...DownloadOption) error {
+	return downloadWith(ctx, opts, WithOutput(w), WithCompression(compress), WithEventHandler(handler))
+}
+
+// downloadWith runs Download with extra applied after opts, leaving the
+// caller's slice unmodified
+func downloadWith(ctx context.Context, opts []DownloadOption, extra ...DownloadOption) error {
+	return Download(ctx, append(opts[:len(opts):len(opts)], extra...)...)
+}
+
+// fetchIntrospection runs the introspection query against the endpoint of o
+// and returns the response with its Metadata recorded, emitting progress
+// events with the bytes received
+func fetchIntrospection(ctx context.Context, o downloadOptions, emitter *eventEmitter) ([]byte, Metadata, error) {
+	token := o.token
+	if o.tokenSource != nil {
+		var err error
+		if token, err = o.tokenSource(ctx); err != nil {
+			return nil, Metadata{}, fmt.Errorf("failed to get token: %w", err)
+		}
 	}
-	token := string(bytes.TrimSpace(tokenBytes))
-	
-	// Prepare GraphQL request
-	requestBody := map[string]string{
-		"query": IntrospectionQuery,
+	// Find a token for the host of the endpoint, see ResolveCredentialForHost
+	cred := ResolveCredentialForHost(token, EndpointHost(o.endpoint))
+	slog.Debug("Using GitHub credential", "host", cred.host, "source", cred.Source)
+
+	introspectOpts := []introspect.Option{
+		introspect.WithToken(cred.Token),
+		introspect.WithHTTPClient(o.client),
+		introspect.WithRetry(o.retry),
+		introspect.WithIfNoneMatch(o.etag),
+		introspect.WithProgress(receiveProgress(emitter)),
+	}
+	doc, err := introspect.Introspect(ctx, o.endpoint, introspectOpts...)
+	// Servers implementing an older specification reject the newer members
+	// of IntrospectionQuery; ask them the query without those
+	legacy := err == nil && rejected(doc.Body)
+	if legacy {
+		slog.Warn("Server rejected the introspection query, retrying with the legacy query", "endpoint", o.endpoint)
+		doc, err = introspect.Introspect(ctx, o.endpoint, append(introspectOpts, introspect.WithQuery(introspect.LegacyQuery))...)
+	}
+	var statusErr *introspect.StatusError
+	if errors.As(err, &statusErr) && !statusErr.RateLimited {
+		// Keep the " (after N attempts)" suffix of retried requests
+		return nil, Metadata{}, fmt.Errorf("%w%s", cred.statusError(statusErr.StatusCode), strings.TrimPrefix(err.Error(), statusErr.Error()))
 	}
-	
-	jsonBody, err := yamlformat.MarshalJSON(requestBody)
 	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
+		return nil, Metadata{}, err
 	}
-	
-	// Create HTTP request
-	req, err := http.NewRequest("POST", GitHubAPIURL, bytes.NewReader(jsonBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+
+	meta := Metadata{
+		DownloadedAt: doc.ReceivedAt.Truncate(time.Second),
+		SHA256:       Fingerprint(doc.Body),
+		Endpoint:     doc.Endpoint,
+		GHESVersion:  doc.GHESVersion,
+		ETag:         doc.ETag,
+		LegacyQuery:  legacy,
+	}
+	body, err := addMetadata(doc.Body, meta)
+	return body, meta, err
+}
+
+// fetchSDL downloads the SDL file at the endpoint of o and returns it
+// converted into an introspection result with its Metadata recorded. The
+// token is only sent when one is given explicitly; published files need
+// none.
+func fetchSDL(ctx context.Context, o downloadOptions, emitter *eventEmitter) ([]byte, Metadata, error) {
+	token := o.token
+	if o.tokenSource != nil {
+		var err error
+		if token, err = o.tokenSource(ctx); err != nil {
+			return nil, Metadata{}, fmt.Errorf("failed to get token: %w", err)
+		}
 	}
-	
-	req.Header.Set("Authorization", "bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	
-	// Execute request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	doc, err := introspect.Fetch(ctx, o.endpoint,
+		introspect.WithToken(token),
+		introspect.WithHTTPClient(o.client),
+		introspect.WithRetry(o.retry),
+		introspect.WithIfNoneMatch(o.etag),
+		introspect.WithProgress(receiveProgress(emitter)))
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
+		return nil, Metadata{}, err
 	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("GitHub API returned HTTP %d", resp.StatusCode)
+	if !isSDL(doc.Body) {
+		return nil, Metadata{}, invalidSchema("failed to convert SDL: %s did not return a GraphQL SDL document", o.endpoint)
 	}
-	
-	// Read response
-	body, err := io.ReadAll(resp.Body)
+	result, err := parseSDL(doc.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-	
-	// Validate it's valid JSON
-	var result map[string]interface{}
-	if err := yamlformat.Unmarshal(body, &result); err != nil {
-		return fmt.Errorf("failed to parse response as JSON: %w", err)
+		return nil, Metadata{}, err
 	}
-	
-	// Check for errors in response
-	if errors, ok := result["errors"]; ok {
-		return fmt.Errorf("GraphQL errors: %v", errors)
-	}
-	
-	// Write to file
-	if err := os.WriteFile(outputPath, body, 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	converted, err := yamlformat.MarshalJSON(result)
+	if err != nil {
+		return nil, Metadata{}, fmt.Errorf("failed to encode schema: %w", err)
 	}
-	
-	return nil
+
+	meta := Metadata{
+		DownloadedAt: doc.ReceivedAt.Truncate(time.Second),
+		SHA256:       Fingerprint(doc.Body),
+		Endpoint:     doc.Endpoint,
+		ETag:         doc.ETag,
+		Source:       SourceSDL,
+	}
+	body, err := addMetadata(converted, meta)
+	return body, meta, err
 }
 
-// DownloadIntrospectionToWriter downloads introspection schema and writes to writer
-func DownloadIntrospectionToWriter(w io.Writer) error {
-	// Get GitHub token from gh auth
-	cmd := exec.Command("gh", "auth", "token")
-	tokenBytes, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("failed to get GitHub token (run 'gh auth login'): %w", err)
+// rejected reports whether body is a response with GraphQL errors and no
+// schema, as servers send for queries they fail to validate
+func rejected(body []byte) bool {
+	if !bytes.Contains(body, []byte(`"errors"`)) {
+		return false
 	}
-	token := string(bytes.TrimSpace(tokenBytes))
-	
-	// Prepare GraphQL request
-	requestBody := map[string]string{
-		"query": IntrospectionQuery,
-	}
-	
-	jsonBody, err := yamlformat.MarshalJSON(requestBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
-	}
-	
-	// Create HTTP request
-	req, err := http.NewRequest("POST", GitHubAPIURL, bytes.NewReader(jsonBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	var result map[string]interface{}
+	if err := yamlformat.Unmarshal(body, &result); err != nil {
+		return false
 	}
-	
-	req.Header.Set("Authorization", "bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	
-	// Execute request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	data, _ := result["data"].(map[string]interface{})
+	return result["errors"] != nil && data["__schema"] == nil
+}
+
+// fileMetadata returns the Metadata of the schema file at path, or nil when
+// the file does not exist, has no Metadata, or is not compressed with c
+func fileMetadata(path string, c Compression) *Metadata {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
+		return nil
 	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("GitHub API returned HTTP %d", resp.StatusCode)
+	if detectCompression(data) != c {
+		return nil
 	}
-	
-	// Copy response to writer
-	if _, err := io.Copy(w, resp.Body); err != nil {
-		return fmt.Errorf("failed to write response: %w", err)
+	if c != NoCompression {
+		buf, err := decompress(data)
+		if err != nil {
+			return nil
+		}
+		defer putBuffer(buf)
+		data = buf.Bytes()
 	}
-	
-	return nil
-}
\ No newline at end of file
+	return metadataOf(data)
+}
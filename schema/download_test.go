@@ -1,10 +1,18 @@
 package schema
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestDownloadIntrospectionSchema tests the introspection download functionality
@@ -55,4 +63,408 @@ func TestDownloadIntrospectionSchema(t *testing.T) {
 	if result == nil {
 		t.Error("Query returned nil result")
 	}
-}
\ No newline at end of file
+}
+func TestDownloadOptions(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Write(SampleData())
+	}))
+	defer server.Close()
+
+	isolateAuth(t)
+	t.Setenv("GH_ENTERPRISE_TOKEN", "env-token")
+
+	tests := []struct {
+		name string
+		opts []DownloadOption
+		want string
+	}{
+		{name: "environment", opts: nil, want: "bearer env-token"},
+		{name: "WithToken", opts: []DownloadOption{WithToken("option-token")}, want: "bearer option-token"},
+		{
+			name: "WithTokenSource",
+			opts: []DownloadOption{WithTokenSource(func(ctx context.Context) (string, error) { return "source-token", nil })},
+			want: "bearer source-token",
+		},
+		{
+			name: "empty token source falls back",
+			opts: []DownloadOption{WithTokenSource(func(ctx context.Context) (string, error) { return "", nil })},
+			want: "bearer env-token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := append([]DownloadOption{WithEndpoint(server.URL), WithOutput(&buf)}, tt.opts...)
+			if err := Download(context.Background(), opts...); err != nil {
+				t.Fatalf("Download failed: %v", err)
+			}
+			if gotAuth != tt.want {
+				t.Errorf("Authorization = %q, want %q", gotAuth, tt.want)
+			}
+			s, err := NewWithData(buf.Bytes())
+			if err != nil {
+				t.Fatalf("Failed to load download: %v", err)
+			}
+			if meta := s.Metadata(); meta == nil || meta.Endpoint != server.URL {
+				t.Errorf("Metadata = %+v, want endpoint %s", meta, server.URL)
+			}
+		})
+	}
+	if DownloadEndpoint != GitHubAPIURL {
+		t.Errorf("WithEndpoint changed DownloadEndpoint to %s", DownloadEndpoint)
+	}
+}
+
+func TestDownloadCustomEndpointWithoutGitHubToken(t *testing.T) {
+	var gotAuth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = append(gotAuth, r.Header.Get("Authorization"))
+		w.Write(SampleData())
+	}))
+	defer server.Close()
+
+	dir := isolateAuth(t)
+	t.Setenv("GH_TOKEN", "github-token")
+	t.Setenv("GITHUB_TOKEN", "github-token")
+	hosts := "github.com:\n    oauth_token: github-config-token\n"
+	if err := os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Download(context.Background(), WithEndpoint(server.URL), WithOutput(io.Discard)); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if len(gotAuth) != 1 || gotAuth[0] != "" {
+		t.Errorf("Expected one request without an Authorization header, got %q", gotAuth)
+	}
+}
+
+// roundTripperFunc replays responses without a server
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestDownloadTransport(t *testing.T) {
+	var requests []*http.Request
+	recorder := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requests = append(requests, r)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"X-Github-Enterprise-Version": {"3.14.0"}},
+			Body:       io.NopCloser(bytes.NewReader(SampleData())),
+			Request:    r,
+		}, nil
+	})
+
+	var buf bytes.Buffer
+	endpoint := "https://ghe.example.com/api/graphql"
+	err := Download(context.Background(), WithOutput(&buf), WithEndpoint(endpoint), WithToken("token"), WithTransport(recorder))
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if len(requests) != 1 || requests[0].URL.String() != endpoint || requests[0].Header.Get("Authorization") != "bearer token" {
+		t.Fatalf("Unexpected requests %+v", requests)
+	}
+	s, err := NewWithData(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to load download: %v", err)
+	}
+	if meta := s.Metadata(); meta == nil || meta.Endpoint != endpoint || meta.GHESVersion != "3.14.0" {
+		t.Errorf("Metadata = %+v", meta)
+	}
+}
+
+func TestDownloadLegacyQuery(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		queries = append(queries, string(body))
+		// A server implementing an older specification
+		if bytes.Contains(body, []byte("specifiedByURL")) {
+			io.WriteString(w, `{"errors": [{"message": "Field 'specifiedByURL' doesn't exist on type '__Type'"}]}`)
+			return
+		}
+		w.Write(SampleData())
+	}))
+	defer server.Close()
+	isolateAuth(t)
+
+	var buf bytes.Buffer
+	if err := Download(context.Background(), WithEndpoint(server.URL), WithOutput(&buf)); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if len(queries) != 2 || strings.Contains(queries[1], "specifiedByURL") {
+		t.Fatalf("Expected a retry with the legacy query, got %d requests", len(queries))
+	}
+	s, err := NewWithData(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to load download: %v", err)
+	}
+	if meta := s.Metadata(); meta == nil || !meta.LegacyQuery || meta.Options() != LegacyQueryOptions {
+		t.Errorf("Metadata = %+v, want LegacyQuery", meta)
+	}
+}
+
+func TestDownloadSDL(t *testing.T) {
+	sdl := `"The query root"
+type Query {
+  "Deprecated"
+  viewer: User @deprecated(reason: "Use me")
+}
+
+type User {
+  login: String!
+}
+`
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		io.WriteString(w, sdl)
+	}))
+	defer server.Close()
+	isolateAuth(t)
+
+	var buf bytes.Buffer
+	if err := Download(context.Background(), WithSource(SourceSDL), WithEndpoint(server.URL), WithOutput(&buf)); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if len(auth) != 1 || auth[0] != "" {
+		t.Errorf("Expected one anonymous request, got %q", auth)
+	}
+	if isSDL(buf.Bytes()) {
+		t.Fatal("Expected the SDL to be converted into an introspection result")
+	}
+	s, err := NewWithDataStrict(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to load download: %v", err)
+	}
+	if _, err := s.Type("User"); err != nil {
+		t.Errorf("Converted schema lacks User: %v", err)
+	}
+	if meta := s.Metadata(); meta == nil || meta.Source != SourceSDL || meta.SHA256 != Fingerprint([]byte(sdl)) {
+		t.Errorf("Metadata = %+v, want the SDL source and its SHA-256", meta)
+	}
+
+	sdl = `{"message": "Not Found"}`
+	if err := Download(context.Background(), WithSource(SourceSDL), WithEndpoint(server.URL), WithOutput(io.Discard)); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("Expected an invalid schema error for a non-SDL response, got %v", err)
+	}
+	if err := Download(context.Background(), WithSource("ftp"), WithOutput(io.Discard)); err == nil {
+		t.Error("Expected an error for an unknown source")
+	}
+}
+
+func TestDownloadRetry(t *testing.T) {
+	calls, failures := 0, 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls++; calls <= failures {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write(SampleData())
+	}))
+	defer server.Close()
+
+	var events []Event
+	var buf bytes.Buffer
+	err := Download(context.Background(),
+		WithOutput(&buf),
+		WithEndpoint(server.URL),
+		WithToken("token"),
+		WithRetry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+		WithEventHandler(func(ev Event) { events = append(events, ev) }),
+	)
+	if err != nil || calls != 2 {
+		t.Fatalf("Download = %v after %d requests, want success after 2", err, calls)
+	}
+	if len(events) != 3 || events[1].Phase != EventProgress || !strings.Contains(events[1].Message, "HTTP 502") {
+		t.Errorf("Expected a progress event for the retry, got %+v", events)
+	}
+
+	calls, failures = 0, 2
+	buf.Reset()
+	err = Download(context.Background(), WithOutput(&buf), WithEndpoint(server.URL), WithToken("token"),
+		WithRetry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+	if err == nil || err.Error() != "GitHub API returned HTTP 502 (after 2 attempts)" || buf.Len() != 0 {
+		t.Errorf("Expected the status error after 2 attempts, got %v", err)
+	}
+}
+
+func TestDownloadIfChanged(t *testing.T) {
+	response := SampleData()
+	var requests, conditional int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") != "" {
+			conditional++
+		}
+		w.Write(response)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "schema.json.gz")
+	download := func(compress bool) error {
+		return Download(context.Background(), WithOutputPath(path), WithCompression(compress),
+			WithEndpoint(server.URL), WithToken("token"), WithIfChanged(true))
+	}
+	if err := download(true); err != nil {
+		t.Fatalf("First download failed: %v", err)
+	}
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := download(true); !errors.Is(err, ErrUnchanged) {
+		t.Fatalf("Expected ErrUnchanged, got %v", err)
+	}
+	if again, _ := os.ReadFile(path); !bytes.Equal(again, first) {
+		t.Error("Expected the unchanged file to be left as it was")
+	}
+
+	// Changing the compression replaces the file even if the schema is the same
+	if err := download(false); err != nil {
+		t.Fatalf("Uncompressed download failed: %v", err)
+	}
+	response = append(bytes.TrimRight(SampleData(), "\n"), ' ')
+	if err := download(false); err != nil {
+		t.Fatalf("Download of a changed schema failed: %v", err)
+	}
+	if requests != 4 || conditional != 0 {
+		t.Errorf("Made %d requests, %d conditional; want 4 and none without an ETag", requests, conditional)
+	}
+
+	if err := Download(context.Background(), WithOutput(io.Discard), WithIfChanged(true)); err == nil {
+		t.Error("Expected WithIfChanged to require WithOutputPath")
+	}
+}
+
+func TestDownloadIfChangedETag(t *testing.T) {
+	var gotIfNoneMatch string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotIfNoneMatch = r.Header.Get("If-None-Match")
+		if gotIfNoneMatch == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(SampleData())
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "schema.json")
+	opts := []DownloadOption{WithOutputPath(path), WithEndpoint(server.URL), WithToken("token"), WithIfChanged(true)}
+	if err := Download(context.Background(), opts...); err != nil {
+		t.Fatalf("First download failed: %v", err)
+	}
+	if meta := fileMetadata(path, NoCompression); meta == nil || meta.ETag != `"v1"` {
+		t.Fatalf("Expected the ETag in the metadata, got %+v", meta)
+	}
+	if err := Download(context.Background(), opts...); !errors.Is(err, ErrUnchanged) || gotIfNoneMatch != `"v1"` {
+		t.Errorf("Expected ErrUnchanged from a conditional request, got %v with If-None-Match %q", err, gotIfNoneMatch)
+	}
+}
+
+func TestDownloadToPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(SampleData())
+	}))
+	defer server.Close()
+
+	var events []Event
+	path := filepath.Join(t.TempDir(), "schema.json.gz")
+	err := Download(context.Background(),
+		WithOutputPath(path),
+		WithCompression(true),
+		WithEndpoint(server.URL),
+		WithToken("token"),
+		WithHTTPClient(server.Client()),
+		WithEventHandler(func(ev Event) { events = append(events, ev) }),
+	)
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	compressed, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := decompressGzip(compressed)
+	if err != nil {
+		t.Fatalf("Expected gzip output: %v", err)
+	}
+	defer putBuffer(buf)
+	s, err := NewWithData(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to load download: %v", err)
+	}
+	if s.Metadata() == nil {
+		t.Error("Expected metadata")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Phase != EventStart || events[0].Message != path ||
+		events[1].Phase != EventDone || events[1].Current != info.Size() {
+		t.Errorf("Unexpected events %+v for a file of %d bytes", events, info.Size())
+	}
+}
+
+func TestDownloadErrors(t *testing.T) {
+	if err := Download(context.Background()); err == nil || !strings.Contains(err.Error(), "WithOutput") {
+		t.Errorf("Expected an error without an output, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := Download(ctx, WithOutputPath(path), WithToken("token")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected no file after a failed download, got %v", err)
+	}
+
+	failing := func(ctx context.Context) (string, error) { return "", fmt.Errorf("vault sealed") }
+	var buf bytes.Buffer
+	if err := Download(context.Background(), WithOutput(&buf), WithTokenSource(failing)); err == nil || !strings.Contains(err.Error(), "vault sealed") {
+		t.Errorf("Expected the token source error, got %v", err)
+	}
+}
+
+func TestDownloadContext(t *testing.T) {
+	// The server never answers until the test ends
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	opts := []DownloadOption{WithEndpoint(server.URL), WithHTTPClient(server.Client()), WithToken("token")}
+	path := filepath.Join(t.TempDir(), "schema.json.gz")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := DownloadAndCompressSchemaContext(ctx, path, opts...); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected no file after a timed out download, got %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	var buf bytes.Buffer
+	if err := DownloadToWriterContext(ctx, &buf, opts...); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output after a canceled download, got %d bytes", buf.Len())
+	}
+}
@@ -0,0 +1,12 @@
+// Code generated by internal/embedinfo; DO NOT EDIT.
+
+package schema
+
+import "time"
+
+var embeddedInfo = SnapshotInfo{
+	CapturedAt:  time.Date(2026, 10, 16, 14, 2, 11, 0, time.UTC),
+	Endpoint:    "https://api.github.com/graphql",
+	Options:     IntrospectionOptions{DeprecatedFields: true, DeprecatedEnumValues: true, DeprecatedInputValues: false, TypeRefDepth: 7, SchemaDescription: false, SpecifiedByURL: false, RepeatableDirectives: false},
+	Fingerprint: "8996ad6e72f530167545a278e93d253cbf417cf53b19afdeecb63000416c61f7",
+}
@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinel errors for branching on error kinds with errors.Is. Errors
+// returned by this package carry the details in typed values, such as
+// *NotFoundError and *StructureError, that match these sentinels.
+var (
+	// ErrTypeNotFound matches lookups of types the schema does not define
+	ErrTypeNotFound = errors.New("type not found")
+	// ErrFieldNotFound matches lookups of fields and input fields a type
+	// does not define
+	ErrFieldNotFound = errors.New("field not found")
+	// ErrMutationNotFound matches lookups of mutations the schema does not
+	// define
+	ErrMutationNotFound = errors.New("mutation not found")
+	// ErrInvalidSchema matches schema documents that cannot be parsed or do
+	// not have the structure of an introspection result
+	ErrInvalidSchema = errors.New("invalid schema")
+)
+
+// NotFoundError reports a type, field, or mutation the schema does not
+// define, with the names it likely meant
+type NotFoundError struct {
+	Kind        string   `json:"kind"`             // "type", "field", "mutation", or "target"
+	Name        string   `json:"name"`             // Name that was looked up
+	Parent      string   `json:"parent,omitempty"` // Type the field was looked up on
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+func (e *NotFoundError) Error() string {
+	msg := fmt.Sprintf("%s %q not found", e.Kind, e.Name)
+	if e.Parent != "" {
+		msg += fmt.Sprintf(" on type %q", e.Parent)
+	}
+	if len(e.Suggestions) == 0 {
+		return msg
+	}
+	quoted := make([]string, len(e.Suggestions))
+	for i, s := range e.Suggestions {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	switch len(quoted) {
+	case 1:
+		return msg + "; did you mean " + quoted[0] + "?"
+	case 2:
+		return msg + "; did you mean " + quoted[0] + " or " + quoted[1] + "?"
+	}
+	return msg + "; did you mean " + strings.Join(quoted[:len(quoted)-1], ", ") + ", or " + quoted[len(quoted)-1] + "?"
+}
+
+// Is reports whether target is the sentinel of the error's kind
+func (e *NotFoundError) Is(target error) bool {
+	switch e.Kind {
+	case "type":
+		return target == ErrTypeNotFound
+	case "field":
+		return target == ErrFieldNotFound
+	case "mutation":
+		return target == ErrMutationNotFound
+	}
+	return false
+}
+
+// invalidSchemaError marks an error in a schema document as ErrInvalidSchema
+// while keeping its message
+type invalidSchemaError struct {
+	err error
+}
+
+func (e *invalidSchemaError) Error() string {
+	return e.err.Error()
+}
+
+func (e *invalidSchemaError) Unwrap() []error {
+	return []error{ErrInvalidSchema, e.err}
+}
+
+// invalidSchema formats an error matching ErrInvalidSchema
+func invalidSchema(format string, args ...interface{}) error {
+	return &invalidSchemaError{err: fmt.Errorf(format, args...)}
+}
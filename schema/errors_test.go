@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	s := loadRichSchema(t)
+	l, err := NewLazyWithData(SampleData())
+	if err != nil {
+		t.Fatalf("Failed to create lazy schema: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		err    error
+		target error
+	}{
+		{"type", func() error { _, err := s.Type("Robot"); return err }(), ErrTypeNotFound},
+		{"lazy type", func() error { _, err := l.Type("Robot"); return err }(), ErrTypeNotFound},
+		{"input object", func() error { _, err := s.InputObject("RobotInput", 0); return err }(), ErrTypeNotFound},
+		{"path", func() error { _, err := s.ResolvePath("Robot.name"); return err }(), ErrTypeNotFound},
+		{"field", func() error { _, err := s.Field("Issue", "nope"); return err }(), ErrFieldNotFound},
+		{"canonical field", func() error { _, err := s.CanonicalFieldName("Issue", "nope"); return err }(), ErrFieldNotFound},
+		{"query field", func() error { _, err := l.QueryField("nope"); return err }(), ErrFieldNotFound},
+		{"mutation", func() error { _, err := s.Mutation("nope"); return err }(), ErrMutationNotFound},
+		{"lazy mutation", func() error { _, err := l.Mutation("nope"); return err }(), ErrMutationNotFound},
+		{"unparsable schema", func() error { _, err := NewWithData([]byte("{")); return err }(), ErrInvalidSchema},
+		{"unparsable lazy schema", func() error { _, err := NewLazyWithData([]byte("[")); return err }(), ErrInvalidSchema},
+		{"malformed schema", func() error { _, err := NewWithDataStrict([]byte(`{"data": {}}`)); return err }(), ErrInvalidSchema},
+	}
+	sentinels := []error{ErrTypeNotFound, ErrFieldNotFound, ErrMutationNotFound, ErrInvalidSchema}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err == nil {
+				t.Fatal("Expected an error")
+			}
+			for _, sentinel := range sentinels {
+				if got := errors.Is(tt.err, sentinel); got != (sentinel == tt.target) {
+					t.Errorf("errors.Is(%v, %v) = %v", tt.err, sentinel, got)
+				}
+			}
+		})
+	}
+}
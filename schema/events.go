@@ -0,0 +1,231 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+// EventPhase describes where a long-running operation is in its lifecycle
+type EventPhase string
+
+const (
+	EventStart    EventPhase = "start"
+	EventProgress EventPhase = "progress"
+	EventDone     EventPhase = "done"
+	EventError    EventPhase = "error"
+)
+
+// Event is a structured progress notification emitted by long-running operations
+// such as downloads, syncs, and diffs. Current and Total count Unit, such as
+// "files" or "types", or bytes when Unit is empty, as for downloads; Total is
+// zero when unknown.
+type Event struct {
+	Operation string     `json:"operation"`
+	Phase     EventPhase `json:"phase"`
+	Message   string     `json:"message,omitempty"`
+	Current   int64      `json:"current,omitempty"`
+	Total     int64      `json:"total,omitempty"`
+	Unit      string     `json:"unit,omitempty"`
+	ElapsedMS int64      `json:"elapsedMs"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// EventHandler receives progress events. Handlers are called synchronously
+// from the operation and should return quickly. A nil EventHandler is valid
+// and discards all events.
+type EventHandler func(Event)
+
+// EventChannel returns an EventHandler that forwards events to ch.
+// Events are dropped when ch is full so that a slow consumer never stalls the operation.
+func EventChannel(ch chan<- Event) EventHandler {
+	return func(ev Event) {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// JSONEventHandler returns an EventHandler that writes each event to w
+// as a single line of JSON (JSON Lines), suitable for --progress json
+func JSONEventHandler(w io.Writer) EventHandler {
+	var mu sync.Mutex
+	return func(ev Event) {
+		b, err := yamlformat.MarshalJSON(ev)
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		// MarshalJSON already ends the line; strip it so each event is exactly one line
+		w.Write(append(bytes.TrimRight(b, "\n"), '\n'))
+	}
+}
+
+// TextEventHandler returns an EventHandler that writes each event to w as a
+// line for people watching a terminal, such as
+// "download: receiving 1.5 MB of 4.2 MB (2.3s)" or
+// "lint: queries/a.graphql 3 of 10 files (0.1s)", suitable for --progress text
+func TextEventHandler(w io.Writer) EventHandler {
+	var mu sync.Mutex
+	return func(ev Event) {
+		elapsed := (time.Duration(ev.ElapsedMS) * time.Millisecond).Round(100 * time.Millisecond)
+		line := ev.Operation + ":"
+		switch ev.Phase {
+		case EventStart:
+			line += " started"
+		case EventError:
+			line += fmt.Sprintf(" failed after %s: %s", elapsed, ev.Error)
+		case EventDone:
+			line += " done"
+		}
+		if ev.Message != "" && ev.Phase != EventError {
+			line += " " + ev.Message
+		}
+		if ev.Current > 0 && ev.Phase != EventError {
+			if ev.Unit == "" {
+				line += " " + formatBytes(ev.Current)
+				if ev.Total > 0 {
+					line += " of " + formatBytes(ev.Total)
+				}
+			} else {
+				line += fmt.Sprintf(" %d", ev.Current)
+				if ev.Total > 0 {
+					line += fmt.Sprintf(" of %d", ev.Total)
+				}
+				line += " " + ev.Unit
+			}
+		}
+		if ev.Phase != EventError {
+			line += fmt.Sprintf(" (%s)", elapsed)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		io.WriteString(w, line+"\n")
+	}
+}
+
+// formatBytes formats a byte count with a decimal unit, such as 1.5 MB
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1e6:
+		return fmt.Sprintf("%.1f MB", float64(n)/1e6)
+	case n >= 1e3:
+		return fmt.Sprintf("%.1f kB", float64(n)/1e3)
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+// eventEmitter stamps events for one operation with its name and elapsed time
+type eventEmitter struct {
+	operation string
+	handler   EventHandler
+	start     time.Time
+}
+
+func newEventEmitter(operation string, handler EventHandler) *eventEmitter {
+	return &eventEmitter{operation: operation, handler: handler, start: time.Now()}
+}
+
+func (e *eventEmitter) emit(ev Event) {
+	if e == nil || e.handler == nil {
+		return
+	}
+	ev.Operation = e.operation
+	ev.ElapsedMS = time.Since(e.start).Milliseconds()
+	e.handler(ev)
+}
+
+// finish emits a done or error event depending on err and returns err unchanged
+func (e *eventEmitter) finish(err error, current int64) error {
+	if err != nil {
+		e.emit(Event{Phase: EventError, Current: current, Error: err.Error()})
+		return err
+	}
+	e.emit(Event{Phase: EventDone, Current: current})
+	return nil
+}
+
+// Progress reports an operation over a known number of items, such as the
+// files of a scan or the targets of a sync, as events counting the items
+type Progress struct {
+	emitter *eventEmitter
+	unit    string
+	total   int64
+
+	mu      sync.Mutex
+	current int64
+}
+
+// NewProgress emits the start event of operation over total items of unit,
+// such as "files", to handler, which may be nil
+func NewProgress(operation, unit string, total int, handler EventHandler) *Progress {
+	p := &Progress{emitter: newEventEmitter(operation, handler), unit: unit, total: int64(total)}
+	p.emitter.emit(Event{Phase: EventStart, Total: p.total, Unit: unit})
+	return p
+}
+
+// Step records n more items as done and emits a progress event with
+// message, such as the name of the last item. It is safe for concurrent use.
+func (p *Progress) Step(n int, message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current += int64(n)
+	p.emitter.emit(Event{Phase: EventProgress, Message: message, Current: p.current, Total: p.total, Unit: p.unit})
+}
+
+// Finish emits a done or error event depending on err and returns err
+// unchanged
+func (p *Progress) Finish(err error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil {
+		p.emitter.emit(Event{Phase: EventError, Current: p.current, Total: p.total, Unit: p.unit, Error: err.Error()})
+		return err
+	}
+	p.emitter.emit(Event{Phase: EventDone, Current: p.current, Total: p.total, Unit: p.unit})
+	return nil
+}
+
+// progressInterval is the minimum number of bytes between two progress events
+const progressInterval = 256 * 1024
+
+// receiveProgress returns an introspect.WithProgress callback emitting
+// throttled progress events for the bytes of a response received so far
+func receiveProgress(emitter *eventEmitter) func(received, total int64) {
+	var reported int64
+	return func(received, total int64) {
+		if received < reported {
+			// A retried request starts over
+			reported = 0
+		}
+		if received-reported < progressInterval && received != total {
+			return
+		}
+		reported = received
+		emitter.emit(Event{Phase: EventProgress, Message: "receiving", Current: received, Total: max(total, 0)})
+	}
+}
+
+// progressWriter counts bytes written through it and emits throttled progress events
+type progressWriter struct {
+	w        io.Writer
+	emitter  *eventEmitter
+	written  int64
+	reported int64
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	if p.written-p.reported >= progressInterval {
+		p.reported = p.written
+		p.emitter.emit(Event{Phase: EventProgress, Current: p.written})
+	}
+	return n, err
+}
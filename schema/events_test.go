@@ -0,0 +1,157 @@
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+func TestEventChannelDoesNotBlock(t *testing.T) {
+	ch := make(chan Event, 1)
+	handler := EventChannel(ch)
+
+	// The second event must be dropped instead of blocking
+	handler(Event{Operation: "download", Phase: EventStart})
+	handler(Event{Operation: "download", Phase: EventDone})
+
+	ev := <-ch
+	if ev.Phase != EventStart {
+		t.Errorf("Expected first event to be start, got %s", ev.Phase)
+	}
+	if len(ch) != 0 {
+		t.Errorf("Expected dropped event, got %d buffered", len(ch))
+	}
+}
+
+func TestJSONEventHandler(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := newEventEmitter("download", JSONEventHandler(&buf))
+	emitter.emit(Event{Phase: EventStart, Message: "schema.json"})
+	emitter.finish(nil, 42)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 JSON lines, got %d: %q", len(lines), buf.String())
+	}
+	var start, done Event
+	if err := yamlformat.Unmarshal([]byte(lines[0]), &start); err != nil {
+		t.Fatalf("Invalid JSON line %q: %v", lines[0], err)
+	}
+	if err := yamlformat.Unmarshal([]byte(lines[1]), &done); err != nil {
+		t.Fatalf("Invalid JSON line %q: %v", lines[1], err)
+	}
+	if start.Operation != "download" || start.Phase != EventStart || start.Message != "schema.json" {
+		t.Errorf("Unexpected start event: %s", lines[0])
+	}
+	if done.Phase != EventDone || done.Current != 42 {
+		t.Errorf("Unexpected done event: %s", lines[1])
+	}
+}
+
+func TestTextEventHandler(t *testing.T) {
+	var buf bytes.Buffer
+	handler := TextEventHandler(&buf)
+	for _, ev := range []Event{
+		{Operation: "download", Phase: EventStart, Message: "schema.json.gz"},
+		{Operation: "download", Phase: EventProgress, Message: "receiving", Current: 1_500_000, Total: 4_200_000, ElapsedMS: 2340},
+		{Operation: "download", Phase: EventProgress, Current: 512},
+		{Operation: "download", Phase: EventDone, Current: 12_345, ElapsedMS: 3100},
+		{Operation: "download", Phase: EventError, Error: "HTTP 502", ElapsedMS: 50},
+	} {
+		handler(ev)
+	}
+	want := `download: started schema.json.gz (0s)
+download: receiving 1.5 MB of 4.2 MB (2.3s)
+download: 512 B (0s)
+download: done 12.3 kB (3.1s)
+download: failed after 100ms: HTTP 502
+`
+	if buf.String() != want {
+		t.Errorf("Got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewProgress("lint", "files", 2, TextEventHandler(&buf))
+	p.Step(1, "a.graphql")
+	p.Step(1, "b.graphql")
+	if err := p.Finish(errors.New("b.graphql: syntax error")); err == nil {
+		t.Error("Expected Finish to return the error")
+	}
+	for _, want := range []string{
+		"lint: started (",
+		"lint: a.graphql 1 of 2 files (",
+		"lint: b.graphql 2 of 2 files (",
+		"lint: failed after ",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Output does not contain %q:\n%s", want, buf.String())
+		}
+	}
+
+	// A nil handler must be safe to use
+	p = NewProgress("lint", "files", 1, nil)
+	p.Step(1, "a.graphql")
+	if err := p.Finish(nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReceiveProgress(t *testing.T) {
+	var events []Event
+	progress := receiveProgress(newEventEmitter("download", func(ev Event) { events = append(events, ev) }))
+	for _, received := range []int64{1000, progressInterval, progressInterval + 1000, 2*progressInterval + 500} {
+		progress(received, 2*progressInterval+500)
+	}
+	// A retried request starts over
+	progress(progressInterval, -1)
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 throttled events, got %+v", events)
+	}
+	if last := events[1]; last.Current != 2*progressInterval+500 || last.Total != 2*progressInterval+500 || last.Message != "receiving" {
+		t.Errorf("Unexpected final event %+v", last)
+	}
+	if events[2].Total != 0 {
+		t.Errorf("Expected an unknown total to be zero, got %+v", events[2])
+	}
+}
+
+func TestProgressWriter(t *testing.T) {
+	var events []Event
+	emitter := newEventEmitter("download", func(ev Event) { events = append(events, ev) })
+
+	var buf bytes.Buffer
+	pw := &progressWriter{w: &buf, emitter: emitter}
+	chunk := make([]byte, progressInterval/2)
+	for i := 0; i < 5; i++ {
+		if _, err := pw.Write(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if pw.written != int64(len(chunk)*5) {
+		t.Errorf("Expected %d bytes written, got %d", len(chunk)*5, pw.written)
+	}
+	if len(events) != 2 {
+		t.Errorf("Expected 2 throttled progress events, got %d", len(events))
+	}
+	for _, ev := range events {
+		if ev.Phase != EventProgress || ev.Operation != "download" {
+			t.Errorf("Unexpected event: %+v", ev)
+		}
+	}
+}
+
+func TestNilEventHandler(t *testing.T) {
+	// A nil handler must be safe to use
+	emitter := newEventEmitter("download", nil)
+	emitter.emit(Event{Phase: EventStart})
+	if err := emitter.finish(nil, 0); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+)
+
+// QueryPlan is an operation laid out as a tree of its selections, each
+// annotated with its resolved type and the share of the cost estimate spent
+// under it, see ExplainOperation
+type QueryPlan struct {
+	Estimate *CostEstimate `json:"estimate"`
+	Root     *PlanNode     `json:"root"`
+}
+
+// PlanNode is a selection of an operation in a QueryPlan. Requests and Nodes
+// total the connections of the whole branch, the node itself included.
+type PlanNode struct {
+	// Name is the response key of a field, "... on Type" for an inline
+	// fragment, "...Name" for a fragment spread, or the operation type and
+	// name for the root
+	Name string `json:"name"`
+	// Path is the response path, such as "repository.issues"; fragments
+	// have the path of the selection set they are in
+	Path     string      `json:"path,omitempty"`
+	Field    string      `json:"field,omitempty"` // Coordinate such as "Repository.issues"
+	Type     string      `json:"type"`            // Field type, or the type condition of a fragment
+	PageSize int         `json:"pageSize,omitempty"`
+	Resolved int         `json:"resolved"` // Times resolved: the product of the enclosing page sizes
+	Requests int         `json:"requests"`
+	Nodes    int         `json:"nodes"`
+	Percent  int         `json:"percent"` // Share of the operation's connection requests
+	Children []*PlanNode `json:"children,omitempty"`
+}
+
+func (n *PlanNode) add(child *PlanNode) *PlanNode {
+	n.Children = append(n.Children, child)
+	return child
+}
+
+func childPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// Explain lays out the only operation in doc as a QueryPlan. Variables and
+// errors are as for EstimateCost.
+func (s *Schema) Explain(doc string, variables map[string]interface{}) (*QueryPlan, error) {
+	parsed, err := graphql.Parse(doc)
+	if err != nil {
+		return nil, err
+	}
+	return s.ExplainOperation(parsed, "", variables)
+}
+
+// ExplainOperation is like Explain for a parsed document. operationName may
+// be empty when the document has a single operation.
+func (s *Schema) ExplainOperation(doc *graphql.Document, operationName string, variables map[string]interface{}) (*QueryPlan, error) {
+	op, root, w, err := s.costWalker(doc, operationName, variables)
+	if err != nil {
+		return nil, err
+	}
+	name := string(op.Operation)
+	if op.Name != "" {
+		name += " " + op.Name
+	}
+	node := &PlanNode{Name: name, Type: root, Resolved: 1}
+	if err := w.fragment(root, op.SelectionSet, 1, op.Pos, node); err != nil {
+		return nil, err
+	}
+	setPercent(node, w.requests)
+	return &QueryPlan{Estimate: w.estimate(op), Root: node}, nil
+}
+
+func setPercent(n *PlanNode, total int) {
+	if total > 0 {
+		n.Percent = int((int64(n.Requests)*100 + int64(total)/2) / int64(total))
+	}
+	for _, c := range n.Children {
+		setPercent(c, total)
+	}
+}
+
+// Tree renders the plan as an indented text tree
+func (p *QueryPlan) Tree() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s  requests %d, nodes %d, cost %d\n", p.Root.Name, p.Root.Type, p.Estimate.Requests, p.Estimate.Nodes, p.Estimate.Cost)
+	var walk func(n *PlanNode, indent string)
+	walk = func(n *PlanNode, indent string) {
+		for i, c := range n.Children {
+			branch, next := "├── ", "│   "
+			if i == len(n.Children)-1 {
+				branch, next = "└── ", "    "
+			}
+			b.WriteString(indent + branch + c.Name + ": " + c.Type)
+			if details := c.details(); len(details) > 0 {
+				b.WriteString("  " + strings.Join(details, ", "))
+			}
+			b.WriteByte('\n')
+			walk(c, indent+next)
+		}
+	}
+	walk(p.Root, "")
+	return b.String()
+}
+
+// Mermaid renders the plan as a Mermaid flowchart. Connections are
+// highlighted, and so are branches holding half or more of the requests.
+func (p *QueryPlan) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	fmt.Fprintf(&b, "  n0[\"%s: %s<br/>requests %d, nodes %d, cost %d\"]\n",
+		mermaidEscape(p.Root.Name), mermaidEscape(p.Root.Type), p.Estimate.Requests, p.Estimate.Nodes, p.Estimate.Cost)
+	var connections, costly []string
+	id := 0
+	var walk func(n *PlanNode, parent string)
+	walk = func(n *PlanNode, parent string) {
+		for _, c := range n.Children {
+			id++
+			node := fmt.Sprintf("n%d", id)
+			label := mermaidEscape(c.Name + ": " + c.Type)
+			if details := c.details(); len(details) > 0 {
+				label += "<br/>" + mermaidEscape(strings.Join(details, ", "))
+			}
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n  %s --> %s\n", node, label, parent, node)
+			switch {
+			case c.Percent >= 50:
+				costly = append(costly, node)
+			case c.PageSize > 0:
+				connections = append(connections, node)
+			}
+			walk(c, node)
+		}
+	}
+	walk(p.Root, "n0")
+	if len(connections) > 0 {
+		b.WriteString("  classDef connection fill:#fff3cd,stroke:#d4a106\n")
+		fmt.Fprintf(&b, "  class %s connection\n", strings.Join(connections, ","))
+	}
+	if len(costly) > 0 {
+		b.WriteString("  classDef costly fill:#f8d7da,stroke:#c62828\n")
+		fmt.Fprintf(&b, "  class %s costly\n", strings.Join(costly, ","))
+	}
+	return b.String()
+}
+
+// details describes the page size, resolution count, and branch totals of n
+func (n *PlanNode) details() []string {
+	var details []string
+	if n.PageSize > 0 {
+		details = append(details, fmt.Sprintf("page size %d", n.PageSize))
+	}
+	if n.Resolved > 1 {
+		details = append(details, fmt.Sprintf("resolved %d times", n.Resolved))
+	}
+	if n.Requests > 0 {
+		details = append(details, fmt.Sprintf("requests %d (%d%%), nodes %d", n.Requests, n.Percent, n.Nodes))
+	}
+	return details
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	s := loadRichSchema(t)
+
+	plan, err := s.Explain(`query Nested($first: Int = 10) {
+  viewer { login }
+  repository(owner: "o", name: "n") {
+    issues(first: $first) { nodes { ...F } }
+  }
+}
+fragment F on Issue { title repository { issues(last: 20) { totalCount } } }`, nil)
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if want := (CostEstimate{Operation: "Nested", Requests: 11, Nodes: 210, Cost: 1}); *plan.Estimate != want {
+		t.Errorf("Estimate = %+v, want %+v", *plan.Estimate, want)
+	}
+	if plan.Root.Name != "query Nested" || plan.Root.Type != "Query" || len(plan.Root.Children) != 2 {
+		t.Fatalf("Root = %+v", plan.Root)
+	}
+
+	issues := plan.Root.Children[1].Children[0]
+	if issues.Path != "repository.issues" || issues.Field != "Repository.issues" || issues.Type != "IssueConnection!" ||
+		issues.PageSize != 10 || issues.Requests != 11 || issues.Nodes != 210 || issues.Percent != 100 {
+		t.Errorf("repository.issues = %+v", issues)
+	}
+	spread := issues.Children[0].Children[0]
+	if spread.Name != "...F" || spread.Type != "Issue" || spread.Resolved != 10 || spread.Requests != 10 || spread.Percent != 91 {
+		t.Errorf("...F = %+v", spread)
+	}
+	nested := spread.Children[1].Children[0]
+	if nested.Path != "repository.issues.nodes.repository.issues" || nested.PageSize != 20 || nested.Nodes != 200 {
+		t.Errorf("Nested issues = %+v", nested)
+	}
+	if leaf := nested.Children[0]; leaf.Resolved != 200 || leaf.Requests != 0 {
+		t.Errorf("totalCount = %+v", leaf)
+	}
+
+	tree := plan.Tree()
+	for _, want := range []string{
+		"query Nested: Query  requests 11, nodes 210, cost 1\n",
+		"├── viewer: User!\n│   └── login: String!\n",
+		"    └── issues: IssueConnection!  page size 10, requests 11 (100%), nodes 210\n",
+	} {
+		if !strings.Contains(tree, want) {
+			t.Errorf("Tree does not contain %q:\n%s", want, tree)
+		}
+	}
+	mermaid := plan.Mermaid()
+	for _, want := range []string{
+		"flowchart TD\n",
+		`n4["issues: IssueConnection!<br/>page size 10, requests 11 (100%), nodes 210"]`,
+		"n3 --> n4\n",
+		"class n3,n4,n5,n6,n8,n9 costly\n",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid does not contain %q:\n%s", want, mermaid)
+		}
+	}
+
+	if _, err := s.Explain(`{ viewer { issues { totalCount } } }`, nil); err == nil {
+		t.Error("Expected an error for a connection without a page size")
+	}
+}
+
+func TestMermaidEscape(t *testing.T) {
+	if got := mermaidEscape(`a "b" <c>`); got != "a #quot;b#quot; #lt;c#gt;" {
+		t.Errorf("mermaidEscape = %q", got)
+	}
+}
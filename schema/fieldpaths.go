@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// FieldPath is a way to reach a field from the query root, as found by
+// FieldPaths
+type FieldPath struct {
+	Type   string `json:"type"`   // Type exposing the field
+	Path   string `json:"path"`   // Dotted path accepted by Skeleton
+	Length int    `json:"length"` // Number of fields selected along the path
+}
+
+// lookupFields are the fields of the query root that fetch any object by a
+// global ID or URL, which FieldPaths does not follow since they would be the
+// shortest path to nearly every type
+var lookupFields = []string{"node", "nodes", "resource"}
+
+// FieldPaths finds every object and interface type exposing a field and the
+// shortest path of fields from the query root to each, ranked by length and
+// then by schema order. Types the query root cannot reach are left out.
+//
+// Paths step into a possible type of an interface or union with a segment
+// naming the type, as in "repository.object.Commit.statusCheckRollup", which
+// does not count towards the length. Connections are walked through nodes.
+// The lookupFields of the query root are not followed.
+func (s *Schema) FieldPaths(field string) ([]FieldPath, error) {
+	m := s.Model()
+	root := m.Type(m.QueryType)
+	if root == nil {
+		return nil, invalidSchema("schema has no query type")
+	}
+
+	// Breadth-first search over fields; a possible type of an abstract type
+	// is found at the same distance as the abstract type
+	paths := map[string][]string{root.Name: nil}
+	lengths := map[string]int{root.Name: 0}
+	queue := []*Type{root}
+	var visit func(t *Type, path []string, length int)
+	visit = func(t *Type, path []string, length int) {
+		if _, ok := paths[t.Name]; ok {
+			return
+		}
+		paths[t.Name], lengths[t.Name] = path, length
+		queue = append(queue, t)
+		for _, name := range t.PossibleTypes {
+			if possible := m.Type(name); possible != nil {
+				visit(possible, append(path[:len(path):len(path)], name), length)
+			}
+		}
+	}
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+		for _, f := range t.Fields {
+			if t == root && slices.Contains(lookupFields, f.Name) {
+				continue
+			}
+			next := m.Type(f.Type.NamedType())
+			if next == nil || !isCompositeKind(next.Kind) {
+				continue
+			}
+			path := paths[t.Name]
+			visit(next, append(path[:len(path):len(path)], f.Name), lengths[t.Name]+1)
+		}
+	}
+
+	found := []FieldPath{}
+	for _, t := range m.Types {
+		if t.Field(field) == nil {
+			continue
+		}
+		path, ok := paths[t.Name]
+		if !ok {
+			continue
+		}
+		found = append(found, FieldPath{
+			Type:   t.Name,
+			Path:   strings.Join(append(path[:len(path):len(path)], field), "."),
+			Length: lengths[t.Name] + 1,
+		})
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no type reachable from %s has a field %q", root.Name, field)
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Length < found[j].Length })
+	return found, nil
+}
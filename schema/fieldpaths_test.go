@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldPaths(t *testing.T) {
+	s := loadRichSchema(t)
+
+	got, err := s.FieldPaths("login")
+	if err != nil {
+		t.Fatalf("FieldPaths failed: %v", err)
+	}
+	want := []FieldPath{
+		{Type: "User", Path: "viewer.login", Length: 2},
+		{Type: "Actor", Path: "repository.owner.login", Length: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	// Paths through unions and connections expand into valid operations
+	for _, field := range []string{"title", "endCursor", "totalCount"} {
+		paths, err := s.FieldPaths(field)
+		if err != nil {
+			t.Fatalf("FieldPaths(%q) failed: %v", field, err)
+		}
+		for _, p := range paths {
+			doc, err := s.Skeleton(p.Path)
+			if err != nil {
+				t.Fatalf("Skeleton(%q) failed: %v", p.Path, err)
+			}
+			if errs, err := s.ValidateQuery(doc); err != nil || len(errs) > 0 {
+				t.Errorf("Expected a valid operation for %s, got %v %v:\n%s", p.Path, errs, err, doc)
+			}
+		}
+	}
+
+	// Mutation payloads are not reachable from the query root
+	if _, err := s.FieldPaths("clientMutationId"); err == nil {
+		t.Error("Expected error for a field only mutations reach")
+	}
+}
@@ -0,0 +1,30 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// syntheticData reads testdata/synthetic.json, a small schema in the shape of
+// GitHub's with members the GitHub schema lacks, such as oneOf and recursive
+// input objects and deprecated input fields, for tests of those cases
+func syntheticData(t testing.TB) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "synthetic.json"))
+	if err != nil {
+		t.Fatalf("Failed to read synthetic schema: %v", err)
+	}
+	return data
+}
+
+// loadRichSchema loads the synthetic schema, a small but self-consistent
+// schema with interfaces, unions, connections, deprecations, and nested inputs
+func loadRichSchema(t testing.TB) *Schema {
+	t.Helper()
+	s, err := NewWithDataStrict(syntheticData(t))
+	if err != nil {
+		t.Fatalf("Failed to load synthetic schema: %v", err)
+	}
+	return s
+}
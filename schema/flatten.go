@@ -0,0 +1,270 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/go-yamlformat"
+)
+
+// FlatTable is a GraphQL response flattened into rows, as returned by Flatten.
+// Columns lists the dotted column names in selection order; every row has a
+// value (possibly nil) for every column.
+type FlatTable struct {
+	Columns []string                 `json:"columns"`
+	Rows    []map[string]interface{} `json:"rows"`
+}
+
+// Flatten turns a GraphQL response into flat records suitable for CSV or
+// warehouse loads, using the operation that produced it and the schema's types.
+//
+// Nested objects become dotted column names built from response keys (aliases
+// are honoured). Lists of objects are unrolled into one row per element, and
+// connections are unrolled through their nodes or edges without adding
+// "nodes", "edges", or "node" to the column names; pageInfo is omitted.
+// Sibling lists produce the cross product of their rows. An empty list or a
+// null object keeps its parent row with nil values, like a left join.
+//
+// response may be the full response with a "data" member or the data object
+// itself. operation is the GraphQL document; it must contain exactly one
+// operation.
+func (s *Schema) Flatten(response []byte, operation string) (*FlatTable, error) {
+	doc, err := graphql.Parse(operation)
+	if err != nil {
+		return nil, err
+	}
+	op, err := doc.Operation("")
+	if err != nil {
+		return nil, err
+	}
+	root := s.RootTypeName(string(op.Operation))
+	if root == "" {
+		return nil, fmt.Errorf("schema does not support %s operations", op.Operation)
+	}
+
+	var decoded interface{}
+	if err := yamlformat.Unmarshal(response, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	data, ok := decoded.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("response must be a JSON object")
+	}
+	if d, ok := data["data"]; ok {
+		if data, ok = d.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("response has no data")
+		}
+	}
+
+	f := &flattener{
+		schema:    s,
+		fragments: doc.Fragments(),
+		seen:      make(map[string]bool),
+	}
+	rows, err := f.object(data, root, []graphql.SelectionSet{op.SelectionSet}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows built from branches that were absent lack some columns
+	for _, row := range rows {
+		for _, column := range f.columns {
+			if _, ok := row[column]; !ok {
+				row[column] = nil
+			}
+		}
+	}
+	return &FlatTable{Columns: f.columns, Rows: rows}, nil
+}
+
+type flattener struct {
+	schema    *Schema
+	fragments map[string]*graphql.FragmentDefinition
+	columns   []string
+	seen      map[string]bool
+}
+
+// collectedField is a response key with all selections that contribute to it,
+// after expanding fragments
+type collectedField struct {
+	key     string
+	field   *graphql.Field
+	parent  string // Type the field is selected on
+	applies bool   // False for fragments on other concrete types
+	sets    []graphql.SelectionSet
+}
+
+// object flattens obj (possibly nil) of type typeName into rows
+func (f *flattener) object(obj map[string]interface{}, typeName string, sets []graphql.SelectionSet, prefix string) ([]map[string]interface{}, error) {
+	typename, _ := obj["__typename"].(string)
+	var fields []*collectedField
+	byKey := make(map[string]*collectedField)
+	for _, set := range sets {
+		if err := f.collect(typeName, set, typename, obj != nil, byKey, &fields, make(map[string]bool)); err != nil {
+			return nil, err
+		}
+	}
+
+	connection := f.isConnection(typeName)
+	edge := f.isEdge(typeName)
+	rows := []map[string]interface{}{{}}
+	for _, c := range fields {
+		var value interface{}
+		if c.applies {
+			value = obj[c.key]
+		}
+
+		var ref *TypeRef
+		if c.field.Name != "__typename" {
+			if ref = TypeRefFromMap(f.schema.rawField(c.parent, c.field.Name)["type"]); ref == nil {
+				return nil, fmt.Errorf("field %q not found on type %q", c.field.Name, c.parent)
+			}
+		}
+
+		column := joinColumn(prefix, c.key)
+		if len(c.sets) == 0 || ref == nil {
+			f.addColumn(column)
+			for _, row := range rows {
+				row[column] = value
+			}
+			continue
+		}
+
+		switch {
+		case connection && c.field.Name == "pageInfo":
+			continue
+		case connection && (c.field.Name == "nodes" || c.field.Name == "edges"), edge && c.field.Name == "node":
+			column = prefix
+		}
+
+		unwrapped := UnwrapTypeRef(ref)
+		named := unwrapped.Name
+
+		var sub []map[string]interface{}
+		items, _ := value.([]interface{})
+		if unwrapped.IsList() && len(items) > 0 {
+			for _, item := range items {
+				itemRows, err := f.object(asObject(item), named, c.sets, column)
+				if err != nil {
+					return nil, err
+				}
+				sub = append(sub, itemRows...)
+			}
+		} else {
+			var err error
+			if sub, err = f.object(asObject(value), named, c.sets, column); err != nil {
+				return nil, err
+			}
+		}
+		rows = crossRows(rows, sub)
+	}
+	return rows, nil
+}
+
+// collect expands fragments and groups the fields of a selection set by response key
+func (f *flattener) collect(typeName string, set graphql.SelectionSet, typename string, applies bool, byKey map[string]*collectedField, out *[]*collectedField, active map[string]bool) error {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *graphql.Field:
+			key := sel.ResponseKey()
+			c, ok := byKey[key]
+			if !ok {
+				c = &collectedField{key: key, field: sel, parent: typeName}
+				byKey[key] = c
+				*out = append(*out, c)
+			}
+			if applies && !c.applies {
+				c.applies, c.parent = true, typeName
+			}
+			if sel.SelectionSet != nil {
+				c.sets = append(c.sets, sel.SelectionSet)
+			}
+		case *graphql.InlineFragment:
+			condition := typeName
+			if sel.TypeCondition != "" {
+				condition = sel.TypeCondition
+			}
+			if err := f.collect(condition, sel.SelectionSet, typename, f.fragmentApplies(applies, typename, condition), byKey, out, active); err != nil {
+				return err
+			}
+		case *graphql.FragmentSpread:
+			fragment, ok := f.fragments[sel.Name]
+			if !ok {
+				return fmt.Errorf("unknown fragment %q", sel.Name)
+			}
+			if active[sel.Name] {
+				return fmt.Errorf("fragment %q spreads itself", sel.Name)
+			}
+			active[sel.Name] = true
+			err := f.collect(fragment.TypeCondition, fragment.SelectionSet, typename, f.fragmentApplies(applies, typename, fragment.TypeCondition), byKey, out, active)
+			delete(active, sel.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fragmentApplies reports whether a fragment on condition contributes values
+// to an object; without __typename the response keys decide
+func (f *flattener) fragmentApplies(applies bool, typename, condition string) bool {
+	return applies && (typename == "" || f.schema.isSubtype(typename, condition))
+}
+
+// isConnection reports whether a type follows the Relay connection shape
+func (f *flattener) isConnection(typeName string) bool {
+	return f.schema.rawField(typeName, "pageInfo") != nil &&
+		(f.schema.rawField(typeName, "nodes") != nil || f.schema.rawField(typeName, "edges") != nil)
+}
+
+// isEdge reports whether a type follows the Relay edge shape
+func (f *flattener) isEdge(typeName string) bool {
+	return f.schema.rawField(typeName, "node") != nil && f.schema.rawField(typeName, "cursor") != nil
+}
+
+func (f *flattener) addColumn(column string) {
+	if !f.seen[column] {
+		f.seen[column] = true
+		f.columns = append(f.columns, column)
+	}
+}
+
+func asObject(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func joinColumn(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// crossRows combines every row of a with every row of b
+func crossRows(a, b []map[string]interface{}) []map[string]interface{} {
+	if len(b) == 1 {
+		for _, row := range a {
+			for k, v := range b[0] {
+				row[k] = v
+			}
+		}
+		return a
+	}
+
+	out := make([]map[string]interface{}, 0, len(a)*len(b))
+	for _, left := range a {
+		for _, right := range b {
+			row := make(map[string]interface{}, len(left)+len(right))
+			for k, v := range left {
+				row[k] = v
+			}
+			for k, v := range right {
+				row[k] = v
+			}
+			out = append(out, row)
+		}
+	}
+	return out
+}
@@ -0,0 +1,161 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	s := loadRichSchema(t)
+
+	tests := []struct {
+		name      string
+		operation string
+		response  string
+		columns   []string
+		rows      []map[string]interface{}
+	}{
+		{
+			name: "connection nodes",
+			operation: `query {
+  repository(owner: "o", name: "n") {
+    name
+    issues(first: 2) { totalCount pageInfo { hasNextPage } nodes { number author { login } } }
+  }
+}`,
+			response: `{"data":{"repository":{"name":"cli","issues":{"totalCount":2,"pageInfo":{"hasNextPage":false},"nodes":[
+  {"number":1,"author":{"login":"octocat"}},
+  {"number":2,"author":null}
+]}}}}`,
+			columns: []string{"repository.name", "repository.issues.totalCount", "repository.issues.number", "repository.issues.author.login"},
+			rows: []map[string]interface{}{
+				{"repository.name": "cli", "repository.issues.totalCount": float64(2), "repository.issues.number": float64(1), "repository.issues.author.login": "octocat"},
+				{"repository.name": "cli", "repository.issues.totalCount": float64(2), "repository.issues.number": float64(2), "repository.issues.author.login": nil},
+			},
+		},
+		{
+			name:      "edges and aliases",
+			operation: `{ repo: repository(owner: "o", name: "n") { issues(first: 2) { edges { cursor node { t: title } } } } }`,
+			response:  `{"repo":{"issues":{"edges":[{"cursor":"a","node":{"t":"one"}},{"cursor":"b","node":{"t":"two"}}]}}}`,
+			columns:   []string{"repo.issues.cursor", "repo.issues.t"},
+			rows: []map[string]interface{}{
+				{"repo.issues.cursor": "a", "repo.issues.t": "one"},
+				{"repo.issues.cursor": "b", "repo.issues.t": "two"},
+			},
+		},
+		{
+			name: "union members",
+			operation: `query {
+  search(first: 2, query: "q", type: ISSUE) { nodes { __typename ... on Issue { title } ...UserFields } }
+}
+fragment UserFields on User { login }`,
+			response: `{"data":{"search":{"nodes":[{"__typename":"Issue","title":"Bug"},{"__typename":"User","login":"octocat"}]}}}`,
+			columns:  []string{"search.__typename", "search.title", "search.login"},
+			rows: []map[string]interface{}{
+				{"search.__typename": "Issue", "search.title": "Bug", "search.login": nil},
+				{"search.__typename": "User", "search.title": nil, "search.login": "octocat"},
+			},
+		},
+		{
+			name:      "empty connection keeps parent",
+			operation: `{ repository(owner: "o", name: "n") { name issues(first: 1) { nodes { number } } } }`,
+			response:  `{"data":{"repository":{"name":"cli","issues":{"nodes":[]}}}}`,
+			columns:   []string{"repository.name", "repository.issues.number"},
+			rows: []map[string]interface{}{
+				{"repository.name": "cli", "repository.issues.number": nil},
+			},
+		},
+		{
+			name:      "sibling lists cross",
+			operation: `{ a: search(first: 2, query: "a", type: USER) { nodes { ... on User { login } } } b: search(first: 2, query: "b", type: USER) { nodes { ... on User { login } } } }`,
+			response:  `{"a":{"nodes":[{"login":"x"},{"login":"y"}]},"b":{"nodes":[{"login":"z"}]}}`,
+			columns:   []string{"a.login", "b.login"},
+			rows: []map[string]interface{}{
+				{"a.login": "x", "b.login": "z"},
+				{"a.login": "y", "b.login": "z"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := s.Flatten([]byte(tt.response), tt.operation)
+			if err != nil {
+				t.Fatalf("Flatten failed: %v", err)
+			}
+			if !reflect.DeepEqual(table.Columns, tt.columns) {
+				t.Errorf("Columns = %v, want %v", table.Columns, tt.columns)
+			}
+			if len(table.Rows) != len(tt.rows) {
+				t.Fatalf("Got %d rows, want %d: %v", len(table.Rows), len(tt.rows), table.Rows)
+			}
+			for i, row := range table.Rows {
+				if !equalRow(row, tt.rows[i]) {
+					t.Errorf("Row %d = %v, want %v", i, row, tt.rows[i])
+				}
+			}
+		})
+	}
+}
+
+// equalRow compares rows, treating all JSON numbers as float64
+func equalRow(got, want map[string]interface{}) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for k, w := range want {
+		g, ok := got[k]
+		if !ok {
+			return false
+		}
+		if n, ok := toFloat(g); ok {
+			g = n
+		}
+		if g != w {
+			return false
+		}
+	}
+	return true
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func TestFlattenErrors(t *testing.T) {
+	s := loadRichSchema(t)
+
+	tests := []struct {
+		name      string
+		operation string
+		response  string
+		want      string
+	}{
+		{"unknown field", `{ viewer { nope { id } } }`, `{"viewer":{}}`, `field "nope" not found on type "User"`},
+		{"unknown leaf field", `{ viewer { nope } }`, `{"viewer":{}}`, `field "nope" not found on type "User"`},
+		{"syntax error", `{ viewer `, `{}`, "syntax error"},
+		{"not an object", `{ viewer { login } }`, `[]`, "must be a JSON object"},
+		{"subscription", `subscription { viewer { login } }`, `{}`, "does not support subscription"},
+		{"unknown fragment", `{ viewer { ...F } }`, `{}`, `unknown fragment "F"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Flatten([]byte(tt.response), tt.operation)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
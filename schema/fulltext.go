@@ -0,0 +1,324 @@
+package schema
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+// TextMatch is a description matching SearchText or SearchDescriptions
+type TextMatch struct {
+	Coordinate string   `json:"coordinate"`           // Schema coordinate such as "Repository.issues(first:)"
+	Kind       string   `json:"kind"`                 // type, field, argument, inputField, enumValue, or directive
+	Path       string   `json:"path"`                 // JSON path of the description in the introspection document
+	Snippet    string   `json:"snippet"`              // Part of the description around the first match
+	Highlights [][2]int `json:"highlights,omitempty"` // Byte ranges of matched terms within Snippet
+	Score      int      `json:"score,omitempty"`      // Only set by SearchText
+}
+
+// Highlight returns the snippet with every highlighted range wrapped in open
+// and close, such as "**" and "**" or "<mark>" and "</mark>"
+func (m TextMatch) Highlight(open, close string) string {
+	var b strings.Builder
+	last := 0
+	for _, h := range m.Highlights {
+		b.WriteString(m.Snippet[last:h[0]])
+		b.WriteString(open)
+		b.WriteString(m.Snippet[h[0]:h[1]])
+		b.WriteString(close)
+		last = h[1]
+	}
+	b.WriteString(m.Snippet[last:])
+	return b.String()
+}
+
+// snippetContext is how many bytes of the description precede the first
+// match in a snippet, and snippetLength the snippet size before trimming to
+// word boundaries
+const (
+	snippetContext = 60
+	snippetLength  = 200
+)
+
+// SearchText searches the descriptions of types, fields, arguments, input
+// fields, enum values, and directives for text. Every whitespace-separated
+// term must occur, case-insensitively; a quoted "phrase" counts as one term.
+// Matches are ranked by how often the terms occur, whether they occur as the
+// typed phrase, and whether they also occur in the member name. limit <= 0
+// returns all matches.
+func (s *Schema) SearchText(text string, limit int) ([]TextMatch, error) {
+	terms := searchTerms(text)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("search text must contain at least one term")
+	}
+	patterns := make([]*regexp.Regexp, len(terms))
+	for i, term := range terms {
+		patterns[i] = regexp.MustCompile("(?i)" + regexp.QuoteMeta(term))
+	}
+	phrase := regexp.MustCompile(`(?i)` + strings.Join(quoteAll(terms), `\s+`))
+
+	var matches []TextMatch
+	walkDescriptions(s.tree(), func(d describedItem) {
+		score := 0
+		for _, p := range patterns {
+			n := len(p.FindAllStringIndex(d.Description, -1))
+			if n == 0 {
+				return
+			}
+			score += n
+			if p.MatchString(d.Name) {
+				score += 2
+			}
+		}
+		if len(terms) > 1 && phrase.MatchString(d.Description) {
+			score += 5
+		}
+		m := snippet(d.Description, patterns)
+		m.Coordinate, m.Kind, m.Path, m.Score = d.Coordinate, d.Kind, d.Path, score
+		matches = append(matches, m)
+	})
+
+	// Stable, so equal scores keep document order
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// DefaultSearchLimit is the number of matches SearchTextHandler returns when
+// a request does not give a limit
+var DefaultSearchLimit = 20
+
+// SearchTextHandler returns an http.Handler answering GET requests such as
+// /search/fulltext?q=merge+pull+request&limit=5 with the matches of
+// SearchText as JSON: {"count": 1, "text": "...", "matches": [...]}. The
+// limit parameter defaults to DefaultSearchLimit, 0 returns all matches, and
+// highlight=** wraps the matched terms of each snippet in the marker instead
+// of listing their byte ranges, like the fulltext command.
+func SearchTextHandler(s *Schema) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		query := r.URL.Query()
+		text := query.Get("q")
+		limit := DefaultSearchLimit
+		if v := query.Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid limit %q", v), http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+
+		matches, err := s.SearchText(text, limit)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if mark := query.Get("highlight"); mark != "" {
+			for i, m := range matches {
+				matches[i].Snippet = m.Highlight(mark, mark)
+				matches[i].Highlights = nil
+			}
+		}
+		if matches == nil {
+			matches = []TextMatch{}
+		}
+		data, err := yamlformat.MarshalJSON(map[string]interface{}{
+			"count":   len(matches),
+			"text":    text,
+			"matches": matches,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+// SearchDescriptions returns the described elements whose description matches
+// the regular expression pattern, case-insensitively, in schema order. Unlike
+// Search, which only looks at type names, it covers the descriptions of
+// types, fields, arguments, input fields, enum values, and directives.
+func (s *Schema) SearchDescriptions(pattern string) ([]TextMatch, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	patterns := []*regexp.Regexp{re}
+
+	matches := []TextMatch{}
+	walkDescriptions(s.tree(), func(d describedItem) {
+		if !re.MatchString(d.Description) {
+			return
+		}
+		m := snippet(d.Description, patterns)
+		m.Coordinate, m.Kind, m.Path = d.Coordinate, d.Kind, d.Path
+		matches = append(matches, m)
+	})
+	return matches, nil
+}
+
+// searchTerms splits text into terms, keeping quoted phrases together
+func searchTerms(text string) []string {
+	var terms []string
+	for i, part := range strings.Split(text, `"`) {
+		if i%2 == 1 {
+			if phrase := strings.Join(strings.Fields(part), " "); phrase != "" {
+				terms = append(terms, phrase)
+			}
+			continue
+		}
+		terms = append(terms, strings.Fields(part)...)
+	}
+	return terms
+}
+
+func quoteAll(terms []string) []string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	return quoted
+}
+
+// snippet cuts a window of description around the first match of any pattern,
+// on word boundaries, and records where each pattern matches inside it
+func snippet(description string, patterns []*regexp.Regexp) TextMatch {
+	first := len(description)
+	for _, p := range patterns {
+		if loc := p.FindStringIndex(description); loc != nil && loc[0] < first {
+			first = loc[0]
+		}
+	}
+
+	start, end := 0, len(description)
+	if first > snippetContext {
+		start = first - snippetContext
+		if i := strings.IndexAny(description[start:first], " \n"); i >= 0 {
+			start += i + 1
+		}
+		for start < first && !utf8.RuneStart(description[start]) {
+			start++
+		}
+	}
+	if start+snippetLength < end {
+		end = start + snippetLength
+		if i := strings.LastIndexAny(description[first:end], " \n"); i > 0 {
+			end = first + i
+		}
+		for end > first && !utf8.RuneStart(description[end]) {
+			end--
+		}
+	}
+
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "..."
+	}
+	if end < len(description) {
+		suffix = "..."
+	}
+	window := description[start:end]
+	m := TextMatch{Snippet: prefix + strings.ReplaceAll(window, "\n", " ") + suffix}
+
+	var ranges [][2]int
+	for _, p := range patterns {
+		for _, loc := range p.FindAllStringIndex(window, -1) {
+			if loc[0] == loc[1] {
+				continue // Empty match of a pattern such as "x*"
+			}
+			ranges = append(ranges, [2]int{loc[0] + len(prefix), loc[1] + len(prefix)})
+		}
+	}
+	m.Highlights = mergeRanges(ranges)
+	return m
+}
+
+// mergeRanges sorts ranges and merges overlapping or adjacent ones
+func mergeRanges(ranges [][2]int) [][2]int {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	merged := [][2]int{}
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r[0] <= merged[n-1][1] {
+			if r[1] > merged[n-1][1] {
+				merged[n-1][1] = r[1]
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// describedItem is a schema element with a non-empty description
+type describedItem struct {
+	Coordinate  string
+	Kind        string
+	Name        string
+	Path        string
+	Description string
+}
+
+// walkDescriptions calls fn for every described element of an introspection
+// document, in document order. Paths index the raw document, so they stay
+// valid even for documents with duplicate or malformed entries.
+func walkDescriptions(data interface{}, fn func(describedItem)) {
+	root, _ := data.(map[string]interface{})
+	d, _ := root["data"].(map[string]interface{})
+	schema, _ := d["__schema"].(map[string]interface{})
+
+	visit := func(entry map[string]interface{}, coordinate, kind, path string) {
+		description, _ := entry["description"].(string)
+		if description == "" {
+			return
+		}
+		name, _ := entry["name"].(string)
+		fn(describedItem{Coordinate: coordinate, Kind: kind, Name: name, Path: path + ".description", Description: description})
+	}
+	each := func(list interface{}, path string, f func(entry map[string]interface{}, name, path string)) {
+		items, _ := list.([]interface{})
+		for i, item := range items {
+			if entry, ok := item.(map[string]interface{}); ok {
+				name, _ := entry["name"].(string)
+				f(entry, name, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	}
+	args := func(entry map[string]interface{}, owner, path string) {
+		each(entry["args"], path+".args", func(arg map[string]interface{}, name, path string) {
+			visit(arg, owner+"("+name+":)", "argument", path)
+		})
+	}
+
+	each(schema["types"], "$.data.__schema.types", func(t map[string]interface{}, typeName, path string) {
+		visit(t, typeName, "type", path)
+		each(t["fields"], path+".fields", func(f map[string]interface{}, name, path string) {
+			visit(f, typeName+"."+name, "field", path)
+			args(f, typeName+"."+name, path)
+		})
+		each(t["inputFields"], path+".inputFields", func(f map[string]interface{}, name, path string) {
+			visit(f, typeName+"."+name, "inputField", path)
+		})
+		each(t["enumValues"], path+".enumValues", func(v map[string]interface{}, name, path string) {
+			visit(v, typeName+"."+name, "enumValue", path)
+		})
+	})
+	each(schema["directives"], "$.data.__schema.directives", func(dir map[string]interface{}, name, path string) {
+		visit(dir, "@"+name, "directive", path)
+		args(dir, "@"+name, path)
+	})
+}
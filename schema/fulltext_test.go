@@ -0,0 +1,203 @@
+package schema
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+func TestSearchText(t *testing.T) {
+	s := loadRichSchema(t)
+
+	matches, err := s.SearchText("Pagination cursor", 0)
+	if err != nil {
+		t.Fatalf("SearchText failed: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match with both terms, got %+v", matches)
+	}
+	m := matches[0]
+	if m.Coordinate != "IssueEdge.cursor" || m.Kind != "field" {
+		t.Errorf("Unexpected match: %+v", m)
+	}
+	if got := m.Highlight("**", "**"); got != "A **cursor** for use in **pagination**." {
+		t.Errorf("Unexpected highlighted snippet: %q", got)
+	}
+
+	// Paths are jq paths into the document, so they resolve to the description
+	for _, text := range []string{`"specified cursor"`, "issue"} {
+		matches, err := s.SearchText(text, 0)
+		if err != nil {
+			t.Fatalf("SearchText(%q) failed: %v", text, err)
+		}
+		if len(matches) == 0 {
+			t.Fatalf("Expected matches for %q", text)
+		}
+		for _, m := range matches {
+			got, err := s.Query(strings.TrimPrefix(m.Path, "$"), nil)
+			if err != nil {
+				t.Fatalf("Path %s does not resolve: %v", m.Path, err)
+			}
+			if description, _ := got.(string); !strings.Contains(strings.ReplaceAll(description, "\n", " "), strings.Trim(m.Snippet, ".")) {
+				t.Errorf("Snippet %q of %s is not part of %q", m.Snippet, m.Coordinate, got)
+			}
+		}
+	}
+
+	matches, err = s.SearchText(`"specified cursor"`, 2)
+	if err != nil {
+		t.Fatalf("SearchText failed: %v", err)
+	}
+	if len(matches) != 2 || matches[0].Coordinate != "Query.search(after:)" || matches[0].Kind != "argument" {
+		t.Errorf("Expected the first 2 argument matches in document order, got %+v", matches)
+	}
+
+	if _, err := s.SearchText(`  "" `, 0); err == nil {
+		t.Error("Expected error for empty search text")
+	}
+}
+
+func TestSearchTextRanking(t *testing.T) {
+	s := loadRichSchema(t)
+
+	matches, err := s.SearchText("issue", 0)
+	if err != nil {
+		t.Fatalf("SearchText failed: %v", err)
+	}
+	for i := 1; i < len(matches); i++ {
+		if matches[i].Score > matches[i-1].Score {
+			t.Fatalf("Matches not ranked by score: %+v", matches)
+		}
+	}
+	// Matching the member name as well outranks a description-only match
+	if top := matches[0]; !strings.Contains(strings.ToLower(top.Coordinate), "issue") {
+		t.Errorf("Expected a member named after the term first, got %+v", top)
+	}
+}
+
+func TestSearchDescriptions(t *testing.T) {
+	s := loadRichSchema(t)
+
+	matches, err := s.SearchDescriptions(`(after|before) the specified`)
+	if err != nil {
+		t.Fatalf("SearchDescriptions failed: %v", err)
+	}
+	var coordinates []string
+	for _, m := range matches {
+		coordinates = append(coordinates, m.Coordinate)
+		if m.Kind != "argument" || len(m.Highlights) != 1 || m.Score != 0 {
+			t.Errorf("Unexpected match: %+v", m)
+		}
+	}
+	want := []string{
+		"Query.search(after:)", "Query.search(before:)",
+		"Repository.issues(after:)", "Repository.issues(before:)",
+		"User.issues(after:)", "User.issues(before:)",
+	}
+	if !reflect.DeepEqual(coordinates, want) {
+		t.Errorf("Expected matches in schema order %v, got %v", want, coordinates)
+	}
+
+	// Descriptions of members other than types, unlike Search
+	matches, err = s.SearchDescriptions("AUTHENTICATED user")
+	if err != nil {
+		t.Fatalf("SearchDescriptions failed: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Coordinate != "Query.viewer" || matches[0].Highlight("[", "]") != "The currently [authenticated user]." {
+		t.Errorf("Unexpected matches: %+v", matches)
+	}
+
+	if matches, err := s.SearchDescriptions("no such phrase"); err != nil || len(matches) != 0 {
+		t.Errorf("Expected no matches, got %+v (err: %v)", matches, err)
+	}
+	if _, err := s.SearchDescriptions("("); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	description := strings.Repeat("lorem ipsum ", 20) + "the needle is here " + strings.Repeat("dolor sit amet ", 30)
+	m := snippet(description, searchPatterns(t, "needle"))
+
+	if !strings.HasPrefix(m.Snippet, "...") || !strings.HasSuffix(m.Snippet, "...") {
+		t.Errorf("Expected ellipses on both ends: %q", m.Snippet)
+	}
+	if len(m.Snippet) > snippetLength+6 {
+		t.Errorf("Snippet too long (%d bytes): %q", len(m.Snippet), m.Snippet)
+	}
+	if len(m.Highlights) != 1 || m.Snippet[m.Highlights[0][0]:m.Highlights[0][1]] != "needle" {
+		t.Errorf("Unexpected highlights %v in %q", m.Highlights, m.Snippet)
+	}
+	// Cuts fall on word boundaries
+	inner := strings.TrimSuffix(strings.TrimPrefix(m.Snippet, "..."), "...")
+	if strings.HasPrefix(inner, " ") || !strings.HasPrefix(inner, "lorem") && !strings.HasPrefix(inner, "ipsum") {
+		t.Errorf("Snippet does not start at a word: %q", m.Snippet)
+	}
+
+	// Overlapping terms merge into one highlight
+	m = snippet("needles", searchPatterns(t, "needle les"))
+	if len(m.Highlights) != 1 || m.Highlights[0] != [2]int{0, 7} {
+		t.Errorf("Expected merged highlight, got %v", m.Highlights)
+	}
+}
+
+func searchPatterns(t *testing.T, text string) []*regexp.Regexp {
+	t.Helper()
+	var patterns []*regexp.Regexp
+	for _, term := range searchTerms(text) {
+		patterns = append(patterns, regexp.MustCompile("(?i)"+regexp.QuoteMeta(term)))
+	}
+	return patterns
+}
+
+func TestSearchTextHandler(t *testing.T) {
+	server := httptest.NewServer(SearchTextHandler(loadRichSchema(t)))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/search/fulltext?q=pagination+cursor&highlight=**")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		Count   int         `json:"count"`
+		Text    string      `json:"text"`
+		Matches []TextMatch `json:"matches"`
+	}
+	if err := yamlformat.Unmarshal(body, &result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected response %s %q", resp.Status, resp.Header.Get("Content-Type"))
+	}
+	if result.Count != 1 || result.Text != "pagination cursor" || result.Matches[0].Snippet != "A **cursor** for use in **pagination**." {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	for _, tt := range []struct {
+		method, query string
+		status        int
+	}{
+		{http.MethodGet, "", http.StatusBadRequest},
+		{http.MethodGet, "q=cursor&limit=x", http.StatusBadRequest},
+		{http.MethodGet, "q=nosuchterm", http.StatusOK},
+		{http.MethodPost, "q=cursor", http.StatusMethodNotAllowed},
+	} {
+		req := httptest.NewRequest(tt.method, "/search/fulltext?"+tt.query, nil)
+		rec := httptest.NewRecorder()
+		SearchTextHandler(loadRichSchema(t)).ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s ?%s = %d, want %d", tt.method, tt.query, rec.Code, tt.status)
+		}
+	}
+}
@@ -1,5 +1,8 @@
 package schema
 
-// This file contains the go:generate directive to update the embedded schema
+// This file contains the go:generate directives to update the embedded schema,
+// its provenance, and the sample cut from it, see EmbeddedInfo and NewSample
 
-//go:generate go run ../cmd/github-schema/main.go download --compress -o schema.json.gz
\ No newline at end of file
+//go:generate go run ../cmd/github-schema download --compress=zstd --if-changed --unchanged-exit-code 0 -o schema.json.zst
+//go:generate go run ./internal/embedinfo -schema schema.json.zst -o embedded_info.go
+//go:generate go run ./internal/gensample -schema schema.json.zst -o sample.json
\ No newline at end of file
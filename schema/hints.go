@@ -0,0 +1,190 @@
+package schema
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ValueHints are the example values and constraints GitHub states in the
+// description of a field, argument, or input field, such as "For example
+// `refs/heads/branch-name`", "Limit: 10", or "An ISO-8601 encoded date
+// string", for tools that generate realistic values or document inputs
+type ValueHints struct {
+	Examples []string `json:"examples,omitempty"`
+	Format   string   `json:"format,omitempty"`  // Such as "ISO 8601" or "RFC 3986, RFC 3987, RFC 6570"
+	Minimum  string   `json:"minimum,omitempty"` // A number or date as written, such as "1" or "1970-01-01"
+	Maximum  string   `json:"maximum,omitempty"`
+}
+
+// Empty reports whether the description stated no hints
+func (h ValueHints) Empty() bool {
+	return len(h.Examples) == 0 && h.Format == "" && h.Minimum == "" && h.Maximum == ""
+}
+
+// MemberHints are the hints of a field, argument, or input field, see
+// Schema.TypeHints
+type MemberHints struct {
+	Coordinate string     `json:"coordinate"` // Such as "Repository.issues(first:)"
+	Hints      ValueHints `json:"hints"`
+}
+
+var (
+	// exampleIntro starts a list of examples, which runs to the end of the
+	// parenthesis or sentence
+	exampleIntro = regexp.MustCompile(`(?i)\b(?:e\.g\.|ie:|for example|one of)[,:]?\s+`)
+	exampleEnd   = regexp.MustCompile(`\)|\.(?:\s|$)`)
+	// exampleCode matches examples written as code or in single quotes
+	exampleCode      = regexp.MustCompile("`([^`]+)`|'([^'\\s]+)'")
+	exampleSeparator = regexp.MustCompile(`,\s*|\s+(?:or|and)\s+`)
+
+	hintNumber   = `(\d+(?:,\d{3})*)`
+	hintBound    = `(\d{4}-\d{2}-\d{2}|-?\d+(?:,\d{3})*(?:\.\d+)?)`
+	maximumHint  = regexp.MustCompile(`(?i)\b(?:limit:|up to|at most|no more than|maximum of|cannot exceed|must not exceed)\s+` + hintNumber + `\b`)
+	minimumHint  = regexp.MustCompile(`(?i)\b(?:at least|minimum of)\s+` + hintNumber + `\b`)
+	betweenHint  = regexp.MustCompile(`(?i)\bbetween\s+` + hintBound + `\s*(?:-|and|to)\s*` + hintBound)
+	isoFormat    = regexp.MustCompile(`\bISO[- ]?8601\b`)
+	rfcFormat    = regexp.MustCompile(`\bRFC ?(\d+)\b`)
+	epochFormat  = regexp.MustCompile(`(?i)\bepoch seconds\b`)
+	base64Format = regexp.MustCompile(`(?i)\bencoded using base64\b`)
+	x509Format   = regexp.MustCompile(`(?i)\bx\.?509\b`)
+)
+
+// ParseValueHints extracts the hints a description states: examples
+// introduced by "e.g.", "ie:", "for example", or "one of", as code spans or as a
+// list of single words; bounds such as "Limit: 10", "a maximum of 1,000",
+// "at least 1", or "between 0-100"; and formats such as ISO 8601 dates, RFCs,
+// epoch seconds, Base64, and X.509. Explanations such as "e.g., you are away"
+// are not examples and are left out.
+func ParseValueHints(description string) ValueHints {
+	var h ValueHints
+	for _, loc := range exampleIntro.FindAllStringIndex(description, -1) {
+		h.Examples = appendExamples(h.Examples, description[loc[1]:])
+	}
+
+	if m := betweenHint.FindStringSubmatch(description); m != nil {
+		h.Minimum, h.Maximum = hintValue(m[1]), hintValue(m[2])
+	}
+	if m := maximumHint.FindStringSubmatch(description); m != nil {
+		h.Maximum = hintValue(m[1])
+	}
+	if m := minimumHint.FindStringSubmatch(description); m != nil {
+		h.Minimum = hintValue(m[1])
+	}
+
+	var formats []string
+	if isoFormat.MatchString(description) {
+		formats = append(formats, "ISO 8601")
+	}
+	for _, m := range rfcFormat.FindAllStringSubmatch(description, -1) {
+		formats = appendUnique(formats, "RFC "+m[1])
+	}
+	if epochFormat.MatchString(description) {
+		formats = append(formats, "Unix epoch seconds")
+	}
+	if base64Format.MatchString(description) {
+		formats = append(formats, "Base64")
+	}
+	if x509Format.MatchString(description) {
+		formats = append(formats, "X.509")
+	}
+	h.Format = strings.Join(formats, ", ")
+	return h
+}
+
+// appendExamples appends the examples at the start of text, which follows an
+// example introduction
+func appendExamples(examples []string, text string) []string {
+	if loc := exampleEnd.FindStringIndex(text); loc != nil {
+		text = text[:loc[0]]
+	}
+	if codes := exampleCode.FindAllStringSubmatch(text, -1); codes != nil {
+		for _, m := range codes {
+			examples = appendUnique(examples, m[1]+m[2])
+		}
+		return examples
+	}
+	items := exampleSeparator.Split(strings.TrimSpace(text), -1)
+	for _, item := range items {
+		if item == "" || strings.ContainsAny(item, " \t\n") {
+			return examples
+		}
+	}
+	for _, item := range items {
+		examples = appendUnique(examples, item)
+	}
+	return examples
+}
+
+// hintValue normalizes a bound, dropping thousands separators
+func hintValue(s string) string {
+	return strings.ReplaceAll(s, ",", "")
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// TypeHints returns the hints of the fields, arguments, and input fields of a
+// type that have any, in schema order. Members of scalar types without an
+// example or format of their own take those of the scalar, such as ISO 8601
+// for DateTime, and the first and last arguments of connections are bounded
+// by 1 and MaxPageSize, which GitHub enforces without stating it. Unknown
+// types fail with a *NotFoundError suggesting similar names.
+func (s *Schema) TypeHints(typeName string) ([]MemberHints, error) {
+	m := s.Model()
+	t := m.Type(typeName)
+	if t == nil {
+		return nil, m.typeNotFound(typeName)
+	}
+	hints := []MemberHints{}
+	add := func(coordinate, description string, ref *TypeRef, paged bool) {
+		h := m.valueHints(description, ref)
+		if paged {
+			if h.Minimum == "" {
+				h.Minimum = "1"
+			}
+			if h.Maximum == "" {
+				h.Maximum = strconv.Itoa(MaxPageSize)
+			}
+		}
+		if !h.Empty() {
+			hints = append(hints, MemberHints{Coordinate: coordinate, Hints: h})
+		}
+	}
+	for _, f := range t.Fields {
+		coordinate := t.Name + "." + f.Name
+		add(coordinate, f.Description, f.Type, false)
+		returns := m.Resolve(f.Type)
+		connection := returns != nil && isConnectionType(returns)
+		for _, arg := range f.Args {
+			paged := connection && (arg.Name == "first" || arg.Name == "last")
+			add(coordinate+"("+arg.Name+":)", arg.Description, arg.Type, paged)
+		}
+	}
+	for _, f := range t.InputFields {
+		add(t.Name+"."+f.Name, f.Description, f.Type, false)
+	}
+	return hints, nil
+}
+
+// valueHints parses description, falling back to the examples and format
+// of the scalar ref names
+func (m *Model) valueHints(description string, ref *TypeRef) ValueHints {
+	h := ParseValueHints(description)
+	if scalar := m.Resolve(ref); scalar != nil && scalar.Kind == "SCALAR" {
+		own := ParseValueHints(scalar.Description)
+		if len(h.Examples) == 0 {
+			h.Examples = own.Examples
+		}
+		if h.Format == "" {
+			h.Format = own.Format
+		}
+	}
+	return h
+}
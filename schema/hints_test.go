@@ -0,0 +1,80 @@
+package schema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseValueHints(t *testing.T) {
+	tests := []struct {
+		description string
+		want        ValueHints
+	}{
+		{"The fully qualified name of the ref to be update. For example `refs/heads/branch-name`", ValueHints{Examples: []string{"refs/heads/branch-name"}}},
+		{"The migration source URL, for example `https://github.com` or `https://monalisa.ghe.com`.", ValueHints{Examples: []string{"https://github.com", "https://monalisa.ghe.com"}}},
+		{"The type of invitation that was sent (e.g. email, user).", ValueHints{Examples: []string{"email", "user"}}},
+		{"Filter advisories by identifier, e.g. GHSA or CVE.", ValueHints{Examples: []string{"GHSA", "CVE"}}},
+		{"For example, 'Open-Source-Collective' for Open Source Collective or 'numfocus' for numFOCUS. Case insensitive.", ValueHints{Examples: []string{"Open-Source-Collective", "numfocus"}}},
+		{"Whether this status should indicate you are not fully available on GitHub, e.g., you are away.", ValueHints{}},
+		{"The fully qualified name of the new Ref (ie: `refs/heads/my_new_branch`).", ValueHints{Examples: []string{"refs/heads/my_new_branch"}}},
+		{"A list of teams being granted access. Limit: 10", ValueHints{Maximum: "10"}},
+		{"Perform a search across resources, returning a maximum of 1,000 results.", ValueHints{Maximum: "1000"}},
+		{"The percentage representing how complete this goal is, between 0-100.", ValueHints{Minimum: "0", Maximum: "100"}},
+		{"Unexpected results may be returned for dates not between 1970-01-01 and 2099-12-13 (inclusive).", ValueHints{Minimum: "1970-01-01", Maximum: "2099-12-13"}},
+		{"Int can represent values between -(2^31) and 2^31 - 1.", ValueHints{}},
+		{"An ISO-8601 encoded UTC date string.", ValueHints{Format: "ISO 8601"}},
+		{"An RFC 3986, RFC 3987, and RFC 6570 (level 4) compliant URI string.", ValueHints{Format: "RFC 3986, RFC 3987, RFC 6570"}},
+		{"The time at which the current rate limit window resets in UTC epoch seconds.", ValueHints{Format: "Unix epoch seconds"}},
+		{"Represents a unique identifier that is Base64 obfuscated.", ValueHints{}},
+	}
+	for _, tt := range tests {
+		if got := ParseValueHints(tt.description); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseValueHints(%q) = %+v, want %+v", tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestTypeHints(t *testing.T) {
+	s, err := NewWithData([]byte(`
+"An ISO-8601 encoded UTC date string."
+scalar DateTime
+
+type Query { repository: Repository }
+
+type Repository {
+  "When the repository was created"
+  createdAt: DateTime!
+  "The emoji of the repository, e.g., :rocket:."
+  emoji: String
+  issues(first: Int, last: Int, "Labels to filter by. Limit: 10" labels: [String!]): IssueConnection!
+  name: String!
+}
+
+type IssueConnection { nodes: [Issue] pageInfo: PageInfo! }
+type Issue { id: ID! }
+type PageInfo { hasNextPage: Boolean! }
+`))
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	hints, err := s.TypeHints("Repository")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MemberHints{
+		{Coordinate: "Repository.createdAt", Hints: ValueHints{Format: "ISO 8601"}},
+		{Coordinate: "Repository.emoji", Hints: ValueHints{Examples: []string{":rocket:"}}},
+		{Coordinate: "Repository.issues(first:)", Hints: ValueHints{Minimum: "1", Maximum: "100"}},
+		{Coordinate: "Repository.issues(last:)", Hints: ValueHints{Minimum: "1", Maximum: "100"}},
+		{Coordinate: "Repository.issues(labels:)", Hints: ValueHints{Maximum: "10"}},
+	}
+	if !reflect.DeepEqual(hints, want) {
+		t.Errorf("TypeHints() = %+v, want %+v", hints, want)
+	}
+
+	var notFound *NotFoundError
+	if _, err := s.TypeHints("Repo"); !errors.As(err, &notFound) {
+		t.Errorf("Expected a *NotFoundError, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package schema
+
+import "fmt"
+
+// InputObjectInfo is an input object type with its fields, as returned by
+// InputObject
+type InputObjectInfo struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	OneOf       bool               `json:"oneOf,omitempty"` // Exactly one field must be set (@oneOf)
+	Fields      []InputObjectField `json:"fields"`
+}
+
+// InputObjectField is a field of an input object. Input holds the expanded
+// input object for fields whose unwrapped type is itself an input object,
+// unless the depth limit was reached or the type is already being expanded
+// higher up the tree, in which case Recursive is set.
+type InputObjectField struct {
+	Name              string           `json:"name"`
+	Description       string           `json:"description"`
+	Type              string           `json:"type"`
+	Required          bool             `json:"required"` // Non-null without a default value
+	DefaultValue      string           `json:"defaultValue,omitempty"`
+	IsDeprecated      bool             `json:"isDeprecated,omitempty"`
+	DeprecationReason string           `json:"deprecationReason,omitempty"`
+	Input             *InputObjectInfo `json:"input,omitempty"`
+	Recursive         bool             `json:"recursive,omitempty"`
+}
+
+// InputObject returns an input object type with nested input objects
+// expanded into a tree. depth limits how many levels of nested input objects
+// are expanded: 0 returns only the fields of the named type, and a negative
+// depth expands everything. Self-referencing inputs stop at the first repeat.
+//
+// OneOf and input field deprecation are only reported for schemas introspected
+// with isOneOf and inputFields(includeDeprecated: true); the embedded schema
+// does not include them.
+func (s *Schema) InputObject(name string, depth int) (*InputObjectInfo, error) {
+	entry := s.rawType(name)
+	if entry == nil {
+		return nil, s.Model().typeNotFound(name)
+	}
+	if kind, _ := entry["kind"].(string); kind != "INPUT_OBJECT" {
+		return nil, fmt.Errorf("type %q is not an input object (kind: %s)", name, kind)
+	}
+	return s.expandInput(entry, depth, map[string]bool{}), nil
+}
+
+func (s *Schema) expandInput(entry map[string]interface{}, depth int, active map[string]bool) *InputObjectInfo {
+	info := &InputObjectInfo{Fields: []InputObjectField{}}
+	info.Name, _ = entry["name"].(string)
+	info.Description, _ = entry["description"].(string)
+	info.OneOf, _ = entry["isOneOf"].(bool)
+
+	active[info.Name] = true
+	defer delete(active, info.Name)
+
+	fields, _ := entry["inputFields"].([]interface{})
+	for _, f := range fields {
+		field, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ref := TypeRefFromMap(field["type"])
+		out := InputObjectField{Type: ref.String()}
+		out.Name, _ = field["name"].(string)
+		out.Description, _ = field["description"].(string)
+		out.DefaultValue, _ = field["defaultValue"].(string)
+		out.IsDeprecated, _ = field["isDeprecated"].(bool)
+		out.DeprecationReason, _ = field["deprecationReason"].(string)
+		out.Required = ref != nil && ref.Kind == "NON_NULL" && field["defaultValue"] == nil
+
+		named := ref.NamedType()
+		if nested := s.rawType(named); nested != nil && nested["kind"] == "INPUT_OBJECT" {
+			switch {
+			case active[named]:
+				out.Recursive = true
+			case depth != 0:
+				out.Input = s.expandInput(nested, depth-1, active)
+			}
+		}
+		info.Fields = append(info.Fields, out)
+	}
+	return info
+}
@@ -0,0 +1,50 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInputObject(t *testing.T) {
+	s := loadRichSchema(t)
+
+	info, err := s.InputObject("CreateIssueInput", -1)
+	if err != nil {
+		t.Fatalf("InputObject failed: %v", err)
+	}
+	if info.Name != "CreateIssueInput" || len(info.Fields) != 6 {
+		t.Fatalf("Unexpected input object: %+v", info)
+	}
+	title := info.Fields[1]
+	if title.Name != "title" || title.Type != "String!" || !title.Required || title.Input != nil {
+		t.Errorf("Unexpected title field: %+v", title)
+	}
+
+	metadata := info.Fields[4]
+	if metadata.Name != "metadata" || metadata.Required || metadata.Input == nil || metadata.Input.Name != "IssueMetadataInput" {
+		t.Fatalf("Expected metadata to expand into IssueMetadataInput, got %+v", metadata)
+	}
+	priority := metadata.Input.Fields[0]
+	if priority.DefaultValue != "3" || priority.Required {
+		t.Errorf("Unexpected priority field: %+v", priority)
+	}
+	related := metadata.Input.Fields[2]
+	if related.Type != "[IssueMetadataInput!]" || !related.Recursive || related.Input != nil {
+		t.Errorf("Expected related to stop at the recursive reference, got %+v", related)
+	}
+
+	shallow, err := s.InputObject("CreateIssueInput", 0)
+	if err != nil {
+		t.Fatalf("InputObject failed: %v", err)
+	}
+	if shallow.Fields[4].Input != nil || shallow.Fields[4].Recursive {
+		t.Errorf("Expected no expansion at depth 0, got %+v", shallow.Fields[4])
+	}
+
+	if _, err := s.InputObject("Issue", 1); err == nil || !strings.Contains(err.Error(), "not an input object") {
+		t.Errorf("Expected not an input object error, got %v", err)
+	}
+	if _, err := s.InputObject("NonExistent", 1); err == nil {
+		t.Error("Expected error for non-existent type")
+	}
+}
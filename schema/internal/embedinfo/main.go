@@ -0,0 +1,111 @@
+// Command embedinfo generates embedded_info.go, the provenance of the
+// embedded schema returned by schema.EmbeddedInfo. It runs after every
+// update of schema.json.zst:
+//
+//	go run ./internal/embedinfo -schema schema.json.zst -o embedded_info.go
+//
+// The capture date, endpoint, and GitHub Enterprise Server version are taken
+// from the metadata recorded by the download, or from the flags for schemas
+// without it. Without metadata or -captured-at, the capture date is the time
+// the schema file was last committed, an upper bound that still tells how old
+// the snapshot is. The query options come from the metadata too; without it,
+// a schema description, even null, tells IntrospectionQuery from the legacy
+// query.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+func main() {
+	schemaPath := flag.String("schema", "schema.json.zst", "Embedded schema file")
+	output := flag.String("o", "embedded_info.go", "Output file")
+	capturedAt := flag.String("captured-at", "", "Capture time (RFC 3339) for schemas without download metadata, by default the last commit of the schema file")
+	endpoint := flag.String("endpoint", schema.GitHubAPIURL, "Endpoint for schemas without download metadata")
+	flag.Parse()
+
+	s, err := schema.NewWithFileStrict(*schemaPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	doc, err := s.Introspection()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	info := schema.SnapshotInfo{
+		Endpoint:    *endpoint,
+		Options:     schema.LegacyQueryOptions,
+		Fingerprint: s.Fingerprint(),
+	}
+	if doc.Data.Schema.Description.Present {
+		info.Options = schema.QueryOptions
+	}
+	if *capturedAt != "" {
+		if info.CapturedAt, err = time.Parse(time.RFC3339, *capturedAt); err != nil {
+			log.Fatalf("invalid -captured-at: %v", err)
+		}
+	}
+	if meta := s.Metadata(); meta != nil {
+		info.CapturedAt, info.Endpoint, info.GHESVersion = meta.DownloadedAt, meta.Endpoint, meta.GHESVersion
+		info.Options = meta.Options()
+	} else if info.CapturedAt.IsZero() {
+		if info.CapturedAt, err = lastCommitTime(*schemaPath); err != nil {
+			log.Fatalf("no capture time for a schema without download metadata, pass -captured-at: %v", err)
+		}
+	}
+
+	src, err := format.Source(generate(info))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// lastCommitTime returns the time path was last committed to git
+func lastCommitTime(path string) (time.Time, error) {
+	out, err := exec.Command("git", "log", "-1", "--format=%cI", "--", path).Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to run git log: %w", err)
+	}
+	committed := strings.TrimSpace(string(out))
+	if committed == "" {
+		return time.Time{}, fmt.Errorf("%s is not committed", path)
+	}
+	return time.Parse(time.RFC3339, committed)
+}
+
+func generate(info schema.SnapshotInfo) []byte {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by internal/embedinfo; DO NOT EDIT.\n\npackage schema\n\n")
+	if !info.CapturedAt.IsZero() {
+		b.WriteString("import \"time\"\n\n")
+	}
+	b.WriteString("var embeddedInfo = SnapshotInfo{\n")
+	if !info.CapturedAt.IsZero() {
+		t := info.CapturedAt.UTC()
+		fmt.Fprintf(&b, "CapturedAt: time.Date(%d, %d, %d, %d, %d, %d, 0, time.UTC),\n", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+	}
+	fmt.Fprintf(&b, "Endpoint: %q,\n", info.Endpoint)
+	if info.GHESVersion != "" {
+		fmt.Fprintf(&b, "GHESVersion: %q,\n", info.GHESVersion)
+	}
+	o := info.Options
+	fmt.Fprintf(&b, "Options: IntrospectionOptions{DeprecatedFields: %t, DeprecatedEnumValues: %t, DeprecatedInputValues: %t, TypeRefDepth: %d, SchemaDescription: %t, SpecifiedByURL: %t, RepeatableDirectives: %t},\n",
+		o.DeprecatedFields, o.DeprecatedEnumValues, o.DeprecatedInputValues, o.TypeRefDepth, o.SchemaDescription, o.SpecifiedByURL, o.RepeatableDirectives)
+	fmt.Fprintf(&b, "Fingerprint: %q,\n", info.Fingerprint)
+	b.WriteString("}\n")
+	return b.Bytes()
+}
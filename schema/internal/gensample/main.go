@@ -0,0 +1,190 @@
+// Command gensample generates sample.json, the subset of the embedded schema
+// returned by schema.NewSample. It runs after every update of
+// schema.json.zst:
+//
+//	go run ./internal/gensample -schema schema.json.zst -o sample.json
+//
+// The object, interface, and union types listed in sampleFields are copied
+// with the listed fields, which keep all their arguments; interfaces and
+// possible types are narrowed to the copied types. The scalars, enums, and
+// input objects the copied members reference are copied whole, as are the
+// directives. Definitions are taken verbatim, so every type, field, and enum
+// value of the sample exists in the GitHub schema with the same type.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/apstndb/github-schema-go/schema/introspection"
+)
+
+// sampleFields lists the output types of the sample with the fields to copy.
+// Unions have no fields. Every output type a copied field returns must be
+// listed.
+var sampleFields = map[string][]string{
+	"Query":                      {"repository", "node", "viewer", "search"},
+	"Mutation":                   {"createIssue", "addStar"},
+	"Node":                       {"id"},
+	"Actor":                      {"login"},
+	"RepositoryOwner":            {"id", "login"},
+	"Starrable":                  {"id", "stargazerCount"},
+	"Repository":                 {"id", "name", "owner", "issues", "issueOrPullRequest", "hasIssuesEnabled", "stargazerCount", "squashPrTitleUsedAsDefault"},
+	"IssueConnection":            {"edges", "nodes", "pageInfo", "totalCount"},
+	"IssueEdge":                  {"cursor", "node"},
+	"PageInfo":                   {"endCursor", "hasNextPage"},
+	"Issue":                      {"id", "number", "title", "body", "state", "author", "repository"},
+	"PullRequest":                {"id", "number", "title", "merged"},
+	"User":                       {"id", "login", "email", "issues"},
+	"IssueOrPullRequest":         nil,
+	"SearchResultItem":           nil,
+	"SearchResultItemConnection": {"nodes", "pageInfo", "issueCount"},
+	"CreateIssuePayload":         {"clientMutationId", "issue"},
+	"AddStarPayload":             {"clientMutationId", "starrable"},
+}
+
+func main() {
+	schemaPath := flag.String("schema", "schema.json.zst", "Embedded schema file")
+	output := flag.String("o", "sample.json", "Output file")
+	flag.Parse()
+
+	s, err := schema.NewWithFile(*schemaPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	doc, err := s.Introspection()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := subset(&doc.Data.Schema); err != nil {
+		log.Fatal(err)
+	}
+	doc.Extensions = nil
+
+	data, err := introspection.Marshal(doc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Indented, so that changes to the sample can be reviewed
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		log.Fatal(err)
+	}
+	out.WriteByte('\n')
+	if err := os.WriteFile(*output, out.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// subset narrows s to the types of sampleFields and the types they
+// reference, keeping the order of s
+func subset(s *introspection.Schema) error {
+	byName := make(map[string]*introspection.Type, len(s.Types))
+	for i := range s.Types {
+		byName[s.Types[i].Name] = &s.Types[i]
+	}
+
+	keep := make(map[string]bool)
+	var pending []string
+	reference := func(ref *introspection.TypeRef) error {
+		name := ref.NamedType()
+		t := byName[name]
+		switch {
+		case t == nil:
+			return fmt.Errorf("type %q not found", name)
+		case t.Kind == "OBJECT" || t.Kind == "INTERFACE" || t.Kind == "UNION":
+			if _, ok := sampleFields[name]; !ok {
+				return fmt.Errorf("output type %q is referenced but not listed in sampleFields", name)
+			}
+		case !keep[name]:
+			keep[name] = true
+			pending = append(pending, name)
+		}
+		return nil
+	}
+
+	for name, fields := range sampleFields {
+		t := byName[name]
+		if t == nil {
+			return fmt.Errorf("type %q not found", name)
+		}
+		keep[name] = true
+		listed := make(map[string]bool, len(fields))
+		for _, fieldName := range fields {
+			f := t.Field(fieldName)
+			if f == nil {
+				return fmt.Errorf("field %q not found on %q", fieldName, name)
+			}
+			if err := reference(&f.Type); err != nil {
+				return err
+			}
+			for i := range f.Args {
+				if err := reference(&f.Args[i].Type); err != nil {
+					return err
+				}
+			}
+			listed[fieldName] = true
+		}
+		if t.Fields != nil {
+			kept := []introspection.Field{}
+			for _, f := range t.Fields {
+				if listed[f.Name] {
+					kept = append(kept, f)
+				}
+			}
+			t.Fields = kept
+		}
+	}
+	for i := range s.Directives {
+		for j := range s.Directives[i].Args {
+			if err := reference(&s.Directives[i].Args[j].Type); err != nil {
+				return err
+			}
+		}
+	}
+	// Input objects reference further input objects, enums, and scalars
+	for len(pending) > 0 {
+		t := byName[pending[0]]
+		pending = pending[1:]
+		for i := range t.InputFields {
+			if err := reference(&t.InputFields[i].Type); err != nil {
+				return err
+			}
+		}
+	}
+
+	types := s.Types[:0]
+	for _, t := range s.Types {
+		if !keep[t.Name] {
+			continue
+		}
+		if t.Interfaces != nil {
+			t.Interfaces = keptRefs(t.Interfaces, keep)
+		}
+		if t.PossibleTypes != nil {
+			t.PossibleTypes = keptRefs(t.PossibleTypes, keep)
+		}
+		types = append(types, t)
+	}
+	s.Types = types
+	if s.SubscriptionType != nil && !keep[s.SubscriptionType.Name] {
+		s.SubscriptionType = nil
+	}
+	return nil
+}
+
+// keptRefs returns the references of refs to kept types
+func keptRefs(refs []introspection.TypeRef, keep map[string]bool) []introspection.TypeRef {
+	kept := []introspection.TypeRef{}
+	for _, ref := range refs {
+		if keep[ref.NamedType()] {
+			kept = append(kept, ref)
+		}
+	}
+	return kept
+}
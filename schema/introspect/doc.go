@@ -0,0 +1,20 @@
+// Package introspect runs the GraphQL introspection query against an
+// endpoint such as GitHub's GraphQL API. It is the HTTP layer of the schema
+// download functions, usable on its own by tools that fetch schemas from
+// other GraphQL servers or test against httptest servers:
+//
+//	doc, err := introspect.Introspect(ctx, "https://api.github.com/graphql",
+//		introspect.WithToken(token))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	s, err := schema.NewWithData(doc.Body)
+//
+// Responses are requested gzip-compressed and decompressed here, whatever the
+// settings of the HTTP client's transport. Unsuccessful HTTP statuses fail
+// with a *StatusError. WithRetry retries network errors, server errors, and
+// rate limits with backoff. Query asks for the members added in recent
+// versions of the specification; send LegacyQuery with WithQuery to servers
+// that reject them. Fetch downloads a file such as a published SDL the same
+// way.
+package introspect
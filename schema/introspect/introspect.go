@@ -0,0 +1,449 @@
+package introspect
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+// Query is the standard introspection query, with deprecated fields, enum
+// values, arguments, and input fields included, the schema description,
+// scalar specifiedByURL, and directive isRepeatable, and type references
+// resolved eight levels deep
+const Query = `
+	{
+	  __schema {
+	    description
+	    queryType { name }
+	    mutationType { name }
+	    subscriptionType { name }
+	    types {
+	      ...FullType
+	    }
+	    directives {
+	      name
+	      description
+	      isRepeatable
+	      locations
+	      args(includeDeprecated: true) {
+	        ...InputValue
+	      }
+	    }
+	  }
+	}
+	
+	fragment FullType on __Type {
+	  kind
+	  name
+	  description
+	  specifiedByURL
+	  fields(includeDeprecated: true) {
+	    name
+	    description
+	    args(includeDeprecated: true) {
+	      ...InputValue
+	    }
+	    type {
+	      ...TypeRef
+	    }
+	    isDeprecated
+	    deprecationReason
+	  }
+	  inputFields(includeDeprecated: true) {
+	    ...InputValue
+	  }
+	  interfaces {
+	    ...TypeRef
+	  }
+	  enumValues(includeDeprecated: true) {
+	    name
+	    description
+	    isDeprecated
+	    deprecationReason
+	  }
+	  possibleTypes {
+	    ...TypeRef
+	  }
+	}
+	
+	fragment InputValue on __InputValue {
+	  name
+	  description
+	  type { ...TypeRef }
+	  defaultValue
+	  isDeprecated
+	  deprecationReason
+	}
+	
+	fragment TypeRef on __Type {
+	  kind
+	  name
+	  ofType {
+	    kind
+	    name
+	    ofType {
+	      kind
+	      name
+	      ofType {
+	        kind
+	        name
+	        ofType {
+	          kind
+	          name
+	          ofType {
+	            kind
+	            name
+	            ofType {
+	              kind
+	              name
+	              ofType {
+	                kind
+	                name
+	              }
+	            }
+	          }
+	        }
+	      }
+	    }
+	  }
+	}`
+
+// LegacyQuery is the introspection query used before Query asked for the
+// newer members of the specification, for servers that reject them
+const LegacyQuery = `
+	{
+	  __schema {
+	    queryType { name }
+	    mutationType { name }
+	    subscriptionType { name }
+	    types {
+	      ...FullType
+	    }
+	    directives {
+	      name
+	      description
+	      locations
+	      args {
+	        ...InputValue
+	      }
+	    }
+	  }
+	}
+	
+	fragment FullType on __Type {
+	  kind
+	  name
+	  description
+	  fields(includeDeprecated: true) {
+	    name
+	    description
+	    args {
+	      ...InputValue
+	    }
+	    type {
+	      ...TypeRef
+	    }
+	    isDeprecated
+	    deprecationReason
+	  }
+	  inputFields {
+	    ...InputValue
+	  }
+	  interfaces {
+	    ...TypeRef
+	  }
+	  enumValues(includeDeprecated: true) {
+	    name
+	    description
+	    isDeprecated
+	    deprecationReason
+	  }
+	  possibleTypes {
+	    ...TypeRef
+	  }
+	}
+	
+	fragment InputValue on __InputValue {
+	  name
+	  description
+	  type { ...TypeRef }
+	  defaultValue
+	}
+	
+	fragment TypeRef on __Type {
+	  kind
+	  name
+	  ofType {
+	    kind
+	    name
+	    ofType {
+	      kind
+	      name
+	      ofType {
+	        kind
+	        name
+	        ofType {
+	          kind
+	          name
+	          ofType {
+	            kind
+	            name
+	            ofType {
+	              kind
+	              name
+	              ofType {
+	                kind
+	                name
+	              }
+	            }
+	          }
+	        }
+	      }
+	    }
+	  }
+	}`
+
+// Document is an introspection response as received
+type Document struct {
+	Body        []byte    // Uncompressed response body: {"data": {"__schema": {...}}}, or the file for Fetch
+	Endpoint    string    // Endpoint the query was sent to
+	GHESVersion string    // GitHub Enterprise Server version, empty for github.com and other servers
+	ReceivedAt  time.Time // When the response was received, in UTC
+	ETag        string    // Entity tag of the response, for WithIfNoneMatch; empty when the server sends none
+}
+
+// ErrNotModified is returned when the server answers a request made with
+// WithIfNoneMatch with HTTP 304: the schema is unchanged
+var ErrNotModified = errors.New("not modified")
+
+// StatusError reports an unsuccessful HTTP status. RateLimited is set for
+// responses to exceeding a primary or secondary rate limit, with RetryAfter
+// the wait the server asked for, if any.
+type StatusError struct {
+	StatusCode  int
+	Endpoint    string
+	RateLimited bool
+	RetryAfter  time.Duration
+}
+
+func (e *StatusError) Error() string {
+	if e.RateLimited {
+		return fmt.Sprintf("%s returned HTTP %d: rate limit exceeded", e.Endpoint, e.StatusCode)
+	}
+	return fmt.Sprintf("%s returned HTTP %d", e.Endpoint, e.StatusCode)
+}
+
+// Temporary reports whether the request may succeed when retried: for
+// server errors and rate limits
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode >= 500 || e.RateLimited
+}
+
+// Option customizes an introspection request
+type Option func(*options)
+
+type options struct {
+	token    string
+	client   *http.Client
+	query    string
+	retry    RetryPolicy
+	etag     string
+	progress func(received, total int64)
+}
+
+// WithToken sends token as a bearer token. Without it the request is anonymous.
+func WithToken(token string) Option {
+	return func(o *options) { o.token = token }
+}
+
+// WithHTTPClient sends the request with client instead of http.DefaultClient
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) { o.client = client }
+}
+
+// WithTransport sends the request through rt, such as an instrumenting or
+// recording transport. It replaces any client given with WithHTTPClient.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *options) { o.client = &http.Client{Transport: rt} }
+}
+
+// WithIfNoneMatch makes the request conditional on the response differing
+// from the one with the given entity tag, see Document.ETag. Introspect fails
+// with ErrNotModified when it does not. An empty etag is ignored.
+func WithIfNoneMatch(etag string) Option {
+	return func(o *options) { o.etag = etag }
+}
+
+// WithProgress calls progress while the response body arrives, with the
+// bytes received so far and the length the server announced, or -1 when it
+// announced none. Both count the body as sent, which is compressed when the
+// server compresses it. progress is called for every read from the network,
+// so it should be cheap; it starts over from zero for retried requests.
+func WithProgress(progress func(received, total int64)) Option {
+	return func(o *options) { o.progress = progress }
+}
+
+// WithQuery sends query instead of Query, for servers that reject parts of
+// the standard query
+func WithQuery(query string) Option {
+	return func(o *options) { o.query = query }
+}
+
+// Introspect sends the introspection query to endpoint and returns the
+// response. GraphQL errors in the response body are left to the caller.
+// Failed requests are retried as WithRetry configures; by default they are
+// not.
+func Introspect(ctx context.Context, endpoint string, opts ...Option) (*Document, error) {
+	o := options{query: Query}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	jsonBody, err := yamlformat.MarshalJSON(map[string]string{
+		"query": o.query,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	return o.retrying(ctx, func() (*Document, error) {
+		return o.send(ctx, "POST", endpoint, jsonBody)
+	})
+}
+
+// Fetch downloads the file at url, such as the GraphQL SDL GitHub publishes
+// with its documentation, with the same compression, conditional request,
+// progress, and retry handling as Introspect. The token is only sent when
+// WithToken gives one; WithQuery is ignored.
+func Fetch(ctx context.Context, url string, opts ...Option) (*Document, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o.retrying(ctx, func() (*Document, error) {
+		return o.send(ctx, "GET", url, nil)
+	})
+}
+
+// retrying calls send until it succeeds or fails permanently, as the retry
+// policy of o says
+func (o *options) retrying(ctx context.Context, send func() (*Document, error)) (*Document, error) {
+	for attempt := 1; ; attempt++ {
+		doc, err := send()
+		if err == nil {
+			return doc, nil
+		}
+		if !retryable(ctx, err) || attempt >= o.retry.MaxAttempts {
+			if attempt > 1 {
+				err = fmt.Errorf("%w (after %d attempts)", err, attempt)
+			}
+			return nil, err
+		}
+		delay, ok := o.retry.delay(attempt, err)
+		if !ok {
+			return nil, err
+		}
+		if o.retry.OnRetry != nil {
+			o.retry.OnRetry(attempt, delay, err)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("failed to execute request: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// send makes a single request, an introspection query when jsonBody is
+// given
+func (o *options) send(ctx context.Context, method, endpoint string, jsonBody []byte) (*Document, error) {
+	var reqBody io.Reader
+	if jsonBody != nil {
+		reqBody = bytes.NewReader(jsonBody)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if o.token != "" {
+		req.Header.Set("Authorization", "bearer "+o.token)
+	}
+	if jsonBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	// Setting the header explicitly keeps any transport from decompressing
+	// transparently, so the compression is handled here
+	req.Header.Set("Accept-Encoding", "gzip")
+	if o.etag != "" {
+		req.Header.Set("If-None-Match", o.etag)
+	}
+
+	// The default client honors the proxy environment variables
+	client := o.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &transientError{fmt.Errorf("failed to execute request: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && o.etag != "" {
+		return nil, ErrNotModified
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, endpoint, time.Now())
+	}
+
+	var reader io.Reader = resp.Body
+	if o.progress != nil {
+		reader = &progressReader{r: resp.Body, total: resp.ContentLength, progress: o.progress}
+	}
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, &transientError{fmt.Errorf("failed to decompress response: %w", err)}
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, &transientError{fmt.Errorf("failed to read response: %w", err)}
+	}
+
+	return &Document{
+		Body:        body,
+		Endpoint:    endpoint,
+		GHESVersion: resp.Header.Get("X-GitHub-Enterprise-Version"),
+		ReceivedAt:  time.Now().UTC(),
+		ETag:        resp.Header.Get("ETag"),
+	}, nil
+}
+
+// progressReader reports the bytes read through it
+type progressReader struct {
+	r        io.Reader
+	received int64
+	total    int64
+	progress func(received, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.received += int64(n)
+		p.progress(p.received, p.total)
+	}
+	return n, err
+}
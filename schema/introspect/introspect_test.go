@@ -0,0 +1,217 @@
+package introspect
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+const response = `{"data": {"__schema": {"queryType": {"name": "Query"}, "types": []}}}`
+
+func TestIntrospect(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		gzip     bool
+		wantAuth string
+	}{
+		{name: "anonymous", wantAuth: ""},
+		{name: "token", opts: []Option{WithToken("secret")}, wantAuth: "bearer secret"},
+		{name: "gzip response", opts: []Option{WithToken("secret")}, gzip: true, wantAuth: "bearer secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAuth, gotQuery, gotEncoding string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAuth = r.Header.Get("Authorization")
+				gotEncoding = r.Header.Get("Accept-Encoding")
+				var req struct {
+					Query string `json:"query"`
+				}
+				body, _ := io.ReadAll(r.Body)
+				if err := yamlformat.Unmarshal(body, &req); err != nil {
+					t.Errorf("Invalid request body: %v", err)
+				}
+				gotQuery = req.Query
+
+				w.Header().Set("X-GitHub-Enterprise-Version", "3.14.0")
+				if tt.gzip {
+					w.Header().Set("Content-Encoding", "gzip")
+					gz := gzip.NewWriter(w)
+					io.WriteString(gz, response)
+					gz.Close()
+					return
+				}
+				io.WriteString(w, response)
+			}))
+			defer server.Close()
+
+			// The test server's client decompresses transparently unless the
+			// request asks for gzip itself
+			opts := append([]Option{WithHTTPClient(server.Client())}, tt.opts...)
+			doc, err := Introspect(context.Background(), server.URL, opts...)
+			if err != nil {
+				t.Fatalf("Introspect failed: %v", err)
+			}
+			if string(doc.Body) != response {
+				t.Errorf("Body = %s", doc.Body)
+			}
+			if doc.Endpoint != server.URL || doc.GHESVersion != "3.14.0" || doc.ReceivedAt.IsZero() {
+				t.Errorf("Unexpected document %+v", doc)
+			}
+			if gotAuth != tt.wantAuth {
+				t.Errorf("Authorization = %q, want %q", gotAuth, tt.wantAuth)
+			}
+			if gotEncoding != "gzip" {
+				t.Errorf("Accept-Encoding = %q, want gzip", gotEncoding)
+			}
+			if gotQuery != Query {
+				t.Errorf("Expected the standard query, got %q", gotQuery)
+			}
+		})
+	}
+}
+
+func TestIntrospectQuery(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotQuery = string(body)
+		io.WriteString(w, response)
+	}))
+	defer server.Close()
+
+	if _, err := Introspect(context.Background(), server.URL, WithQuery("{ __schema { queryType { name } } }")); err != nil {
+		t.Fatalf("Introspect failed: %v", err)
+	}
+	if !strings.Contains(gotQuery, "queryType { name }") || strings.Contains(gotQuery, "FullType") {
+		t.Errorf("Expected the custom query, got %s", gotQuery)
+	}
+}
+
+func TestIntrospectProgress(t *testing.T) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	io.WriteString(gz, response)
+	gz.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed.Bytes())
+	}))
+	defer server.Close()
+
+	var received, total int64
+	if _, err := Introspect(context.Background(), server.URL, WithProgress(func(r, t int64) { received, total = r, t })); err != nil {
+		t.Fatalf("Introspect failed: %v", err)
+	}
+	// The compressed body as sent is counted
+	if want := int64(compressed.Len()); received != want || total != want {
+		t.Errorf("Progress reported %d of %d bytes, want %d", received, total, want)
+	}
+}
+
+func TestIntrospectTransport(t *testing.T) {
+	var got *http.Request
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		got = r
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(response)),
+			Request:    r,
+		}, nil
+	})
+
+	doc, err := Introspect(context.Background(), "https://example.com/graphql", WithToken("secret"), WithTransport(rt))
+	if err != nil {
+		t.Fatalf("Introspect failed: %v", err)
+	}
+	if string(doc.Body) != response {
+		t.Errorf("Body = %s", doc.Body)
+	}
+	if got == nil || got.Method != "POST" || got.URL.Host != "example.com" || got.Header.Get("Authorization") != "bearer secret" {
+		t.Errorf("Unexpected request %+v", got)
+	}
+}
+
+// roundTripperFunc answers requests without a server
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestIntrospectIfNoneMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"abc"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"abc"`)
+		io.WriteString(w, response)
+	}))
+	defer server.Close()
+
+	doc, err := Introspect(context.Background(), server.URL)
+	if err != nil || doc.ETag != `"abc"` {
+		t.Fatalf("Introspect = %+v, %v; want ETag \"abc\"", doc, err)
+	}
+	if _, err := Introspect(context.Background(), server.URL, WithIfNoneMatch(doc.ETag)); !errors.Is(err, ErrNotModified) {
+		t.Errorf("Expected ErrNotModified, got %v", err)
+	}
+	if _, err := Introspect(context.Background(), server.URL, WithIfNoneMatch(`"old"`)); err != nil {
+		t.Errorf("Expected a changed response, got %v", err)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	const sdl = "type Query { viewer: String }\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.Header.Get("Authorization") != "" {
+			t.Errorf("Got %s request with Authorization %q, want an anonymous GET", r.Method, r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("If-None-Match") == `"sdl"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"sdl"`)
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		io.WriteString(gz, sdl)
+		gz.Close()
+	}))
+	defer server.Close()
+
+	doc, err := Fetch(context.Background(), server.URL)
+	if err != nil || string(doc.Body) != sdl || doc.ETag != `"sdl"` {
+		t.Fatalf("Fetch = %+v, %v; want the SDL with its ETag", doc, err)
+	}
+	if _, err := Fetch(context.Background(), server.URL, WithIfNoneMatch(doc.ETag)); !errors.Is(err, ErrNotModified) {
+		t.Errorf("Expected ErrNotModified, got %v", err)
+	}
+}
+
+func TestIntrospectErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := Introspect(context.Background(), server.URL)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized || statusErr.Endpoint != server.URL {
+		t.Errorf("Expected *StatusError with HTTP 401, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Introspect(ctx, server.URL); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
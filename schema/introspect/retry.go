@@ -0,0 +1,144 @@
+package introspect
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how Introspect retries failed requests. Network
+// errors, server errors (HTTP 5xx), and rate limit responses are retried
+// with jittered exponential backoff; other failures are returned at once.
+type RetryPolicy struct {
+	// MaxAttempts is the number of requests to make, including the first;
+	// values below 2 disable retries
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled for each later
+	// one; zero means one second
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts; zero means one minute. A
+	// response asking to wait longer with Retry-After is returned instead of
+	// retried.
+	MaxDelay time.Duration
+	// OnRetry, if set, is called after the failed attempt, before waiting
+	// delay to retry
+	OnRetry func(attempt int, delay time.Duration, err error)
+}
+
+// DefaultRetryPolicy makes up to three attempts, waiting about one and then
+// two seconds between them, or as long as a rate limit response asks up to a
+// minute
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute}
+
+// WithRetry retries failed requests as policy describes
+func WithRetry(policy RetryPolicy) Option {
+	return func(o *options) { o.retry = policy }
+}
+
+// delay returns how long to wait before retrying after the given attempt
+// failed with err, and false when the wait err asks for exceeds MaxDelay
+func (p RetryPolicy) delay(attempt int, err error) (time.Duration, bool) {
+	base, limit := p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = time.Second
+	}
+	if limit <= 0 {
+		limit = time.Minute
+	}
+	d := base
+	for i := 1; i < attempt && d < limit; i++ {
+		d *= 2
+	}
+	d = min(d, limit)
+	// Equal jitter: half of the delay is fixed, the other half random, so
+	// clients failing together do not retry together
+	d = d/2 + rand.N(d/2+1)
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > d {
+		if statusErr.RetryAfter > limit {
+			return 0, false
+		}
+		d = statusErr.RetryAfter
+	}
+	return d, true
+}
+
+// transientError marks a failure to send the request or receive the response,
+// which may succeed when retried
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+// retryable reports whether the request that failed with err is worth
+// retrying: the failure is temporary and ctx is not done
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Temporary()
+	}
+	var transient *transientError
+	return errors.As(err, &transient)
+}
+
+// newStatusError describes an unsuccessful response, detecting rate limits
+// as GitHub documents them: a Retry-After header, an exhausted
+// x-ratelimit-remaining with the reset time in x-ratelimit-reset, or a
+// secondary rate limit message, after which clients should wait a minute
+func newStatusError(resp *http.Response, endpoint string, now time.Time) *StatusError {
+	e := &StatusError{StatusCode: resp.StatusCode, Endpoint: endpoint}
+	if wait, ok := retryAfter(resp.Header.Get("Retry-After"), now); ok {
+		e.RetryAfter = wait
+		e.RateLimited = resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests
+		return e
+	}
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return e
+	}
+	if resp.Header.Get("X-Ratelimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-Ratelimit-Reset"), 10, 64); err == nil {
+			e.RateLimited = true
+			e.RetryAfter = max(time.Unix(reset, 0).Sub(now), 0)
+			return e
+		}
+	}
+	var reader io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		if gz, err := gzip.NewReader(resp.Body); err == nil {
+			defer gz.Close()
+			reader = gz
+		}
+	}
+	body, _ := io.ReadAll(io.LimitReader(reader, 4096))
+	if resp.StatusCode == http.StatusTooManyRequests || strings.Contains(strings.ToLower(string(body)), "rate limit") {
+		e.RateLimited = true
+		e.RetryAfter = time.Minute
+	}
+	return e
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as an HTTP date
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,187 @@
+package introspect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fastRetry retries without noticeable delays
+var fastRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+func TestIntrospectRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  func(w http.ResponseWriter) // Writes a failed response
+		failCount int
+		wantCalls int
+		wantErr   string
+	}{
+		{
+			name:      "server errors",
+			failures:  func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+			failCount: 2, wantCalls: 3,
+		},
+		{
+			name: "secondary rate limit with Retry-After",
+			failures: func(w http.ResponseWriter) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusForbidden)
+			},
+			failCount: 1, wantCalls: 2,
+		},
+		{
+			name:      "exhausted",
+			failures:  func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+			failCount: 5, wantCalls: 3,
+			wantErr: "returned HTTP 503 (after 3 attempts)",
+		},
+		{
+			name:      "not found is not retried",
+			failures:  func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) },
+			failCount: 5, wantCalls: 1,
+			wantErr: "returned HTTP 404",
+		},
+		{
+			name: "rate limit beyond MaxDelay",
+			failures: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusForbidden)
+				io.WriteString(w, `{"message": "You have exceeded a secondary rate limit."}`)
+			},
+			failCount: 5, wantCalls: 1,
+			wantErr: "rate limit exceeded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls <= tt.failCount {
+					tt.failures(w)
+					return
+				}
+				io.WriteString(w, response)
+			}))
+			defer server.Close()
+
+			var retries []int
+			policy := fastRetry
+			policy.OnRetry = func(attempt int, delay time.Duration, err error) { retries = append(retries, attempt) }
+			doc, err := Introspect(context.Background(), server.URL, WithRetry(policy))
+			if tt.wantErr == "" {
+				if err != nil || string(doc.Body) != response {
+					t.Fatalf("Introspect = %v, %v", doc, err)
+				}
+			} else if err == nil || !strings.HasSuffix(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error ending in %q, got %v", tt.wantErr, err)
+			}
+			if calls != tt.wantCalls || len(retries) != tt.wantCalls-1 {
+				t.Errorf("Made %d requests with retries %v, want %d requests", calls, retries, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIntrospectRetryNetworkError(t *testing.T) {
+	calls := 0
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return nil, fmt.Errorf("connection reset by peer")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(response)), Request: r}, nil
+	})
+	if _, err := Introspect(context.Background(), "https://example.com/graphql", WithTransport(rt), WithRetry(fastRetry)); err != nil || calls != 2 {
+		t.Errorf("Introspect = %v after %d requests, want success after 2", err, calls)
+	}
+
+	calls = 0
+	if _, err := Introspect(context.Background(), "https://example.com/graphql", WithTransport(rt)); err == nil || calls != 1 {
+		t.Errorf("Expected a single failed request without WithRetry, got %v after %d requests", err, calls)
+	}
+}
+
+func TestIntrospectRetryCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	policy.OnRetry = func(int, time.Duration, error) { cancel() }
+	start := time.Now()
+	if _, err := Introspect(ctx, server.URL, WithRetry(policy)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Cancellation took %s", elapsed)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 10: time.Second} {
+		d, ok := p.delay(attempt, fmt.Errorf("network"))
+		if !ok || d < want/2 || d > want {
+			t.Errorf("delay(%d) = %s, want between %s and %s", attempt, d, want/2, want)
+		}
+	}
+
+	d, ok := p.delay(1, &StatusError{StatusCode: 429, RateLimited: true, RetryAfter: 700 * time.Millisecond})
+	if !ok || d != 700*time.Millisecond {
+		t.Errorf("Expected the Retry-After wait, got %s, %v", d, ok)
+	}
+	if _, ok := p.delay(1, &StatusError{StatusCode: 429, RateLimited: true, RetryAfter: time.Hour}); ok {
+		t.Error("Expected a wait beyond MaxDelay to give up")
+	}
+}
+
+func TestNewStatusError(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		status      int
+		header      http.Header
+		body        string
+		rateLimited bool
+		retryAfter  time.Duration
+	}{
+		{name: "server error", status: 502},
+		{name: "server error with Retry-After", status: 503, header: http.Header{"Retry-After": {"5"}}, retryAfter: 5 * time.Second},
+		{name: "forbidden", status: 403, body: `{"message": "Resource not accessible by integration"}`},
+		{name: "Retry-After date", status: 429, header: http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}}, rateLimited: true, retryAfter: time.Minute},
+		{
+			name:        "primary rate limit",
+			status:      403,
+			header:      http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {strconv.FormatInt(now.Add(30*time.Second).Unix(), 10)}},
+			rateLimited: true, retryAfter: 30 * time.Second,
+		},
+		{name: "secondary rate limit message", status: 403, body: `{"message": "You have exceeded a secondary rate limit."}`, rateLimited: true, retryAfter: time.Minute},
+		{name: "too many requests", status: 429, rateLimited: true, retryAfter: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := tt.header
+			if header == nil {
+				header = http.Header{}
+			}
+			resp := &http.Response{StatusCode: tt.status, Header: header, Body: io.NopCloser(strings.NewReader(tt.body))}
+			e := newStatusError(resp, "https://example.com", now)
+			if e.RateLimited != tt.rateLimited || e.RetryAfter != tt.retryAfter {
+				t.Errorf("RateLimited = %v, RetryAfter = %s; want %v, %s", e.RateLimited, e.RetryAfter, tt.rateLimited, tt.retryAfter)
+			}
+			if want := tt.status >= 500 || tt.rateLimited; e.Temporary() != want {
+				t.Errorf("Temporary() = %v, want %v", e.Temporary(), want)
+			}
+		})
+	}
+}
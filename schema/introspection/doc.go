@@ -0,0 +1,26 @@
+// Package introspection defines typed Go structs for GraphQL introspection
+// results, such as the documents the schema package embeds and downloads.
+//
+// The structs are the typed foundation of the schema package: schema.Model,
+// the lookup-friendly view that diffs, SDL output, code generation, and query
+// validation work on, is built from a Document with null and absent values
+// folded away. The structs themselves mirror the JSON of a response field for
+// field: nullable values are pointers, lists that are null stay nil, and type
+// references nest through OfType. Parsing a document fetched with
+// introspect.Query and marshaling it back reproduces the original byte for
+// byte once both sides are canonicalized with Canonicalize, which makes the
+// structs a safe basis for tools that rewrite schemas:
+//
+//	doc, err := introspection.Parse(data)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, t := range doc.Data.Schema.Types {
+//		fmt.Println(t.Kind, t.Name)
+//	}
+//	out, err := introspection.Marshal(doc)
+//
+// Parse and Marshal use go-yamlformat; the encodings that keep null and
+// absent members apart are defined for it, so use these functions rather
+// than encoding/json.
+package introspection
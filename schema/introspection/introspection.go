@@ -0,0 +1,610 @@
+package introspection
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+// Document is an introspection response: {"data": {"__schema": {...}}}.
+// Extensions holds the "extensions" entry, such as the download metadata
+// recorded by the schema package, as generic values.
+type Document struct {
+	Data       Data                   `json:"data"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Data is the data entry of a Document
+type Data struct {
+	Schema Schema `json:"__schema"`
+}
+
+// Schema mirrors __Schema. Root types the schema does not have are nil.
+type Schema struct {
+	Description      Optional[string] `json:"description,omitzero"`
+	QueryType        *RootType        `json:"queryType"`
+	MutationType     *RootType        `json:"mutationType"`
+	SubscriptionType *RootType        `json:"subscriptionType"`
+	Types            []Type           `json:"types"`
+	Directives       []Directive      `json:"directives"`
+}
+
+// RootType names a root operation type
+type RootType struct {
+	Name string `json:"name"`
+}
+
+// Type mirrors __Type for a named type. Lists that do not apply to Kind are
+// nil, which marshals as null as servers return them; lists that apply but
+// are empty are non-nil. SpecifiedByURL and IsOneOf are only present in
+// responses to queries that ask for them.
+type Type struct {
+	Kind           string           `json:"kind"`
+	Name           string           `json:"name"`
+	Description    *string          `json:"description"`
+	SpecifiedByURL Optional[string] `json:"specifiedByURL,omitzero"`
+	Fields         []Field          `json:"fields"`
+	InputFields    []InputValue     `json:"inputFields"`
+	Interfaces     []TypeRef        `json:"interfaces"`
+	EnumValues     []EnumValue      `json:"enumValues"`
+	PossibleTypes  []TypeRef        `json:"possibleTypes"`
+	IsOneOf        *bool            `json:"isOneOf,omitempty"`
+}
+
+// Field mirrors __Field
+type Field struct {
+	Name              string       `json:"name"`
+	Description       *string      `json:"description"`
+	Args              []InputValue `json:"args"`
+	Type              TypeRef      `json:"type"`
+	IsDeprecated      bool         `json:"isDeprecated"`
+	DeprecationReason *string      `json:"deprecationReason"`
+}
+
+// InputValue mirrors __InputValue, an argument or input field. DefaultValue
+// is a GraphQL literal. The deprecation members are only present in
+// responses to queries that ask for deprecated input values.
+type InputValue struct {
+	Name              string           `json:"name"`
+	Description       *string          `json:"description"`
+	Type              TypeRef          `json:"type"`
+	DefaultValue      *string          `json:"defaultValue"`
+	IsDeprecated      *bool            `json:"isDeprecated,omitempty"`
+	DeprecationReason Optional[string] `json:"deprecationReason,omitzero"`
+}
+
+// EnumValue mirrors __EnumValue
+type EnumValue struct {
+	Name              string  `json:"name"`
+	Description       *string `json:"description"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+// Directive mirrors __Directive. IsRepeatable is only present in responses
+// to queries that ask for it.
+type Directive struct {
+	Name         string       `json:"name"`
+	Description  *string      `json:"description"`
+	IsRepeatable *bool        `json:"isRepeatable,omitempty"`
+	Locations    []string     `json:"locations"`
+	Args         []InputValue `json:"args"`
+}
+
+// Optional is a nullable member that only some introspection queries ask
+// for, such as specifiedByURL. The zero value is absent and left out when
+// marshaling; a present member has Present set and Value nil for null.
+type Optional[T any] struct {
+	Value   *T
+	Present bool
+}
+
+// Some returns a present member with the given value
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Value: &v, Present: true}
+}
+
+// IsZero reports whether the member is absent
+func (o Optional[T]) IsZero() bool {
+	return !o.Present
+}
+
+// Get returns the value, or the zero value of T when the member is absent or
+// null
+func (o Optional[T]) Get() T {
+	if o.Value == nil {
+		var zero T
+		return zero
+	}
+	return *o.Value
+}
+
+// MarshalYAML encodes the value, or null
+func (o Optional[T]) MarshalYAML() (interface{}, error) {
+	return o.Value, nil
+}
+
+// UnmarshalYAML decodes a present member. The decoder does not call it for
+// null, which the members holding an Optional record with nullMembers.
+func (o *Optional[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v T
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	*o = Some(v)
+	return nil
+}
+
+// member records whether a member of an object is present and not null,
+// without decoding its value
+type member struct {
+	notNull bool
+}
+
+func (m *member) UnmarshalYAML(func(interface{}) error) error {
+	m.notNull = true
+	return nil
+}
+
+// nullMembers decodes which members of an object are present with a null
+// value, for Optional members, which the decoder leaves absent for null
+func nullMembers(unmarshal func(interface{}) error) (map[string]bool, error) {
+	var members map[string]member
+	if err := unmarshal(&members); err != nil {
+		return nil, err
+	}
+	nulls := make(map[string]bool)
+	for name, m := range members {
+		if !m.notNull {
+			nulls[name] = true
+		}
+	}
+	return nulls, nil
+}
+
+// markNull marks o as a present null when nulls has name
+func markNull[T any](o *Optional[T], nulls map[string]bool, name string) {
+	if nulls[name] {
+		*o = Optional[T]{Present: true}
+	}
+}
+
+// list returns s, or nil for a nil slice, which the encoder would write as
+// an empty list rather than null
+func list[S ~[]E, E any](s S) interface{} {
+	if s == nil {
+		return nil
+	}
+	return s
+}
+
+// MarshalYAML encodes the schema, writing nil lists as null
+func (s Schema) MarshalYAML() (interface{}, error) {
+	return struct {
+		Description      Optional[string] `json:"description,omitzero"`
+		QueryType        *RootType        `json:"queryType"`
+		MutationType     *RootType        `json:"mutationType"`
+		SubscriptionType *RootType        `json:"subscriptionType"`
+		Types            interface{}      `json:"types"`
+		Directives       interface{}      `json:"directives"`
+	}{s.Description, s.QueryType, s.MutationType, s.SubscriptionType, list(s.Types), list(s.Directives)}, nil
+}
+
+// MarshalYAML encodes the type, writing nil lists as null
+func (t Type) MarshalYAML() (interface{}, error) {
+	return struct {
+		Kind           string           `json:"kind"`
+		Name           string           `json:"name"`
+		Description    *string          `json:"description"`
+		SpecifiedByURL Optional[string] `json:"specifiedByURL,omitzero"`
+		Fields         interface{}      `json:"fields"`
+		InputFields    interface{}      `json:"inputFields"`
+		Interfaces     interface{}      `json:"interfaces"`
+		EnumValues     interface{}      `json:"enumValues"`
+		PossibleTypes  interface{}      `json:"possibleTypes"`
+		IsOneOf        *bool            `json:"isOneOf,omitempty"`
+	}{t.Kind, t.Name, t.Description, t.SpecifiedByURL, list(t.Fields), list(t.InputFields), list(t.Interfaces), list(t.EnumValues), list(t.PossibleTypes), t.IsOneOf}, nil
+}
+
+// MarshalYAML encodes the field, writing nil arguments as null
+func (f Field) MarshalYAML() (interface{}, error) {
+	return struct {
+		Name              string      `json:"name"`
+		Description       *string     `json:"description"`
+		Args              interface{} `json:"args"`
+		Type              TypeRef     `json:"type"`
+		IsDeprecated      bool        `json:"isDeprecated"`
+		DeprecationReason *string     `json:"deprecationReason"`
+	}{f.Name, f.Description, list(f.Args), f.Type, f.IsDeprecated, f.DeprecationReason}, nil
+}
+
+// MarshalYAML encodes the directive, writing nil lists as null
+func (d Directive) MarshalYAML() (interface{}, error) {
+	return struct {
+		Name         string      `json:"name"`
+		Description  *string     `json:"description"`
+		IsRepeatable *bool       `json:"isRepeatable,omitempty"`
+		Locations    interface{} `json:"locations"`
+		Args         interface{} `json:"args"`
+	}{d.Name, d.Description, d.IsRepeatable, list(d.Locations), list(d.Args)}, nil
+}
+
+// schemaMembers, typeMembers, and inputValueMembers have the members of
+// Schema, Type, and InputValue without their methods
+type (
+	schemaMembers     Schema
+	typeMembers       Type
+	inputValueMembers InputValue
+)
+
+// UnmarshalYAML decodes the schema, keeping a null description
+func (s *Schema) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal((*schemaMembers)(s)); err != nil {
+		return err
+	}
+	nulls, err := nullMembers(unmarshal)
+	if err != nil {
+		return err
+	}
+	markNull(&s.Description, nulls, "description")
+	return nil
+}
+
+// UnmarshalYAML decodes the type, keeping a null specifiedByURL
+func (t *Type) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal((*typeMembers)(t)); err != nil {
+		return err
+	}
+	nulls, err := nullMembers(unmarshal)
+	if err != nil {
+		return err
+	}
+	markNull(&t.SpecifiedByURL, nulls, "specifiedByURL")
+	return nil
+}
+
+// UnmarshalYAML decodes the input value, keeping a null deprecationReason
+func (v *InputValue) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal((*inputValueMembers)(v)); err != nil {
+		return err
+	}
+	nulls, err := nullMembers(unmarshal)
+	if err != nil {
+		return err
+	}
+	markNull(&v.DeprecationReason, nulls, "deprecationReason")
+	return nil
+}
+
+// TypeRef mirrors the type references of __Type: NON_NULL and LIST wrap the
+// type in OfType and have no Name; named types have a Name and no OfType.
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   *string  `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+
+	// The innermost reference of a query's nesting has no ofType member at
+	// all; remembered so it marshals the same way
+	noOfType bool
+}
+
+// typeRefMembers has the members of TypeRef without its methods
+type typeRefMembers struct {
+	Kind   string   `json:"kind"`
+	Name   *string  `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+// typeRefLeaf is a TypeRef without the ofType member
+type typeRefLeaf struct {
+	Kind string  `json:"kind"`
+	Name *string `json:"name"`
+}
+
+// UnmarshalYAML decodes a type reference, noting whether ofType is present
+func (r *TypeRef) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v typeRefMembers
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	var members map[string]member
+	if err := unmarshal(&members); err != nil {
+		return err
+	}
+	_, hasOfType := members["ofType"]
+	*r = TypeRef{Kind: v.Kind, Name: v.Name, OfType: v.OfType, noOfType: !hasOfType}
+	return nil
+}
+
+// MarshalYAML encodes a type reference, leaving out ofType only where the
+// decoded reference did not have it
+func (r TypeRef) MarshalYAML() (interface{}, error) {
+	if r.noOfType && r.OfType == nil {
+		return typeRefLeaf{Kind: r.Kind, Name: r.Name}, nil
+	}
+	return typeRefMembers{Kind: r.Kind, Name: r.Name, OfType: r.OfType}, nil
+}
+
+// String returns the type in GraphQL notation, such as "[Issue!]!"
+func (r *TypeRef) String() string {
+	switch {
+	case r == nil:
+		return ""
+	case r.Kind == "NON_NULL":
+		return r.OfType.String() + "!"
+	case r.Kind == "LIST":
+		return "[" + r.OfType.String() + "]"
+	case r.Name != nil:
+		return *r.Name
+	}
+	return ""
+}
+
+// NamedType returns the name of the innermost named type
+func (r *TypeRef) NamedType() string {
+	for r != nil && r.Name == nil {
+		r = r.OfType
+	}
+	if r == nil {
+		return ""
+	}
+	return *r.Name
+}
+
+// Parse decodes an introspection response, or a bare {"__schema": ...}
+// document as graphql-js and Apollo tooling write them. Responses without
+// data, such as ones reporting only errors, are rejected.
+func Parse(data []byte) (*Document, error) {
+	var doc struct {
+		Document `json:",inline"`
+		Schema   *Schema `json:"__schema"`
+	}
+	if err := yamlformat.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse introspection result: %w", err)
+	}
+	if doc.Schema != nil && doc.Data.Schema.Types == nil {
+		doc.Data.Schema = *doc.Schema
+	}
+	if doc.Data.Schema.Types == nil {
+		return nil, fmt.Errorf("introspection result has no data.__schema.types")
+	}
+	return &doc.Document, nil
+}
+
+// FromValue converts a document that was already decoded into generic
+// values, such as by yamlformat.Unmarshal into an interface{}, without
+// encoding it again. Like Parse, it accepts bare {"__schema": ...} documents
+// and rejects documents without types. Members of the wrong type are left
+// out rather than rejected, so documents that are loaded leniently still
+// convert.
+func FromValue(v interface{}) (*Document, error) {
+	root, _ := v.(map[string]interface{})
+	data, _ := root["data"].(map[string]interface{})
+	schema, _ := data["__schema"].(map[string]interface{})
+	if schema == nil {
+		schema, _ = root["__schema"].(map[string]interface{})
+	}
+	types, ok := schema["types"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("introspection result has no data.__schema.types")
+	}
+
+	doc := &Document{}
+	doc.Extensions, _ = root["extensions"].(map[string]interface{})
+	s := &doc.Data.Schema
+	s.Description = optionalString(schema, "description")
+	s.QueryType = rootTypeOf(schema["queryType"])
+	s.MutationType = rootTypeOf(schema["mutationType"])
+	s.SubscriptionType = rootTypeOf(schema["subscriptionType"])
+	s.Types = make([]Type, 0, len(types))
+	for _, item := range objects(types) {
+		s.Types = append(s.Types, typeOf(item))
+	}
+	if directives, ok := schema["directives"].([]interface{}); ok {
+		s.Directives = make([]Directive, 0, len(directives))
+		for _, item := range objects(directives) {
+			d := Directive{
+				Name:         stringOf(item, "name"),
+				Description:  stringPtr(item, "description"),
+				IsRepeatable: boolPtr(item, "isRepeatable"),
+				Args:         inputValuesOf(item["args"]),
+			}
+			if locations, ok := item["locations"].([]interface{}); ok {
+				d.Locations = make([]string, 0, len(locations))
+				for _, l := range locations {
+					if location, ok := l.(string); ok {
+						d.Locations = append(d.Locations, location)
+					}
+				}
+			}
+			s.Directives = append(s.Directives, d)
+		}
+	}
+	return doc, nil
+}
+
+func typeOf(entry map[string]interface{}) Type {
+	t := Type{
+		Kind:           stringOf(entry, "kind"),
+		Name:           stringOf(entry, "name"),
+		Description:    stringPtr(entry, "description"),
+		SpecifiedByURL: optionalString(entry, "specifiedByURL"),
+		InputFields:    inputValuesOf(entry["inputFields"]),
+		Interfaces:     typeRefsOf(entry["interfaces"]),
+		PossibleTypes:  typeRefsOf(entry["possibleTypes"]),
+		IsOneOf:        boolPtr(entry, "isOneOf"),
+	}
+	if fields, ok := entry["fields"].([]interface{}); ok {
+		t.Fields = make([]Field, 0, len(fields))
+		for _, item := range objects(fields) {
+			isDeprecated, _ := item["isDeprecated"].(bool)
+			t.Fields = append(t.Fields, Field{
+				Name:              stringOf(item, "name"),
+				Description:       stringPtr(item, "description"),
+				Args:              inputValuesOf(item["args"]),
+				Type:              typeRefOf(item["type"]),
+				IsDeprecated:      isDeprecated,
+				DeprecationReason: stringPtr(item, "deprecationReason"),
+			})
+		}
+	}
+	if values, ok := entry["enumValues"].([]interface{}); ok {
+		t.EnumValues = make([]EnumValue, 0, len(values))
+		for _, item := range objects(values) {
+			isDeprecated, _ := item["isDeprecated"].(bool)
+			t.EnumValues = append(t.EnumValues, EnumValue{
+				Name:              stringOf(item, "name"),
+				Description:       stringPtr(item, "description"),
+				IsDeprecated:      isDeprecated,
+				DeprecationReason: stringPtr(item, "deprecationReason"),
+			})
+		}
+	}
+	return t
+}
+
+// inputValuesOf converts a list of __InputValue entries, or returns nil
+// when v is not a list
+func inputValuesOf(v interface{}) []InputValue {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make([]InputValue, 0, len(list))
+	for _, item := range objects(list) {
+		values = append(values, InputValue{
+			Name:              stringOf(item, "name"),
+			Description:       stringPtr(item, "description"),
+			Type:              typeRefOf(item["type"]),
+			DefaultValue:      stringPtr(item, "defaultValue"),
+			IsDeprecated:      boolPtr(item, "isDeprecated"),
+			DeprecationReason: optionalString(item, "deprecationReason"),
+		})
+	}
+	return values
+}
+
+// typeRefsOf converts a list of type references, or returns nil when v is
+// not a list
+func typeRefsOf(v interface{}) []TypeRef {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	refs := make([]TypeRef, 0, len(list))
+	for _, item := range objects(list) {
+		refs = append(refs, typeRefOf(item))
+	}
+	return refs
+}
+
+func typeRefOf(v interface{}) TypeRef {
+	entry, _ := v.(map[string]interface{})
+	ofType, hasOfType := entry["ofType"]
+	r := TypeRef{Kind: stringOf(entry, "kind"), Name: stringPtr(entry, "name"), noOfType: !hasOfType}
+	if _, ok := ofType.(map[string]interface{}); ok {
+		inner := typeRefOf(ofType)
+		r.OfType = &inner
+	}
+	return r
+}
+
+func rootTypeOf(v interface{}) *RootType {
+	entry, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return &RootType{Name: stringOf(entry, "name")}
+}
+
+// objects returns the objects of list
+func objects(list []interface{}) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		if entry, ok := item.(map[string]interface{}); ok {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+func stringOf(entry map[string]interface{}, key string) string {
+	s, _ := entry[key].(string)
+	return s
+}
+
+func stringPtr(entry map[string]interface{}, key string) *string {
+	if s, ok := entry[key].(string); ok {
+		return &s
+	}
+	return nil
+}
+
+func boolPtr(entry map[string]interface{}, key string) *bool {
+	if b, ok := entry[key].(bool); ok {
+		return &b
+	}
+	return nil
+}
+
+// optionalString converts a member that some queries do not ask for
+func optionalString(entry map[string]interface{}, key string) Optional[string] {
+	v, ok := entry[key]
+	if !ok {
+		return Optional[string]{}
+	}
+	if s, ok := v.(string); ok {
+		return Some(s)
+	}
+	return Optional[string]{Present: true}
+}
+
+// Marshal encodes a document as JSON on a single line with members in the
+// order of the introspection query, as GraphQL servers return them. HTML
+// characters in descriptions are kept as they are.
+func Marshal(doc *Document) ([]byte, error) {
+	return marshal(doc)
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	data, err := yamlformat.MarshalJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(data, []byte("\n")), nil
+}
+
+// Canonicalize rewrites a JSON document in a canonical form: on a single
+// line, with object members sorted by name, and strings and numbers written
+// uniformly. Two documents with the same content canonicalize to the same
+// bytes regardless of formatting and member order.
+func Canonicalize(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := yamlformat.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to canonicalize JSON: %w", err)
+	}
+	// Maps are encoded with sorted keys
+	return marshal(v)
+}
+
+// Type returns the named type, or nil. It scans the types in order; build an
+// index for repeated lookups.
+func (s *Schema) Type(name string) *Type {
+	for i := range s.Types {
+		if s.Types[i].Name == name {
+			return &s.Types[i]
+		}
+	}
+	return nil
+}
+
+// Field returns the named field, or nil
+func (t *Type) Field(name string) *Field {
+	for i := range t.Fields {
+		if t.Fields[i].Name == name {
+			return &t.Fields[i]
+		}
+	}
+	return nil
+}
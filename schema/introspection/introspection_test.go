@@ -0,0 +1,211 @@
+package introspection
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/apstndb/go-yamlformat"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func readFixture(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	if !bytes.HasPrefix(data, []byte{0x28, 0xb5, 0x2f, 0xfd}) {
+		return data
+	}
+	dec, err := zstd.NewReader(nil)
+	if err != nil {
+		t.Fatalf("Failed to decompress %s: %v", path, err)
+	}
+	defer dec.Close()
+	if data, err = dec.DecodeAll(data, nil); err != nil {
+		t.Fatalf("Failed to decompress %s: %v", path, err)
+	}
+	return data
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, path := range []string{"../schema.json.zst", "../sample.json"} {
+		t.Run(path, func(t *testing.T) {
+			data := readFixture(t, path)
+			if len(data) == 0 {
+				t.Skip("Schema file is empty")
+			}
+			doc, err := Parse(data)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			out, err := Marshal(doc)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			want, err := Canonicalize(data)
+			if err != nil {
+				t.Fatalf("Canonicalize failed: %v", err)
+			}
+			got, err := Canonicalize(out)
+			if err != nil {
+				t.Fatalf("Canonicalize failed: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				i := 0
+				for i < len(got) && i < len(want) && got[i] == want[i] {
+					i++
+				}
+				start := max(i-80, 0)
+				t.Errorf("Round trip differs at byte %d:\n got %s\nwant %s", i, got[start:min(i+80, len(got))], want[start:min(i+80, len(want))])
+			}
+		})
+	}
+}
+
+func TestFromValue(t *testing.T) {
+	data := readFixture(t, "../sample.json")
+	want, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	var v interface{}
+	if err := yamlformat.Unmarshal(data, &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	got, err := FromValue(v)
+	if err != nil {
+		t.Fatalf("FromValue failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("FromValue differs from Parse")
+	}
+
+	// Members of the wrong type are left out, documents without types fail
+	if doc, err := FromValue(map[string]interface{}{"__schema": map[string]interface{}{
+		"types": []interface{}{"Query", map[string]interface{}{"kind": "OBJECT", "name": "Query", "fields": "none"}},
+	}}); err != nil || len(doc.Data.Schema.Types) != 1 || doc.Data.Schema.Types[0].Fields != nil {
+		t.Errorf("FromValue = %+v, %v", doc, err)
+	}
+	if _, err := FromValue(map[string]interface{}{"data": nil}); err == nil {
+		t.Error("Expected an error for a document without types")
+	}
+}
+
+func TestTypeRef(t *testing.T) {
+	doc, err := Parse([]byte(`{"data": {"__schema": {"queryType": {"name": "Query"}, "mutationType": null, "subscriptionType": null, "directives": [],
+"types": [{"kind": "OBJECT", "name": "Query", "description": null, "inputFields": null, "interfaces": [], "enumValues": null, "possibleTypes": null,
+"fields": [{"name": "ids", "description": "A <b>list</b>", "args": [], "isDeprecated": false, "deprecationReason": null,
+"type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "LIST", "name": null, "ofType": {"kind": "SCALAR", "name": "ID"}}}}]}]}}}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	query := doc.Data.Schema.Type("Query")
+	if query == nil || query.Field("ids") == nil || doc.Data.Schema.Type("Nope") != nil {
+		t.Fatalf("Lookups failed: %+v", doc.Data.Schema.Types)
+	}
+	ref := query.Field("ids").Type
+	if ref.String() != "[ID]!" || ref.NamedType() != "ID" {
+		t.Errorf("String = %q, NamedType = %q", ref.String(), ref.NamedType())
+	}
+
+	out, err := Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	for _, want := range []string{
+		`"ofType": {"kind": "SCALAR", "name": "ID"}}`, // No ofType member where the input had none
+		`"mutationType": null`,
+		`"inputFields": null`,
+		`"description": "A <b>list</b>"`,
+	} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("Output does not contain %s:\n%s", want, out)
+		}
+	}
+
+	// Constructed references always have the member
+	name := "ID"
+	if out, _ := Marshal(&Document{Data: Data{Schema: Schema{Types: []Type{{Kind: "SCALAR", Name: "ID", Interfaces: []TypeRef{{Kind: "SCALAR", Name: &name}}}}}}}); !bytes.Contains(out, []byte(`{"kind": "SCALAR", "name": "ID", "ofType": null}`)) {
+		t.Errorf("Expected ofType null for a constructed reference:\n%s", out)
+	}
+}
+
+func TestOptional(t *testing.T) {
+	// Members the legacy query does not ask for stay absent, and null stays
+	// null where the newer query asks for them
+	for _, data := range []string{
+		`{"data":{"__schema":{"queryType":{"name":"Query"},"mutationType":null,"subscriptionType":null,"types":[{"kind":"SCALAR","name":"URI","description":null,"fields":null,"inputFields":null,"interfaces":null,"enumValues":null,"possibleTypes":null}],"directives":[{"name":"skip","description":null,"locations":["FIELD"],"args":[{"name":"if","description":null,"type":{"kind":"SCALAR","name":"Boolean","ofType":null},"defaultValue":null}]}]}}}`,
+		`{"data":{"__schema":{"description":null,"queryType":{"name":"Query"},"mutationType":null,"subscriptionType":null,"types":[{"kind":"SCALAR","name":"URI","description":null,"specifiedByURL":"https://www.rfc-editor.org/rfc/rfc3986","fields":null,"inputFields":null,"interfaces":null,"enumValues":null,"possibleTypes":null}],"directives":[{"name":"skip","description":null,"isRepeatable":false,"locations":["FIELD"],"args":[{"name":"if","description":null,"type":{"kind":"SCALAR","name":"Boolean","ofType":null},"defaultValue":null,"isDeprecated":false,"deprecationReason":null}]}]}}}`,
+	} {
+		doc, err := Parse([]byte(data))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		out, err := Marshal(doc)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		got, err := Canonicalize(out)
+		if err != nil {
+			t.Fatalf("Canonicalize failed: %v", err)
+		}
+		want, err := Canonicalize([]byte(data))
+		if err != nil {
+			t.Fatalf("Canonicalize failed: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("Round trip differs:\n got %s\nwant %s", got, want)
+		}
+	}
+
+	doc, _ := Parse([]byte(`{"data": {"__schema": {"description": "GitHub", "types": [{"kind": "SCALAR", "name": "URI", "specifiedByURL": null}]}}}`))
+	schema := doc.Data.Schema
+	if !schema.Description.Present || schema.Description.Get() != "GitHub" {
+		t.Errorf("Description = %+v", schema.Description)
+	}
+	if url := schema.Types[0].SpecifiedByURL; !url.Present || url.Value != nil || url.Get() != "" {
+		t.Errorf("SpecifiedByURL = %+v, want present null", url)
+	}
+	if s := Some("x"); !s.Present || s.Get() != "x" {
+		t.Errorf("Some = %+v", s)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{`{"errors": [{"message": "bad credentials"}]}`, `not json`} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Expected an error for %s", data)
+		}
+	}
+}
+
+func TestParseBare(t *testing.T) {
+	doc, err := Parse([]byte(`{"__schema": {"queryType": {"name": "Query"}, "types": [{"kind": "OBJECT", "name": "Query"}]}}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(doc.Data.Schema.Types) != 1 || doc.Data.Schema.QueryType == nil || doc.Data.Schema.QueryType.Name != "Query" {
+		t.Errorf("Schema = %+v", doc.Data.Schema)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	a, err := Canonicalize([]byte("{\"b\": 1.50, \"a\": \"\\u003c\"}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Canonicalize([]byte(`{"a":"<","b":1.50}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(a) != `{"a": "<", "b": 1.5}` || !bytes.Equal(a, b) {
+		t.Errorf("Canonicalize = %s and %s", a, b)
+	}
+	if _, err := Canonicalize([]byte(`{} {}`)); err == nil {
+		t.Error("Expected an error for trailing data")
+	}
+}
@@ -0,0 +1,324 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+// maxFlagDepth bounds how deep MutationFlags descends into nested input objects
+const maxFlagDepth = 3
+
+// MutationFlag is a scalar or enum input of a mutation that can be given as
+// a command line flag, see MutationFlags
+type MutationFlag struct {
+	// Name is the kebab-case flag name, such as "subject-id", or
+	// "metadata-priority" for a field of a nested input object
+	Name string `json:"name"`
+	// Path is the argument and the input fields leading to the value, such
+	// as ["input", "subjectId"]
+	Path        []string `json:"path"`
+	Type        string   `json:"type"` // In GraphQL notation, such as "ID!" or "[ID!]"
+	Kind        string   `json:"kind"` // SCALAR or ENUM
+	List        bool     `json:"list,omitempty"`
+	Required    bool     `json:"required"`
+	Description string   `json:"description,omitempty"`
+	EnumValues  []string `json:"enumValues,omitempty"`
+}
+
+// MutationCall is an operation calling a mutation with the values of its
+// flags, see NewMutationCall
+type MutationCall struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+	// Errors are the problems of the values, as ValidateVariables reports
+	// them, such as missing required flags or unknown enum values
+	Errors []VariableError `json:"errors,omitempty"`
+}
+
+// MutationFlags flattens the arguments of mutation into flags: scalar and
+// enum arguments and input fields become one flag each, and the fields of
+// nested input objects are prefixed with the field name. The fields of the
+// input argument of GitHub's mutations are not prefixed. Lists of input
+// objects and input objects nested more than three levels deep cannot be
+// given as flags and are left out.
+func (s *Schema) MutationFlags(mutation string) ([]MutationFlag, error) {
+	m := s.Model()
+	def := m.Mutation(mutation)
+	if def == nil {
+		return nil, m.rootFieldNotFound(m.MutationType, "Mutation", "mutation", mutation)
+	}
+	var flags []MutationFlag
+	for _, arg := range def.Args {
+		prefix := kebabCase(arg.Name) + "-"
+		if arg.Name == "input" {
+			prefix = ""
+		}
+		flags = m.appendFlags(flags, arg, []string{arg.Name}, prefix, true, make(map[string]bool))
+	}
+	return flags, nil
+}
+
+// appendFlags appends the flags of the input value v at path. required is
+// whether the enclosing values are required.
+func (m *Model) appendFlags(flags []MutationFlag, v *InputValue, path []string, prefix string, required bool, active map[string]bool) []MutationFlag {
+	t := m.Resolve(v.Type)
+	if t == nil {
+		return flags
+	}
+	required = required && v.Type.IsNonNull() && v.DefaultValue == nil
+	switch t.Kind {
+	case "SCALAR", "ENUM":
+		name := kebabCase(path[len(path)-1])
+		if len(path) == 1 {
+			prefix = ""
+		}
+		flag := MutationFlag{
+			Name:        prefix + name,
+			Path:        path,
+			Type:        v.Type.String(),
+			Kind:        t.Kind,
+			List:        v.Type.IsList(),
+			Required:    required,
+			Description: firstLine(v.Description),
+		}
+		for _, e := range t.EnumValues {
+			flag.EnumValues = append(flag.EnumValues, e.Name)
+		}
+		return append(flags, flag)
+	case "INPUT_OBJECT":
+		if v.Type.IsList() || active[t.Name] || len(path) > maxFlagDepth {
+			return flags
+		}
+		active[t.Name] = true
+		defer delete(active, t.Name)
+		if len(path) > 1 {
+			prefix += kebabCase(path[len(path)-1]) + "-"
+		}
+		for _, f := range t.InputFields {
+			flags = m.appendFlags(flags, f, append(path[:len(path):len(path)], f.Name), prefix, required, active)
+		}
+	}
+	return flags
+}
+
+// NewMutationCall builds the operation calling mutation with values, the
+// flags of MutationFlags given on the command line keyed by name. Values are
+// converted to the type of their flag, with one value per item for lists;
+// Boolean flags take "true" or "false". The payload selection has its scalar
+// fields and the id, or __typename, of the objects it returns.
+//
+// Values that do not convert or fit their type, unknown flags, and missing
+// required flags are reported in Errors rather than failing.
+func (s *Schema) NewMutationCall(mutation string, values map[string][]string) (*MutationCall, error) {
+	flags, err := s.MutationFlags(mutation)
+	if err != nil {
+		return nil, err
+	}
+	m := s.Model()
+	def := m.Mutation(mutation)
+
+	call := &MutationCall{Variables: make(map[string]interface{})}
+	byPath := make(map[string]string, len(flags)) // Variable paths to flag names
+	for _, flag := range flags {
+		byPath["$"+strings.Join(flag.Path, ".")] = flag.Name
+		raw, ok := values[flag.Name]
+		if !ok || len(raw) == 0 {
+			continue
+		}
+		value, err := flagValue(m, flag, raw)
+		if err != nil {
+			call.Errors = append(call.Errors, VariableError{Path: "--" + flag.Name, Message: err.Error()})
+			continue
+		}
+		setPath(call.Variables, flag.Path, value)
+	}
+	// Start required input objects empty, so that their missing fields are
+	// reported one by one
+	for _, arg := range def.Args {
+		if t := m.Resolve(arg.Type); t != nil && t.Kind == "INPUT_OBJECT" && arg.Required() && call.Variables[arg.Name] == nil {
+			call.Variables[arg.Name] = map[string]interface{}{}
+		}
+	}
+	var unknown []string
+	for name := range values {
+		if !slices.ContainsFunc(flags, func(f MutationFlag) bool { return f.Name == name }) {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		call.Errors = append(call.Errors, VariableError{Path: "--" + name, Message: fmt.Sprintf("mutation %q has no such flag", mutation)})
+	}
+
+	// Declare every argument that is given or required
+	var variables, arguments []string
+	for _, arg := range def.Args {
+		if _, given := call.Variables[arg.Name]; !given && !(arg.Type.IsNonNull() && arg.DefaultValue == nil) {
+			continue
+		}
+		variables = append(variables, fmt.Sprintf("$%s: %s", arg.Name, arg.Type))
+		arguments = append(arguments, fmt.Sprintf("%s: $%s", arg.Name, arg.Name))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "mutation %s", strings.ToUpper(def.Name[:1])+def.Name[1:])
+	if len(variables) > 0 {
+		fmt.Fprintf(&b, "(%s)", strings.Join(variables, ", "))
+	}
+	b.WriteString(" {\n  " + def.Name)
+	if len(arguments) > 0 {
+		fmt.Fprintf(&b, "(%s)", strings.Join(arguments, ", "))
+	}
+	if payload := m.Resolve(def.Type); payload != nil && len(payload.Fields) > 0 {
+		b.WriteString(" {\n")
+		m.writePayload(&b, payload, "    ")
+		b.WriteString("  }")
+	}
+	b.WriteString("\n}\n")
+	call.Query = b.String()
+
+	variablesJSON, err := yamlformat.MarshalJSON(call.Variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode variables: %w", err)
+	}
+	problems, err := s.ValidateVariables(call.Query, variablesJSON)
+	if err != nil {
+		return nil, err
+	}
+	// Point at the flags rather than the variables the user never wrote
+	for _, p := range problems {
+		path, _, _ := strings.Cut(p.Path, "[")
+		if name, ok := byPath[path]; ok {
+			p.Path = "--" + name
+		}
+		for _, flag := range flags {
+			container := "$" + strings.Join(flag.Path[:len(flag.Path)-1], ".")
+			if p.Path == container && strings.Contains(p.Message, fmt.Sprintf("requires field %q ", flag.Path[len(flag.Path)-1])) {
+				p = VariableError{Path: "--" + flag.Name, Message: fmt.Sprintf("required flag of type %s is not given", flag.Type)}
+				break
+			}
+		}
+		call.Errors = append(call.Errors, p)
+	}
+	return call, nil
+}
+
+// writePayload writes the selection of a mutation payload: its fields
+// without required arguments, scalars as they are and objects by id
+func (m *Model) writePayload(b *strings.Builder, payload *Type, indent string) {
+	for _, f := range payload.Fields {
+		if hasRequiredArg(f) || f.IsDeprecated {
+			continue
+		}
+		t := m.Resolve(f.Type)
+		if t == nil {
+			continue
+		}
+		switch {
+		case t.Kind == "SCALAR" || t.Kind == "ENUM":
+			b.WriteString(indent + f.Name + "\n")
+		case t.Field("id") != nil:
+			b.WriteString(indent + f.Name + " { id }\n")
+		default:
+			b.WriteString(indent + f.Name + " { __typename }\n")
+		}
+	}
+}
+
+func hasRequiredArg(f *Field) bool {
+	for _, arg := range f.Args {
+		if arg.Required() {
+			return true
+		}
+	}
+	return false
+}
+
+// flagValue converts the values given for a flag to a JSON value of its
+// type: a list for list flags, the last value otherwise
+func flagValue(m *Model, flag MutationFlag, raw []string) (interface{}, error) {
+	if !flag.List {
+		return scalarValue(m, flag, raw[len(raw)-1])
+	}
+	items := make([]interface{}, len(raw))
+	for i, r := range raw {
+		item, err := scalarValue(m, flag, r)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// scalarValue converts a single flag value to the JSON value of the named
+// type of flag
+func scalarValue(m *Model, flag MutationFlag, raw string) (interface{}, error) {
+	t := m.Type(strings.Trim(flag.Type, "[]!"))
+	switch {
+	case t == nil:
+		return raw, nil
+	case t.Name == "Int":
+		n, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return raw, fmt.Errorf("%q is not an Int", raw)
+		}
+		return n, nil
+	case t.Name == "Float":
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return raw, fmt.Errorf("%q is not a Float", raw)
+		}
+		return f, nil
+	case t.Name == "Boolean":
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return raw, fmt.Errorf("%q is not a Boolean", raw)
+		}
+		return v, nil
+	}
+	return raw, nil
+}
+
+// setPath sets the value at path in nested maps, creating them as needed
+func setPath(m map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[key] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = value
+}
+
+// kebabCase converts a GraphQL name such as "subjectId" to "subject-id";
+// acronyms stay together, as in "pullRequestURL" to "pull-request-url"
+func kebabCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			lowerBefore := i > 0 && !unicode.IsUpper(runes[i-1])
+			acronymEnd := i > 0 && i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])
+			if lowerBefore || acronymEnd {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// firstLine returns the first line of a description
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
@@ -0,0 +1,124 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMutationFlags(t *testing.T) {
+	s := loadRichSchema(t)
+
+	flags, err := s.MutationFlags("createIssue")
+	if err != nil {
+		t.Fatalf("MutationFlags failed: %v", err)
+	}
+	byName := make(map[string]MutationFlag)
+	var names []string
+	for _, f := range flags {
+		byName[f.Name] = f
+		names = append(names, f.Name)
+	}
+	want := []string{
+		"repository-id", "title", "body", "label-ids", "metadata-priority", "metadata-state",
+		"metadata-parent-id", "metadata-parent-number", "metadata-parent-url", "client-mutation-id",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Flags = %v, want %v", names, want)
+	}
+	if f := byName["repository-id"]; !f.Required || f.Type != "ID!" || !reflect.DeepEqual(f.Path, []string{"input", "repositoryId"}) {
+		t.Errorf("repository-id = %+v", f)
+	}
+	if f := byName["label-ids"]; !f.List || f.Required {
+		t.Errorf("label-ids = %+v", f)
+	}
+	if f := byName["metadata-state"]; f.Kind != "ENUM" || len(f.EnumValues) == 0 || f.Required {
+		t.Errorf("metadata-state = %+v", f)
+	}
+	if f := byName["metadata-parent-number"]; !reflect.DeepEqual(f.Path, []string{"input", "metadata", "parent", "number"}) {
+		t.Errorf("metadata-parent-number = %+v", f)
+	}
+
+	if _, err := s.MutationFlags("createIssues"); err == nil || !strings.Contains(err.Error(), "createIssue") {
+		t.Errorf("Expected a not found error suggesting createIssue, got %v", err)
+	}
+}
+
+func TestNewMutationCall(t *testing.T) {
+	s := loadRichSchema(t)
+
+	call, err := s.NewMutationCall("createIssue", map[string][]string{
+		"repository-id":     {"R_1"},
+		"title":             {"first", "second"},
+		"label-ids":         {"L_1", "L_2"},
+		"metadata-priority": {"2"},
+	})
+	if err != nil {
+		t.Fatalf("NewMutationCall failed: %v", err)
+	}
+	if len(call.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", call.Errors)
+	}
+	wantQuery := `mutation CreateIssue($input: CreateIssueInput!) {
+  createIssue(input: $input) {
+    clientMutationId
+    issue { id }
+  }
+}
+`
+	if call.Query != wantQuery {
+		t.Errorf("Query:\n%s\nwant:\n%s", call.Query, wantQuery)
+	}
+	wantVariables := map[string]interface{}{
+		"input": map[string]interface{}{
+			"repositoryId": "R_1",
+			"title":        "second",
+			"labelIds":     []interface{}{"L_1", "L_2"},
+			"metadata":     map[string]interface{}{"priority": int64(2)},
+		},
+	}
+	if !reflect.DeepEqual(call.Variables, wantVariables) {
+		t.Errorf("Variables = %#v, want %#v", call.Variables, wantVariables)
+	}
+}
+
+func TestNewMutationCallErrors(t *testing.T) {
+	s := loadRichSchema(t)
+
+	call, err := s.NewMutationCall("createIssue", map[string][]string{
+		"title":             {"t"},
+		"metadata-priority": {"high"},
+		"metadata-state":    {"MERGED"},
+		"assignee":          {"octocat"},
+	})
+	if err != nil {
+		t.Fatalf("NewMutationCall failed: %v", err)
+	}
+	var got []string
+	for _, e := range call.Errors {
+		got = append(got, e.Error())
+	}
+	want := []string{
+		`--metadata-priority: "high" is not an Int`,
+		`--assignee: mutation "createIssue" has no such flag`,
+		`--repository-id: required flag of type ID! is not given`,
+		`--metadata-state: expected value of type IssueState, found "MERGED"`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Errors:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestKebabCase(t *testing.T) {
+	for name, want := range map[string]string{
+		"subjectId":        "subject-id",
+		"body":             "body",
+		"pullRequestURL":   "pull-request-url",
+		"URLTemplate":      "url-template",
+		"clientMutationId": "client-mutation-id",
+	} {
+		if got := kebabCase(name); got != want {
+			t.Errorf("kebabCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
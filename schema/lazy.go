@@ -0,0 +1,689 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+// LazySchema provides on-demand access to a schema document. A single scan
+// over the raw JSON builds a byte-offset index of the types array, and
+// individual type entries are parsed only when they are requested, so looking
+// up one type never pays for parsing the whole schema.
+//
+// Unlike Schema, a LazySchema retains the raw document, which must not be
+// modified while the LazySchema is in use.
+type LazySchema struct {
+	data         []byte
+	index        map[string]lazyEntry
+	names        []string
+	queryType    string
+	mutationType string
+
+	mu     sync.Mutex
+	parsed map[string]map[string]interface{}
+	unmap  func() error
+}
+
+// lazyEntry locates one element of the types array within the raw document
+type lazyEntry struct {
+	position   int // index in the types array
+	start, end int // byte range of the JSON object
+}
+
+// NewLazy creates a LazySchema using the embedded schema
+func NewLazy() (*LazySchema, error) {
+	buf, err := decompress(embeddedSchema)
+	if err != nil {
+		return nil, err
+	}
+	// The lazy schema keeps referencing the data, so copy it out of the pooled buffer
+	data := append([]byte(nil), buf.Bytes()...)
+	putBuffer(buf)
+
+	return NewLazyWithData(data)
+}
+
+// defaultLazy indexes the embedded schema once for DefaultLazy
+var defaultLazy = sync.OnceValues(NewLazy)
+
+// DefaultLazy returns a LazySchema of the embedded schema, indexed on the first
+// call and shared by every later call. Types are still parsed only when they
+// are requested, and only once across all callers, which suits short-lived
+// commands that look up a few types.
+func DefaultLazy() (*LazySchema, error) {
+	return defaultLazy()
+}
+
+// NewLazyWithFile creates a LazySchema from a file
+func NewLazyWithFile(path string) (*LazySchema, error) {
+	slog.Debug("Loading lazy schema from file", "path", path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+
+	return NewLazyWithData(data)
+}
+
+// NewLazyWithMmap creates a LazySchema backed by a read-only memory mapping of
+// an uncompressed JSON schema file. Only the index and the types that are
+// actually requested are copied onto the heap, which keeps the resident set
+// small for very large custom schemas. The caller must call Close to release
+// the mapping. On platforms without mmap support the file is read into memory.
+func NewLazyWithMmap(path string) (*LazySchema, error) {
+	slog.Debug("Mapping lazy schema from file", "path", path)
+
+	data, unmap, err := mmapFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map schema file: %w", err)
+	}
+	if c := detectCompression(data); c != NoCompression {
+		unmap()
+		return nil, fmt.Errorf("failed to map schema file: %s is %s compressed; decompress it first", path, c)
+	}
+
+	l, err := NewLazyWithData(data)
+	if err != nil {
+		unmap()
+		return nil, err
+	}
+	l.unmap = unmap
+	return l, nil
+}
+
+// Close releases the memory mapping of a LazySchema created by NewLazyWithMmap.
+// Types already returned by RawType remain valid; further lookups fail.
+// Close is a no-op for other LazySchema instances.
+func (l *LazySchema) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.unmap == nil {
+		return nil
+	}
+	err := l.unmap()
+	l.unmap = nil
+	l.data = nil
+	l.index = map[string]lazyEntry{}
+	return err
+}
+
+// NewLazyWithData creates a LazySchema from raw JSON data, indexing the types
+// array without parsing its entries. Gzip and zstd compressed data is
+// decompressed into a copy first. An SDL document is converted into an
+// introspection result first, so it is parsed in full.
+func NewLazyWithData(data []byte) (*LazySchema, error) {
+	if detectCompression(data) != NoCompression {
+		buf, err := decompress(data)
+		if err != nil {
+			return nil, invalidSchema("%w", err)
+		}
+		// The lazy schema keeps referencing the data, so copy it out of the pooled buffer
+		data = append([]byte(nil), buf.Bytes()...)
+		putBuffer(buf)
+	}
+	if isSDL(data) {
+		doc, err := parseSDL(data)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = yamlformat.MarshalJSON(doc); err != nil {
+			return nil, fmt.Errorf("failed to encode schema: %w", err)
+		}
+	}
+
+	l := &LazySchema{
+		data:   data,
+		index:  make(map[string]lazyEntry),
+		parsed: make(map[string]map[string]interface{}),
+	}
+	if err := l.buildIndex(); err != nil {
+		return nil, invalidSchema("failed to index schema: %w", err)
+	}
+
+	slog.Debug("Indexed lazy schema", "types", len(l.names), "size", len(data))
+	return l, nil
+}
+
+// TypeNames returns the names of all types in document order
+func (l *LazySchema) TypeNames() []string {
+	return append([]string(nil), l.names...)
+}
+
+// RawType parses and returns the introspection entry of a single type.
+// Parsed entries are cached, and the returned map must not be modified.
+func (l *LazySchema) RawType(name string) (map[string]interface{}, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if t, ok := l.parsed[name]; ok {
+		return t, nil
+	}
+
+	entry, ok := l.index[name]
+	if !ok || l.data == nil {
+		return nil, &NotFoundError{Kind: "type", Name: name, Suggestions: suggestNames(name, l.names)}
+	}
+
+	var t map[string]interface{}
+	if err := yamlformat.Unmarshal(l.data[entry.start:entry.end], &t); err != nil {
+		return nil, invalidSchema("failed to parse type %q at $.data.__schema.types[%d]: %w", name, entry.position, err)
+	}
+	l.parsed[name] = t
+	return t, nil
+}
+
+// has reports whether the index holds a type, taking the lock because Close
+// replaces the index
+func (l *LazySchema) has(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.index[name]
+	return ok
+}
+
+// Type queries information about a GraphQL type, parsing only that type.
+// The result has the same shape as Schema.Type.
+func (l *LazySchema) Type(typeName string) (map[string]interface{}, error) {
+	if !l.has(typeName) {
+		return nil, &NotFoundError{Kind: "type", Name: typeName, Suggestions: suggestNames(typeName, l.names)}
+	}
+
+	sub, err := l.subset(typeName)
+	if err != nil {
+		return nil, err
+	}
+	return sub.Type(typeName)
+}
+
+// EnumValues returns the values of an enum type, parsing only that type
+func (l *LazySchema) EnumValues(enumName string) ([]EnumValueInfo, error) {
+	if !l.has(enumName) {
+		return nil, &NotFoundError{Kind: "type", Name: enumName, Suggestions: suggestNames(enumName, l.names)}
+	}
+
+	sub, err := l.subset(enumName)
+	if err != nil {
+		return nil, err
+	}
+	return sub.EnumValues(enumName)
+}
+
+// Implementers returns the names of the object types implementing an
+// interface, parsing only the interface type
+func (l *LazySchema) Implementers(interfaceName string) ([]string, error) {
+	if !l.has(interfaceName) {
+		return nil, &NotFoundError{Kind: "type", Name: interfaceName, Suggestions: suggestNames(interfaceName, l.names)}
+	}
+
+	sub, err := l.subset(interfaceName)
+	if err != nil {
+		return nil, err
+	}
+	return sub.Implementers(interfaceName)
+}
+
+// UnionMembers returns the names of the possible types of a union, parsing
+// only the union type
+func (l *LazySchema) UnionMembers(unionName string) ([]string, error) {
+	if !l.has(unionName) {
+		return nil, &NotFoundError{Kind: "type", Name: unionName, Suggestions: suggestNames(unionName, l.names)}
+	}
+
+	sub, err := l.subset(unionName)
+	if err != nil {
+		return nil, err
+	}
+	return sub.UnionMembers(unionName)
+}
+
+// Mutation queries information about a GraphQL mutation, parsing only the
+// mutation root and the mutation's input type. The result has the same shape
+// as Schema.Mutation.
+func (l *LazySchema) Mutation(mutationName string) (map[string]interface{}, error) {
+	if !l.has(l.mutationType) {
+		return nil, &NotFoundError{Kind: "mutation", Name: mutationName}
+	}
+	root, err := l.RawType(l.mutationType)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{l.mutationType}
+	fields, _ := root["fields"].([]interface{})
+	for _, f := range fields {
+		field, _ := f.(map[string]interface{})
+		if field["name"] != mutationName {
+			continue
+		}
+		// Mirror mutationQuery: the first argument's unwrapped type holds the input fields
+		if args, _ := field["args"].([]interface{}); len(args) > 0 {
+			arg, _ := args[0].(map[string]interface{})
+			argType, _ := arg["type"].(map[string]interface{})
+			ofType, _ := argType["ofType"].(map[string]interface{})
+			if input, ok := ofType["name"].(string); ok {
+				if l.has(input) {
+					names = append(names, input)
+				}
+			}
+		}
+		break
+	}
+
+	sub, err := l.subset(names...)
+	if err != nil {
+		return nil, err
+	}
+	return sub.Mutation(mutationName)
+}
+
+// QueryField queries information about a field on the Query root, parsing
+// only the root type. The result has the same shape as Schema.QueryField.
+func (l *LazySchema) QueryField(fieldName string) (map[string]interface{}, error) {
+	root := l.queryType
+	if root == "" {
+		root = "Query"
+	}
+	if !l.has(root) {
+		return nil, &NotFoundError{Kind: "field", Name: fieldName, Parent: root}
+	}
+
+	sub, err := l.subset(root)
+	if err != nil {
+		return nil, err
+	}
+	return sub.QueryField(fieldName)
+}
+
+// Schema parses the whole document and returns a fully materialized Schema
+func (l *LazySchema) Schema() (*Schema, error) {
+	// Copy under the lock, since Close may unmap the data while it is parsed
+	l.mu.Lock()
+	data := bytes.Clone(l.data)
+	l.mu.Unlock()
+	if data == nil {
+		return nil, invalidSchema("schema is closed")
+	}
+	return NewWithData(data)
+}
+
+// subset builds a Schema containing only the named types, so the predefined
+// queries can run against it unchanged
+func (l *LazySchema) subset(names ...string) (*Schema, error) {
+	types := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		t, err := l.RawType(name)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+
+	schema := map[string]interface{}{"types": types}
+	if l.queryType != "" {
+		schema["queryType"] = map[string]interface{}{"name": l.queryType}
+	}
+	if l.mutationType != "" {
+		schema["mutationType"] = map[string]interface{}{"name": l.mutationType}
+	}
+	return &Schema{data: map[string]interface{}{
+		"data": map[string]interface{}{"__schema": schema},
+	}}, nil
+}
+
+// buildIndex walks the document structure down to the types array and
+// records the byte range and name of every type entry
+func (l *LazySchema) buildIndex() error {
+	sc := &jsonScanner{data: l.data}
+
+	schema := func() error {
+		return sc.eachKey(func(key string) error {
+			switch key {
+			case "types":
+				return l.indexTypes(sc)
+			case "queryType", "mutationType":
+				name, err := sc.nameOfObject()
+				if err != nil {
+					return err
+				}
+				if key == "queryType" {
+					l.queryType = name
+				} else {
+					l.mutationType = name
+				}
+				return nil
+			default:
+				return sc.skipValue()
+			}
+		})
+	}
+	// Bare {"__schema": ...} documents are accepted as well
+	err := sc.eachKey(func(key string) error {
+		switch key {
+		case "__schema":
+			return schema()
+		case "data":
+			if c, err := sc.peek(); err == nil && c == 'n' {
+				return sc.skipValue()
+			}
+			return sc.eachKey(func(key string) error {
+				if key != "__schema" {
+					return sc.skipValue()
+				}
+				return schema()
+			})
+		default:
+			return sc.skipValue()
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if l.names == nil {
+		return fmt.Errorf("no types array found at $.data.__schema.types")
+	}
+	if l.mutationType == "" {
+		l.mutationType = "Mutation"
+	}
+	return nil
+}
+
+func (l *LazySchema) indexTypes(sc *jsonScanner) error {
+	l.names = []string{}
+	return sc.eachElement(func(i int) error {
+		sc.skipSpace()
+		start := sc.pos
+		name, err := sc.nameOfObject()
+		if err != nil {
+			return fmt.Errorf("types[%d]: %w", i, err)
+		}
+		if _, dup := l.index[name]; !dup && name != "" {
+			l.index[name] = lazyEntry{position: i, start: start, end: sc.pos}
+			l.names = append(l.names, name)
+		}
+		return nil
+	})
+}
+
+// jsonScanner is a minimal forward-only JSON tokenizer that can skip values
+// without materializing them
+type jsonScanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *jsonScanner) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("offset %d: %s", s.pos, fmt.Sprintf(format, args...))
+}
+
+func (s *jsonScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *jsonScanner) consume(c byte) error {
+	s.skipSpace()
+	if s.pos >= len(s.data) {
+		return s.errorf("unexpected end of input, expected %q", c)
+	}
+	if s.data[s.pos] != c {
+		return s.errorf("expected %q, got %q", c, s.data[s.pos])
+	}
+	s.pos++
+	return nil
+}
+
+// peek returns the next non-space byte without consuming it
+func (s *jsonScanner) peek() (byte, error) {
+	s.skipSpace()
+	if s.pos >= len(s.data) {
+		return 0, s.errorf("unexpected end of input")
+	}
+	return s.data[s.pos], nil
+}
+
+// eachKey iterates over the members of an object, calling fn with the scanner
+// positioned at each value. fn must consume the value.
+func (s *jsonScanner) eachKey(fn func(key string) error) error {
+	if err := s.consume('{'); err != nil {
+		return err
+	}
+	if c, err := s.peek(); err != nil {
+		return err
+	} else if c == '}' {
+		s.pos++
+		return nil
+	}
+	for {
+		key, err := s.readString()
+		if err != nil {
+			return err
+		}
+		if err := s.consume(':'); err != nil {
+			return err
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+		c, err := s.peek()
+		if err != nil {
+			return err
+		}
+		s.pos++
+		switch c {
+		case ',':
+		case '}':
+			return nil
+		default:
+			return s.errorf("expected ',' or '}', got %q", c)
+		}
+	}
+}
+
+// eachElement iterates over the elements of an array, calling fn with the
+// scanner positioned at each element. fn must consume the element.
+func (s *jsonScanner) eachElement(fn func(i int) error) error {
+	if err := s.consume('['); err != nil {
+		return err
+	}
+	if c, err := s.peek(); err != nil {
+		return err
+	} else if c == ']' {
+		s.pos++
+		return nil
+	}
+	for i := 0; ; i++ {
+		if err := fn(i); err != nil {
+			return err
+		}
+		c, err := s.peek()
+		if err != nil {
+			return err
+		}
+		s.pos++
+		switch c {
+		case ',':
+		case ']':
+			return nil
+		default:
+			return s.errorf("expected ',' or ']', got %q", c)
+		}
+	}
+}
+
+// nameOfObject consumes an object (or null) and returns its top-level "name" string
+func (s *jsonScanner) nameOfObject() (string, error) {
+	if c, err := s.peek(); err != nil {
+		return "", err
+	} else if c == 'n' {
+		return "", s.skipValue()
+	}
+	var name string
+	err := s.eachKey(func(key string) error {
+		if key == "name" {
+			if c, err := s.peek(); err == nil && c == '"' {
+				n, err := s.readString()
+				name = n
+				return err
+			}
+		}
+		return s.skipValue()
+	})
+	return name, err
+}
+
+// skipValue consumes one JSON value of any type
+func (s *jsonScanner) skipValue() error {
+	c, err := s.peek()
+	if err != nil {
+		return err
+	}
+	switch c {
+	case '"':
+		_, err := s.stringEnd()
+		return err
+	case '{', '[':
+		return s.skipContainer()
+	default:
+		start := s.pos
+		for s.pos < len(s.data) {
+			switch s.data[s.pos] {
+			case ',', '}', ']', ' ', '\t', '\n', '\r':
+				if s.pos == start {
+					return s.errorf("unexpected %q", s.data[s.pos])
+				}
+				return nil
+			}
+			s.pos++
+		}
+		return nil
+	}
+}
+
+// skipContainer consumes a balanced object or array without recursion
+func (s *jsonScanner) skipContainer() error {
+	depth := 0
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '"':
+			if _, err := s.stringEnd(); err != nil {
+				return err
+			}
+			continue
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				s.pos++
+				return nil
+			}
+		}
+		s.pos++
+	}
+	return s.errorf("unexpected end of input in object or array")
+}
+
+// stringEnd consumes a string and reports whether it contained escapes
+func (s *jsonScanner) stringEnd() (escaped bool, err error) {
+	s.pos++ // opening quote
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '\\':
+			escaped = true
+			s.pos += 2
+			continue
+		case '"':
+			s.pos++
+			return escaped, nil
+		}
+		s.pos++
+	}
+	return false, s.errorf("unterminated string")
+}
+
+// readString consumes a string and returns its decoded value
+func (s *jsonScanner) readString() (string, error) {
+	if c, err := s.peek(); err != nil {
+		return "", err
+	} else if c != '"' {
+		return "", s.errorf("expected string, got %q", c)
+	}
+	start := s.pos
+	escaped, err := s.stringEnd()
+	if err != nil {
+		return "", err
+	}
+	raw := s.data[start+1 : s.pos-1]
+	if !escaped {
+		return string(raw), nil
+	}
+	return unescapeJSONString(raw)
+}
+
+// unescapeJSONString decodes the escape sequences of a JSON string body
+func unescapeJSONString(raw []byte) (string, error) {
+	out := make([]rune, 0, len(raw))
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if c != '\\' {
+			r, size := utf8.DecodeRune(raw[i:])
+			out = append(out, r)
+			i += size - 1
+			continue
+		}
+		i++
+		if i >= len(raw) {
+			return "", fmt.Errorf("invalid escape at end of string")
+		}
+		switch raw[i] {
+		case '"', '\\', '/':
+			out = append(out, rune(raw[i]))
+		case 'b':
+			out = append(out, '\b')
+		case 'f':
+			out = append(out, '\f')
+		case 'n':
+			out = append(out, '\n')
+		case 'r':
+			out = append(out, '\r')
+		case 't':
+			out = append(out, '\t')
+		case 'u':
+			if i+4 >= len(raw) {
+				return "", fmt.Errorf("invalid unicode escape")
+			}
+			v, err := strconv.ParseUint(string(raw[i+1:i+5]), 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape: %w", err)
+			}
+			r := rune(v)
+			i += 4
+			if utf16.IsSurrogate(r) && i+6 < len(raw) && raw[i+1] == '\\' && raw[i+2] == 'u' {
+				if v2, err := strconv.ParseUint(string(raw[i+3:i+7]), 16, 16); err == nil {
+					r = utf16.DecodeRune(r, rune(v2))
+					i += 6
+				}
+			}
+			out = append(out, r)
+		default:
+			return "", fmt.Errorf("invalid escape %q", raw[i])
+		}
+	}
+	return string(out), nil
+}
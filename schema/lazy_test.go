@@ -0,0 +1,294 @@
+package schema
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLazySchemaMatchesSchema(t *testing.T) {
+	s, err := NewWithData(testSchemaData)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	l, err := NewLazyWithData(testSchemaData)
+	if err != nil {
+		t.Fatalf("Failed to create lazy schema: %v", err)
+	}
+
+	wantNames := []string{"PullRequest", "Issue", "CreateIssueInput", "Mutation"}
+	if got := l.TypeNames(); !reflect.DeepEqual(got, wantNames) {
+		t.Errorf("TypeNames() = %v, want %v", got, wantNames)
+	}
+
+	for _, name := range []string{"PullRequest", "CreateIssueInput"} {
+		want, err := s.Type(name)
+		if err != nil {
+			t.Fatalf("Type(%s) failed: %v", name, err)
+		}
+		got, err := l.Type(name)
+		if err != nil {
+			t.Fatalf("lazy Type(%s) failed: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("lazy Type(%s) = %v, want %v", name, got, want)
+		}
+	}
+
+	want, err := s.Mutation("createIssue")
+	if err != nil {
+		t.Fatalf("Mutation failed: %v", err)
+	}
+	got, err := l.Mutation("createIssue")
+	if err != nil {
+		t.Fatalf("lazy Mutation failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lazy Mutation = %v, want %v", got, want)
+	}
+
+	if _, err := l.Type("NonExistent"); err == nil {
+		t.Error("Expected error for non-existent type")
+	}
+	if _, err := l.Mutation("nonExistent"); err == nil {
+		t.Error("Expected error for non-existent mutation")
+	}
+}
+
+func TestLazySchemaAccessors(t *testing.T) {
+	l, err := NewLazyWithData(syntheticData(t))
+	if err != nil {
+		t.Fatalf("Failed to create lazy schema: %v", err)
+	}
+
+	want, err := loadRichSchema(t).EnumValues("IssueState")
+	if err != nil {
+		t.Fatalf("EnumValues failed: %v", err)
+	}
+	got, err := l.EnumValues("IssueState")
+	if err != nil {
+		t.Fatalf("lazy EnumValues failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lazy EnumValues = %+v, want %+v", got, want)
+	}
+	if _, err := l.EnumValues("NonExistent"); err == nil {
+		t.Error("Expected error for non-existent type")
+	}
+
+	implementers, err := l.Implementers("Actor")
+	if err != nil {
+		t.Fatalf("lazy Implementers failed: %v", err)
+	}
+	if !reflect.DeepEqual(implementers, []string{"User"}) {
+		t.Errorf("lazy Implementers(Actor) = %v", implementers)
+	}
+
+	members, err := l.UnionMembers("IssueOrPullRequest")
+	if err != nil {
+		t.Fatalf("lazy UnionMembers failed: %v", err)
+	}
+	if !reflect.DeepEqual(members, []string{"Issue", "PullRequest"}) {
+		t.Errorf("lazy UnionMembers(IssueOrPullRequest) = %v", members)
+	}
+
+	wantField, err := loadRichSchema(t).QueryField("repository")
+	if err != nil {
+		t.Fatalf("QueryField failed: %v", err)
+	}
+	gotField, err := l.QueryField("repository")
+	if err != nil {
+		t.Fatalf("lazy QueryField failed: %v", err)
+	}
+	if !reflect.DeepEqual(gotField, wantField) {
+		t.Errorf("lazy QueryField = %v, want %v", gotField, wantField)
+	}
+}
+
+func TestLazySchemaParsesOnDemand(t *testing.T) {
+	l, err := NewLazyWithData(testSchemaData)
+	if err != nil {
+		t.Fatalf("Failed to create lazy schema: %v", err)
+	}
+	if _, err := l.Type("Issue"); err != nil {
+		t.Fatalf("Type failed: %v", err)
+	}
+	if len(l.parsed) != 1 {
+		t.Errorf("Expected exactly 1 parsed type, got %d", len(l.parsed))
+	}
+}
+
+func TestDefaultLazy(t *testing.T) {
+	l, err := DefaultLazy()
+	if err != nil {
+		t.Fatalf("Failed to load default lazy schema: %v", err)
+	}
+	again, err := DefaultLazy()
+	if err != nil || again != l {
+		t.Errorf("DefaultLazy() returned %p and %p (%v), want the same instance", l, again, err)
+	}
+	if _, err := l.Type("Repository"); err != nil {
+		t.Errorf("Type failed: %v", err)
+	}
+}
+
+func TestNewLazyWithDataInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"truncated", `{"data": {"__schema": {"types": [{"name": "Query", "kind": "OB`},
+		{"no types", `{"data": {"__schema": {}}}`},
+		{"not an object", `[1, 2, 3]`},
+		{"unterminated container", `{"data": {"__schema": {"types": [{"name": "Query", "fields": [}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewLazyWithData([]byte(tt.data)); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestUnescapeJSONString(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{`plain`, "plain"},
+		{`quote \" and \\ slash \/`, `quote " and \ slash /`},
+		{`line\nbreak\ttab`, "line\nbreak\ttab"},
+		{`\u00e9t\u00e9`, "\u00e9t\u00e9"},
+		{`emoji \ud83d\ude00`, "emoji \U0001F600"},
+		{"raw \u00fcnicode", "raw \u00fcnicode"},
+	}
+	for _, tt := range tests {
+		got, err := unescapeJSONString([]byte(tt.raw))
+		if err != nil {
+			t.Errorf("unescapeJSONString(%q) failed: %v", tt.raw, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("unescapeJSONString(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkLazyType(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l, err := NewLazy()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := l.Type("Issue"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestNewLazyWithMmap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, testSchemaData, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := NewLazyWithMmap(path)
+	if err != nil {
+		t.Fatalf("NewLazyWithMmap failed: %v", err)
+	}
+	result, err := l.Type("PullRequest")
+	if err != nil {
+		t.Fatalf("Type failed: %v", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Results parsed before Close must stay valid after the mapping is released
+	if name := result["type"].(map[string]interface{})["name"]; name != "PullRequest" {
+		t.Errorf("Expected PullRequest, got %v", name)
+	}
+	if _, err := l.RawType("Issue"); err == nil {
+		t.Error("Expected error after Close")
+	}
+}
+
+func TestLazySchemaCloseConcurrently(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, testSchemaData, 0644); err != nil {
+		t.Fatal(err)
+	}
+	l, err := NewLazyWithMmap(path)
+	if err != nil {
+		t.Fatalf("NewLazyWithMmap failed: %v", err)
+	}
+
+	// Lookups racing with Close either succeed or fail, run with -race
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.Type("PullRequest")
+			l.EnumValues("PullRequestState")
+			l.Implementers("Node")
+			l.UnionMembers("IssueOrPullRequest")
+			l.Mutation("addStar")
+			l.QueryField("repository")
+			l.Schema()
+		}()
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	wg.Wait()
+}
+
+func TestNewLazyWithMmapRejectsGzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json.gz")
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(testSchemaData)
+	gz.Close()
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewLazyWithMmap(path); err == nil || !strings.Contains(err.Error(), "gzip") {
+		t.Errorf("Expected gzip error, got %v", err)
+	}
+}
+
+func TestNewLazyWithDataSDL(t *testing.T) {
+	l, err := NewLazyWithData([]byte("type Query { viewer: User }\n\"A user.\"\ntype User { login: String! }"))
+	if err != nil {
+		t.Fatalf("Failed to load SDL: %v", err)
+	}
+	user, err := l.RawType("User")
+	if err != nil {
+		t.Fatalf("Failed to look up User: %v", err)
+	}
+	if user["description"] != "A user." {
+		t.Errorf("Expected the description of User, got %v", user["description"])
+	}
+}
+
+func TestNewLazyWithDataBare(t *testing.T) {
+	l, err := NewLazyWithData([]byte(`{"__schema": {"queryType": {"name": "Query"}, "types": [{"kind": "OBJECT", "name": "Query", "fields": []}]}}`))
+	if err != nil {
+		t.Fatalf("Failed to load a bare __schema document: %v", err)
+	}
+	if _, err := l.RawType("Query"); err != nil {
+		t.Errorf("Failed to look up Query: %v", err)
+	}
+	if _, err := NewLazyWithData([]byte(`{"data": null, "errors": [{"message": "Bad credentials"}]}`)); err == nil {
+		t.Error("Expected an error for a response without a schema")
+	}
+}
@@ -0,0 +1,95 @@
+package schema
+
+import "slices"
+
+// rawTypes returns the introspection entries of all types keyed by name.
+// The index is built on first use and shared by all native lookups.
+func (s *Schema) rawTypes() map[string]map[string]interface{} {
+	s.indexOnce.Do(func() {
+		s.index = make(map[string]map[string]interface{})
+		root, _ := s.tree().(map[string]interface{})
+		data, _ := root["data"].(map[string]interface{})
+		schema, _ := data["__schema"].(map[string]interface{})
+		types, _ := schema["types"].([]interface{})
+		for _, t := range types {
+			entry, ok := t.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if name, ok := entry["name"].(string); ok {
+				if _, dup := s.index[name]; !dup {
+					s.index[name] = entry
+				}
+			}
+		}
+	})
+	return s.index
+}
+
+// rawType returns the introspection entry of the named type, or nil
+func (s *Schema) rawType(name string) map[string]interface{} {
+	return s.rawTypes()[name]
+}
+
+// isSubtype reports whether values of type sub are valid where type super is
+// expected: the same type, a member of a union, or an implementation of an interface
+func (s *Schema) isSubtype(sub, super string) bool {
+	if sub == super {
+		return true
+	}
+	m := s.Model()
+	if t := m.Type(super); t != nil && slices.Contains(t.PossibleTypes, sub) {
+		return true
+	}
+	// Interfaces may implement other interfaces, which possibleTypes does not list
+	t := m.Type(sub)
+	return t != nil && slices.Contains(t.Interfaces, super)
+}
+
+// RootTypeName returns the name of the root type for an operation kind
+// ("query", "mutation", or "subscription"), or "" if the schema has none
+func (s *Schema) RootTypeName(operation string) string {
+	root, _ := s.tree().(map[string]interface{})
+	data, _ := root["data"].(map[string]interface{})
+	schema, _ := data["__schema"].(map[string]interface{})
+	ref, _ := schema[operation+"Type"].(map[string]interface{})
+	name, _ := ref["name"].(string)
+	return name
+}
+
+// FieldType returns the type of a field of an object or interface type.
+// The reference is shared with the Model and must not be modified.
+func (s *Schema) FieldType(typeName, fieldName string) (*TypeRef, error) {
+	m := s.Model()
+	t := m.Type(typeName)
+	if t == nil {
+		return nil, m.typeNotFound(typeName)
+	}
+	field := t.Field(fieldName)
+	if field == nil {
+		return nil, fieldNotFound(t, fieldName)
+	}
+	return field.Type, nil
+}
+
+// Resolve returns the named type that ref refers to, such as Issue for a
+// field of type "[Issue!]!", with a NotFoundError when the schema has no such
+// type. The type is shared with the Model and must not be modified.
+func (s *Schema) Resolve(ref *TypeRef) (*Type, error) {
+	m := s.Model()
+	if t := m.Resolve(ref); t != nil {
+		return t, nil
+	}
+	return nil, m.typeNotFound(ref.NamedType())
+}
+
+// rawField returns the introspection entry of a field of the named type, or nil
+func (s *Schema) rawField(typeName, fieldName string) map[string]interface{} {
+	fields, _ := s.rawType(typeName)["fields"].([]interface{})
+	for _, f := range fields {
+		if field, ok := f.(map[string]interface{}); ok && field["name"] == fieldName {
+			return field
+		}
+	}
+	return nil
+}
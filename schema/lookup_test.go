@@ -0,0 +1,31 @@
+package schema
+
+import (
+	"testing"
+)
+
+func TestFieldType(t *testing.T) {
+	s := loadRichSchema(t)
+
+	ref, err := s.FieldType("Repository", "issues")
+	if err != nil {
+		t.Fatalf("FieldType failed: %v", err)
+	}
+	if got := ref.String(); got != "IssueConnection!" {
+		t.Errorf("FieldType(Repository.issues) = %s, want IssueConnection!", got)
+	}
+
+	if _, err := s.FieldType("Repository", "nope"); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+	if _, err := s.FieldType("Robot", "id"); err == nil {
+		t.Error("Expected error for unknown type")
+	}
+
+	if got := s.RootTypeName("mutation"); got != "Mutation" {
+		t.Errorf("RootTypeName(mutation) = %q", got)
+	}
+	if got := s.RootTypeName("subscription"); got != "" {
+		t.Errorf("RootTypeName(subscription) = %q, want empty", got)
+	}
+}
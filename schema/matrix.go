@@ -0,0 +1,124 @@
+package schema
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// MatrixFormat selects how WriteInputMatrix renders a matrix
+type MatrixFormat string
+
+const (
+	// MatrixTable renders aligned plain text columns
+	MatrixTable MatrixFormat = "table"
+
+	// MatrixMarkdown renders a GitHub Flavored Markdown table
+	MatrixMarkdown MatrixFormat = "markdown"
+)
+
+// InputMatrixRow is one field of an input object matrix
+type InputMatrixRow struct {
+	Field      string `json:"field"` // Dotted path for fields of nested input objects
+	Type       string `json:"type"`
+	Required   bool   `json:"required"`
+	Default    string `json:"default,omitempty"`
+	Deprecated string `json:"deprecated,omitempty"` // Deprecation reason, or "yes" without one
+	OneOfGroup string `json:"oneOfGroup,omitempty"` // Input object of which exactly one field must be set
+}
+
+// MatrixRows flattens an input object into one row per field, with the fields
+// of expanded nested input objects following their parent field
+func (info *InputObjectInfo) MatrixRows() []InputMatrixRow {
+	var rows []InputMatrixRow
+	info.appendRows(&rows, "")
+	return rows
+}
+
+func (info *InputObjectInfo) appendRows(rows *[]InputMatrixRow, prefix string) {
+	for _, f := range info.Fields {
+		row := InputMatrixRow{
+			Field:    prefix + f.Name,
+			Type:     f.Type,
+			Required: f.Required,
+			Default:  f.DefaultValue,
+		}
+		if f.IsDeprecated {
+			row.Deprecated = f.DeprecationReason
+			if row.Deprecated == "" {
+				row.Deprecated = "yes"
+			}
+		}
+		if info.OneOf {
+			row.OneOfGroup = info.Name
+		}
+		*rows = append(*rows, row)
+		if f.Input != nil {
+			f.Input.appendRows(rows, row.Field+".")
+		}
+	}
+}
+
+// WriteInputMatrix writes an input object as a matrix of its fields by type,
+// requiredness, default value, deprecation, and oneOf group, the summary
+// reviewers ask for before approving automation that calls a mutation
+func WriteInputMatrix(w io.Writer, info *InputObjectInfo, format MatrixFormat) error {
+	rows := info.MatrixRows()
+	switch format {
+	case MatrixTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "FIELD\tTYPE\tREQUIRED\tDEFAULT\tDEPRECATED\tONEOF GROUP")
+		for _, row := range rows {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", row.Field, row.Type, yesNo(row.Required),
+				orDash(row.Default), orDash(row.Deprecated), orDash(row.OneOfGroup))
+		}
+		return tw.Flush()
+	case MatrixMarkdown:
+		var b strings.Builder
+		fmt.Fprintf(&b, "### `%s`\n\n", info.Name)
+		b.WriteString("| Field | Type | Required | Default | Deprecated | oneOf group |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+		for _, row := range rows {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n", markdownCode(row.Field), markdownCode(row.Type), yesNo(row.Required),
+				markdownCode(row.Default), markdownText(row.Deprecated), markdownCode(row.OneOfGroup))
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	default:
+		return fmt.Errorf("unknown matrix format %q (valid formats: %s, %s)", format, MatrixTable, MatrixMarkdown)
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// markdownCode renders s as an inline code span inside a table cell
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = strings.ReplaceAll(s, "|", `\|`)
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
+
+// markdownText escapes s for a table cell; @ is escaped so it does not mention users
+func markdownText(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "@", `\@`)
+}
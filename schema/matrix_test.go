@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteInputMatrix(t *testing.T) {
+	s := loadRichSchema(t)
+	info, err := s.InputObject("IssueMetadataInput", 1)
+	if err != nil {
+		t.Fatalf("InputObject failed: %v", err)
+	}
+
+	tests := []struct {
+		format MatrixFormat
+		want   string
+	}{
+		{
+			format: MatrixTable,
+			want: `FIELD          TYPE                   REQUIRED  DEFAULT  DEPRECATED                     ONEOF GROUP
+priority       Int                    no        3        -                              -
+state          IssueState             no        OPEN     -                              -
+related        [IssueMetadataInput!]  no        -        -                              -
+parent         IssueLocatorInput      no        -        -                              -
+parent.id      ID                     no        -        -                              IssueLocatorInput
+parent.number  Int                    no        -        -                              IssueLocatorInput
+parent.url     String                 no        -        Use ` + "`id` or `number`" + ` instead.  IssueLocatorInput
+`,
+		},
+		{
+			format: MatrixMarkdown,
+			want: "### `IssueMetadataInput`\n\n" +
+				"| Field | Type | Required | Default | Deprecated | oneOf group |\n" +
+				"| --- | --- | --- | --- | --- | --- |\n" +
+				"| `priority` | `Int` | no | `3` |  |  |\n" +
+				"| `state` | `IssueState` | no | `OPEN` |  |  |\n" +
+				"| `related` | `[IssueMetadataInput!]` | no |  |  |  |\n" +
+				"| `parent` | `IssueLocatorInput` | no |  |  |  |\n" +
+				"| `parent.id` | `ID` | no |  |  | `IssueLocatorInput` |\n" +
+				"| `parent.number` | `Int` | no |  |  | `IssueLocatorInput` |\n" +
+				"| `parent.url` | `String` | no |  | Use `id` or `number` instead. | `IssueLocatorInput` |\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteInputMatrix(&buf, info, tt.format); err != nil {
+				t.Fatalf("WriteInputMatrix failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Got:\n%s\nWant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+
+	if err := WriteInputMatrix(&bytes.Buffer{}, info, "html"); err == nil || !strings.Contains(err.Error(), "unknown matrix format") {
+		t.Errorf("Expected unknown format error, got %v", err)
+	}
+}
+
+func TestMarkdownEscaping(t *testing.T) {
+	if got := markdownText("Ask @octocat | see docs"); got != `Ask \@octocat \| see docs` {
+		t.Errorf("markdownText = %q", got)
+	}
+	if got := markdownCode("a`b"); got != "`` a`b ``" {
+		t.Errorf("markdownCode = %q", got)
+	}
+}
@@ -0,0 +1,125 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+// metadataExtension is the key of Metadata under the "extensions" entry of a
+// downloaded document. Extensions are part of the GraphQL response format,
+// so documents with metadata remain standard introspection results.
+const metadataExtension = "githubSchema"
+
+// Metadata records where and when a schema was downloaded
+type Metadata struct {
+	DownloadedAt time.Time `json:"downloadedAt"`
+	SHA256       string    `json:"sha256"` // Of the response as received, before Metadata was added
+	Endpoint     string    `json:"endpoint"`
+	GHESVersion  string    `json:"ghesVersion,omitempty"` // GitHub Enterprise Server version, empty for github.com
+	ETag         string    `json:"etag,omitempty"`        // Entity tag of the response, if the server sent one
+	LegacyQuery  bool      `json:"legacyQuery,omitempty"` // The server rejected IntrospectionQuery and was sent LegacyIntrospectionQuery
+	// Source is SourceSDL for schemas converted from a published SDL file,
+	// whose SHA256 and ETag are those of the SDL; empty for introspection
+	Source DownloadSource `json:"source,omitempty"`
+}
+
+// Options returns the options of the query the schema was downloaded with
+func (m *Metadata) Options() IntrospectionOptions {
+	if m.LegacyQuery {
+		return LegacyQueryOptions
+	}
+	return QueryOptions
+}
+
+// Metadata returns the metadata recorded when the schema was downloaded, or
+// nil for documents without it, such as ones downloaded by other tools
+func (s *Schema) Metadata() *Metadata {
+	if s.metadata != nil {
+		meta := *s.metadata
+		return &meta
+	}
+	doc, ok := s.tree().(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	extensions, ok := doc["extensions"].(map[string]interface{})
+	if !ok || extensions[metadataExtension] == nil {
+		return nil
+	}
+	// Round trip through JSON to decode the generic value into the struct
+	data, err := yamlformat.MarshalJSON(extensions[metadataExtension])
+	if err != nil {
+		return nil
+	}
+	var meta Metadata
+	if err := yamlformat.Unmarshal(data, &meta); err != nil {
+		return nil
+	}
+	return &meta
+}
+
+// metadataOf decodes the Metadata of a downloaded document without parsing
+// the whole document. The key of the entry cannot occur elsewhere, since
+// quotes inside JSON strings are escaped.
+func metadataOf(data []byte) *Metadata {
+	key := []byte(`"` + metadataExtension + `":`)
+	i := bytes.LastIndex(data, key)
+	if i < 0 {
+		return nil
+	}
+	sc := &jsonScanner{data: data[i+len(key):]}
+	sc.skipSpace()
+	start := sc.pos
+	if err := sc.skipValue(); err != nil {
+		return nil
+	}
+	var meta Metadata
+	if err := yamlformat.Unmarshal(sc.data[start:sc.pos], &meta); err != nil {
+		return nil
+	}
+	return &meta
+}
+
+// addMetadata checks that body is a successful GraphQL response and records
+// meta under its extensions
+func addMetadata(body []byte, meta Metadata) ([]byte, error) {
+	var result map[string]interface{}
+	if err := yamlformat.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response as JSON: %w", err)
+	}
+	if errors, ok := result["errors"]; ok {
+		return nil, fmt.Errorf("GraphQL errors: %v", errors)
+	}
+	encoded, err := yamlformat.MarshalJSON(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	if _, ok := result["extensions"]; !ok {
+		// Append the entry so the rest of the response is kept byte for byte
+		trimmed := bytes.TrimRight(body, " \t\r\n")
+		if bytes.HasSuffix(trimmed, []byte("}")) {
+			out := make([]byte, 0, len(trimmed)+len(encoded)+64)
+			out = append(out, trimmed[:len(trimmed)-1]...)
+			out = append(out, `,"extensions":{"`+metadataExtension+`":`...)
+			out = append(out, bytes.TrimRight(encoded, "\n")...)
+			out = append(out, "}}\n"...)
+			return out, nil
+		}
+	}
+
+	extensions, _ := result["extensions"].(map[string]interface{})
+	if extensions == nil {
+		extensions = make(map[string]interface{})
+	}
+	var value interface{}
+	if err := yamlformat.Unmarshal(encoded, &value); err != nil {
+		return nil, fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	extensions[metadataExtension] = value
+	result["extensions"] = extensions
+	return yamlformat.MarshalJSON(result)
+}
@@ -0,0 +1,98 @@
+package schema
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddMetadata(t *testing.T) {
+	meta := Metadata{
+		DownloadedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		SHA256:       Fingerprint(syntheticData(t)),
+		Endpoint:     GitHubAPIURL,
+	}
+	for name, body := range map[string][]byte{
+		"appended":       syntheticData(t),
+		"has extensions": []byte(`{"data": {"__schema": {"queryType": {"name": "Query"}, "types": [{"kind": "OBJECT", "name": "Query", "fields": []}]}}, "extensions": {"cost": 1}}`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			out, err := addMetadata(body, meta)
+			if err != nil {
+				t.Fatalf("addMetadata failed: %v", err)
+			}
+			s, err := NewWithData(out)
+			if err != nil {
+				t.Fatalf("Failed to load result: %v\n%s", err, out)
+			}
+			got := s.Metadata()
+			if got == nil || *got != meta {
+				t.Errorf("Expected %+v, got %+v", meta, got)
+			}
+			if got := metadataOf(out); got == nil || *got != meta {
+				t.Errorf("metadataOf: expected %+v, got %+v", meta, got)
+			}
+			if name == "appended" && s.Fingerprint() != loadRichSchema(t).Fingerprint() {
+				t.Error("Expected metadata not to change the fingerprint")
+			}
+		})
+	}
+
+	if _, err := addMetadata([]byte(`{"errors": [{"message": "bad"}]}`), meta); err == nil {
+		t.Error("Expected error for GraphQL errors")
+	}
+	if loadRichSchema(t).Metadata() != nil {
+		t.Error("Expected no metadata for the sample")
+	}
+}
+
+func TestDownloadMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("X-GitHub-Enterprise-Version", "3.14.0")
+		gz := gzip.NewWriter(w)
+		gz.Write(SampleData())
+		gz.Close()
+	}))
+	defer server.Close()
+
+	defer func(endpoint, token string) { DownloadEndpoint, ExplicitToken = endpoint, token }(DownloadEndpoint, ExplicitToken)
+	DownloadEndpoint, ExplicitToken = server.URL, "test-token"
+
+	var buf bytes.Buffer
+	if err := DownloadAndCompressToWriter(&buf); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("Expected gzip output: %v", err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("Failed to decompress: %v", err)
+	}
+	s, err := NewWithDataStrict(data)
+	if err != nil {
+		t.Fatalf("Failed to load download: %v", err)
+	}
+	meta := s.Metadata()
+	if meta == nil {
+		t.Fatal("Expected metadata")
+	}
+	if meta.SHA256 != Fingerprint(SampleData()) || meta.Endpoint != server.URL || meta.GHESVersion != "3.14.0" ||
+		time.Since(meta.DownloadedAt) > time.Minute {
+		t.Errorf("Unexpected metadata %+v", meta)
+	}
+	if !strings.HasPrefix(string(data), string(bytes.TrimRight(SampleData(), "\n}"))) {
+		t.Error("Expected the response to be kept byte for byte")
+	}
+}
@@ -0,0 +1,316 @@
+package schema
+
+import "github.com/apstndb/github-schema-go/schema/introspection"
+
+// Model is a typed form of the introspection result with name indexes, for
+// code that needs many lookups and would otherwise walk interface{} values
+// or run jq. Obtain it with Schema.Model; it must not be modified.
+type Model struct {
+	Description      string       `json:"description,omitempty"` // Of the schema, when the server describes it
+	QueryType        string       `json:"queryType"`
+	MutationType     string       `json:"mutationType,omitempty"`
+	SubscriptionType string       `json:"subscriptionType,omitempty"`
+	Types            []*Type      `json:"types"` // In schema order
+	Directives       []*Directive `json:"directives"`
+
+	types map[string]*Type
+}
+
+// Type is a named type of the schema, mirroring __Type. Members that do not
+// apply to Kind are empty.
+type Type struct {
+	Kind           string        `json:"kind"`
+	Name           string        `json:"name"`
+	Description    string        `json:"description"`
+	SpecifiedByURL string        `json:"specifiedByURL,omitempty"` // Specification of a custom scalar
+	Fields         []*Field      `json:"fields,omitempty"`
+	InputFields    []*InputValue `json:"inputFields,omitempty"`
+	Interfaces     []string      `json:"interfaces,omitempty"`
+	PossibleTypes  []string      `json:"possibleTypes,omitempty"`
+	EnumValues     []*EnumValue  `json:"enumValues,omitempty"`
+	OneOf          bool          `json:"oneOf,omitempty"`
+
+	fields      map[string]*Field
+	inputFields map[string]*InputValue
+}
+
+// Field is a field of an object or interface type, mirroring __Field
+type Field struct {
+	Name              string        `json:"name"`
+	Description       string        `json:"description"`
+	Args              []*InputValue `json:"args"`
+	Type              *TypeRef      `json:"type"`
+	IsDeprecated      bool          `json:"isDeprecated,omitempty"`
+	DeprecationReason string        `json:"deprecationReason,omitempty"`
+}
+
+// InputValue is an argument or input field, mirroring __InputValue.
+// DefaultValue is a GraphQL literal, or nil when there is no default.
+type InputValue struct {
+	Name              string   `json:"name"`
+	Description       string   `json:"description"`
+	Type              *TypeRef `json:"type"`
+	DefaultValue      *string  `json:"defaultValue,omitempty"`
+	IsDeprecated      bool     `json:"isDeprecated,omitempty"`
+	DeprecationReason string   `json:"deprecationReason,omitempty"`
+}
+
+// EnumValue is a value of an enum type, mirroring __EnumValue
+type EnumValue struct {
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	IsDeprecated      bool   `json:"isDeprecated,omitempty"`
+	DeprecationReason string `json:"deprecationReason,omitempty"`
+}
+
+// Directive is a directive supported by the schema, mirroring __Directive
+type Directive struct {
+	Name         string        `json:"name"`
+	Description  string        `json:"description"`
+	Locations    []string      `json:"locations"`
+	Args         []*InputValue `json:"args"`
+	IsRepeatable bool          `json:"isRepeatable,omitempty"`
+}
+
+// Model returns the typed model of the schema. It is built on first use from
+// the introspection document of the same parsed data as the jq queries and
+// shared by later calls.
+func (s *Schema) Model() *Model {
+	s.modelOnce.Do(func() {
+		doc, err := introspection.FromValue(s.tree())
+		if err != nil {
+			// Loaded schemas have types, which checkReferences verifies
+			doc = &introspection.Document{}
+		}
+		s.model = buildModel(doc)
+	})
+	return s.model
+}
+
+// tree returns the parsed introspection result. A schema loaded from the
+// cache has only its Model and rebuilds the result on first use.
+func (s *Schema) tree() interface{} {
+	s.dataOnce.Do(func() {
+		if s.data == nil && s.model != nil {
+			s.data = s.model.document(s.metadata)
+		}
+	})
+	return s.data
+}
+
+// Introspection returns the schema as a typed introspection document. Unlike
+// the Model, which is built from it, it keeps every value of the result, such
+// as null descriptions and null lists, for code that rewrites schemas. Each
+// call converts a new copy that the caller may modify.
+func (s *Schema) Introspection() (*introspection.Document, error) {
+	return introspection.FromValue(s.tree())
+}
+
+// Type returns the named type, or nil
+func (m *Model) Type(name string) *Type {
+	return m.types[name]
+}
+
+// Resolve returns the named type that ref refers to through its wrappers,
+// or nil when the schema has no such type
+func (m *Model) Resolve(ref *TypeRef) *Type {
+	if ref = ref.Unwrap(); ref == nil {
+		return nil
+	}
+	return m.Type(ref.Name)
+}
+
+// Mutation returns the named field of the mutation root type, or nil
+func (m *Model) Mutation(name string) *Field {
+	return m.rootField(m.MutationType, "Mutation", name)
+}
+
+// QueryField returns the named field of the query root type, or nil
+func (m *Model) QueryField(name string) *Field {
+	return m.rootField(m.QueryType, "Query", name)
+}
+
+// rootField looks up a field of a root type, falling back to the conventional
+// root type name for documents that do not declare it
+func (m *Model) rootField(root, fallback, name string) *Field {
+	if t := m.rootType(root, fallback); t != nil {
+		return t.Field(name)
+	}
+	return nil
+}
+
+func (m *Model) rootType(root, fallback string) *Type {
+	if root == "" {
+		root = fallback
+	}
+	return m.Type(root)
+}
+
+// Field returns the named field of an object or interface type, or nil
+func (t *Type) Field(name string) *Field {
+	return t.fields[name]
+}
+
+// InputField returns the named field of an input object type, or nil
+func (t *Type) InputField(name string) *InputValue {
+	return t.inputFields[name]
+}
+
+// Arg returns the named argument of the field, or nil
+func (f *Field) Arg(name string) *InputValue {
+	return findInputValue(f.Args, name)
+}
+
+// Arg returns the named argument of the directive, or nil
+func (d *Directive) Arg(name string) *InputValue {
+	return findInputValue(d.Args, name)
+}
+
+// Required reports whether a value must be provided: the type is non-null
+// and there is no default
+func (v *InputValue) Required() bool {
+	return v.Type != nil && v.Type.Kind == "NON_NULL" && v.DefaultValue == nil
+}
+
+func findInputValue(values []*InputValue, name string) *InputValue {
+	for _, v := range values {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// buildModel converts the introspection document. Types without a name and
+// repeated definitions are skipped like the native lookups do; use
+// NewWithDataStrict to reject them.
+func buildModel(doc *introspection.Document) *Model {
+	schema := &doc.Data.Schema
+	m := &Model{
+		Description:      schema.Description.Get(),
+		QueryType:        rootName(schema.QueryType),
+		MutationType:     rootName(schema.MutationType),
+		SubscriptionType: rootName(schema.SubscriptionType),
+		Types:            make([]*Type, 0, len(schema.Types)),
+		Directives:       make([]*Directive, 0, len(schema.Directives)),
+		types:            make(map[string]*Type, len(schema.Types)),
+	}
+	for i := range schema.Types {
+		t := buildType(&schema.Types[i])
+		if t.Name == "" || m.types[t.Name] != nil {
+			continue
+		}
+		m.types[t.Name] = t
+		m.Types = append(m.Types, t)
+	}
+	for _, d := range schema.Directives {
+		m.Directives = append(m.Directives, &Directive{
+			Name:         d.Name,
+			Description:  deref(d.Description),
+			Locations:    d.Locations,
+			Args:         inputValues(d.Args),
+			IsRepeatable: d.IsRepeatable != nil && *d.IsRepeatable,
+		})
+	}
+	return m
+}
+
+func buildType(entry *introspection.Type) *Type {
+	t := &Type{
+		Kind:           entry.Kind,
+		Name:           entry.Name,
+		Description:    deref(entry.Description),
+		SpecifiedByURL: entry.SpecifiedByURL.Get(),
+		InputFields:    inputValues(entry.InputFields),
+		Interfaces:     refNames(entry.Interfaces),
+		PossibleTypes:  refNames(entry.PossibleTypes),
+		OneOf:          entry.IsOneOf != nil && *entry.IsOneOf,
+	}
+	if len(entry.Fields) > 0 {
+		t.fields = make(map[string]*Field, len(entry.Fields))
+	}
+	for i := range entry.Fields {
+		f := &entry.Fields[i]
+		field := &Field{
+			Name:              f.Name,
+			Description:       deref(f.Description),
+			Args:              inputValues(f.Args),
+			Type:              typeRef(&f.Type),
+			IsDeprecated:      f.IsDeprecated,
+			DeprecationReason: deref(f.DeprecationReason),
+		}
+		t.Fields = append(t.Fields, field)
+		t.fields[field.Name] = field
+	}
+	if len(t.InputFields) > 0 {
+		t.inputFields = make(map[string]*InputValue, len(t.InputFields))
+		for _, v := range t.InputFields {
+			t.inputFields[v.Name] = v
+		}
+	}
+	for _, v := range entry.EnumValues {
+		t.EnumValues = append(t.EnumValues, &EnumValue{
+			Name:              v.Name,
+			Description:       deref(v.Description),
+			IsDeprecated:      v.IsDeprecated,
+			DeprecationReason: deref(v.DeprecationReason),
+		})
+	}
+	return t
+}
+
+// inputValues converts a list of __InputValue entries
+func inputValues(list []introspection.InputValue) []*InputValue {
+	values := make([]*InputValue, 0, len(list))
+	for i := range list {
+		v := &list[i]
+		values = append(values, &InputValue{
+			Name:              v.Name,
+			Description:       deref(v.Description),
+			Type:              typeRef(&v.Type),
+			DefaultValue:      v.DefaultValue,
+			IsDeprecated:      v.IsDeprecated != nil && *v.IsDeprecated,
+			DeprecationReason: v.DeprecationReason.Get(),
+		})
+	}
+	return values
+}
+
+// typeRef converts a type reference, bounding its depth like TypeRefFromMap
+func typeRef(ref *introspection.TypeRef) *TypeRef {
+	var root *TypeRef
+	next := &root
+	for depth := 0; ref != nil && depth <= maxTypeRefDepth; depth++ {
+		r := &TypeRef{Kind: ref.Kind, Name: deref(ref.Name)}
+		*next = r
+		next = &r.OfType
+		ref = ref.OfType
+	}
+	return root
+}
+
+// refNames returns the names of the interfaces or possible types of a type
+func refNames(refs []introspection.TypeRef) []string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Name != nil {
+			names = append(names, *ref.Name)
+		}
+	}
+	return names
+}
+
+// rootName returns the name of a root operation type, or ""
+func rootName(ref *introspection.RootType) string {
+	if ref == nil {
+		return ""
+	}
+	return ref.Name
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
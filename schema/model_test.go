@@ -0,0 +1,149 @@
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/schema/introspection"
+)
+
+func TestModel(t *testing.T) {
+	s := loadRichSchema(t)
+	m := s.Model()
+	if m != s.Model() {
+		t.Error("Model is not shared between calls")
+	}
+
+	if m.QueryType != "Query" || m.MutationType != "Mutation" || m.SubscriptionType != "" {
+		t.Errorf("Unexpected root types: %q %q %q", m.QueryType, m.MutationType, m.SubscriptionType)
+	}
+	if len(m.Types) != 26 {
+		t.Errorf("Expected 26 types, got %d", len(m.Types))
+	}
+	if m.Type("Nope") != nil {
+		t.Error("Expected nil for unknown type")
+	}
+
+	repo := m.Type("Repository")
+	if repo == nil || repo.Kind != "OBJECT" {
+		t.Fatalf("Unexpected Repository: %+v", repo)
+	}
+	issues := repo.Field("issues")
+	if issues == nil || issues.Type.String() != "IssueConnection!" {
+		t.Fatalf("Unexpected Repository.issues: %+v", issues)
+	}
+	if first := issues.Arg("first"); first == nil || first.Type.String() != "Int" || first.Required() {
+		t.Errorf("Unexpected issues(first:): %+v", first)
+	}
+	if f := repo.Field("isTemplateRepo"); f == nil || !f.IsDeprecated || f.DeprecationReason == "" {
+		t.Errorf("Expected deprecated isTemplateRepo, got %+v", f)
+	}
+	if repo.Field("nope") != nil || issues.Arg("nope") != nil {
+		t.Error("Expected nil for unknown field or argument")
+	}
+
+	if got := m.Type("IssueOrPullRequest").PossibleTypes; !reflect.DeepEqual(got, []string{"Issue", "PullRequest"}) {
+		t.Errorf("Unexpected possible types: %v", got)
+	}
+	if got := m.Type("Issue").Interfaces; len(got) == 0 {
+		t.Error("Expected Issue to implement interfaces")
+	}
+
+	locator := m.Type("IssueLocatorInput")
+	if !locator.OneOf || locator.InputField("url") == nil || !locator.InputField("url").IsDeprecated {
+		t.Errorf("Unexpected IssueLocatorInput: %+v", locator)
+	}
+
+	createIssue := m.Mutation("createIssue")
+	if createIssue == nil || !createIssue.Arg("input").Required() {
+		t.Fatalf("Unexpected createIssue: %+v", createIssue)
+	}
+	input := m.Type(createIssue.Arg("input").Type.NamedType())
+	if input == nil || input.InputField("repositoryId") == nil || !input.InputField("repositoryId").Required() {
+		t.Errorf("Unexpected CreateIssueInput: %+v", input)
+	}
+	if m.QueryField("repository") == nil || m.QueryField("createIssue") != nil {
+		t.Error("QueryField should only find Query fields")
+	}
+
+	var include *Directive
+	for _, d := range m.Directives {
+		if d.Name == "include" {
+			include = d
+		}
+	}
+	if include == nil || include.Arg("if") == nil || !include.Arg("if").Required() {
+		t.Errorf("Unexpected include directive: %+v", include)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	s := loadRichSchema(t)
+	ref, err := s.FieldType("Repository", "issues")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := s.Resolve(ref)
+	if err != nil || conn.Name != "IssueConnection" || conn.Kind != "OBJECT" {
+		t.Fatalf("Resolve(%s) = %+v, %v", ref, conn, err)
+	}
+	if nodes := conn.Field("nodes"); nodes == nil || s.Model().Resolve(nodes.Type) != s.Model().Type("Issue") {
+		t.Errorf("Expected IssueConnection.nodes to resolve to Issue, got %+v", nodes)
+	}
+
+	_, err = s.Resolve(&TypeRef{Kind: "NON_NULL", OfType: &TypeRef{Kind: "OBJECT", Name: "Isue"}})
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) || notFound.Name != "Isue" {
+		t.Errorf("Expected NotFoundError for Isue, got %v", err)
+	}
+}
+
+func TestModelDefaultValue(t *testing.T) {
+	m := loadRichSchema(t).Model()
+	var deprecated *Directive
+	for _, d := range m.Directives {
+		if d.Name == "deprecated" {
+			deprecated = d
+		}
+	}
+	reason := deprecated.Arg("reason")
+	if reason.DefaultValue == nil || *reason.DefaultValue != `"No longer supported"` || reason.Required() {
+		t.Errorf("Unexpected reason argument: %+v", reason)
+	}
+}
+
+func TestIntrospection(t *testing.T) {
+	s := loadRichSchema(t)
+	doc, err := s.Introspection()
+	if err != nil {
+		t.Fatalf("Introspection failed: %v", err)
+	}
+	if len(doc.Data.Schema.Types) != len(s.Model().Types) || doc.Data.Schema.QueryType.Name != "Query" {
+		t.Errorf("Unexpected document: %d types, query type %+v", len(doc.Data.Schema.Types), doc.Data.Schema.QueryType)
+	}
+
+	// The document holds the same content as the source
+	out, err := introspection.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	got, err := introspection.Canonicalize(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := introspection.Canonicalize(syntheticData(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("Introspection does not round trip the synthetic schema")
+	}
+
+	// Callers get their own copy
+	doc.Data.Schema.Types[0].Name = "Changed"
+	if again, _ := s.Introspection(); again.Data.Schema.Types[0].Name == "Changed" {
+		t.Error("Introspection returned shared data")
+	}
+}
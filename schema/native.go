@@ -0,0 +1,234 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Native implementations of the predefined lookups, walking the Model instead
+// of running jq over the whole document. The jq expressions in queries.go stay
+// the reference for Queries and Run, and TestNativeMatchesJQ checks that both
+// produce the same results. Results use map[string]interface{} and
+// []interface{} like decoded jq output, with null descriptions as nil.
+
+// typeResult mirrors typeQuery
+func typeResult(t *Type) map[string]interface{} {
+	out := map[string]interface{}{
+		"name":        t.Name,
+		"kind":        t.Kind,
+		"description": nullable(t.Description),
+		"fields":      nil,
+		"inputFields": nil,
+		"enumValues":  nil,
+	}
+	if hasFields(t) {
+		fields := make([]interface{}, len(t.Fields))
+		for i, f := range t.Fields {
+			var args interface{}
+			if len(f.Args) > 0 {
+				list := make([]interface{}, len(f.Args))
+				for j, a := range f.Args {
+					list[j] = map[string]interface{}{
+						"name":        a.Name,
+						"description": nullable(a.Description),
+						"type":        a.Type.String(),
+					}
+				}
+				args = list
+			}
+			fields[i] = map[string]interface{}{
+				"name":        f.Name,
+				"description": nullable(f.Description),
+				"type":        f.Type.String(),
+				"arguments":   args,
+			}
+		}
+		out["fields"] = fields
+	}
+	if t.Kind == "INPUT_OBJECT" {
+		fields := make([]interface{}, len(t.InputFields))
+		for i, f := range t.InputFields {
+			fields[i] = map[string]interface{}{
+				"name":        f.Name,
+				"description": nullable(f.Description),
+				"type":        f.Type.String(),
+				"required":    isNonNull(f.Type),
+			}
+		}
+		out["inputFields"] = fields
+	}
+	if t.Kind == "ENUM" {
+		values := make([]interface{}, len(t.EnumValues))
+		for i, v := range t.EnumValues {
+			values[i] = map[string]interface{}{
+				"name":        v.Name,
+				"description": nullable(v.Description),
+			}
+		}
+		out["enumValues"] = values
+	}
+	return map[string]interface{}{"type": out}
+}
+
+// searchResult mirrors searchQuery, which matches with the "i" flag of jq's test
+func (m *Model) searchResult(pattern string) (map[string]interface{}, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	results := []interface{}{}
+	for _, t := range m.Types {
+		if !re.MatchString(t.Name) {
+			continue
+		}
+		var description interface{}
+		if t.Description != "" {
+			description = truncate(t.Description, 100)
+		}
+		results = append(results, map[string]interface{}{
+			"name":        t.Name,
+			"kind":        t.Kind,
+			"description": description,
+		})
+	}
+	return map[string]interface{}{
+		"count":   len(results),
+		"pattern": pattern,
+		"results": results,
+	}, nil
+}
+
+// mutationResult mirrors mutationQuery: a mutation taking a single input
+// object argument has the fields of that object listed in the description
+func (m *Model) mutationResult(f *Field) map[string]interface{} {
+	var inputs []interface{}
+	if len(f.Args) > 0 && f.Args[0].Type != nil && f.Args[0].Type.OfType != nil {
+		if input := m.Type(f.Args[0].Type.OfType.Name); input != nil && input.Kind == "INPUT_OBJECT" {
+			arg := f.Args[0]
+			var b strings.Builder
+			b.WriteString(arg.Description)
+			fmt.Fprintf(&b, "\n\nInput object '%s' has the following fields:\n", input.Name)
+			for i, field := range input.InputFields {
+				if i > 0 {
+					b.WriteByte('\n')
+				}
+				b.WriteString("- " + field.Name + ": " + field.Type.String())
+				if isNonNull(field.Type) {
+					b.WriteString(" (required)")
+				}
+				if field.Description != "" {
+					b.WriteString("\n  " + field.Description)
+				}
+			}
+			inputs = []interface{}{map[string]interface{}{
+				"name":        arg.Name,
+				"type":        arg.Type.String(),
+				"description": b.String(),
+				"required":    isNonNull(arg.Type),
+			}}
+		}
+	}
+	if inputs == nil {
+		inputs = make([]interface{}, len(f.Args))
+		for i, a := range f.Args {
+			inputs[i] = map[string]interface{}{
+				"name":        a.Name,
+				"type":        a.Type.String(),
+				"description": nullable(a.Description),
+				"required":    isNonNull(a.Type),
+			}
+		}
+	}
+	return map[string]interface{}{"mutation": map[string]interface{}{
+		"name":        f.Name,
+		"description": nullable(f.Description),
+		"inputs":      inputs,
+	}}
+}
+
+// queryFieldResult mirrors queryFieldQuery
+func queryFieldResult(f *Field) map[string]interface{} {
+	args := make([]interface{}, len(f.Args))
+	for i, a := range f.Args {
+		var def interface{}
+		if a.DefaultValue != nil {
+			def = *a.DefaultValue
+		}
+		args[i] = map[string]interface{}{
+			"name":         a.Name,
+			"type":         a.Type.String(),
+			"description":  nullable(a.Description),
+			"required":     a.Required(),
+			"defaultValue": def,
+		}
+	}
+	return map[string]interface{}{"queryField": map[string]interface{}{
+		"name":              f.Name,
+		"description":       nullable(f.Description),
+		"type":              f.Type.String(),
+		"arguments":         args,
+		"isDeprecated":      f.IsDeprecated,
+		"deprecationReason": nullable(f.DeprecationReason),
+	}}
+}
+
+// fieldInfos mirrors fieldsQuery
+func fieldInfos(t *Type) []FieldInfo {
+	if !hasFields(t) {
+		return nil
+	}
+	fields := make([]FieldInfo, len(t.Fields))
+	for i, f := range t.Fields {
+		fields[i] = FieldInfo{
+			Name:              f.Name,
+			Description:       f.Description,
+			Type:              f.Type.String(),
+			Arguments:         argumentInfos(f.Args),
+			IsDeprecated:      f.IsDeprecated,
+			DeprecationReason: f.DeprecationReason,
+		}
+	}
+	return fields
+}
+
+// argumentInfos converts arguments, returning nil when there are none like
+// fieldsQuery does
+func argumentInfos(args []*InputValue) []ArgumentInfo {
+	if len(args) == 0 {
+		return nil
+	}
+	out := make([]ArgumentInfo, len(args))
+	for i, a := range args {
+		out[i] = ArgumentInfo{Name: a.Name, Description: a.Description, Type: a.Type.String()}
+	}
+	return out
+}
+
+// hasFields reports whether typeQuery and fieldsQuery list fields for t
+func hasFields(t *Type) bool {
+	return t.Kind == "OBJECT" || t.Kind == "INTERFACE"
+}
+
+// isNonNull is the requiredness used by typeQuery and mutationQuery, which
+// ignore default values
+func isNonNull(ref *TypeRef) bool {
+	return ref != nil && ref.Kind == "NON_NULL"
+}
+
+// nullable returns nil for an empty string, matching a null in introspection
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// truncate shortens s to n code points followed by "...", counting like jq
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "..."
+}
@@ -0,0 +1,123 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestNativeMatchesJQ checks that the native lookups return exactly what the
+// jq expressions in queries.go return for the same input
+func TestNativeMatchesJQ(t *testing.T) {
+	schemas := map[string]*Schema{"rich": loadRichSchema(t)}
+	if !testing.Short() {
+		s, err := New()
+		if err != nil {
+			t.Fatalf("Failed to load embedded schema: %v", err)
+		}
+		schemas["embedded"] = s
+	}
+
+	for name, s := range schemas {
+		t.Run(name, func(t *testing.T) {
+			m := s.Model()
+			types := m.Types
+			if name == "embedded" {
+				// Every kind and the types the jq branches single out
+				types = nil
+				for _, n := range []string{"Repository", "PageInfo", "CreateIssueInput", "IssueState", "Node", "SearchResultItem", "DateTime", "__Type"} {
+					types = append(types, m.Type(n))
+				}
+			}
+
+			for _, typ := range types {
+				vars := map[string]interface{}{"type": typ.Name}
+				assertSame(t, "Type "+typ.Name, typeResult(typ), mustRun(t, s, typeQuery, vars))
+
+				var fields struct {
+					Fields []FieldInfo `json:"fields"`
+				}
+				decodeInto(t, mustRun(t, s, fieldsQuery, vars), &fields)
+				assertSame(t, "Fields "+typ.Name, fieldInfos(typ), fields.Fields)
+			}
+
+			for _, root := range []*Type{m.Type("Mutation"), m.Type(m.QueryType)} {
+				if root == nil {
+					continue
+				}
+				for _, f := range root.Fields {
+					if root.Name == "Mutation" {
+						assertSame(t, "Mutation "+f.Name, m.mutationResult(f), mustRun(t, s, mutationQuery, map[string]interface{}{"mutation": f.Name}))
+					} else {
+						assertSame(t, "QueryField "+f.Name, queryFieldResult(f), mustRun(t, s, queryFieldQuery, map[string]interface{}{"field": f.Name}))
+					}
+				}
+			}
+
+			for _, pattern := range []string{"issue", "^PageInfo$", "Connection$", "nomatch"} {
+				native, err := m.searchResult(pattern)
+				if err != nil {
+					t.Fatalf("searchResult(%q) failed: %v", pattern, err)
+				}
+				assertSame(t, "Search "+pattern, native, mustRun(t, s, searchQuery, map[string]interface{}{"pattern": pattern}))
+			}
+
+			for _, kind := range ListKinds {
+				native, err := s.List(kind)
+				if err != nil {
+					t.Fatalf("List(%q) failed: %v", kind, err)
+				}
+				query := map[string]string{
+					"types": ListTypesQuery, "objects": ListObjectTypesQuery, "inputs": ListInputTypesQuery,
+					"enums": ListEnumTypesQuery, "interfaces": ListInterfaceTypesQuery, "unions": ListUnionTypesQuery,
+					"scalars": ListScalarTypesQuery, "mutations": ListMutationsQuery, "queries": ListQueriesQuery,
+				}[kind]
+				result, err := s.Query("["+query+"]", nil)
+				if err != nil {
+					t.Fatalf("jq List(%q) failed: %v", kind, err)
+				}
+				want := []string{}
+				decodeInto(t, result, &want)
+				assertSame(t, "List "+kind, native, want)
+			}
+		})
+	}
+}
+
+func mustRun(t *testing.T, s *Schema, query string, vars map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	result, err := s.runQuery(query, vars)
+	if err != nil {
+		t.Fatalf("jq query with %v failed: %v", vars, err)
+	}
+	return result
+}
+
+func decodeInto(t *testing.T, result interface{}, out interface{}) {
+	t.Helper()
+	if err := decodeResult(result, out); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+}
+
+func assertSame(t *testing.T, what string, native, jq interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(native, jq) {
+		t.Errorf("%s differs:\nnative: %#v\njq:     %#v", what, native, jq)
+	}
+}
+
+func TestNativeLookupErrors(t *testing.T) {
+	s := loadRichSchema(t)
+	if _, err := s.Type("Nope"); err == nil {
+		t.Error("Expected error for unknown type")
+	}
+	if _, err := s.Mutation("nope"); err == nil {
+		t.Error("Expected error for unknown mutation")
+	}
+	if _, err := s.Search("("); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+	if _, err := s.EnumValues("Issue"); err == nil || err.Error() != `type "Issue" is not an enum (kind: OBJECT)` {
+		t.Errorf("Unexpected EnumValues error: %v", err)
+	}
+}
@@ -0,0 +1,423 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+)
+
+// OperationChangeType identifies what changed between two versions of an
+// operation document
+type OperationChangeType string
+
+const (
+	OperationAdded       OperationChangeType = "OPERATION_ADDED"
+	OperationRemoved     OperationChangeType = "OPERATION_REMOVED"
+	OperationRenamed     OperationChangeType = "OPERATION_RENAMED"
+	OperationKindChanged OperationChangeType = "OPERATION_KIND_CHANGED"
+
+	VariableAdded          OperationChangeType = "VARIABLE_ADDED"
+	VariableRemoved        OperationChangeType = "VARIABLE_REMOVED"
+	VariableTypeChanged    OperationChangeType = "VARIABLE_TYPE_CHANGED"
+	VariableDefaultChanged OperationChangeType = "VARIABLE_DEFAULT_CHANGED"
+
+	SelectionAdded        OperationChangeType = "SELECTION_ADDED"
+	SelectionRemoved      OperationChangeType = "SELECTION_REMOVED"
+	SelectionFieldChanged OperationChangeType = "SELECTION_FIELD_CHANGED" // Same response key, different field
+	DirectivesChanged     OperationChangeType = "DIRECTIVES_CHANGED"
+
+	ArgumentValueAdded   OperationChangeType = "ARGUMENT_ADDED"
+	ArgumentValueRemoved OperationChangeType = "ARGUMENT_REMOVED"
+	ArgumentValueChanged OperationChangeType = "ARGUMENT_CHANGED"
+
+	FragmentAdded         OperationChangeType = "FRAGMENT_ADDED"
+	FragmentRemoved       OperationChangeType = "FRAGMENT_REMOVED"
+	FragmentTypeChanged   OperationChangeType = "FRAGMENT_TYPE_CHANGED"
+	FragmentSpreadAdded   OperationChangeType = "FRAGMENT_SPREAD_ADDED"
+	FragmentSpreadRemoved OperationChangeType = "FRAGMENT_SPREAD_REMOVED"
+	InlineFragmentAdded   OperationChangeType = "INLINE_FRAGMENT_ADDED"
+	InlineFragmentRemoved OperationChangeType = "INLINE_FRAGMENT_REMOVED"
+)
+
+// OperationChange is a single difference between two operation documents.
+// Path locates the change by response keys, starting at the operation or
+// fragment name, such as "GetIssues.repository.issues" or
+// "IssueFields.author". Coordinate and SchemaType annotate it with the
+// schema: the field, argument, or variable involved, as in "Repository.issues"
+// or "Repository.issues(first:)", and its type. They are empty when the
+// schema does not define the element, for example in operations written
+// against a newer schema.
+type OperationChange struct {
+	Type       OperationChangeType `json:"type"`
+	Path       string              `json:"path"`
+	Message    string              `json:"message"`
+	Coordinate string              `json:"coordinate,omitempty"`
+	SchemaType string              `json:"schemaType,omitempty"`
+	OldValue   string              `json:"oldValue,omitempty"`
+	NewValue   string              `json:"newValue,omitempty"`
+}
+
+// DiffOperations compares two versions of an operation document, such as a
+// .graphql file before and after a pull request. Operations and fragments are
+// matched by name; when both documents contain a single operation, the two
+// are compared even if renamed. Selections are matched by response key,
+// inline fragments by type condition, and fragment spreads by name, so
+// fragment changes are reported once on the fragment definition. Changes are
+// in the order of the old document, with elements only in the new document
+// following their siblings.
+func (s *Schema) DiffOperations(oldDoc, newDoc *graphql.Document) []OperationChange {
+	d := &operationDiffer{model: s.Model(), schema: s, changes: []OperationChange{}}
+
+	oldOps, newOps := oldDoc.Operations(), newDoc.Operations()
+	if len(oldOps) == 1 && len(newOps) == 1 {
+		d.operation(oldOps[0], newOps[0])
+	} else {
+		matched := make(map[string]bool)
+		for _, o := range oldOps {
+			if n := findOperation(newOps, o.Name); n != nil {
+				matched[o.Name] = true
+				d.operation(o, n)
+				continue
+			}
+			d.add(OperationRemoved, operationPath(o), fmt.Sprintf("%s removed", operationLabel(o)), "", "", string(o.Operation), "")
+		}
+		for _, n := range newOps {
+			if !matched[n.Name] {
+				d.add(OperationAdded, operationPath(n), fmt.Sprintf("%s added", operationLabel(n)), "", "", "", string(n.Operation))
+			}
+		}
+	}
+
+	oldFragments, newFragments := oldDoc.Fragments(), newDoc.Fragments()
+	for _, def := range oldDoc.Definitions {
+		o, ok := def.(*graphql.FragmentDefinition)
+		if !ok {
+			continue
+		}
+		n, ok := newFragments[o.Name]
+		if !ok {
+			d.add(FragmentRemoved, o.Name, fmt.Sprintf("fragment %q removed", o.Name), "", o.TypeCondition, o.TypeCondition, "")
+			continue
+		}
+		if o.TypeCondition != n.TypeCondition {
+			d.add(FragmentTypeChanged, o.Name, fmt.Sprintf("fragment %q type condition changed from %s to %s", o.Name, o.TypeCondition, n.TypeCondition),
+				"", n.TypeCondition, o.TypeCondition, n.TypeCondition)
+		}
+		d.directives(o.Name, "", o.Directives, n.Directives)
+		d.selectionSet(o.Name, d.model.Type(o.TypeCondition), d.model.Type(n.TypeCondition), o.SelectionSet, n.SelectionSet)
+	}
+	for _, def := range newDoc.Definitions {
+		if n, ok := def.(*graphql.FragmentDefinition); ok && oldFragments[n.Name] == nil {
+			d.add(FragmentAdded, n.Name, fmt.Sprintf("fragment %q added", n.Name), "", n.TypeCondition, "", n.TypeCondition)
+		}
+	}
+	return d.changes
+}
+
+// operationDiffer accumulates the changes between two operation documents
+type operationDiffer struct {
+	model   *Model
+	schema  *Schema
+	changes []OperationChange
+}
+
+func (d *operationDiffer) add(t OperationChangeType, path, message, coordinate, schemaType, oldValue, newValue string) {
+	d.changes = append(d.changes, OperationChange{
+		Type: t, Path: path, Message: message, Coordinate: coordinate, SchemaType: schemaType, OldValue: oldValue, NewValue: newValue,
+	})
+}
+
+func findOperation(ops []*graphql.OperationDefinition, name string) *graphql.OperationDefinition {
+	for _, op := range ops {
+		if op.Name == name {
+			return op
+		}
+	}
+	return nil
+}
+
+// operationPath names an operation at the start of change paths
+func operationPath(op *graphql.OperationDefinition) string {
+	if op.Name == "" {
+		return string(op.Operation)
+	}
+	return op.Name
+}
+
+func (d *operationDiffer) operation(o, n *graphql.OperationDefinition) {
+	path := operationPath(n)
+	if o.Name != n.Name {
+		d.add(OperationRenamed, path, fmt.Sprintf("%s renamed to %q", operationLabel(o), n.Name), "", "", o.Name, n.Name)
+	}
+	if o.Operation != n.Operation {
+		d.add(OperationKindChanged, path, fmt.Sprintf("operation changed from %s to %s", o.Operation, n.Operation),
+			"", "", string(o.Operation), string(n.Operation))
+	}
+	d.variables(path, o.VariableDefinitions, n.VariableDefinitions)
+	d.directives(path, "", o.Directives, n.Directives)
+	d.selectionSet(path, d.rootType(o), d.rootType(n), o.SelectionSet, n.SelectionSet)
+}
+
+func (d *operationDiffer) rootType(op *graphql.OperationDefinition) *Type {
+	fallback := strings.ToUpper(string(op.Operation[:1])) + string(op.Operation[1:])
+	return d.model.rootType(d.schema.RootTypeName(string(op.Operation)), fallback)
+}
+
+func (d *operationDiffer) variables(path string, oldVars, newVars []*graphql.VariableDefinition) {
+	find := func(vars []*graphql.VariableDefinition, name string) *graphql.VariableDefinition {
+		for _, v := range vars {
+			if v.Name == name {
+				return v
+			}
+		}
+		return nil
+	}
+	for _, o := range oldVars {
+		coordinate := "$" + o.Name
+		n := find(newVars, o.Name)
+		if n == nil {
+			d.add(VariableRemoved, path, fmt.Sprintf("variable %s removed", coordinate), coordinate, o.Type.String(), o.Type.String(), "")
+			continue
+		}
+		if o.Type.String() != n.Type.String() {
+			d.add(VariableTypeChanged, path, fmt.Sprintf("variable %s type changed from %s to %s", coordinate, o.Type, n.Type),
+				coordinate, n.Type.String(), o.Type.String(), n.Type.String())
+		}
+		if oldDefault, newDefault := valueString(o.DefaultValue), valueString(n.DefaultValue); oldDefault != newDefault {
+			d.add(VariableDefaultChanged, path, fmt.Sprintf("variable %s default changed from %s to %s", coordinate, orNone(oldDefault), orNone(newDefault)),
+				coordinate, n.Type.String(), oldDefault, newDefault)
+		}
+	}
+	for _, n := range newVars {
+		if find(oldVars, n.Name) == nil {
+			coordinate := "$" + n.Name
+			d.add(VariableAdded, path, fmt.Sprintf("variable %s added", coordinate), coordinate, n.Type.String(), "", n.Type.String())
+		}
+	}
+}
+
+func valueString(v *graphql.Value) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+func directivesString(directives []*graphql.Directive) string {
+	var parts []string
+	for _, dir := range directives {
+		part := "@" + dir.Name
+		if len(dir.Arguments) > 0 {
+			args := make([]string, len(dir.Arguments))
+			for i, a := range dir.Arguments {
+				args[i] = a.Name + ": " + a.Value.String()
+			}
+			part += "(" + strings.Join(args, ", ") + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (d *operationDiffer) directives(path, coordinate string, oldDirectives, newDirectives []*graphql.Directive) {
+	if o, n := directivesString(oldDirectives), directivesString(newDirectives); o != n {
+		d.add(DirectivesChanged, path, fmt.Sprintf("directives changed from %s to %s", orNone(o), orNone(n)), coordinate, "", o, n)
+	}
+}
+
+// selectionGroup is the selections of a set sharing a key, merged the way
+// GraphQL merges fields with the same response key
+type selectionGroup struct {
+	key        string
+	field      *graphql.Field // First field, for fields
+	condition  string         // Type condition, for inline fragments
+	spread     string         // Fragment name, for spreads
+	children   graphql.SelectionSet
+	directives []*graphql.Directive
+}
+
+// groupSelections keys the selections of a set in document order: fields by
+// response key, inline fragments by "... on Type", and spreads by "...Name"
+func groupSelections(set graphql.SelectionSet) []*selectionGroup {
+	var groups []*selectionGroup
+	byKey := make(map[string]*selectionGroup)
+	group := func(key string) (*selectionGroup, bool) {
+		if g, ok := byKey[key]; ok {
+			return g, false
+		}
+		g := &selectionGroup{key: key}
+		byKey[key] = g
+		groups = append(groups, g)
+		return g, true
+	}
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *graphql.Field:
+			g, first := group(sel.ResponseKey())
+			if first {
+				g.field, g.directives = sel, sel.Directives
+			}
+			g.children = append(g.children, sel.SelectionSet...)
+		case *graphql.InlineFragment:
+			key := "..."
+			if sel.TypeCondition != "" {
+				key = "... on " + sel.TypeCondition
+			}
+			g, first := group(key)
+			if first {
+				g.condition, g.directives = sel.TypeCondition, sel.Directives
+			}
+			g.children = append(g.children, sel.SelectionSet...)
+		case *graphql.FragmentSpread:
+			g, first := group("..." + sel.Name)
+			if first {
+				g.spread, g.directives = sel.Name, sel.Directives
+			}
+		}
+	}
+	return groups
+}
+
+func (d *operationDiffer) selectionSet(path string, oldParent, newParent *Type, oldSet, newSet graphql.SelectionSet) {
+	oldGroups, newGroups := groupSelections(oldSet), groupSelections(newSet)
+	newByKey := make(map[string]*selectionGroup, len(newGroups))
+	for _, g := range newGroups {
+		newByKey[g.key] = g
+	}
+	oldKeys := make(map[string]bool, len(oldGroups))
+	for _, o := range oldGroups {
+		oldKeys[o.key] = true
+		if n, ok := newByKey[o.key]; ok {
+			d.selection(path, oldParent, newParent, o, n)
+		} else {
+			d.selectionChange(path, oldParent, o, false)
+		}
+	}
+	for _, n := range newGroups {
+		if !oldKeys[n.key] {
+			d.selectionChange(path, newParent, n, true)
+		}
+	}
+}
+
+// selectionChange reports a selection only in one of the documents
+func (d *operationDiffer) selectionChange(path string, parent *Type, g *selectionGroup, added bool) {
+	verb := "removed"
+	if added {
+		verb = "added"
+	}
+	switch {
+	case g.field != nil:
+		coordinate, schemaType := d.fieldAnnotation(parent, g.field.Name)
+		t, oldValue, newValue := SelectionRemoved, g.field.Name, ""
+		if added {
+			t, oldValue, newValue = SelectionAdded, "", g.field.Name
+		}
+		d.add(t, joinPath(path, g.key), fmt.Sprintf("field %q %s", g.key, verb), coordinate, schemaType, oldValue, newValue)
+	case g.spread != "":
+		t := FragmentSpreadRemoved
+		if added {
+			t = FragmentSpreadAdded
+		}
+		d.add(t, path, fmt.Sprintf("fragment spread ...%s %s", g.spread, verb), "", "", "", "")
+	default:
+		t := InlineFragmentRemoved
+		if added {
+			t = InlineFragmentAdded
+		}
+		d.add(t, path, fmt.Sprintf("inline fragment %q %s", g.key, verb), "", g.condition, "", "")
+	}
+}
+
+// selection compares the selections of both documents with the same key
+func (d *operationDiffer) selection(path string, oldParent, newParent *Type, o, n *selectionGroup) {
+	switch {
+	case o.field != nil:
+		fieldPath := joinPath(path, o.key)
+		coordinate, schemaType := d.fieldAnnotation(newParent, n.field.Name)
+		if o.field.Name != n.field.Name {
+			d.add(SelectionFieldChanged, fieldPath, fmt.Sprintf("%q now selects field %q instead of %q", o.key, n.field.Name, o.field.Name),
+				coordinate, schemaType, o.field.Name, n.field.Name)
+		}
+		d.arguments(fieldPath, newParent, o.field, n.field)
+		d.directives(fieldPath, coordinate, o.directives, n.directives)
+		d.selectionSet(fieldPath, d.childType(oldParent, o.field.Name), d.childType(newParent, n.field.Name), o.children, n.children)
+	case o.spread != "":
+		d.directives(path, "", o.directives, n.directives)
+	default:
+		oldType, newType := oldParent, newParent
+		if o.condition != "" {
+			oldType, newType = d.model.Type(o.condition), d.model.Type(n.condition)
+		}
+		d.directives(path, "", o.directives, n.directives)
+		d.selectionSet(path, oldType, newType, o.children, n.children)
+	}
+}
+
+func (d *operationDiffer) arguments(path string, parent *Type, o, n *graphql.Field) {
+	var def *Field
+	if parent != nil {
+		def = parent.Field(n.Name)
+	}
+	annotate := func(name string) (string, string) {
+		if def == nil {
+			return "", ""
+		}
+		coordinate := fmt.Sprintf("%s.%s(%s:)", parent.Name, def.Name, name)
+		if a := findInputValue(def.Args, name); a != nil {
+			return coordinate, a.Type.String()
+		}
+		return coordinate, ""
+	}
+	for _, oa := range o.Arguments {
+		coordinate, schemaType := annotate(oa.Name)
+		na := n.Argument(oa.Name)
+		if na == nil {
+			d.add(ArgumentValueRemoved, path, fmt.Sprintf("argument %s removed", oa.Name), coordinate, schemaType, oa.Value.String(), "")
+			continue
+		}
+		if oa.Value.String() != na.Value.String() {
+			d.add(ArgumentValueChanged, path, fmt.Sprintf("argument %s changed from %s to %s", oa.Name, oa.Value, na.Value),
+				coordinate, schemaType, oa.Value.String(), na.Value.String())
+		}
+	}
+	for _, na := range n.Arguments {
+		if o.Argument(na.Name) == nil {
+			coordinate, schemaType := annotate(na.Name)
+			d.add(ArgumentValueAdded, path, fmt.Sprintf("argument %s added", na.Name), coordinate, schemaType, "", na.Value.String())
+		}
+	}
+}
+
+// fieldAnnotation returns the coordinate and type of a field, or empty
+// strings when parent does not define it
+func (d *operationDiffer) fieldAnnotation(parent *Type, name string) (string, string) {
+	if parent == nil {
+		return "", ""
+	}
+	if name == "__typename" {
+		return parent.Name + "." + name, "String!"
+	}
+	f := parent.Field(name)
+	if f == nil {
+		return "", ""
+	}
+	return parent.Name + "." + f.Name, f.Type.String()
+}
+
+// childType returns the named type of a field, or nil when unknown
+func (d *operationDiffer) childType(parent *Type, name string) *Type {
+	if parent == nil {
+		return nil
+	}
+	f := parent.Field(name)
+	if f == nil {
+		return nil
+	}
+	return d.model.Type(f.Type.NamedType())
+}
+
+func joinPath(path, key string) string {
+	return path + "." + key
+}
@@ -0,0 +1,85 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/graphql"
+)
+
+func TestDiffOperations(t *testing.T) {
+	s := loadRichSchema(t)
+
+	oldDoc, err := graphql.Parse(`
+query Issues($owner: String!, $name: String!, $count: Int = 10) {
+  repository(owner: $owner, name: $name) {
+    hasIssuesEnabled
+    issues(first: $count, states: [OPEN]) {
+      nodes { ...IssueFields }
+    }
+  }
+}
+
+fragment IssueFields on Issue { title }
+fragment Unused on User { login }
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse old document: %v", err)
+	}
+	newDoc, err := graphql.Parse(`
+query RepositoryIssues($owner: String!, $name: String!, $count: Int = 50, $after: String) {
+  repository(owner: $owner, name: $name) {
+    issues(first: $count, after: $after) {
+      totalCount
+      nodes { ...IssueFields ... on Issue { author { ... on User { login } } } }
+    }
+  }
+}
+
+fragment IssueFields on Issue { title: body }
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse new document: %v", err)
+	}
+
+	got := s.DiffOperations(oldDoc, newDoc)
+	want := []OperationChange{
+		{Type: OperationRenamed, Path: "RepositoryIssues", Message: `query "Issues" renamed to "RepositoryIssues"`, OldValue: "Issues", NewValue: "RepositoryIssues"},
+		{Type: VariableDefaultChanged, Path: "RepositoryIssues", Message: "variable $count default changed from 10 to 50", Coordinate: "$count", SchemaType: "Int", OldValue: "10", NewValue: "50"},
+		{Type: VariableAdded, Path: "RepositoryIssues", Message: "variable $after added", Coordinate: "$after", SchemaType: "String", NewValue: "String"},
+		{Type: SelectionRemoved, Path: "RepositoryIssues.repository.hasIssuesEnabled", Message: `field "hasIssuesEnabled" removed`, Coordinate: "Repository.hasIssuesEnabled", SchemaType: "Boolean!", OldValue: "hasIssuesEnabled"},
+		{Type: ArgumentValueRemoved, Path: "RepositoryIssues.repository.issues", Message: "argument states removed", Coordinate: "Repository.issues(states:)", SchemaType: "[IssueState!]", OldValue: "[OPEN]"},
+		{Type: ArgumentValueAdded, Path: "RepositoryIssues.repository.issues", Message: "argument after added", Coordinate: "Repository.issues(after:)", SchemaType: "String", NewValue: "$after"},
+		{Type: InlineFragmentAdded, Path: "RepositoryIssues.repository.issues.nodes", Message: `inline fragment "... on Issue" added`, SchemaType: "Issue"},
+		{Type: SelectionAdded, Path: "RepositoryIssues.repository.issues.totalCount", Message: `field "totalCount" added`, Coordinate: "IssueConnection.totalCount", SchemaType: "Int!", NewValue: "totalCount"},
+		{Type: SelectionFieldChanged, Path: "IssueFields.title", Message: `"title" now selects field "body" instead of "title"`, Coordinate: "Issue.body", SchemaType: "String!", OldValue: "title", NewValue: "body"},
+		{Type: FragmentRemoved, Path: "Unused", Message: `fragment "Unused" removed`, SchemaType: "User", OldValue: "User"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffOperations =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if changes := s.DiffOperations(newDoc, newDoc); len(changes) != 0 {
+		t.Errorf("Expected no changes between identical documents, got %+v", changes)
+	}
+}
+
+func TestDiffOperationsByName(t *testing.T) {
+	s := loadRichSchema(t)
+	oldDoc, err := graphql.Parse(`query A { viewer { login } } query B { viewer { login } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newDoc, err := graphql.Parse(`query A { viewer { login name: login } } mutation C { createIssue(input: {}) { issue { id } } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := s.DiffOperations(oldDoc, newDoc)
+	var types []OperationChangeType
+	for _, c := range got {
+		types = append(types, c.Type)
+	}
+	if want := []OperationChangeType{SelectionAdded, OperationRemoved, OperationAdded}; !reflect.DeepEqual(types, want) {
+		t.Errorf("Change types = %v, want %v (%+v)", types, want, got)
+	}
+}
@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PathStep is one field of a path resolved by ResolvePath
+type PathStep struct {
+	Parent string `json:"parent"` // Type the field is selected on
+	Field  string `json:"field"`
+	Type   string `json:"type"` // GraphQL notation, e.g. "[Label]"
+	Named  string `json:"named"`
+	Kind   string `json:"kind"` // Kind of the named type
+}
+
+// ResolvePath walks a dotted selection path such as
+// "Repository.issues.nodes.labels" and returns the field resolved at each
+// step. The first segment names the starting type; every following segment
+// is a field of the previous step's named type. Union types have no fields,
+// so a path cannot continue through them.
+func (s *Schema) ResolvePath(path string) ([]PathStep, error) {
+	segments := strings.Split(path, ".")
+	if len(segments) < 2 {
+		return nil, fmt.Errorf("path %q must start with a type name followed by at least one field", path)
+	}
+
+	current := segments[0]
+	entry := s.rawType(current)
+	if entry == nil {
+		return nil, s.Model().typeNotFound(current)
+	}
+
+	steps := make([]PathStep, 0, len(segments)-1)
+	for i, name := range segments[1:] {
+		if name == "" {
+			return nil, fmt.Errorf("path %q has an empty segment", path)
+		}
+		if i > 0 {
+			if kind, _ := entry["kind"].(string); kind != "OBJECT" && kind != "INTERFACE" {
+				return nil, fmt.Errorf("cannot select %q on %s (kind: %s) at %s", name, current, kind, strings.Join(segments[:i+1], "."))
+			}
+		}
+		field := s.rawField(current, name)
+		if field == nil {
+			return nil, fmt.Errorf("field %q not found on type %q at %s", name, current, strings.Join(segments[:i+1], "."))
+		}
+
+		ref := TypeRefFromMap(field["type"])
+		unwrapped := UnwrapTypeRef(ref)
+		steps = append(steps, PathStep{
+			Parent: current,
+			Field:  name,
+			Type:   FormatTypeRef(ref),
+			Named:  unwrapped.Name,
+			Kind:   unwrapped.Kind,
+		})
+		current = unwrapped.Name
+		entry = s.rawType(current)
+	}
+	return steps, nil
+}
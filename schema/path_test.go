@@ -0,0 +1,42 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolvePath(t *testing.T) {
+	s := loadRichSchema(t)
+
+	steps, err := s.ResolvePath("Repository.issues.nodes.author.login")
+	if err != nil {
+		t.Fatalf("ResolvePath failed: %v", err)
+	}
+	want := []PathStep{
+		{Parent: "Repository", Field: "issues", Type: "IssueConnection!", Named: "IssueConnection", Kind: "OBJECT"},
+		{Parent: "IssueConnection", Field: "nodes", Type: "[Issue]", Named: "Issue", Kind: "OBJECT"},
+		{Parent: "Issue", Field: "author", Type: "Actor", Named: "Actor", Kind: "INTERFACE"},
+		{Parent: "Actor", Field: "login", Type: "String!", Named: "String", Kind: "SCALAR"},
+	}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("ResolvePath = %+v, want %+v", steps, want)
+	}
+
+	tests := []struct {
+		path    string
+		wantErr string
+	}{
+		{"Repository", "at least one field"},
+		{"Robot.name", `type "Robot" not found`},
+		{"Repository.nope", `field "nope" not found on type "Repository" at Repository`},
+		{"Repository..name", "empty segment"},
+		{"Repository.name.length", `cannot select "length" on String (kind: SCALAR) at Repository.name`},
+		{"Repository.issueOrPullRequest.title", "kind: UNION"},
+	}
+	for _, tt := range tests {
+		if _, err := s.ResolvePath(tt.path); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ResolvePath(%q) error = %v, want %q", tt.path, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package schema
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// maxPooledBuffer is the largest buffer returned to the pool. Larger buffers
+// (unusually big custom schemas) are left to the garbage collector so that a
+// single outlier does not pin its memory for the lifetime of the process.
+const maxPooledBuffer = 64 << 20
+
+// bufferPool recycles the byte buffers that hold raw schema JSON while it is parsed
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// gzipReaderPool recycles gzip readers and their decompression state
+var gzipReaderPool sync.Pool
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// isGzip reports whether data starts with the gzip magic number
+func isGzip(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+// decompressGzip decompresses data into a pooled buffer.
+// The caller must release the buffer with putBuffer once it is no longer referenced.
+func decompressGzip(data []byte) (*bytes.Buffer, error) {
+	var reader *gzip.Reader
+	if r, ok := gzipReaderPool.Get().(*gzip.Reader); ok {
+		if err := r.Reset(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		reader = r
+	} else {
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		reader = r
+	}
+	defer gzipReaderPool.Put(reader)
+
+	buf := getBuffer()
+	// The gzip trailer records the uncompressed size (mod 2^32), which lets
+	// us size the buffer once instead of growing it repeatedly
+	if len(data) >= 4 {
+		if size := int(binary.LittleEndian.Uint32(data[len(data)-4:])); size <= maxPooledBuffer {
+			buf.Grow(size)
+		}
+	}
+	if _, err := io.Copy(buf, reader); err != nil {
+		putBuffer(buf)
+		return nil, fmt.Errorf("failed to decompress schema: %w", err)
+	}
+	return buf, nil
+}
+
+// readFileBuffer reads a file into a pooled buffer, decompressing it when it
+// is gzip or zstd compressed.
+// The caller must release the buffer with putBuffer once it is no longer referenced.
+func readFileBuffer(path string) (*bytes.Buffer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readStatBuffer(f)
+}
+
+// readStatBuffer reads a file opened from any file system with readBuffer,
+// sizing the buffer by the file's size
+func readStatBuffer(f fs.File) (*bytes.Buffer, error) {
+	size := int64(-1)
+	if info, err := f.Stat(); err == nil {
+		size = info.Size()
+	}
+	return readBuffer(f, size)
+}
+
+// readBuffer reads r into a pooled buffer, decompressing the data when it
+// is gzip or zstd compressed. size is the length of the data when known, or
+// negative.
+// The caller must release the buffer with putBuffer once it is no longer referenced.
+func readBuffer(r io.Reader, size int64) (*bytes.Buffer, error) {
+	buf := getBuffer()
+	if size > 0 && size <= maxPooledBuffer {
+		buf.Grow(int(size))
+	}
+	if _, err := buf.ReadFrom(r); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	if detectCompression(buf.Bytes()) != NoCompression {
+		defer putBuffer(buf)
+		return decompress(buf.Bytes())
+	}
+	return buf, nil
+}
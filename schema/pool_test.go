@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecompressGzip(t *testing.T) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write(testSchemaData)
+	gz.Close()
+
+	// Run twice so the second call reuses pooled readers and buffers
+	for i := 0; i < 2; i++ {
+		buf, err := decompressGzip(compressed.Bytes())
+		if err != nil {
+			t.Fatalf("decompressGzip failed: %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), testSchemaData) {
+			t.Errorf("Decompressed data does not match original")
+		}
+		putBuffer(buf)
+	}
+
+	if _, err := decompressGzip([]byte("not gzip")); err == nil {
+		t.Error("Expected error for invalid gzip data")
+	}
+}
+
+func TestPooledLoadsAreIndependent(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.json")
+	second := filepath.Join(dir, "second.json")
+	if err := os.WriteFile(first, testSchemaData, 0644); err != nil {
+		t.Fatal(err)
+	}
+	other := bytes.Replace(testSchemaData, []byte(`"PullRequest"`), []byte(`"MergeRequest"`), 1)
+	if err := os.WriteFile(second, other, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s1, err := NewWithFile(first)
+	if err != nil {
+		t.Fatalf("Failed to load first schema: %v", err)
+	}
+	// Loading the second schema reuses the buffer released by the first load
+	if _, err := NewWithFile(second); err != nil {
+		t.Fatalf("Failed to load second schema: %v", err)
+	}
+
+	if _, err := s1.Type("PullRequest"); err != nil {
+		t.Errorf("First schema was affected by buffer reuse: %v", err)
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := New(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
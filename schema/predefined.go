@@ -0,0 +1,106 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PredefinedQuery is a jq expression used by the Schema methods, together
+// with the names of the $variables it expects
+type PredefinedQuery struct {
+	Expression string
+	Variables  []string
+}
+
+// PredefinedQueries are the predefined jq queries, see Queries. The comment
+// of each member gives the shape of its output.
+type PredefinedQueries struct {
+	Type           PredefinedQuery // {type: {name, kind, description, fields, inputFields, enumValues}}
+	Fields         PredefinedQuery // {fields: [{name, description, type, arguments, isDeprecated, deprecationReason}]}
+	EnumValues     PredefinedQuery // {kind, enumValues: [{name, description, isDeprecated, deprecationReason}]}
+	Search         PredefinedQuery // {count, pattern, results: [{name, kind, description}]}
+	Mutation       PredefinedQuery // {mutation: {name, description, inputs: [{name, type, description, required}]}}
+	QueryField     PredefinedQuery // {queryField: {name, description, type, arguments: [{name, type, description, required, defaultValue}], isDeprecated, deprecationReason}}
+	FieldSearch    PredefinedQuery // [{type, kind, fields: [{name, type, description}]}]
+	Implementers   PredefinedQuery // {interface, kind, implementers: [name]}
+	UnionMembers   PredefinedQuery // {union, kind, members: [name]}
+	Directives     PredefinedQuery // {directives: [{name, description, locations, arguments}]}
+	ListMutations  PredefinedQuery // One mutation name per result
+	ListTypes      PredefinedQuery // One type name per result
+	ListObjects    PredefinedQuery // One object type name per result
+	ListInputs     PredefinedQuery // One input object type name per result
+	ListEnums      PredefinedQuery // One enum type name per result
+	ListInterfaces PredefinedQuery // One interface type name per result
+	ListUnions     PredefinedQuery // One union type name per result
+	ListScalars    PredefinedQuery // One scalar type name per result
+	ListQueries    PredefinedQuery // One Query root field name per result
+	ListOfKind     PredefinedQuery // One name per result for types whose kind is in $kinds
+}
+
+// Queries returns the predefined jq queries so that other tools can run or
+// compose them with Schema.Query instead of copying the expressions. Every call
+// returns a new copy, so callers cannot change the queries others run.
+//
+// The output shape of every query is covered by golden tests and is stable
+// within a major version: members may be added to the results, but existing
+// members are not removed, renamed, or retyped. The expressions themselves may
+// change as long as their output does not.
+//
+// Expressions that format types start with the definition of a jq function
+// formatType, so they compose with a pipe:
+//
+//	s.Query(schema.Queries().Type.Expression+" | .type.fields[].name", map[string]interface{}{"type": "Issue"})
+func Queries() PredefinedQueries {
+	return PredefinedQueries{
+		Type:           PredefinedQuery{Expression: typeQuery, Variables: []string{"type"}},
+		Fields:         PredefinedQuery{Expression: fieldsQuery, Variables: []string{"type"}},
+		EnumValues:     PredefinedQuery{Expression: enumValuesQuery, Variables: []string{"type"}},
+		Search:         PredefinedQuery{Expression: searchQuery, Variables: []string{"pattern"}},
+		Mutation:       PredefinedQuery{Expression: mutationQuery, Variables: []string{"mutation"}},
+		QueryField:     PredefinedQuery{Expression: queryFieldQuery, Variables: []string{"field"}},
+		FieldSearch:    PredefinedQuery{Expression: fieldSearchQuery, Variables: []string{"pattern"}},
+		Implementers:   PredefinedQuery{Expression: interfaceImplementersQuery, Variables: []string{"interface"}},
+		UnionMembers:   PredefinedQuery{Expression: unionMembersQuery, Variables: []string{"union"}},
+		Directives:     PredefinedQuery{Expression: directivesQuery},
+		ListMutations:  PredefinedQuery{Expression: ListMutationsQuery},
+		ListTypes:      PredefinedQuery{Expression: ListTypesQuery},
+		ListObjects:    PredefinedQuery{Expression: ListObjectTypesQuery},
+		ListInputs:     PredefinedQuery{Expression: ListInputTypesQuery},
+		ListEnums:      PredefinedQuery{Expression: ListEnumTypesQuery},
+		ListInterfaces: PredefinedQuery{Expression: ListInterfaceTypesQuery},
+		ListUnions:     PredefinedQuery{Expression: ListUnionTypesQuery},
+		ListScalars:    PredefinedQuery{Expression: ListScalarTypesQuery},
+		ListQueries:    PredefinedQuery{Expression: ListQueriesQuery},
+		ListOfKind:     PredefinedQuery{Expression: ListTypesOfKindQuery, Variables: []string{"kinds"}},
+	}
+}
+
+// Run runs a predefined query after checking that exactly the variables it
+// expects are provided. Results follow the same conventions as Query.
+func (s *Schema) Run(q PredefinedQuery, variables map[string]interface{}) (interface{}, error) {
+	for _, name := range q.Variables {
+		if _, ok := variables[name]; !ok {
+			return nil, fmt.Errorf("missing variable $%s", name)
+		}
+	}
+	if len(variables) > len(q.Variables) {
+		var unexpected []string
+		for name := range variables {
+			if !containsString(q.Variables, name) {
+				unexpected = append(unexpected, "$"+name)
+			}
+		}
+		sort.Strings(unexpected)
+		return nil, fmt.Errorf("unexpected variables %v", unexpected)
+	}
+	return s.Query(q.Expression, variables)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
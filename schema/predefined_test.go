@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata/golden")
+
+// TestQueriesGolden pins the output shape of every predefined query against
+// the embedded schema. After updating the embedded schema or intentionally
+// extending a query, regenerate the files with:
+//
+//	go test ./schema -run TestQueriesGolden -update
+func TestQueriesGolden(t *testing.T) {
+	s, err := New()
+	if err != nil {
+		t.Fatalf("Failed to load embedded schema: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		query     PredefinedQuery
+		variables map[string]interface{}
+	}{
+		{"type", Queries().Type, map[string]interface{}{"type": "PageInfo"}},
+		{"fields", Queries().Fields, map[string]interface{}{"type": "PageInfo"}},
+		{"enum_values", Queries().EnumValues, map[string]interface{}{"type": "IssueState"}},
+		{"search", Queries().Search, map[string]interface{}{"pattern": "^PageInfo$"}},
+		{"mutation", Queries().Mutation, map[string]interface{}{"mutation": "addStar"}},
+		{"query_field", Queries().QueryField, map[string]interface{}{"field": "repository"}},
+		{"field_search", Queries().FieldSearch, map[string]interface{}{"pattern": "^hasNextPage$"}},
+		{"implementers", Queries().Implementers, map[string]interface{}{"interface": "Starrable"}},
+		{"union_members", Queries().UnionMembers, map[string]interface{}{"union": "IssueOrPullRequest"}},
+		{"directives", Queries().Directives, nil},
+		{"list_mutations", Queries().ListMutations, nil},
+		{"list_types", Queries().ListTypes, nil},
+		{"list_objects", Queries().ListObjects, nil},
+		{"list_inputs", Queries().ListInputs, nil},
+		{"list_enums", Queries().ListEnums, nil},
+		{"list_interfaces", Queries().ListInterfaces, nil},
+		{"list_unions", Queries().ListUnions, nil},
+		{"list_scalars", Queries().ListScalars, nil},
+		{"list_queries", Queries().ListQueries, nil},
+		{"list_of_kind", Queries().ListOfKind, map[string]interface{}{"kinds": []interface{}{"UNION", "SCALAR"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.Run(tt.query, tt.variables)
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			path := filepath.Join("testdata", "golden", tt.name+".json")
+			if *updateGolden {
+				var buf bytes.Buffer
+				if err := yamlformat.NewEncoderForFormat(&buf, yamlformat.FormatJSON).Encode(result); err != nil {
+					t.Fatalf("Failed to encode result: %v", err)
+				}
+				if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+					t.Fatalf("Failed to write golden file: %v", err)
+				}
+				return
+			}
+
+			golden, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read golden file (run with -update to create it): %v", err)
+			}
+			var want interface{}
+			if err := yamlformat.Unmarshal(golden, &want); err != nil {
+				t.Fatalf("Failed to parse golden file: %v", err)
+			}
+
+			// Round-trip the result so numbers decode the same way as the golden file
+			data, err := yamlformat.MarshalJSON(result)
+			if err != nil {
+				t.Fatalf("Failed to encode result: %v", err)
+			}
+			var got interface{}
+			if err := yamlformat.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Output of %s does not match %s; if the change is intended, run with -update", tt.name, path)
+			}
+		})
+	}
+}
+
+func TestRunChecksVariables(t *testing.T) {
+	s := loadRichSchema(t)
+
+	if _, err := s.Run(Queries().Type, nil); err == nil || !strings.Contains(err.Error(), "missing variable $type") {
+		t.Errorf("Expected missing variable error, got %v", err)
+	}
+	if _, err := s.Run(Queries().Type, map[string]interface{}{"type": "Issue", "extra": 1}); err == nil || !strings.Contains(err.Error(), "$extra") {
+		t.Errorf("Expected unexpected variable error, got %v", err)
+	}
+
+	// Predefined queries compose with a pipe
+	result, err := s.Query(Queries().Type.Expression+" | .type.fields[0].type", map[string]interface{}{"type": "Issue"})
+	if err != nil {
+		t.Fatalf("Composed query failed: %v", err)
+	}
+	if result != "ID!" {
+		t.Errorf("Composed query = %v, want ID!", result)
+	}
+}
+
+func TestQueriesReturnsCopies(t *testing.T) {
+	q := Queries()
+	q.Type.Expression = "."
+	q.Type.Variables[0] = "changed"
+	if got := Queries().Type; got.Expression != typeQuery || got.Variables[0] != "type" {
+		t.Errorf("Changes to one copy leaked into Queries(): %+v", got)
+	}
+}
@@ -3,8 +3,9 @@ package schema
 // Predefined jq queries for common operations
 
 const (
-	// typeQuery formats a GraphQL type with all its fields
-	typeQuery = `
+	// formatTypeDef renders a type reference in GraphQL notation such as "[Issue!]!".
+	// FormatTypeRef is the Go equivalent; keep both in sync.
+	formatTypeDef = `
 def formatType:
   if type == "object" and .kind == "NON_NULL" then
     (.ofType | formatType) + "!"
@@ -15,7 +16,10 @@ def formatType:
   else
     .
   end;
+`
 
+	// typeQuery formats a GraphQL type with all its fields
+	typeQuery = formatTypeDef + `
 .data.__schema.types[] | 
 select(.name == $type) |
 {
@@ -70,6 +74,51 @@ select(.name == $type) |
   }
 }`
 
+	// fieldsQuery lists the fields of a type with their arguments and deprecation status
+	fieldsQuery = formatTypeDef + `
+.data.__schema.types[] |
+select(.name == $type) |
+{
+  fields: (
+    if .fields then
+      [.fields[] | {
+        name,
+        description,
+        type: (.type | formatType),
+        arguments: (
+          if (.args | length) > 0 then
+            [.args[] | {
+              name,
+              description,
+              type: (.type | formatType)
+            }]
+          else
+            null
+          end
+        ),
+        isDeprecated: (.isDeprecated // false),
+        deprecationReason
+      }]
+    else
+      null
+    end
+  )
+}`
+
+	// enumValuesQuery lists the values of an enum type with their deprecation status
+	enumValuesQuery = `
+.data.__schema.types[] |
+select(.name == $type) |
+{
+  kind,
+  enumValues: [.enumValues[]? | {
+    name,
+    description,
+    isDeprecated: (.isDeprecated // false),
+    deprecationReason
+  }]
+}`
+
 	// searchQuery searches for types matching a pattern
 	searchQuery = `
 [.data.__schema.types[] | 
@@ -91,18 +140,7 @@ select(.name == $type) |
   }`
 
 	// mutationQuery formats a mutation with expanded input details
-	mutationQuery = `
-def formatType:
-  if type == "object" and .kind == "NON_NULL" then
-    (.ofType | formatType) + "!"
-  elif type == "object" and .kind == "LIST" then
-    "[" + (.ofType | formatType) + "]"
-  elif type == "object" then
-    .name // .kind
-  else
-    .
-  end;
-
+	mutationQuery = formatTypeDef + `
 # Find the mutation
 (.data.__schema.types[] | select(.name == "Mutation").fields[] | select(.name == $mutation)) as $mut |
 
@@ -143,23 +181,36 @@ else
   }
 end`
 
+	// queryFieldQuery describes a field on the Query root with its arguments
+	queryFieldQuery = formatTypeDef + `
+(.data.__schema.queryType.name // "Query") as $root |
+.data.__schema.types[] | select(.name == $root).fields[] | select(.name == $field) |
+{
+  queryField: {
+    name,
+    description,
+    type: (.type | formatType),
+    arguments: [.args[] | {
+      name,
+      type: (.type | formatType),
+      description,
+      required: (.type.kind == "NON_NULL" and .defaultValue == null),
+      defaultValue
+    }],
+    isDeprecated: (.isDeprecated // false),
+    deprecationReason
+  }
+}`
+
 	// fieldSearchQuery searches for fields across all types
-	fieldSearchQuery = `
+	fieldSearchQuery = formatTypeDef + `
 [.data.__schema.types[] |
 {
   type: .name,
   kind: .kind,
   fields: [.fields[]? | select(.name | test($pattern; "i")) | {
     name,
-    type: (
-      if .type.kind == "NON_NULL" then
-        .type.ofType.name + "!"
-      elif .type.kind == "LIST" then
-        "[" + (.type.ofType.name // .type.ofType.kind) + "]"
-      else
-        .type.name
-      end
-    ),
+    type: (.type | formatType),
     description
   }]
 } |
@@ -172,14 +223,67 @@ select(.name == $interface) |
 if .possibleTypes then
   {
     interface: .name,
+    kind: .kind,
     implementers: [.possibleTypes[] | .name]
   }
 else
   {
     interface: .name,
+    kind: .kind,
     implementers: []
   }
 end`
+
+	// directivesQuery lists the directives supported by the schema
+	directivesQuery = formatTypeDef + `
+{
+  directives: [.data.__schema.directives[]? | {
+    name,
+    description,
+    locations,
+    arguments: (
+      if (.args | length) > 0 then
+        [.args[] | {
+          name,
+          description,
+          type: (.type | formatType),
+          defaultValue
+        }]
+      else
+        null
+      end
+    )
+  }]
+}`
+
+	// deprecatedQuery lists the deprecated fields, field arguments, input
+	// fields, and enum values of every type that has any; arguments are
+	// named field.argument
+	deprecatedQuery = `
+{
+  deprecated: [.data.__schema.types[] |
+    {
+      name,
+      kind,
+      fields: [.fields[]? | select(.isDeprecated == true) | {name, deprecationReason}],
+      arguments: [.fields[]? | .name as $field | .args[]? | select(.isDeprecated == true) | {name: ($field + "." + .name), deprecationReason}],
+      inputFields: [.inputFields[]? | select(.isDeprecated == true) | {name, deprecationReason}],
+      enumValues: [.enumValues[]? | select(.isDeprecated == true) | {name, deprecationReason}]
+    } |
+    select((.fields + .arguments + .inputFields + .enumValues) | length > 0) |
+    with_entries(select(.value != []))
+  ]
+}`
+
+	// unionMembersQuery finds the possible types of a union
+	unionMembersQuery = `
+.data.__schema.types[] |
+select(.name == $union) |
+{
+  union: .name,
+  kind: .kind,
+  members: [.possibleTypes[]? | .name]
+}`
 )
 
 // Additional helper queries that can be exposed
@@ -196,4 +300,22 @@ const (
 
 	// ListInputTypesQuery lists only input types
 	ListInputTypesQuery = `.data.__schema.types[] | select(.kind == "INPUT_OBJECT") | .name`
+
+	// ListEnumTypesQuery lists only enum types
+	ListEnumTypesQuery = `.data.__schema.types[] | select(.kind == "ENUM") | .name`
+
+	// ListInterfaceTypesQuery lists only interface types
+	ListInterfaceTypesQuery = `.data.__schema.types[] | select(.kind == "INTERFACE") | .name`
+
+	// ListUnionTypesQuery lists only union types
+	ListUnionTypesQuery = `.data.__schema.types[] | select(.kind == "UNION") | .name`
+
+	// ListScalarTypesQuery lists only scalar types
+	ListScalarTypesQuery = `.data.__schema.types[] | select(.kind == "SCALAR") | .name`
+
+	// ListQueriesQuery lists all fields of the Query root
+	ListQueriesQuery = `(.data.__schema.queryType.name // "Query") as $root | .data.__schema.types[] | select(.name == $root) | .fields[] | .name`
+
+	// ListTypesOfKindQuery lists the types whose kind is in the $kinds array
+	ListTypesOfKindQuery = `.data.__schema.types[] | select(.kind as $k | $kinds | index($k)) | .name`
 )
\ No newline at end of file
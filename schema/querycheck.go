@@ -0,0 +1,562 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+)
+
+// QueryError is a problem ValidateQuery found in a document, at the line and
+// column of the offending node
+type QueryError struct {
+	Message string `json:"message"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+}
+
+func (e QueryError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// Meta fields that every composite type or the query root has without
+// listing them in the schema
+var (
+	typenameField = &Field{Name: "__typename", Type: &TypeRef{Kind: "NON_NULL", OfType: &TypeRef{Kind: "SCALAR", Name: "String"}}}
+	schemaField   = &Field{Name: "__schema", Type: &TypeRef{Kind: "NON_NULL", OfType: &TypeRef{Kind: "OBJECT", Name: "__Schema"}}}
+	typeField     = &Field{
+		Name: "__type",
+		Args: []*InputValue{{Name: "name", Type: &TypeRef{Kind: "NON_NULL", OfType: &TypeRef{Kind: "SCALAR", Name: "String"}}}},
+		Type: &TypeRef{Kind: "OBJECT", Name: "__Type"},
+	}
+)
+
+// ValidateQuery checks an executable GraphQL document against the schema:
+// selected fields exist on their parent types and have subselections exactly
+// when their types are composite, arguments and input object fields are
+// defined and their literal values fit their types, required arguments are
+// given, directives are defined and used in valid locations, fragment type
+// conditions name composite types that can apply where the fragments are
+// spread, and variables are defined, used, and of types allowed where they
+// appear. Unknown, unused, and cyclic fragments are reported as well.
+//
+// The problems are sorted by position and empty for a valid document. A
+// document that cannot be parsed fails with a *graphql.SyntaxError instead.
+// Whether selections with the same response key can be merged is not checked.
+func (s *Schema) ValidateQuery(doc string) ([]QueryError, error) {
+	parsed, err := graphql.Parse(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &queryValidator{schema: s, model: s.Model(), fragments: make(map[string]*graphql.FragmentDefinition)}
+	var fragments []*graphql.FragmentDefinition
+	for _, def := range parsed.Definitions {
+		f, ok := def.(*graphql.FragmentDefinition)
+		if !ok {
+			continue
+		}
+		if v.fragments[f.Name] != nil {
+			v.errorf(f.Pos, "fragment %q is defined more than once", f.Name)
+			continue
+		}
+		v.fragments[f.Name] = f
+		fragments = append(fragments, f)
+	}
+
+	// Fragments are walked once; operations then collect the variable usages
+	// of the fragments they reach
+	scopes := make(map[string]*queryScope, len(fragments))
+	for _, f := range fragments {
+		scopes[f.Name] = v.fragment(f)
+	}
+	v.checkCycles(fragments, scopes)
+
+	ops := parsed.Operations()
+	names := make(map[string]bool)
+	used := make(map[string]bool)
+	for _, op := range ops {
+		if op.Name == "" && len(ops) > 1 {
+			v.errorf(op.Pos, "anonymous operation must be the only operation in the document")
+		}
+		if op.Name != "" {
+			if names[op.Name] {
+				v.errorf(op.Pos, "operation %q is defined more than once", op.Name)
+			}
+			names[op.Name] = true
+		}
+
+		scope, types := v.operation(op)
+		usages := scope.usages
+		for _, name := range reachableFragments(scope, scopes) {
+			used[name] = true
+			usages = append(usages, scopes[name].usages...)
+		}
+		v.checkVariables(op, types, usages)
+	}
+	for _, f := range fragments {
+		if !used[f.Name] {
+			v.errorf(f.Pos, "fragment %q is never used", f.Name)
+		}
+	}
+
+	return v.result(), nil
+}
+
+// queryValidator accumulates the problems of one document
+type queryValidator struct {
+	schema    *Schema
+	model     *Model
+	fragments map[string]*graphql.FragmentDefinition
+	errors    []QueryError
+	scope     *queryScope // Of the operation or fragment being walked
+}
+
+// queryScope records the variable usages and fragment spreads of an
+// operation or fragment definition
+type queryScope struct {
+	usages  []variableUsage
+	spreads []*graphql.FragmentSpread
+}
+
+// variableUsage is a variable appearing where a value of typ is expected
+type variableUsage struct {
+	name       string
+	typ        *TypeRef
+	hasDefault bool // The argument or input field has a default value
+	pos        graphql.Position
+}
+
+func (v *queryValidator) errorf(pos graphql.Position, format string, args ...interface{}) {
+	v.errors = append(v.errors, QueryError{Message: fmt.Sprintf(format, args...), Line: pos.Line, Column: pos.Column})
+}
+
+// result sorts the problems by position and drops duplicates, which occur
+// when several operations spread the same faulty fragment
+func (v *queryValidator) result() []QueryError {
+	sort.SliceStable(v.errors, func(i, j int) bool {
+		a, b := v.errors[i], v.errors[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return slices.Compact(v.errors)
+}
+
+func (v *queryValidator) fragment(f *graphql.FragmentDefinition) *queryScope {
+	v.scope = &queryScope{}
+	v.directives(f.Directives, "FRAGMENT_DEFINITION")
+	if t := v.compositeType(f.TypeCondition, f.Pos); t != nil {
+		v.selectionSet(t, f.SelectionSet)
+	}
+	return v.scope
+}
+
+// operation walks an operation and returns its scope and the types of its
+// variables
+func (v *queryValidator) operation(op *graphql.OperationDefinition) (*queryScope, map[string]*TypeRef) {
+	v.scope = &queryScope{}
+	v.directives(op.Directives, strings.ToUpper(string(op.Operation)))
+
+	types := make(map[string]*TypeRef)
+	for _, d := range op.VariableDefinitions {
+		v.directives(d.Directives, "VARIABLE_DEFINITION")
+		if _, ok := types[d.Name]; ok {
+			v.errorf(d.Pos, "variable $%s is defined more than once", d.Name)
+			continue
+		}
+		types[d.Name] = v.variableType(d)
+	}
+
+	root := v.model.Type(v.schema.RootTypeName(string(op.Operation)))
+	if root == nil {
+		v.errorf(op.Pos, "schema does not support %s operations", op.Operation)
+	} else {
+		v.selectionSet(root, op.SelectionSet)
+	}
+	return v.scope, types
+}
+
+// variableType checks the type and default value of a variable definition
+// and returns the type, or nil when it is not a known input type
+func (v *queryValidator) variableType(d *graphql.VariableDefinition) *TypeRef {
+	named := v.model.Type(d.Type.NamedType())
+	if named == nil {
+		v.errorf(d.Type.Pos, "unknown type %q", d.Type.NamedType())
+		return nil
+	}
+	switch named.Kind {
+	case "SCALAR", "ENUM", "INPUT_OBJECT":
+	default:
+		v.errorf(d.Type.Pos, "variable $%s cannot be of non-input type %q", d.Name, named.Name)
+		return nil
+	}
+	ref := typeRefOf(d.Type, named.Kind)
+	if d.DefaultValue != nil {
+		v.value(d.DefaultValue, ref, false)
+	}
+	return ref
+}
+
+// typeRefOf converts the type of a variable definition whose named type is of
+// the given kind
+func typeRefOf(t *graphql.Type, kind string) *TypeRef {
+	ref := &TypeRef{Kind: kind, Name: t.Name}
+	if t.Elem != nil {
+		ref = &TypeRef{Kind: "LIST", OfType: typeRefOf(t.Elem, kind)}
+	}
+	if t.NonNull {
+		ref = &TypeRef{Kind: "NON_NULL", OfType: ref}
+	}
+	return ref
+}
+
+// checkVariables matches the variables used by an operation, including
+// through fragments, against its definitions
+func (v *queryValidator) checkVariables(op *graphql.OperationDefinition, types map[string]*TypeRef, usages []variableUsage) {
+	defaults := make(map[string]bool)
+	for _, d := range op.VariableDefinitions {
+		defaults[d.Name] = d.DefaultValue != nil && d.DefaultValue.Kind != graphql.NullValue
+	}
+
+	used := make(map[string]bool)
+	for _, u := range usages {
+		typ, ok := types[u.name]
+		if !ok {
+			v.errorf(u.pos, "variable $%s is not defined by %s", u.name, operationLabel(op))
+			continue
+		}
+		used[u.name] = true
+		if typ == nil || u.typ == nil {
+			continue
+		}
+		expected := u.typ
+		if expected.Kind == "NON_NULL" && typ.Kind != "NON_NULL" {
+			// A nullable variable may fill a non-null position that has a
+			// default, or when the variable itself has a non-null default
+			if !defaults[u.name] && !u.hasDefault {
+				v.errorf(u.pos, "variable $%s of type %s cannot be used where %s is expected", u.name, typ, u.typ)
+				continue
+			}
+			expected = expected.OfType
+		}
+		if !v.schema.TypesCompatible(expected, typ, OutputPosition) {
+			v.errorf(u.pos, "variable $%s of type %s cannot be used where %s is expected", u.name, typ, u.typ)
+		}
+	}
+	for _, d := range op.VariableDefinitions {
+		if !used[d.Name] {
+			v.errorf(d.Pos, "variable $%s is never used in %s", d.Name, operationLabel(op))
+		}
+	}
+}
+
+func operationLabel(op *graphql.OperationDefinition) string {
+	if op.Name == "" {
+		return "anonymous " + string(op.Operation)
+	}
+	return fmt.Sprintf("%s %q", op.Operation, op.Name)
+}
+
+// reachableFragments returns the names of the defined fragments that scope
+// spreads, directly or through other fragments
+func reachableFragments(scope *queryScope, scopes map[string]*queryScope) []string {
+	var names []string
+	seen := make(map[string]bool)
+	pending := []*queryScope{scope}
+	for len(pending) > 0 {
+		s := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, spread := range s.spreads {
+			if seen[spread.Name] || scopes[spread.Name] == nil {
+				continue
+			}
+			seen[spread.Name] = true
+			names = append(names, spread.Name)
+			pending = append(pending, scopes[spread.Name])
+		}
+	}
+	return names
+}
+
+// checkCycles reports fragment spreads that lead back to a fragment being
+// spread, once per cycle at the spread closing it
+func (v *queryValidator) checkCycles(fragments []*graphql.FragmentDefinition, scopes map[string]*queryScope) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		for _, spread := range scopes[name].spreads {
+			if scopes[spread.Name] == nil {
+				continue
+			}
+			switch state[spread.Name] {
+			case visiting:
+				v.errorf(spread.Pos, "fragment %q spreads itself through %q", spread.Name, name)
+			case 0:
+				visit(spread.Name)
+			}
+		}
+		state[name] = done
+	}
+	for _, f := range fragments {
+		if state[f.Name] == 0 {
+			visit(f.Name)
+		}
+	}
+}
+
+func (v *queryValidator) selectionSet(parent *Type, set graphql.SelectionSet) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *graphql.Field:
+			v.field(parent, sel)
+		case *graphql.InlineFragment:
+			v.directives(sel.Directives, "INLINE_FRAGMENT")
+			t := parent
+			if sel.TypeCondition != "" {
+				if t = v.compositeType(sel.TypeCondition, sel.Pos); t == nil {
+					continue
+				}
+				if !v.overlaps(parent, t) {
+					v.errorf(sel.Pos, "fragment on %q can never apply to type %q", t.Name, parent.Name)
+				}
+			}
+			v.selectionSet(t, sel.SelectionSet)
+		case *graphql.FragmentSpread:
+			v.directives(sel.Directives, "FRAGMENT_SPREAD")
+			v.scope.spreads = append(v.scope.spreads, sel)
+			f := v.fragments[sel.Name]
+			if f == nil {
+				v.errorf(sel.Pos, "unknown fragment %q", sel.Name)
+				continue
+			}
+			if t := v.model.Type(f.TypeCondition); t != nil && isCompositeKind(t.Kind) && !v.overlaps(parent, t) {
+				v.errorf(sel.Pos, "fragment %q on %q can never apply to type %q", f.Name, t.Name, parent.Name)
+			}
+		}
+	}
+}
+
+func (v *queryValidator) field(parent *Type, f *graphql.Field) {
+	v.directives(f.Directives, "FIELD")
+
+	def := parent.Field(f.Name)
+	switch {
+	case f.Name == "__typename":
+		def = typenameField
+	case parent.Name == v.model.QueryType && f.Name == "__schema":
+		def = schemaField
+	case parent.Name == v.model.QueryType && f.Name == "__type":
+		def = typeField
+	}
+	if def == nil {
+		v.errorf(f.Pos, "field %q not found on type %q", f.Name, parent.Name)
+		return
+	}
+	v.arguments(f.Arguments, def.Args, f.Pos, fmt.Sprintf("field %q", f.Name))
+
+	named := v.model.Type(def.Type.NamedType())
+	if named == nil {
+		return
+	}
+	switch {
+	case isCompositeKind(named.Kind) && len(f.SelectionSet) == 0:
+		v.errorf(f.Pos, "field %q of type %q must have a selection of subfields", f.Name, def.Type)
+	case isCompositeKind(named.Kind):
+		v.selectionSet(named, f.SelectionSet)
+	case len(f.SelectionSet) > 0:
+		v.errorf(f.Pos, "field %q of type %q must not have a selection", f.Name, def.Type)
+	}
+}
+
+// compositeType returns the type a fragment conditions on, reporting
+// unknown and non-composite types
+func (v *queryValidator) compositeType(name string, pos graphql.Position) *Type {
+	t := v.model.Type(name)
+	if t == nil {
+		v.errorf(pos, "unknown type %q", name)
+		return nil
+	}
+	if !isCompositeKind(t.Kind) {
+		v.errorf(pos, "fragment cannot condition on non-composite type %q", name)
+		return nil
+	}
+	return t
+}
+
+func isCompositeKind(kind string) bool {
+	return kind == "OBJECT" || kind == "INTERFACE" || kind == "UNION"
+}
+
+// overlaps reports whether some object type is both a and b
+func (v *queryValidator) overlaps(a, b *Type) bool {
+	objects := func(t *Type) []string {
+		if t.Kind == "OBJECT" {
+			return []string{t.Name}
+		}
+		return t.PossibleTypes
+	}
+	for _, name := range objects(a) {
+		if slices.Contains(objects(b), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// directives checks directives used at location, such as FIELD or QUERY
+func (v *queryValidator) directives(directives []*graphql.Directive, location string) {
+	seen := make(map[string]bool)
+	for _, d := range directives {
+		var def *Directive
+		for _, candidate := range v.model.Directives {
+			if candidate.Name == d.Name {
+				def = candidate
+				break
+			}
+		}
+		if def == nil {
+			v.errorf(d.Pos, "unknown directive @%s", d.Name)
+			continue
+		}
+		if !slices.Contains(def.Locations, location) {
+			v.errorf(d.Pos, "directive @%s cannot be used on %s", d.Name, location)
+		}
+		if seen[d.Name] && !def.IsRepeatable {
+			v.errorf(d.Pos, "directive @%s is used more than once", d.Name)
+		}
+		seen[d.Name] = true
+		v.arguments(d.Arguments, def.Args, d.Pos, "directive @"+d.Name)
+	}
+}
+
+// arguments checks the arguments given to owner, a field or directive at pos
+func (v *queryValidator) arguments(args []*graphql.Argument, defs []*InputValue, pos graphql.Position, owner string) {
+	seen := make(map[string]bool)
+	for _, a := range args {
+		if seen[a.Name] {
+			v.errorf(a.Pos, "argument %q is given more than once", a.Name)
+			continue
+		}
+		seen[a.Name] = true
+		def := findInputValue(defs, a.Name)
+		if def == nil {
+			v.errorf(a.Pos, "unknown argument %q on %s", a.Name, owner)
+			continue
+		}
+		v.value(a.Value, def.Type, def.DefaultValue != nil)
+	}
+	for _, def := range defs {
+		if def.Required() && !seen[def.Name] {
+			v.errorf(pos, "%s requires argument %q of type %s", owner, def.Name, def.Type)
+		}
+	}
+}
+
+// value checks a value given where typ is expected. Variables are recorded in
+// the scope and checked against the operation's definitions later.
+func (v *queryValidator) value(val *graphql.Value, typ *TypeRef, hasDefault bool) {
+	if typ == nil {
+		return
+	}
+	if val.Kind == graphql.VariableValue {
+		v.scope.usages = append(v.scope.usages, variableUsage{name: val.Raw, typ: typ, hasDefault: hasDefault, pos: val.Pos})
+		return
+	}
+	switch {
+	case typ.Kind == "NON_NULL":
+		if val.Kind == graphql.NullValue {
+			v.errorf(val.Pos, "expected value of type %s, found null", typ)
+			return
+		}
+		v.value(val, typ.OfType, false)
+		return
+	case val.Kind == graphql.NullValue:
+		return
+	case typ.Kind == "LIST":
+		// A single value is coerced into a list of one item
+		items := []*graphql.Value{val}
+		if val.Kind == graphql.ListValue {
+			items = val.List
+		}
+		for _, item := range items {
+			v.value(item, typ.OfType, false)
+		}
+		return
+	}
+
+	t := v.model.Type(typ.Name)
+	if t == nil {
+		return
+	}
+	switch t.Kind {
+	case "SCALAR":
+		if !scalarAccepts(t.Name, val) {
+			v.errorf(val.Pos, "expected value of type %s, found %s", typ, val)
+		}
+	case "ENUM":
+		if val.Kind != graphql.EnumValue || !slices.ContainsFunc(t.EnumValues, func(e *EnumValue) bool { return e.Name == val.Raw }) {
+			v.errorf(val.Pos, "expected value of type %s, found %s", typ, val)
+		}
+	case "INPUT_OBJECT":
+		if val.Kind != graphql.ObjectValue {
+			v.errorf(val.Pos, "expected value of type %s, found %s", typ, val)
+			return
+		}
+		v.inputObject(t, val)
+	}
+}
+
+func (v *queryValidator) inputObject(t *Type, val *graphql.Value) {
+	seen := make(map[string]bool)
+	for _, f := range val.Fields {
+		if seen[f.Name] {
+			v.errorf(f.Pos, "input field %q is given more than once", f.Name)
+			continue
+		}
+		seen[f.Name] = true
+		def := t.InputField(f.Name)
+		if def == nil {
+			v.errorf(f.Pos, "field %q is not defined by input type %q", f.Name, t.Name)
+			continue
+		}
+		v.value(f.Value, def.Type, def.DefaultValue != nil)
+	}
+	for _, def := range t.InputFields {
+		if def.Required() && !seen[def.Name] {
+			v.errorf(val.Pos, "input type %q requires field %q of type %s", t.Name, def.Name, def.Type)
+		}
+	}
+	if t.OneOf && (len(val.Fields) != 1 || val.Fields[0].Value.Kind == graphql.NullValue) {
+		v.errorf(val.Pos, "exactly one field of oneOf input type %q must be given and non-null", t.Name)
+	}
+}
+
+// scalarAccepts reports whether a literal is valid for a scalar. Custom
+// scalars define their own literal formats, so they accept any value.
+func scalarAccepts(name string, val *graphql.Value) bool {
+	switch name {
+	case "Int":
+		_, err := strconv.ParseInt(val.Raw, 10, 32)
+		return val.Kind == graphql.IntValue && err == nil
+	case "Float":
+		return val.Kind == graphql.IntValue || val.Kind == graphql.FloatValue
+	case "String":
+		return val.Kind == graphql.StringValue
+	case "Boolean":
+		return val.Kind == graphql.BooleanValue
+	case "ID":
+		return val.Kind == graphql.StringValue || val.Kind == graphql.IntValue
+	default:
+		return true
+	}
+}
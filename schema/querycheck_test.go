@@ -0,0 +1,226 @@
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/graphql"
+)
+
+func TestValidateQueryValid(t *testing.T) {
+	s := loadRichSchema(t)
+
+	doc := `query Issues($owner: String!, $name: String = "cli", $states: [IssueState!], $skip: Boolean!) {
+  repository(owner: $owner, name: $name) {
+    ...RepositoryIssues @skip(if: $skip)
+    issueOrPullRequest(number: 1) {
+      __typename
+      ... on Issue { title }
+      ... on PullRequest { merged }
+    }
+    owner { login }
+  }
+  search(query: "is:open", type: ISSUE, first: 10) {
+    nodes { ... on Node { id } }
+  }
+  __type(name: "Issue") { name }
+}
+
+fragment RepositoryIssues on Repository {
+  issues(first: 5, states: $states) { nodes { number state } }
+}
+
+mutation Create {
+  createIssue(input: {repositoryId: "R_1", title: "t", labelIds: "L_1", metadata: {priority: 1, parent: {number: 2}}}) {
+    clientMutationId
+  }
+}`
+	errs, err := s.ValidateQuery(doc)
+	if err != nil {
+		t.Fatalf("ValidateQuery failed: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected no problems, got %v", errs)
+	}
+}
+
+func TestValidateQuery(t *testing.T) {
+	s := loadRichSchema(t)
+
+	tests := []struct {
+		name string
+		doc  string
+		want []string // "line:column: message"
+	}{
+		{
+			name: "unknown field",
+			doc:  `{ viewer { login nickname } }`,
+			want: []string{`1:18: field "nickname" not found on type "User"`},
+		},
+		{
+			name: "selections",
+			doc: `{
+  viewer
+  repository(owner: "o", name: "n") { name { length } }
+}`,
+			want: []string{
+				`2:3: field "viewer" of type "User!" must have a selection of subfields`,
+				`3:39: field "name" of type "String!" must not have a selection`,
+			},
+		},
+		{
+			name: "union fields",
+			doc:  `{ repository(owner: "o", name: "n") { issueOrPullRequest(number: 1) { title } } }`,
+			want: []string{`1:71: field "title" not found on type "IssueOrPullRequest"`},
+		},
+		{
+			name: "arguments",
+			doc:  `{ repository(owner: "o", owner: "p", branch: "main") { id } }`,
+			want: []string{
+				`1:3: field "repository" requires argument "name" of type String!`,
+				`1:26: argument "owner" is given more than once`,
+				`1:38: unknown argument "branch" on field "repository"`,
+			},
+		},
+		{
+			name: "literal values",
+			doc: `{
+  repository(owner: 1, name: null) {
+    issues(first: 3000000000, states: [OPEN, MERGED]) { totalCount }
+  }
+  search(query: "q", type: "ISSUE") { issueCount }
+}`,
+			want: []string{
+				`2:21: expected value of type String, found 1`,
+				`2:30: expected value of type String!, found null`,
+				`3:19: expected value of type Int, found 3000000000`,
+				`3:46: expected value of type IssueState, found MERGED`,
+				`5:28: expected value of type SearchType, found "ISSUE"`,
+			},
+		},
+		{
+			name: "input objects",
+			doc: `mutation {
+  a: createIssue(input: {title: "t", color: "red"}) { clientMutationId }
+  b: createIssue(input: {repositoryId: "R", title: "t", metadata: {parent: {id: "I", number: 1}}}) { clientMutationId }
+}`,
+			want: []string{
+				`2:25: input type "CreateIssueInput" requires field "repositoryId" of type ID!`,
+				`2:38: field "color" is not defined by input type "CreateIssueInput"`,
+				`3:76: exactly one field of oneOf input type "IssueLocatorInput" must be given and non-null`,
+			},
+		},
+		{
+			name: "directives",
+			doc:  `query Q @skip(if: true) { viewer { login @include @deprecated @skip(if: false) @skip(if: true) } }`,
+			want: []string{
+				`1:9: directive @skip cannot be used on QUERY`,
+				`1:42: directive @include requires argument "if" of type Boolean!`,
+				`1:51: directive @deprecated cannot be used on FIELD`,
+				`1:80: directive @skip is used more than once`,
+			},
+		},
+		{
+			name: "fragments",
+			doc: `{
+  viewer { ...UserFields ...Missing ... on Issue { id } ... on Mystery { id } }
+}
+
+fragment UserFields on User { login }
+
+fragment Unused on Repository { id }
+
+fragment OnScalar on String { length }`,
+			want: []string{
+				`2:26: unknown fragment "Missing"`,
+				`2:37: fragment on "Issue" can never apply to type "User"`,
+				`2:57: unknown type "Mystery"`,
+				`7:1: fragment "Unused" is never used`,
+				`9:1: fragment cannot condition on non-composite type "String"`,
+				`9:1: fragment "OnScalar" is never used`,
+			},
+		},
+		{
+			name: "fragment spread on other type",
+			doc: `{ viewer { ...RepositoryName } }
+
+fragment RepositoryName on Repository { name }`,
+			want: []string{`1:12: fragment "RepositoryName" on "Repository" can never apply to type "User"`},
+		},
+		{
+			name: "fragment cycle",
+			doc: `{ viewer { ...A } }
+
+fragment A on User { ...B }
+
+fragment B on User { ...A login }`,
+			want: []string{`5:22: fragment "A" spreads itself through "B"`},
+		},
+		{
+			name: "variables",
+			doc: `query Q($owner: String!, $unused: Int, $first: String, $repo: Repository, $number: Int) {
+  repository(owner: $owner, name: $name) {
+    issues(first: $first) { totalCount }
+    issueOrPullRequest(number: $number) { __typename }
+  }
+}`,
+			want: []string{
+				`1:26: variable $unused is never used in query "Q"`,
+				`1:56: variable $repo is never used in query "Q"`,
+				`1:63: variable $repo cannot be of non-input type "Repository"`,
+				`2:35: variable $name is not defined by query "Q"`,
+				`3:19: variable $first of type String cannot be used where Int is expected`,
+				`4:32: variable $number of type Int cannot be used where Int! is expected`,
+			},
+		},
+		{
+			name: "variables through fragments",
+			doc: `query A { viewer { ...Issues } }
+
+query B($first: Int) { viewer { ...Issues } }
+
+fragment Issues on User { issues(first: $first) { totalCount } }`,
+			want: []string{`5:41: variable $first is not defined by query "A"`},
+		},
+		{
+			name: "operations",
+			doc: `{ viewer { login } }
+
+query Q { viewer { login } }
+
+query Q { viewer { login } }
+
+subscription S { viewer { login } }`,
+			want: []string{
+				`1:1: anonymous operation must be the only operation in the document`,
+				`5:1: operation "Q" is defined more than once`,
+				`7:1: schema does not support subscription operations`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := s.ValidateQuery(tt.doc)
+			if err != nil {
+				t.Fatalf("ValidateQuery failed: %v", err)
+			}
+			got := make([]string, len(errs))
+			for i, e := range errs {
+				got[i] = e.Error()
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(tt.want, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestValidateQuerySyntaxError(t *testing.T) {
+	_, err := loadRichSchema(t).ValidateQuery(`{ viewer { login }`)
+	var syntaxErr *graphql.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("Expected a *graphql.SyntaxError, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/apstndb/github-schema-go/schema/introspect"
+	"github.com/apstndb/go-yamlformat"
+)
+
+// WithCacheDir keeps the schema fetched by NewWithURL in dir, keyed by URL.
+// The next fetch of the same URL is conditional on the ETag the server sent,
+// and the kept copy is loaded when the server answers that it is unchanged.
+// Download ignores it.
+func WithCacheDir(dir string) DownloadOption {
+	return func(o *downloadOptions) { o.cacheDir = dir }
+}
+
+// NewWithURL fetches the schema at url over HTTP and loads it like
+// NewWithData: an introspection result, gzip or zstd compressed or not, or a
+// GraphQL SDL document. A token is only sent when WithToken or
+// WithTokenSource gives one; ResolveCredential is not consulted, so GitHub
+// credentials never reach hosts they were not meant for. WithHTTPClient,
+// WithTransport, WithRetry, and WithCacheDir apply as well; the other
+// download options are ignored.
+func NewWithURL(ctx context.Context, url string, opts ...DownloadOption) (*Schema, error) {
+	o := downloadOptions{retry: introspect.DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var cachePath string
+	if o.cacheDir != "" {
+		cachePath = filepath.Join(o.cacheDir, "url-"+Fingerprint([]byte(url))[:16]+".json.zst")
+		if meta := fileMetadata(cachePath, Zstd); meta != nil && meta.Endpoint == url {
+			o.etag = meta.ETag
+		}
+	}
+	token := o.token
+	if o.tokenSource != nil {
+		var err error
+		if token, err = o.tokenSource(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get token: %w", err)
+		}
+	}
+
+	slog.Debug("Fetching schema", "url", url, "cached", o.etag != "")
+	doc, err := introspect.Fetch(ctx, url,
+		introspect.WithToken(token),
+		introspect.WithHTTPClient(o.client),
+		introspect.WithRetry(o.retry),
+		introspect.WithIfNoneMatch(o.etag))
+	if errors.Is(err, introspect.ErrNotModified) {
+		slog.Debug("Schema unchanged, loading cached copy", "url", url, "path", cachePath)
+		return NewWithFile(cachePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schema: %w", err)
+	}
+	s, err := NewWithData(doc.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without an ETag the copy could never be revalidated
+	if cachePath != "" && doc.ETag != "" {
+		meta := Metadata{
+			DownloadedAt: doc.ReceivedAt.Truncate(time.Second),
+			SHA256:       Fingerprint(doc.Body),
+			Endpoint:     url,
+			ETag:         doc.ETag,
+		}
+		if err := writeURLCache(cachePath, s, meta); err != nil {
+			slog.Warn("Failed to cache schema", "url", url, "path", cachePath, "error", err)
+		}
+	}
+	return s, nil
+}
+
+// writeURLCache writes s with meta recorded, zstd-compressed, atomically so
+// concurrent fetches never observe a partially written copy
+func writeURLCache(path string, s *Schema, meta Metadata) error {
+	data, err := yamlformat.MarshalJSON(s.tree())
+	if err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+	if data, err = addMetadata(data, meta); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".schema-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := writeSchema(tmp, data, Zstd); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,73 @@
+package schema
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewWithURL(t *testing.T) {
+	var compressed bytes.Buffer
+	if err := writeSchema(&compressed, SampleData(), Gzip); err != nil {
+		t.Fatal(err)
+	}
+	var requests, notModified int
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		authorization = r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/schema.json.gz":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write(compressed.Bytes())
+		case "/schema.graphql":
+			w.Write([]byte("type Query { viewer: String }\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	url := server.URL + "/schema.json.gz"
+	for i := 0; i < 2; i++ {
+		s, err := NewWithURL(ctx, url, WithCacheDir(dir))
+		if err != nil {
+			t.Fatalf("NewWithURL failed: %v", err)
+		}
+		if _, err := s.Type("Repository"); err != nil {
+			t.Errorf("Failed to query the fetched schema: %v", err)
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("Expected a fetch and a revalidation, got %d requests, %d not modified", requests, notModified)
+	}
+	if authorization != "" {
+		t.Errorf("Expected no credentials without WithToken, got %q", authorization)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 || filepath.Ext(entries[0].Name()) != ".zst" {
+		t.Errorf("Expected one cached copy, got %v", entries)
+	}
+
+	s, err := NewWithURL(ctx, server.URL+"/schema.graphql", WithToken("token"))
+	if err != nil {
+		t.Fatalf("NewWithURL failed for SDL: %v", err)
+	}
+	if s.RootTypeName("query") != "Query" || authorization != "bearer token" {
+		t.Errorf("Unexpected SDL schema or authorization %q", authorization)
+	}
+
+	if _, err := NewWithURL(ctx, server.URL+"/missing", WithRetry(RetryPolicy{MaxAttempts: 1})); err == nil {
+		t.Error("Expected an error for a missing schema")
+	}
+}
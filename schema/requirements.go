@@ -0,0 +1,312 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/go-yamlformat"
+)
+
+// Requirements are the parts of a schema a consumer depends on, usually
+// collected from its operations with RequirementsOf and checked against a new
+// schema snapshot with AssertCompatible
+type Requirements struct {
+	// Types are the types operations name: fragment type conditions and
+	// variable types
+	Types []string `json:"types"`
+	// Fields are the selected fields and the fields of input objects
+	// operations pass, with the types they had
+	Fields []FieldRequirement `json:"fields"`
+	// Mutations are the mutations operations call
+	Mutations []string `json:"mutations,omitempty"`
+}
+
+// FieldRequirement is a field or input field a consumer depends on
+type FieldRequirement struct {
+	Type      string            `json:"type"` // Type the field belongs to
+	Field     string            `json:"field"`
+	Returns   string            `json:"returns"`             // GraphQL notation, e.g. "IssueConnection!"
+	Arguments map[string]string `json:"arguments,omitempty"` // Types of the arguments passed, by name
+}
+
+// Incompatibility is a requirement the schema no longer satisfies
+type Incompatibility struct {
+	Coordinate string `json:"coordinate"` // Such as "Repository", "Repository.issues", or "Repository.issues(states:)"
+	Message    string `json:"message"`
+}
+
+// CompatibilityError lists every requirement AssertCompatible found unmet
+type CompatibilityError struct {
+	Incompatibilities []Incompatibility
+}
+
+func (e *CompatibilityError) Error() string {
+	items := make([]string, len(e.Incompatibilities))
+	for i, inc := range e.Incompatibilities {
+		items[i] = inc.Coordinate + ": " + inc.Message
+	}
+	return fmt.Sprintf("schema is incompatible with %d requirement(s): %s", len(e.Incompatibilities), strings.Join(items, "; "))
+}
+
+// ParseRequirements reads requirements written as JSON or YAML, such as the
+// output of the requirements command
+func ParseRequirements(data []byte) (*Requirements, error) {
+	var req Requirements
+	if err := yamlformat.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("failed to parse requirements: %w", err)
+	}
+	return &req, nil
+}
+
+// RequirementsOf collects the types, fields, and mutations the operations and
+// fragments of docs depend on. Fields record their current types and the
+// arguments passed to them, and every field of the input objects that
+// variables and arguments take is recorded too, since a consumer may set any
+// of them. Selections the schema does not define fail with a *QueryError.
+func (s *Schema) RequirementsOf(docs ...*graphql.Document) (*Requirements, error) {
+	c := &requirementCollector{
+		model:     s.Model(),
+		types:     make(map[string]bool),
+		fields:    make(map[string]*FieldRequirement),
+		mutations: make(map[string]bool),
+		inputs:    make(map[string]bool),
+	}
+	c.mutationRoot = s.RootTypeName("mutation")
+
+	for _, doc := range docs {
+		for _, def := range doc.Definitions {
+			switch def := def.(type) {
+			case *graphql.OperationDefinition:
+				root := s.RootTypeName(string(def.Operation))
+				if root == "" {
+					return nil, costError(def.Pos, "schema does not support %s operations", def.Operation)
+				}
+				for _, v := range def.VariableDefinitions {
+					name := v.Type.NamedType()
+					if c.model.Type(name) == nil {
+						return nil, costError(v.Pos, "unknown type %q", name)
+					}
+					c.types[name] = true
+					c.inputType(name)
+				}
+				if err := c.selectionSet(root, def.SelectionSet, def.Pos); err != nil {
+					return nil, err
+				}
+			case *graphql.FragmentDefinition:
+				c.types[def.TypeCondition] = true
+				if err := c.selectionSet(def.TypeCondition, def.SelectionSet, def.Pos); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	req := &Requirements{Types: sortedKeys(c.types), Fields: []FieldRequirement{}, Mutations: sortedKeys(c.mutations)}
+	for _, key := range sortedKeys(c.fields) {
+		req.Fields = append(req.Fields, *c.fields[key])
+	}
+	return req, nil
+}
+
+// requirementCollector accumulates the requirements of documents
+type requirementCollector struct {
+	model        *Model
+	mutationRoot string
+	types        map[string]bool
+	fields       map[string]*FieldRequirement // By "Type.field"
+	mutations    map[string]bool
+	inputs       map[string]bool // Input objects already recorded
+}
+
+func (c *requirementCollector) selectionSet(typeName string, set graphql.SelectionSet, pos graphql.Position) error {
+	t := c.model.Type(typeName)
+	if t == nil {
+		return costError(pos, "unknown type %q", typeName)
+	}
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *graphql.Field:
+			if strings.HasPrefix(sel.Name, "__") {
+				continue
+			}
+			def := t.Field(sel.Name)
+			if def == nil {
+				return costError(sel.Pos, "field %q not found on type %q", sel.Name, typeName)
+			}
+			req := c.field(t, def)
+			for _, arg := range sel.Arguments {
+				a := findInputValue(def.Args, arg.Name)
+				if a == nil {
+					return costError(arg.Pos, "unknown argument %q on field %q", arg.Name, sel.Name)
+				}
+				if req.Arguments == nil {
+					req.Arguments = make(map[string]string)
+				}
+				req.Arguments[a.Name] = a.Type.String()
+				c.inputType(a.Type.NamedType())
+			}
+			if t.Name == c.mutationRoot {
+				c.mutations[def.Name] = true
+			}
+			if len(sel.SelectionSet) > 0 {
+				if err := c.selectionSet(def.Type.NamedType(), sel.SelectionSet, sel.Pos); err != nil {
+					return err
+				}
+			}
+		case *graphql.InlineFragment:
+			condition := typeName
+			if sel.TypeCondition != "" {
+				condition = sel.TypeCondition
+				c.types[condition] = true
+			}
+			if err := c.selectionSet(condition, sel.SelectionSet, sel.Pos); err != nil {
+				return err
+			}
+		}
+		// Fragment spreads are covered by collecting every fragment definition
+	}
+	return nil
+}
+
+// field returns the requirement of a field, recording it on first use
+func (c *requirementCollector) field(t *Type, f *Field) *FieldRequirement {
+	key := t.Name + "." + f.Name
+	if req, ok := c.fields[key]; ok {
+		return req
+	}
+	req := &FieldRequirement{Type: t.Name, Field: f.Name, Returns: f.Type.String()}
+	c.fields[key] = req
+	return req
+}
+
+// inputType records every field of an input object and of the input objects
+// nested in it
+func (c *requirementCollector) inputType(name string) {
+	t := c.model.Type(name)
+	if t == nil || t.Kind != "INPUT_OBJECT" || c.inputs[name] {
+		return
+	}
+	c.inputs[name] = true
+	for _, v := range t.InputFields {
+		key := t.Name + "." + v.Name
+		c.fields[key] = &FieldRequirement{Type: t.Name, Field: v.Name, Returns: v.Type.String()}
+		c.inputType(v.Type.NamedType())
+	}
+}
+
+// AssertCompatible checks that the schema still satisfies requirements, such
+// as those RequirementsOf collected against an earlier snapshot, and returns
+// a *CompatibilityError listing every unmet one, or nil. Types and mutations
+// must exist; fields must exist with types the recorded ones can be read as,
+// by the rules of TypesCompatible; the arguments passed must keep compatible
+// types; and fields must not have gained required arguments, nor input
+// objects required fields, the consumer does not pass.
+//
+// It is meant to run in a consumer's tests or init, so bumping the embedded
+// snapshot fails loudly where the consumer's operations would break.
+func (s *Schema) AssertCompatible(req *Requirements) error {
+	m := s.Model()
+	var found []Incompatibility
+	report := func(coordinate, format string, args ...interface{}) {
+		found = append(found, Incompatibility{Coordinate: coordinate, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, name := range req.Types {
+		if m.Type(name) == nil {
+			report(name, "%s", m.typeNotFound(name))
+		}
+	}
+	for _, name := range req.Mutations {
+		if m.Mutation(name) == nil {
+			report(name, "%s", m.rootFieldNotFound(m.MutationType, "Mutation", "mutation", name))
+		}
+	}
+
+	missingTypes := make(map[string]bool)
+	listed := make(map[string]bool) // "Type.field" of every requirement
+	for _, f := range req.Fields {
+		listed[f.Type+"."+f.Field] = true
+	}
+	inputObjects := make(map[string]bool)
+	for _, f := range req.Fields {
+		coordinate := f.Type + "." + f.Field
+		t := m.Type(f.Type)
+		if t == nil {
+			if !missingTypes[f.Type] && !slices.Contains(req.Types, f.Type) {
+				report(f.Type, "%s", m.typeNotFound(f.Type))
+			}
+			missingTypes[f.Type] = true
+			continue
+		}
+		recorded, err := ParseTypeRef(f.Returns)
+		if err != nil {
+			return fmt.Errorf("invalid requirement %s: %w", coordinate, err)
+		}
+
+		if t.Kind == "INPUT_OBJECT" {
+			inputObjects[t.Name] = true
+			v := t.InputField(f.Field)
+			switch {
+			case v == nil:
+				report(coordinate, "%s", fieldNotFound(t, f.Field))
+			case !s.TypesCompatible(recorded, v.Type, InputPosition):
+				report(coordinate, "input field type changed from %s to %s", f.Returns, v.Type)
+			}
+			continue
+		}
+
+		def := t.Field(f.Field)
+		if def == nil {
+			report(coordinate, "%s", fieldNotFound(t, f.Field))
+			continue
+		}
+		if !s.TypesCompatible(recorded, def.Type, OutputPosition) {
+			report(coordinate, "field type changed from %s to %s", f.Returns, def.Type)
+		}
+		for _, name := range sortedKeys(f.Arguments) {
+			argCoordinate := fmt.Sprintf("%s(%s:)", coordinate, name)
+			a := def.Arg(name)
+			if a == nil {
+				report(argCoordinate, "argument was removed")
+				continue
+			}
+			passed, err := ParseTypeRef(f.Arguments[name])
+			if err != nil {
+				return fmt.Errorf("invalid requirement %s: %w", argCoordinate, err)
+			}
+			if !s.TypesCompatible(passed, a.Type, InputPosition) {
+				report(argCoordinate, "argument type changed from %s to %s", f.Arguments[name], a.Type)
+			}
+		}
+		for _, a := range def.Args {
+			if _, ok := f.Arguments[a.Name]; !ok && a.Required() {
+				report(fmt.Sprintf("%s(%s:)", coordinate, a.Name), "new required argument of type %s is not passed", a.Type)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(inputObjects) {
+		for _, v := range m.Type(name).InputFields {
+			if !listed[name+"."+v.Name] && v.Required() {
+				report(name+"."+v.Name, "new required input field of type %s is not set", v.Type)
+			}
+		}
+	}
+
+	if len(found) > 0 {
+		return &CompatibilityError{Incompatibilities: found}
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
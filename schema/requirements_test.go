@@ -0,0 +1,148 @@
+package schema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/graphql"
+)
+
+const requirementsDoc = `
+query Issues($owner: String!, $name: String!, $states: [IssueState!]) {
+  repository(owner: $owner, name: $name) {
+    hasIssuesEnabled
+    issues(first: 10, states: $states) {
+      totalCount
+      nodes { ...IssueFields }
+    }
+  }
+}
+
+mutation Create($input: CreateIssueInput!) {
+  createIssue(input: $input) { issue { id } }
+}
+
+fragment IssueFields on Issue { title author { ... on User { login } } }
+`
+
+func requirementsOf(t *testing.T, s *Schema) *Requirements {
+	t.Helper()
+	doc, err := graphql.Parse(requirementsDoc)
+	if err != nil {
+		t.Fatalf("Failed to parse operations: %v", err)
+	}
+	req, err := s.RequirementsOf(doc)
+	if err != nil {
+		t.Fatalf("RequirementsOf failed: %v", err)
+	}
+	return req
+}
+
+func TestRequirementsOf(t *testing.T) {
+	req := requirementsOf(t, loadRichSchema(t))
+
+	if want := []string{"CreateIssueInput", "Issue", "IssueState", "String", "User"}; !reflect.DeepEqual(req.Types, want) {
+		t.Errorf("Types = %q, want %q", req.Types, want)
+	}
+	if want := []string{"createIssue"}; !reflect.DeepEqual(req.Mutations, want) {
+		t.Errorf("Mutations = %q, want %q", req.Mutations, want)
+	}
+
+	fields := make(map[string]FieldRequirement)
+	for _, f := range req.Fields {
+		fields[f.Type+"."+f.Field] = f
+	}
+	for _, want := range []FieldRequirement{
+		{Type: "Query", Field: "repository", Returns: "Repository", Arguments: map[string]string{"owner": "String!", "name": "String!"}},
+		{Type: "Repository", Field: "issues", Returns: "IssueConnection!", Arguments: map[string]string{"first": "Int", "states": "[IssueState!]"}},
+		{Type: "Issue", Field: "title", Returns: "String!"},
+		{Type: "User", Field: "login", Returns: "String!"},
+		{Type: "Mutation", Field: "createIssue", Returns: "CreateIssuePayload", Arguments: map[string]string{"input": "CreateIssueInput!"}},
+		{Type: "CreateIssueInput", Field: "title", Returns: "String!"},
+		{Type: "IssueMetadataInput", Field: "priority", Returns: "Int"},
+	} {
+		got, ok := fields[want.Type+"."+want.Field]
+		if !ok {
+			t.Errorf("Missing requirement %s.%s", want.Type, want.Field)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Requirement %s.%s = %+v, want %+v", want.Type, want.Field, got, want)
+		}
+	}
+
+	data := []byte(`{"types": ["Issue"], "fields": [{"type": "Issue", "field": "title", "returns": "String!"}]}`)
+	parsed, err := ParseRequirements(data)
+	if err != nil {
+		t.Fatalf("ParseRequirements failed: %v", err)
+	}
+	if len(parsed.Fields) != 1 || parsed.Fields[0].Returns != "String!" {
+		t.Errorf("ParseRequirements = %+v", parsed)
+	}
+}
+
+func TestRequirementsOfUnknownField(t *testing.T) {
+	doc, err := graphql.Parse(`{ viewer { nickname } }`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	_, err = loadRichSchema(t).RequirementsOf(doc)
+	var qe *QueryError
+	if !errors.As(err, &qe) || qe.Line != 1 || qe.Column != 12 {
+		t.Errorf("Expected *QueryError at 1:12, got %v", err)
+	}
+}
+
+func TestAssertCompatible(t *testing.T) {
+	s := loadRichSchema(t)
+	req := requirementsOf(t, s)
+	if err := s.AssertCompatible(req); err != nil {
+		t.Fatalf("Expected the collecting schema to be compatible, got %v", err)
+	}
+
+	changed := modifiedSample(t, func(types map[string]map[string]interface{}) {
+		nonNull := func(name, kind string) map[string]interface{} {
+			return map[string]interface{}{"kind": "NON_NULL", "name": nil, "ofType": map[string]interface{}{"kind": kind, "name": name, "ofType": nil}}
+		}
+		repo := types["Repository"]
+		removeMember(repo, "fields", "hasIssuesEnabled")
+		issues := member(t, repo, "fields", "issues")
+		member(t, issues, "args", "states")["type"] = map[string]interface{}{"kind": "LIST", "name": nil, "ofType": nonNull("String", "SCALAR")}
+		issues["args"] = append(issues["args"].([]interface{}), map[string]interface{}{
+			"name": "orderBy", "description": "", "type": nonNull("String", "SCALAR"), "defaultValue": nil,
+		})
+		// A non-null return type still reads as the nullable one
+		member(t, types["Query"], "fields", "repository")["type"] = nonNull("Repository", "OBJECT")
+		member(t, types["Issue"], "fields", "title")["type"] = map[string]interface{}{"kind": "SCALAR", "name": "String", "ofType": nil}
+		input := types["CreateIssueInput"]
+		input["inputFields"] = append(input["inputFields"].([]interface{}), map[string]interface{}{
+			"name": "assigneeIds", "description": "", "type": nonNull("ID", "SCALAR"), "defaultValue": nil,
+		})
+		delete(types, "User")
+		member(t, types["Mutation"], "fields", "createIssue")["name"] = "openIssue"
+	})
+
+	err := changed.AssertCompatible(req)
+	var ce *CompatibilityError
+	if !errors.As(err, &ce) {
+		t.Fatalf("Expected *CompatibilityError, got %v", err)
+	}
+	got := make(map[string]string)
+	for _, inc := range ce.Incompatibilities {
+		got[inc.Coordinate] = inc.Message
+	}
+	want := map[string]string{
+		"User":                         `type "User" not found`,
+		"createIssue":                  `mutation "createIssue" not found; did you mean "openIssue"?`,
+		"Mutation.createIssue":         `field "createIssue" not found on type "Mutation"; did you mean "openIssue"?`,
+		"Repository.hasIssuesEnabled":  `field "hasIssuesEnabled" not found on type "Repository"`,
+		"Repository.issues(states:)":   "argument type changed from [IssueState!] to [String!]",
+		"Repository.issues(orderBy:)":  "new required argument of type String! is not passed",
+		"Issue.title":                  "field type changed from String! to String",
+		"CreateIssueInput.assigneeIds": "new required input field of type ID! is not set",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Incompatibilities = %v\nwant %v", got, want)
+	}
+}
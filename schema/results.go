@@ -0,0 +1,382 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+// TypeInfo is the typed form of the result of Schema.Type
+type TypeInfo struct {
+	Name        string           `json:"name"`
+	Kind        string           `json:"kind"`
+	Description string           `json:"description"`
+	Fields      []FieldInfo      `json:"fields"`
+	InputFields []InputFieldInfo `json:"inputFields"`
+	EnumValues  []EnumValueInfo  `json:"enumValues"`
+}
+
+// FieldInfo describes a field of an object or interface type.
+// Type is formatted in GraphQL notation such as "[Issue!]!".
+// Deprecation info is only populated by Fields and Field.
+type FieldInfo struct {
+	Name              string         `json:"name"`
+	Description       string         `json:"description"`
+	Type              string         `json:"type"`
+	Arguments         []ArgumentInfo `json:"arguments"`
+	IsDeprecated      bool           `json:"isDeprecated,omitempty"`
+	DeprecationReason string         `json:"deprecationReason,omitempty"`
+}
+
+// ArgumentInfo describes an argument of a field or directive.
+// DefaultValue is a GraphQL literal such as "true" or "\"No longer supported\"";
+// it is only populated by Directives and Field.
+type ArgumentInfo struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Type         string `json:"type"`
+	DefaultValue string `json:"defaultValue,omitempty"`
+}
+
+// DirectiveInfo describes a directive supported by the schema
+type DirectiveInfo struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Locations   []string       `json:"locations"`
+	Arguments   []ArgumentInfo `json:"arguments"`
+}
+
+// InputFieldInfo describes a field of an input object type
+type InputFieldInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
+}
+
+// EnumValueInfo describes a value of an enum type.
+// Deprecation info is only populated by EnumValues.
+type EnumValueInfo struct {
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	IsDeprecated      bool   `json:"isDeprecated,omitempty"`
+	DeprecationReason string `json:"deprecationReason,omitempty"`
+}
+
+// MutationInfo is the typed form of the result of Schema.Mutation
+type MutationInfo struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Inputs      []MutationInput `json:"inputs"`
+}
+
+// MutationInput describes an argument of a mutation. For mutations taking a
+// single input object, Description also lists the fields of that object.
+type MutationInput struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// SearchResult is the typed form of the result of Schema.Search.
+// Facets counts the results per category, with "other" for uncategorized types.
+type SearchResult struct {
+	Count   int            `json:"count"`
+	Pattern string         `json:"pattern"`
+	Results []SearchMatch  `json:"results"`
+	Facets  map[string]int `json:"facets"`
+}
+
+// SearchMatch is a type whose name matched a search pattern.
+// Long descriptions are truncated to 100 characters.
+type SearchMatch struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+}
+
+// LookupType is like Type but returns a typed result
+func (s *Schema) LookupType(typeName string) (*TypeInfo, error) {
+	result, err := s.Type(typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Type TypeInfo `json:"type"`
+	}
+	if err := decodeResult(result, &out); err != nil {
+		return nil, err
+	}
+	return &out.Type, nil
+}
+
+// LookupMutation is like Mutation but returns a typed result
+func (s *Schema) LookupMutation(mutationName string) (*MutationInfo, error) {
+	result, err := s.Mutation(mutationName)
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Mutation MutationInfo `json:"mutation"`
+	}
+	if err := decodeResult(result, &out); err != nil {
+		return nil, err
+	}
+	return &out.Mutation, nil
+}
+
+// Fields returns the fields of an object or interface type, including
+// deprecated ones. Types without fields, such as enums, yield an empty slice.
+func (s *Schema) Fields(typeName string) ([]FieldInfo, error) {
+	m := s.Model()
+	t := m.Type(typeName)
+	if t == nil {
+		return nil, m.typeNotFound(typeName)
+	}
+	if fields := fieldInfos(t); fields != nil {
+		return fields, nil
+	}
+	return []FieldInfo{}, nil
+}
+
+// Field returns one field of a type with its arguments and deprecation info.
+// Unlike Fields, arguments include their default values. Fields of input
+// object types are returned without arguments.
+func (s *Schema) Field(typeName, fieldName string) (*FieldInfo, error) {
+	m := s.Model()
+	t := m.Type(typeName)
+	if t == nil {
+		return nil, m.typeNotFound(typeName)
+	}
+	if v := t.InputField(fieldName); v != nil {
+		return &FieldInfo{
+			Name:              v.Name,
+			Description:       v.Description,
+			Type:              v.Type.String(),
+			IsDeprecated:      v.IsDeprecated,
+			DeprecationReason: v.DeprecationReason,
+		}, nil
+	}
+	f := t.Field(fieldName)
+	if f == nil {
+		return nil, fieldNotFound(t, fieldName)
+	}
+	info := &FieldInfo{
+		Name:              f.Name,
+		Description:       f.Description,
+		Type:              f.Type.String(),
+		Arguments:         argumentInfos(f.Args),
+		IsDeprecated:      f.IsDeprecated,
+		DeprecationReason: f.DeprecationReason,
+	}
+	for i, a := range f.Args {
+		if a.DefaultValue != nil {
+			info.Arguments[i].DefaultValue = *a.DefaultValue
+		}
+	}
+	return info, nil
+}
+
+// EnumValues returns the values of an enum type, including deprecated ones.
+// It fails if the type does not exist or is not an enum.
+func (s *Schema) EnumValues(enumName string) ([]EnumValueInfo, error) {
+	t, err := s.typeOfKind(enumName, "ENUM", "an enum")
+	if err != nil {
+		return nil, err
+	}
+	values := make([]EnumValueInfo, len(t.EnumValues))
+	for i, v := range t.EnumValues {
+		values[i] = EnumValueInfo(*v)
+	}
+	return values, nil
+}
+
+// Implementers returns the names of the object types implementing an interface.
+// It fails if the type does not exist or is not an interface.
+func (s *Schema) Implementers(interfaceName string) ([]string, error) {
+	t, err := s.typeOfKind(interfaceName, "INTERFACE", "an interface")
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{}, t.PossibleTypes...), nil
+}
+
+// UnionMembers returns the names of the possible types of a union.
+// It fails if the type does not exist or is not a union.
+func (s *Schema) UnionMembers(unionName string) ([]string, error) {
+	t, err := s.typeOfKind(unionName, "UNION", "a union")
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{}, t.PossibleTypes...), nil
+}
+
+// typeOfKind returns the named type, failing if it does not exist or has
+// another kind; article names the kind in the error
+func (s *Schema) typeOfKind(name, kind, article string) (*Type, error) {
+	m := s.Model()
+	t := m.Type(name)
+	if t == nil {
+		return nil, m.typeNotFound(name)
+	}
+	if t.Kind != kind {
+		return nil, fmt.Errorf("type %q is not %s (kind: %s)", name, article, t.Kind)
+	}
+	return t, nil
+}
+
+// Directives returns the directives declared by the schema, such as
+// @include, @skip, and @deprecated
+func (s *Schema) Directives() ([]DirectiveInfo, error) {
+	result, err := s.runQuery(directivesQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Directives []DirectiveInfo `json:"directives"`
+	}
+	if err := decodeResult(result, &out); err != nil {
+		return nil, err
+	}
+	return out.Directives, nil
+}
+
+// DeprecatedType groups the deprecated members of a type
+type DeprecatedType struct {
+	Name        string             `json:"name"`
+	Kind        string             `json:"kind"`
+	Fields      []DeprecatedMember `json:"fields,omitempty"`
+	Arguments   []DeprecatedMember `json:"arguments,omitempty"` // Named field.argument
+	InputFields []DeprecatedMember `json:"inputFields,omitempty"`
+	EnumValues  []DeprecatedMember `json:"enumValues,omitempty"`
+}
+
+// Members returns the deprecated fields, arguments, input fields, and enum
+// values of t
+func (t DeprecatedType) Members() []DeprecatedMember {
+	var members []DeprecatedMember
+	for _, list := range [][]DeprecatedMember{t.Fields, t.Arguments, t.InputFields, t.EnumValues} {
+		members = append(members, list...)
+	}
+	return members
+}
+
+// DeprecatedMember is a deprecated field, field argument, input field, or
+// enum value
+type DeprecatedMember struct {
+	Name              string `json:"name"`
+	DeprecationReason string `json:"deprecationReason"`
+}
+
+// Deprecated returns every type with deprecated fields, field arguments,
+// input fields, or enum values, in schema order. Deprecated arguments and
+// input fields are only reported for schemas introspected with
+// args(includeDeprecated: true) and inputFields(includeDeprecated: true).
+func (s *Schema) Deprecated() ([]DeprecatedType, error) {
+	result, err := s.runQuery(deprecatedQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Deprecated []DeprecatedType `json:"deprecated"`
+	}
+	if err := decodeResult(result, &out); err != nil {
+		return nil, err
+	}
+	return out.Deprecated, nil
+}
+
+// SearchTypes is like Search but returns a typed result
+func (s *Schema) SearchTypes(pattern string) (*SearchResult, error) {
+	result, err := s.Search(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var out SearchResult
+	if err := decodeResult(result, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// decodeResult converts a generic query result into a typed value
+func decodeResult(result interface{}, out interface{}) error {
+	data, err := yamlformat.MarshalJSON(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode query result: %w", err)
+	}
+	if err := yamlformat.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode query result: %w", err)
+	}
+	return nil
+}
+
+// ListKinds are the kinds accepted by List
+var ListKinds = []string{"types", "objects", "inputs", "enums", "interfaces", "unions", "scalars", "mutations", "queries"}
+
+// listTypeKinds maps the list kinds naming types to their GraphQL kind,
+// matching the List*TypesQuery expressions
+var listTypeKinds = map[string]string{
+	"objects":    "OBJECT",
+	"inputs":     "INPUT_OBJECT",
+	"enums":      "ENUM",
+	"interfaces": "INTERFACE",
+	"unions":     "UNION",
+	"scalars":    "SCALAR",
+}
+
+// List returns the names of all types of a kind, or of all mutations or
+// Query root fields, in schema order. kind is one of ListKinds.
+func (s *Schema) List(kind string) ([]string, error) {
+	m := s.Model()
+	switch kind {
+	case "types":
+		names := make([]string, len(m.Types))
+		for i, t := range m.Types {
+			names[i] = t.Name
+		}
+		return names, nil
+	case "mutations":
+		return fieldNames(m.Type("Mutation")), nil
+	case "queries":
+		return fieldNames(m.rootType(m.QueryType, "Query")), nil
+	}
+	typeKind, ok := listTypeKinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown list kind %q (valid kinds: %s)", kind, strings.Join(ListKinds, ", "))
+	}
+	return s.ListTypesOfKind(typeKind)
+}
+
+// ListTypesOfKind returns the names of the types with any of the given
+// GraphQL kinds, such as OBJECT or ENUM, in schema order
+func (s *Schema) ListTypesOfKind(kinds ...string) ([]string, error) {
+	names := []string{}
+	for _, t := range s.Model().Types {
+		for _, kind := range kinds {
+			if strings.EqualFold(t.Kind, kind) {
+				names = append(names, t.Name)
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
+// fieldNames returns the field names of t, or none when t is nil
+func fieldNames(t *Type) []string {
+	names := []string{}
+	if t != nil {
+		for _, f := range t.Fields {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
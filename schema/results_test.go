@@ -0,0 +1,373 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLookupType(t *testing.T) {
+	s, err := NewWithData(testSchemaData)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	info, err := s.LookupType("PullRequest")
+	if err != nil {
+		t.Fatalf("LookupType failed: %v", err)
+	}
+	if info.Name != "PullRequest" || info.Kind != "OBJECT" {
+		t.Errorf("Unexpected type info: %+v", info)
+	}
+	if len(info.Fields) != 2 {
+		t.Fatalf("Expected 2 fields, got %d", len(info.Fields))
+	}
+	if info.Fields[0].Name != "id" || info.Fields[0].Type != "ID!" {
+		t.Errorf("Unexpected first field: %+v", info.Fields[0])
+	}
+
+	input, err := s.LookupType("CreateIssueInput")
+	if err != nil {
+		t.Fatalf("LookupType failed: %v", err)
+	}
+	if len(input.InputFields) != 1 || !input.InputFields[0].Required || input.InputFields[0].Type != "String!" {
+		t.Errorf("Unexpected input fields: %+v", input.InputFields)
+	}
+
+	if _, err := s.LookupType("NonExistent"); err == nil {
+		t.Error("Expected error for non-existent type")
+	}
+}
+
+func TestLookupMutation(t *testing.T) {
+	s, err := NewWithData(testSchemaData)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	info, err := s.LookupMutation("createIssue")
+	if err != nil {
+		t.Fatalf("LookupMutation failed: %v", err)
+	}
+	if info.Name != "createIssue" || info.Description != "Creates a new issue." {
+		t.Errorf("Unexpected mutation info: %+v", info)
+	}
+	if len(info.Inputs) != 1 || info.Inputs[0].Type != "CreateIssueInput!" || !info.Inputs[0].Required {
+		t.Errorf("Unexpected inputs: %+v", info.Inputs)
+	}
+}
+
+func TestSearchTypes(t *testing.T) {
+	s, err := NewWithData(testSchemaData)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	result, err := s.SearchTypes("Issue")
+	if err != nil {
+		t.Fatalf("SearchTypes failed: %v", err)
+	}
+	if result.Count != 2 || len(result.Results) != 2 || result.Pattern != "Issue" {
+		t.Errorf("Unexpected search result: %+v", result)
+	}
+	if result.Results[0].Name != "Issue" || result.Results[0].Kind != "OBJECT" {
+		t.Errorf("Unexpected first match: %+v", result.Results[0])
+	}
+}
+
+func TestFields(t *testing.T) {
+	s := loadRichSchema(t)
+
+	fields, err := s.Fields("Repository")
+	if err != nil {
+		t.Fatalf("Fields failed: %v", err)
+	}
+
+	byName := make(map[string]FieldInfo)
+	for _, f := range fields {
+		byName[f.Name] = f
+	}
+
+	issues, ok := byName["issues"]
+	if !ok {
+		t.Fatalf("Expected issues field, got %+v", fields)
+	}
+	if issues.Type != "IssueConnection!" {
+		t.Errorf("Expected type IssueConnection!, got %q", issues.Type)
+	}
+	var states *ArgumentInfo
+	for i := range issues.Arguments {
+		if issues.Arguments[i].Name == "states" {
+			states = &issues.Arguments[i]
+		}
+	}
+	if states == nil || states.Type != "[IssueState!]" {
+		t.Errorf("Unexpected states argument: %+v", issues.Arguments)
+	}
+	if issues.IsDeprecated {
+		t.Error("Expected issues not to be deprecated")
+	}
+
+	deprecated := byName["isTemplateRepo"]
+	if !deprecated.IsDeprecated || deprecated.DeprecationReason == "" {
+		t.Errorf("Expected isTemplateRepo to be deprecated with a reason, got %+v", deprecated)
+	}
+
+	if fields, err := s.Fields("IssueState"); err != nil || fields == nil || len(fields) != 0 {
+		t.Errorf("Expected an empty slice for an enum, got %v (err: %v)", fields, err)
+	}
+
+	if _, err := s.Fields("NonExistent"); err == nil {
+		t.Error("Expected error for non-existent type")
+	}
+}
+
+func TestField(t *testing.T) {
+	s := loadRichSchema(t)
+
+	repo, err := s.Field("Query", "repository")
+	if err != nil {
+		t.Fatalf("Field failed: %v", err)
+	}
+	if repo.Type != "Repository" || repo.Description == "" || len(repo.Arguments) != 3 {
+		t.Fatalf("Unexpected field: %+v", repo)
+	}
+	if follow := repo.Arguments[2]; follow.Name != "followRenames" || follow.DefaultValue != "true" {
+		t.Errorf("Expected followRenames to default to true, got %+v", follow)
+	}
+	if repo.Arguments[0].DefaultValue != "" {
+		t.Errorf("Expected no default for owner, got %+v", repo.Arguments[0])
+	}
+
+	deprecated, err := s.Field("Repository", "isTemplateRepo")
+	if err != nil {
+		t.Fatalf("Field failed: %v", err)
+	}
+	if !deprecated.IsDeprecated || deprecated.DeprecationReason == "" {
+		t.Errorf("Expected a deprecated field, got %+v", deprecated)
+	}
+
+	input, err := s.Field("CreateIssueInput", "title")
+	if err != nil {
+		t.Fatalf("Field failed for input object: %v", err)
+	}
+	if input.Type != "String!" || input.Arguments != nil {
+		t.Errorf("Unexpected input field: %+v", input)
+	}
+
+	if _, err := s.Field("Repository", "nope"); err == nil || !strings.Contains(err.Error(), "Repository") {
+		t.Errorf("Expected error naming the type, got %v", err)
+	}
+	if _, err := s.Field("Nope", "id"); err == nil {
+		t.Error("Expected error for non-existent type")
+	}
+}
+
+func TestEnumValues(t *testing.T) {
+	s := loadRichSchema(t)
+
+	values, err := s.EnumValues("IssueState")
+	if err != nil {
+		t.Fatalf("EnumValues failed: %v", err)
+	}
+	if len(values) != 3 {
+		t.Fatalf("Expected 3 values, got %+v", values)
+	}
+	if values[0].Name != "OPEN" || values[0].Description == "" || values[0].IsDeprecated {
+		t.Errorf("Unexpected first value: %+v", values[0])
+	}
+	if !values[2].IsDeprecated || values[2].DeprecationReason == "" {
+		t.Errorf("Expected LOCKED to be deprecated with a reason, got %+v", values[2])
+	}
+
+	if _, err := s.EnumValues("Issue"); err == nil || !strings.Contains(err.Error(), "not an enum") {
+		t.Errorf("Expected not an enum error, got %v", err)
+	}
+	if _, err := s.EnumValues("NonExistent"); err == nil {
+		t.Error("Expected error for non-existent type")
+	}
+}
+
+func TestImplementers(t *testing.T) {
+	s := loadRichSchema(t)
+
+	names, err := s.Implementers("Node")
+	if err != nil {
+		t.Fatalf("Implementers failed: %v", err)
+	}
+	want := []string{"Issue", "PullRequest", "Repository", "User"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Implementers(Node) = %v, want %v", names, want)
+	}
+
+	if _, err := s.Implementers("IssueOrPullRequest"); err == nil || !strings.Contains(err.Error(), "not an interface") {
+		t.Errorf("Expected not an interface error for a union, got %v", err)
+	}
+	if _, err := s.Implementers("NonExistent"); err == nil {
+		t.Error("Expected error for non-existent type")
+	}
+}
+
+func TestUnionMembers(t *testing.T) {
+	s := loadRichSchema(t)
+
+	members, err := s.UnionMembers("SearchResultItem")
+	if err != nil {
+		t.Fatalf("UnionMembers failed: %v", err)
+	}
+	want := []string{"Issue", "PullRequest", "Repository", "User"}
+	if !reflect.DeepEqual(members, want) {
+		t.Errorf("UnionMembers(SearchResultItem) = %v, want %v", members, want)
+	}
+
+	if _, err := s.UnionMembers("Node"); err == nil || !strings.Contains(err.Error(), "not a union") {
+		t.Errorf("Expected not a union error for an interface, got %v", err)
+	}
+	if _, err := s.UnionMembers("NonExistent"); err == nil {
+		t.Error("Expected error for non-existent type")
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	s := loadRichSchema(t)
+
+	directives, err := s.Directives()
+	if err != nil {
+		t.Fatalf("Directives failed: %v", err)
+	}
+
+	byName := make(map[string]DirectiveInfo)
+	for _, d := range directives {
+		byName[d.Name] = d
+	}
+	if len(byName) != 3 {
+		t.Fatalf("Expected 3 directives, got %+v", directives)
+	}
+
+	include := byName["include"]
+	if !reflect.DeepEqual(include.Locations, []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"}) {
+		t.Errorf("Unexpected include locations: %v", include.Locations)
+	}
+	if len(include.Arguments) != 1 || include.Arguments[0].Type != "Boolean!" || include.Arguments[0].DefaultValue != "" {
+		t.Errorf("Unexpected include arguments: %+v", include.Arguments)
+	}
+
+	deprecated := byName["deprecated"]
+	if len(deprecated.Arguments) != 1 || deprecated.Arguments[0].DefaultValue != `"No longer supported"` {
+		t.Errorf("Unexpected deprecated arguments: %+v", deprecated.Arguments)
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	s := loadRichSchema(t)
+
+	got, err := s.Deprecated()
+	if err != nil {
+		t.Fatalf("Deprecated failed: %v", err)
+	}
+
+	want := []DeprecatedType{
+		{
+			Name: "Repository",
+			Kind: "OBJECT",
+			Fields: []DeprecatedMember{
+				{Name: "isTemplateRepo", DeprecationReason: "Use `Repository.isTemplate` instead. Removal on 2025-01-01 UTC."},
+			},
+		},
+		{
+			Name: "IssueState",
+			Kind: "ENUM",
+			EnumValues: []DeprecatedMember{
+				{Name: "LOCKED", DeprecationReason: "Locking is now tracked by `Issue.locked`."},
+			},
+		},
+		{
+			Name: "IssueLocatorInput",
+			Kind: "INPUT_OBJECT",
+			InputFields: []DeprecatedMember{
+				{Name: "url", DeprecationReason: "Use `id` or `number` instead."},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Deprecated = %+v, want %+v", got, want)
+	}
+}
+
+func TestDeprecatedArguments(t *testing.T) {
+	s := modifiedSample(t, func(types map[string]map[string]interface{}) {
+		after := member(t, member(t, types["User"], "fields", "issues"), "args", "after")
+		after["isDeprecated"] = true
+		after["deprecationReason"] = "Use `before`."
+	})
+
+	got, err := s.Deprecated()
+	if err != nil {
+		t.Fatalf("Deprecated failed: %v", err)
+	}
+	want := DeprecatedType{
+		Name:      "User",
+		Kind:      "OBJECT",
+		Arguments: []DeprecatedMember{{Name: "issues.after", DeprecationReason: "Use `before`."}},
+	}
+	for _, d := range got {
+		if d.Name == "User" {
+			if !reflect.DeepEqual(d, want) {
+				t.Errorf("Deprecated User = %+v, want %+v", d, want)
+			}
+			if len(d.Members()) != 1 {
+				t.Errorf("Members = %+v", d.Members())
+			}
+			return
+		}
+	}
+	t.Errorf("Expected User in %+v", got)
+}
+
+func TestList(t *testing.T) {
+	s := loadRichSchema(t)
+
+	tests := []struct {
+		kind string
+		want []string
+	}{
+		{"enums", []string{"IssueState", "SearchType"}},
+		{"interfaces", []string{"Node", "Actor"}},
+		{"unions", []string{"IssueOrPullRequest", "SearchResultItem"}},
+		{"inputs", []string{"CreateIssueInput", "IssueMetadataInput", "IssueLocatorInput", "AddStarInput"}},
+		{"scalars", []string{"ID", "String", "Int", "Boolean"}},
+		{"mutations", []string{"createIssue", "addStar"}},
+		{"queries", []string{"repository", "node", "viewer", "search"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			got, err := s.List(tt.kind)
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List(%s) = %v, want %v", tt.kind, got, tt.want)
+			}
+		})
+	}
+
+	types, err := s.List("types")
+	if err != nil || len(types) != 26 {
+		t.Errorf("List(types) = %d names, %v", len(types), err)
+	}
+	if _, err := s.List("widgets"); err == nil || !strings.Contains(err.Error(), "valid kinds") {
+		t.Errorf("Expected unknown kind error, got %v", err)
+	}
+
+	got, err := s.ListTypesOfKind("union", "ENUM")
+	if err != nil {
+		t.Fatalf("ListTypesOfKind failed: %v", err)
+	}
+	want := []string{"IssueOrPullRequest", "SearchResultItem", "IssueState", "SearchType"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListTypesOfKind = %v, want %v", got, want)
+	}
+	if got, err := s.ListTypesOfKind("DIRECTIVE"); err != nil || len(got) != 0 {
+		t.Errorf("ListTypesOfKind(DIRECTIVE) = %v, %v; want empty", got, err)
+	}
+}
@@ -0,0 +1,160 @@
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RetentionPolicy decides which files of a snapshot archive or cache
+// directory to keep, so directories that collect a snapshot per download do
+// not grow without bound. A file is kept when either rule keeps it.
+type RetentionPolicy struct {
+	KeepLast    int `json:"keepLast,omitempty"`    // Newest files to keep
+	KeepMonthly int `json:"keepMonthly,omitempty"` // Months, newest first, whose newest file is kept
+}
+
+// CacheRetention is the policy NewCached and friends prune the cache
+// directory with after writing an entry. Entries are dated by their last
+// use. A zero policy disables pruning.
+var CacheRetention = RetentionPolicy{KeepLast: 8}
+
+// snapshotSuffixes are the file name suffixes of the files pruning considers:
+// schema files as downloaded or exported, and parsed schema cache entries
+var snapshotSuffixes = []string{".json", ".json.gz", ".json.zst", ".graphql", ".gql", ".gob"}
+
+// SnapshotFile is a file of a snapshot archive or cache directory
+type SnapshotFile struct {
+	Path    string    `json:"path"`
+	ModTime time.Time `json:"modTime"` // When the file was written, or last used for cache entries
+	Size    int64     `json:"size"`
+}
+
+// ListSnapshots returns the schema files and cache entries of dir, newest
+// first. Hidden files, such as cache entries being written, subdirectories,
+// and files that do not start like an introspection result, SDL, or gzip or
+// zstd stream, such as other JSON files, are left out.
+func ListSnapshots(dir string) ([]SnapshotFile, error) {
+	return listFiles(dir, func(name string) bool {
+		for _, suffix := range snapshotSuffixes {
+			if strings.HasSuffix(name, suffix) {
+				return suffix == ".gob" || looksLikeSchema(filepath.Join(dir, name))
+			}
+		}
+		return false
+	})
+}
+
+// looksLikeSchema reports whether the file at path starts like a schema file
+func looksLikeSchema(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, 4096)
+	n, _ := io.ReadFull(f, head)
+	head = head[:n]
+	return detectCompression(head) != NoCompression || isSDL(head) || bytes.Contains(head, []byte(`"__schema"`))
+}
+
+// listFiles returns the regular, not hidden files of dir whose names match,
+// newest first
+func listFiles(dir string, match func(name string) bool) ([]SnapshotFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+	var files []SnapshotFile
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") || !match(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // Removed since ReadDir
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", e.Name(), err)
+		}
+		files = append(files, SnapshotFile{Path: filepath.Join(dir, e.Name()), ModTime: info.ModTime(), Size: info.Size()})
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].ModTime.After(files[j].ModTime) })
+	return files, nil
+}
+
+// Select splits files, which must be sorted newest first as ListSnapshots
+// returns them, into the files the policy keeps and the ones it removes.
+// Months are calendar months in UTC.
+func (p RetentionPolicy) Select(files []SnapshotFile) (keep, remove []SnapshotFile) {
+	months := make(map[string]bool)
+	for i, f := range files {
+		month := f.ModTime.UTC().Format("2006-01")
+		kept := i < p.KeepLast
+		if !months[month] && len(months) < p.KeepMonthly {
+			months[month] = true
+			kept = true
+		}
+		if kept {
+			keep = append(keep, f)
+		} else {
+			remove = append(remove, f)
+		}
+	}
+	return keep, remove
+}
+
+// Prune removes the schema files and cache entries of dir that policy does
+// not keep and returns them; with dryRun it only returns them. A zero policy
+// would remove everything and is rejected.
+func Prune(dir string, policy RetentionPolicy, dryRun bool) ([]SnapshotFile, error) {
+	if err := policy.check(); err != nil {
+		return nil, err
+	}
+	files, err := ListSnapshots(dir)
+	if err != nil {
+		return nil, err
+	}
+	return removeFiles(policy, files, dryRun)
+}
+
+// PruneCache is Prune for a parsed schema cache directory, which only
+// considers the cache entries, dated by their last use
+func PruneCache(cacheDir string, policy RetentionPolicy, dryRun bool) ([]SnapshotFile, error) {
+	if err := policy.check(); err != nil {
+		return nil, err
+	}
+	files, err := listFiles(cacheDir, func(name string) bool { return strings.HasSuffix(name, ".gob") })
+	if err != nil {
+		return nil, err
+	}
+	return removeFiles(policy, files, dryRun)
+}
+
+func (p RetentionPolicy) check() error {
+	if p.KeepLast <= 0 && p.KeepMonthly <= 0 {
+		return fmt.Errorf("retention policy keeps nothing; set KeepLast or KeepMonthly")
+	}
+	return nil
+}
+
+// removeFiles removes the files policy does not keep, unless dryRun
+func removeFiles(policy RetentionPolicy, files []SnapshotFile, dryRun bool) ([]SnapshotFile, error) {
+	_, remove := policy.Select(files)
+	if dryRun {
+		return remove, nil
+	}
+	for i, f := range remove {
+		if err := os.Remove(f.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return remove[:i], fmt.Errorf("failed to remove %s: %w", f.Path, err)
+		}
+	}
+	return remove, nil
+}
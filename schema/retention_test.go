@@ -0,0 +1,123 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRetentionPolicySelect(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+	// Newest first, as ListSnapshots returns them
+	var files []SnapshotFile
+	for _, d := range []string{"2024-05-20", "2024-05-10", "2024-04-28", "2024-04-02", "2024-02-15", "2023-12-31"} {
+		files = append(files, SnapshotFile{Path: d, ModTime: day(d)})
+	}
+	paths := func(files []SnapshotFile) []string {
+		var p []string
+		for _, f := range files {
+			p = append(p, f.Path)
+		}
+		return p
+	}
+
+	tests := []struct {
+		policy RetentionPolicy
+		keep   []string
+	}{
+		{RetentionPolicy{KeepLast: 2}, []string{"2024-05-20", "2024-05-10"}},
+		{RetentionPolicy{KeepMonthly: 3}, []string{"2024-05-20", "2024-04-28", "2024-02-15"}},
+		{RetentionPolicy{KeepLast: 2, KeepMonthly: 4}, []string{"2024-05-20", "2024-05-10", "2024-04-28", "2024-02-15", "2023-12-31"}},
+		{RetentionPolicy{KeepLast: 10}, paths(files)},
+	}
+	for _, tt := range tests {
+		keep, remove := tt.policy.Select(files)
+		if !slices.Equal(paths(keep), tt.keep) {
+			t.Errorf("%+v keeps %v, want %v", tt.policy, paths(keep), tt.keep)
+		}
+		if len(keep)+len(remove) != len(files) {
+			t.Errorf("%+v keeps %d and removes %d of %d files", tt.policy, len(keep), len(remove), len(files))
+		}
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	write := func(name, content string, age time.Duration) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snapshot := `{"data": {"__schema": {"types": []}}}`
+	write("a.json", snapshot, 1*time.Hour)
+	write("b.json", snapshot, 2*time.Hour)
+	write("c.graphql", "type Query { a: Int }", 3*time.Hour)
+	write("d.json.gz", "\x1f\x8b", 4*time.Hour)
+	// Not snapshots
+	write("package.json", `{"name": "app"}`, 5*time.Hour)
+	write(".e.json", snapshot, 6*time.Hour)
+	write("notes.txt", "", 7*time.Hour)
+
+	if _, err := Prune(dir, RetentionPolicy{}, false); err == nil {
+		t.Error("Expected an error for a policy that keeps nothing")
+	}
+
+	removed, err := Prune(dir, RetentionPolicy{KeepLast: 2}, true)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if len(removed) != 2 || filepath.Base(removed[0].Path) != "c.graphql" || filepath.Base(removed[1].Path) != "d.json.gz" {
+		t.Fatalf("Dry run would remove %+v", removed)
+	}
+	if _, err := os.Stat(removed[0].Path); err != nil {
+		t.Errorf("Dry run removed %s", removed[0].Path)
+	}
+
+	if _, err := Prune(dir, RetentionPolicy{KeepLast: 2}, false); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	var left []string
+	for _, e := range entries {
+		left = append(left, e.Name())
+	}
+	if want := []string{".e.json", "a.json", "b.json", "notes.txt", "package.json"}; !slices.Equal(left, want) {
+		t.Errorf("Left %v, want %v", left, want)
+	}
+}
+
+func TestPruneCache(t *testing.T) {
+	dir := t.TempDir()
+	defer func(policy RetentionPolicy) { CacheRetention = policy }(CacheRetention)
+	CacheRetention = RetentionPolicy{KeepLast: 1}
+
+	first := []byte(`{"data": {"__schema": {"types": [{"kind": "OBJECT", "name": "A"}]}}}`)
+	if _, err := NewCachedWithData(first, dir); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	entry := filepath.Join(dir, Fingerprint(first)+"."+cacheFormatVersion+".gob")
+	if err := os.Chtimes(entry, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewCachedWithData([]byte(`{"data": {"__schema": {"types": []}}}`), dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(entry); err == nil {
+		t.Error("Expected the older cache entry to be pruned")
+	}
+	if files, _ := ListSnapshots(dir); len(files) != 1 {
+		t.Errorf("Expected one cache entry, got %+v", files)
+	}
+}
@@ -0,0 +1,32 @@
+package schema
+
+import (
+	_ "embed"
+	"log/slog"
+)
+
+// A small, self-consistent subset of the GitHub schema for tests: the Query
+// and Mutation roots with a few fields, Repository, Issue, PullRequest, and
+// User, a connection with edges and PageInfo, interfaces, unions, enums,
+// nested input objects, and deprecated members. It is generated from the
+// embedded schema by internal/gensample, which copies the definitions
+// verbatim and narrows interfaces and unions to the copied types. Every
+// referenced type is included.
+//
+//go:embed sample.json
+var sampleSchema []byte
+
+// NewSample creates a Schema instance from a small subset of the GitHub
+// schema, for unit tests of tools built on this package. It parses in
+// well under a millisecond and does not depend on the full embedded schema.
+// Its definitions follow the embedded schema when the sample is regenerated.
+func NewSample() (*Schema, error) {
+	slog.Debug("Creating schema from sample data", "size", len(sampleSchema))
+	return NewWithDataStrict(sampleSchema)
+}
+
+// SampleData returns a copy of the introspection document behind NewSample,
+// for tests that modify it or load it with other constructors
+func SampleData() []byte {
+	return append([]byte(nil), sampleSchema...)
+}
@@ -0,0 +1,115 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewSample(t *testing.T) {
+	s, err := NewSample()
+	if err != nil {
+		t.Fatalf("NewSample failed: %v", err)
+	}
+	m := s.Model()
+
+	for _, name := range []string{"Query", "Mutation", "Repository", "Issue", "PullRequest", "User", "Node", "IssueOrPullRequest"} {
+		if m.Type(name) == nil {
+			t.Errorf("Expected %s in the sample", name)
+		}
+	}
+	if m.Mutation("createIssue") == nil || m.QueryField("repository") == nil {
+		t.Error("Expected createIssue and repository root fields")
+	}
+
+	// Every reference must resolve, so tools can walk the sample freely
+	resolve := func(ref *TypeRef, where string) {
+		if name := ref.NamedType(); m.Type(name) == nil {
+			t.Errorf("%s references missing type %q", where, name)
+		}
+	}
+	for _, typ := range m.Types {
+		for _, f := range typ.Fields {
+			resolve(f.Type, typ.Name+"."+f.Name)
+			for _, a := range f.Args {
+				resolve(a.Type, typ.Name+"."+f.Name+"("+a.Name+":)")
+			}
+		}
+		for _, f := range typ.InputFields {
+			resolve(f.Type, typ.Name+"."+f.Name)
+		}
+		for _, name := range append(typ.Interfaces, typ.PossibleTypes...) {
+			if m.Type(name) == nil {
+				t.Errorf("%s references missing type %q", typ.Name, name)
+			}
+		}
+	}
+
+	data := SampleData()
+	data[0] = 'x'
+	if SampleData()[0] == 'x' {
+		t.Error("SampleData returned the embedded bytes instead of a copy")
+	}
+}
+
+// TestSampleSubset checks that the sample is cut from the embedded schema:
+// every member it has exists there with the same type
+func TestSampleSubset(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Loads the embedded schema")
+	}
+	sample, err := NewSample()
+	if err != nil {
+		t.Fatalf("NewSample failed: %v", err)
+	}
+	embedded, err := New()
+	if err != nil {
+		t.Fatalf("Failed to load embedded schema: %v", err)
+	}
+	full := embedded.Model()
+
+	contains := func(list []string, name string) bool {
+		for _, item := range list {
+			if item == name {
+				return true
+			}
+		}
+		return false
+	}
+	sameInputs := func(where string, got, want []*InputValue) {
+		if len(got) != len(want) {
+			t.Errorf("%s has %d arguments or input fields, want %d", where, len(got), len(want))
+			return
+		}
+		for i, v := range got {
+			if v.Name != want[i].Name || v.Type.String() != want[i].Type.String() || !reflect.DeepEqual(v.DefaultValue, want[i].DefaultValue) {
+				t.Errorf("%s.%s differs from the GitHub schema", where, v.Name)
+			}
+		}
+	}
+	for _, typ := range sample.Model().Types {
+		real := full.Type(typ.Name)
+		if real == nil || real.Kind != typ.Kind || real.Description != typ.Description {
+			t.Errorf("Type %s is not in the GitHub schema as it is in the sample", typ.Name)
+			continue
+		}
+		for _, f := range typ.Fields {
+			rf := real.Field(f.Name)
+			if rf == nil || rf.Type.String() != f.Type.String() || rf.IsDeprecated != f.IsDeprecated {
+				t.Errorf("Field %s.%s is not in the GitHub schema as it is in the sample", typ.Name, f.Name)
+				continue
+			}
+			sameInputs(typ.Name+"."+f.Name, f.Args, rf.Args)
+		}
+		if typ.Kind == "INPUT_OBJECT" {
+			sameInputs(typ.Name, typ.InputFields, real.InputFields)
+		}
+		if typ.Kind == "ENUM" && !reflect.DeepEqual(typ.EnumValues, real.EnumValues) {
+			t.Errorf("Enum %s differs from the GitHub schema", typ.Name)
+		}
+		for _, name := range append(typ.Interfaces, typ.PossibleTypes...) {
+			if !contains(append(real.Interfaces, real.PossibleTypes...), name) {
+				t.Errorf("Type %s is not related to %s in the GitHub schema", typ.Name, name)
+			}
+		}
+	}
+}
@@ -1,14 +1,14 @@
 package schema
 
 import (
-	"bytes"
-	"compress/gzip"
 	"context"
 	_ "embed"
 	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
-	"os"
+	"sync"
+	"time"
 
 	jqyaml "github.com/apstndb/go-jq-yamlformat"
 	"github.com/apstndb/go-yamlformat"
@@ -17,79 +17,254 @@ import (
 // Embed the GitHub GraphQL schema in standard introspection format
 // This file is obtained via GitHub GraphQL API introspection query
 //
-//go:embed schema.json.gz
+//go:embed schema.json.zst
 var embeddedSchema []byte
 
 // Schema provides methods to query GitHub GraphQL schema
 type Schema struct {
-	data interface{} // Parsed JSON schema
+	data     interface{} // Parsed JSON schema, see tree
+	dataOnce sync.Once
+	metadata *Metadata // Of a schema loaded from the cache, see tree
+
+	indexOnce sync.Once
+	index     map[string]map[string]interface{} // Type entries by name, see rawTypes
+
+	modelOnce sync.Once
+	model     *Model // Typed model, see Model
+
+	fingerprintOnce sync.Once
+	fingerprint     string // See Schema.Fingerprint
 }
 
 // New creates a Schema instance using the embedded schema
 func New() (*Schema, error) {
 	slog.Debug("Creating schema from embedded data", "size", len(embeddedSchema))
 	
-	reader, err := gzip.NewReader(bytes.NewReader(embeddedSchema))
+	buf, err := decompress(embeddedSchema)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
-	}
-	defer reader.Close()
-
-	data, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decompress schema: %w", err)
+		return nil, err
 	}
+	// NewWithData does not retain the input, so the buffer can be recycled
+	defer putBuffer(buf)
 	
-	slog.Debug("Decompressed schema", "size", len(data))
+	slog.Debug("Decompressed schema", "size", buf.Len())
 
-	return NewWithData(data)
+	return NewWithData(buf.Bytes())
 }
 
-// NewWithFile creates a Schema instance from a file
+// defaultSchema parses the embedded schema once for Default
+var defaultSchema = sync.OnceValues(New)
+
+// Default returns the embedded schema, decompressed and parsed on the first
+// call and shared by every later call, so programs that need the schema in
+// several places pay for loading it once. A Schema is safe for concurrent use;
+// the shared instance and the values it returns must not be modified. Use New
+// for a private copy.
+func Default() (*Schema, error) {
+	return defaultSchema()
+}
+
+// NewWithFile creates a Schema instance from an introspection result or SDL file
 func NewWithFile(path string) (*Schema, error) {
 	slog.Debug("Loading schema from file", "path", path)
 	
-	data, err := os.ReadFile(path)
+	buf, err := readFileBuffer(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read schema file: %w", err)
 	}
+	defer putBuffer(buf)
 	
-	slog.Debug("Loaded schema file", "size", len(data))
+	slog.Debug("Loaded schema file", "size", buf.Len())
+
+	return NewWithData(buf.Bytes())
+}
+
+// NewWithReader creates a Schema instance from an introspection result or
+// SDL read from r, such as an HTTP response body or an archive entry. Like
+// files, the data may be gzip or zstd compressed. r is read to the end but
+// not closed.
+func NewWithReader(r io.Reader) (*Schema, error) {
+	buf, err := readBuffer(r, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	defer putBuffer(buf)
 
-	return NewWithData(data)
+	return NewWithData(buf.Bytes())
 }
 
-// NewWithData creates a Schema instance from raw JSON data
+// NewWithFS creates a Schema instance from the introspection result or SDL
+// file at path in fsys, such as an embed.FS, like NewWithFile
+func NewWithFS(fsys fs.FS, path string) (*Schema, error) {
+	slog.Debug("Loading schema from file system", "path", path)
+
+	f, err := fsys.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+	defer f.Close()
+	buf, err := readStatBuffer(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+	defer putBuffer(buf)
+
+	return NewWithData(buf.Bytes())
+}
+
+// NewWithData creates a Schema instance from raw JSON data, or from a GraphQL
+// SDL document such as GitHub's published schema.docs.graphql, which is
+// converted into an introspection result. Gzip and zstd compressed data, such
+// as the contents of a downloaded schema.json.gz, is decompressed first.
+// Both the full response, {"data": {"__schema": ...}}, and the bare
+// {"__schema": ...} that graphql-js and Apollo tooling write are accepted.
+// Documents whose root operation types, type names, or type references are
+// broken fail with a *ValidationError listing every problem.
+// The parsed schema does not reference data after NewWithData returns.
 func NewWithData(data []byte) (*Schema, error) {
+	return newWithData(data, false)
+}
+
+// newWithData loads data like NewWithData, checking the structure of the
+// document first with strict, so its errors point at the offending value
+// before dangling references are reported
+func newWithData(data []byte, strict bool) (*Schema, error) {
+	if detectCompression(data) != NoCompression {
+		buf, err := decompress(data)
+		if err != nil {
+			return nil, invalidSchema("%w", err)
+		}
+		defer putBuffer(buf)
+		data = buf.Bytes()
+	}
+
 	var schema interface{}
-	// Use consistent unmarshaling with proper number handling
-	if err := yamlformat.Unmarshal(data, &schema); err != nil {
-		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	if isSDL(data) {
+		var err error
+		if schema, err = parseSDL(data); err != nil {
+			return nil, err
+		}
+	} else {
+		// Use consistent unmarshaling with proper number handling
+		if err := yamlformat.Unmarshal(data, &schema); err != nil {
+			return nil, invalidSchema("failed to parse schema: %w", err)
+		}
+		var err error
+		if schema, err = normalizeDocument(schema); err != nil {
+			return nil, err
+		}
+	}
+	if strict {
+		if err := checkStructure(schema); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkReferences(schema); err != nil {
+		return nil, err
 	}
 
 	return &Schema{data: schema}, nil
 }
 
-// Type queries information about a GraphQL type
+// normalizeDocument wraps a bare {"__schema": ...} document in the "data"
+// member of a response. A response with GraphQL errors is accepted as long
+// as it has a schema; without one, the errors are what failed.
+func normalizeDocument(doc interface{}) (interface{}, error) {
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return doc, nil
+	}
+	if _, bare := root["__schema"]; bare && root["data"] == nil {
+		wrapped := map[string]interface{}{"data": map[string]interface{}{"__schema": root["__schema"]}}
+		for key, value := range root {
+			if key != "__schema" && key != "data" {
+				wrapped[key] = value
+			}
+		}
+		return wrapped, nil
+	}
+	errs, _ := root["errors"].([]interface{})
+	if len(errs) == 0 {
+		return doc, nil
+	}
+	if data, _ := root["data"].(map[string]interface{}); data["__schema"] == nil {
+		return nil, invalidSchema("introspection result has no schema but %d errors: %s", len(errs), graphQLErrorMessage(errs[0]))
+	}
+	slog.Warn("Introspection result has errors; loading its schema anyway", "errors", len(errs), "first", graphQLErrorMessage(errs[0]))
+	return doc, nil
+}
+
+// graphQLErrorMessage returns the message of an entry of a response's errors
+func graphQLErrorMessage(e interface{}) string {
+	if m, ok := e.(map[string]interface{}); ok {
+		if message, ok := m["message"].(string); ok {
+			return message
+		}
+	}
+	return fmt.Sprint(e)
+}
+
+// Type queries information about a GraphQL type. Unknown types fail with a
+// *NotFoundError suggesting similar names.
 func (s *Schema) Type(typeName string) (map[string]interface{}, error) {
-	query := typeQuery
-	return s.runQuery(query, map[string]interface{}{"type": typeName})
+	m := s.Model()
+	t := m.Type(typeName)
+	if t == nil {
+		return nil, m.typeNotFound(typeName)
+	}
+	return typeResult(t), nil
 }
 
-// Search searches for types matching a pattern
+// Search searches for types matching a pattern. Besides the matches, the
+// result counts them per category of the taxonomy in facets, see Categories.
 func (s *Schema) Search(pattern string) (map[string]interface{}, error) {
-	query := searchQuery
-	return s.runQuery(query, map[string]interface{}{"pattern": pattern})
+	result, err := s.Model().searchResult(pattern)
+	if err != nil {
+		return nil, err
+	}
+	results, _ := result["results"].([]interface{})
+	result["facets"] = categoryFacets(results)
+	return result, nil
 }
 
-// Mutation queries information about a GraphQL mutation
+// Mutation queries information about a GraphQL mutation. Unknown mutations
+// fail with a *NotFoundError suggesting similar names.
 func (s *Schema) Mutation(mutationName string) (map[string]interface{}, error) {
-	query := mutationQuery
-	return s.runQuery(query, map[string]interface{}{"mutation": mutationName})
+	m := s.Model()
+	f := m.Mutation(mutationName)
+	if f == nil {
+		return nil, m.rootFieldNotFound(m.MutationType, "Mutation", "mutation", mutationName)
+	}
+	return m.mutationResult(f), nil
 }
 
-// Query runs a custom jq query on the schema
+// QueryField queries information about a field on the Query root: its return
+// type and its arguments with their requiredness and default values. An
+// argument is required when it is non-null and has no default.
+func (s *Schema) QueryField(fieldName string) (map[string]interface{}, error) {
+	m := s.Model()
+	f := m.QueryField(fieldName)
+	if f == nil {
+		return nil, m.rootFieldNotFound(m.QueryType, "Query", "field", fieldName)
+	}
+	return queryFieldResult(f), nil
+}
+
+// DefaultQueryTimeout bounds how long Query runs a jq expression
+var DefaultQueryTimeout = 30 * time.Second
+
+// Query runs a custom jq query on the schema, giving up after
+// DefaultQueryTimeout
 func (s *Schema) Query(jqQuery string, variables map[string]interface{}) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultQueryTimeout)
+	defer cancel()
+	return s.QueryContext(ctx, jqQuery, variables)
+}
+
+// QueryContext runs a custom jq query on the schema until ctx is done, so
+// callers can bound or cancel expressions that walk the whole document. An
+// interrupted query fails with an error wrapping ctx.Err().
+func (s *Schema) QueryContext(ctx context.Context, jqQuery string, variables map[string]interface{}) (interface{}, error) {
 	// Create pipeline with the query
 	pipeline, err := jqyaml.New(jqyaml.WithQuery(jqQuery))
 	if err != nil {
@@ -103,6 +278,8 @@ func (s *Schema) Query(jqQuery string, variables map[string]interface{}) (interf
 			results = append(results, item)
 			return nil
 		}),
+		// ctx is the only limit
+		jqyaml.WithTimeout(0),
 	}
 	
 	// Add variables if provided
@@ -111,8 +288,10 @@ func (s *Schema) Query(jqQuery string, variables map[string]interface{}) (interf
 	}
 
 	// Execute the pipeline
-	ctx := context.Background()
-	if err := pipeline.Execute(ctx, s.data, opts...); err != nil {
+	if err := pipeline.Execute(ctx, s.tree(), opts...); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("jq query interrupted: %w", ctxErr)
+		}
 		return nil, err
 	}
 
@@ -136,7 +315,7 @@ func (s *Schema) runQuery(query string, variables map[string]interface{}) (map[s
 	}
 
 	if result == nil {
-		return nil, fmt.Errorf("no results found")
+		return nil, invalidSchema("no results found")
 	}
 
 	if m, ok := result.(map[string]interface{}); ok {
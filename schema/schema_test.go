@@ -1,8 +1,14 @@
 package schema
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"testing/iotest"
+	"time"
 )
 
 // Test data - minimal schema for testing
@@ -103,6 +109,20 @@ func TestNewWithData(t *testing.T) {
 	}
 }
 
+func TestDefault(t *testing.T) {
+	s, err := Default()
+	if err != nil {
+		t.Fatalf("Failed to load default schema: %v", err)
+	}
+	again, err := Default()
+	if err != nil || again != s {
+		t.Errorf("Default() returned %p and %p (%v), want the same instance", s, again, err)
+	}
+	if got, want := s.Fingerprint(), EmbeddedInfo().Fingerprint; got != want {
+		t.Errorf("Fingerprint() = %s, want the embedded %s", got, want)
+	}
+}
+
 func TestType(t *testing.T) {
 	s, err := NewWithData(testSchemaData)
 	if err != nil {
@@ -317,6 +337,44 @@ func TestMutation(t *testing.T) {
 	}
 }
 
+func TestQueryField(t *testing.T) {
+	s := loadRichSchema(t)
+
+	result, err := s.QueryField("repository")
+	if err != nil {
+		t.Fatalf("QueryField failed: %v", err)
+	}
+	field := result["queryField"].(map[string]interface{})
+	if field["name"] != "repository" || field["type"] != "Repository" {
+		t.Errorf("Unexpected field: %v", field)
+	}
+
+	args := field["arguments"].([]interface{})
+	want := []struct {
+		name         string
+		typ          string
+		required     bool
+		defaultValue interface{}
+	}{
+		{"owner", "String!", true, nil},
+		{"name", "String!", true, nil},
+		{"followRenames", "Boolean", false, "true"},
+	}
+	if len(args) != len(want) {
+		t.Fatalf("Expected %d arguments, got %v", len(want), args)
+	}
+	for i, w := range want {
+		arg := args[i].(map[string]interface{})
+		if arg["name"] != w.name || arg["type"] != w.typ || arg["required"] != w.required || arg["defaultValue"] != w.defaultValue {
+			t.Errorf("Argument %d = %v, want %+v", i, arg, w)
+		}
+	}
+
+	if _, err := s.QueryField("createIssue"); err == nil {
+		t.Error("Expected error for a mutation name")
+	}
+}
+
 func TestQuery(t *testing.T) {
 	s, err := NewWithData(testSchemaData)
 	if err != nil {
@@ -385,6 +443,29 @@ func TestQuery(t *testing.T) {
 	}
 }
 
+func TestQueryContext(t *testing.T) {
+	s, err := NewWithData(testSchemaData)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	result, err := s.QueryContext(context.Background(), `.data.__schema.types[0].name`, nil)
+	if err != nil || result != "PullRequest" {
+		t.Errorf("QueryContext() = %v, %v, want PullRequest", result, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = s.QueryContext(ctx, `last(range(1e12))`, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected an error wrapping context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Query ran for %v after its deadline", elapsed)
+	}
+}
+
 func TestVariableHandling(t *testing.T) {
 	s, err := NewWithData(testSchemaData)
 	if err != nil {
@@ -461,6 +542,43 @@ func TestNewWithFile_NotExist(t *testing.T) {
 	}
 }
 
+func TestNewWithReaderAndFS(t *testing.T) {
+	var compressed bytes.Buffer
+	if err := writeSchema(&compressed, testSchemaData, Zstd); err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{
+		"schemas/github.json":     {Data: testSchemaData},
+		"schemas/github.json.zst": {Data: compressed.Bytes()},
+		"schemas/schema.graphql":  {Data: []byte("type Query { viewer: String }")},
+	}
+
+	for _, path := range []string{"schemas/github.json", "schemas/github.json.zst", "schemas/schema.graphql"} {
+		s, err := NewWithFS(fsys, path)
+		if err != nil {
+			t.Errorf("NewWithFS(%q) failed: %v", path, err)
+			continue
+		}
+		if s.Model().Type("PullRequest") == nil && s.Model().Type("Query") == nil {
+			t.Errorf("NewWithFS(%q) loaded no types", path)
+		}
+	}
+	if _, err := NewWithFS(fsys, "schemas/missing.json"); err == nil || !strings.Contains(err.Error(), "failed to read schema file") {
+		t.Errorf("Expected 'failed to read schema file' error, got: %v", err)
+	}
+
+	s, err := NewWithReader(bytes.NewReader(compressed.Bytes()))
+	if err != nil {
+		t.Fatalf("NewWithReader failed: %v", err)
+	}
+	if _, err := s.Type("PullRequest"); err != nil {
+		t.Errorf("Failed to get PullRequest type: %v", err)
+	}
+	if _, err := NewWithReader(iotest.ErrReader(errors.New("connection reset"))); err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("Expected the read error, got: %v", err)
+	}
+}
+
 func TestNewWithData_InvalidJSON(t *testing.T) {
 	// Use clearly invalid JSON/YAML that go-yaml cannot parse
 	_, err := NewWithData([]byte(`[1, 2, }`))
@@ -472,6 +590,30 @@ func TestNewWithData_InvalidJSON(t *testing.T) {
 	}
 }
 
+func TestNewWithDataDocumentShapes(t *testing.T) {
+	schema := `{"queryType": {"name": "Query"}, "types": [{"kind": "OBJECT", "name": "Query", "fields": [{"name": "viewer", "args": [], "type": {"kind": "SCALAR", "name": "String"}}]}]}`
+	for name, data := range map[string]string{
+		"response":    `{"data": {"__schema": ` + schema + `}}`,
+		"bare":        `{"__schema": ` + schema + `}`,
+		"with errors": `{"data": {"__schema": ` + schema + `}, "errors": [{"message": "field is restricted"}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			s, err := NewWithDataStrict([]byte(data))
+			if err != nil {
+				t.Fatalf("NewWithDataStrict failed: %v", err)
+			}
+			if f, err := s.Field("Query", "viewer"); err != nil || f.Type != "String" {
+				t.Errorf("Query.viewer = %+v, %v", f, err)
+			}
+		})
+	}
+
+	_, err := NewWithData([]byte(`{"data": null, "errors": [{"message": "Bad credentials"}]}`))
+	if err == nil || !strings.Contains(err.Error(), "Bad credentials") {
+		t.Errorf("Expected the GraphQL error to be reported, got %v", err)
+	}
+}
+
 func TestEmptyResults(t *testing.T) {
 	s, err := NewWithData(testSchemaData)
 	if err != nil {
@@ -0,0 +1,581 @@
+package schema
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+)
+
+// builtinScalars and builtinDirectives are defined by the GraphQL
+// specification and left out of SDL output, as graphql-js printSchema does
+var (
+	builtinScalars    = map[string]bool{"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true}
+	builtinDirectives = map[string]bool{"skip": true, "include": true, "deprecated": true, "specifiedBy": true, "oneOf": true}
+)
+
+// defaultDeprecationReason is the reason @deprecated uses when none is given
+const defaultDeprecationReason = "No longer supported"
+
+// ToSDL renders the schema in the GraphQL schema definition language, with
+// descriptions and deprecations, for tools such as gqlparser, genqlient, and
+// graphql-codegen that read SDL rather than introspection results. Built-in
+// scalars, directives, and introspection types are left out, and the schema
+// definition is only written when the schema has a description or the root
+// types are not named Query, Mutation, and Subscription.
+func (s *Schema) ToSDL() string {
+	m := s.Model()
+	var b strings.Builder
+	var blocks []string
+
+	if m.Description != "" ||
+		(m.QueryType != "" && m.QueryType != "Query") ||
+		(m.MutationType != "" && m.MutationType != "Mutation") ||
+		(m.SubscriptionType != "" && m.SubscriptionType != "Subscription") {
+		writeDescription(&b, m.Description, "")
+		b.WriteString("schema {\n")
+		for _, root := range [][2]string{{"query", m.QueryType}, {"mutation", m.MutationType}, {"subscription", m.SubscriptionType}} {
+			if root[1] != "" {
+				b.WriteString("  " + root[0] + ": " + root[1] + "\n")
+			}
+		}
+		b.WriteString("}")
+		blocks = append(blocks, b.String())
+	}
+
+	for _, d := range m.Directives {
+		if !builtinDirectives[d.Name] {
+			blocks = append(blocks, sdlDirective(d))
+		}
+	}
+	for _, t := range m.Types {
+		if strings.HasPrefix(t.Name, "__") || (t.Kind == "SCALAR" && builtinScalars[t.Name]) {
+			continue
+		}
+		blocks = append(blocks, sdlType(t))
+	}
+	if len(blocks) == 0 {
+		return ""
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+func sdlDirective(d *Directive) string {
+	var b strings.Builder
+	writeDescription(&b, d.Description, "")
+	b.WriteString("directive @" + d.Name)
+	writeArgs(&b, d.Args, "")
+	if d.IsRepeatable {
+		b.WriteString(" repeatable")
+	}
+	b.WriteString(" on " + strings.Join(d.Locations, " | "))
+	return b.String()
+}
+
+func sdlType(t *Type) string {
+	var b strings.Builder
+	writeDescription(&b, t.Description, "")
+	switch t.Kind {
+	case "SCALAR":
+		b.WriteString("scalar " + t.Name)
+		if t.SpecifiedByURL != "" {
+			b.WriteString(" @specifiedBy(url: " + graphql.QuoteString(t.SpecifiedByURL) + ")")
+		}
+	case "OBJECT", "INTERFACE":
+		keyword := "type "
+		if t.Kind == "INTERFACE" {
+			keyword = "interface "
+		}
+		b.WriteString(keyword + t.Name)
+		if len(t.Interfaces) > 0 {
+			b.WriteString(" implements " + strings.Join(t.Interfaces, " & "))
+		}
+		b.WriteString(" {\n")
+		for _, f := range t.Fields {
+			writeDescription(&b, f.Description, "  ")
+			b.WriteString("  " + f.Name)
+			writeArgs(&b, f.Args, "  ")
+			b.WriteString(": " + f.Type.String())
+			writeDeprecated(&b, f.IsDeprecated, f.DeprecationReason)
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+	case "UNION":
+		b.WriteString("union " + t.Name)
+		if len(t.PossibleTypes) > 0 {
+			b.WriteString(" = " + strings.Join(t.PossibleTypes, " | "))
+		}
+	case "ENUM":
+		b.WriteString("enum " + t.Name + " {\n")
+		for _, v := range t.EnumValues {
+			writeDescription(&b, v.Description, "  ")
+			b.WriteString("  " + v.Name)
+			writeDeprecated(&b, v.IsDeprecated, v.DeprecationReason)
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+	case "INPUT_OBJECT":
+		b.WriteString("input " + t.Name)
+		if t.OneOf {
+			b.WriteString(" @oneOf")
+		}
+		b.WriteString(" {\n")
+		for _, f := range t.InputFields {
+			writeDescription(&b, f.Description, "  ")
+			b.WriteString("  ")
+			writeInputValue(&b, f)
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+	}
+	return b.String()
+}
+
+// writeArgs writes an argument list, one argument per line when any of them
+// has a description
+func writeArgs(b *strings.Builder, args []*InputValue, indent string) {
+	if len(args) == 0 {
+		return
+	}
+	multiline := false
+	for _, a := range args {
+		multiline = multiline || a.Description != ""
+	}
+	if !multiline {
+		b.WriteString("(")
+		for i, a := range args {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeInputValue(b, a)
+		}
+		b.WriteString(")")
+		return
+	}
+	b.WriteString("(\n")
+	for _, a := range args {
+		writeDescription(b, a.Description, indent+"  ")
+		b.WriteString(indent + "  ")
+		writeInputValue(b, a)
+		b.WriteString("\n")
+	}
+	b.WriteString(indent + ")")
+}
+
+func writeInputValue(b *strings.Builder, v *InputValue) {
+	b.WriteString(v.Name + ": " + v.Type.String())
+	if v.DefaultValue != nil {
+		b.WriteString(" = " + *v.DefaultValue)
+	}
+	writeDeprecated(b, v.IsDeprecated, v.DeprecationReason)
+}
+
+func writeDeprecated(b *strings.Builder, deprecated bool, reason string) {
+	if !deprecated {
+		return
+	}
+	b.WriteString(" @deprecated")
+	if reason != "" && reason != defaultDeprecationReason {
+		b.WriteString("(reason: " + graphql.QuoteString(reason) + ")")
+	}
+}
+
+// writeDescription writes a description on its own line, as a block string
+// when it spans lines
+func writeDescription(b *strings.Builder, description, indent string) {
+	if description == "" {
+		return
+	}
+	if !strings.Contains(description, "\n") {
+		b.WriteString(indent + graphql.QuoteString(description) + "\n")
+		return
+	}
+	b.WriteString(indent + `"""` + "\n")
+	for _, line := range strings.Split(strings.ReplaceAll(description, `"""`, `\"""`), "\n") {
+		if line == "" {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(indent + line + "\n")
+		}
+	}
+	b.WriteString(indent + `"""` + "\n")
+}
+
+// sdlSniffLength bounds the part of a document isSDL looks at
+const sdlSniffLength = 64 * 1024
+
+// isSDL reports whether data is a GraphQL SDL document rather than an
+// introspection result, looking past whitespace and comments at its first token
+func isSDL(data []byte) bool {
+	if len(data) > sdlSniffLength {
+		data = data[:sdlSniffLength]
+	}
+	return graphql.IsSchemaDocument(string(data))
+}
+
+// preludeSDL declares the built-in scalars, directives, and introspection
+// types, which SDL documents leave out
+//
+//go:embed prelude.graphql
+var preludeSDL string
+
+// parseSDL parses an SDL document, such as GitHub's published
+// schema.docs.graphql, and converts it into an introspection result of the
+// same shape as the one Download fetches. Types and directives are sorted by
+// name like GitHub's introspection results, and the built-in scalars,
+// directives, and introspection types are added as a server reports them;
+// declaring them again in the document keeps the built-in ones.
+func parseSDL(data []byte) (interface{}, error) {
+	doc, err := graphql.ParseSchema(string(data))
+	if err != nil {
+		return nil, invalidSchema("failed to parse SDL: %w", err)
+	}
+	prelude, err := graphql.ParseSchema(preludeSDL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the built-in definitions: %w", err)
+	}
+	b, err := newSDLBuilder(prelude, doc)
+	if err != nil {
+		return nil, invalidSchema("failed to parse SDL: %w", err)
+	}
+
+	names := make([]string, 0, len(b.types))
+	for name := range b.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	types := make([]interface{}, len(names))
+	for i, name := range names {
+		types[i] = b.introspectType(b.types[name])
+	}
+
+	names = names[:0]
+	for name := range b.directives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	directives := make([]interface{}, len(names))
+	for i, name := range names {
+		d := b.directives[name]
+		locations := make([]interface{}, len(d.Locations))
+		for j, l := range d.Locations {
+			locations[j] = l
+		}
+		directives[i] = map[string]interface{}{
+			"name":         d.Name,
+			"description":  nullable(d.Description),
+			"locations":    locations,
+			"args":         b.introspectArgs(d.Arguments),
+			"isRepeatable": d.Repeatable,
+		}
+	}
+
+	root := func(name string) interface{} {
+		if name == "" {
+			return nil
+		}
+		return map[string]interface{}{"name": name}
+	}
+	return map[string]interface{}{"data": map[string]interface{}{"__schema": map[string]interface{}{
+		"description":      nullable(b.description),
+		"queryType":        root(b.roots[graphql.Query]),
+		"mutationType":     root(b.roots[graphql.Mutation]),
+		"subscriptionType": root(b.roots[graphql.Subscription]),
+		"types":            types,
+		"directives":       directives,
+	}}}, nil
+}
+
+// sdlBuilder holds the definitions of an SDL document merged with their
+// extensions and the built-in definitions
+type sdlBuilder struct {
+	types       map[string]*graphql.TypeDefinition
+	directives  map[string]*graphql.DirectiveDefinition
+	roots       map[graphql.OperationType]string
+	description string
+	possible    map[string][]string // Objects implementing each interface, sorted
+}
+
+// newSDLBuilder merges the definitions of prelude and doc, applies the
+// extensions of doc, and checks that every referenced type is defined
+func newSDLBuilder(prelude, doc *graphql.SchemaDocument) (*sdlBuilder, error) {
+	b := &sdlBuilder{
+		types:      make(map[string]*graphql.TypeDefinition),
+		directives: make(map[string]*graphql.DirectiveDefinition),
+		roots:      make(map[graphql.OperationType]string),
+		possible:   make(map[string][]string),
+	}
+	for _, def := range prelude.Types {
+		b.types[def.Name] = def
+	}
+	for _, def := range prelude.Directives {
+		b.directives[def.Name] = def
+	}
+
+	var extensions []*graphql.TypeDefinition
+	for _, def := range doc.Types {
+		switch existing := b.types[def.Name]; {
+		case def.Extend:
+			extensions = append(extensions, def)
+		case existing == nil:
+			b.types[def.Name] = def
+		case !slices.Contains(prelude.Types, existing):
+			return nil, fmt.Errorf("%s: type %q is defined twice", def.Pos, def.Name)
+		}
+	}
+	for _, ext := range extensions {
+		def := b.types[ext.Name]
+		switch {
+		case def == nil:
+			return nil, fmt.Errorf("%s: cannot extend type %q, which is not defined", ext.Pos, ext.Name)
+		case def.Kind != ext.Kind:
+			return nil, fmt.Errorf("%s: cannot extend %s %q as %s", ext.Pos, def.Kind, ext.Name, ext.Kind)
+		}
+		merged := *def
+		merged.Directives = slices.Concat(def.Directives, ext.Directives)
+		merged.Interfaces = slices.Concat(def.Interfaces, ext.Interfaces)
+		merged.Fields = slices.Concat(def.Fields, ext.Fields)
+		merged.Types = slices.Concat(def.Types, ext.Types)
+		merged.EnumValues = slices.Concat(def.EnumValues, ext.EnumValues)
+		merged.InputFields = slices.Concat(def.InputFields, ext.InputFields)
+		b.types[ext.Name] = &merged
+	}
+	for _, def := range doc.Directives {
+		existing := b.directives[def.Name]
+		switch {
+		case existing == nil:
+			b.directives[def.Name] = def
+		case !slices.Contains(prelude.Directives, existing):
+			return nil, fmt.Errorf("%s: directive @%s is defined twice", def.Pos, def.Name)
+		}
+	}
+
+	defined := false
+	for _, def := range doc.Schema {
+		if !def.Extend {
+			if defined {
+				return nil, fmt.Errorf("%s: the schema is defined twice", def.Pos)
+			}
+			defined = true
+			b.description = def.Description
+		}
+		for _, op := range def.OperationTypes {
+			if b.types[op.Type] == nil {
+				return nil, fmt.Errorf("%s: %s type %q is not defined", op.Pos, op.Operation, op.Type)
+			}
+			b.roots[op.Operation] = op.Type
+		}
+	}
+	// Without a schema definition the root types go by their usual names
+	if !defined {
+		for op, name := range map[graphql.OperationType]string{graphql.Query: "Query", graphql.Mutation: "Mutation", graphql.Subscription: "Subscription"} {
+			if b.roots[op] == "" && b.types[name] != nil {
+				b.roots[op] = name
+			}
+		}
+	}
+
+	// In name order, so the first error found does not vary
+	names := make([]string, 0, len(b.types))
+	for name := range b.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def := b.types[name]
+		if err := b.checkReferences(def); err != nil {
+			return nil, err
+		}
+		if def.Kind == "OBJECT" {
+			for _, name := range def.Interfaces {
+				b.possible[name] = append(b.possible[name], def.Name)
+			}
+		}
+	}
+	for _, def := range doc.Directives {
+		for _, a := range def.Arguments {
+			if err := b.checkType(a.Type); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b, nil
+}
+
+// checkReferences checks that the types def refers to are defined and of a
+// kind that fits
+func (b *sdlBuilder) checkReferences(def *graphql.TypeDefinition) error {
+	for _, name := range def.Interfaces {
+		if t := b.types[name]; t == nil || t.Kind != "INTERFACE" {
+			return fmt.Errorf("%s: %q implements %q, which is not a defined interface", def.Pos, def.Name, name)
+		}
+	}
+	for _, name := range def.Types {
+		if t := b.types[name]; t == nil || t.Kind != "OBJECT" {
+			return fmt.Errorf("%s: union %q includes %q, which is not a defined object type", def.Pos, def.Name, name)
+		}
+	}
+	for _, f := range def.Fields {
+		if err := b.checkType(f.Type); err != nil {
+			return err
+		}
+		for _, a := range f.Arguments {
+			if err := b.checkType(a.Type); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range def.InputFields {
+		if err := b.checkType(f.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *sdlBuilder) checkType(t *graphql.Type) error {
+	if b.types[t.NamedType()] == nil {
+		return fmt.Errorf("%s: type %q is not defined", t.Pos, t.NamedType())
+	}
+	return nil
+}
+
+// introspectType converts a definition into a __Type entry
+func (b *sdlBuilder) introspectType(def *graphql.TypeDefinition) map[string]interface{} {
+	entry := map[string]interface{}{
+		"kind":          def.Kind,
+		"name":          def.Name,
+		"description":   nullable(def.Description),
+		"fields":        nil,
+		"inputFields":   nil,
+		"interfaces":    nil,
+		"enumValues":    nil,
+		"possibleTypes": nil,
+	}
+	if def.Kind == "SCALAR" {
+		entry["specifiedByURL"] = specifiedBy(def.Directives)
+	} else {
+		entry["specifiedByURL"] = nil
+	}
+	switch def.Kind {
+	case "OBJECT", "INTERFACE":
+		fields := make([]interface{}, len(def.Fields))
+		for i, f := range def.Fields {
+			deprecated, reason := deprecation(f.Directives)
+			fields[i] = map[string]interface{}{
+				"name":              f.Name,
+				"description":       nullable(f.Description),
+				"args":              b.introspectArgs(f.Arguments),
+				"type":              b.introspectTypeRef(f.Type),
+				"isDeprecated":      deprecated,
+				"deprecationReason": reason,
+			}
+		}
+		entry["fields"] = fields
+		entry["interfaces"] = b.namedRefs(def.Interfaces)
+		if def.Kind == "INTERFACE" {
+			entry["possibleTypes"] = b.namedRefs(b.possible[def.Name])
+		}
+	case "UNION":
+		entry["possibleTypes"] = b.namedRefs(def.Types)
+	case "ENUM":
+		values := make([]interface{}, len(def.EnumValues))
+		for i, v := range def.EnumValues {
+			deprecated, reason := deprecation(v.Directives)
+			values[i] = map[string]interface{}{
+				"name":              v.Name,
+				"description":       nullable(v.Description),
+				"isDeprecated":      deprecated,
+				"deprecationReason": reason,
+			}
+		}
+		entry["enumValues"] = values
+	case "INPUT_OBJECT":
+		entry["inputFields"] = b.introspectArgs(def.InputFields)
+		entry["isOneOf"] = directive(def.Directives, "oneOf") != nil
+	}
+	return entry
+}
+
+// introspectArgs converts arguments or input fields into __InputValue
+// entries
+func (b *sdlBuilder) introspectArgs(values []*graphql.InputValueDefinition) []interface{} {
+	list := make([]interface{}, len(values))
+	for i, v := range values {
+		deprecated, reason := deprecation(v.Directives)
+		var defaultValue interface{}
+		if v.DefaultValue != nil {
+			defaultValue = v.DefaultValue.String()
+		}
+		list[i] = map[string]interface{}{
+			"name":              v.Name,
+			"description":       nullable(v.Description),
+			"type":              b.introspectTypeRef(v.Type),
+			"defaultValue":      defaultValue,
+			"isDeprecated":      deprecated,
+			"deprecationReason": reason,
+		}
+	}
+	return list
+}
+
+// introspectTypeRef converts a type reference into nested kind, name, and
+// ofType members
+func (b *sdlBuilder) introspectTypeRef(t *graphql.Type) map[string]interface{} {
+	if t.NonNull {
+		inner := *t
+		inner.NonNull = false
+		return map[string]interface{}{"kind": "NON_NULL", "name": nil, "ofType": b.introspectTypeRef(&inner)}
+	}
+	if t.Elem != nil {
+		return map[string]interface{}{"kind": "LIST", "name": nil, "ofType": b.introspectTypeRef(t.Elem)}
+	}
+	return map[string]interface{}{"kind": b.types[t.Name].Kind, "name": t.Name, "ofType": nil}
+}
+
+func (b *sdlBuilder) namedRefs(names []string) []interface{} {
+	refs := make([]interface{}, len(names))
+	for i, name := range names {
+		refs[i] = b.introspectTypeRef(&graphql.Type{Name: name})
+	}
+	return refs
+}
+
+// directive returns the first directive named name in directives, or nil
+func directive(directives []*graphql.Directive, name string) *graphql.Directive {
+	for _, d := range directives {
+		if d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// deprecation returns isDeprecated and deprecationReason for a @deprecated
+// directive in directives
+func deprecation(directives []*graphql.Directive) (bool, interface{}) {
+	d := directive(directives, "deprecated")
+	if d == nil {
+		return false, nil
+	}
+	if arg := d.Argument("reason"); arg != nil && arg.Value.Kind == graphql.StringValue {
+		return true, arg.Value.Raw
+	}
+	return true, defaultDeprecationReason
+}
+
+// specifiedBy returns the specifiedByURL of a scalar with the directives, the
+// url of its @specifiedBy directive or nil
+func specifiedBy(directives []*graphql.Directive) interface{} {
+	d := directive(directives, "specifiedBy")
+	if d == nil {
+		return nil
+	}
+	if arg := d.Argument("url"); arg != nil && arg.Value.Kind == graphql.StringValue {
+		return arg.Value.Raw
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestToSDLGolden(t *testing.T) {
+	sdl := loadRichSchema(t).ToSDL()
+
+	path := filepath.Join("testdata", "golden", "synthetic.graphql")
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(sdl), 0644); err != nil {
+			t.Fatalf("Failed to write golden file: %v", err)
+		}
+		return
+	}
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file (run with -update to create it): %v", err)
+	}
+	if sdl != string(golden) {
+		t.Errorf("SDL does not match %s (run with -update to rewrite it):\n%s", path, sdl)
+	}
+}
+
+func TestToSDL(t *testing.T) {
+	s, err := NewWithData([]byte(`{"data": {"__schema": {
+  "description": "The example schema.", "queryType": {"name": "Root"}, "mutationType": null, "subscriptionType": null,
+  "types": [
+    {"kind": "OBJECT", "name": "Root", "description": null, "interfaces": [], "fields": [
+      {"name": "when", "description": "Quoted \"time\"\\path", "type": {"kind": "SCALAR", "name": "DateTime", "ofType": null}, "isDeprecated": true, "deprecationReason": "No longer supported",
+       "args": [{"name": "at", "description": null, "type": {"kind": "INPUT_OBJECT", "name": "At", "ofType": null}, "defaultValue": "{hour: 1}"}]}
+    ]},
+    {"kind": "SCALAR", "name": "DateTime", "description": "An ISO-8601 timestamp.\n\nIn UTC.", "specifiedByURL": "https://tools.ietf.org/html/rfc3339"},
+    {"kind": "INPUT_OBJECT", "name": "At", "description": null, "isOneOf": true, "inputFields": [
+      {"name": "hour", "description": null, "type": {"kind": "SCALAR", "name": "Int", "ofType": null}, "defaultValue": null}
+    ]},
+    {"kind": "SCALAR", "name": "Int", "description": "Built in."},
+    {"kind": "OBJECT", "name": "__Type", "description": null, "interfaces": [], "fields": []}
+  ],
+  "directives": [
+    {"name": "deprecated", "description": null, "locations": ["FIELD_DEFINITION"], "args": []},
+    {"name": "preview", "description": "Preview API.", "locations": ["FIELD_DEFINITION", "ENUM_VALUE"], "isRepeatable": true,
+     "args": [{"name": "toggledBy", "description": "The toggle.", "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "SCALAR", "name": "String", "ofType": null}}, "defaultValue": null}]}
+  ]
+}}}`))
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	want := `"The example schema."
+schema {
+  query: Root
+}
+
+"Preview API."
+directive @preview(
+  "The toggle."
+  toggledBy: String!
+) repeatable on FIELD_DEFINITION | ENUM_VALUE
+
+type Root {
+  "Quoted \"time\"\\path"
+  when(at: At = {hour: 1}): DateTime @deprecated
+}
+
+"""
+An ISO-8601 timestamp.
+
+In UTC.
+"""
+scalar DateTime @specifiedBy(url: "https://tools.ietf.org/html/rfc3339")
+
+input At @oneOf {
+  hour: Int
+}
+`
+	if got := s.ToSDL(); got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestNewWithDataSDLRoundTrip(t *testing.T) {
+	golden, err := os.ReadFile(filepath.Join("testdata", "golden", "synthetic.graphql"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	s, err := NewWithData(golden)
+	if err != nil {
+		t.Fatalf("Failed to load SDL: %v", err)
+	}
+	want := loadRichSchema(t).Model()
+	got := s.Model()
+
+	// Built-in scalars and introspection types are not part of the SDL, so
+	// they come from prelude.graphql
+	for _, wt := range want.Types {
+		if strings.HasPrefix(wt.Name, "__") || builtinScalars[wt.Name] {
+			continue
+		}
+		gt := got.Type(wt.Name)
+		if gt == nil {
+			t.Errorf("Type %s is missing", wt.Name)
+			continue
+		}
+		if gt.Kind != wt.Kind || sdlType(gt) != sdlType(wt) {
+			t.Errorf("Type %s differs:\n%s\nwant:\n%s", wt.Name, sdlType(gt), sdlType(wt))
+		}
+	}
+	if got.QueryType != want.QueryType || got.MutationType != want.MutationType {
+		t.Errorf("Root types are %s/%s, want %s/%s", got.QueryType, got.MutationType, want.QueryType, want.MutationType)
+	}
+}
+
+func TestNewWithDataSDL(t *testing.T) {
+	s, err := NewWithData([]byte(`# GitHub-style schema
+"A repository."
+type Repository implements Node {
+  id: ID!
+  "Issues in the repository."
+  issues(
+    "How to order them."
+    orderBy: IssueOrder = {field: CREATED_AT, direction: DESC}
+    labels: [String!] = ["bug"]
+    first: Int
+  ): [Issue]
+  legacy: String @deprecated(reason: "Use ` + "`issues`" + ` instead.")
+}
+
+type Issue implements Node { id: ID! }
+
+interface Node { id: ID! }
+
+input IssueOrder { field: IssueOrderField! direction: OrderDirection! }
+
+enum IssueOrderField { CREATED_AT UPDATED_AT @deprecated }
+
+enum OrderDirection { ASC DESC }
+
+type Query { node(id: ID!): Node repository: Repository }
+
+"A GitHub-style schema."
+schema { query: Query }
+
+scalar URI @specifiedBy(url: "https://www.rfc-editor.org/rfc/rfc3986")
+`))
+	if err != nil {
+		t.Fatalf("Failed to load SDL: %v", err)
+	}
+	m := s.Model()
+
+	if m.QueryType != "Query" || m.MutationType != "" {
+		t.Errorf("Expected root types Query and none, got %q and %q", m.QueryType, m.MutationType)
+	}
+	if q := m.Type("Query"); q == nil || len(q.Fields) != 2 {
+		t.Errorf("Expected Query to have 2 fields without introspection fields, got %+v", q)
+	}
+	if m.Type("String") == nil || m.Type("__Schema") == nil {
+		t.Error("Expected built-in scalars and introspection types to be present")
+	}
+
+	issues := m.Type("Repository").Field("issues")
+	if issues.Description != "Issues in the repository." || issues.Type.String() != "[Issue]" {
+		t.Errorf("Unexpected issues field: %q %s", issues.Description, issues.Type)
+	}
+	for i, want := range []string{"{field: CREATED_AT, direction: DESC}", `["bug"]`} {
+		if def := issues.Args[i].DefaultValue; def == nil || *def != want {
+			t.Errorf("Expected default value %s for %s, got %v", want, issues.Args[i].Name, def)
+		}
+	}
+	if issues.Args[2].DefaultValue != nil {
+		t.Errorf("Expected no default value for first, got %s", *issues.Args[2].DefaultValue)
+	}
+
+	legacy := m.Type("Repository").Field("legacy")
+	if !legacy.IsDeprecated || legacy.DeprecationReason != "Use `issues` instead." {
+		t.Errorf("Unexpected deprecation of legacy: %v %q", legacy.IsDeprecated, legacy.DeprecationReason)
+	}
+	updated := m.Type("IssueOrderField").EnumValues[1]
+	if !updated.IsDeprecated || updated.DeprecationReason != defaultDeprecationReason {
+		t.Errorf("Unexpected deprecation of UPDATED_AT: %v %q", updated.IsDeprecated, updated.DeprecationReason)
+	}
+	if got := m.Type("Node").PossibleTypes; len(got) != 2 || got[0] != "Issue" || got[1] != "Repository" {
+		t.Errorf("Expected possible types [Issue Repository], got %v", got)
+	}
+	if got := m.Type("Repository").Interfaces; len(got) != 1 || got[0] != "Node" {
+		t.Errorf("Expected interfaces [Node], got %v", got)
+	}
+
+	if m.Description != "A GitHub-style schema." || m.Type("URI").SpecifiedByURL != "https://www.rfc-editor.org/rfc/rfc3986" {
+		t.Errorf("Unexpected schema description %q or specifiedByURL %q", m.Description, m.Type("URI").SpecifiedByURL)
+	}
+
+	if _, err := s.Type("IssueOrder"); err != nil {
+		t.Errorf("Expected the predefined lookups to work on SDL input: %v", err)
+	}
+}
+
+func TestNewWithDataSDLExtensions(t *testing.T) {
+	s, err := NewWithData([]byte(`type Query { a: Int }
+extend type Query { b: Node }
+interface Node { id: ID! }
+interface Entity implements Node { id: ID! }
+type User implements Entity & Node { id: ID! }
+union Result = User
+extend union Result = Query
+enum Color { RED }
+extend enum Color { BLUE }
+"Redeclaring a built-in directive keeps the built-in one"
+directive @deprecated(reason: String) on FIELD_DEFINITION
+extend schema { mutation: User }
+`))
+	if err != nil {
+		t.Fatalf("Failed to load SDL: %v", err)
+	}
+	m := s.Model()
+	if q := m.Type("Query"); len(q.Fields) != 2 || q.Fields[1].Type.String() != "Node" {
+		t.Errorf("Expected the extension to add Query.b, got %+v", q.Fields)
+	}
+	// Only object types are possible types
+	if got := m.Type("Node").PossibleTypes; len(got) != 1 || got[0] != "User" {
+		t.Errorf("Expected possible types [User], got %v", got)
+	}
+	if got := m.Type("Result").PossibleTypes; len(got) != 2 || got[1] != "Query" {
+		t.Errorf("Expected union members [User Query], got %v", got)
+	}
+	if got := m.Type("Color").EnumValues; len(got) != 2 {
+		t.Errorf("Expected 2 enum values, got %d", len(got))
+	}
+	if m.QueryType != "Query" || m.MutationType != "User" {
+		t.Errorf("Unexpected root types %q and %q", m.QueryType, m.MutationType)
+	}
+	for _, d := range m.Directives {
+		if d.Name == "deprecated" && (d.Description == "" || len(d.Locations) != 4) {
+			t.Errorf("Expected the built-in @deprecated, got %+v", d)
+		}
+	}
+}
+
+func TestNewWithDataInvalidSDL(t *testing.T) {
+	tests := []struct {
+		sdl  string
+		want string
+	}{
+		{"type Query { repository: Repository }", `1:26: type "Repository" is not defined`},
+		{"type Query { a: Int }\ntype Query { b: Int }", `2:1: type "Query" is defined twice`},
+		{"type Query { a: Int }\nextend type Missing { b: Int }", `cannot extend type "Missing"`},
+		{"type Query { a: Int }\nextend enum Query { B }", `cannot extend OBJECT "Query" as ENUM`},
+		{"type Query implements Int { a: Int }", `"Query" implements "Int", which is not a defined interface`},
+		{"type Query { a: Int }\nunion U = Int", `union "U" includes "Int"`},
+		{"schema { query: Root }", `query type "Root" is not defined`},
+		{"type Query { a: Int }\nschema { query: Query }\nschema { query: Query }", "the schema is defined twice"},
+		{"directive @a on FIELD\ndirective @a on FIELD", "directive @a is defined twice"},
+		{"type Query {", "failed to parse SDL: syntax error at 1:13"},
+	}
+	for _, tt := range tests {
+		_, err := NewWithData([]byte(tt.sdl))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("NewWithData(%q): expected an error containing %q, got %v", tt.sdl, tt.want, err)
+		}
+	}
+}
+
+func TestIsSDL(t *testing.T) {
+	tests := []struct {
+		data string
+		want bool
+	}{
+		{`{"data": {}}`, false},
+		{"  \n{}", false},
+		{"data:\n  __schema: {}", false},
+		{"", false},
+		{"type Query { a: Int }", true},
+		{"\ufeffschema { query: Q }", true},
+		{"# comment\n\n\"\"\"Doc\"\"\"\nscalar A", true},
+		{"directive @a on FIELD", true},
+		{"# only a comment", false},
+	}
+	for _, tt := range tests {
+		if got := isSDL([]byte(tt.data)); got != tt.want {
+			t.Errorf("isSDL(%q) = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
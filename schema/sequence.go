@@ -0,0 +1,284 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// MutationSequence is a chain of mutations planned by SequenceMutations
+type MutationSequence struct {
+	Steps []MutationStep `json:"steps"`
+	// Warnings name required ID inputs that no earlier payload provides, so
+	// they have to be given by the caller
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// MutationStep is one mutation of a MutationSequence
+type MutationStep struct {
+	Mutation string          `json:"mutation"`
+	Inputs   []SequenceInput `json:"inputs"`  // Required inputs and IDs from earlier steps
+	Outputs  []string        `json:"outputs"` // Paths of the IDs the payload provides, such as "projectV2.id"
+
+	wrapped  bool            // The inputs are the fields of a single input argument
+	asString map[string]bool // Inputs whose values are passed as strings
+}
+
+// SequenceInput is an input of a mutation and where its value comes from
+type SequenceInput struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	// FromStep and FromPath locate the ID in the payload of an earlier step,
+	// numbered from 1. Inputs without them are given by the caller.
+	FromStep int    `json:"fromStep,omitempty"`
+	FromPath string `json:"fromPath,omitempty"`
+}
+
+// SequenceMutations plans a chain of mutations, such as createProjectV2 then
+// addProjectV2ItemById, by matching the ID inputs of each mutation with the
+// IDs the payloads of earlier mutations provide.
+//
+// A payload provides the id of each of its fields whose type has one. An
+// input named like projectId or labelIds takes the most recent provided ID
+// whose payload field or type starts with its stem ("project" matches the
+// projectV2 field), or whose type implements an interface or belongs to a
+// union with a name ending in the stem. Required ID inputs left unmatched
+// are reported as warnings.
+func (s *Schema) SequenceMutations(mutations []string) (*MutationSequence, error) {
+	if len(mutations) == 0 {
+		return nil, fmt.Errorf("no mutations to sequence")
+	}
+	m := s.Model()
+
+	type provided struct {
+		step     int
+		path     string
+		stems    []string // Lowercased names the ID can be matched by
+		suffixes []string // Lowercased names whose suffix can match
+	}
+	var available []provided
+	seq := &MutationSequence{}
+	for i, name := range mutations {
+		field := m.Mutation(name)
+		if field == nil {
+			return nil, m.rootFieldNotFound(m.MutationType, "Mutation", "mutation", name)
+		}
+		step := MutationStep{Mutation: name, Inputs: []SequenceInput{}, Outputs: []string{}, asString: make(map[string]bool)}
+
+		inputs := field.Args
+		if len(field.Args) == 1 && field.Args[0].Name == "input" {
+			if t := m.Type(field.Args[0].Type.NamedType()); t != nil && t.Kind == "INPUT_OBJECT" {
+				inputs, step.wrapped = t.InputFields, true
+			}
+		}
+		for _, in := range inputs {
+			input := SequenceInput{Name: in.Name, Type: in.Type.String(), Required: in.Required()}
+			if in.Type.NamedType() == "ID" {
+				stem := strings.ToLower(strings.TrimSuffix(strings.TrimSuffix(in.Name, "Ids"), "Id"))
+				for j := len(available) - 1; j >= 0 && stem != ""; j-- {
+					p := available[j]
+					if slices.ContainsFunc(p.stems, func(n string) bool { return strings.HasPrefix(n, stem) }) ||
+						slices.ContainsFunc(p.suffixes, func(n string) bool { return strings.HasSuffix(n, stem) }) {
+						input.FromStep, input.FromPath = p.step, p.path
+						break
+					}
+				}
+				if input.Required && input.FromStep == 0 {
+					seq.Warnings = append(seq.Warnings, fmt.Sprintf("%s.%s (%s) is not provided by an earlier mutation", name, in.Name, input.Type))
+				}
+			}
+			if input.Required || input.FromStep > 0 {
+				step.Inputs = append(step.Inputs, input)
+				step.asString[in.Name] = isStringInput(m, in.Type)
+			}
+		}
+
+		if payload := m.Type(field.Type.NamedType()); payload != nil {
+			for _, f := range payload.Fields {
+				t := m.Type(f.Type.NamedType())
+				if t == nil || t.Field("id") == nil || UnwrapTypeRef(f.Type).IsList() {
+					continue
+				}
+				path := f.Name + ".id"
+				step.Outputs = append(step.Outputs, path)
+				p := provided{step: i + 1, path: path, stems: []string{strings.ToLower(f.Name), strings.ToLower(t.Name)}}
+				for _, iface := range t.Interfaces {
+					p.suffixes = append(p.suffixes, strings.ToLower(iface))
+				}
+				for _, u := range m.Types {
+					if u.Kind == "UNION" && slices.Contains(u.PossibleTypes, t.Name) {
+						p.suffixes = append(p.suffixes, strings.ToLower(u.Name))
+					}
+				}
+				available = append(available, p)
+			}
+		}
+		seq.Steps = append(seq.Steps, step)
+	}
+	return seq, nil
+}
+
+// Script returns a POSIX shell script running the sequence with gh and jq.
+// Each step posts its mutation with gh api graphql and extracts the IDs
+// later steps use into shell variables; inputs given by the caller are read
+// from environment variables named after them, such as OWNER_ID, which the
+// script checks are set. String, ID, enum, and custom scalar inputs are
+// passed as strings, others as JSON text.
+func (q *MutationSequence) Script() string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# Runs " + strings.Join(q.mutationNames(), ", then ") + "\n")
+	b.WriteString("set -eu\n\n")
+
+	// Shell variables of caller inputs and of extracted IDs
+	callerVars := make([][]string, len(q.Steps))
+	used := make(map[string]bool)
+	var required []string
+	for i, step := range q.Steps {
+		callerVars[i] = make([]string, len(step.Inputs))
+		for j, in := range step.Inputs {
+			if in.FromStep > 0 {
+				continue
+			}
+			v := shellName(in.Name)
+			if used[v] {
+				v = shellName(step.Mutation) + "_" + v
+			}
+			used[v] = true
+			callerVars[i][j] = v
+			required = append(required, fmt.Sprintf(": \"${%s:?%s for %s.%s}\"\n", v, in.Type, step.Mutation, in.Name))
+		}
+	}
+	outputVars := make(map[string]string) // "step/path" to variable
+	for _, step := range q.Steps {
+		for _, in := range step.Inputs {
+			key := fmt.Sprintf("%d/%s", in.FromStep, in.FromPath)
+			if in.FromStep == 0 || outputVars[key] != "" {
+				continue
+			}
+			v := shellName(strings.TrimSuffix(in.FromPath, ".id")) + "_ID"
+			for n := 2; used[v]; n++ {
+				v = fmt.Sprintf("%s_ID_%d", shellName(strings.TrimSuffix(in.FromPath, ".id")), n)
+			}
+			used[v] = true
+			outputVars[key] = v
+		}
+	}
+	for _, line := range required {
+		b.WriteString(line)
+	}
+	if len(required) > 0 {
+		b.WriteString("\n")
+	}
+
+	for i, step := range q.Steps {
+		fmt.Fprintf(&b, "# %d. %s\n", i+1, step.Mutation)
+		fmt.Fprintf(&b, "QUERY%d='%s'\n", i+1, strings.TrimSuffix(step.operation(), "\n"))
+
+		var args, vars []string
+		for j, in := range step.Inputs {
+			value := "$" + callerVars[i][j]
+			if in.FromStep > 0 {
+				value = "$" + outputVars[fmt.Sprintf("%d/%s", in.FromStep, in.FromPath)]
+			}
+			option, ref := "--argjson", "$"+in.Name
+			switch {
+			case in.FromStep > 0:
+				option = "--arg"
+				if strings.HasPrefix(in.Type, "[") {
+					ref = "[$" + in.Name + "]"
+				}
+			case step.asString[in.Name]:
+				option = "--arg"
+			}
+			args = append(args, fmt.Sprintf("%s %s \"%s\"", option, in.Name, value))
+			vars = append(vars, in.Name+": "+ref)
+		}
+		fmt.Fprintf(&b, "STEP%d=$(jq -n --arg query \"$QUERY%[1]d\"", i+1)
+		for _, a := range args {
+			b.WriteString(" \\\n  " + a)
+		}
+		fmt.Fprintf(&b, " \\\n  '{query: $query, variables: {%s}}' | gh api graphql --input -)\n", strings.Join(vars, ", "))
+		for _, path := range step.Outputs {
+			if v := outputVars[fmt.Sprintf("%d/%s", i+1, path)]; v != "" {
+				fmt.Fprintf(&b, "%s=$(printf '%%s\\n' \"$STEP%d\" | jq -r '.data.%s.%s')\n", v, i+1, step.Mutation, path)
+			}
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "printf '%%s\\n' \"$STEP%d\"\n", len(q.Steps))
+	return b.String()
+}
+
+func (q *MutationSequence) mutationNames() []string {
+	names := make([]string, len(q.Steps))
+	for i, step := range q.Steps {
+		names[i] = step.Mutation
+	}
+	return names
+}
+
+// operation returns the mutation document of a step, selecting the IDs its
+// payload provides
+func (step MutationStep) operation() string {
+	var defs, args []string
+	for _, in := range step.Inputs {
+		defs = append(defs, fmt.Sprintf("$%s: %s", in.Name, in.Type))
+		args = append(args, fmt.Sprintf("%s: $%s", in.Name, in.Name))
+	}
+
+	var b strings.Builder
+	b.WriteString("mutation " + strings.ToUpper(step.Mutation[:1]) + step.Mutation[1:])
+	if len(defs) > 0 {
+		b.WriteString("(" + strings.Join(defs, ", ") + ")")
+	}
+	b.WriteString(" {\n  " + step.Mutation)
+	switch {
+	case step.wrapped:
+		b.WriteString("(input: {" + strings.Join(args, ", ") + "})")
+	case len(args) > 0:
+		b.WriteString("(" + strings.Join(args, ", ") + ")")
+	}
+	b.WriteString(" {\n")
+	if len(step.Outputs) == 0 {
+		b.WriteString("    clientMutationId\n")
+	}
+	for _, path := range step.Outputs {
+		fmt.Fprintf(&b, "    %s {\n      id\n    }\n", strings.TrimSuffix(path, ".id"))
+	}
+	b.WriteString("  }\n}\n")
+	return b.String()
+}
+
+// isStringInput reports whether values of an input type are passed as JSON
+// strings: IDs, strings, enums, and custom scalars
+func isStringInput(m *Model, ref *TypeRef) bool {
+	if UnwrapTypeRef(ref).IsList() {
+		return false
+	}
+	t := m.Type(ref.NamedType())
+	switch {
+	case t == nil:
+		return false
+	case t.Kind == "ENUM":
+		return true
+	case t.Kind != "SCALAR":
+		return false
+	}
+	return t.Name != "Int" && t.Name != "Float" && t.Name != "Boolean"
+}
+
+// shellName converts a name such as projectV2 or ownerId to PROJECT_V2 or
+// OWNER_ID
+func shellName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSequenceMutations(t *testing.T) {
+	s := loadRichSchema(t)
+
+	seq, err := s.SequenceMutations([]string{"addStar", "createIssue"})
+	if err != nil {
+		t.Fatalf("SequenceMutations failed: %v", err)
+	}
+	want := []MutationStep{
+		{
+			Mutation: "addStar",
+			Inputs:   []SequenceInput{{Name: "starrableId", Type: "ID!", Required: true}},
+			Outputs:  []string{"starrable.id"},
+		},
+		{
+			Mutation: "createIssue",
+			Inputs: []SequenceInput{
+				{Name: "repositoryId", Type: "ID!", Required: true, FromStep: 1, FromPath: "starrable.id"},
+				{Name: "title", Type: "String!", Required: true},
+			},
+			Outputs: []string{"issue.id"},
+		},
+	}
+	for i := range seq.Steps {
+		seq.Steps[i].wrapped, seq.Steps[i].asString = false, nil
+	}
+	if !reflect.DeepEqual(seq.Steps, want) {
+		t.Errorf("Expected %+v, got %+v", want, seq.Steps)
+	}
+	if want := []string{"addStar.starrableId (ID!) is not provided by an earlier mutation"}; !reflect.DeepEqual(seq.Warnings, want) {
+		t.Errorf("Expected warnings %v, got %v", want, seq.Warnings)
+	}
+
+	if _, err := s.SequenceMutations([]string{"addStar", "deleteEverything"}); err == nil {
+		t.Error("Expected error for an unknown mutation")
+	}
+}
+
+func TestMutationSequenceScript(t *testing.T) {
+	s := loadRichSchema(t)
+
+	seq, err := s.SequenceMutations([]string{"addStar", "createIssue"})
+	if err != nil {
+		t.Fatalf("SequenceMutations failed: %v", err)
+	}
+	for _, step := range seq.Steps {
+		if errs, err := s.ValidateQuery(step.operation()); err != nil || len(errs) > 0 {
+			t.Errorf("Expected a valid operation for %s, got %v %v:\n%s", step.Mutation, errs, err, step.operation())
+		}
+	}
+
+	script := seq.Script()
+	for _, want := range []string{
+		`: "${STARRABLE_ID:?ID! for addStar.starrableId}"`,
+		`: "${TITLE:?String! for createIssue.title}"`,
+		`STARRABLE_ID_2=$(printf '%s\n' "$STEP1" | jq -r '.data.addStar.starrable.id')`,
+		`--arg repositoryId "$STARRABLE_ID_2"`,
+		`'{query: $query, variables: {repositoryId: $repositoryId, title: $title}}' | gh api graphql --input -)`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("Expected script to contain %q:\n%s", want, script)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// skeletonPageSize is the default of the first variables Skeleton declares
+// for connections
+const skeletonPageSize = 10
+
+// Skeleton expands a dotted field path such as
+// "repository.pullRequests.nodes.reviews" into a complete operation that
+// selects it. The path starts with a field of the query root, or with a root
+// type name such as "Mutation" followed by one of its fields. A segment
+// naming a possible type of the interface or union before it, as in
+// "repository.object.Commit.history", selects an inline fragment on it.
+//
+// Required arguments along the path become variables named after the
+// argument, prefixed with the field name when an earlier field already took
+// the name. Connections get first and after variables, with first defaulting
+// to 10, and a pageInfo selection; when the path ends at a connection or
+// continues with a field of its node type, "nodes" is inserted, or "edges"
+// and "node" for connections without nodes. The path ends in a selection of
+// id, or __typename for types without one. The operation is named after the
+// path, leaving out nodes, edges, and node.
+func (s *Schema) Skeleton(path string) (string, error) {
+	segments := strings.Split(path, ".")
+	for _, name := range segments {
+		if name == "" {
+			return "", fmt.Errorf("path %q has an empty segment", path)
+		}
+	}
+
+	m := s.Model()
+	operation := "query"
+	for _, op := range []string{"query", "mutation", "subscription"} {
+		if root := s.RootTypeName(op); root != "" && root == segments[0] {
+			operation, segments = op, segments[1:]
+			break
+		}
+	}
+	if len(segments) == 0 {
+		return "", fmt.Errorf("path %q must contain at least one field", path)
+	}
+	root := m.Type(s.RootTypeName(operation))
+	if root == nil {
+		return "", fmt.Errorf("schema does not support %s operations", operation)
+	}
+
+	b := &skeletonBuilder{model: m, used: make(map[string]bool)}
+	var body strings.Builder
+	if err := b.selection(&body, root, segments, "  "); err != nil {
+		return "", err
+	}
+
+	var name strings.Builder
+	for _, segment := range b.path {
+		name.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "%s %s", operation, name.String())
+	if len(b.variables) > 0 {
+		fmt.Fprintf(&out, "(%s)", strings.Join(b.variables, ", "))
+	}
+	fmt.Fprintf(&out, " {\n%s}\n", body.String())
+	return out.String(), nil
+}
+
+// skeletonBuilder writes the selections of a skeleton and collects the
+// variables they use
+type skeletonBuilder struct {
+	model     *Model
+	variables []string // Definitions such as "$owner: String!"
+	used      map[string]bool
+	path      []string // Fields walked, for the operation name
+}
+
+// selection writes the selection of segments on parent at indent
+func (b *skeletonBuilder) selection(w *strings.Builder, parent *Type, segments []string, indent string) error {
+	// Step through the connection or edge when the path does not name one
+	// of its own fields
+	if len(segments) == 0 || parent.Field(segments[0]) == nil {
+		switch {
+		case isConnectionType(parent) && parent.Field("nodes") != nil:
+			segments = append([]string{"nodes"}, segments...)
+		case isConnectionType(parent):
+			segments = append([]string{"edges", "node"}, segments...)
+		case isEdgeType(parent):
+			segments = append([]string{"node"}, segments...)
+		}
+	}
+
+	if len(segments) == 0 {
+		leaf := "__typename"
+		if parent.Field("id") != nil {
+			leaf = "id"
+		}
+		fmt.Fprintf(w, "%s%s\n", indent, leaf)
+		return nil
+	}
+
+	name := segments[0]
+	if parent.Field(name) == nil && slices.Contains(parent.PossibleTypes, name) {
+		fmt.Fprintf(w, "%s... on %s {\n", indent, name)
+		if err := b.selection(w, b.model.Type(name), segments[1:], indent+"  "); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s}\n", indent)
+		return nil
+	}
+	if parent.Kind == "UNION" {
+		return fmt.Errorf("cannot select %q on union %s", name, parent.Name)
+	}
+	field := parent.Field(name)
+	if field == nil {
+		return fmt.Errorf("field %q not found on type %q", name, parent.Name)
+	}
+	if name != "nodes" && name != "edges" && name != "node" {
+		b.path = append(b.path, name)
+	}
+
+	named := b.model.Type(field.Type.NamedType())
+	if named == nil {
+		return fmt.Errorf("type %q of field %s.%s not found", field.Type.NamedType(), parent.Name, name)
+	}
+	var args []string
+	for _, arg := range field.Args {
+		paginates := isConnectionType(named) && (arg.Name == "first" || arg.Name == "after")
+		if !arg.Required() && !paginates {
+			continue
+		}
+		variable := b.variable(field.Name, arg.Name, arg.Type.String(), arg.Name == "first" && !arg.Required())
+		args = append(args, arg.Name+": $"+variable)
+	}
+	fmt.Fprintf(w, "%s%s", indent, name)
+	if len(args) > 0 {
+		fmt.Fprintf(w, "(%s)", strings.Join(args, ", "))
+	}
+
+	if !isCompositeKind(named.Kind) {
+		if len(segments) > 1 {
+			return fmt.Errorf("cannot select %q on %s (kind: %s)", segments[1], named.Name, named.Kind)
+		}
+		w.WriteString("\n")
+		return nil
+	}
+	w.WriteString(" {\n")
+	if err := b.selection(w, named, segments[1:], indent+"  "); err != nil {
+		return err
+	}
+	if isConnectionType(named) && (len(segments) < 2 || segments[1] != "pageInfo") {
+		fmt.Fprintf(w, "%s  pageInfo {\n%[1]s    hasNextPage\n%[1]s    endCursor\n%[1]s  }\n", indent)
+	}
+	fmt.Fprintf(w, "%s}\n", indent)
+	return nil
+}
+
+// variable declares a variable for an argument and returns its name
+func (b *skeletonBuilder) variable(field, arg, typ string, paged bool) string {
+	name := arg
+	if b.used[name] {
+		name = field + strings.ToUpper(arg[:1]) + arg[1:]
+	}
+	for i := 2; b.used[name]; i++ {
+		name = fmt.Sprintf("%s%s%d", field, strings.ToUpper(arg[:1])+arg[1:], i)
+	}
+	b.used[name] = true
+
+	definition := fmt.Sprintf("$%s: %s", name, typ)
+	if paged {
+		definition += fmt.Sprintf(" = %d", skeletonPageSize)
+	}
+	b.variables = append(b.variables, definition)
+	return name
+}
+
+// isConnectionType reports whether a type follows the Relay connection shape
+func isConnectionType(t *Type) bool {
+	return t.Field("pageInfo") != nil && (t.Field("nodes") != nil || t.Field("edges") != nil)
+}
+
+// isEdgeType reports whether a type follows the Relay edge shape
+func isEdgeType(t *Type) bool {
+	return t.Field("node") != nil && t.Field("cursor") != nil
+}
@@ -0,0 +1,93 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSkeleton(t *testing.T) {
+	s := loadRichSchema(t)
+
+	got, err := s.Skeleton("repository.issues.repository.issues.author")
+	if err != nil {
+		t.Fatalf("Skeleton failed: %v", err)
+	}
+	want := `query RepositoryIssuesRepositoryIssuesAuthor($owner: String!, $name: String!, $first: Int = 10, $after: String, $issuesFirst: Int = 10, $issuesAfter: String) {
+  repository(owner: $owner, name: $name) {
+    issues(first: $first, after: $after) {
+      nodes {
+        repository {
+          issues(first: $issuesFirst, after: $issuesAfter) {
+            nodes {
+              author {
+                __typename
+              }
+            }
+            pageInfo {
+              hasNextPage
+              endCursor
+            }
+          }
+        }
+      }
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+    }
+  }
+}
+`
+	if got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestSkeletonValid(t *testing.T) {
+	s := loadRichSchema(t)
+
+	for _, path := range []string{
+		"viewer",
+		"viewer.issues",
+		"repository.issues.edges",
+		"repository.issues.pageInfo.endCursor",
+		"repository.issues.totalCount",
+		"search",
+		"Mutation.createIssue.issue.title",
+		"repository.issueOrPullRequest.PullRequest.merged",
+		"search.Issue.author",
+	} {
+		t.Run(path, func(t *testing.T) {
+			doc, err := s.Skeleton(path)
+			if err != nil {
+				t.Fatalf("Skeleton failed: %v", err)
+			}
+			errs, err := s.ValidateQuery(doc)
+			if err != nil || len(errs) > 0 {
+				t.Errorf("Expected a valid operation, got %v %v:\n%s", errs, err, doc)
+			}
+		})
+	}
+
+	doc, _ := s.Skeleton("Mutation.createIssue")
+	if !strings.HasPrefix(doc, "mutation CreateIssue($input: CreateIssueInput!) {") {
+		t.Errorf("Expected a mutation with an input variable, got:\n%s", doc)
+	}
+}
+
+func TestSkeletonErrors(t *testing.T) {
+	s := loadRichSchema(t)
+
+	for _, path := range []string{
+		"",
+		"Query",
+		"repository..name",
+		"repository.missing",
+		"repository.name.length",
+		"repository.issueOrPullRequest.title",
+	} {
+		if _, err := s.Skeleton(path); err == nil {
+			t.Errorf("Skeleton(%q): expected error", path)
+		}
+	}
+}
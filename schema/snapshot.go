@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"fmt"
+	"time"
+)
+
+// IntrospectionOptions describes what an introspection query asked for
+type IntrospectionOptions struct {
+	DeprecatedFields      bool `json:"deprecatedFields"`      // fields(includeDeprecated: true)
+	DeprecatedEnumValues  bool `json:"deprecatedEnumValues"`  // enumValues(includeDeprecated: true)
+	DeprecatedInputValues bool `json:"deprecatedInputValues"` // inputFields and args(includeDeprecated: true)
+	TypeRefDepth          int  `json:"typeRefDepth"`          // Levels of ofType nesting requested
+	SchemaDescription     bool `json:"schemaDescription"`     // __schema { description }
+	SpecifiedByURL        bool `json:"specifiedByURL"`        // __Type.specifiedByURL
+	RepeatableDirectives  bool `json:"repeatableDirectives"`  // __Directive.isRepeatable
+}
+
+// QueryOptions are the options of IntrospectionQuery, which the download
+// functions use
+var QueryOptions = IntrospectionOptions{
+	DeprecatedFields:      true,
+	DeprecatedEnumValues:  true,
+	DeprecatedInputValues: true,
+	TypeRefDepth:          7,
+	SchemaDescription:     true,
+	SpecifiedByURL:        true,
+	RepeatableDirectives:  true,
+}
+
+// LegacyQueryOptions are the options of LegacyIntrospectionQuery, which the
+// download functions fall back to
+var LegacyQueryOptions = IntrospectionOptions{
+	DeprecatedFields:     true,
+	DeprecatedEnumValues: true,
+	TypeRefDepth:         7,
+}
+
+// SnapshotInfo is the provenance of a schema snapshot. CapturedAt is zero and
+// GHESVersion empty when unknown.
+type SnapshotInfo struct {
+	CapturedAt  time.Time            `json:"capturedAt,omitzero"`
+	Endpoint    string               `json:"endpoint"`
+	GHESVersion string               `json:"ghesVersion,omitempty"`
+	Options     IntrospectionOptions `json:"options"`
+	Fingerprint string               `json:"fingerprint"` // Schema.Fingerprint of the snapshot
+}
+
+// EmbeddedInfo returns the provenance of the embedded schema without loading
+// it. It is generated into embedded_info.go whenever the embedded schema is
+// updated, so tools can display and log which GitHub schema they reason about.
+func EmbeddedInfo() SnapshotInfo {
+	return embeddedInfo
+}
+
+// VerifyEmbedded loads the embedded schema and checks that it is a
+// well-formed introspection result with the fingerprint EmbeddedInfo
+// records, catching binaries whose schema was corrupted or replaced
+// without regenerating its provenance
+func VerifyEmbedded() error {
+	s, err := New()
+	if err != nil {
+		return fmt.Errorf("failed to load embedded schema: %w", err)
+	}
+	if err := checkStructure(s.tree()); err != nil {
+		return fmt.Errorf("embedded schema is malformed: %w", err)
+	}
+	if got, want := s.Fingerprint(), EmbeddedInfo().Fingerprint; got != want {
+		return fmt.Errorf("embedded schema has fingerprint %s, but %s is recorded", got, want)
+	}
+	return nil
+}
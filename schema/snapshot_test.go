@@ -0,0 +1,59 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQueryOptions(t *testing.T) {
+	// QueryOptions and LegacyQueryOptions must describe their queries
+	for _, q := range []struct {
+		query   string
+		options IntrospectionOptions
+	}{
+		{IntrospectionQuery, QueryOptions},
+		{LegacyIntrospectionQuery, LegacyQueryOptions},
+	} {
+		typeRef := q.query[strings.Index(q.query, "fragment TypeRef"):]
+		if got := strings.Count(typeRef, "ofType {"); got != q.options.TypeRefDepth {
+			t.Errorf("TypeRefDepth = %d, query nests %d levels", q.options.TypeRefDepth, got)
+		}
+		for _, tt := range []struct {
+			option bool
+			arg    string
+		}{
+			{q.options.DeprecatedFields, "fields(includeDeprecated: true)"},
+			{q.options.DeprecatedEnumValues, "enumValues(includeDeprecated: true)"},
+			{q.options.DeprecatedInputValues, "inputFields(includeDeprecated: true)"},
+			{q.options.SchemaDescription, "__schema {\n\t    description"},
+			{q.options.SpecifiedByURL, "specifiedByURL"},
+			{q.options.RepeatableDirectives, "isRepeatable"},
+		} {
+			if got := strings.Contains(q.query, tt.arg); got != tt.option {
+				t.Errorf("Option for %s is %v, but the query has it: %v", tt.arg, tt.option, got)
+			}
+		}
+	}
+}
+
+func TestEmbeddedInfo(t *testing.T) {
+	info := EmbeddedInfo()
+	if info.CapturedAt.IsZero() || info.Endpoint == "" || len(info.Fingerprint) != 64 || info.Options != QueryOptions && info.Options != LegacyQueryOptions {
+		t.Errorf("Unexpected embedded info %+v", info)
+	}
+
+	if testing.Short() {
+		return
+	}
+	// Fails when schema.json.zst was updated without regenerating embedded_info.go
+	s, err := New()
+	if err != nil {
+		t.Fatalf("Failed to load embedded schema: %v", err)
+	}
+	if s.Fingerprint() != info.Fingerprint {
+		t.Errorf("embedded_info.go is stale: fingerprint %s, embedded schema %s; run go generate ./schema", info.Fingerprint, s.Fingerprint())
+	}
+	if err := VerifyEmbedded(); err != nil {
+		t.Errorf("VerifyEmbedded failed: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions caps the number of names a NotFoundError suggests
+const maxSuggestions = 5
+
+// typeNotFound returns the error for a type name the model does not define
+func (m *Model) typeNotFound(name string) *NotFoundError {
+	names := make([]string, len(m.Types))
+	for i, t := range m.Types {
+		names[i] = t.Name
+	}
+	return &NotFoundError{Kind: "type", Name: name, Suggestions: suggestNames(name, names)}
+}
+
+// fieldNotFound returns the error for a name that is neither a field nor an
+// input field of t
+func fieldNotFound(t *Type, name string) *NotFoundError {
+	var names []string
+	for _, f := range t.Fields {
+		names = append(names, f.Name)
+	}
+	for _, v := range t.InputFields {
+		names = append(names, v.Name)
+	}
+	return &NotFoundError{Kind: "field", Name: name, Parent: t.Name, Suggestions: suggestNames(name, names)}
+}
+
+// rootFieldNotFound returns the error for a field missing from a root type;
+// kind is "mutation" for the mutation root and "field" otherwise
+func (m *Model) rootFieldNotFound(root, fallback, kind, name string) *NotFoundError {
+	err := &NotFoundError{Kind: kind, Name: name}
+	t := m.rootType(root, fallback)
+	if t == nil {
+		return err
+	}
+	if kind == "field" {
+		err.Parent = t.Name
+	}
+	names := make([]string, len(t.Fields))
+	for i, f := range t.Fields {
+		names[i] = f.Name
+	}
+	err.Suggestions = suggestNames(name, names)
+	return err
+}
+
+// suggestNames returns the candidates name was likely meant to be: those
+// within an edit distance of about 40% of its length, or starting with it,
+// compared case-insensitively. The closest come first, at most
+// maxSuggestions of them.
+func suggestNames(name string, candidates []string) []string {
+	type scored struct {
+		name     string
+		distance int
+	}
+	lower := strings.ToLower(name)
+	threshold := len(lower)*2/5 + 1
+	var matches []scored
+	for _, c := range candidates {
+		if c == name {
+			continue
+		}
+		lc := strings.ToLower(c)
+		d := editDistance(lower, lc)
+		if d <= threshold || (len(lower) >= 3 && strings.HasPrefix(lc, lower)) {
+			matches = append(matches, scored{c, d})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+	if len(matches) > maxSuggestions {
+		matches = matches[:maxSuggestions]
+	}
+	var names []string
+	for _, m := range matches {
+		names = append(names, m.name)
+	}
+	return names
+}
+
+// editDistance returns the Levenshtein distance between two strings, counting
+// a transposition of adjacent characters as one edit
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// Three rows suffice: the transposition looks two rows back
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,135 @@
+package schema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNotFoundSuggestions(t *testing.T) {
+	s := loadRichSchema(t)
+	l, err := NewLazyWithData(SampleData())
+	if err != nil {
+		t.Fatalf("Failed to create lazy schema: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		lookup  func() error
+		want    NotFoundError
+		message string
+	}{
+		{
+			name:    "type",
+			lookup:  func() error { _, err := s.Type("PullReqest"); return err },
+			want:    NotFoundError{Kind: "type", Name: "PullReqest", Suggestions: []string{"PullRequest"}},
+			message: `type "PullReqest" not found; did you mean "PullRequest"?`,
+		},
+		{
+			name:    "lazy type",
+			lookup:  func() error { _, err := l.Type("PullReqest"); return err },
+			want:    NotFoundError{Kind: "type", Name: "PullReqest", Suggestions: []string{"PullRequest"}},
+			message: `type "PullReqest" not found; did you mean "PullRequest"?`,
+		},
+		{
+			name:    "transposition",
+			lookup:  func() error { _, err := s.Fields("Reopsitory"); return err },
+			want:    NotFoundError{Kind: "type", Name: "Reopsitory", Suggestions: []string{"Repository"}},
+			message: `type "Reopsitory" not found; did you mean "Repository"?`,
+		},
+		{
+			name:    "mutation",
+			lookup:  func() error { _, err := s.Mutation("createIsue"); return err },
+			want:    NotFoundError{Kind: "mutation", Name: "createIsue", Suggestions: []string{"createIssue"}},
+			message: `mutation "createIsue" not found; did you mean "createIssue"?`,
+		},
+		{
+			name:    "lazy mutation",
+			lookup:  func() error { _, err := l.Mutation("addstr"); return err },
+			want:    NotFoundError{Kind: "mutation", Name: "addstr", Suggestions: []string{"addStar"}},
+			message: `mutation "addstr" not found; did you mean "addStar"?`,
+		},
+		{
+			name:    "query field",
+			lookup:  func() error { _, err := s.QueryField("repo"); return err },
+			want:    NotFoundError{Kind: "field", Name: "repo", Parent: "Query", Suggestions: []string{"repository"}},
+			message: `field "repo" not found on type "Query"; did you mean "repository"?`,
+		},
+		{
+			name:    "field",
+			lookup:  func() error { _, err := s.Field("Repository", "stargazers"); return err },
+			want:    NotFoundError{Kind: "field", Name: "stargazers", Parent: "Repository", Suggestions: []string{"stargazerCount"}},
+			message: `field "stargazers" not found on type "Repository"; did you mean "stargazerCount"?`,
+		},
+		{
+			name:    "field type",
+			lookup:  func() error { _, err := s.FieldType("User", "logn"); return err },
+			want:    NotFoundError{Kind: "field", Name: "logn", Parent: "User", Suggestions: []string{"login"}},
+			message: `field "logn" not found on type "User"; did you mean "login"?`,
+		},
+		{
+			name:    "no suggestions",
+			lookup:  func() error { _, err := s.EnumValues("Xyzzy"); return err },
+			want:    NotFoundError{Kind: "type", Name: "Xyzzy"},
+			message: `type "Xyzzy" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.lookup()
+			var nf *NotFoundError
+			if !errors.As(err, &nf) {
+				t.Fatalf("Expected *NotFoundError, got %T: %v", err, err)
+			}
+			if !reflect.DeepEqual(*nf, tt.want) {
+				t.Errorf("NotFoundError = %+v, want %+v", *nf, tt.want)
+			}
+			if err.Error() != tt.message {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.message)
+			}
+		})
+	}
+}
+
+func TestSuggestNames(t *testing.T) {
+	candidates := []string{"PullRequest", "PullRequestReview", "PullRequestReviewComment", "Push", "Issue"}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"pullrequestreveiw", []string{"PullRequestReview", "PullRequest"}},
+		{"PullRequestRev", []string{"PullRequest", "PullRequestReview", "PullRequestReviewComment"}},
+		{"Isue", []string{"Issue"}},
+		{"Psh", []string{"Push"}},
+		{"Pu", nil},
+		{"Label", nil},
+	}
+	for _, tt := range tests {
+		if got := suggestNames(tt.name, candidates); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("suggestNames(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	err := &NotFoundError{Kind: "type", Name: "Pul", Suggestions: []string{"Push", "PullRequest", "PullRequestReview"}}
+	want := `type "Pul" not found; did you mean "Push", "PullRequest", or "PullRequestReview"?`
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"repository", "reopsitory", 1},
+		{"issue", "issue", 0},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/apstndb/github-schema-go/schema/introspect"
+)
+
+// SyncStatus is the outcome of refreshing a target
+type SyncStatus string
+
+const (
+	SyncUpdated   SyncStatus = "updated"   // The schema file was rewritten
+	SyncUnchanged SyncStatus = "unchanged" // The server returned the schema the file holds
+	SyncSkipped   SyncStatus = "skipped"   // The target cannot be downloaded, see SyncResult.Error
+	SyncFailed    SyncStatus = "failed"
+)
+
+// SyncResult is the outcome of refreshing one target
+type SyncResult struct {
+	Target    string     `json:"target"`
+	Endpoint  string     `json:"endpoint,omitempty"`
+	Status    SyncStatus `json:"status"`
+	Attempts  int        `json:"attempts"` // Requests made, including retries
+	ElapsedMS int64      `json:"elapsedMs"`
+	Error     string     `json:"error,omitempty"` // Why the target failed or was skipped
+}
+
+// SyncOptions configures SyncTargets
+type SyncOptions struct {
+	// Concurrency bounds the downloads in flight; zero means 4
+	Concurrency int
+	// Options returns the download options of a target, such as its token
+	// source or retry policy, applied after the endpoint and output path. It
+	// may be nil.
+	Options func(t Target) []DownloadOption
+	// OnResult is called as each target finishes, from the goroutine that
+	// refreshed it; calls are serialized. It may be nil.
+	OnResult func(SyncResult)
+	// Handler receives the progress of the sync as a "sync" operation
+	// counting targets, with a progress event per finished target. It may
+	// be nil.
+	Handler EventHandler
+}
+
+// SyncTargets downloads the schema of every target with an endpoint
+// concurrently, rewriting its file only when the schema changed (see
+// WithIfChanged). Targets without an endpoint and SDL files are skipped. The results are in the order of
+// targets; a failed target does not stop the others.
+func SyncTargets(ctx context.Context, targets []Target, opts SyncOptions) []SyncResult {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	results := make([]SyncResult, len(targets))
+	progress := NewProgress("sync", "targets", len(targets), opts.Handler)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i, t := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = syncTarget(ctx, t, opts.Options)
+			progress.Step(1, t.Name+" "+string(results[i].Status))
+			if opts.OnResult != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				opts.OnResult(results[i])
+			}
+		}()
+	}
+	wg.Wait()
+	progress.Finish(nil)
+	return results
+}
+
+func syncTarget(ctx context.Context, t Target, options func(Target) []DownloadOption) SyncResult {
+	result := SyncResult{Target: t.Name, Endpoint: t.Endpoint}
+	switch {
+	case t.Endpoint == "":
+		result.Status, result.Error = SyncSkipped, "no endpoint registered"
+		return result
+	case strings.HasSuffix(t.Path, ".graphql") || strings.HasSuffix(t.Path, ".gql"):
+		result.Status, result.Error = SyncSkipped, "SDL files cannot be downloaded"
+		return result
+	}
+
+	start := time.Now()
+	opts := []DownloadOption{
+		WithEndpoint(t.Endpoint),
+		WithOutputPath(t.Path),
+		WithIfChanged(true),
+	}
+	if options != nil {
+		opts = append(opts, options(t)...)
+	}
+	// Count the attempts around whatever retry policy the options set
+	o := downloadOptions{retry: introspect.DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	onRetry := o.retry.OnRetry
+	result.Attempts = 1
+	o.retry.OnRetry = func(attempt int, delay time.Duration, err error) {
+		result.Attempts = attempt + 1
+		if onRetry != nil {
+			onRetry(attempt, delay, err)
+		}
+	}
+	opts = append(opts, WithRetry(o.retry))
+
+	err := Download(ctx, opts...)
+	result.ElapsedMS = time.Since(start).Milliseconds()
+	switch {
+	case errors.Is(err, ErrUnchanged):
+		result.Status = SyncUnchanged
+	case err != nil:
+		result.Status, result.Error = SyncFailed, err.Error()
+	default:
+		result.Status = SyncUpdated
+	}
+	return result
+}
+
+// WriteSyncTable writes results as a table with a row per target and a
+// summary line
+func WriteSyncTable(w io.Writer, results []SyncResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TARGET\tSTATUS\tATTEMPTS\tTIME\tERROR")
+	counts := make(map[SyncStatus]int)
+	for _, r := range results {
+		counts[r.Status]++
+		elapsed := (time.Duration(r.ElapsedMS) * time.Millisecond).Round(100 * time.Millisecond)
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", r.Target, r.Status, r.Attempts, elapsed, orDash(r.Error))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d targets: %d updated, %d unchanged, %d skipped, %d failed\n",
+		len(results), counts[SyncUpdated], counts[SyncUnchanged], counts[SyncSkipped], counts[SyncFailed])
+	return err
+}
@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSyncTargets(t *testing.T) {
+	isolateAuth(t)
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(SampleData())
+	}))
+	defer ok.Close()
+	var failing atomic.Int32
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		failing.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+
+	dir := t.TempDir()
+	targets := []Target{
+		{Name: "dotcom", Path: filepath.Join(dir, "dotcom.json"), Endpoint: ok.URL},
+		{Name: "ghes", Path: filepath.Join(dir, "ghes.json"), Endpoint: broken.URL},
+		{Name: "local", Path: filepath.Join(dir, "local.json")},
+		{Name: "docs", Path: filepath.Join(dir, "schema.docs.graphql"), Endpoint: ok.URL},
+	}
+	var reported atomic.Int32
+	opts := SyncOptions{
+		Concurrency: 2,
+		Options: func(t Target) []DownloadOption {
+			return []DownloadOption{WithRetry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond})}
+		},
+		OnResult: func(SyncResult) { reported.Add(1) },
+	}
+	var events []Event
+	opts.Handler = func(ev Event) { events = append(events, ev) }
+	results := SyncTargets(context.Background(), targets, opts)
+	opts.Handler = nil
+
+	want := []struct {
+		status   SyncStatus
+		attempts int
+	}{{SyncUpdated, 1}, {SyncFailed, 2}, {SyncSkipped, 0}, {SyncSkipped, 0}}
+	for i, r := range results {
+		if r.Target != targets[i].Name || r.Status != want[i].status || r.Attempts != want[i].attempts {
+			t.Errorf("Result %d = %+v, want %s after %d attempts", i, r, want[i].status, want[i].attempts)
+		}
+	}
+	if failing.Load() != 2 || reported.Load() != 4 {
+		t.Errorf("Broken server got %d requests and %d results were reported", failing.Load(), reported.Load())
+	}
+	// Start, a progress event per target, and done
+	if len(events) != 6 || events[0].Phase != EventStart || events[5].Phase != EventDone || events[5].Current != 4 || events[5].Unit != "targets" {
+		t.Errorf("Unexpected sync events %+v", events)
+	}
+	if s, err := NewWithFileStrict(targets[0].Path); err != nil || s.Metadata() == nil {
+		t.Errorf("Synced file does not load with metadata: %v", err)
+	}
+
+	// The second sync leaves the file alone
+	if results := SyncTargets(context.Background(), targets[:1], opts); results[0].Status != SyncUnchanged {
+		t.Errorf("Second sync = %+v, want unchanged", results[0])
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSyncTable(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"TARGET  STATUS", "dotcom  updated  1", "no endpoint registered", "4 targets: 1 updated, 0 unchanged, 2 skipped, 1 failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Table does not contain %q:\n%s", want, out)
+		}
+	}
+}
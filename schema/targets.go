@@ -0,0 +1,241 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+// Target is a schema registered under a name, such as the github.com schema
+// and the schemas of the GitHub Enterprise Server versions an organization runs
+type Target struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`               // Introspection result or SDL file
+	Endpoint string `json:"endpoint,omitempty"` // GraphQL endpoint the schema is downloaded from
+}
+
+// Registry is the set of registered targets, kept in a YAML file
+type Registry struct {
+	Targets []Target `json:"targets"`
+}
+
+// DefaultRegistryPath returns the registry file in the user configuration directory
+func DefaultRegistryPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user config directory: %w", err)
+	}
+	return filepath.Join(dir, "github-schema", "targets.yaml"), nil
+}
+
+// LoadRegistry reads a registry file. A missing file is an empty registry.
+func LoadRegistry(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Registry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry: %w", err)
+	}
+	var r Registry
+	if err := yamlformat.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse registry %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// Save writes the registry file, creating its directory when needed
+func (r *Registry) Save(path string) error {
+	data, err := yamlformat.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode registry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Target returns the target registered under name, or nil
+func (r *Registry) Target(name string) *Target {
+	for i := range r.Targets {
+		if r.Targets[i].Name == name {
+			return &r.Targets[i]
+		}
+	}
+	return nil
+}
+
+// Add registers t, replacing a target of the same name in place
+func (r *Registry) Add(t Target) error {
+	if t.Name == "" || t.Path == "" {
+		return fmt.Errorf("target requires a name and a path")
+	}
+	if existing := r.Target(t.Name); existing != nil {
+		*existing = t
+		return nil
+	}
+	r.Targets = append(r.Targets, t)
+	return nil
+}
+
+// Remove unregisters the target named name
+func (r *Registry) Remove(name string) error {
+	for i, t := range r.Targets {
+		if t.Name == name {
+			r.Targets = append(r.Targets[:i], r.Targets[i+1:]...)
+			return nil
+		}
+	}
+	names := make([]string, len(r.Targets))
+	for i, t := range r.Targets {
+		names[i] = t.Name
+	}
+	return &NotFoundError{Kind: "target", Name: name, Suggestions: suggestNames(name, names)}
+}
+
+// NamedSchema is a loaded schema with the name of its target
+type NamedSchema struct {
+	Name   string
+	Schema *Schema
+}
+
+// SymbolMatrix records which targets define each symbol matching a pattern
+type SymbolMatrix struct {
+	Pattern string           `json:"pattern"`
+	Targets []string         `json:"targets"`
+	Symbols []SymbolPresence `json:"symbols"`
+}
+
+// SymbolPresence is one row of a SymbolMatrix
+type SymbolPresence struct {
+	Symbol  string          `json:"symbol"` // Type name, or Type.field coordinate
+	Kind    string          `json:"kind"`   // Kind of the type, FIELD, or INPUT_FIELD
+	Present map[string]bool `json:"present"`
+}
+
+// SearchTargets searches several schemas at once. Type names are matched
+// against pattern like Search does; a pattern containing a dot is matched
+// against Type.field coordinates instead, so "PullRequest\.merge" finds the
+// merge fields of PullRequest. Every symbol defined by any of the schemas is
+// listed in name order with the targets it is present on.
+func SearchTargets(pattern string, schemas []NamedSchema) (*SymbolMatrix, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	coordinates := strings.Contains(pattern, ".")
+
+	matrix := &SymbolMatrix{Pattern: pattern, Targets: []string{}, Symbols: []SymbolPresence{}}
+	rows := make(map[string]*SymbolPresence)
+	add := func(target, symbol, kind string) {
+		row, ok := rows[symbol]
+		if !ok {
+			row = &SymbolPresence{Symbol: symbol, Kind: kind, Present: make(map[string]bool)}
+			rows[symbol] = row
+		}
+		row.Present[target] = true
+	}
+	for _, ns := range schemas {
+		matrix.Targets = append(matrix.Targets, ns.Name)
+		for _, t := range ns.Schema.Model().Types {
+			if !coordinates {
+				if re.MatchString(t.Name) {
+					add(ns.Name, t.Name, t.Kind)
+				}
+				continue
+			}
+			for _, f := range t.Fields {
+				if c := t.Name + "." + f.Name; re.MatchString(c) {
+					add(ns.Name, c, "FIELD")
+				}
+			}
+			for _, v := range t.InputFields {
+				if c := t.Name + "." + v.Name; re.MatchString(c) {
+					add(ns.Name, c, "INPUT_FIELD")
+				}
+			}
+		}
+	}
+
+	for _, row := range rows {
+		for _, target := range matrix.Targets {
+			if !row.Present[target] {
+				row.Present[target] = false
+			}
+		}
+		matrix.Symbols = append(matrix.Symbols, *row)
+	}
+	sort.Slice(matrix.Symbols, func(i, j int) bool { return matrix.Symbols[i].Symbol < matrix.Symbols[j].Symbol })
+	return matrix, nil
+}
+
+// Partial returns the symbols missing from at least one target
+func (m *SymbolMatrix) Partial() []SymbolPresence {
+	partial := []SymbolPresence{}
+	for _, row := range m.Symbols {
+		for _, present := range row.Present {
+			if !present {
+				partial = append(partial, row)
+				break
+			}
+		}
+	}
+	return partial
+}
+
+// WriteSymbolMatrix writes a matrix with one column per target, marking the
+// targets a symbol is present on with "yes" and the others with "-"
+func WriteSymbolMatrix(w io.Writer, m *SymbolMatrix, format MatrixFormat) error {
+	switch format {
+	case MatrixTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprint(tw, "SYMBOL\tKIND")
+		for _, target := range m.Targets {
+			fmt.Fprintf(tw, "\t%s", target)
+		}
+		fmt.Fprintln(tw)
+		for _, row := range m.Symbols {
+			fmt.Fprintf(tw, "%s\t%s", row.Symbol, row.Kind)
+			for _, target := range m.Targets {
+				fmt.Fprintf(tw, "\t%s", presence(row.Present[target]))
+			}
+			fmt.Fprintln(tw)
+		}
+		return tw.Flush()
+	case MatrixMarkdown:
+		var b strings.Builder
+		b.WriteString("| Symbol | Kind |")
+		for _, target := range m.Targets {
+			fmt.Fprintf(&b, " %s |", markdownText(target))
+		}
+		b.WriteString("\n| --- | --- |" + strings.Repeat(" --- |", len(m.Targets)) + "\n")
+		for _, row := range m.Symbols {
+			fmt.Fprintf(&b, "| %s | %s |", markdownCode(row.Symbol), row.Kind)
+			for _, target := range m.Targets {
+				fmt.Fprintf(&b, " %s |", presence(row.Present[target]))
+			}
+			b.WriteString("\n")
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	default:
+		return fmt.Errorf("unknown matrix format %q (valid formats: %s, %s)", format, MatrixTable, MatrixMarkdown)
+	}
+}
+
+func presence(present bool) string {
+	if present {
+		return "yes"
+	}
+	return "-"
+}
@@ -0,0 +1,106 @@
+package schema
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "targets.yaml")
+	r, err := LoadRegistry(path)
+	if err != nil || len(r.Targets) != 0 {
+		t.Fatalf("LoadRegistry of a missing file = %+v, %v; want an empty registry", r, err)
+	}
+
+	for _, target := range []Target{
+		{Name: "dotcom", Path: "/schemas/dotcom.json"},
+		{Name: "ghes", Path: "/schemas/old.json"},
+		{Name: "ghes", Path: "/schemas/ghes.json", Endpoint: "https://ghes.example.com/api/graphql"},
+	} {
+		if err := r.Add(target); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if err := r.Add(Target{Name: "nopath"}); err == nil {
+		t.Errorf("Expected an error for a target without a path")
+	}
+	if err := r.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadRegistry(path)
+	if err != nil {
+		t.Fatalf("LoadRegistry failed: %v", err)
+	}
+	want := []Target{
+		{Name: "dotcom", Path: "/schemas/dotcom.json"},
+		{Name: "ghes", Path: "/schemas/ghes.json", Endpoint: "https://ghes.example.com/api/graphql"},
+	}
+	if !reflect.DeepEqual(loaded.Targets, want) {
+		t.Errorf("Targets = %+v, want %+v", loaded.Targets, want)
+	}
+
+	if err := loaded.Remove("dotcom"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	var nf *NotFoundError
+	if err := loaded.Remove("gjes"); !errors.As(err, &nf) || !reflect.DeepEqual(nf.Suggestions, []string{"ghes"}) {
+		t.Errorf("Remove of an unknown target = %v, want a suggestion of \"ghes\"", err)
+	}
+	if len(loaded.Targets) != 1 || loaded.Target("ghes") == nil {
+		t.Errorf("Targets after Remove = %+v", loaded.Targets)
+	}
+}
+
+func TestSearchTargets(t *testing.T) {
+	dotcom := loadRichSchema(t)
+	ghes := modifiedSample(t, func(types map[string]map[string]interface{}) {
+		delete(types, "IssueState")
+		removeMember(types["Issue"], "fields", "title")
+	})
+	schemas := []NamedSchema{{Name: "dotcom", Schema: dotcom}, {Name: "ghes", Schema: ghes}}
+
+	matrix, err := SearchTargets("^Issue(State)?$", schemas)
+	if err != nil {
+		t.Fatalf("SearchTargets failed: %v", err)
+	}
+	want := []SymbolPresence{
+		{Symbol: "Issue", Kind: "OBJECT", Present: map[string]bool{"dotcom": true, "ghes": true}},
+		{Symbol: "IssueState", Kind: "ENUM", Present: map[string]bool{"dotcom": true, "ghes": false}},
+	}
+	if !reflect.DeepEqual(matrix.Symbols, want) {
+		t.Errorf("Symbols = %+v, want %+v", matrix.Symbols, want)
+	}
+	if !reflect.DeepEqual(matrix.Targets, []string{"dotcom", "ghes"}) {
+		t.Errorf("Targets = %q", matrix.Targets)
+	}
+	if partial := matrix.Partial(); len(partial) != 1 || partial[0].Symbol != "IssueState" {
+		t.Errorf("Partial = %+v, want IssueState only", partial)
+	}
+
+	// Patterns with a dot match field coordinates
+	matrix, err = SearchTargets(`^Issue\.title$`, schemas)
+	if err != nil {
+		t.Fatalf("SearchTargets failed: %v", err)
+	}
+	want = []SymbolPresence{{Symbol: "Issue.title", Kind: "FIELD", Present: map[string]bool{"dotcom": true, "ghes": false}}}
+	if !reflect.DeepEqual(matrix.Symbols, want) {
+		t.Errorf("Symbols = %+v, want %+v", matrix.Symbols, want)
+	}
+
+	var b strings.Builder
+	if err := WriteSymbolMatrix(&b, matrix, MatrixMarkdown); err != nil {
+		t.Fatalf("WriteSymbolMatrix failed: %v", err)
+	}
+	wantTable := "| Symbol | Kind | dotcom | ghes |\n| --- | --- | --- | --- |\n| `Issue.title` | FIELD | yes | - |\n"
+	if b.String() != wantTable {
+		t.Errorf("Markdown =\n%s\nwant\n%s", b.String(), wantTable)
+	}
+
+	if _, err := SearchTargets("[", schemas); err == nil {
+		t.Errorf("Expected an error for an invalid pattern")
+	}
+}
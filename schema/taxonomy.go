@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Category is a domain of the GitHub API in the curated taxonomy
+type Category struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	pattern *regexp.Regexp
+}
+
+func category(name, description, pattern string) Category {
+	return Category{Name: name, Description: description, pattern: regexp.MustCompile(pattern)}
+}
+
+// Categories is the curated taxonomy that groups the types of the schema by
+// domain. A type belongs to the first category whose pattern matches its
+// name, so mutation inputs and payloads such as UpdateProjectV2Input join the
+// category of the object they act on, and audit log entries stay together
+// whatever they record. Types matching no category, such as scalars, root
+// types, and shared interfaces, are uncategorized.
+var Categories = []Category{
+	category("audit-log", "Audit log entries of organizations and enterprises", `AuditEntry|^AuditLog`),
+	category("enterprise", "Enterprise accounts, settings, and administration", `Enterprise`),
+	category("security", "Security advisories, vulnerability alerts, and Dependabot", `SecurityAdvisory|SecurityVulnerability|Vulnerab|Dependabot|CodeScanning|SecretScanning|^CVSS|^CWE`),
+	category("sponsors", "GitHub Sponsors listings, tiers, and sponsorships", `Sponsor`),
+	category("discussions", "Discussions, categories, comments, and polls", `Discussion`),
+	category("projects", "Projects and their items, fields, and views", `Project`),
+	category("actions", "Actions workflows, checks, statuses, deployments, and environments", `Workflow|Check(Run|Suite|Step|Annotation|Conclusion|Status)|StatusCheck|^Status(Context|State)?$|Deploy(ment|ed)|Environment`),
+	category("packages", "Packages and their versions and files", `Package`),
+	category("marketplace", "Marketplace listings and categories", `Marketplace`),
+	category("gists", "Gists and their files and comments", `Gist`),
+	category("notifications", "Notifications and subscriptions", `Notification|Subscri`),
+	category("migrations", "Repository migrations and migration sources", `Migrat`),
+	category("pull-requests", "Pull requests, reviews, and merge queues", `PullRequest|Review|Merge(Queue|d|able|StateStatus)|AutoMerge|AutoRebase|AutoSquash|AutomaticBaseChange|(Base|Head)Ref|ConvertToDraft|FileViewed|FileAsViewed|DiffSide`),
+	category("issues", "Issues, milestones, labels, and assignees", `Issue|Milestone|Label|Unlabeled|Demilestoned|Assign|AsDuplicate|RenamedTitle`),
+	category("git", "Commits, refs, trees, blobs, tags, and signatures", `Commit|^Git|^Ref(Update|Connection|Edge|Order|OrderField)?$|[a-z]Refs?(Input|Payload)$|^Tree|^Blob|^Tag$|Signature|^Blame|Submodule|^Push$|^File(Addition|Changes|Deletion)$`),
+	category("repositories", "Repositories, branches, rulesets, releases, and topics", `Repository|^Repo[A-Z]|Branch|Rule|Parameters|ConditionTarget|Bypass|PushAllowance|Release|Topic|Language|License|DeployKey|Star(gazer|rable|Order)|(Add|Remove)Star`),
+	category("organizations", "Organizations, teams, memberships, and access settings", `Organization|^Org[A-Z]|Team|OutsideCollaborator|IpAllowList|VerifiableDomain`),
+	category("users", "Users, followers, profiles, and contributions", `^User|User(Status|List)|Follow|Mannequin|SocialAccount|Contribution`),
+}
+
+// CategoryOf returns the name of the category of a type, or "" when the type
+// is uncategorized
+func CategoryOf(typeName string) string {
+	for _, c := range Categories {
+		if c.pattern.MatchString(typeName) {
+			return c.Name
+		}
+	}
+	return ""
+}
+
+// CategoryNames returns the names of the Categories in taxonomy order
+func CategoryNames() []string {
+	names := make([]string, len(Categories))
+	for i, c := range Categories {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// ListTypesInCategory returns the names of the types in a category, in schema order
+func (s *Schema) ListTypesInCategory(category string) ([]string, error) {
+	if !isCategory(category) {
+		return nil, fmt.Errorf("unknown category %q (valid categories: %s)", category, strings.Join(CategoryNames(), ", "))
+	}
+	names := []string{}
+	for _, t := range s.Model().Types {
+		if CategoryOf(t.Name) == category {
+			names = append(names, t.Name)
+		}
+	}
+	return names, nil
+}
+
+func isCategory(name string) bool {
+	for _, c := range Categories {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// categoryFacets counts the matches of a search result per category.
+// Uncategorized types are counted under "other".
+func categoryFacets(results []interface{}) map[string]interface{} {
+	facets := make(map[string]interface{})
+	for _, r := range results {
+		name, _ := r.(map[string]interface{})["name"].(string)
+		c := CategoryOf(name)
+		if c == "" {
+			c = "other"
+		}
+		n, _ := facets[c].(int)
+		facets[c] = n + 1
+	}
+	return facets
+}
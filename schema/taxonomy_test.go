@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCategoryOf(t *testing.T) {
+	tests := map[string]string{
+		"ProjectV2":                          "projects",
+		"UpdateProjectV2ItemFieldValueInput": "projects",
+		"CheckRun":                           "actions",
+		"WorkflowRun":                        "actions",
+		"RepositoryVulnerabilityAlert":       "security",
+		"SecurityAdvisory":                   "security",
+		"Discussion":                         "discussions",
+		"EnterpriseOwnerInfo":                "enterprise",
+		"SponsorsTier":                       "sponsors",
+		"OrgAddMemberAuditEntry":             "audit-log",
+		"PullRequestReview":                  "pull-requests",
+		"Issue":                              "issues",
+		"Commit":                             "git",
+		"Repository":                         "repositories",
+		"Team":                               "organizations",
+		"User":                               "users",
+		"PageInfo":                           "",
+		"String":                             "",
+		"__Type":                             "",
+	}
+	for name, want := range tests {
+		if got := CategoryOf(name); got != want {
+			t.Errorf("CategoryOf(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestListTypesInCategory(t *testing.T) {
+	s := loadRichSchema(t)
+
+	names, err := s.ListTypesInCategory("issues")
+	if err != nil {
+		t.Fatalf("ListTypesInCategory failed: %v", err)
+	}
+	want := []string{"IssueConnection", "IssueEdge", "Issue", "IssueState", "CreateIssueInput", "IssueMetadataInput", "IssueLocatorInput", "CreateIssuePayload"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+
+	if names, err := s.ListTypesInCategory("sponsors"); err != nil || len(names) != 0 {
+		t.Errorf("Expected no sponsors types in the sample, got %v, %v", names, err)
+	}
+	if _, err := s.ListTypesInCategory("nope"); err == nil {
+		t.Error("Expected an error for an unknown category")
+	}
+}
+
+func TestSearchFacets(t *testing.T) {
+	result, err := loadRichSchema(t).SearchTypes("^(Issue|Repository|PageInfo)$")
+	if err != nil {
+		t.Fatalf("SearchTypes failed: %v", err)
+	}
+	want := map[string]int{"issues": 1, "repositories": 1, "other": 1}
+	if !reflect.DeepEqual(result.Facets, want) {
+		t.Errorf("Expected facets %v, got %v", want, result.Facets)
+	}
+}
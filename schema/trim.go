@@ -0,0 +1,208 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/go-yamlformat"
+)
+
+// Trim reduces a recorded GraphQL response to the fields selected by the
+// operation that produced it, so large recordings become small, stable test
+// fixtures. The operation is validated against the schema: every selected
+// field must exist on its parent type, and selections on leaf fields or
+// missing selections on composite fields are rejected.
+//
+// Response members that are not selected are dropped; selected members that
+// are absent from the response stay absent. Fragments on other concrete types
+// are applied using __typename when it is present. When maxItems is positive,
+// lists are truncated to their first maxItems elements.
+//
+// response may be the full response or the data object itself; a full
+// response keeps its "errors" member and drops "extensions". The result is
+// indented JSON with sorted keys.
+func (s *Schema) Trim(response []byte, operation string, maxItems int) ([]byte, error) {
+	doc, err := graphql.Parse(operation)
+	if err != nil {
+		return nil, err
+	}
+	op, err := doc.Operation("")
+	if err != nil {
+		return nil, err
+	}
+	root := s.RootTypeName(string(op.Operation))
+	if root == "" {
+		return nil, fmt.Errorf("schema does not support %s operations", op.Operation)
+	}
+
+	var decoded interface{}
+	if err := yamlformat.Unmarshal(response, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	top, ok := decoded.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("response must be a JSON object")
+	}
+
+	t := &trimmer{
+		flattener: &flattener{schema: s, fragments: doc.Fragments()},
+		maxItems:  maxItems,
+	}
+	if err := t.check(root, op.SelectionSet, make(map[string]bool)); err != nil {
+		return nil, err
+	}
+	sets := []graphql.SelectionSet{op.SelectionSet}
+
+	var out interface{}
+	if d, ok := top["data"]; ok {
+		full := map[string]interface{}{"data": nil}
+		if data := asObject(d); data != nil {
+			if full["data"], err = t.object(data, root, sets); err != nil {
+				return nil, err
+			}
+		}
+		if errs, ok := top["errors"]; ok {
+			full["errors"] = errs
+		}
+		out = full
+	} else if out, err = t.object(top, root, sets); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := yamlformat.NewEncoderForFormat(&buf, yamlformat.FormatJSON).Encode(out); err != nil {
+		return nil, fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// trimmer walks a response alongside the selections, reusing the fragment
+// expansion of flattener
+type trimmer struct {
+	*flattener
+	maxItems int
+}
+
+// object keeps the selected members of obj, which has type typeName
+func (t *trimmer) object(obj map[string]interface{}, typeName string, sets []graphql.SelectionSet) (map[string]interface{}, error) {
+	typename, _ := obj["__typename"].(string)
+	var fields []*collectedField
+	byKey := make(map[string]*collectedField)
+	for _, set := range sets {
+		if err := t.collect(typeName, set, typename, true, byKey, &fields, make(map[string]bool)); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make(map[string]interface{}, len(fields))
+	for _, c := range fields {
+		value, ok := obj[c.key]
+		if !ok || !c.applies {
+			continue
+		}
+		named, err := t.fieldType(c.parent, c.field.Name, len(c.sets) > 0)
+		if err != nil {
+			return nil, err
+		}
+		if out[c.key], err = t.value(value, named, c.sets); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// value trims a member value: lists element-wise and objects by their selections
+func (t *trimmer) value(v interface{}, named string, sets []graphql.SelectionSet) (interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		if t.maxItems > 0 && len(v) > t.maxItems {
+			v = v[:t.maxItems]
+		}
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			var err error
+			if items[i], err = t.value(item, named, sets); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	case map[string]interface{}:
+		if len(sets) == 0 {
+			// Custom scalars may be JSON objects
+			return v, nil
+		}
+		return t.object(v, named, sets)
+	default:
+		return v, nil
+	}
+}
+
+// check validates a selection set on typeName against the schema, including
+// branches the response does not reach
+func (t *trimmer) check(typeName string, set graphql.SelectionSet, active map[string]bool) error {
+	if t.schema.rawType(typeName) == nil {
+		return fmt.Errorf("unknown type %q", typeName)
+	}
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *graphql.Field:
+			named, err := t.fieldType(typeName, sel.Name, len(sel.SelectionSet) > 0)
+			if err != nil {
+				return err
+			}
+			if len(sel.SelectionSet) > 0 {
+				if err := t.check(named, sel.SelectionSet, active); err != nil {
+					return err
+				}
+			}
+		case *graphql.InlineFragment:
+			condition := typeName
+			if sel.TypeCondition != "" {
+				condition = sel.TypeCondition
+			}
+			if err := t.check(condition, sel.SelectionSet, active); err != nil {
+				return err
+			}
+		case *graphql.FragmentSpread:
+			fragment, ok := t.fragments[sel.Name]
+			if !ok {
+				return fmt.Errorf("unknown fragment %q", sel.Name)
+			}
+			if active[sel.Name] {
+				return fmt.Errorf("fragment %q spreads itself", sel.Name)
+			}
+			active[sel.Name] = true
+			err := t.check(fragment.TypeCondition, fragment.SelectionSet, active)
+			delete(active, sel.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fieldType returns the unwrapped type name of a field, checking that it has
+// a selection exactly when its type is composite
+func (t *trimmer) fieldType(parent, fieldName string, hasSelection bool) (string, error) {
+	if fieldName == "__typename" {
+		return "String", nil
+	}
+	ref := TypeRefFromMap(t.schema.rawField(parent, fieldName)["type"])
+	if ref == nil {
+		return "", fmt.Errorf("field %q not found on type %q", fieldName, parent)
+	}
+	named := ref.NamedType()
+	switch kind, _ := t.schema.rawType(named)["kind"].(string); kind {
+	case "OBJECT", "INTERFACE", "UNION":
+		if !hasSelection {
+			return "", fmt.Errorf("field %q of type %q must have a selection of subfields", fieldName, named)
+		}
+	default:
+		if hasSelection {
+			return "", fmt.Errorf("field %q of type %q must not have a selection", fieldName, named)
+		}
+	}
+	return named, nil
+}
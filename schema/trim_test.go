@@ -0,0 +1,98 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/apstndb/go-yamlformat"
+)
+
+func TestTrim(t *testing.T) {
+	s := loadRichSchema(t)
+
+	tests := []struct {
+		name      string
+		operation string
+		response  string
+		maxItems  int
+		want      string
+	}{
+		{
+			name:      "drops unselected members",
+			operation: `{ repository(owner: "o", name: "n") { name issues(first: 2) { nodes { number } } } }`,
+			response: `{"data":{"repository":{"name":"cli","description":"extra","issues":{"totalCount":9,"nodes":[
+  {"number":1,"title":"one"},{"number":2,"title":"two"}
+]}}},"extensions":{"cost":1}}`,
+			want: `{"data":{"repository":{"name":"cli","issues":{"nodes":[{"number":1},{"number":2}]}}}}`,
+		},
+		{
+			name:      "truncates lists",
+			operation: `{ repository(owner: "o", name: "n") { issues(first: 3) { nodes { number } } } }`,
+			response:  `{"repository":{"issues":{"nodes":[{"number":1},{"number":2},{"number":3}]}}}`,
+			maxItems:  1,
+			want:      `{"repository":{"issues":{"nodes":[{"number":1}]}}}`,
+		},
+		{
+			name: "fragments by typename",
+			operation: `query {
+  search(first: 2, query: "q", type: ISSUE) { nodes { __typename ... on Issue { title } ...U } }
+}
+fragment U on User { login }`,
+			response: `{"data":{"search":{"nodes":[
+  {"__typename":"Issue","title":"Bug","login":"stale","id":"1"},
+  {"__typename":"User","login":"octocat","title":"stale"}
+]}},"errors":[{"message":"partial"}]}`,
+			want: `{"data":{"search":{"nodes":[{"__typename":"Issue","title":"Bug"},{"__typename":"User","login":"octocat"}]}},"errors":[{"message":"partial"}]}`,
+		},
+		{
+			name:      "aliases and nulls",
+			operation: `{ r: repository(owner: "o", name: "n") { n: name owner: issues(first: 1) { totalCount } } }`,
+			response:  `{"data":{"r":{"n":"cli","owner":null,"name":"ignored"}}}`,
+			want:      `{"data":{"r":{"n":"cli","owner":null}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Trim([]byte(tt.response), tt.operation, tt.maxItems)
+			if err != nil {
+				t.Fatalf("Trim failed: %v", err)
+			}
+			var gotValue, wantValue interface{}
+			if err := yamlformat.Unmarshal(got, &gotValue); err != nil {
+				t.Fatalf("Trim returned invalid JSON: %v\n%s", err, got)
+			}
+			if err := yamlformat.Unmarshal([]byte(tt.want), &wantValue); err != nil {
+				t.Fatalf("Invalid expectation: %v", err)
+			}
+			if !reflect.DeepEqual(gotValue, wantValue) {
+				t.Errorf("Trim = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrimValidation(t *testing.T) {
+	s := loadRichSchema(t)
+
+	tests := []struct {
+		name      string
+		operation string
+		wantErr   string
+	}{
+		{"unknown field", `{ viewer { nope } }`, `field "nope" not found on type "User"`},
+		{"missing selection", `{ viewer }`, "must have a selection"},
+		{"selection on leaf", `{ viewer { login { x } } }`, "must not have a selection"},
+		{"unknown field in unused fragment", `{ search(first: 1, query: "q", type: ISSUE) { nodes { ... on Issue { bogus } } } }`, `field "bogus" not found on type "Issue"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Trim([]byte(`{"data":{"viewer":{"login":"x"},"search":{"nodes":[]}}}`), tt.operation, 0)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TypeRef is a reference to a type as it appears in introspection results.
+// Wrapping kinds (NON_NULL and LIST) carry the wrapped type in OfType and have no name.
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+// String returns the GraphQL notation of the type reference, e.g. "[Issue!]!"
+func (r *TypeRef) String() string {
+	return FormatTypeRef(r)
+}
+
+// FormatTypeRef renders a type reference in GraphQL notation, unwrapping
+// NON_NULL and LIST kinds into "String!" or "[Issue!]!". It is the Go
+// equivalent of the formatType definition used by the predefined jq queries
+// and performs a single allocation for the result.
+func FormatTypeRef(ref *TypeRef) string {
+	n := formattedLen(ref)
+	if n == 0 {
+		return ""
+	}
+	return string(AppendTypeRef(make([]byte, 0, n), ref))
+}
+
+// AppendTypeRef appends the GraphQL notation of ref to dst and returns the
+// extended buffer. It does not allocate when dst has sufficient capacity.
+func AppendTypeRef(dst []byte, ref *TypeRef) []byte {
+	// Count the wrappers first so closing tokens can be emitted in reverse order
+	// without recursion or an explicit stack
+	depth := 0
+	inner := ref
+	for inner != nil && (inner.Kind == "NON_NULL" || inner.Kind == "LIST") && depth < maxTypeRefDepth {
+		if inner.Kind == "LIST" {
+			dst = append(dst, '[')
+		}
+		inner = inner.OfType
+		depth++
+	}
+
+	if inner != nil {
+		if inner.Name != "" {
+			dst = append(dst, inner.Name...)
+		} else {
+			dst = append(dst, inner.Kind...)
+		}
+	}
+
+	for i := depth - 1; i >= 0; i-- {
+		if wrapperAt(ref, i).Kind == "LIST" {
+			dst = append(dst, ']')
+		} else {
+			dst = append(dst, '!')
+		}
+	}
+	return dst
+}
+
+// wrapperAt returns the i-th wrapper of ref, counting from the outermost
+func wrapperAt(ref *TypeRef, i int) *TypeRef {
+	for ; i > 0; i-- {
+		ref = ref.OfType
+	}
+	return ref
+}
+
+// formattedLen returns the length of the GraphQL notation of ref
+func formattedLen(ref *TypeRef) int {
+	n := 0
+	depth := 0
+	for ref != nil && (ref.Kind == "NON_NULL" || ref.Kind == "LIST") && depth < maxTypeRefDepth {
+		if ref.Kind == "LIST" {
+			n += 2
+		} else {
+			n++
+		}
+		ref = ref.OfType
+		depth++
+	}
+	if ref != nil {
+		if ref.Name != "" {
+			n += len(ref.Name)
+		} else {
+			n += len(ref.Kind)
+		}
+	}
+	return n
+}
+
+// TypeRefFromMap converts a type reference decoded as generic JSON, such as
+// the "type" member of a field returned by Query, into a TypeRef.
+// It returns nil when v is not a JSON object.
+func TypeRefFromMap(v interface{}) *TypeRef {
+	var root *TypeRef
+	next := &root
+	for depth := 0; depth <= maxTypeRefDepth; depth++ {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			break
+		}
+		ref := &TypeRef{}
+		ref.Kind, _ = m["kind"].(string)
+		ref.Name, _ = m["name"].(string)
+		*next = ref
+		next = &ref.OfType
+		v = m["ofType"]
+	}
+	return root
+}
+
+// UnwrappedType is a type reference split into its named type and the
+// NON_NULL and LIST wrappers around it
+type UnwrappedType struct {
+	Name      string   `json:"name"`
+	Kind      string   `json:"kind"`      // Kind of the named type; empty for references from ParseTypeRef
+	Modifiers []string `json:"modifiers"` // Wrapper kinds, outermost first
+}
+
+// UnwrapTypeRef splits ref into its named type and modifiers, so
+// "[IssueConnection!]!" becomes IssueConnection with the modifiers
+// NON_NULL, LIST, NON_NULL
+func UnwrapTypeRef(ref *TypeRef) UnwrappedType {
+	u := UnwrappedType{Modifiers: []string{}}
+	for depth := 0; ref != nil && (ref.Kind == "NON_NULL" || ref.Kind == "LIST") && depth < maxTypeRefDepth; depth++ {
+		u.Modifiers = append(u.Modifiers, ref.Kind)
+		ref = ref.OfType
+	}
+	if ref != nil {
+		u.Name, u.Kind = ref.Name, ref.Kind
+	}
+	return u
+}
+
+// IsList reports whether the type is wrapped in a list at any level
+func (u UnwrappedType) IsList() bool {
+	for _, m := range u.Modifiers {
+		if m == "LIST" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNonNull reports whether the outermost wrapper is NON_NULL
+func (u UnwrappedType) IsNonNull() bool {
+	return len(u.Modifiers) > 0 && u.Modifiers[0] == "NON_NULL"
+}
+
+// NamedType returns the name of the innermost type of ref, or "" for nil
+func (r *TypeRef) NamedType() string {
+	return UnwrapTypeRef(r).Name
+}
+
+// Unwrap returns the named type inside the NON_NULL and LIST wrappers of ref,
+// so "[Issue!]!" becomes Issue. It returns nil for nil.
+func (r *TypeRef) Unwrap() *TypeRef {
+	for depth := 0; r != nil && (r.Kind == "NON_NULL" || r.Kind == "LIST") && depth < maxTypeRefDepth; depth++ {
+		r = r.OfType
+	}
+	return r
+}
+
+// IsNonNull reports whether the outermost wrapper of ref is NON_NULL
+func (r *TypeRef) IsNonNull() bool {
+	return r != nil && r.Kind == "NON_NULL"
+}
+
+// IsList reports whether ref is wrapped in a list at any level, like
+// UnwrappedType.IsList
+func (r *TypeRef) IsList() bool {
+	for depth := 0; r != nil && depth < maxTypeRefDepth; depth++ {
+		if r.Kind == "LIST" {
+			return true
+		}
+		r = r.OfType
+	}
+	return false
+}
+
+// Nullable returns ref without its outermost NON_NULL wrapper, so "[Issue!]!"
+// becomes "[Issue!]" and other references are returned as they are
+func (r *TypeRef) Nullable() *TypeRef {
+	if r.IsNonNull() {
+		return r.OfType
+	}
+	return r
+}
+
+// Elem returns the element type of a list reference, so "[Issue!]!" becomes
+// "Issue!". It returns nil when ref is not a list, ignoring NON_NULL.
+func (r *TypeRef) Elem() *TypeRef {
+	if r = r.Nullable(); r != nil && r.Kind == "LIST" {
+		return r.OfType
+	}
+	return nil
+}
+
+// ParseTypeRef parses GraphQL type notation such as "[Issue!]!", as found in
+// FieldInfo.Type, back into a TypeRef. The kind of the named type is not
+// known from the notation and is left empty.
+func ParseTypeRef(notation string) (*TypeRef, error) {
+	ref, rest, err := parseTypeRef(notation, 0)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("invalid type notation %q: unexpected %q", notation, rest)
+	}
+	return ref, nil
+}
+
+func parseTypeRef(s string, depth int) (*TypeRef, string, error) {
+	if depth >= maxTypeRefDepth {
+		return nil, "", fmt.Errorf("type notation nested deeper than %d levels", maxTypeRefDepth)
+	}
+
+	var ref *TypeRef
+	if strings.HasPrefix(s, "[") {
+		inner, rest, err := parseTypeRef(s[1:], depth+1)
+		if err != nil {
+			return nil, "", err
+		}
+		if !strings.HasPrefix(rest, "]") {
+			return nil, "", fmt.Errorf("invalid type notation: missing ]")
+		}
+		ref, s = &TypeRef{Kind: "LIST", OfType: inner}, rest[1:]
+	} else {
+		end := strings.IndexAny(s, "[]!")
+		if end < 0 {
+			end = len(s)
+		}
+		if end == 0 {
+			return nil, "", fmt.Errorf("invalid type notation: missing type name")
+		}
+		ref, s = &TypeRef{Name: s[:end]}, s[end:]
+	}
+
+	if strings.HasPrefix(s, "!") {
+		ref, s = &TypeRef{Kind: "NON_NULL", OfType: ref}, s[1:]
+	}
+	return ref, s, nil
+}
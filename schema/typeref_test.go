@@ -0,0 +1,199 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormatTypeRefNotation(t *testing.T) {
+	scalar := func(name string) *TypeRef { return &TypeRef{Kind: "SCALAR", Name: name} }
+	nonNull := func(of *TypeRef) *TypeRef { return &TypeRef{Kind: "NON_NULL", OfType: of} }
+	list := func(of *TypeRef) *TypeRef { return &TypeRef{Kind: "LIST", OfType: of} }
+
+	tests := []struct {
+		name string
+		ref  *TypeRef
+		want string
+	}{
+		{"nil", nil, ""},
+		{"named", scalar("String"), "String"},
+		{"non-null", nonNull(scalar("ID")), "ID!"},
+		{"list", list(scalar("String")), "[String]"},
+		{"non-null list of non-null", nonNull(list(nonNull(&TypeRef{Kind: "OBJECT", Name: "Issue"}))), "[Issue!]!"},
+		{"nested lists", list(list(scalar("Int"))), "[[Int]]"},
+		{"unnamed falls back to kind", &TypeRef{Kind: "OBJECT"}, "OBJECT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatTypeRef(tt.ref); got != tt.want {
+				t.Errorf("FormatTypeRef() = %q, want %q", got, tt.want)
+			}
+			if got := string(AppendTypeRef([]byte("x:"), tt.ref)); got != "x:"+tt.want {
+				t.Errorf("AppendTypeRef() = %q, want %q", got, "x:"+tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTypeRefAllocations(t *testing.T) {
+	ref := &TypeRef{Kind: "NON_NULL", OfType: &TypeRef{Kind: "LIST", OfType: &TypeRef{Kind: "NON_NULL", OfType: &TypeRef{Kind: "OBJECT", Name: "Issue"}}}}
+	buf := make([]byte, 0, 64)
+
+	if n := testing.AllocsPerRun(100, func() { buf = AppendTypeRef(buf[:0], ref) }); n != 0 {
+		t.Errorf("AppendTypeRef allocated %v times, want 0", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { _ = FormatTypeRef(ref) }); n != 1 {
+		t.Errorf("FormatTypeRef allocated %v times, want 1", n)
+	}
+}
+
+func TestFormatTypeRefMatchesJQ(t *testing.T) {
+	s, err := NewWithData(testSchemaData)
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	raw, err := s.Query(`.data.__schema.types[] | select(.name == "PullRequest") | .fields[] | {name, type}`, nil)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	result, err := s.Type("PullRequest")
+	if err != nil {
+		t.Fatalf("Type failed: %v", err)
+	}
+
+	formatted := map[string]string{}
+	for _, f := range result["type"].(map[string]interface{})["fields"].([]interface{}) {
+		field := f.(map[string]interface{})
+		formatted[field["name"].(string)] = field["type"].(string)
+	}
+	for _, f := range raw.([]interface{}) {
+		field := f.(map[string]interface{})
+		name := field["name"].(string)
+		if got := FormatTypeRef(TypeRefFromMap(field["type"])); got != formatted[name] {
+			t.Errorf("Field %s: FormatTypeRef = %q, jq formatType = %q", name, got, formatted[name])
+		}
+	}
+}
+
+func TestTypeRefFromMap(t *testing.T) {
+	if TypeRefFromMap(nil) != nil {
+		t.Error("Expected nil for non-object input")
+	}
+
+	ref := TypeRefFromMap(map[string]interface{}{
+		"kind": "NON_NULL",
+		"name": nil,
+		"ofType": map[string]interface{}{
+			"kind": "SCALAR",
+			"name": "ID",
+		},
+	})
+	if ref.String() != "ID!" {
+		t.Errorf("Expected ID!, got %s", ref)
+	}
+}
+
+func TestUnwrapTypeRef(t *testing.T) {
+	issue := &TypeRef{Kind: "OBJECT", Name: "IssueConnection"}
+	tests := []struct {
+		name     string
+		ref      *TypeRef
+		want     UnwrappedType
+		list     bool
+		nonNull  bool
+		notation string
+	}{
+		{"nil", nil, UnwrappedType{Modifiers: []string{}}, false, false, ""},
+		{"named", issue, UnwrappedType{Name: "IssueConnection", Kind: "OBJECT", Modifiers: []string{}}, false, false, "IssueConnection"},
+		{
+			"non-null list of non-null",
+			&TypeRef{Kind: "NON_NULL", OfType: &TypeRef{Kind: "LIST", OfType: &TypeRef{Kind: "NON_NULL", OfType: issue}}},
+			UnwrappedType{Name: "IssueConnection", Kind: "OBJECT", Modifiers: []string{"NON_NULL", "LIST", "NON_NULL"}},
+			true, true, "[IssueConnection!]!",
+		},
+		{
+			"nullable list",
+			&TypeRef{Kind: "LIST", OfType: &TypeRef{Kind: "NON_NULL", OfType: issue}},
+			UnwrappedType{Name: "IssueConnection", Kind: "OBJECT", Modifiers: []string{"LIST", "NON_NULL"}},
+			true, false, "[IssueConnection!]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnwrapTypeRef(tt.ref)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnwrapTypeRef() = %+v, want %+v", got, tt.want)
+			}
+			if got.IsList() != tt.list || got.IsNonNull() != tt.nonNull {
+				t.Errorf("IsList() = %v, IsNonNull() = %v, want %v, %v", got.IsList(), got.IsNonNull(), tt.list, tt.nonNull)
+			}
+			if tt.ref.NamedType() != tt.want.Name {
+				t.Errorf("NamedType() = %q, want %q", tt.ref.NamedType(), tt.want.Name)
+			}
+			if tt.notation == "" {
+				return
+			}
+
+			// ParseTypeRef inverts FormatTypeRef, apart from the kind of the named type
+			parsed, err := ParseTypeRef(tt.notation)
+			if err != nil {
+				t.Fatalf("ParseTypeRef(%q) failed: %v", tt.notation, err)
+			}
+			if FormatTypeRef(parsed) != tt.notation {
+				t.Errorf("ParseTypeRef(%q) formats as %q", tt.notation, FormatTypeRef(parsed))
+			}
+			if u := UnwrapTypeRef(parsed); u.Name != tt.want.Name || !reflect.DeepEqual(u.Modifiers, tt.want.Modifiers) {
+				t.Errorf("ParseTypeRef(%q) unwraps to %+v", tt.notation, u)
+			}
+		})
+	}
+}
+
+func TestTypeRefNavigation(t *testing.T) {
+	tests := []struct {
+		notation string
+		nonNull  bool
+		list     bool
+		nullable string
+		elem     string
+	}{
+		{"Issue", false, false, "Issue", ""},
+		{"Issue!", true, false, "Issue", ""},
+		{"[Issue!]!", true, true, "[Issue!]", "Issue!"},
+		{"[[Int]]", false, true, "[[Int]]", "[Int]"},
+	}
+	for _, tt := range tests {
+		ref, err := ParseTypeRef(tt.notation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ref.IsNonNull() != tt.nonNull || ref.IsList() != tt.list {
+			t.Errorf("%s: IsNonNull() = %v, IsList() = %v", tt.notation, ref.IsNonNull(), ref.IsList())
+		}
+		if got := ref.Nullable().String(); got != tt.nullable {
+			t.Errorf("%s: Nullable() = %s, want %s", tt.notation, got, tt.nullable)
+		}
+		if got := ref.Elem().String(); got != tt.elem {
+			t.Errorf("%s: Elem() = %s, want %s", tt.notation, got, tt.elem)
+		}
+		if u := ref.Unwrap(); u == nil || u.Name != ref.NamedType() || u.OfType != nil {
+			t.Errorf("%s: Unwrap() = %+v", tt.notation, u)
+		}
+	}
+
+	var nilRef *TypeRef
+	if nilRef.Unwrap() != nil || nilRef.IsNonNull() || nilRef.IsList() || nilRef.Nullable() != nil || nilRef.Elem() != nil {
+		t.Error("Expected nil references to stay nil")
+	}
+}
+
+func TestParseTypeRefErrors(t *testing.T) {
+	for _, notation := range []string{"", "[Issue", "Issue]", "!", "[]", "Issue!!", "[[Int]"} {
+		if _, err := ParseTypeRef(notation); err == nil {
+			t.Errorf("ParseTypeRef(%q) succeeded, want error", notation)
+		}
+	}
+}
@@ -0,0 +1,455 @@
+package schema
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// maxTypeRefDepth bounds ofType nesting; real schemas need at most a handful of levels
+const maxTypeRefDepth = 32
+
+// validTypeKinds lists the __TypeKind values defined by the GraphQL specification
+var validTypeKinds = map[string]bool{
+	"SCALAR":       true,
+	"OBJECT":       true,
+	"INTERFACE":    true,
+	"UNION":        true,
+	"ENUM":         true,
+	"INPUT_OBJECT": true,
+	"LIST":         true,
+	"NON_NULL":     true,
+}
+
+// StructureError reports a structural problem in a schema document
+// together with the JSON path of the offending value
+type StructureError struct {
+	Path    string // JSON path such as $.data.__schema.types[3].fields[0].type
+	Message string
+}
+
+func (e *StructureError) Error() string {
+	return fmt.Sprintf("invalid schema at %s: %s", e.Path, e.Message)
+}
+
+// Is reports whether target is ErrInvalidSchema
+func (e *StructureError) Is(target error) bool {
+	return target == ErrInvalidSchema
+}
+
+// NewWithDataStrict creates a Schema instance like NewWithData, but verifies
+// the structure of the introspection document before returning. Malformed or
+// truncated documents fail with a *StructureError pointing at the offending
+// JSON path instead of surfacing later as confusing query errors.
+func NewWithDataStrict(data []byte) (*Schema, error) {
+	return newWithData(data, true)
+}
+
+// NewWithFileStrict creates a Schema instance from a file using NewWithDataStrict
+func NewWithFileStrict(path string) (*Schema, error) {
+	slog.Debug("Loading schema from file (strict)", "path", path)
+
+	buf, err := readFileBuffer(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+	defer putBuffer(buf)
+
+	return NewWithDataStrict(buf.Bytes())
+}
+
+// checkStructure verifies that v has the shape of a GraphQL introspection result
+func checkStructure(v interface{}) error {
+	root, err := expectObject(v, "$")
+	if err != nil {
+		return err
+	}
+	data, err := expectObject(root["data"], "$.data")
+	if err != nil {
+		return err
+	}
+	schema, err := expectObject(data["__schema"], "$.data.__schema")
+	if err != nil {
+		return err
+	}
+	if err := checkOptionalString(schema["description"], "$.data.__schema.description"); err != nil {
+		return err
+	}
+
+	for _, key := range []string{"queryType", "mutationType", "subscriptionType"} {
+		path := "$.data.__schema." + key
+		if schema[key] == nil {
+			continue
+		}
+		obj, err := expectObject(schema[key], path)
+		if err != nil {
+			return err
+		}
+		if _, err := expectString(obj["name"], path+".name"); err != nil {
+			return err
+		}
+	}
+
+	types, err := expectArray(schema["types"], "$.data.__schema.types")
+	if err != nil {
+		return err
+	}
+	for i, t := range types {
+		if err := checkFullType(t, fmt.Sprintf("$.data.__schema.types[%d]", i)); err != nil {
+			return err
+		}
+	}
+
+	if schema["directives"] != nil {
+		directives, err := expectArray(schema["directives"], "$.data.__schema.directives")
+		if err != nil {
+			return err
+		}
+		for i, d := range directives {
+			if err := checkDirective(d, fmt.Sprintf("$.data.__schema.directives[%d]", i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkFullType(v interface{}, path string) error {
+	t, err := expectObject(v, path)
+	if err != nil {
+		return err
+	}
+	kind, err := expectString(t["kind"], path+".kind")
+	if err != nil {
+		return err
+	}
+	if !validTypeKinds[kind] || kind == "LIST" || kind == "NON_NULL" {
+		return &StructureError{Path: path + ".kind", Message: fmt.Sprintf("unexpected type kind %q", kind)}
+	}
+	name, err := expectString(t["name"], path+".name")
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return &StructureError{Path: path + ".name", Message: "type name must not be empty"}
+	}
+	if err := checkOptionalString(t["description"], path+".description"); err != nil {
+		return err
+	}
+	if err := checkOptionalString(t["specifiedByURL"], path+".specifiedByURL"); err != nil {
+		return err
+	}
+
+	if err := eachOptional(t["fields"], path+".fields", checkField); err != nil {
+		return err
+	}
+	if err := eachOptional(t["inputFields"], path+".inputFields", checkInputValue); err != nil {
+		return err
+	}
+	if err := eachOptional(t["interfaces"], path+".interfaces", checkTypeRef); err != nil {
+		return err
+	}
+	if err := eachOptional(t["possibleTypes"], path+".possibleTypes", checkTypeRef); err != nil {
+		return err
+	}
+	return eachOptional(t["enumValues"], path+".enumValues", checkEnumValue)
+}
+
+func checkField(v interface{}, path string) error {
+	f, err := expectObject(v, path)
+	if err != nil {
+		return err
+	}
+	if _, err := expectString(f["name"], path+".name"); err != nil {
+		return err
+	}
+	if err := checkOptionalString(f["description"], path+".description"); err != nil {
+		return err
+	}
+	if err := checkOptionalString(f["deprecationReason"], path+".deprecationReason"); err != nil {
+		return err
+	}
+	if err := eachOptional(f["args"], path+".args", checkInputValue); err != nil {
+		return err
+	}
+	return checkTypeRef(f["type"], path+".type")
+}
+
+func checkInputValue(v interface{}, path string) error {
+	iv, err := expectObject(v, path)
+	if err != nil {
+		return err
+	}
+	if _, err := expectString(iv["name"], path+".name"); err != nil {
+		return err
+	}
+	if err := checkOptionalString(iv["description"], path+".description"); err != nil {
+		return err
+	}
+	if err := checkOptionalString(iv["defaultValue"], path+".defaultValue"); err != nil {
+		return err
+	}
+	if err := checkOptionalString(iv["deprecationReason"], path+".deprecationReason"); err != nil {
+		return err
+	}
+	return checkTypeRef(iv["type"], path+".type")
+}
+
+func checkEnumValue(v interface{}, path string) error {
+	ev, err := expectObject(v, path)
+	if err != nil {
+		return err
+	}
+	if _, err := expectString(ev["name"], path+".name"); err != nil {
+		return err
+	}
+	if err := checkOptionalString(ev["description"], path+".description"); err != nil {
+		return err
+	}
+	return checkOptionalString(ev["deprecationReason"], path+".deprecationReason")
+}
+
+func checkDirective(v interface{}, path string) error {
+	d, err := expectObject(v, path)
+	if err != nil {
+		return err
+	}
+	if _, err := expectString(d["name"], path+".name"); err != nil {
+		return err
+	}
+	if err := eachOptional(d["locations"], path+".locations", func(v interface{}, path string) error {
+		_, err := expectString(v, path)
+		return err
+	}); err != nil {
+		return err
+	}
+	return eachOptional(d["args"], path+".args", checkInputValue)
+}
+
+// checkTypeRef verifies a (possibly wrapped) type reference without recursion
+func checkTypeRef(v interface{}, path string) error {
+	for depth := 0; ; depth++ {
+		if depth > maxTypeRefDepth {
+			return &StructureError{Path: path, Message: "type reference is nested too deeply"}
+		}
+		ref, err := expectObject(v, path)
+		if err != nil {
+			return err
+		}
+		kind, err := expectString(ref["kind"], path+".kind")
+		if err != nil {
+			return err
+		}
+		if !validTypeKinds[kind] {
+			return &StructureError{Path: path + ".kind", Message: fmt.Sprintf("unexpected type kind %q", kind)}
+		}
+		if kind != "LIST" && kind != "NON_NULL" {
+			_, err := expectString(ref["name"], path+".name")
+			return err
+		}
+		v = ref["ofType"]
+		path += ".ofType"
+	}
+}
+
+// eachOptional calls check for every element of an array that may also be null or absent
+func eachOptional(v interface{}, path string, check func(v interface{}, path string) error) error {
+	if v == nil {
+		return nil
+	}
+	items, err := expectArray(v, path)
+	if err != nil {
+		return err
+	}
+	for i, item := range items {
+		if err := check(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func expectObject(v interface{}, path string) (map[string]interface{}, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, &StructureError{Path: path, Message: "expected object, got " + jsonKind(v)}
+	}
+	return m, nil
+}
+
+func expectArray(v interface{}, path string) ([]interface{}, error) {
+	a, ok := v.([]interface{})
+	if !ok {
+		return nil, &StructureError{Path: path, Message: "expected array, got " + jsonKind(v)}
+	}
+	return a, nil
+}
+
+func expectString(v interface{}, path string) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", &StructureError{Path: path, Message: "expected string, got " + jsonKind(v)}
+	}
+	return s, nil
+}
+
+func checkOptionalString(v interface{}, path string) error {
+	if v == nil {
+		return nil
+	}
+	_, err := expectString(v, path)
+	return err
+}
+
+// jsonKind names the JSON type of a decoded value for error messages
+func jsonKind(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	default:
+		return "number"
+	}
+}
+
+// maxReportedProblems bounds the problems a ValidationError lists in its
+// message; Problems keeps all of them
+const maxReportedProblems = 10
+
+// ValidationError reports every problem NewWithData found in a schema
+// document whose root types, type names, or type references are broken.
+// Each problem is a *StructureError, so errors.As finds the first one.
+type ValidationError struct {
+	Problems []*StructureError
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid schema: %d problems", len(e.Problems))
+	for i, p := range e.Problems {
+		if i == maxReportedProblems {
+			fmt.Fprintf(&b, "\n  and %d more", len(e.Problems)-i)
+			break
+		}
+		fmt.Fprintf(&b, "\n  %s: %s", p.Path, p.Message)
+	}
+	return b.String()
+}
+
+// Is reports whether target is ErrInvalidSchema
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidSchema
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Problems))
+	for i, p := range e.Problems {
+		errs[i] = p
+	}
+	return errs
+}
+
+// checkReferences verifies what every loaded schema needs for its lookups to
+// make sense: a types array of named, distinct types, root operation types
+// that are among them, and field, argument, input field, interface, and
+// possible type references that resolve. Unlike checkStructure it does not
+// insist on members that lookups can do without, such as missing field types.
+func checkReferences(v interface{}) *ValidationError {
+	root, _ := v.(map[string]interface{})
+	data, _ := root["data"].(map[string]interface{})
+	schema, ok := data["__schema"].(map[string]interface{})
+	if !ok {
+		return &ValidationError{Problems: []*StructureError{{Path: "$.data.__schema", Message: "introspection result has no schema object"}}}
+	}
+	types, ok := schema["types"].([]interface{})
+	if !ok {
+		return &ValidationError{Problems: []*StructureError{{Path: "$.data.__schema.types", Message: "schema has no types array"}}}
+	}
+
+	var problems []*StructureError
+	report := func(path, format string, args ...interface{}) {
+		problems = append(problems, &StructureError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	defined := make(map[string]bool, len(types))
+	for i, t := range types {
+		path := fmt.Sprintf("$.data.__schema.types[%d]", i)
+		obj, _ := t.(map[string]interface{})
+		name, _ := obj["name"].(string)
+		switch {
+		case obj == nil:
+			report(path, "expected an object, got %s", jsonKind(t))
+		case name == "":
+			report(path+".name", "type has no name")
+		case defined[name]:
+			report(path+".name", "type %q is defined more than once", name)
+		default:
+			defined[name] = true
+		}
+	}
+
+	for _, key := range []string{"queryType", "mutationType", "subscriptionType"} {
+		obj, _ := schema[key].(map[string]interface{})
+		if name, _ := obj["name"].(string); name != "" && !defined[name] {
+			report("$.data.__schema."+key+".name", "%s root type %q is not defined", strings.TrimSuffix(key, "Type"), name)
+		}
+	}
+
+	// ref checks a reference to a type, unwrapping lists and non-null types
+	var ref func(v interface{}, path string)
+	ref = func(v interface{}, path string) {
+		for depth := 0; depth < maxTypeRefDepth; depth++ {
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return
+			}
+			if name, _ := obj["name"].(string); name != "" {
+				// Built-in scalars are often left out of hand-written and
+				// trimmed documents, and introspection types are never listed
+				if !defined[name] && !builtinScalars[name] && !strings.HasPrefix(name, "__") {
+					report(path+".name", "type %q is not defined", name)
+				}
+				return
+			}
+			v, path = obj["ofType"], path+".ofType"
+		}
+	}
+	eachObject := func(v interface{}, path string, fn func(obj map[string]interface{}, path string)) {
+		items, _ := v.([]interface{})
+		for i, item := range items {
+			if obj, ok := item.(map[string]interface{}); ok {
+				fn(obj, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	}
+	inputValue := func(obj map[string]interface{}, path string) { ref(obj["type"], path+".type") }
+	for i, t := range types {
+		obj, _ := t.(map[string]interface{})
+		path := fmt.Sprintf("$.data.__schema.types[%d]", i)
+		eachObject(obj["fields"], path+".fields", func(f map[string]interface{}, path string) {
+			ref(f["type"], path+".type")
+			eachObject(f["args"], path+".args", inputValue)
+		})
+		eachObject(obj["inputFields"], path+".inputFields", inputValue)
+		eachObject(obj["interfaces"], path+".interfaces", func(i map[string]interface{}, path string) { ref(i, path) })
+		eachObject(obj["possibleTypes"], path+".possibleTypes", func(p map[string]interface{}, path string) { ref(p, path) })
+	}
+	eachObject(schema["directives"], "$.data.__schema.directives", func(d map[string]interface{}, path string) {
+		eachObject(d["args"], path+".args", inputValue)
+	})
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: problems}
+}
@@ -0,0 +1,169 @@
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewWithDataStrict(t *testing.T) {
+	valid := `{"data": {"__schema": {"queryType": {"name": "Query"}, "types": [{"kind": "OBJECT", "name": "Query", "fields": [{"name": "viewer", "args": [], "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "OBJECT", "name": "User"}}}]}, {"kind": "OBJECT", "name": "User", "fields": []}]}}}`
+	if _, err := NewWithDataStrict([]byte(valid)); err != nil {
+		t.Fatalf("Expected valid schema, got: %v", err)
+	}
+
+	// Fields must carry a type; the minimal test fixture omits it for mutations
+	if _, err := NewWithDataStrict(testSchemaData); err == nil {
+		t.Error("Expected error for field without type")
+	}
+
+	tests := []struct {
+		name     string
+		data     string
+		wantPath string
+	}{
+		{
+			name:     "missing data wrapper",
+			data:     `{"schema": {"types": []}}`,
+			wantPath: "$.data",
+		},
+		{
+			name:     "types is not an array",
+			data:     `{"data": {"__schema": {"types": {}}}}`,
+			wantPath: "$.data.__schema.types",
+		},
+		{
+			name:     "type without name",
+			data:     `{"data": {"__schema": {"types": [{"kind": "OBJECT", "name": "Query"}, {"kind": "OBJECT"}]}}}`,
+			wantPath: "$.data.__schema.types[1].name",
+		},
+		{
+			name:     "invalid kind",
+			data:     `{"data": {"__schema": {"types": [{"kind": "TABLE", "name": "Query"}]}}}`,
+			wantPath: "$.data.__schema.types[0].kind",
+		},
+		{
+			name:     "wrapper without ofType",
+			data:     `{"data": {"__schema": {"types": [{"kind": "OBJECT", "name": "Query", "fields": [{"name": "id", "type": {"kind": "NON_NULL"}}]}]}}}`,
+			wantPath: "$.data.__schema.types[0].fields[0].type.ofType",
+		},
+		{
+			name:     "non-string argument name",
+			data:     `{"data": {"__schema": {"types": [{"kind": "OBJECT", "name": "Query", "fields": [{"name": "node", "args": [{"name": 1}], "type": {"kind": "OBJECT", "name": "Node"}}]}]}}}`,
+			wantPath: "$.data.__schema.types[0].fields[0].args[0].name",
+		},
+		{
+			name:     "non-string schema description",
+			data:     `{"data": {"__schema": {"description": 1, "types": []}}}`,
+			wantPath: "$.data.__schema.description",
+		},
+		{
+			name:     "non-string specifiedByURL",
+			data:     `{"data": {"__schema": {"types": [{"kind": "SCALAR", "name": "URI", "specifiedByURL": true}]}}}`,
+			wantPath: "$.data.__schema.types[0].specifiedByURL",
+		},
+		{
+			name:     "non-string input field deprecation reason",
+			data:     `{"data": {"__schema": {"types": [{"kind": "INPUT_OBJECT", "name": "In", "inputFields": [{"name": "old", "type": {"kind": "SCALAR", "name": "Int"}, "isDeprecated": true, "deprecationReason": []}]}]}}}`,
+			wantPath: "$.data.__schema.types[0].inputFields[0].deprecationReason",
+		},
+		{
+			name:     "directive locations",
+			data:     `{"data": {"__schema": {"types": [], "directives": [{"name": "skip", "locations": "FIELD"}]}}}`,
+			wantPath: "$.data.__schema.directives[0].locations",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWithDataStrict([]byte(tt.data))
+			var structErr *StructureError
+			if !errors.As(err, &structErr) {
+				t.Fatalf("Expected *StructureError, got %v", err)
+			}
+			if structErr.Path != tt.wantPath {
+				t.Errorf("Expected path %s, got %s (%v)", tt.wantPath, structErr.Path, err)
+			}
+		})
+	}
+}
+
+func TestCheckTypeRefDepth(t *testing.T) {
+	var ref interface{} = map[string]interface{}{"kind": "SCALAR", "name": "String"}
+	for i := 0; i < maxTypeRefDepth+2; i++ {
+		ref = map[string]interface{}{"kind": "LIST", "ofType": ref}
+	}
+
+	var structErr *StructureError
+	if err := checkTypeRef(ref, "$"); !errors.As(err, &structErr) {
+		t.Errorf("Expected depth error, got %v", err)
+	}
+}
+
+func FuzzNewWithDataStrict(f *testing.F) {
+	f.Add(testSchemaData)
+	f.Add(testSchemaData[:len(testSchemaData)/2])
+	f.Add([]byte(`{"data": {"__schema": {"types": [{"kind": "OBJECT", "name": "Mutation", "fields": []}]}}}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s, err := NewWithDataStrict(data)
+		if err != nil {
+			return
+		}
+		// Documents accepted by the strict loader must be safe to query
+		s.Type("PullRequest")
+		s.Mutation("createIssue")
+		s.Search("Issue")
+	})
+}
+
+func TestNewWithDataReferences(t *testing.T) {
+	data := `{"data": {"__schema": {"queryType": {"name": "Query"}, "mutationType": {"name": "Mutation"}, "types": [
+		{"kind": "OBJECT", "name": "Query", "fields": [
+			{"name": "viewer", "args": [{"name": "as", "type": {"kind": "INPUT_OBJECT", "name": "ViewerInput"}}], "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "OBJECT", "name": "User"}}},
+			{"name": "id", "args": [], "type": {"kind": "SCALAR", "name": "ID"}}
+		], "interfaces": [{"kind": "INTERFACE", "name": "Node"}]},
+		{"kind": "OBJECT", "name": "Query", "fields": []},
+		{"kind": "SCALAR", "name": ""}
+	]}}}`
+	_, err := NewWithData([]byte(data))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+	want := []StructureError{
+		{Path: "$.data.__schema.types[1].name", Message: `type "Query" is defined more than once`},
+		{Path: "$.data.__schema.types[2].name", Message: "type has no name"},
+		{Path: "$.data.__schema.mutationType.name", Message: `mutation root type "Mutation" is not defined`},
+		{Path: "$.data.__schema.types[0].fields[0].type.ofType.name", Message: `type "User" is not defined`},
+		{Path: "$.data.__schema.types[0].fields[0].args[0].type.name", Message: `type "ViewerInput" is not defined`},
+		{Path: "$.data.__schema.types[0].interfaces[0].name", Message: `type "Node" is not defined`},
+	}
+	if len(validationErr.Problems) != len(want) {
+		t.Fatalf("Problems:\n%v\nwant %d", err, len(want))
+	}
+	for i, p := range validationErr.Problems {
+		if *p != want[i] {
+			t.Errorf("Problem %d = %+v, want %+v", i, *p, want[i])
+		}
+	}
+	if !errors.Is(err, ErrInvalidSchema) {
+		t.Error("Expected the error to match ErrInvalidSchema")
+	}
+	var structErr *StructureError
+	if !errors.As(err, &structErr) || structErr.Path != want[0].Path {
+		t.Errorf("Expected errors.As to find the first problem, got %v", structErr)
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "invalid schema: 6 problems\n  $.data.__schema.types[1].name: ") {
+		t.Errorf("Unexpected message:\n%s", msg)
+	}
+
+	for name, data := range map[string]string{
+		"no schema": `{"data": {}}`,
+		"no types":  `{"data": {"__schema": {}}}`,
+	} {
+		if _, err := NewWithData([]byte(data)); !errors.As(err, &validationErr) {
+			t.Errorf("%s: expected *ValidationError, got %v", name, err)
+		}
+	}
+}
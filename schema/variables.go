@@ -0,0 +1,247 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/go-yamlformat"
+)
+
+// VariableError is a problem ValidateVariables found in a variables payload,
+// at the path of the offending value such as "$input.labelIds[1]"
+type VariableError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e VariableError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidateVariables checks a variables payload, a JSON object, against the
+// variable definitions of the only operation in doc, the way GitHub coerces
+// variables before executing: every value must fit its declared type,
+// including the fields of nested input objects, enum values, and list items,
+// and variables of non-null types without defaults must be given. A single
+// value is accepted for a list type. Variables the operation does not define
+// are reported as well. Custom scalars such as DateTime accept any value.
+//
+// The problems are empty for a valid payload. An empty payload is treated as
+// an empty object. Documents that cannot be parsed, with several operations,
+// or with a payload that is not a JSON object fail with an error instead.
+func (s *Schema) ValidateVariables(doc string, variablesJSON []byte) ([]VariableError, error) {
+	parsed, err := graphql.Parse(doc)
+	if err != nil {
+		return nil, err
+	}
+	op, err := parsed.Operation("")
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded interface{}
+	if strings.TrimSpace(string(variablesJSON)) != "" {
+		if err := yamlformat.Unmarshal(variablesJSON, &decoded); err != nil {
+			return nil, fmt.Errorf("failed to parse variables: %w", err)
+		}
+	}
+	variables, ok := decoded.(map[string]interface{})
+	if decoded != nil && !ok {
+		return nil, fmt.Errorf("variables must be a JSON object")
+	}
+
+	c := &variableChecker{model: s.Model()}
+	defined := make(map[string]bool)
+	for _, d := range op.VariableDefinitions {
+		defined[d.Name] = true
+		path := "$" + d.Name
+		named := c.model.Type(d.Type.NamedType())
+		if named == nil {
+			c.errorf(path, "unknown type %q", d.Type.NamedType())
+			continue
+		}
+		value, given := variables[d.Name]
+		switch {
+		case !given && d.Type.NonNull && d.DefaultValue == nil:
+			c.errorf(path, "variable of required type %s was not provided", d.Type)
+		case given:
+			c.value(value, typeRefOf(d.Type, named.Kind), path)
+		}
+	}
+
+	var undefined []string
+	for name := range variables {
+		if !defined[name] {
+			undefined = append(undefined, name)
+		}
+	}
+	sort.Strings(undefined)
+	for _, name := range undefined {
+		c.errorf("$"+name, "variable is not defined by %s", operationLabel(op))
+	}
+	return c.errors, nil
+}
+
+// variableChecker accumulates the problems of one variables payload
+type variableChecker struct {
+	model  *Model
+	errors []VariableError
+}
+
+func (c *variableChecker) errorf(path, format string, args ...interface{}) {
+	c.errors = append(c.errors, VariableError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// value checks a decoded JSON value given where typ is expected
+func (c *variableChecker) value(v interface{}, typ *TypeRef, path string) {
+	switch {
+	case typ.Kind == "NON_NULL":
+		if v == nil {
+			c.errorf(path, "expected value of type %s, found null", typ)
+			return
+		}
+		if typ.OfType.Kind != "LIST" {
+			c.named(v, typ.OfType, typ, path)
+			return
+		}
+		c.value(v, typ.OfType, path)
+		return
+	case v == nil:
+		return
+	case typ.Kind == "LIST":
+		items, ok := v.([]interface{})
+		if !ok {
+			// A single value is coerced into a list of one item
+			c.value(v, typ.OfType, path)
+			return
+		}
+		for i, item := range items {
+			c.value(item, typ.OfType, fmt.Sprintf("%s[%d]", path, i))
+		}
+		return
+	}
+	c.named(v, typ, typ, path)
+}
+
+// named checks a non-null value given where the named type typ is expected;
+// declared is the type reported in messages, typ possibly wrapped as non-null
+func (c *variableChecker) named(v interface{}, typ, declared *TypeRef, path string) {
+	t := c.model.Type(typ.Name)
+	if t == nil {
+		return
+	}
+	switch t.Kind {
+	case "SCALAR":
+		if !scalarAcceptsJSON(t.Name, v) {
+			c.errorf(path, "expected value of type %s, found %s", declared, jsonText(v))
+		}
+	case "ENUM":
+		name, _ := v.(string)
+		if !slices.ContainsFunc(t.EnumValues, func(e *EnumValue) bool { return e.Name == name }) {
+			c.errorf(path, "expected value of type %s, found %s", declared, jsonText(v))
+		}
+	case "INPUT_OBJECT":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			c.errorf(path, "expected value of type %s, found %s", declared, jsonText(v))
+			return
+		}
+		c.inputObject(t, obj, path)
+	}
+}
+
+func (c *variableChecker) inputObject(t *Type, obj map[string]interface{}, path string) {
+	for _, def := range t.InputFields {
+		v, given := obj[def.Name]
+		switch {
+		case given:
+			c.value(v, def.Type, path+"."+def.Name)
+		case def.Required():
+			c.errorf(path, "input type %q requires field %q of type %s", t.Name, def.Name, def.Type)
+		}
+	}
+
+	var unknown []string
+	for name := range obj {
+		if t.InputField(name) == nil {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		c.errorf(path+"."+name, "field is not defined by input type %q", t.Name)
+	}
+
+	if t.OneOf {
+		nonNull := 0
+		for _, v := range obj {
+			if v != nil {
+				nonNull++
+			}
+		}
+		if len(obj) != 1 || nonNull != 1 {
+			c.errorf(path, "exactly one field of oneOf input type %q must be given and non-null", t.Name)
+		}
+	}
+}
+
+// scalarAcceptsJSON reports whether a decoded JSON value is valid for a
+// scalar. Custom scalars define their own formats, so they accept any value.
+func scalarAcceptsJSON(name string, v interface{}) bool {
+	switch name {
+	case "Int":
+		n, ok := jsonInteger(v)
+		return ok && n >= math.MinInt32 && n <= math.MaxInt32
+	case "Float":
+		_, isInt := jsonInteger(v)
+		_, isFloat := v.(float64)
+		return isInt || isFloat
+	case "String":
+		_, ok := v.(string)
+		return ok
+	case "Boolean":
+		_, ok := v.(bool)
+		return ok
+	case "ID":
+		_, isString := v.(string)
+		_, isInt := jsonInteger(v)
+		return isString || isInt
+	default:
+		return true
+	}
+}
+
+// jsonInteger returns the value of a decoded JSON number without a
+// fractional part
+func jsonInteger(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint64:
+		if n > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(n), true
+	case float64:
+		if n != math.Trunc(n) || math.Abs(n) > 1<<53 {
+			return 0, false
+		}
+		return int64(n), true
+	}
+	return 0, false
+}
+
+// jsonText renders a decoded value as compact JSON for messages
+func jsonText(v interface{}) string {
+	data, err := yamlformat.MarshalJSON(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(string(data))
+}
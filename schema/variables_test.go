@@ -0,0 +1,94 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateVariables(t *testing.T) {
+	s := loadRichSchema(t)
+
+	const doc = `query Issues($owner: String!, $name: String = "cli", $first: Int, $states: [IssueState!], $search: SearchType!) {
+  repository(owner: $owner, name: $name) { issues(first: $first, states: $states) { totalCount } }
+  search(query: "q", type: $search) { issueCount }
+}`
+	const mutation = `mutation Create($input: CreateIssueInput!) {
+  createIssue(input: $input) { clientMutationId }
+}`
+
+	tests := []struct {
+		name      string
+		doc       string
+		variables string
+		want      []string // "path: message"
+	}{
+		{
+			name:      "valid",
+			doc:       doc,
+			variables: `{"owner": "cli", "first": 10, "states": "OPEN", "search": "ISSUE"}`,
+		},
+		{
+			name:      "scalars and enums",
+			doc:       doc,
+			variables: `{"owner": 1, "name": null, "first": 3000000000, "states": ["OPEN", "MERGED", null], "search": null, "extra": true}`,
+			want: []string{
+				`$owner: expected value of type String!, found 1`,
+				`$first: expected value of type Int, found 3000000000`,
+				`$states[1]: expected value of type IssueState!, found "MERGED"`,
+				`$states[2]: expected value of type IssueState!, found null`,
+				`$search: expected value of type SearchType!, found null`,
+				`$extra: variable is not defined by query "Issues"`,
+			},
+		},
+		{
+			name:      "missing required",
+			doc:       doc,
+			variables: ``,
+			want: []string{
+				`$owner: variable of required type String! was not provided`,
+				`$search: variable of required type SearchType! was not provided`,
+			},
+		},
+		{
+			name:      "valid input object",
+			doc:       mutation,
+			variables: `{"input": {"repositoryId": "R_1", "title": "t", "labelIds": "L_1", "metadata": {"priority": 1, "parent": {"number": 2}}}}`,
+		},
+		{
+			name:      "input objects",
+			doc:       mutation,
+			variables: `{"input": {"title": 1.5, "color": "red", "labelIds": [1, true], "metadata": {"state": "LOCKED", "parent": {"id": "I", "number": 2}, "related": [{"priority": "high"}]}}}`,
+			want: []string{
+				`$input: input type "CreateIssueInput" requires field "repositoryId" of type ID!`,
+				`$input.title: expected value of type String!, found 1.5`,
+				`$input.labelIds[1]: expected value of type ID!, found true`,
+				`$input.metadata.related[0].priority: expected value of type Int, found "high"`,
+				`$input.metadata.parent: exactly one field of oneOf input type "IssueLocatorInput" must be given and non-null`,
+				`$input.color: field is not defined by input type "CreateIssueInput"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := s.ValidateVariables(tt.doc, []byte(tt.variables))
+			if err != nil {
+				t.Fatalf("ValidateVariables failed: %v", err)
+			}
+			got := make([]string, len(errs))
+			for i, e := range errs {
+				got[i] = e.Error()
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(tt.want, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+
+	if _, err := s.ValidateVariables(doc, []byte(`[1]`)); err == nil {
+		t.Error("Expected error for a payload that is not an object")
+	}
+	if _, err := s.ValidateVariables(doc+"\n"+mutation, nil); err == nil {
+		t.Error("Expected error for a document with several operations")
+	}
+}
@@ -0,0 +1,22 @@
+// Package typename decodes interface and union selections of GitHub GraphQL
+// responses into concrete Go types chosen by the __typename field.
+//
+// Decoding polymorphic results by hand means switching on __typename and
+// re-decoding the same bytes, and it silently breaks when a selection forgets
+// __typename or a type is misspelled. A Registry checks every registration and
+// every decoded __typename against the schema instead:
+//
+//	r := typename.NewRegistry(s)
+//	r.MustRegister("Issue", Issue{})
+//	r.MustRegister("PullRequest", PullRequest{})
+//
+//	// raw is the json.RawMessage of repository.issueOrPullRequest
+//	v, err := r.Decode("IssueOrPullRequest", raw)
+//	switch v := v.(type) {
+//	case *Issue:
+//	case *PullRequest:
+//	}
+//
+// Register and MustRegister take the GraphQL type name and a prototype value,
+// so generated code can register all of its types from a single function.
+package typename
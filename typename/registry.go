@@ -0,0 +1,130 @@
+package typename
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/apstndb/github-schema-go/schema"
+	"github.com/apstndb/go-yamlformat"
+)
+
+// Registry maps GraphQL object types to the Go types their selections decode into.
+// It is safe for concurrent use.
+type Registry struct {
+	schema *schema.Schema
+
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+}
+
+// NewRegistry creates an empty Registry that validates against the given schema
+func NewRegistry(s *schema.Schema) *Registry {
+	return &Registry{
+		schema: s,
+		types:  make(map[string]reflect.Type),
+	}
+}
+
+// Register associates an object type with the Go type of prototype, which may
+// be a struct value or a pointer to one. Registering a name that is not an
+// object type in the schema is an error, as is registering it twice with
+// different Go types.
+func (r *Registry) Register(typeName string, prototype interface{}) error {
+	t := reflect.TypeOf(prototype)
+	if t == nil {
+		return fmt.Errorf("cannot register nil prototype for %q", typeName)
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	info, err := r.schema.LookupType(typeName)
+	if err != nil {
+		return fmt.Errorf("failed to look up type %q: %w", typeName, err)
+	}
+	if info.Kind != "OBJECT" {
+		return fmt.Errorf("type %q is not an object type (kind: %s)", typeName, info.Kind)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.types[typeName]; ok && existing != t {
+		return fmt.Errorf("type %q is already registered as %s", typeName, existing)
+	}
+	r.types[typeName] = t
+	return nil
+}
+
+// MustRegister is like Register but panics on error. It is intended for
+// generated registration code that runs during package initialization.
+func (r *Registry) MustRegister(typeName string, prototype interface{}) {
+	if err := r.Register(typeName, prototype); err != nil {
+		panic(err)
+	}
+}
+
+// Decode decodes a single JSON object selected through abstractType (an
+// interface or union) into the Go type registered for its __typename and
+// returns a pointer to the new value. The selection must include __typename.
+// JSON null decodes to nil.
+func (r *Registry) Decode(abstractType string, data []byte) (interface{}, error) {
+	if isNull(data) {
+		return nil, nil
+	}
+	var probe struct {
+		Typename *string `json:"__typename"`
+	}
+	if err := yamlformat.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to decode response object: %w", err)
+	}
+	if probe.Typename == nil {
+		return nil, fmt.Errorf("response object for %s has no __typename; add __typename to the selection", abstractType)
+	}
+	typeName := *probe.Typename
+
+	abstract := &schema.TypeRef{Name: abstractType}
+	concrete := &schema.TypeRef{Name: typeName}
+	if !r.schema.TypesCompatible(abstract, concrete, schema.OutputPosition) {
+		return nil, fmt.Errorf("type %q is not a possible type of %q", typeName, abstractType)
+	}
+
+	r.mu.RLock()
+	t, ok := r.types[typeName]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no Go type registered for %q", typeName)
+	}
+
+	v := reflect.New(t)
+	if err := yamlformat.Unmarshal(data, v.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", typeName, err)
+	}
+	return v.Interface(), nil
+}
+
+// DecodeList decodes a JSON array of objects selected through abstractType,
+// such as the nodes of a connection, using Decode for every element
+func (r *Registry) DecodeList(abstractType string, data []byte) ([]interface{}, error) {
+	var items []interface{}
+	if err := yamlformat.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to decode response list: %w", err)
+	}
+
+	values := make([]interface{}, len(items))
+	for i, item := range items {
+		raw, err := yamlformat.MarshalJSON(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode list element %d: %w", i, err)
+		}
+		if values[i], err = r.Decode(abstractType, raw); err != nil {
+			return nil, fmt.Errorf("list element %d: %w", i, err)
+		}
+	}
+	return values, nil
+}
+
+func isNull(data []byte) bool {
+	var v interface{}
+	return yamlformat.Unmarshal(data, &v) == nil && v == nil
+}
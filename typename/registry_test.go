@@ -0,0 +1,134 @@
+package typename
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+type issue struct {
+	Typename string `json:"__typename"`
+	Number   int    `json:"number"`
+	Title    string `json:"title"`
+}
+
+type pullRequest struct {
+	Typename string `json:"__typename"`
+	Number   int    `json:"number"`
+	Merged   bool   `json:"merged"`
+}
+
+func newTestRegistry(t *testing.T) *Registry {
+	t.Helper()
+	s, err := schema.NewSample()
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	r := NewRegistry(s)
+	if err := r.Register("Issue", issue{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := r.Register("PullRequest", &pullRequest{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	return r
+}
+
+func TestDecode(t *testing.T) {
+	r := newTestRegistry(t)
+
+	v, err := r.Decode("IssueOrPullRequest", []byte(`{"__typename":"Issue","number":1,"title":"Bug"}`))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	got, ok := v.(*issue)
+	if !ok {
+		t.Fatalf("Expected *issue, got %T", v)
+	}
+	if got.Number != 1 || got.Title != "Bug" {
+		t.Errorf("Unexpected issue: %+v", got)
+	}
+
+	v, err = r.Decode("Node", []byte(`{"__typename":"PullRequest","number":2,"merged":true}`))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if pr, ok := v.(*pullRequest); !ok || !pr.Merged {
+		t.Errorf("Expected merged *pullRequest, got %#v", v)
+	}
+
+	if v, err := r.Decode("IssueOrPullRequest", []byte(`null`)); err != nil || v != nil {
+		t.Errorf("Expected nil for null, got %v (err: %v)", v, err)
+	}
+}
+
+func TestDecodeList(t *testing.T) {
+	r := newTestRegistry(t)
+
+	values, err := r.DecodeList("SearchResultItem", []byte(`[
+		{"__typename":"Issue","number":1,"title":"Bug"},
+		{"__typename":"PullRequest","number":2,"merged":false}
+	]`))
+	if err != nil {
+		t.Fatalf("DecodeList failed: %v", err)
+	}
+	if len(values) != 2 {
+		t.Fatalf("Expected 2 values, got %d", len(values))
+	}
+	if _, ok := values[0].(*issue); !ok {
+		t.Errorf("Expected *issue, got %T", values[0])
+	}
+	if _, ok := values[1].(*pullRequest); !ok {
+		t.Errorf("Expected *pullRequest, got %T", values[1])
+	}
+
+	if _, err := r.DecodeList("SearchResultItem", []byte(`[{"__typename":"User","login":"octocat"}]`)); err == nil || !strings.Contains(err.Error(), "element 0") {
+		t.Errorf("Expected error for unregistered element, got %v", err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	r := newTestRegistry(t)
+
+	tests := []struct {
+		name     string
+		abstract string
+		data     string
+		want     string
+	}{
+		{"missing typename", "IssueOrPullRequest", `{"number":1}`, "no __typename"},
+		{"not a possible type", "IssueOrPullRequest", `{"__typename":"Repository"}`, "not a possible type"},
+		{"unregistered", "SearchResultItem", `{"__typename":"User"}`, "no Go type registered"},
+		{"invalid json", "IssueOrPullRequest", `{`, "failed to decode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.Decode(tt.abstract, []byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRegisterErrors(t *testing.T) {
+	r := newTestRegistry(t)
+
+	if err := r.Register("Node", issue{}); err == nil {
+		t.Error("Expected error registering an interface")
+	}
+	if err := r.Register("Robot", issue{}); err == nil {
+		t.Error("Expected error registering an unknown type")
+	}
+	if err := r.Register("Issue", pullRequest{}); err == nil {
+		t.Error("Expected error registering a type twice with different Go types")
+	}
+	if err := r.Register("Issue", &issue{}); err != nil {
+		t.Errorf("Re-registering the same Go type should succeed: %v", err)
+	}
+	if err := r.Register("User", nil); err == nil {
+		t.Error("Expected error registering nil")
+	}
+}
@@ -0,0 +1,175 @@
+package usage
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+// Report summarizes the schema usage of the analyzed documents
+type Report struct {
+	Operations       int         `json:"operations"`
+	Fragments        int         `json:"fragments"`
+	Coverage         float64     `json:"coverage"`         // Percentage of the fields of used types that are selected
+	DeprecatedUsages int         `json:"deprecatedUsages"` // Selections of deprecated fields
+	Types            []TypeUsage `json:"types"`            // Sorted by name
+}
+
+// TypeUsage is the usage of a single type. Coverage is the percentage of the
+// type's fields that are selected; it is nil for types without fields.
+type TypeUsage struct {
+	Name         string       `json:"name"`
+	Kind         string       `json:"kind"`
+	References   int          `json:"references"` // Selections, variables, and type conditions resolving to the type
+	Fields       int          `json:"fields"`
+	Coverage     *float64     `json:"coverage,omitempty"`
+	UsedFields   []FieldUsage `json:"usedFields,omitempty"`   // In schema order
+	UnusedFields []string     `json:"unusedFields,omitempty"` // In schema order
+}
+
+// FieldUsage is the number of times a field is selected
+type FieldUsage struct {
+	Name       string `json:"name"`
+	Count      int    `json:"count"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+}
+
+// Analyzer accumulates usage over any number of documents
+type Analyzer struct {
+	schema *schema.Schema
+
+	operations int
+	fragments  int
+	references map[string]int
+	fields     map[string]map[string]int
+}
+
+// NewAnalyzer creates an Analyzer for the given schema
+func NewAnalyzer(s *schema.Schema) *Analyzer {
+	return &Analyzer{
+		schema:     s,
+		references: make(map[string]int),
+		fields:     make(map[string]map[string]int),
+	}
+}
+
+// Add records the usage of every operation and fragment in doc. Selections
+// are validated against the schema; fragment spreads are not followed,
+// because the spread fragment is counted where it is defined.
+func (a *Analyzer) Add(doc *graphql.Document) error {
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *graphql.OperationDefinition:
+			root := a.schema.RootTypeName(string(def.Operation))
+			if root == "" {
+				return fmt.Errorf("schema does not support %s operations", def.Operation)
+			}
+			for _, v := range def.VariableDefinitions {
+				a.references[v.Type.NamedType()]++
+			}
+			a.operations++
+			a.references[root]++
+			if err := a.selectionSet(root, def.SelectionSet); err != nil {
+				return err
+			}
+		case *graphql.FragmentDefinition:
+			a.fragments++
+			a.references[def.TypeCondition]++
+			if err := a.selectionSet(def.TypeCondition, def.SelectionSet); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Analyzer) selectionSet(typeName string, set graphql.SelectionSet) error {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *graphql.Field:
+			if strings.HasPrefix(sel.Name, "__") {
+				continue
+			}
+			ref, err := a.schema.FieldType(typeName, sel.Name)
+			if err != nil {
+				return fmt.Errorf("field %q not found on type %q at %s", sel.Name, typeName, sel.Pos)
+			}
+			if a.fields[typeName] == nil {
+				a.fields[typeName] = make(map[string]int)
+			}
+			a.fields[typeName][sel.Name]++
+
+			named := ref.NamedType()
+			a.references[named]++
+			if len(sel.SelectionSet) > 0 {
+				if err := a.selectionSet(named, sel.SelectionSet); err != nil {
+					return err
+				}
+			}
+		case *graphql.InlineFragment:
+			condition := typeName
+			if sel.TypeCondition != "" {
+				condition = sel.TypeCondition
+				a.references[condition]++
+			}
+			if err := a.selectionSet(condition, sel.SelectionSet); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Report builds the usage report for the documents added so far
+func (a *Analyzer) Report() (*Report, error) {
+	names := make([]string, 0, len(a.references))
+	for name := range a.references {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	report := &Report{Operations: a.operations, Fragments: a.fragments, Types: []TypeUsage{}}
+	model := a.schema.Model()
+	totalFields, usedFields := 0, 0
+	for _, name := range names {
+		info, err := a.schema.LookupType(name)
+		if err != nil {
+			return nil, fmt.Errorf("unknown type %q: %w", name, err)
+		}
+
+		t := TypeUsage{Name: name, Kind: info.Kind, References: a.references[name], Fields: len(info.Fields)}
+		counts := a.fields[name]
+		for _, f := range info.Fields {
+			if n := counts[f.Name]; n > 0 {
+				deprecated := model.Type(name).Field(f.Name).IsDeprecated
+				if deprecated {
+					report.DeprecatedUsages += n
+				}
+				t.UsedFields = append(t.UsedFields, FieldUsage{Name: f.Name, Count: n, Deprecated: deprecated})
+			} else {
+				t.UnusedFields = append(t.UnusedFields, f.Name)
+			}
+		}
+		if t.Fields > 0 {
+			coverage := percent(len(t.UsedFields), t.Fields)
+			t.Coverage = &coverage
+			totalFields += t.Fields
+			usedFields += len(t.UsedFields)
+		}
+		report.Types = append(report.Types, t)
+	}
+	report.Coverage = percent(usedFields, totalFields)
+	return report, nil
+}
+
+// percent returns part/total as a percentage rounded to one decimal place
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
@@ -0,0 +1,124 @@
+package usage
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apstndb/github-schema-go/graphql"
+	"github.com/apstndb/github-schema-go/schema"
+)
+
+func loadSchema(t testing.TB) *schema.Schema {
+	t.Helper()
+	s, err := schema.NewWithFile(filepath.Join("..", "schema", "testdata", "synthetic.json"))
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	return s
+}
+
+func TestAnalyzer(t *testing.T) {
+	a := NewAnalyzer(loadSchema(t))
+	sources := []string{
+		`query Issues($owner: String!) { repository(owner: $owner, name: "n") { name issues(first: 10) { nodes { __typename ...IssueFields } } } }`,
+		`fragment IssueFields on Issue { number title author { login ... on User { login } } }
+{ viewer { login } }`,
+	}
+	for _, src := range sources {
+		doc, err := graphql.Parse(src)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if err := a.Add(doc); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	report, err := a.Report()
+	if err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if report.Operations != 2 || report.Fragments != 1 || report.Coverage != 35.7 {
+		t.Errorf("Unexpected totals: %+v", report)
+	}
+
+	want := []struct {
+		name       string
+		references int
+		coverage   float64 // -1 for types without fields
+		used       string
+	}{
+		{"Actor", 1, 100, "login:1"},
+		{"Int", 1, -1, ""},
+		{"Issue", 2, 42.9, "number:1 title:1 author:1"},
+		{"IssueConnection", 1, 25, "nodes:1"},
+		{"Query", 2, 50, "repository:1 viewer:1"},
+		{"Repository", 1, 25, "name:1 issues:1"},
+		{"String", 6, -1, ""},
+		{"User", 2, 25, "login:2"},
+	}
+	if len(report.Types) != len(want) {
+		t.Fatalf("Got %d types, want %d: %+v", len(report.Types), len(want), report.Types)
+	}
+	for i, w := range want {
+		got := report.Types[i]
+		var used []string
+		for _, f := range got.UsedFields {
+			used = append(used, fmt.Sprintf("%s:%d", f.Name, f.Count))
+		}
+		coverage := -1.0
+		if got.Coverage != nil {
+			coverage = *got.Coverage
+		}
+		if got.Name != w.name || got.References != w.references || coverage != w.coverage || strings.Join(used, " ") != w.used {
+			t.Errorf("Type %d = %s refs=%d coverage=%v used=%v, want %+v", i, got.Name, got.References, coverage, used, w)
+		}
+	}
+	if issue := report.Types[2]; strings.Join(issue.UnusedFields, ",") != "id,body,state,repository" {
+		t.Errorf("Issue unused fields = %v", issue.UnusedFields)
+	}
+}
+
+func TestAnalyzerUnknownField(t *testing.T) {
+	doc, err := graphql.Parse(`{ viewer {
+  nope } }`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	err = NewAnalyzer(loadSchema(t)).Add(doc)
+	if err == nil || !strings.Contains(err.Error(), `field "nope" not found on type "User" at 2:3`) {
+		t.Errorf("Expected unknown field error, got %v", err)
+	}
+}
+
+func TestAnalyzerDeprecatedUsages(t *testing.T) {
+	a := NewAnalyzer(loadSchema(t))
+	for _, src := range []string{
+		`{ repository(owner: "o", name: "n") { isTemplateRepo name } }`,
+		`fragment R on Repository { isTemplateRepo }`,
+	} {
+		doc, err := graphql.Parse(src)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if err := a.Add(doc); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	report, err := a.Report()
+	if err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if report.DeprecatedUsages != 2 {
+		t.Errorf("DeprecatedUsages = %d, want 2", report.DeprecatedUsages)
+	}
+	for _, typ := range report.Types {
+		for _, f := range typ.UsedFields {
+			if want := typ.Name == "Repository" && f.Name == "isTemplateRepo"; f.Deprecated != want {
+				t.Errorf("%s.%s deprecated = %v, want %v", typ.Name, f.Name, f.Deprecated, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Package usage measures which parts of the schema a codebase depends on by
+// walking its GraphQL operations and fragments.
+//
+// Every operation and fragment is analyzed on its own, starting from its root
+// or type condition, so fragments spread across files are counted once where
+// they are defined:
+//
+//	a := usage.NewAnalyzer(s)
+//	for _, src := range sources {
+//		doc, err := graphql.Parse(src)
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		if err := a.Add(doc); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//	report, err := a.Report()
+//
+// The report lists every type reached by a selection, variable, or type
+// condition, with per-field selection counts and the share of each type's
+// fields in use, so teams can prune generated code and focus schema update
+// reviews on what they actually query.
+package usage